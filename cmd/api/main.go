@@ -3,11 +3,15 @@ package main
 
 import (
 	"context"
+	"crypto/subtle"
 	"database/sql"
 	"log"
 	"net/http"
 	"os"
 	"os/signal"
+	"runtime"
+	"strings"
+	"sync"
 	"syscall"
 	"time"
 
@@ -17,10 +21,14 @@ import (
 	"github.com/Marga-Ghale/ora-scrum-backend/internal/cron"
 	"github.com/Marga-Ghale/ora-scrum-backend/internal/db"
 	"github.com/Marga-Ghale/ora-scrum-backend/internal/email"
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/logger"
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/metrics"
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/models"
 	"github.com/Marga-Ghale/ora-scrum-backend/internal/notification"
 	"github.com/Marga-Ghale/ora-scrum-backend/internal/repository"
 	"github.com/Marga-Ghale/ora-scrum-backend/internal/service"
 	"github.com/Marga-Ghale/ora-scrum-backend/internal/socket"
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/storage"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -44,7 +52,11 @@ func main() {
 	// ============================================
 	// Load configuration
 	// ============================================
-	cfg := config.Load()
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("❌ Invalid configuration: %v", err)
+	}
+	logger.Setup(cfg.LogLevel)
 
 	// ============================================
 	// Set Gin mode
@@ -109,6 +121,25 @@ func main() {
 		}
 	}
 
+	// ============================================
+	// Initialize File Storage
+	// ============================================
+	fileStore, err := storage.New(&storage.Config{
+		Driver:      cfg.StorageDriver,
+		LocalDir:    cfg.StorageLocalDir,
+		PublicURL:   cfg.StoragePublicURL,
+		S3Endpoint:  cfg.S3Endpoint,
+		S3Region:    cfg.S3Region,
+		S3Bucket:    cfg.S3Bucket,
+		S3AccessKey: cfg.S3AccessKey,
+		S3SecretKey: cfg.S3SecretKey,
+		S3PublicURL: cfg.S3PublicURL,
+	})
+	if err != nil {
+		log.Fatalf("❌ Failed to initialize file storage: %v", err)
+	}
+	log.Printf("🗂️  File storage initialized (%s)", cfg.StorageDriver)
+
 	// ============================================
 	// Initialize Email Service (optional)
 	// ============================================
@@ -123,6 +154,8 @@ func main() {
 			FromName: cfg.SMTPFromName,
 			UseTLS:   cfg.SMTPUseTLS,
 		})
+		stopEmailChecks := emailSvc.StartHealthChecks(healthTimeout)
+		defer stopEmailChecks()
 		log.Println("📧 Email service initialized")
 	} else {
 		log.Println("⚠️  Email not configured (SMTP_HOST not set)")
@@ -157,6 +190,9 @@ func main() {
 		repos.ProjectRepo,
 	)
 	notificationSvc.SetBroadcaster(broadcaster)
+	notificationSvc.SetPreferenceRepo(repos.NotificationPreferenceRepo)
+	notificationSvc.SetTaskRepo(repos.TaskRepo)
+	notificationSvc.SetCoalesceWindow(time.Duration(cfg.NotificationCoalesceSeconds) * time.Second)
 
 	// ============================================
 	// Initialize All Services
@@ -167,9 +203,39 @@ func main() {
 		NotifSvc:    notificationSvc,
 		EmailSvc:    emailSvc,
 		Broadcaster: broadcaster,
+		Storage:     fileStore,
+		Redis:       redisDB,
 	})
 	log.Println("✨ All services initialized")
 
+	// Only let clients subscribe to rooms of entities they can access; rooms
+	// of any other shape are refused rather than left open
+	hub.SetRoomAuthorizer(func(userID, room string) bool {
+		kind, id, ok := strings.Cut(room, ":")
+		if !ok || id == "" {
+			return false
+		}
+		switch kind {
+		case "user":
+			return id == userID
+		case "chat":
+			isMember, err := services.Chat.IsChannelMember(context.Background(), id, userID)
+			return err == nil && isMember
+		case service.EntityTypeWorkspace, service.EntityTypeSpace, service.EntityTypeFolder, service.EntityTypeProject:
+			hasAccess, _, err := services.Member.HasEffectiveAccess(context.Background(), kind, id, userID)
+			return err == nil && hasAccess
+		default:
+			return false
+		}
+	})
+
+	// Persist WebSocket-driven presence and tell the user's workspaces
+	hub.SetPresenceHandler(func(userID string, online bool) {
+		if err := services.User.SetPresence(context.Background(), userID, online); err != nil {
+			log.Printf("⚠️ Failed to update presence for user %s: %v", userID, err)
+		}
+	})
+
 	// ============================================
 	// Initialize Handlers
 	// ============================================
@@ -191,52 +257,77 @@ func main() {
     repos.UserRepo,
     repos.NotificationRepo,
     services.SprintAnalytics, // ✅ This is a SERVICE
+    time.Duration(cfg.PresenceAwayMinutes)*time.Minute,
+    time.Duration(cfg.PresenceOfflineMinutes)*time.Minute,
+    cfg.CronSchedules,
+    cron.NotificationCleanup{
+        ReadAfter:   time.Duration(cfg.NotificationRetentionDays) * 24 * time.Hour,
+        UnreadAfter: time.Duration(cfg.NotificationUnreadRetentionDays) * 24 * time.Hour,
+        DryRun:      cfg.NotificationCleanupDryRun,
+    },
 )
+	// Presence of connected users is kept by the hub, not the inactivity jobs
+	cronScheduler.SetConnectedUsers(hub.GetOnlineUsers)
 	cronScheduler.Start()
 	defer cronScheduler.Stop()
 
 	// ============================================
 	// Create Gin Router
 	// ============================================
-	r := gin.Default()
+	r := gin.New()
 
-	// Add comprehensive logging
+	// Every request gets an ID first, so that its logs (including the request
+	// log line) carry it
+	r.Use(middleware.RequestID())
 	r.Use(middleware.RequestLogger())
-	r.Use(middleware.ErrorLogger())
+	r.Use(gin.Recovery())
+
+	// Prometheus metrics (optional)
+	if cfg.MetricsEnabled {
+		r.Use(middleware.Metrics())
+		registerRuntimeMetrics(pgPool, hub, services)
+		r.GET("/metrics", gin.WrapH(metrics.Handler()))
+		log.Println("📈 Metrics enabled on /metrics")
+	}
 
 	// Configure CORS
 	r.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"http://localhost:3000", "http://localhost:5173", "https://scrum.oratechnologies.io"},
 		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", middleware.RequestIDHeader},
+		ExposeHeaders:    []string{"Content-Length", "Retry-After", "X-Total-Count", "X-Unread-Count", "X-Has-More", "Link", middleware.RequestIDHeader},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
 	}))
 
+	// Locally stored uploads are served from disk as downloads, never inline
+	if local, ok := fileStore.(*storage.Local); ok {
+		r.Group("/uploads", middleware.ServeAsDownload()).Static("/", local.Dir())
+	}
+
 	// Health check endpoint - supports ALL HTTP methods (GET, HEAD, POST, etc.)
-	r.Any("/health", func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{
-			"status":     "healthy",
-			"timestamp":  time.Now(),
-			"database":   "connected",
-			"cache":      getCacheStatus(redisDB),
-			"websocket":  "active",
-			"ws_clients": hub.GetConnectedClientsCount(),
-			"email":      getEmailStatus(emailSvc),
-		})
-	})
+	r.Any("/health", healthHandler(pgPool, redisDB, emailSvc, hub, services))
+	if cfg.HealthDetailToken != "" {
+		r.GET("/health/detailed", requireHealthToken(cfg.HealthDetailToken), detailedHealthHandler(pgPool, sqlDB, hub))
+	}
 
-	// API routes
-	api := r.Group("/api")
+	// Rate limiting (Redis-backed when available)
+	rateLimiter := middleware.NewRateLimiter(redisDB)
+	rateWindow := time.Duration(cfg.RateLimitWindowSeconds) * time.Second
+	authLimit := rateLimiter.RateLimit(cfg.RateLimitAuth, rateWindow)
+	bulkLimit := rateLimiter.RateLimit(cfg.RateLimitBulk, rateWindow)
+
+	// API routes. Path IDs are checked up front so a malformed one is a 400,
+	// not a database error.
+	api := r.Group("/api", middleware.ValidateUUIDParams())
 	{
 		// ============================================
 		// Public routes (no auth required)
 		// ============================================
 		auth := api.Group("/auth")
 		{
-			auth.POST("/register", h.Auth.Register)
-			auth.POST("/login", h.Auth.Login)
+			auth.POST("/register", authLimit, h.Auth.Register)
+			auth.POST("/login", authLimit, h.Auth.Login)
 			auth.POST("/refresh", h.Auth.RefreshToken)
 			auth.POST("/logout", h.Auth.Logout)
 		}
@@ -250,17 +341,32 @@ func main() {
 		// WebSocket route
 		api.GET("/ws", wsHandler.HandleWebSocket)
 
+		// Calendar feed, authenticated by the feed token in the URL
+		api.GET("/users/me/calendar.ics", rateLimiter.RateLimit(cfg.RateLimitAPI, rateWindow), h.Calendar.Feed)
+
+		// Source control events, authenticated by the workspace integration token
+		api.POST("/integrations/scm/event", rateLimiter.RateLimit(cfg.RateLimitAPI, rateWindow), h.SCM.Event)
+
 		// ============================================
 		// Protected routes (require auth middleware)
 		// ============================================
 		protected := api.Group("")
 		protected.Use(middleware.AuthMiddleware(services.Auth))
+		protected.Use(rateLimiter.RateLimit(cfg.RateLimitAPI, rateWindow))
 		{
 			// User routes
 			users := protected.Group("/users")
 			{
 				users.GET("/me", h.User.GetCurrentUser)
 				users.PUT("/me", h.User.UpdateCurrentUser)
+				users.DELETE("/me", h.User.DeleteCurrentUser)
+				users.POST("/me/avatar", middleware.LimitBody(int64(cfg.AvatarMaxSizeMB)<<20), h.User.UploadAvatar)
+				users.GET("/me/checklist-items", h.Task.ListMyChecklistItems)
+				users.GET("/me/work", h.Task.GetMyWork)
+				users.GET("/me/notification-preferences", h.Notification.GetPreferences)
+				users.PUT("/me/notification-preferences", h.Notification.UpdatePreferences)
+				users.POST("/me/calendar-token", h.Calendar.GenerateToken)
+				users.DELETE("/me/calendar-token", h.Calendar.RevokeToken)
 				users.GET("/search", h.User.SearchUsers)
 			}
 
@@ -272,15 +378,38 @@ func main() {
 				workspaces.GET("/:id", h.Workspace.Get)
 				workspaces.PUT("/:id", h.Workspace.Update)
 				workspaces.DELETE("/:id", h.Workspace.Delete)
+				workspaces.GET("/:id/presence", h.Workspace.GetPresence)
 
 				// Invitations
 				workspaces.POST("/:id/invitations", invitationHandler.CreateWorkspaceInvitation)
 				workspaces.GET("/:id/invitations", invitationHandler.GetWorkspaceInvitations)
+				workspaces.POST("/:id/invitations/bulk", bulkLimit, invitationHandler.BulkInviteWorkspace)
+				workspaces.GET("/:id/invitations/bulk/:resultId", invitationHandler.GetBulkInvitationResult)
+
+				// Access requests
+				workspaces.POST("/:id/access-requests", h.AccessRequest.Create)
+				workspaces.GET("/:id/access-requests", h.AccessRequest.ListPending)
 
 				// Spaces
 				workspaces.GET("/:id/spaces", h.Space.ListByWorkspace)
 				workspaces.POST("/:id/spaces", h.Space.Create)
 
+				// Project templates
+				workspaces.GET("/:id/templates", h.Project.ListTemplates)
+
+				// Outgoing webhooks
+				workspaces.GET("/:id/webhooks", h.Webhook.List)
+				workspaces.POST("/:id/webhooks", h.Webhook.Create)
+				workspaces.GET("/:id/webhooks/:webhookId", h.Webhook.Get)
+				workspaces.PUT("/:id/webhooks/:webhookId", h.Webhook.Update)
+				workspaces.DELETE("/:id/webhooks/:webhookId", h.Webhook.Delete)
+				workspaces.POST("/:id/webhooks/:webhookId/test", h.Webhook.Test)
+				workspaces.GET("/:id/webhooks/:webhookId/dead-letters", h.Webhook.ListDeadLetters)
+
+				// Source control integration token
+				workspaces.POST("/:id/integration-token", h.SCM.GenerateToken)
+				workspaces.DELETE("/:id/integration-token", h.SCM.RevokeToken)
+
 				// Teams
 				workspaces.GET("/:id/teams", teamHandler.ListByWorkspace)
 
@@ -295,6 +424,12 @@ func main() {
 				spaces.PUT("/:id", h.Space.Update)
 				spaces.DELETE("/:id", h.Space.Delete)
 
+				// Members (?effective=true adds inherited members)
+				spaces.GET("/:id/members", h.Member.ListEntityMembers(service.EntityTypeSpace))
+				spaces.POST("/:id/members", h.Member.AddEntityMember(service.EntityTypeSpace))
+				spaces.PUT("/:id/members/:userId", h.Member.UpdateEntityMemberRole(service.EntityTypeSpace))
+				spaces.DELETE("/:id/members/:userId", h.Member.RemoveEntityMember(service.EntityTypeSpace))
+
 				// Folder routes
 				spaces.GET("/:id/folders", h.Folder.ListBySpace)
 				spaces.POST("/:id/folders", h.Folder.Create)
@@ -302,6 +437,7 @@ func main() {
 				// Project routes
 				spaces.GET("/:id/projects", h.Project.ListBySpace)
 				spaces.POST("/:id/projects", h.Project.Create)
+				spaces.POST("/:id/projects/from-template", h.Project.CreateFromTemplate)
 			}
 
 			// Folder routes
@@ -313,14 +449,42 @@ func main() {
 				folders.DELETE("/:id", h.Folder.Delete)
 				folders.PATCH("/:id/visibility", h.Folder.UpdateVisibility)
 				folders.GET("/:id/projects", h.Project.ListByFolder)
+
+				// Members (?effective=true adds inherited members)
+				folders.GET("/:id/members", h.Member.ListEntityMembers(service.EntityTypeFolder))
+				folders.POST("/:id/members", h.Member.AddEntityMember(service.EntityTypeFolder))
+				folders.PUT("/:id/members/:userId", h.Member.UpdateEntityMemberRole(service.EntityTypeFolder))
+				folders.DELETE("/:id/members/:userId", h.Member.RemoveEntityMember(service.EntityTypeFolder))
+			}
+
+			// Saved view routes
+			views := protected.Group("/views")
+			{
+				views.GET("/:id", h.Task.GetView)
+				views.PUT("/:id", h.Task.UpdateView)
+				views.DELETE("/:id", h.Task.DeleteView)
+				views.GET("/:id/tasks", h.Task.ApplyView)
+			}
+
+			// Project template routes
+			templates := protected.Group("/templates")
+			{
+				templates.DELETE("/:id", h.Project.DeleteTemplate)
 			}
 
 			// Project routes
 			projects := protected.Group("/projects")
 			{
+				projects.GET("/key-available", h.Project.KeyAvailable)
 				projects.GET("/:id", h.Project.Get)
+				projects.GET("/:id/stats", h.Project.GetStats)
 				projects.PUT("/:id", h.Project.Update)
 				projects.DELETE("/:id", h.Project.Delete)
+				projects.POST("/:id/templates", h.Project.SaveAsTemplate)
+				projects.GET("/:id/members", h.Member.ListProjectMembers) // ?effective=true adds inherited members
+				projects.POST("/:id/members", h.Member.AddEntityMember(service.EntityTypeProject))
+				projects.PUT("/:id/members/:userId", h.Member.UpdateEntityMemberRole(service.EntityTypeProject))
+				projects.DELETE("/:id/members/:userId", h.Member.RemoveEntityMember(service.EntityTypeProject))
 
 				// Invitations
 				projects.POST("/:id/invitations", invitationHandler.CreateProjectInvitation)
@@ -328,7 +492,34 @@ func main() {
 
 				// Tasks
 				projects.GET("/:id/tasks", h.Task.ListByProject)
+				projects.GET("/:id/backlog", h.Task.GetBacklog)
 				projects.POST("/:id/tasks", h.Task.Create)
+				projects.GET("/:id/time-report", h.Task.GetTimeReport)
+
+				// Saved views
+				projects.GET("/:id/views", h.Task.ListViews)
+				projects.GET("/:id/export", h.Task.ExportTasks)
+				projects.POST("/:id/views", h.Task.CreateView)
+
+				// WIP limits
+				projects.GET("/:id/wip-limits", h.Task.GetWIPLimits)
+				projects.PUT("/:id/wip-limits", h.Task.SetWIPLimit)
+				projects.GET("/:id/task-settings", h.Task.GetTaskSettings)
+				projects.PUT("/:id/task-settings", h.Task.UpdateTaskSettings)
+
+				// Commit reference settings
+				projects.GET("/:id/scm-settings", h.SCM.GetSettings)
+				projects.PUT("/:id/scm-settings", h.SCM.UpdateSettings)
+
+				// Recurring task templates
+				projects.GET("/:id/recurring-tasks", h.RecurringTask.ListByProject)
+				projects.POST("/:id/recurring-tasks", h.RecurringTask.Create)
+
+				// Status workflow
+				projects.GET("/:id/statuses", h.ProjectStatus.List)
+				projects.POST("/:id/statuses", h.ProjectStatus.Create)
+				projects.PUT("/:id/statuses/:statusId", h.ProjectStatus.Update)
+				projects.DELETE("/:id/statuses/:statusId", h.ProjectStatus.Delete)
 
 				// Labels
 				projects.GET("/:id/labels", h.Label.ListByProject)
@@ -348,8 +539,11 @@ func main() {
 				// Specific routes BEFORE generic :id
 				tasks.GET("/my", h.Task.ListMyTasks)
 				tasks.GET("/filter", h.Task.FilterTasks)
+				tasks.POST("/filter", h.Task.FilterTasks)
+				tasks.GET("/search", h.Task.SearchTasks)
 
 				// Core CRUD
+				tasks.GET("/key/:key", h.Task.GetByKey)
 				tasks.GET("/:id", h.Task.Get)
 				tasks.PUT("/:id", h.Task.Update)
 				tasks.DELETE("/:id", h.Task.Delete)
@@ -362,6 +556,7 @@ func main() {
 				tasks.GET("/:id/blocked-by", h.Task.ListBlockedBy)
 				tasks.GET("/:id/checklists", h.Task.ListChecklists)
 				tasks.GET("/:id/activity", h.Task.GetActivity)
+				tasks.GET("/:id/history", h.Task.GetHistory)
 				tasks.GET("/:id/time", h.Task.GetTimeEntries)
 				tasks.GET("/:id/time/total", h.Task.GetTotalTime)
 
@@ -377,17 +572,27 @@ func main() {
 				tasks.POST("/:id/watchers", h.Task.AddWatcher)
 				tasks.DELETE("/:id/watchers/:watcherId", h.Task.RemoveWatcher)
 
+				// Labels
+				tasks.GET("/:id/labels", h.Task.GetLabels)
+				tasks.POST("/:id/labels/:labelId", h.Task.AddLabel)
+				tasks.DELETE("/:id/labels/:labelId", h.Task.RemoveLabel)
+
 				// Sprint & hierarchy
 				tasks.POST("/:id/move-sprint", h.Task.MoveToSprint)
+				tasks.POST("/:id/move-project", h.Task.MoveToProject)
 				tasks.POST("/:id/convert-subtask", h.Task.ConvertToSubtask)
 				tasks.POST("/:id/complete", h.Task.MarkComplete)
 
 				// Actions
 				tasks.POST("/:id/comments", h.Task.AddComment)
 				tasks.PUT("/comments/:commentId", h.Task.UpdateComment)
+				tasks.GET("/comments/:commentId/history", h.Task.GetCommentHistory)
 				tasks.DELETE("/comments/:commentId", h.Task.DeleteComment)
+				tasks.POST("/comments/:commentId/reactions", h.Task.AddCommentReaction)
+				tasks.DELETE("/comments/:commentId/reactions", h.Task.RemoveCommentReaction)
 
 				tasks.POST("/:id/attachments", h.Task.AddAttachment)
+				tasks.POST("/:id/attachments/upload", middleware.LimitBody(int64(cfg.AttachmentMaxSizeMB)<<20), h.Task.UploadAttachment)
 				tasks.DELETE("/attachments/:attachmentId", h.Task.DeleteAttachment)
 
 				tasks.POST("/:id/timer/start", h.Task.StartTimer)
@@ -396,6 +601,8 @@ func main() {
 				tasks.POST("/:id/time", h.Task.LogTime)
 
 				tasks.PATCH("/:id/move", h.Task.UpdatePositionAndStatus)
+				tasks.POST("/:id/reorder", h.Task.ReorderTask)
+				tasks.POST("/:id/clone", h.Task.CloneTask)
 
 
 				tasks.POST("/:id/dependencies", h.Task.AddDependency)
@@ -403,13 +610,18 @@ func main() {
 
 				tasks.POST("/:id/checklists", h.Task.CreateChecklist)
 				tasks.POST("/checklists/:checklistId/items", h.Task.AddChecklistItem)
+				tasks.PATCH("/checklists/:checklistId/reorder", h.Task.ReorderChecklist)
+				tasks.PUT("/checklists/items/:itemId", h.Task.UpdateChecklistItem)
 				tasks.PATCH("/checklists/items/:itemId", h.Task.ToggleChecklistItem)
+				tasks.PATCH("/checklists/items/:itemId/reorder", h.Task.ReorderChecklistItem)
 				tasks.DELETE("/checklists/items/:itemId", h.Task.DeleteChecklistItem)
 
 				// Bulk operations
-				tasks.POST("/bulk/status", h.Task.BulkUpdateStatus)
-				tasks.POST("/bulk/assign", h.Task.BulkAssign)
-				tasks.POST("/bulk/move-sprint", h.Task.BulkMoveToSprint)
+				tasks.POST("/bulk/status", bulkLimit, h.Task.BulkUpdateStatus)
+				tasks.POST("/bulk/assign", bulkLimit, h.Task.BulkAssign)
+				tasks.POST("/bulk/move-sprint", bulkLimit, h.Task.BulkMoveToSprint)
+				tasks.POST("/bulk/delete", bulkLimit, h.Task.BulkDelete)
+				tasks.POST("/bulk/labels", bulkLimit, h.Task.BulkLabels)
 			}
 
 
@@ -437,6 +649,7 @@ func main() {
 			{
 				// Basic CRUD (using :id)
 				sprints.GET("/:id", h.Sprint.Get)
+				sprints.GET("/:id/board", h.Task.GetSprintBoard)
 				sprints.PUT("/:id", h.Sprint.Update)
 				sprints.DELETE("/:id", h.Sprint.Delete)
 				sprints.POST("/:id/start", h.Sprint.Start)
@@ -447,6 +660,12 @@ func main() {
 				sprints.GET("/:id/goals", h.Goal.ListBySprint)
 				sprints.GET("/:id/goals/summary", h.Goal.GetSprintGoalsSummary)
 				sprints.GET("/:id/report", h.SprintAnalytics.GetSprintReport)
+				sprints.GET("/:id/scope-report", h.Sprint.GetReport)
+
+				// Capacity planning
+				sprints.GET("/:id/capacity", h.Sprint.GetCapacity)
+				sprints.PUT("/:id/capacity/:userId", h.Sprint.SetMemberCapacity)
+				sprints.DELETE("/:id/capacity/:userId", h.Sprint.RemoveMemberCapacity)
 				sprints.POST("/:id/report/generate", h.SprintAnalytics.GenerateSprintReport)
 				sprints.GET("/:id/cycle-time", h.SprintAnalytics.GetSprintCycleTime)
 				sprints.GET("/:id/analytics", h.SprintAnalytics.GetSprintAnalyticsDashboard)
@@ -470,6 +689,7 @@ func main() {
 			// Label routes
 			labels := protected.Group("/labels")
 			{
+				labels.POST("/merge", h.Label.Merge)
 				labels.PUT("/:id", h.Label.Update)
 				labels.DELETE("/:id", h.Label.Delete)
 			}
@@ -480,6 +700,7 @@ func main() {
 				notifications.GET("", h.Notification.List)
 				notifications.GET("/count", h.Notification.Count)
 				notifications.PUT("/:id/read", h.Notification.MarkRead)
+				notifications.PUT("/:id/unread", h.Notification.MarkUnread)
 				notifications.PUT("/read-all", h.Notification.MarkAllRead)
 				notifications.DELETE("/:id", h.Notification.Delete)
 				notifications.DELETE("", h.Notification.DeleteAll)
@@ -512,6 +733,7 @@ func main() {
 				chat.POST("/channels/:id/join", chatHandler.JoinChannel)
 				chat.POST("/channels/:id/leave", chatHandler.LeaveChannel)
 				chat.GET("/channels/:id/members", chatHandler.GetChannelMembers)
+				chat.GET("/channels/:id/presence", chatHandler.GetChannelPresence)
 				chat.POST("/channels/:id/members/add", chatHandler.AddMember)
 				    chat.POST("/channels/:id/members/remove", chatHandler.RemoveMember)  
 
@@ -520,6 +742,7 @@ func main() {
 
 				chat.GET("/channels/:id/messages", chatHandler.GetMessages)
 				chat.POST("/channels/:id/messages", chatHandler.SendMessage)
+				chat.GET("/channels/:id/messages/search", chatHandler.SearchMessages)
 				chat.GET("/messages/:messageId/thread", chatHandler.GetThreadMessages)
 				chat.PUT("/messages/:messageId", chatHandler.UpdateMessage)
 				chat.DELETE("/messages/:messageId", chatHandler.DeleteMessage)
@@ -544,6 +767,13 @@ func main() {
 				invitations.GET("/stats", invitationHandler.GetInvitationStats)
 			}
 
+			// Access request review
+			accessRequests := protected.Group("/access-requests")
+			{
+				accessRequests.POST("/:id/approve", h.AccessRequest.Approve)
+				accessRequests.POST("/:id/deny", h.AccessRequest.Deny)
+			}
+
 			// Member Management Routes
 			members := protected.Group("/members")
 			{
@@ -572,6 +802,12 @@ func main() {
 				members.GET("/:entityType/:entityId/access-level", h.Member.GetAccessLevel)
 			}
 
+			// Search routes
+			search := protected.Group("/search")
+			{
+				search.GET("/comments", h.Task.SearchComments)
+			}
+
 			// Activity routes
 			activities := protected.Group("/activities")
 			{
@@ -613,16 +849,159 @@ func main() {
 	log.Println("Server exited")
 }
 
-func getCacheStatus(redisDB *db.RedisDB) string {
-	if redisDB != nil {
-		return "connected"
+// healthTimeout bounds the dependency checks of /health, so a hung database,
+// Redis or SMTP server cannot make probes hang
+const healthTimeout = 2 * time.Second
+
+// componentHealth is the result of checking one dependency: "up", "down",
+// "disabled" when it is not configured, or "unknown" before its first check
+type componentHealth struct {
+	Status    string     `json:"status"`
+	LatencyMs int64      `json:"latencyMs"`
+	CheckedAt *time.Time `json:"checkedAt,omitempty"`
+}
+
+// emailHealth reports the email service's last send or background check
+// rather than dialing SMTP, so probes never reach the mail server
+func emailHealth(emailSvc *email.Service) componentHealth {
+	if emailSvc == nil {
+		return componentHealth{Status: "disabled"}
+	}
+	last, ok := emailSvc.Health()
+	if !ok {
+		return componentHealth{Status: "unknown"}
+	}
+	result := componentHealth{Status: "up", LatencyMs: last.Latency.Milliseconds(), CheckedAt: &last.CheckedAt}
+	if last.Err != nil {
+		result.Status = "down"
+	}
+	return result
+}
+
+// healthHandler pings the database and Redis concurrently and adds the last
+// known SMTP state. It answers 503 when the database is down, and 200 with
+// status "degraded" when only an optional dependency is.
+func healthHandler(pgPool *pgxpool.Pool, redisDB *db.RedisDB, emailSvc *email.Service, hub *socket.Hub, services *service.Services) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), healthTimeout)
+		defer cancel()
+
+		database := componentHealth{Status: "disabled"}
+		cache := componentHealth{Status: "disabled"}
+
+		var wg sync.WaitGroup
+		check := func(name string, result *componentHealth, ping func() error) {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				start := time.Now()
+				err := ping()
+				*result = componentHealth{Status: "up", LatencyMs: time.Since(start).Milliseconds()}
+				if err != nil {
+					result.Status = "down"
+					log.Printf("[Health] %s check failed: %v", name, err)
+				}
+			}()
+		}
+
+		check("database", &database, func() error { return pgPool.Ping(ctx) })
+		if redisDB != nil {
+			check("cache", &cache, func() error { return redisDB.Ping(ctx) })
+		}
+		wg.Wait()
+		mail := emailHealth(emailSvc)
+
+		status, code := "healthy", http.StatusOK
+		switch {
+		case database.Status != "up":
+			status, code = "unhealthy", http.StatusServiceUnavailable
+		case cache.Status == "down" || mail.Status == "down":
+			status = "degraded"
+		}
+
+		c.JSON(code, gin.H{
+			"status":       status,
+			"timestamp":    time.Now(),
+			"database":     database,
+			"cache":        cache,
+			"email":        mail,
+			"access_cache": services.AccessCache.Stats(),
+			"read_cache":   services.ReadCache.Stats(),
+			"websocket":    "active",
+			"ws_clients":   hub.GetConnectedClientsCount(),
+		})
 	}
-	return "disabled"
 }
 
-func getEmailStatus(emailSvc *email.Service) string {
-	if emailSvc != nil {
-		return "configured"
+// requireHealthToken admits requests sending the token as
+// "Authorization: Bearer <token>"
+func requireHealthToken(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		sent, _ := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(sent), []byte(token)) != 1 {
+			c.AbortWithStatusJSON(http.StatusUnauthorized,
+				models.NewErrorResponse(models.CodeUnauthenticated, "Invalid health token", nil))
+			return
+		}
+		c.Next()
 	}
-	return "disabled"
-}
\ No newline at end of file
+}
+
+// detailedHealthHandler reports connection pool, WebSocket and goroutine
+// counts, for a quick look at the process during incidents
+func detailedHealthHandler(pgPool *pgxpool.Pool, sqlDB *sql.DB, hub *socket.Hub) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		pool := pgPool.Stat()
+		sqlStats := sqlDB.Stats()
+
+		c.JSON(http.StatusOK, gin.H{
+			"timestamp": time.Now(),
+			"pgx_pool": gin.H{
+				"max":                    pool.MaxConns(),
+				"total":                  pool.TotalConns(),
+				"idle":                   pool.IdleConns(),
+				"in_use":                 pool.AcquiredConns(),
+				"constructing":           pool.ConstructingConns(),
+				"acquire_count":          pool.AcquireCount(),
+				"empty_acquire_count":    pool.EmptyAcquireCount(),
+				"canceled_acquire_count": pool.CanceledAcquireCount(),
+				"acquire_wait_ms":        pool.AcquireDuration().Milliseconds(),
+			},
+			"sql_pool": gin.H{
+				"max":        sqlStats.MaxOpenConnections,
+				"total":      sqlStats.OpenConnections,
+				"idle":       sqlStats.Idle,
+				"in_use":     sqlStats.InUse,
+				"wait_count": sqlStats.WaitCount,
+				"wait_ms":    sqlStats.WaitDuration.Milliseconds(),
+			},
+			"ws_clients": hub.GetConnectedClientsCount(),
+			"goroutines": runtime.NumGoroutine(),
+		})
+	}
+}
+
+// registerRuntimeMetrics exposes the connection pool, WebSocket and cache
+// figures that are read from their owners at scrape time
+func registerRuntimeMetrics(pgPool *pgxpool.Pool, hub *socket.Hub, services *service.Services) {
+	metrics.NewGaugeFunc("ora_db_pool_connections", "Open PostgreSQL connections in the pgx pool.",
+		func() float64 { return float64(pgPool.Stat().TotalConns()) })
+	metrics.NewGaugeFunc("ora_db_pool_idle_connections", "Idle PostgreSQL connections in the pgx pool.",
+		func() float64 { return float64(pgPool.Stat().IdleConns()) })
+	metrics.NewGaugeFunc("ora_db_pool_in_use_connections", "PostgreSQL connections currently acquired from the pgx pool.",
+		func() float64 { return float64(pgPool.Stat().AcquiredConns()) })
+	metrics.NewGaugeFunc("ora_db_pool_max_connections", "Maximum size of the pgx pool.",
+		func() float64 { return float64(pgPool.Stat().MaxConns()) })
+	metrics.NewCounterFunc("ora_db_pool_acquires_total", "Connections acquired from the pgx pool.",
+		func() float64 { return float64(pgPool.Stat().AcquireCount()) })
+	metrics.NewCounterFunc("ora_db_pool_acquire_wait_seconds_total", "Time spent waiting to acquire pgx pool connections.",
+		func() float64 { return pgPool.Stat().AcquireDuration().Seconds() })
+
+	metrics.NewGaugeFunc("ora_websocket_clients", "Connected WebSocket clients.",
+		func() float64 { return float64(hub.GetConnectedClientsCount()) })
+
+	metrics.NewGaugeFunc("ora_access_cache_hit_ratio", "Share of access checks answered from the Redis cache.",
+		func() float64 { return services.AccessCache.Stats().HitRate })
+	metrics.NewGaugeFunc("ora_read_cache_hit_ratio", "Share of cached reads (boards, stats, member lists) answered from Redis.",
+		func() float64 { return services.ReadCache.Stats().HitRate })
+}
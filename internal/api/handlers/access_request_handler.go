@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/api/middleware"
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/models"
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type AccessRequestHandler struct {
+	accessRequestService service.AccessRequestService
+}
+
+func NewAccessRequestHandler(accessRequestService service.AccessRequestService) *AccessRequestHandler {
+	return &AccessRequestHandler{accessRequestService: accessRequestService}
+}
+
+// Create asks the admins of a workspace (or one of its spaces, folders or projects) for access
+// POST /api/workspaces/:id/access-requests
+func (h *AccessRequestHandler) Create(c *gin.Context) {
+	userID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	workspaceID := c.Param("id")
+
+	// Body is optional: an empty request asks for access to the workspace
+	var req models.CreateAccessRequestRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondBindError(c, err)
+			return
+		}
+	}
+
+	accessReq, err := h.accessRequestService.RequestAccess(c.Request.Context(), workspaceID, userID, &req)
+	if err != nil {
+		logAPIError(c, "AccessRequest.Create", err, map[string]interface{}{
+			"workspaceID": workspaceID,
+			"type":        req.Type,
+			"targetID":    req.TargetID,
+		})
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, accessReq)
+}
+
+// ListPending returns the pending requests for a target; defaults to the workspace itself
+// GET /api/workspaces/:id/access-requests?type=project&targetId=...
+func (h *AccessRequestHandler) ListPending(c *gin.Context) {
+	userID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	targetType := c.DefaultQuery("type", service.EntityTypeWorkspace)
+	targetID := c.Query("targetId")
+	if targetID == "" && targetType == service.EntityTypeWorkspace {
+		targetID = c.Param("id")
+	}
+
+	requests, err := h.accessRequestService.ListPending(c.Request.Context(), targetType, targetID, userID)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, requests)
+}
+
+// Approve grants the requester membership of the target
+// POST /api/access-requests/:id/approve
+func (h *AccessRequestHandler) Approve(c *gin.Context) {
+	userID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	requestID := c.Param("id")
+
+	accessReq, err := h.accessRequestService.Approve(c.Request.Context(), requestID, userID)
+	if err != nil {
+		logAPIError(c, "AccessRequest.Approve", err, map[string]interface{}{
+			"requestID": requestID,
+		})
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, accessReq)
+}
+
+// Deny refuses the request with an optional reason
+// POST /api/access-requests/:id/deny
+func (h *AccessRequestHandler) Deny(c *gin.Context) {
+	userID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	requestID := c.Param("id")
+
+	var req models.DenyAccessRequestRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondBindError(c, err)
+			return
+		}
+	}
+
+	accessReq, err := h.accessRequestService.Deny(c.Request.Context(), requestID, userID, req.Reason)
+	if err != nil {
+		logAPIError(c, "AccessRequest.Deny", err, map[string]interface{}{
+			"requestID": requestID,
+		})
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, accessReq)
+}
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/api/middleware"
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// ============================================
+// Calendar Handler
+// ============================================
+
+type CalendarHandler struct {
+	calendarService service.CalendarService
+}
+
+// GenerateToken issues a new calendar feed token and returns the feed URL.
+// Any previous token stops working.
+// POST /api/users/me/calendar-token
+func (h *CalendarHandler) GenerateToken(c *gin.Context) {
+	userID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	token, err := h.calendarService.GenerateFeedToken(c.Request.Context(), userID)
+	if err != nil {
+		logAPIError(c, "Calendar.GenerateToken", err, nil)
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"token": token,
+		"url":   calendarFeedURL(c, token),
+	})
+}
+
+// RevokeToken disables the user's calendar feed
+// DELETE /api/users/me/calendar-token
+func (h *CalendarHandler) RevokeToken(c *gin.Context) {
+	userID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.calendarService.RevokeFeedToken(c.Request.Context(), userID); err != nil {
+		logAPIError(c, "Calendar.RevokeToken", err, nil)
+		handleServiceError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// Feed serves the iCalendar feed of the token's owner. It is not behind the
+// auth middleware: the token query parameter is the credential.
+// GET /api/users/me/calendar.ics?token=
+func (h *CalendarHandler) Feed(c *gin.Context) {
+	feed, err := h.calendarService.Feed(c.Request.Context(), c.Query("token"))
+	if err != nil {
+		if err != service.ErrInvalidToken {
+			logAPIError(c, "Calendar.Feed", err, nil)
+		}
+		handleServiceError(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "private, max-age=300")
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", feed)
+}
+
+// calendarFeedURL builds the absolute feed URL from the request, honouring a
+// TLS-terminating proxy
+func calendarFeedURL(c *gin.Context, token string) string {
+	scheme := "http"
+	if c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	return scheme + "://" + c.Request.Host + "/api/users/me/calendar.ics?token=" + token
+}
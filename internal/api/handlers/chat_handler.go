@@ -3,7 +3,9 @@ package handlers
 import (
 	"net/http"
 	"strconv"
+	"strings"
 
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/api/middleware"
 	"github.com/Marga-Ghale/ora-scrum-backend/internal/service"
 	"github.com/gin-gonic/gin"
 )
@@ -57,14 +59,14 @@ type ReactionRequest struct {
 func (h *ChatHandler) CreateChannel(c *gin.Context) {
 	var req CreateChannelRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
 	userID := c.GetString("userID")
 	channel, err := h.chatSvc.CreateChannel(c.Request.Context(), req.Name, req.Type, req.TargetID, req.WorkspaceID, userID, req.IsPrivate)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		handleServiceError(c, err)
 		return
 	}
 
@@ -77,7 +79,7 @@ func (h *ChatHandler) GetChannel(c *gin.Context) {
 
 	channel, err := h.chatSvc.GetChannel(c.Request.Context(), channelID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Channel not found"})
+		respondError(c, http.StatusNotFound, "Channel not found")
 		return
 	}
 
@@ -90,13 +92,13 @@ func (h *ChatHandler) GetChannelByTarget(c *gin.Context) {
 	targetID := c.Query("targetId")
 
 	if targetType == "" || targetID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "type and targetId are required"})
+		respondError(c, http.StatusBadRequest, "type and targetId are required")
 		return
 	}
 
 	channel, err := h.chatSvc.GetChannelByTarget(c.Request.Context(), targetType, targetID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Channel not found"})
+		respondError(c, http.StatusNotFound, "Channel not found")
 		return
 	}
 
@@ -109,7 +111,7 @@ func (h *ChatHandler) ListChannels(c *gin.Context) {
 
 	channels, err := h.chatSvc.ListChannels(c.Request.Context(), userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		handleServiceError(c, err)
 		return
 	}
 
@@ -122,7 +124,7 @@ func (h *ChatHandler) ListWorkspaceChannels(c *gin.Context) {
 
 	channels, err := h.chatSvc.ListWorkspaceChannels(c.Request.Context(), workspaceID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		handleServiceError(c, err)
 		return
 	}
 
@@ -136,10 +138,10 @@ func (h *ChatHandler) DeleteChannel(c *gin.Context) {
 
 	if err := h.chatSvc.DeleteChannel(c.Request.Context(), channelID, userID); err != nil {
 		if err == service.ErrForbidden {
-			c.JSON(http.StatusForbidden, gin.H{"error": "You don't have permission to delete this channel"})
+			respondError(c, http.StatusForbidden, "You don't have permission to delete this channel")
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		handleServiceError(c, err)
 		return
 	}
 
@@ -150,18 +152,20 @@ func (h *ChatHandler) DeleteChannel(c *gin.Context) {
 // Direct Message Endpoints
 // ============================================
 
-// CreateDirectChannel creates or gets a direct message channel
+// CreateDirectChannel returns the DM with another user, creating it the first time
+// POST /api/chat/direct
 func (h *ChatHandler) CreateDirectChannel(c *gin.Context) {
 	var req CreateDirectChannelRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
 	userID := c.GetString("userID")
 	channel, err := h.chatSvc.CreateDirectChannel(c.Request.Context(), userID, req.UserID, req.WorkspaceID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		logAPIError(c, "Chat.CreateDirectChannel", err, map[string]interface{}{"otherUserID": req.UserID})
+		handleServiceError(c, err)
 		return
 	}
 
@@ -178,7 +182,7 @@ func (h *ChatHandler) JoinChannel(c *gin.Context) {
 	userID := c.GetString("userID")
 
 	if err := h.chatSvc.JoinChannel(c.Request.Context(), channelID, userID); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		handleServiceError(c, err)
 		return
 	}
 
@@ -191,7 +195,7 @@ func (h *ChatHandler) LeaveChannel(c *gin.Context) {
 	userID := c.GetString("userID")
 
 	if err := h.chatSvc.LeaveChannel(c.Request.Context(), channelID, userID); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		handleServiceError(c, err)
 		return
 	}
 
@@ -204,20 +208,38 @@ func (h *ChatHandler) GetChannelMembers(c *gin.Context) {
 
 	members, err := h.chatSvc.GetChannelMembers(c.Request.Context(), channelID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		handleServiceError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, members)
 }
 
+// GetChannelPresence returns the members currently connected to a channel
+func (h *ChatHandler) GetChannelPresence(c *gin.Context) {
+	channelID := c.Param("id")
+	userID := c.GetString("userID")
+
+	userIDs, err := h.chatSvc.GetChannelPresence(c.Request.Context(), channelID, userID)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"channelId": channelID,
+		"userIds":   userIDs,
+		"count":     len(userIDs),
+	})
+}
+
 // MarkAsRead marks channel as read
 func (h *ChatHandler) MarkAsRead(c *gin.Context) {
 	channelID := c.Param("id")
 	userID := c.GetString("userID")
 
 	if err := h.chatSvc.MarkChannelAsRead(c.Request.Context(), channelID, userID); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		handleServiceError(c, err)
 		return
 	}
 
@@ -234,56 +256,99 @@ func (h *ChatHandler) SendMessage(c *gin.Context) {
 
 	var req SendMessageRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
 	userID := c.GetString("userID")
 	message, err := h.chatSvc.SendMessage(c.Request.Context(), channelID, userID, req.Content, req.MessageType, req.ParentID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		handleServiceError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusCreated, message)
 }
 
-// GetMessages gets messages from a channel
+// GetMessages returns a page of a channel's messages, newest first, as a
+// bare array; X-Has-More tells whether older pages exist. offset is kept for
+// clients that predate the cursors.
+// GET /api/chat/channels/:id/messages?before=&after=&limit=&offset=
 func (h *ChatHandler) GetMessages(c *gin.Context) {
 	channelID := c.Param("id")
 
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
-	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	offset, _ := strconv.Atoi(c.Query("offset"))
 
-	messages, err := h.chatSvc.GetMessages(c.Request.Context(), channelID, limit, offset)
+	messages, hasMore, err := h.chatSvc.GetMessages(c.Request.Context(), channelID, c.Query("before"), c.Query("after"), limit, offset)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		logAPIError(c, "Chat.GetMessages", err, map[string]interface{}{"channelID": channelID})
+		handleServiceError(c, err)
 		return
 	}
 
+	setHasMoreHeader(c, hasMore)
 	c.JSON(http.StatusOK, messages)
 }
 
-// GetThreadMessages gets thread replies
+// SearchMessages full-text searches a channel's messages
+// GET /api/chat/channels/:id/messages/search?q=
+func (h *ChatHandler) SearchMessages(c *gin.Context) {
+	userID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	query := strings.TrimSpace(c.Query("q"))
+	if query == "" {
+		respondError(c, http.StatusBadRequest, "Query parameter 'q' is required")
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	results, err := h.chatSvc.SearchMessages(c.Request.Context(), c.Param("id"), userID, query, limit)
+	if err != nil {
+		logAPIError(c, "Chat.SearchMessages", err, map[string]interface{}{"channelID": c.Param("id")})
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, results)
+}
+
+// GetThreadMessages returns a thread's replies, oldest first, as a bare
+// array. Without a cursor or limit it returns the whole thread.
+// GET /api/chat/messages/:messageId/thread?before=&after=&limit=
 func (h *ChatHandler) GetThreadMessages(c *gin.Context) {
 	messageID := c.Param("messageId")
 
-	messages, err := h.chatSvc.GetThreadMessages(c.Request.Context(), messageID)
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	messages, hasMore, err := h.chatSvc.GetThreadMessages(c.Request.Context(), messageID, c.Query("before"), c.Query("after"), limit)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		logAPIError(c, "Chat.GetThreadMessages", err, map[string]interface{}{"messageID": messageID})
+		handleServiceError(c, err)
 		return
 	}
 
+	setHasMoreHeader(c, hasMore)
 	c.JSON(http.StatusOK, messages)
 }
 
+// setHasMoreHeader reports whether a message page has more beyond it, which
+// keeps the body the bare array older clients expect
+func setHasMoreHeader(c *gin.Context, hasMore bool) {
+	c.Header("X-Has-More", strconv.FormatBool(hasMore))
+}
+
 // UpdateMessage edits a message
 func (h *ChatHandler) UpdateMessage(c *gin.Context) {
 	messageID := c.Param("messageId")
 
 	var req UpdateMessageRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
@@ -291,10 +356,10 @@ func (h *ChatHandler) UpdateMessage(c *gin.Context) {
 	message, err := h.chatSvc.EditMessage(c.Request.Context(), messageID, userID, req.Content)
 	if err != nil {
 		if err == service.ErrForbidden {
-			c.JSON(http.StatusForbidden, gin.H{"error": "You can only edit your own messages"})
+			respondError(c, http.StatusForbidden, "You can only edit your own messages")
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		handleServiceError(c, err)
 		return
 	}
 
@@ -308,10 +373,10 @@ func (h *ChatHandler) DeleteMessage(c *gin.Context) {
 
 	if err := h.chatSvc.DeleteMessage(c.Request.Context(), messageID, userID); err != nil {
 		if err == service.ErrForbidden {
-			c.JSON(http.StatusForbidden, gin.H{"error": "You can only delete your own messages"})
+			respondError(c, http.StatusForbidden, "You can only delete your own messages")
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		handleServiceError(c, err)
 		return
 	}
 
@@ -328,13 +393,13 @@ func (h *ChatHandler) AddReaction(c *gin.Context) {
 
 	var req ReactionRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
 	userID := c.GetString("userID")
 	if err := h.chatSvc.AddReaction(c.Request.Context(), messageID, userID, req.Emoji); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		handleServiceError(c, err)
 		return
 	}
 
@@ -348,7 +413,7 @@ func (h *ChatHandler) RemoveReaction(c *gin.Context) {
 	userID := c.GetString("userID")
 
 	if err := h.chatSvc.RemoveReaction(c.Request.Context(), messageID, userID, emoji); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		handleServiceError(c, err)
 		return
 	}
 
@@ -361,7 +426,7 @@ func (h *ChatHandler) GetReactions(c *gin.Context) {
 
 	reactions, err := h.chatSvc.GetReactions(c.Request.Context(), messageID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		handleServiceError(c, err)
 		return
 	}
 
@@ -379,7 +444,7 @@ func (h *ChatHandler) GetUnreadCount(c *gin.Context) {
 
 	count, err := h.chatSvc.GetUnreadCount(c.Request.Context(), channelID, userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		handleServiceError(c, err)
 		return
 	}
 
@@ -392,7 +457,7 @@ func (h *ChatHandler) GetAllUnreadCounts(c *gin.Context) {
 
 	counts, err := h.chatSvc.GetAllUnreadCounts(c.Request.Context(), userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		handleServiceError(c, err)
 		return
 	}
 
@@ -410,7 +475,7 @@ func (h *ChatHandler) AddMember(c *gin.Context) {
 
 	var req AddMemberRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
@@ -418,14 +483,14 @@ func (h *ChatHandler) AddMember(c *gin.Context) {
 
 	if err := h.chatSvc.AddMemberToChannel(c.Request.Context(), channelID, req.UserID, currentUserID); err != nil {
 		if err == service.ErrForbidden {
-			c.JSON(http.StatusForbidden, gin.H{"error": "You don't have permission to add members"})
+			respondError(c, http.StatusForbidden, "You don't have permission to add members")
 			return
 		}
 		if err == service.ErrNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Channel not found"})
+			respondError(c, http.StatusNotFound, "Channel not found")
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		handleServiceError(c, err)
 		return
 	}
 
@@ -444,7 +509,7 @@ func (h *ChatHandler) RemoveMember(c *gin.Context) {
 
 	var req RemoveMemberRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
@@ -452,14 +517,14 @@ func (h *ChatHandler) RemoveMember(c *gin.Context) {
 
 	if err := h.chatSvc.RemoveMemberFromChannel(c.Request.Context(), channelID, req.UserID, currentUserID); err != nil {
 		if err == service.ErrForbidden {
-			c.JSON(http.StatusForbidden, gin.H{"error": "You don't have permission to remove members"})
+			respondError(c, http.StatusForbidden, "You don't have permission to remove members")
 			return
 		}
 		if err == service.ErrNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Channel not found"})
+			respondError(c, http.StatusNotFound, "Channel not found")
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		handleServiceError(c, err)
 		return
 	}
 
@@ -475,17 +540,17 @@ func (h *ChatHandler) UpdateChannel(c *gin.Context) {
 		IsPrivate bool   `json:"isPrivate"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
 	channel, err := h.chatSvc.UpdateChannel(c.Request.Context(), channelID, req.Name, req.IsPrivate)
 	if err != nil {
 		if err == service.ErrNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Channel not found"})
+			respondError(c, http.StatusNotFound, "Channel not found")
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		handleServiceError(c, err)
 		return
 	}
 
@@ -501,10 +566,10 @@ func (h *ChatHandler) ArchiveChannel(c *gin.Context) {
 
 	if err := h.chatSvc.ArchiveChannel(c.Request.Context(), channelID, userID); err != nil {
 		if err == service.ErrForbidden {
-			c.JSON(http.StatusForbidden, gin.H{"error": "You don't have permission to archive this channel"})
+			respondError(c, http.StatusForbidden, "You don't have permission to archive this channel")
 			return
 		}
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/repository"
+)
+
+func TestCommentByDeletedUserRendersPlaceholder(t *testing.T) {
+	deletedAt := time.Now()
+	comment := &repository.TaskComment{
+		ID:      "c1",
+		TaskID:  "t1",
+		UserID:  "gone",
+		Content: "Written before I left",
+		User:    &repository.User{ID: "gone", Name: "Former Name", Email: "scrubbed@example.com", DeletedAt: &deletedAt},
+	}
+
+	body, err := json.Marshal(toCommentResponse(comment))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got struct {
+		Content string `json:"content"`
+		User    struct {
+			ID      string `json:"id"`
+			Name    string `json:"name"`
+			Email   string `json:"email"`
+			Deleted bool   `json:"deleted"`
+		} `json:"user"`
+	}
+	if err := json.Unmarshal(body, &got); err != nil {
+		t.Fatal(err)
+	}
+
+	if got.Content != comment.Content {
+		t.Errorf("content = %q, want the original comment", got.Content)
+	}
+	if got.User.ID != "gone" || got.User.Name != "Deleted user" || !got.User.Deleted {
+		t.Errorf("author = %+v, want Deleted user marked deleted", got.User)
+	}
+	if got.User.Email != "" {
+		t.Errorf("deleted author still exposes email %q", got.User.Email)
+	}
+}
@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"sort"
+
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/models"
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// ============================================
+// Error Responses
+// ============================================
+
+// serviceErrors maps service sentinel errors to a status and code. Entries
+// are matched in order with errors.Is, so an error must come before any
+// error it wraps. When message is empty the error's own text is sent.
+var serviceErrors = []struct {
+	err     error
+	status  int
+	code    string
+	message string
+}{
+	// Authentication
+	{service.ErrInvalidCredentials, http.StatusUnauthorized, models.CodeInvalidCredentials, "Invalid credentials"},
+	{service.ErrInvalidToken, http.StatusUnauthorized, models.CodeInvalidToken, ""},
+	{service.ErrRefreshTokenExpired, http.StatusUnauthorized, models.CodeRefreshTokenExpired, "Refresh token expired"},
+	{service.ErrRefreshTokenReused, http.StatusUnauthorized, models.CodeRefreshTokenReused, "Refresh token already used, all sessions have been signed out"},
+
+	// Permissions
+	{service.ErrUnauthorized, http.StatusForbidden, models.CodeUnauthorized, "Unauthorized"},
+	{service.ErrForbidden, http.StatusForbidden, models.CodeUnauthorized, "Unauthorized"},
+	{service.ErrEmailDomainNotAllowed, http.StatusForbidden, models.CodeEmailDomainNotAllowed, ""},
+
+	// Missing resources
+	{service.ErrUserNotFound, http.StatusNotFound, models.CodeUserNotFound, "User not found"},
+	{service.ErrTaskNotFound, http.StatusNotFound, models.CodeTaskNotFound, "Task not found"},
+	{service.ErrCommentNotFound, http.StatusNotFound, models.CodeCommentNotFound, "Comment not found"},
+	{service.ErrAttachmentNotFound, http.StatusNotFound, models.CodeAttachmentNotFound, "Attachment not found"},
+	{service.ErrProjectNotFound, http.StatusNotFound, models.CodeProjectNotFound, "Project not found"},
+	{service.ErrSprintNotFound, http.StatusNotFound, models.CodeSprintNotFound, "Sprint not found"},
+	{service.ErrWorkspaceNotFound, http.StatusNotFound, models.CodeWorkspaceNotFound, "Workspace not found"},
+	{service.ErrSpaceNotFound, http.StatusNotFound, models.CodeSpaceNotFound, "Space not found"},
+	{service.ErrFolderNotFound, http.StatusNotFound, models.CodeFolderNotFound, "Folder not found"},
+	{service.ErrGoalNotFound, http.StatusNotFound, models.CodeGoalNotFound, "Goal not found"},
+	{service.ErrLabelNotFound, http.StatusNotFound, models.CodeLabelNotFound, "Label not found"},
+	{service.ErrTeamNotFound, http.StatusNotFound, models.CodeTeamNotFound, "Team not found"},
+	{service.ErrNotFound, http.StatusNotFound, models.CodeNotFound, "Resource not found"},
+
+	// Conflicts
+	{service.ErrStaleVersion, http.StatusConflict, models.CodeStaleVersion, ""},
+	{service.ErrUserExists, http.StatusConflict, models.CodeUserExists, "User already exists"},
+	{service.ErrSprintAlreadyActive, http.StatusConflict, models.CodeSprintAlreadyActive, ""},
+	{service.ErrWIPLimitExceeded, http.StatusConflict, models.CodeWIPLimitExceeded, ""},
+	{service.ErrLastOwner, http.StatusBadRequest, models.CodeLastOwner, ""},
+	{service.ErrHasSubtasks, http.StatusConflict, models.CodeHasSubtasks, ""},
+	{service.ErrLimitExceeded, http.StatusConflict, models.CodeLimitExceeded, ""},
+	{service.ErrConflict, http.StatusConflict, models.CodeConflict, ""},
+
+	// Invalid requests
+	{service.ErrInvalidTransition, http.StatusUnprocessableEntity, models.CodeInvalidTransition, ""},
+	{service.ErrSprintNoTasks, http.StatusUnprocessableEntity, models.CodeSprintNoTasks, ""},
+	{service.ErrFileTooLarge, http.StatusRequestEntityTooLarge, models.CodeFileTooLarge, ""},
+	{service.ErrTooManyRequests, http.StatusTooManyRequests, models.CodeRateLimited, ""},
+	{service.ErrInvalidEntityType, http.StatusBadRequest, models.CodeInvalidEntityType, "Invalid entity type"},
+	{service.ErrBadRequest, http.StatusBadRequest, models.CodeInvalidInput, ""},
+	{service.ErrInvalidInput, http.StatusBadRequest, models.CodeInvalidInput, ""},
+}
+
+// statusCodes is the code of an error response that has no more specific one
+var statusCodes = map[int]string{
+	http.StatusBadRequest:            models.CodeInvalidInput,
+	http.StatusUnauthorized:          models.CodeUnauthenticated,
+	http.StatusForbidden:             models.CodeUnauthorized,
+	http.StatusNotFound:              models.CodeNotFound,
+	http.StatusConflict:              models.CodeConflict,
+	http.StatusGone:                  models.CodeGone,
+	http.StatusRequestEntityTooLarge: models.CodePayloadTooLarge,
+	http.StatusUnprocessableEntity:   models.CodeUnprocessable,
+	http.StatusTooManyRequests:       models.CodeRateLimited,
+}
+
+// handleServiceError writes the response for an error returned by a service.
+// Errors that are not in serviceErrors are reported as a bare 500, without
+// their text.
+func handleServiceError(c *gin.Context, err error) {
+	status, code, message := serviceErrorResponse(err)
+	respondErrorCode(c, status, code, message, nil)
+}
+
+func serviceErrorResponse(err error) (status int, code, message string) {
+	for _, e := range serviceErrors {
+		if errors.Is(err, e.err) {
+			if e.message == "" {
+				return e.status, e.code, err.Error()
+			}
+			return e.status, e.code, e.message
+		}
+	}
+	return http.StatusInternalServerError, models.CodeInternal, "Internal server error"
+}
+
+// logAPIError logs an error a handler got from a service, with the request's
+// ID and user from its context. Errors that map to a client error status are
+// logged at warn level, the rest at error level.
+func logAPIError(c *gin.Context, action string, err error, fields map[string]interface{}) {
+	level := slog.LevelError
+	if status, _, _ := serviceErrorResponse(err); status < http.StatusInternalServerError {
+		level = slog.LevelWarn
+	}
+
+	attrs := []slog.Attr{
+		slog.String("action", action),
+		slog.String("route", c.FullPath()),
+		slog.Any("error", err),
+	}
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		attrs = append(attrs, slog.Any(key, fields[key]))
+	}
+	slog.LogAttrs(c.Request.Context(), level, "api error", attrs...)
+}
+
+// respondError writes an error with the generic code of its status
+func respondError(c *gin.Context, status int, message string) {
+	code, ok := statusCodes[status]
+	if !ok {
+		code = models.CodeInternal
+	}
+	respondErrorCode(c, status, code, message, nil)
+}
+
+// respondBindError rejects a request body or query that failed to bind. For
+// failed validation rules the details list each field and rule.
+func respondBindError(c *gin.Context, err error) {
+	var validationErrs validator.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		respondErrorCode(c, http.StatusBadRequest, models.CodeInvalidInput, err.Error(), nil)
+		return
+	}
+	details := make([]models.FieldError, len(validationErrs))
+	for i, fe := range validationErrs {
+		details[i] = models.FieldError{Field: fe.Field(), Rule: fe.Tag()}
+	}
+	respondErrorCode(c, http.StatusBadRequest, models.CodeInvalidInput, err.Error(), details)
+}
+
+// respondFormFileError rejects a multipart upload whose file part could not be
+// read, telling an over-limit body (see middleware.LimitBody) apart
+func respondFormFileError(c *gin.Context, err error) {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		respondErrorCode(c, http.StatusRequestEntityTooLarge, models.CodeFileTooLarge, "file is too large", nil)
+		return
+	}
+	respondError(c, http.StatusBadRequest, "file is required")
+}
+
+func respondErrorCode(c *gin.Context, status int, code, message string, details interface{}) {
+	c.JSON(status, models.NewErrorResponse(code, message, details))
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/Marga-Ghale/ora-scrum-backend/internal/api/middleware"
@@ -26,7 +27,7 @@ func (h *FolderHandler) ListBySpace(c *gin.Context) {
 
 	folders, err := h.folderService.ListBySpace(c.Request.Context(), spaceID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch folders"})
+		handleServiceError(c, err)
 		return
 	}
 
@@ -48,7 +49,7 @@ func (h *FolderHandler) ListByUser(c *gin.Context) {
 
 	folders, err := h.folderService.ListByUser(c.Request.Context(), userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch folders"})
+		handleServiceError(c, err)
 		return
 	}
 
@@ -66,7 +67,7 @@ func (h *FolderHandler) Create(c *gin.Context) {
 	// Get spaceID from URL parameter
 	spaceID := c.Param("id") // Changed from "spaceId" to "id"
 	if spaceID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "spaceId is required"})
+		respondError(c, http.StatusBadRequest, "spaceId is required")
 		return
 	}
 
@@ -77,7 +78,7 @@ func (h *FolderHandler) Create(c *gin.Context) {
 
 	var req models.CreateFolderRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
@@ -91,15 +92,15 @@ func (h *FolderHandler) Create(c *gin.Context) {
 		req.Color,       // color from body
 	)
 	if err != nil {
-		if err == service.ErrNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Space not found"})
+		if errors.Is(err, service.ErrNotFound) {
+			handleServiceError(c, err)
 			return
 		}
 		if err == service.ErrUnauthorized {
-			c.JSON(http.StatusForbidden, gin.H{"error": "Unauthorized to create folder in this space"})
+			respondError(c, http.StatusForbidden, "Unauthorized to create folder in this space")
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create folder"})
+		handleServiceError(c, err)
 		return
 	}
 
@@ -113,11 +114,7 @@ func (h *FolderHandler) Get(c *gin.Context) {
 
 	folder, err := h.folderService.GetByID(c.Request.Context(), id)
 	if err != nil {
-		if err == service.ErrNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Folder not found"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch folder"})
+		handleServiceError(c, err)
 		return
 	}
 
@@ -131,7 +128,7 @@ func (h *FolderHandler) Update(c *gin.Context) {
 
 	var req models.UpdateFolderRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
@@ -147,11 +144,7 @@ func (h *FolderHandler) Update(c *gin.Context) {
 		req.AllowedTeams,
 	)
 	if err != nil {
-		if err == service.ErrNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Folder not found"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update folder"})
+		handleServiceError(c, err)
 		return
 	}
 
@@ -164,11 +157,7 @@ func (h *FolderHandler) Delete(c *gin.Context) {
 	id := c.Param("id")
 
 	if err := h.folderService.Delete(c.Request.Context(), id); err != nil {
-		if err == service.ErrNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Folder not found"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete folder"})
+		handleServiceError(c, err)
 		return
 	}
 
@@ -186,7 +175,7 @@ func (h *FolderHandler) UpdateVisibility(c *gin.Context) {
 		AllowedTeams []string `json:"allowedTeams"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
@@ -197,11 +186,7 @@ func (h *FolderHandler) UpdateVisibility(c *gin.Context) {
 		req.AllowedUsers,
 		req.AllowedTeams,
 	); err != nil {
-		if err == service.ErrNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Folder not found"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update visibility"})
+		handleServiceError(c, err)
 		return
 	}
 
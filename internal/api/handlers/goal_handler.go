@@ -28,7 +28,7 @@ func (h *GoalHandler) Create(c *gin.Context) {
 
 	var req service.CreateGoalRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
@@ -36,7 +36,7 @@ func (h *GoalHandler) Create(c *gin.Context) {
 
 	goal, err := h.goalService.Create(c.Request.Context(), &req)
 	if err != nil {
-		handleGoalError(c, err)
+		handleServiceError(c, err)
 		return
 	}
 
@@ -52,7 +52,7 @@ func (h *GoalHandler) Get(c *gin.Context) {
 	goalID := c.Param("id")
 	goal, err := h.goalService.GetByID(c.Request.Context(), goalID, userID)
 	if err != nil {
-		handleGoalError(c, err)
+		handleServiceError(c, err)
 		return
 	}
 
@@ -79,7 +79,7 @@ func (h *GoalHandler) ListByWorkspace(c *gin.Context) {
 
 	goals, err := h.goalService.ListByWorkspace(c.Request.Context(), workspaceID, userID, goalTypePtr, statusPtr)
 	if err != nil {
-		handleGoalError(c, err)
+		handleServiceError(c, err)
 		return
 	}
 
@@ -95,7 +95,7 @@ func (h *GoalHandler) ListByProject(c *gin.Context) {
 	projectID := c.Param("id")
 	goals, err := h.goalService.ListByProject(c.Request.Context(), projectID, userID)
 	if err != nil {
-		handleGoalError(c, err)
+		handleServiceError(c, err)
 		return
 	}
 
@@ -111,7 +111,7 @@ func (h *GoalHandler) ListBySprint(c *gin.Context) {
 	sprintID := c.Param("id")  // ✅ FIXED - was "sprintId"
 	goals, err := h.goalService.ListBySprint(c.Request.Context(), sprintID, userID)
 	if err != nil {
-		handleGoalError(c, err)
+		handleServiceError(c, err)
 		return
 	}
 
@@ -123,7 +123,7 @@ func (h *GoalHandler) GetSprintGoalsSummary(c *gin.Context) {
 
 	summary, err := h.goalService.GetSprintGoalsSummary(c.Request.Context(), sprintID)
 	if err != nil {
-		handleGoalError(c, err)
+		handleServiceError(c, err)
 		return
 	}
 
@@ -139,13 +139,13 @@ func (h *GoalHandler) Update(c *gin.Context) {
 	goalID := c.Param("id")
 	var req service.UpdateGoalRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
 	goal, err := h.goalService.Update(c.Request.Context(), goalID, userID, &req)
 	if err != nil {
-		handleGoalError(c, err)
+		handleServiceError(c, err)
 		return
 	}
 
@@ -163,13 +163,13 @@ func (h *GoalHandler) UpdateProgress(c *gin.Context) {
 		CurrentValue float64 `json:"currentValue" binding:"required"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
 	err := h.goalService.UpdateProgress(c.Request.Context(), goalID, userID, req.CurrentValue)
 	if err != nil {
-		handleGoalError(c, err)
+		handleServiceError(c, err)
 		return
 	}
 
@@ -187,13 +187,13 @@ func (h *GoalHandler) UpdateStatus(c *gin.Context) {
 		Status string `json:"status" binding:"required"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
 	err := h.goalService.UpdateStatus(c.Request.Context(), goalID, userID, req.Status)
 	if err != nil {
-		handleGoalError(c, err)
+		handleServiceError(c, err)
 		return
 	}
 
@@ -209,7 +209,7 @@ func (h *GoalHandler) Delete(c *gin.Context) {
 	goalID := c.Param("id")
 	err := h.goalService.Delete(c.Request.Context(), goalID, userID)
 	if err != nil {
-		handleGoalError(c, err)
+		handleServiceError(c, err)
 		return
 	}
 
@@ -229,13 +229,13 @@ func (h *GoalHandler) AddKeyResult(c *gin.Context) {
 	goalID := c.Param("id")
 	var req service.CreateKeyResultRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
 	kr, err := h.goalService.AddKeyResult(c.Request.Context(), goalID, userID, &req)
 	if err != nil {
-		handleGoalError(c, err)
+		handleServiceError(c, err)
 		return
 	}
 
@@ -251,13 +251,13 @@ func (h *GoalHandler) UpdateKeyResult(c *gin.Context) {
 	keyResultID := c.Param("krId")
 	var req service.UpdateKeyResultRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
 	err := h.goalService.UpdateKeyResult(c.Request.Context(), keyResultID, userID, &req)
 	if err != nil {
-		handleGoalError(c, err)
+		handleServiceError(c, err)
 		return
 	}
 
@@ -275,13 +275,13 @@ func (h *GoalHandler) UpdateKeyResultProgress(c *gin.Context) {
 		CurrentValue float64 `json:"currentValue" binding:"required"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
 	err := h.goalService.UpdateKeyResultProgress(c.Request.Context(), keyResultID, userID, req.CurrentValue)
 	if err != nil {
-		handleGoalError(c, err)
+		handleServiceError(c, err)
 		return
 	}
 
@@ -297,7 +297,7 @@ func (h *GoalHandler) DeleteKeyResult(c *gin.Context) {
 	keyResultID := c.Param("krId")
 	err := h.goalService.DeleteKeyResult(c.Request.Context(), keyResultID, userID)
 	if err != nil {
-		handleGoalError(c, err)
+		handleServiceError(c, err)
 		return
 	}
 
@@ -319,13 +319,13 @@ func (h *GoalHandler) LinkTask(c *gin.Context) {
 		TaskID string `json:"taskId" binding:"required"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
 	err := h.goalService.LinkTask(c.Request.Context(), goalID, req.TaskID, userID)
 	if err != nil {
-		handleGoalError(c, err)
+		handleServiceError(c, err)
 		return
 	}
 
@@ -343,7 +343,7 @@ func (h *GoalHandler) UnlinkTask(c *gin.Context) {
 
 	err := h.goalService.UnlinkTask(c.Request.Context(), goalID, taskID, userID)
 	if err != nil {
-		handleGoalError(c, err)
+		handleServiceError(c, err)
 		return
 	}
 
@@ -359,7 +359,7 @@ func (h *GoalHandler) GetGoalsByTask(c *gin.Context) {
 	taskID := c.Param("taskId")
 	goals, err := h.goalService.GetGoalsByTask(c.Request.Context(), taskID, userID)
 	if err != nil {
-		handleGoalError(c, err)
+		handleServiceError(c, err)
 		return
 	}
 
@@ -375,7 +375,7 @@ func (h *GoalHandler) GetGoalProgress(c *gin.Context) {
 
 	progress, err := h.goalService.GetGoalProgress(c.Request.Context(), goalID)
 	if err != nil {
-		handleGoalError(c, err)
+		handleServiceError(c, err)
 		return
 	}
 
@@ -384,21 +384,3 @@ func (h *GoalHandler) GetGoalProgress(c *gin.Context) {
 		"progress": progress,
 	})
 }
-
-
-// ============================================
-// ERROR HANDLER
-// ============================================
-
-func handleGoalError(c *gin.Context, err error) {
-	switch err {
-	case service.ErrUnauthorized:
-		c.JSON(http.StatusForbidden, gin.H{"error": "Unauthorized"})
-	case service.ErrNotFound:
-		c.JSON(http.StatusNotFound, gin.H{"error": "Goal not found"})
-	case service.ErrInvalidInput:
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
-	default:
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
-	}
-}
\ No newline at end of file
@@ -1,12 +1,9 @@
 package handlers
 
 import (
-	"net/http"
-
 	"github.com/Marga-Ghale/ora-scrum-backend/internal/models"
 	"github.com/Marga-Ghale/ora-scrum-backend/internal/repository"
 	"github.com/Marga-Ghale/ora-scrum-backend/internal/service"
-	"github.com/gin-gonic/gin"
 )
 
 // Handlers contains all HTTP handlers
@@ -24,6 +21,12 @@ type Handlers struct {
 	Goal  	 *GoalHandler
 	SprintAnalytics *SprintAnalyticsHandler
 	Sprint 	 *SprintHandler
+	RecurringTask *RecurringTaskHandler
+	ProjectStatus *ProjectStatusHandler
+	AccessRequest *AccessRequestHandler
+	Calendar      *CalendarHandler
+	Webhook       *WebhookHandler
+	SCM           *SCMHandler
 }
 
 // NewHandlers creates all handlers
@@ -42,6 +45,12 @@ func NewHandlers(services *service.Services) *Handlers {
 		Goal:         &GoalHandler{goalService: services.Goal},
 		SprintAnalytics: &SprintAnalyticsHandler{analyticsService: services.SprintAnalytics},
 		Sprint: NewSprintHandler(services.Sprint, services.SprintAnalytics),  
+		RecurringTask: NewRecurringTaskHandler(services.RecurringTask),
+		ProjectStatus: NewProjectStatusHandler(services.ProjectStatus),
+		AccessRequest: NewAccessRequestHandler(services.AccessRequest),
+		Calendar:      &CalendarHandler{calendarService: services.Calendar},
+		Webhook:       &WebhookHandler{webhookService: services.Webhook},
+		SCM:           &SCMHandler{scmService: services.SCM},
 	}
 }
 // ============================================
@@ -49,6 +58,15 @@ func NewHandlers(services *service.Services) *Handlers {
 // ============================================
 
 func toUserResponse(u *repository.User) models.UserResponse {
+	if u.DeletedAt != nil {
+		return models.UserResponse{
+			ID:        u.ID,
+			Name:      repository.DeletedUserName,
+			Status:    "offline",
+			Deleted:   true,
+			CreatedAt: u.CreatedAt,
+		}
+	}
 	return models.UserResponse{
 		ID:        u.ID,
 		Email:     u.Email,
@@ -68,8 +86,21 @@ func toTaskResponse(t *repository.Task) models.TaskResponse {
 		return models.TaskResponse{}
 	}
 
+	var labels []models.LabelResponse
+	if t.Labels != nil {
+		labels = make([]models.LabelResponse, len(t.Labels))
+		for i, l := range t.Labels {
+			labels[i] = toLabelResponse(l)
+		}
+	}
+
+	variance, variancePercent := hoursVariance(t.EstimatedHours, t.ActualHours)
+
 	return models.TaskResponse{
 		ID:             t.ID,
+		Key:            t.Key,
+		Number:         t.Number,
+		Version:        t.Version,
 		Title:          t.Title,
 		Description:    t.Description,
 		Status:         t.Status,
@@ -81,9 +112,12 @@ func toTaskResponse(t *repository.Task) models.TaskResponse {
 		AssigneeIDs:    safeStringSlice(t.AssigneeIDs),
 		WatcherIDs:     safeStringSlice(t.WatcherIDs),
 		LabelIDs:       safeStringSlice(t.LabelIDs),
+		Labels:         labels,
 		StoryPoints:    t.StoryPoints,
 		EstimatedHours: t.EstimatedHours,
 		ActualHours:    t.ActualHours,
+		HoursVariance:        variance,
+		HoursVariancePercent: variancePercent,
 		StartDate:      t.StartDate,
 		DueDate:        t.DueDate,
 		CompletedAt:    t.CompletedAt,
@@ -92,8 +126,13 @@ func toTaskResponse(t *repository.Task) models.TaskResponse {
 		CreatedBy:      t.CreatedBy,
 		CreatedAt:      t.CreatedAt,
 		UpdatedAt:      t.UpdatedAt,
-		SubtaskCount:   0,
+		SubtaskCount:   t.SubtaskTotal,
 		Subtasks:       nil,
+		ChecklistTotal:     t.ChecklistTotal,
+		ChecklistCompleted: t.ChecklistCompleted,
+		SubtaskTotal:       t.SubtaskTotal,
+		SubtaskCompleted:   t.SubtaskCompleted,
+		SubtaskStoryPoints: t.SubtaskStoryPoints,
 		
 		// ✅ CYCLE TIME TRACKING
 		StartedAt:        t.StartedAt,
@@ -102,6 +141,21 @@ func toTaskResponse(t *repository.Task) models.TaskResponse {
 	}
 }
 
+// hoursVariance returns actual - estimated hours and that as a percentage of
+// the estimate, or nils when there is no estimate. No logged time counts as zero.
+func hoursVariance(estimated, actual *float64) (*float64, *float64) {
+	if estimated == nil || *estimated <= 0 {
+		return nil, nil
+	}
+	spent := 0.0
+	if actual != nil {
+		spent = *actual
+	}
+	variance := spent - *estimated
+	percent := variance / *estimated * 100
+	return &variance, &percent
+}
+
 // Enhanced converter with subtasks
 func toTaskResponseWithSubtasks(t *repository.Task, subtasks []*repository.Task) models.TaskResponse {
 	response := toTaskResponse(t)  // ✅ Gets parent task with cycle time fields
@@ -152,6 +206,7 @@ func toNotificationResponse(n *repository.Notification) models.NotificationRespo
 		Title:     n.Title,
 		Message:   n.Message,
 		Read:      n.Read,
+		Count:     n.Count,
 		CreatedAt: n.CreatedAt,
 	}
 	if n.Data != nil {
@@ -293,20 +348,3 @@ func toWorkspaceResponse(ws *repository.Workspace) models.WorkspaceResponse {
 
 	return resp
 }
-
-// ============================================
-// HELPER FUNCTIONS
-// ============================================
-
-func handleServiceError(c *gin.Context, err error) {
-	switch err {
-	case service.ErrUnauthorized:
-		c.JSON(http.StatusForbidden, gin.H{"error": "Unauthorized"})
-	case service.ErrNotFound:
-		c.JSON(http.StatusNotFound, gin.H{"error": "Resource not found"})
-	case service.ErrInvalidInput:
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
-	default:
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
-	}
-}
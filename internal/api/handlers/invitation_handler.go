@@ -1,10 +1,13 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 	"time"
 
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/api/middleware"
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/models"
 	"github.com/Marga-Ghale/ora-scrum-backend/internal/repository"
 	"github.com/Marga-Ghale/ora-scrum-backend/internal/service"
 	"github.com/gin-gonic/gin"
@@ -33,13 +36,13 @@ func (h *InvitationHandler) CreateWorkspaceInvitation(c *gin.Context) {
 
 	var req CreateInvitationRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
 	inv, err := h.invSvc.CreateWorkspaceInvitation(c.Request.Context(), workspaceID, req.Email, req.Role, userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondInvitationCreateError(c, err)
 		return
 	}
 
@@ -64,13 +67,13 @@ func (h *InvitationHandler) CreateProjectInvitation(c *gin.Context) {
 
 	var req CreateInvitationRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
 	inv, err := h.invSvc.CreateProjectInvitation(c.Request.Context(), req.WorkspaceID, projectID, req.Email, req.Role, userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondInvitationCreateError(c, err)
 		return
 	}
 
@@ -97,10 +100,11 @@ func (h *InvitationHandler) GetWorkspaceInvitations(c *gin.Context) {
 
 	invitations, total, err := h.invSvc.ListByWorkspace(c.Request.Context(), workspaceID, limit, offset)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		handleServiceError(c, err)
 		return
 	}
 
+	setPaginationHeaders(c, total, limit, offset)
 	c.JSON(http.StatusOK, gin.H{
 		"invitations": invitations,
 		"total":       total,
@@ -126,10 +130,11 @@ func (h *InvitationHandler) GetProjectInvitations(c *gin.Context) {
 
 	invitations, total, err := h.invSvc.ListByProject(c.Request.Context(), projectID, limit, offset)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		handleServiceError(c, err)
 		return
 	}
 
+	setPaginationHeaders(c, total, limit, offset)
 	c.JSON(http.StatusOK, gin.H{
 		"invitations": invitations,
 		"total":       total,
@@ -138,6 +143,73 @@ func (h *InvitationHandler) GetProjectInvitations(c *gin.Context) {
 	})
 }
 
+// BulkInviteWorkspace godoc
+// @Summary Invite many emails at once
+// @Tags invitations
+// @Accept json
+// @Produce json
+// @Param id path string true "Workspace ID"
+// @Param request body BulkInvitationRequest true "Bulk invitation details"
+// @Success 202 {object} map[string]interface{}
+// @Router /workspaces/{id}/invitations/bulk [post]
+func (h *InvitationHandler) BulkInviteWorkspace(c *gin.Context) {
+	workspaceID := c.Param("id")
+	userID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	var req BulkInvitationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	result, err := h.invSvc.BulkInvite(
+		c.Request.Context(),
+		workspaceID,
+		userID,
+		req.Emails,
+		repository.InvitationType(req.Type),
+		req.TargetID,
+		repository.WorkspaceRole(req.Role),
+	)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": "Bulk invitation started",
+		"result":  result,
+	})
+}
+
+// GetBulkInvitationResult godoc
+// @Summary Get bulk invitation progress
+// @Tags invitations
+// @Produce json
+// @Param id path string true "Workspace ID"
+// @Param resultId path string true "Bulk result ID"
+// @Success 200 {object} map[string]interface{}
+// @Router /workspaces/{id}/invitations/bulk/{resultId} [get]
+func (h *InvitationHandler) GetBulkInvitationResult(c *gin.Context) {
+	userID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.invSvc.GetBulkResult(c.Request.Context(), c.Param("id"), c.Param("resultId"), userID)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"result": result,
+	})
+}
+
 // GetMyInvitations godoc
 // @Summary Get my pending invitations
 // @Tags invitations
@@ -149,7 +221,7 @@ func (h *InvitationHandler) GetMyInvitations(c *gin.Context) {
 
 	invitations, err := h.invSvc.GetMyInvitations(c.Request.Context(), userEmail)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		handleServiceError(c, err)
 		return
 	}
 
@@ -171,7 +243,7 @@ func (h *InvitationHandler) AcceptInvitation(c *gin.Context) {
 
 	err := h.invSvc.AcceptByToken(c.Request.Context(), token, userID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
@@ -193,7 +265,11 @@ func (h *InvitationHandler) ResendInvitation(c *gin.Context) {
 
 	inv, err := h.invSvc.ResendInvitation(c.Request.Context(), id, &userID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		if errors.Is(err, service.ErrTooManyRequests) {
+			respondError(c, http.StatusTooManyRequests, err.Error())
+			return
+		}
+		respondError(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
@@ -216,7 +292,7 @@ func (h *InvitationHandler) CancelInvitation(c *gin.Context) {
 
 	err := h.invSvc.CancelInvitation(c.Request.Context(), id, userID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
@@ -232,33 +308,45 @@ func (h *InvitationHandler) CancelInvitation(c *gin.Context) {
 // @Produce json
 // @Param request body AcceptLinkRequest true "Link details"
 // @Success 200 {object} map[string]interface{}
+// @Success 202 {object} map[string]interface{} "Link requires approval; access request created"
 // @Router /invitations/accept-link [post]
 func (h *InvitationHandler) AcceptInvitationByLink(c *gin.Context) {
 	var req AcceptLinkRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
 	userID := c.GetString("user_id")
 	userEmail := c.GetString("user_email")
 
-	inv, settings, err := h.invSvc.UseLink(c.Request.Context(), req.LinkToken, userEmail)
+	result, err := h.invSvc.AcceptLink(c.Request.Context(), req.LinkToken, userID, userEmail)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		switch {
+		case errors.Is(err, service.ErrEmailDomainNotAllowed):
+			handleServiceError(c, err)
+		case errors.Is(err, repository.ErrLinkUnavailable):
+			respondError(c, http.StatusGone, err.Error())
+		default:
+			respondError(c, http.StatusBadRequest, err.Error())
+		}
 		return
 	}
 
-	err = h.invSvc.AcceptByID(c.Request.Context(), inv.ID, userID)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if result.PendingApproval {
+		c.JSON(http.StatusAccepted, gin.H{
+			"message":       "Access request sent for approval",
+			"status":        "pending_approval",
+			"accessRequest": result.AccessRequest,
+			"settings":      result.Settings,
+		})
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"message":    "Invitation accepted successfully",
-		"invitation": inv,
-		"settings":   settings,
+		"invitation": result.Invitation,
+		"settings":   result.Settings,
 	})
 }
 
@@ -275,7 +363,7 @@ func (h *InvitationHandler) CreateLinkInvitation(c *gin.Context) {
 
 	var req CreateLinkInvitationRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
@@ -296,7 +384,7 @@ func (h *InvitationHandler) CreateLinkInvitation(c *gin.Context) {
 
 	err := h.invSvc.CreateLinkSettings(c.Request.Context(), settings)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		handleServiceError(c, err)
 		return
 	}
 
@@ -318,12 +406,12 @@ func (h *InvitationHandler) GetLinkInvitation(c *gin.Context) {
 
 	settings, err := h.invSvc.GetLinkSettingsByToken(c.Request.Context(), token)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Link not found"})
+		respondError(c, http.StatusNotFound, "Link not found")
 		return
 	}
 
 	if !settings.IsValid() {
-		c.JSON(http.StatusGone, gin.H{"error": "Link is expired or inactive"})
+		respondError(c, http.StatusGone, "Link is expired or inactive")
 		return
 	}
 
@@ -342,13 +430,13 @@ func (h *InvitationHandler) GetLinkInvitation(c *gin.Context) {
 func (h *InvitationHandler) GetInvitationStats(c *gin.Context) {
 	workspaceID := c.Query("workspaceId")
 	if workspaceID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "workspaceId is required"})
+		respondError(c, http.StatusBadRequest, "workspaceId is required")
 		return
 	}
 
 	stats, err := h.invSvc.GetStatsByWorkspace(c.Request.Context(), workspaceID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		handleServiceError(c, err)
 		return
 	}
 
@@ -363,6 +451,26 @@ type CreateInvitationRequest struct {
 	WorkspaceID string `json:"workspace_id,omitempty"`
 }
 
+// respondInvitationCreateError surfaces capacity limits with the numbers the UI needs
+func respondInvitationCreateError(c *gin.Context, err error) {
+	var limitErr *service.InvitationLimitError
+	if errors.As(err, &limitErr) {
+		respondErrorCode(c, http.StatusConflict, models.CodeLimitExceeded, err.Error(), gin.H{
+			"pendingCount": limitErr.PendingCount,
+			"limit":        limitErr.Limit,
+		})
+		return
+	}
+	handleServiceError(c, err)
+}
+
+type BulkInvitationRequest struct {
+	Emails   []string `json:"emails" binding:"required,min=1,max=100"`
+	Type     string   `json:"type,omitempty"`
+	TargetID string   `json:"target_id,omitempty"`
+	Role     string   `json:"role" binding:"required"`
+}
+
 type AcceptLinkRequest struct {
 	LinkToken string `json:"link_token" binding:"required"`
 }
@@ -3,7 +3,9 @@ package handlers
 import (
 	"net/http"
 
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/api/middleware"
 	"github.com/Marga-Ghale/ora-scrum-backend/internal/models"
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/repository"
 	"github.com/Marga-Ghale/ora-scrum-backend/internal/service"
 	"github.com/gin-gonic/gin"
 )
@@ -19,15 +21,15 @@ type LabelHandler struct {
 func (h *LabelHandler) ListByProject(c *gin.Context) {
 	projectID := c.Param("id")
 
-	labels, err := h.labelService.ListByProject(c.Request.Context(), projectID)
+	labels, err := h.labelService.ListUsageByProject(c.Request.Context(), projectID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch labels"})
+		handleServiceError(c, err)
 		return
 	}
 
-	response := make([]models.LabelResponse, len(labels))
+	response := make([]models.LabelUsageResponse, len(labels))
 	for i, l := range labels {
-		response[i] = toLabelResponse(l)
+		response[i] = toLabelUsageResponse(l)
 	}
 
 	c.JSON(http.StatusOK, response)
@@ -38,17 +40,17 @@ func (h *LabelHandler) Create(c *gin.Context) {
 
 	var req models.CreateLabelRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
 	label, err := h.labelService.Create(c.Request.Context(), projectID, req.Name, req.Color)
 	if err != nil {
 		if err == service.ErrConflict {
-			c.JSON(http.StatusConflict, gin.H{"error": "Label with this name already exists"})
+			respondError(c, http.StatusConflict, "Label with this name already exists")
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create label"})
+		handleServiceError(c, err)
 		return
 	}
 
@@ -60,21 +62,17 @@ func (h *LabelHandler) Update(c *gin.Context) {
 
 	var req models.UpdateLabelRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
 	label, err := h.labelService.Update(c.Request.Context(), id, req.Name, req.Color)
 	if err != nil {
 		if err == service.ErrConflict {
-			c.JSON(http.StatusConflict, gin.H{"error": "Label with this name already exists"})
+			respondError(c, http.StatusConflict, "Label with this name already exists")
 			return
 		}
-		if err == service.ErrNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Label not found"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update label"})
+		handleServiceError(c, err)
 		return
 	}
 
@@ -85,9 +83,44 @@ func (h *LabelHandler) Delete(c *gin.Context) {
 	id := c.Param("id")
 
 	if err := h.labelService.Delete(c.Request.Context(), id); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete label"})
+		handleServiceError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusNoContent, nil)
 }
+
+// Merge folds a duplicate label into another one
+// POST /api/labels/merge
+func (h *LabelHandler) Merge(c *gin.Context) {
+	userID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	var req models.MergeLabelsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	label, err := h.labelService.MergeLabels(c.Request.Context(), req.ProjectID, req.SourceLabelID, req.TargetLabelID, userID)
+	if err != nil {
+		logAPIError(c, "Label.Merge", err, map[string]interface{}{
+			"projectID":     req.ProjectID,
+			"sourceLabelID": req.SourceLabelID,
+			"targetLabelID": req.TargetLabelID,
+		})
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toLabelUsageResponse(label))
+}
+
+func toLabelUsageResponse(l *repository.LabelUsage) models.LabelUsageResponse {
+	return models.LabelUsageResponse{
+		LabelResponse: toLabelResponse(l.Label),
+		UsageCount:    l.UsageCount,
+	}
+}
@@ -1,7 +1,7 @@
 package handlers
 
 import (
-	"log"
+	"errors"
 	"net/http"
 
 	"github.com/Marga-Ghale/ora-scrum-backend/internal/api/middleware"
@@ -28,10 +28,10 @@ func (h *MemberHandler) ListDirectMembers(c *gin.Context) {
 	members, err := h.memberService.ListDirectMembers(c.Request.Context(), entityType, entityID)
 	if err != nil {
 		if err == service.ErrInvalidEntityType {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid entity type"})
+			respondErrorCode(c, http.StatusBadRequest, models.CodeInvalidEntityType, "Invalid entity type", nil)
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch members"})
+		handleServiceError(c, err)
 		return
 	}
 
@@ -51,10 +51,10 @@ func (h *MemberHandler) ListEffectiveMembers(c *gin.Context) {
 	members, err := h.memberService.ListEffectiveMembers(c.Request.Context(), entityType, entityID)
 	if err != nil {
 		if err == service.ErrInvalidEntityType {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid entity type"})
+			respondErrorCode(c, http.StatusBadRequest, models.CodeInvalidEntityType, "Invalid entity type", nil)
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch members"})
+		handleServiceError(c, err)
 		return
 	}
 
@@ -66,44 +66,101 @@ func (h *MemberHandler) ListEffectiveMembers(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
+// ListProjectMembers lists the project's direct members or, with
+// ?effective=true, also everyone who has access through the project's
+// folder, space or workspace (isInherited/inheritedFrom tell them apart)
+// GET /api/projects/:id/members
+func (h *MemberHandler) ListProjectMembers(c *gin.Context) {
+	h.listMembers(c, service.EntityTypeProject, c.Param("id"))
+}
+
+// ============================================
+// Members of a Space, Folder or Project
+// ============================================
+
+// ListEntityMembers returns a handler for GET /api/{entities}/:id/members,
+// which works like ListProjectMembers for the given entity type
+func (h *MemberHandler) ListEntityMembers(entityType string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		h.listMembers(c, entityType, c.Param("id"))
+	}
+}
+
+// AddEntityMember returns a handler for POST /api/{entities}/:id/members
+func (h *MemberHandler) AddEntityMember(entityType string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		h.addMember(c, entityType, c.Param("id"))
+	}
+}
+
+// UpdateEntityMemberRole returns a handler for
+// PUT /api/{entities}/:id/members/:userId
+func (h *MemberHandler) UpdateEntityMemberRole(entityType string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		h.updateMemberRole(c, entityType, c.Param("id"), c.Param("userId"))
+	}
+}
+
+// RemoveEntityMember returns a handler for
+// DELETE /api/{entities}/:id/members/:userId
+func (h *MemberHandler) RemoveEntityMember(entityType string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		h.removeMember(c, entityType, c.Param("id"), c.Param("userId"))
+	}
+}
+
+// listMembers lists an entity's members for a user who can see the entity,
+// directly or through one of its ancestors
+func (h *MemberHandler) listMembers(c *gin.Context, entityType, entityID string) {
+	userID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	effective := c.Query("effective") == "true"
+
+	hasAccess, _, err := h.memberService.HasEffectiveAccess(c.Request.Context(), entityType, entityID, userID)
+	if err != nil {
+		logAPIError(c, "Member.ListMembers", err, map[string]interface{}{
+			"entityType": entityType, "entityID": entityID,
+		})
+		handleServiceError(c, err)
+		return
+	}
+	if !hasAccess {
+		handleServiceError(c, service.ErrUnauthorized)
+		return
+	}
+
+	var members []*service.UnifiedMember
+	if effective {
+		members, err = h.memberService.ListEffectiveMembers(c.Request.Context(), entityType, entityID)
+	} else {
+		members, err = h.memberService.ListDirectMembers(c.Request.Context(), entityType, entityID)
+	}
+	if err != nil {
+		logAPIError(c, "Member.ListMembers", err, map[string]interface{}{
+			"entityType": entityType, "entityID": entityID, "effective": effective,
+		})
+		handleServiceError(c, err)
+		return
+	}
+
+	response := make([]models.UnifiedMemberResponse, len(members))
+	for i, m := range members {
+		response[i] = toUnifiedMemberResponse(m)
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// ============================================
+// Members by Entity Type
+// ============================================
+
 // AddMember adds a member by user ID
 func (h *MemberHandler) AddMember(c *gin.Context) {
-    entityType := c.Param("entityType")
-    entityID := c.Param("entityId")
-    inviterID, ok := middleware.RequireUserID(c)
-    if !ok {
-        return
-    }
-
-    var req models.AddMemberRequest
-    if err := c.ShouldBindJSON(&req); err != nil {
-        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-        return
-    }
-
-    err := h.memberService.AddMember(c.Request.Context(), entityType, entityID, req.UserID, req.Role, inviterID)
-    if err != nil {
-        // ✅ ADD THIS LOGGING
-        log.Printf("[MemberHandler][AddMember] entityType=%s entityID=%s userID=%s inviterID=%s error=%v", 
-            entityType, entityID, req.UserID, inviterID, err)
-        
-        if err == service.ErrUserNotFound {
-            c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
-            return
-        }
-        if err == service.ErrConflict {
-            c.JSON(http.StatusConflict, gin.H{"error": "User is already a member"})
-            return
-        }
-        if err == service.ErrUnauthorized {  // ✅ ADD THIS CHECK
-            c.JSON(http.StatusForbidden, gin.H{"error": "You don't have permission to add members"})
-            return
-        }
-        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add member"})
-        return
-    }
-
-    c.JSON(http.StatusCreated, gin.H{"message": "Member added successfully"})
+	h.addMember(c, c.Param("entityType"), c.Param("entityId"))
 }
 
 // InviteMemberByEmail invites by email
@@ -117,30 +174,30 @@ func (h *MemberHandler) InviteMemberByEmail(c *gin.Context) {
 
 	var req models.InviteMemberRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
 	err := h.memberService.InviteMemberByEmail(c.Request.Context(), entityType, entityID, req.Email, req.Role, inviterID)
 	if err != nil {
-		// ✅ ADD LOGGING
-		log.Printf("[MemberHandler][InviteMemberByEmail] entityType=%s entityID=%s email=%s inviterID=%s error=%v", 
-			entityType, entityID, req.Email, inviterID, err)
+		logAPIError(c, "Member.InviteMemberByEmail", err, map[string]interface{}{
+			"entityType": entityType, "entityID": entityID, "email": req.Email,
+		})
 		
 		if err == service.ErrUserNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": "User with this email not found"})
+			respondErrorCode(c, http.StatusNotFound, models.CodeUserNotFound, "User with this email not found", nil)
 			return
 		}
 		if err == service.ErrConflict {
-			c.JSON(http.StatusConflict, gin.H{"error": "User is already a member"})
+			respondError(c, http.StatusConflict, "User is already a member")
 			return
 		}
 		// ✅ ADD THIS CHECK
 		if err == service.ErrUnauthorized {
-			c.JSON(http.StatusForbidden, gin.H{"error": "You don't have permission to add members"})
+			respondError(c, http.StatusForbidden, "You don't have permission to add members")
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to invite member"})
+		handleServiceError(c, err)
 		return
 	}
 
@@ -148,12 +205,50 @@ func (h *MemberHandler) InviteMemberByEmail(c *gin.Context) {
 }
 
 
+// UpdateMemberRole changes a direct member's role
 func (h *MemberHandler) UpdateMemberRole(c *gin.Context) {
-	entityType := c.Param("entityType")
-	entityID := c.Param("entityId")
-	userID := c.Param("userId")
-	
-	// ✅ Get requester ID
+	h.updateMemberRole(c, c.Param("entityType"), c.Param("entityId"), c.Param("userId"))
+}
+
+// RemoveMember removes a direct member
+func (h *MemberHandler) RemoveMember(c *gin.Context) {
+	h.removeMember(c, c.Param("entityType"), c.Param("entityId"), c.Param("userId"))
+}
+
+func (h *MemberHandler) addMember(c *gin.Context, entityType, entityID string) {
+	inviterID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	var req models.AddMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	err := h.memberService.AddMember(c.Request.Context(), entityType, entityID, req.UserID, req.Role, inviterID)
+	if err != nil {
+		logAPIError(c, "Member.AddMember", err, map[string]interface{}{
+			"entityType": entityType, "entityID": entityID, "memberID": req.UserID,
+		})
+
+		switch {
+		case err == service.ErrConflict:
+			respondError(c, http.StatusConflict, "User is already a member")
+		case err == service.ErrUnauthorized:
+			respondError(c, http.StatusForbidden, "You don't have permission to add members")
+		default:
+			// Role hierarchy violations carry the reason
+			handleServiceError(c, err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Member added successfully"})
+}
+
+func (h *MemberHandler) updateMemberRole(c *gin.Context, entityType, entityID, userID string) {
 	requesterID, ok := middleware.RequireUserID(c)
 	if !ok {
 		return
@@ -161,68 +256,61 @@ func (h *MemberHandler) UpdateMemberRole(c *gin.Context) {
 
 	var req models.UpdateMemberRoleRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
-	// ✅ Pass requesterID to service
 	err := h.memberService.UpdateMemberRole(c.Request.Context(), entityType, entityID, userID, req.Role, requesterID)
 	if err != nil {
-		log.Printf("[MemberHandler][UpdateMemberRole] error=%v", err)
-		
-		if err == service.ErrUnauthorized {
-			c.JSON(http.StatusForbidden, gin.H{"error": "You don't have permission to update this member's role"})
-			return
-		}
-		if err == service.ErrUserNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Member not found"})
-			return
+		logAPIError(c, "Member.UpdateMemberRole", err, map[string]interface{}{
+			"entityType": entityType, "entityID": entityID, "memberID": userID,
+		})
+
+		switch {
+		case err == service.ErrUnauthorized:
+			respondError(c, http.StatusForbidden, "You don't have permission to update this member's role")
+		case errors.Is(err, service.ErrUnauthorized):
+			// Role hierarchy violations carry the reason
+			respondError(c, http.StatusForbidden, err.Error())
+		case err == service.ErrUserNotFound:
+			respondErrorCode(c, http.StatusNotFound, models.CodeUserNotFound, "Member not found", nil)
+		case err == service.ErrLastOwner:
+			respondErrorCode(c, http.StatusBadRequest, models.CodeLastOwner, "Cannot demote the last owner", nil)
+		default:
+			handleServiceError(c, err)
 		}
-		if err == service.ErrLastOwner {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Cannot demote the last owner"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update role"})
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "Role updated successfully"})
 }
 
-
-func (h *MemberHandler) RemoveMember(c *gin.Context) {
-	entityType := c.Param("entityType")
-	entityID := c.Param("entityId")
-	userID := c.Param("userId")
-	
-	// ✅ Get requester ID
+func (h *MemberHandler) removeMember(c *gin.Context, entityType, entityID, userID string) {
 	requesterID, ok := middleware.RequireUserID(c)
 	if !ok {
 		return
 	}
 
-	// ✅ Pass requesterID to service
 	err := h.memberService.RemoveMember(c.Request.Context(), entityType, entityID, userID, requesterID)
 	if err != nil {
-		log.Printf("[MemberHandler][RemoveMember] error=%v", err)
-		
-		if err == service.ErrUnauthorized {
-			c.JSON(http.StatusForbidden, gin.H{"error": "You don't have permission to remove this member"})
-			return
-		}
-		if err == service.ErrUserNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Member not found"})
-			return
-		}
-		if err == service.ErrLastOwner {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Cannot remove the last owner"})
-			return
+		logAPIError(c, "Member.RemoveMember", err, map[string]interface{}{
+			"entityType": entityType, "entityID": entityID, "memberID": userID,
+		})
+
+		switch {
+		case err == service.ErrUnauthorized:
+			respondError(c, http.StatusForbidden, "You don't have permission to remove this member")
+		case err == service.ErrUserNotFound:
+			respondErrorCode(c, http.StatusNotFound, models.CodeUserNotFound, "Member not found", nil)
+		case err == service.ErrLastOwner:
+			respondErrorCode(c, http.StatusBadRequest, models.CodeLastOwner, "Cannot remove the last owner", nil)
+		default:
+			handleServiceError(c, err)
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to remove member"})
 		return
 	}
 
-	c.JSON(http.StatusNoContent, nil)
+	c.Status(http.StatusNoContent)
 }
 
 // CheckAccess checks if user has access (direct or inherited)
@@ -242,7 +330,7 @@ func (h *MemberHandler) CheckAccess(c *gin.Context) {
 
 	hasAccess, inheritedFrom, err := h.memberService.HasEffectiveAccess(c.Request.Context(), entityType, entityID, userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check access"})
+		handleServiceError(c, err)
 		return
 	}
 
@@ -270,10 +358,10 @@ func (h *MemberHandler) GetAccessLevel(c *gin.Context) {
 	role, inheritedFrom, err := h.memberService.GetAccessLevel(c.Request.Context(), entityType, entityID, userID)
 	if err != nil {
 		if err == service.ErrUnauthorized {
-			c.JSON(http.StatusForbidden, gin.H{"error": "No access"})
+			respondError(c, http.StatusForbidden, "No access")
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get access level"})
+		handleServiceError(c, err)
 		return
 	}
 
@@ -293,7 +381,7 @@ func (h *MemberHandler) GetUserMemberships(c *gin.Context) {
 
 	memberships, err := h.memberService.GetUserMemberships(c.Request.Context(), userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch memberships"})
+		handleServiceError(c, err)
 		return
 	}
 
@@ -309,7 +397,7 @@ func (h *MemberHandler) GetUserAllAccess(c *gin.Context) {
 
 	accessMap, err := h.memberService.GetUserAllAccess(c.Request.Context(), userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch access"})
+		handleServiceError(c, err)
 		return
 	}
 
@@ -353,7 +441,7 @@ func (h *MemberHandler) GetAccessibleWorkspaces(c *gin.Context) {
 
 	workspaces, err := h.memberService.GetAccessibleWorkspaces(c.Request.Context(), userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch accessible workspaces"})
+		handleServiceError(c, err)
 		return
 	}
 
@@ -369,7 +457,7 @@ func (h *MemberHandler) GetAccessibleSpaces(c *gin.Context) {
 
 	spaces, err := h.memberService.GetAccessibleSpaces(c.Request.Context(), userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch accessible spaces"})
+		handleServiceError(c, err)
 		return
 	}
 
@@ -385,7 +473,7 @@ func (h *MemberHandler) GetAccessibleFolders(c *gin.Context) {
 
 	folders, err := h.memberService.GetAccessibleFolders(c.Request.Context(), userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch accessible folders"})
+		handleServiceError(c, err)
 		return
 	}
 
@@ -401,7 +489,7 @@ func (h *MemberHandler) GetAccessibleProjects(c *gin.Context) {
 
 	projects, err := h.memberService.GetAccessibleProjects(c.Request.Context(), userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch accessible projects"})
+		handleServiceError(c, err)
 		return
 	}
 
@@ -418,7 +506,7 @@ func (h *MemberHandler) GetVisibleSpaces(c *gin.Context) {
 
 	spaces, err := h.memberService.GetVisibleSpaces(c.Request.Context(), userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch visible spaces"})
+		handleServiceError(c, err)
 		return
 	}
 
@@ -442,7 +530,7 @@ func (h *MemberHandler) GetAccessInfo(c *gin.Context) {
 
 	accessInfo, err := h.memberService.GetAccessInfo(c.Request.Context(), entityType, entityID, userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check access"})
+		handleServiceError(c, err)
 		return
 	}
 
@@ -458,7 +546,7 @@ func (h *MemberHandler) GetEligibleUsers(c *gin.Context) {
 	
 	members, err := h.memberService.GetEligibleUsersForEntity(c.Request.Context(), entityType, entityID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		handleServiceError(c, err)
 		return
 	}
 	
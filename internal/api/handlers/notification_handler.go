@@ -2,9 +2,12 @@ package handlers
 
 import (
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/Marga-Ghale/ora-scrum-backend/internal/api/middleware"
 	"github.com/Marga-Ghale/ora-scrum-backend/internal/models"
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/repository"
 	"github.com/Marga-Ghale/ora-scrum-backend/internal/service"
 	"github.com/gin-gonic/gin"
 )
@@ -17,20 +20,45 @@ type NotificationHandler struct {
 	notificationService service.NotificationService
 }
 
+// List returns the user's notifications, newest first and 100 at a time by
+// default. The body stays a bare array; the counts travel in headers.
+// GET /api/notifications?type=&unread=&before=&after=&limit=&offset=&sort=asc|desc
 func (h *NotificationHandler) List(c *gin.Context) {
 	userID, ok := middleware.RequireUserID(c)
 	if !ok {
 		return
 	}
 
-	unreadOnly := c.Query("unread") == "true"
+	filter := repository.NotificationFilter{
+		Type:       c.Query("type"),
+		UnreadOnly: c.Query("unread") == "true",
+		Ascending:  c.Query("sort") == "asc",
+	}
+	if filter.Before, ok = notificationTimeQuery(c, "before"); !ok {
+		return
+	}
+	if filter.After, ok = notificationTimeQuery(c, "after"); !ok {
+		return
+	}
+	filter.Limit, _ = strconv.Atoi(c.Query("limit"))
+	filter.Offset, _ = strconv.Atoi(c.Query("offset"))
+	if filter.Limit <= 0 || filter.Limit > repository.DefaultNotificationLimit {
+		filter.Limit = repository.DefaultNotificationLimit
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
 
-	notifications, err := h.notificationService.List(c.Request.Context(), userID, unreadOnly)
+	notifications, total, unread, err := h.notificationService.List(c.Request.Context(), userID, filter)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch notifications"})
+		logAPIError(c, "Notification.List", err, map[string]interface{}{"filter": filter})
+		handleServiceError(c, err)
 		return
 	}
 
+	setPaginationHeaders(c, total, filter.Limit, filter.Offset)
+	c.Header("X-Unread-Count", strconv.Itoa(unread))
+
 	response := make([]models.NotificationResponse, len(notifications))
 	for i, n := range notifications {
 		response[i] = toNotificationResponse(n)
@@ -39,6 +67,21 @@ func (h *NotificationHandler) List(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
+// notificationTimeQuery parses an optional RFC 3339 query parameter,
+// answering 400 when it is malformed
+func notificationTimeQuery(c *gin.Context, param string) (*time.Time, bool) {
+	raw := c.Query(param)
+	if raw == "" {
+		return nil, true
+	}
+	t, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "Invalid "+param+" timestamp, use RFC 3339")
+		return nil, false
+	}
+	return &t, true
+}
+
 func (h *NotificationHandler) Count(c *gin.Context) {
 	userID, ok := middleware.RequireUserID(c)
 	if !ok {
@@ -47,7 +90,7 @@ func (h *NotificationHandler) Count(c *gin.Context) {
 
 	total, unread, err := h.notificationService.Count(c.Request.Context(), userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count notifications"})
+		handleServiceError(c, err)
 		return
 	}
 
@@ -61,13 +104,31 @@ func (h *NotificationHandler) MarkRead(c *gin.Context) {
 	id := c.Param("id")
 
 	if err := h.notificationService.MarkAsRead(c.Request.Context(), id); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to mark notification as read"})
+		handleServiceError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "Notification marked as read"})
 }
 
+// MarkUnread flags a notification as unread again
+// PUT /api/notifications/:id/unread
+func (h *NotificationHandler) MarkUnread(c *gin.Context) {
+	userID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+	id := c.Param("id")
+
+	if err := h.notificationService.MarkAsUnread(c.Request.Context(), userID, id); err != nil {
+		logAPIError(c, "Notification.MarkUnread", err, map[string]interface{}{"notificationID": id})
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Notification marked as unread"})
+}
+
 func (h *NotificationHandler) MarkAllRead(c *gin.Context) {
 	userID, ok := middleware.RequireUserID(c)
 	if !ok {
@@ -75,7 +136,7 @@ func (h *NotificationHandler) MarkAllRead(c *gin.Context) {
 	}
 
 	if err := h.notificationService.MarkAllAsRead(c.Request.Context(), userID); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to mark notifications as read"})
+		handleServiceError(c, err)
 		return
 	}
 
@@ -86,7 +147,7 @@ func (h *NotificationHandler) Delete(c *gin.Context) {
 	id := c.Param("id")
 
 	if err := h.notificationService.Delete(c.Request.Context(), id); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete notification"})
+		handleServiceError(c, err)
 		return
 	}
 
@@ -100,9 +161,51 @@ func (h *NotificationHandler) DeleteAll(c *gin.Context) {
 	}
 
 	if err := h.notificationService.DeleteAll(c.Request.Context(), userID); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete notifications"})
+		handleServiceError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusNoContent, nil)
 }
+
+// GetPreferences returns the user's setting for every notification type
+// GET /api/users/me/notification-preferences
+func (h *NotificationHandler) GetPreferences(c *gin.Context) {
+	userID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	prefs, err := h.notificationService.GetPreferences(c.Request.Context(), userID)
+	if err != nil {
+		logAPIError(c, "Notification.GetPreferences", err, nil)
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, prefs)
+}
+
+// UpdatePreferences turns in-app and email delivery on or off per type
+// PUT /api/users/me/notification-preferences
+func (h *NotificationHandler) UpdatePreferences(c *gin.Context) {
+	userID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateNotificationPreferencesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	prefs, err := h.notificationService.UpdatePreferences(c.Request.Context(), userID, req.Preferences)
+	if err != nil {
+		logAPIError(c, "Notification.UpdatePreferences", err, map[string]interface{}{"count": len(req.Preferences)})
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, prefs)
+}
@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ============================================
+// Pagination Headers
+// ============================================
+
+// setPaginationHeaders describes a page of a GET list in headers, so the body
+// can stay as it is: X-Total-Count holds the number of matching items and Link
+// (RFC 5988) points at the first, previous, next and last pages. The links
+// keep the request's query and only replace limit and offset.
+func setPaginationHeaders(c *gin.Context, total, limit, offset int) {
+	setTotalCountHeader(c, total)
+	if limit <= 0 {
+		return
+	}
+
+	var links []string
+	link := func(rel string, offset int) {
+		query := c.Request.URL.Query()
+		query.Set("limit", strconv.Itoa(limit))
+		query.Set("offset", strconv.Itoa(offset))
+		links = append(links, fmt.Sprintf(`<%s?%s>; rel="%s"`, c.Request.URL.Path, query.Encode(), rel))
+	}
+
+	lastOffset := 0
+	if total > 0 {
+		lastOffset = (total - 1) / limit * limit
+	}
+
+	link("first", 0)
+	if offset > 0 {
+		prev := offset - limit
+		if prev < 0 {
+			prev = 0
+		}
+		link("prev", prev)
+	}
+	if offset+limit < total {
+		link("next", offset+limit)
+	}
+	link("last", lastOffset)
+
+	c.Header("Link", strings.Join(links, ", "))
+}
+
+// setTotalCountHeader sets X-Total-Count for lists that are not paged through
+// the query string
+func setTotalCountHeader(c *gin.Context, total int) {
+	c.Header("X-Total-Count", strconv.Itoa(total))
+}
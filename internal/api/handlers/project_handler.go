@@ -1,8 +1,9 @@
 package handlers
 
 import (
-	"log"
+	"log/slog"
 	"net/http"
+	"strings"
 
 	"github.com/Marga-Ghale/ora-scrum-backend/internal/api/middleware"
 	"github.com/Marga-Ghale/ora-scrum-backend/internal/models"
@@ -31,8 +32,8 @@ func (h *ProjectHandler) ListBySpace(c *gin.Context) {
 
 	projects, err := h.projectService.ListBySpace(c.Request.Context(), spaceID)
 	if err != nil {
-		log.Printf("[ProjectHandler][ListBySpace] spaceID=%s error=%v", spaceID, err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch projects"})
+		logAPIError(c, "Project.ListBySpace", err, map[string]interface{}{"spaceID": spaceID})
+		handleServiceError(c, err)
 		return
 	}
 
@@ -50,8 +51,8 @@ func (h *ProjectHandler) ListByFolder(c *gin.Context) {
 
 	projects, err := h.projectService.ListByFolder(c.Request.Context(), folderID)
 	if err != nil {
-		log.Printf("[ProjectHandler][ListByFolder] folderID=%s error=%v", folderID, err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch projects"})
+		logAPIError(c, "Project.ListByFolder", err, map[string]interface{}{"folderID": folderID})
+		handleServiceError(c, err)
 		return
 	}
 
@@ -69,14 +70,13 @@ func (h *ProjectHandler) Create(c *gin.Context) {
 
 	userID, ok := middleware.RequireUserID(c)
 	if !ok {
-		log.Printf("[ProjectHandler][Create] missing userID")
 		return
 	}
 
 	var req models.CreateProjectRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		log.Printf("[ProjectHandler][Create] invalid payload error=%v", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		slog.DebugContext(c.Request.Context(), "invalid project payload", "error", err)
+		respondError(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
@@ -93,51 +93,89 @@ func (h *ProjectHandler) Create(c *gin.Context) {
 		req.LeadID,
 	)
 	if err != nil {
-		log.Printf(
-			"[ProjectHandler][Create] spaceID=%s userID=%s error=%v",
-			spaceID,
-			userID,
-			err,
-		)
+		logAPIError(c, "Project.Create", err, map[string]interface{}{"spaceID": spaceID})
 
 		if err == service.ErrConflict {
-			c.JSON(http.StatusConflict, gin.H{"error": "Project key already exists"})
+			respondError(c, http.StatusConflict, "Project key already exists")
 			return
 		}
-		if err == service.ErrNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Space or folder not found"})
-			return
-		}
-
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create project"})
+		handleServiceError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusCreated, toProjectResponse(project))
 }
 
+// KeyAvailable - Check whether a project key is free, suggesting another when it isn't
+// GET /api/projects/key-available?key=
+func (h *ProjectHandler) KeyAvailable(c *gin.Context) {
+	key := c.Query("key")
+	if key == "" {
+		respondError(c, http.StatusBadRequest, "key is required")
+		return
+	}
+
+	available, err := h.projectService.IsKeyAvailable(c.Request.Context(), key)
+	if err != nil {
+		logAPIError(c, "Project.KeyAvailable", err, map[string]interface{}{"key": key})
+		handleServiceError(c, err)
+		return
+	}
+
+	response := models.ProjectKeyAvailabilityResponse{
+		Key:       strings.ToUpper(strings.TrimSpace(key)),
+		Available: available,
+	}
+	if !available {
+		if suggestion, err := h.projectService.SuggestKey(c.Request.Context(), key); err == nil {
+			response.Suggestion = &suggestion
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
 // Get - Get a project by ID
 func (h *ProjectHandler) Get(c *gin.Context) {
 	id := c.Param("id")
 
 	project, err := h.projectService.GetByID(c.Request.Context(), id)
 	if err != nil {
-		log.Printf("[ProjectHandler][Get] projectID=%s error=%v", id, err)
-		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+		logAPIError(c, "Project.Get", err, map[string]interface{}{"projectID": id})
+		respondError(c, http.StatusNotFound, "Project not found")
 		return
 	}
 
 	c.JSON(http.StatusOK, toProjectResponse(project))
 }
 
+// GetStats - Task counts and story points for a project
+func (h *ProjectHandler) GetStats(c *gin.Context) {
+	userID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	id := c.Param("id")
+
+	stats, err := h.projectService.GetStats(c.Request.Context(), id, userID)
+	if err != nil {
+		logAPIError(c, "Project.GetStats", err, map[string]interface{}{"projectID": id})
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
 // Update - Update a project
 func (h *ProjectHandler) Update(c *gin.Context) {
 	id := c.Param("id")
 
 	var req models.UpdateProjectRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		log.Printf("[ProjectHandler][Update] projectID=%s invalid payload error=%v", id, err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		slog.DebugContext(c.Request.Context(), "invalid project payload", "projectID", id, "error", err)
+		respondError(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
@@ -159,18 +197,13 @@ func (h *ProjectHandler) Update(c *gin.Context) {
 		folderIDUpdate,  // ✅ Use converted value
 	)
 	if err != nil {
-		log.Printf("[ProjectHandler][Update] projectID=%s error=%v", id, err)
+		logAPIError(c, "Project.Update", err, map[string]interface{}{"projectID": id})
 
 		if err == service.ErrConflict {
-			c.JSON(http.StatusConflict, gin.H{"error": "Project key already exists"})
-			return
-		}
-		if err == service.ErrNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+			respondError(c, http.StatusConflict, "Project key already exists")
 			return
 		}
-
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update project"})
+		handleServiceError(c, err)
 		return
 	}
 
@@ -181,14 +214,112 @@ func (h *ProjectHandler) Delete(c *gin.Context) {
 	id := c.Param("id")
 
 	if err := h.projectService.Delete(c.Request.Context(), id); err != nil {
-		log.Printf("[ProjectHandler][Delete] projectID=%s error=%v", id, err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete project"})
+		logAPIError(c, "Project.Delete", err, map[string]interface{}{"projectID": id})
+		handleServiceError(c, err)
 		return
 	}
 
 	c.Status(http.StatusNoContent)
 }
 
+// SaveAsTemplate - Save a project's structure as a workspace template
+// POST /api/projects/:id/templates
+func (h *ProjectHandler) SaveAsTemplate(c *gin.Context) {
+	userID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	id := c.Param("id")
+
+	var req models.SaveProjectTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	template, err := h.projectService.SaveAsTemplate(c.Request.Context(), id, userID, req.Name, req.Description)
+	if err != nil {
+		logAPIError(c, "Project.SaveAsTemplate", err, map[string]interface{}{"projectID": id})
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, toProjectTemplateResponse(template))
+}
+
+// ListTemplates - List project templates saved in a workspace
+// GET /api/workspaces/:id/templates
+func (h *ProjectHandler) ListTemplates(c *gin.Context) {
+	userID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	workspaceID := c.Param("id")
+
+	templates, err := h.projectService.ListTemplates(c.Request.Context(), workspaceID, userID)
+	if err != nil {
+		logAPIError(c, "Project.ListTemplates", err, map[string]interface{}{"workspaceID": workspaceID})
+		handleServiceError(c, err)
+		return
+	}
+
+	response := make([]models.ProjectTemplateResponse, len(templates))
+	for i, t := range templates {
+		response[i] = toProjectTemplateResponse(t)
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// DeleteTemplate - Delete a project template
+// DELETE /api/templates/:id
+func (h *ProjectHandler) DeleteTemplate(c *gin.Context) {
+	userID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	id := c.Param("id")
+
+	if err := h.projectService.DeleteTemplate(c.Request.Context(), id, userID); err != nil {
+		logAPIError(c, "Project.DeleteTemplate", err, map[string]interface{}{"templateID": id})
+		handleServiceError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// CreateFromTemplate - Create a project in a space from a template
+// POST /api/spaces/:id/projects/from-template
+func (h *ProjectHandler) CreateFromTemplate(c *gin.Context) {
+	userID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	spaceID := c.Param("id")
+
+	var req models.CreateProjectFromTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	project, err := h.projectService.CreateFromTemplate(c.Request.Context(), req.TemplateID, spaceID, userID, req.Name, req.Key)
+	if err != nil {
+		logAPIError(c, "Project.CreateFromTemplate", err, map[string]interface{}{
+			"spaceID":    spaceID,
+			"templateID": req.TemplateID,
+		})
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, toProjectResponse(project))
+}
 
 // ============================================
 // Helper Functions
@@ -221,4 +352,20 @@ func toProjectResponse(p *repository.Project) models.ProjectResponse {
 		CreatedAt:   p.CreatedAt,
 		UpdatedAt:   p.UpdatedAt,
 	}
-}
\ No newline at end of file
+}
+
+func toProjectTemplateResponse(t *repository.ProjectTemplate) models.ProjectTemplateResponse {
+	return models.ProjectTemplateResponse{
+		ID:              t.ID,
+		WorkspaceID:     t.WorkspaceID,
+		Name:            t.Name,
+		Description:     t.Description,
+		SourceProjectID: t.SourceProjectID,
+		StatusCount:     len(t.Definition.Statuses),
+		LabelCount:      len(t.Definition.Labels),
+		TaskCount:       len(t.Definition.Tasks),
+		Definition:      t.Definition,
+		CreatedBy:       t.CreatedBy,
+		CreatedAt:       t.CreatedAt,
+	}
+}
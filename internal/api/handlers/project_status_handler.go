@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/api/middleware"
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/models"
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type ProjectStatusHandler struct {
+	statusService service.ProjectStatusService
+}
+
+func NewProjectStatusHandler(statusService service.ProjectStatusService) *ProjectStatusHandler {
+	return &ProjectStatusHandler{statusService: statusService}
+}
+
+// List returns the project's workflow statuses in board order
+// GET /api/projects/:id/statuses
+func (h *ProjectStatusHandler) List(c *gin.Context) {
+	userID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	statuses, err := h.statusService.ListStatuses(c.Request.Context(), c.Param("id"), userID)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, statuses)
+}
+
+// Create adds a status to the project's workflow
+// POST /api/projects/:id/statuses
+func (h *ProjectStatusHandler) Create(c *gin.Context) {
+	userID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	projectID := c.Param("id")
+
+	var req models.CreateProjectStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	status, err := h.statusService.CreateStatus(c.Request.Context(), projectID, userID, &req)
+	if err != nil {
+		logAPIError(c, "ProjectStatus.Create", err, map[string]interface{}{
+			"projectID": projectID,
+			"key":       req.Key,
+		})
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, status)
+}
+
+// Update changes a status' name, color, position or allowed transitions
+// PUT /api/projects/:id/statuses/:statusId
+func (h *ProjectStatusHandler) Update(c *gin.Context) {
+	userID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	projectID := c.Param("id")
+	statusID := c.Param("statusId")
+
+	var req models.UpdateProjectStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	status, err := h.statusService.UpdateStatus(c.Request.Context(), projectID, statusID, userID, &req)
+	if err != nil {
+		logAPIError(c, "ProjectStatus.Update", err, map[string]interface{}{
+			"projectID": projectID,
+			"statusID":  statusID,
+		})
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
+
+// Delete removes a status that no task uses anymore
+// DELETE /api/projects/:id/statuses/:statusId
+func (h *ProjectStatusHandler) Delete(c *gin.Context) {
+	userID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.statusService.DeleteStatus(c.Request.Context(), c.Param("id"), c.Param("statusId"), userID); err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
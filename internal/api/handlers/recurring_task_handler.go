@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/api/middleware"
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/models"
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type RecurringTaskHandler struct {
+	recurringTaskService service.RecurringTaskService
+}
+
+func NewRecurringTaskHandler(recurringTaskService service.RecurringTaskService) *RecurringTaskHandler {
+	return &RecurringTaskHandler{recurringTaskService: recurringTaskService}
+}
+
+// Create stores a new recurring task template for a project
+// POST /api/projects/:id/recurring-tasks
+func (h *RecurringTaskHandler) Create(c *gin.Context) {
+	userID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	projectID := c.Param("id")
+
+	var req models.CreateRecurringTaskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	rt, err := h.recurringTaskService.CreateRecurringTask(c.Request.Context(), projectID, userID, &req)
+	if err != nil {
+		logAPIError(c, "RecurringTask.Create", err, map[string]interface{}{
+			"projectID": projectID,
+			"cadence":   req.Cadence,
+		})
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, rt)
+}
+
+// ListByProject returns all recurring task templates of a project
+// GET /api/projects/:id/recurring-tasks
+func (h *RecurringTaskHandler) ListByProject(c *gin.Context) {
+	userID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	projectID := c.Param("id")
+
+	templates, err := h.recurringTaskService.ListRecurringTasks(c.Request.Context(), projectID, userID)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, templates)
+}
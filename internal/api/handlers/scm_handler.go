@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/api/middleware"
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/models"
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// ============================================
+// SCM Integration Handler
+// ============================================
+
+type SCMHandler struct {
+	scmService service.SCMService
+}
+
+// GenerateToken issues a new integration token for the workspace. Any
+// previous token stops working.
+// POST /api/workspaces/:id/integration-token
+func (h *SCMHandler) GenerateToken(c *gin.Context) {
+	userID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	token, err := h.scmService.GenerateIntegrationToken(c.Request.Context(), c.Param("id"), userID)
+	if err != nil {
+		logAPIError(c, "SCM.GenerateToken", err, map[string]interface{}{"workspaceID": c.Param("id")})
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"token": token})
+}
+
+// RevokeToken disables the workspace's integration token
+// DELETE /api/workspaces/:id/integration-token
+func (h *SCMHandler) RevokeToken(c *gin.Context) {
+	userID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.scmService.RevokeIntegrationToken(c.Request.Context(), c.Param("id"), userID); err != nil {
+		logAPIError(c, "SCM.RevokeToken", err, map[string]interface{}{"workspaceID": c.Param("id")})
+		handleServiceError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetSettings returns the project's commit reference settings
+// GET /api/projects/:id/scm-settings
+func (h *SCMHandler) GetSettings(c *gin.Context) {
+	userID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	settings, err := h.scmService.GetProjectSettings(c.Request.Context(), c.Param("id"), userID)
+	if err != nil {
+		logAPIError(c, "SCM.GetSettings", err, map[string]interface{}{"projectID": c.Param("id")})
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
+
+// UpdateSettings changes whether magic words move tasks, and to which status
+// PUT /api/projects/:id/scm-settings
+func (h *SCMHandler) UpdateSettings(c *gin.Context) {
+	userID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateSCMSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	settings, err := h.scmService.UpdateProjectSettings(c.Request.Context(), c.Param("id"), userID, &req)
+	if err != nil {
+		logAPIError(c, "SCM.UpdateSettings", err, map[string]interface{}{"projectID": c.Param("id")})
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
+
+// Event processes commits and branches pushed by a source control system. It
+// is not behind the auth middleware: the workspace integration token is sent
+// as "Authorization: Bearer <token>" or in the X-Ora-Token header.
+// POST /api/integrations/scm/event
+func (h *SCMHandler) Event(c *gin.Context) {
+	var req models.SCMEventRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	result, err := h.scmService.HandleEvent(c.Request.Context(), integrationToken(c), &req)
+	if err != nil {
+		if err != service.ErrInvalidToken {
+			logAPIError(c, "SCM.Event", err, map[string]interface{}{"repository": req.Repository})
+		}
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func integrationToken(c *gin.Context) string {
+	if token := c.GetHeader("X-Ora-Token"); token != "" {
+		return token
+	}
+	if token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer "); ok {
+		return strings.TrimSpace(token)
+	}
+	return ""
+}
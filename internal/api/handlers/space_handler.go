@@ -37,7 +37,7 @@ func (h *SpaceHandler) Create(c *gin.Context) {
 
 	var req models.CreateSpaceRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
@@ -51,14 +51,7 @@ func (h *SpaceHandler) Create(c *gin.Context) {
 		req.Color,
 	)
 	if err != nil {
-		switch err {
-		case service.ErrUnauthorized:
-			c.JSON(http.StatusForbidden, gin.H{"error": "No access to workspace"})
-		case service.ErrNotFound:
-			c.JSON(http.StatusNotFound, gin.H{"error": "Workspace not found"})
-		default:
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create space"})
-		}
+		handleServiceError(c, err)
 		return
 	}
 
@@ -74,7 +67,7 @@ func (h *SpaceHandler) ListByWorkspace(c *gin.Context) {
 
 	spaces, err := h.spaceService.ListByWorkspace(c.Request.Context(), workspaceID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch spaces"})
+		handleServiceError(c, err)
 		return
 	}
 
@@ -95,7 +88,7 @@ func (h *SpaceHandler) Get(c *gin.Context) {
 
 	space, err := h.spaceService.GetByID(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Space not found"})
+		respondError(c, http.StatusNotFound, "Space not found")
 		return
 	}
 
@@ -111,7 +104,7 @@ func (h *SpaceHandler) Update(c *gin.Context) {
 
 	var req models.UpdateSpaceRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
@@ -127,11 +120,7 @@ func (h *SpaceHandler) Update(c *gin.Context) {
 		req.AllowedTeams,
 	)
 	if err != nil {
-		if err == service.ErrNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Space not found"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update space"})
+		handleServiceError(c, err)
 		return
 	}
 
@@ -146,7 +135,7 @@ func (h *SpaceHandler) Delete(c *gin.Context) {
 	id := c.Param("id")
 
 	if err := h.spaceService.Delete(c.Request.Context(), id); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete space"})
+		handleServiceError(c, err)
 		return
 	}
 
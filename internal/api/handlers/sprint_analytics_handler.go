@@ -31,7 +31,7 @@ func (h *SprintAnalyticsHandler) GetSprintReport(c *gin.Context) {
 	sprintID := c.Param("id")
 	report, err := h.analyticsService.GetSprintReport(c.Request.Context(), sprintID, userID)
 	if err != nil {
-		handleAnalyticsError(c, err)
+		handleServiceError(c, err)
 		return
 	}
 
@@ -48,7 +48,7 @@ func (h *SprintAnalyticsHandler) GenerateSprintReport(c *gin.Context) {
 	sprintID := c.Param("id")
 	report, err := h.analyticsService.GenerateSprintReport(c.Request.Context(), sprintID, userID)
 	if err != nil {
-		handleAnalyticsError(c, err)
+		handleServiceError(c, err)
 		return
 	}
 
@@ -72,7 +72,7 @@ func (h *SprintAnalyticsHandler) GetVelocityHistory(c *gin.Context) {
 
 	history, err := h.analyticsService.GetVelocityHistory(c.Request.Context(), projectID, userID, limit)
 	if err != nil {
-		handleAnalyticsError(c, err)
+		handleServiceError(c, err)
 		return
 	}
 
@@ -95,7 +95,7 @@ func (h *SprintAnalyticsHandler) GetVelocityTrend(c *gin.Context) {
 
 	trend, err := h.analyticsService.GetVelocityTrend(c.Request.Context(), projectID, userID, sprintCount)
 	if err != nil {
-		handleAnalyticsError(c, err)
+		handleServiceError(c, err)
 		return
 	}
 
@@ -116,7 +116,7 @@ func (h *SprintAnalyticsHandler) GetSprintCycleTime(c *gin.Context) {
 	sprintID := c.Param("id")
 	stats, err := h.analyticsService.GetCycleTimeStats(c.Request.Context(), sprintID, userID)
 	if err != nil {
-		handleAnalyticsError(c, err)
+		handleServiceError(c, err)
 		return
 	}
 
@@ -139,7 +139,7 @@ func (h *SprintAnalyticsHandler) GetProjectCycleTime(c *gin.Context) {
 
 	avg, err := h.analyticsService.GetProjectCycleTimeAvg(c.Request.Context(), projectID, userID, days)
 	if err != nil {
-		handleAnalyticsError(c, err)
+		handleServiceError(c, err)
 		return
 	}
 
@@ -156,7 +156,7 @@ func (h *SprintAnalyticsHandler) GetTaskStatusHistory(c *gin.Context) {
 	taskID := c.Param("id")
 	history, err := h.analyticsService.GetTaskStatusHistory(c.Request.Context(), taskID, userID)
 	if err != nil {
-		handleAnalyticsError(c, err)
+		handleServiceError(c, err)
 		return
 	}
 
@@ -187,7 +187,7 @@ sprintID := c.Query("sprintId")
 
 	data, err := h.analyticsService.GetGanttData(c.Request.Context(), projectID, userID, sprintIDPtr)
 	if err != nil {
-		handleAnalyticsError(c, err)
+		handleServiceError(c, err)
 		return
 	}
 
@@ -208,7 +208,7 @@ func (h *SprintAnalyticsHandler) GetSprintAnalyticsDashboard(c *gin.Context) {
 	sprintID := c.Param("id")
 	dashboard, err := h.analyticsService.GetSprintAnalyticsDashboard(c.Request.Context(), sprintID, userID)
 	if err != nil {
-		handleAnalyticsError(c, err)
+		handleServiceError(c, err)
 		return
 	}
 
@@ -225,26 +225,9 @@ func (h *SprintAnalyticsHandler) GetProjectAnalyticsDashboard(c *gin.Context) {
 	projectID := c.Param("id")
 	dashboard, err := h.analyticsService.GetProjectAnalyticsDashboard(c.Request.Context(), projectID, userID)
 	if err != nil {
-		handleAnalyticsError(c, err)
+		handleServiceError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, dashboard)
 }
-
-// ============================================
-// ERROR HANDLER
-// ============================================
-
-func handleAnalyticsError(c *gin.Context, err error) {
-	switch err {
-	case service.ErrUnauthorized:
-		c.JSON(http.StatusForbidden, gin.H{"error": "Unauthorized"})
-	case service.ErrNotFound:
-		c.JSON(http.StatusNotFound, gin.H{"error": "Resource not found"})
-	case service.ErrInvalidInput:
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
-	default:
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
-	}
-}
\ No newline at end of file
@@ -1,10 +1,13 @@
 package handlers
 
 import (
-	"log"
+	"encoding/csv"
+	"log/slog"
 	"net/http"
+	"strconv"
 
 	"github.com/Marga-Ghale/ora-scrum-backend/internal/api/middleware"
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/models"
 	"github.com/Marga-Ghale/ora-scrum-backend/internal/repository"
 	"github.com/Marga-Ghale/ora-scrum-backend/internal/service"
 	"github.com/gin-gonic/gin"
@@ -34,8 +37,8 @@ func (h *SprintHandler) Create(c *gin.Context) {
 
 	var sprint repository.Sprint
 	if err := c.ShouldBindJSON(&sprint); err != nil {
-		log.Printf("❌ [Sprint Create] JSON binding failed - Error: %v", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		slog.DebugContext(c.Request.Context(), "invalid sprint payload", "error", err)
+		respondError(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
@@ -45,16 +48,13 @@ func (h *SprintHandler) Create(c *gin.Context) {
 		sprint.Status = "planning"
 	}
 
-	log.Printf("📝 [Sprint Create] Creating sprint - Name: %s, ProjectID: %s, UserID: %s", 
-		sprint.Name, sprint.ProjectID, userID)
-
 	if err := h.sprintService.Create(c.Request.Context(), &sprint, userID); err != nil {
-		log.Printf("❌ [Sprint Create] Failed - Error: %v", err)
+		logAPIError(c, "Sprint.Create", err, map[string]interface{}{"projectID": sprint.ProjectID})
 		handleServiceError(c, err)
 		return
 	}
 
-	log.Printf("✅ [Sprint Create] Success - SprintID: %s", sprint.ID)
+	slog.InfoContext(c.Request.Context(), "sprint created", "sprintID", sprint.ID, "projectID", sprint.ProjectID)
 	c.JSON(http.StatusCreated, sprint)
 }
 
@@ -65,11 +65,10 @@ func (h *SprintHandler) Get(c *gin.Context) {
 	}
 
 	sprintID := c.Param("id")
-	log.Printf("📝 [Sprint Get] Fetching sprint - SprintID: %s, UserID: %s", sprintID, userID)
 
 	sprint, err := h.sprintService.Get(c.Request.Context(), sprintID, userID)
 	if err != nil {
-		log.Printf("❌ [Sprint Get] Failed - SprintID: %s, Error: %v", sprintID, err)
+		logAPIError(c, "Sprint.Get", err, map[string]interface{}{"sprintID": sprintID})
 		handleServiceError(c, err)
 		return
 	}
@@ -84,16 +83,14 @@ func (h *SprintHandler) ListByProject(c *gin.Context) {
 	}
 
 	projectID := c.Param("id")
-	log.Printf("📝 [Sprint List] Fetching sprints - ProjectID: %s, UserID: %s", projectID, userID)
 
 	sprints, err := h.sprintService.ListByProject(c.Request.Context(), projectID, userID)
 	if err != nil {
-		log.Printf("❌ [Sprint List] Failed - ProjectID: %s, Error: %v", projectID, err)
+		logAPIError(c, "Sprint.ListByProject", err, map[string]interface{}{"projectID": projectID})
 		handleServiceError(c, err)
 		return
 	}
 
-	log.Printf("✅ [Sprint List] Found %d sprints", len(sprints))
 	c.JSON(http.StatusOK, sprints)
 }
 
@@ -104,11 +101,10 @@ func (h *SprintHandler) GetActive(c *gin.Context) {
 	}
 
 	projectID := c.Param("id")
-	log.Printf("📝 [Sprint GetActive] Fetching active sprint - ProjectID: %s, UserID: %s", projectID, userID)
 
 	sprint, err := h.sprintService.GetActiveSprint(c.Request.Context(), projectID, userID)
 	if err != nil {
-		log.Printf("❌ [Sprint GetActive] Failed - ProjectID: %s, Error: %v", projectID, err)
+		logAPIError(c, "Sprint.GetActive", err, map[string]interface{}{"projectID": projectID})
 		handleServiceError(c, err)
 		return
 	}
@@ -124,16 +120,15 @@ func (h *SprintHandler) Update(c *gin.Context) {
 
 	var sprint repository.Sprint
 	if err := c.ShouldBindJSON(&sprint); err != nil {
-		log.Printf("❌ [Sprint Update] JSON binding failed - Error: %v", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		slog.DebugContext(c.Request.Context(), "invalid sprint payload", "error", err)
+		respondError(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
 	sprint.ID = c.Param("id")
-	log.Printf("📝 [Sprint Update] Updating sprint - SprintID: %s, UserID: %s", sprint.ID, userID)
 
 	if err := h.sprintService.Update(c.Request.Context(), &sprint, userID); err != nil {
-		log.Printf("❌ [Sprint Update] Failed - SprintID: %s, Error: %v", sprint.ID, err)
+		logAPIError(c, "Sprint.Update", err, map[string]interface{}{"sprintID": sprint.ID})
 		handleServiceError(c, err)
 		return
 	}
@@ -148,10 +143,9 @@ func (h *SprintHandler) Delete(c *gin.Context) {
 	}
 
 	sprintID := c.Param("id")
-	log.Printf("📝 [Sprint Delete] Deleting sprint - SprintID: %s, UserID: %s", sprintID, userID)
 
 	if err := h.sprintService.Delete(c.Request.Context(), sprintID, userID); err != nil {
-		log.Printf("❌ [Sprint Delete] Failed - SprintID: %s, Error: %v", sprintID, err)
+		logAPIError(c, "Sprint.Delete", err, map[string]interface{}{"sprintID": sprintID})
 		handleServiceError(c, err)
 		return
 	}
@@ -166,17 +160,16 @@ func (h *SprintHandler) Start(c *gin.Context) {
 	}
 
 	sprintID := c.Param("id")
-	log.Printf("📝 [Sprint Start] Starting sprint - SprintID: %s, UserID: %s", sprintID, userID)
 
 	response, err := h.sprintService.StartSprint(c.Request.Context(), sprintID, userID)
 	if err != nil {
-		log.Printf("❌ [Sprint Start] Failed - SprintID: %s, Error: %v", sprintID, err)
+		logAPIError(c, "Sprint.Start", err, map[string]interface{}{"sprintID": sprintID})
 		handleServiceError(c, err)
 		return
 	}
 
-	log.Printf("✅ [Sprint Start] Success - SprintID: %s, Committed: %d tasks, %d points", 
-		sprintID, response.CommittedTasks, response.CommittedPoints)
+	slog.InfoContext(c.Request.Context(), "sprint started", "sprintID", sprintID,
+		"committedTasks", response.CommittedTasks, "committedPoints", response.CommittedPoints)
 
 	c.JSON(http.StatusOK, response)
 }
@@ -191,29 +184,28 @@ func (h *SprintHandler) Complete(c *gin.Context) {
 	sprintID := c.Param("id")
 	
 	// Parse options from request body (optional)
+	// Body is optional; an empty MoveIncompleteTo defaults to the backlog
 	var options service.SprintCompleteOptions
 	if err := c.ShouldBindJSON(&options); err != nil {
-		// Default to backlog if no options provided
 		options.MoveIncompleteTo = "backlog"
 	}
 
-	log.Printf("📝 [Sprint Complete] Completing sprint - SprintID: %s, MoveIncompleteTo: %s", sprintID, options.MoveIncompleteTo)
 
 	response, err := h.sprintService.CompleteSprintWithOptions(c.Request.Context(), sprintID, userID, &options)
 	if err != nil {
-		log.Printf("❌ [Sprint Complete] Failed - SprintID: %s, Error: %v", sprintID, err)
+		logAPIError(c, "Sprint.Complete", err, map[string]interface{}{"sprintID": sprintID})
 		handleServiceError(c, err)
 		return
 	}
 
 	// Record velocity
 	if err := h.analyticsService.RecordSprintVelocity(c.Request.Context(), sprintID); err != nil {
-		log.Printf("⚠️ [Sprint Complete] Failed to record velocity - SprintID: %s, Error: %v", sprintID, err)
+		slog.WarnContext(c.Request.Context(), "failed to record sprint velocity", "sprintID", sprintID, "error", err)
 	}
 
-	log.Printf("✅ [Sprint Complete] Success - Completed: %d/%d tasks, Moved %d incomplete tasks to %s",
-		response.CompletedTasks, response.CompletedTasks+response.IncompleteTasks,
-		response.IncompleteTasks, response.TasksMovedTo)
+	slog.InfoContext(c.Request.Context(), "sprint completed", "sprintID", sprintID,
+		"completedTasks", response.CompletedTasks, "incompleteTasks", response.IncompleteTasks,
+		"movedTo", response.TasksMovedTo)
 
 	c.JSON(http.StatusOK, response)
 }
@@ -250,29 +242,158 @@ func (h *SprintHandler) CompleteWithOptions(c *gin.Context) {
 		req.MoveIncompleteTo = "backlog" // Default
 	}
 
-	log.Printf("📝 [Sprint Complete] SprintID: %s, MoveIncompleteTo: %s", sprintID, req.MoveIncompleteTo)
 
 	response, err := h.sprintService.CompleteSprintWithOptions(c.Request.Context(), sprintID, userID, &service.SprintCompleteOptions{
 		MoveIncompleteTo: req.MoveIncompleteTo,
 	})
 	if err != nil {
-		log.Printf("❌ [Sprint Complete] Failed - SprintID: %s, Error: %v", sprintID, err)
+		logAPIError(c, "Sprint.Complete", err, map[string]interface{}{"sprintID": sprintID})
 		handleServiceError(c, err)
 		return
 	}
 
 	// Record velocity
 	if err := h.analyticsService.RecordSprintVelocity(c.Request.Context(), sprintID); err != nil {
-		log.Printf("⚠️ Failed to record velocity: %v", err)
+		slog.WarnContext(c.Request.Context(), "failed to record sprint velocity", "sprintID", sprintID, "error", err)
 	}
 
-	log.Printf("✅ [Sprint Complete] Success - Completed: %d tasks (%d pts), Incomplete: %d tasks (%d pts), Moved to: %s",
-		response.CompletedTasks,
-		response.CompletedPoints,
-		response.IncompleteTasks,
-		response.IncompletePoints,
-		response.TasksMovedTo,
-	)
+	slog.InfoContext(c.Request.Context(), "sprint completed", "sprintID", sprintID,
+		"completedTasks", response.CompletedTasks, "completedPoints", response.CompletedPoints,
+		"incompleteTasks", response.IncompleteTasks, "incompletePoints", response.IncompletePoints,
+		"movedTo", response.TasksMovedTo)
 
 	c.JSON(http.StatusOK, response)
-}
\ No newline at end of file
+}
+
+// GetReport returns the sprint report: committed and completed points, scope
+// added or removed mid-sprint and carried-over work. format=csv downloads the
+// report's tasks as CSV, one row per task and section.
+// GET /api/sprints/:id/scope-report?format=json|csv
+func (h *SprintHandler) GetReport(c *gin.Context) {
+	userID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	sprintID := c.Param("id")
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "csv" {
+		respondError(c, http.StatusBadRequest, "format must be json or csv")
+		return
+	}
+
+	report, err := h.sprintService.GetSprintReport(c.Request.Context(), sprintID, userID)
+	if err != nil {
+		logAPIError(c, "Sprint.GetReport", err, map[string]interface{}{"sprintID": sprintID})
+		handleServiceError(c, err)
+		return
+	}
+
+	if format == "json" {
+		c.JSON(http.StatusOK, report)
+		return
+	}
+
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", `attachment; filename="sprint-report-`+sprintID+`.csv"`)
+	c.Status(http.StatusOK)
+
+	w := csv.NewWriter(c.Writer)
+	w.Write([]string{"Section", "Key", "Title", "Status", "Story Points", "Changed At"})
+	sections := []struct {
+		name  string
+		tasks []service.SprintReportTask
+	}{
+		{"Completed", report.Completed},
+		{"Incomplete", report.Incomplete},
+		{"Added", report.Added},
+		{"Removed", report.Removed},
+		{"Carried Over", report.CarriedOver},
+	}
+	for _, section := range sections {
+		for _, task := range section.tasks {
+			changedAt := ""
+			if task.ChangedAt != nil {
+				changedAt = task.ChangedAt.UTC().Format("2006-01-02 15:04")
+			}
+			w.Write([]string{
+				section.name, task.Key, task.Title, task.Status, strconv.Itoa(task.StoryPoints), changedAt,
+			})
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		slog.ErrorContext(c.Request.Context(), "sprint report CSV write failed", "sprintID", sprintID, "error", err)
+	}
+}
+
+// GetCapacity compares the estimated hours of the sprint's tasks with each
+// member's available hours. ?multiAssignee=split (default) divides the
+// estimate of a task with several assignees between them; full charges it
+// to each of them.
+// GET /api/sprints/:id/capacity
+func (h *SprintHandler) GetCapacity(c *gin.Context) {
+	userID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	sprintID := c.Param("id")
+	plan, err := h.sprintService.GetCapacity(c.Request.Context(), sprintID, userID, c.Query("multiAssignee"))
+	if err != nil {
+		logAPIError(c, "Sprint.GetCapacity", err, map[string]interface{}{"sprintID": sprintID})
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, plan)
+}
+
+// SetMemberCapacity sets how many hours a member can work during the sprint
+// PUT /api/sprints/:id/capacity/:userId
+func (h *SprintHandler) SetMemberCapacity(c *gin.Context) {
+	userID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	var req models.SetSprintCapacityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	sprintID := c.Param("id")
+	memberID := c.Param("userId")
+	capacity, err := h.sprintService.SetMemberCapacity(c.Request.Context(), sprintID, memberID, *req.HoursAvailable, userID)
+	if err != nil {
+		logAPIError(c, "Sprint.SetMemberCapacity", err, map[string]interface{}{
+			"sprintID": sprintID, "memberID": memberID,
+		})
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, capacity)
+}
+
+// RemoveMemberCapacity clears a member's capacity for the sprint
+// DELETE /api/sprints/:id/capacity/:userId
+func (h *SprintHandler) RemoveMemberCapacity(c *gin.Context) {
+	userID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	sprintID := c.Param("id")
+	memberID := c.Param("userId")
+	if err := h.sprintService.RemoveMemberCapacity(c.Request.Context(), sprintID, memberID, userID); err != nil {
+		logAPIError(c, "Sprint.RemoveMemberCapacity", err, map[string]interface{}{
+			"sprintID": sprintID, "memberID": memberID,
+		})
+		handleServiceError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -1,10 +1,14 @@
 package handlers
 
 import (
+	"encoding/csv"
+	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/Marga-Ghale/ora-scrum-backend/internal/api/middleware"
 	"github.com/Marga-Ghale/ora-scrum-backend/internal/models"
@@ -23,16 +27,25 @@ func NewTaskHandler(taskService service.TaskService) *TaskHandler {
 	}
 }
 
-func logAPIError(c *gin.Context, action string, err error, fields map[string]interface{}) {
-	log.Printf(
-		"[API_ERROR] action=%s method=%s path=%s userID=%v fields=%v err=%v",
-		action,
-		c.Request.Method,
-		c.FullPath(),
-		c.GetString("userID"),
-		fields,
-		err,
-	)
+// requireTaskVersion resolves the task version the client last read, for
+// optimistic concurrency. It comes from the "version" body field or, failing
+// that, an If-Match header holding the number (quoted like an ETag or bare).
+// Responds 400 and returns false when neither is usable.
+func requireTaskVersion(c *gin.Context, bodyVersion *int) (*int, bool) {
+	if bodyVersion != nil {
+		return bodyVersion, true
+	}
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" {
+		respondError(c, http.StatusBadRequest, "version is required: send the task's version field or an If-Match header")
+		return nil, false
+	}
+	version, err := strconv.Atoi(strings.Trim(strings.TrimPrefix(header, "W/"), `"`))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "If-Match must hold the task version")
+		return nil, false
+	}
+	return &version, true
 }
 
 
@@ -50,7 +63,7 @@ func (h *TaskHandler) Create(c *gin.Context) {
 
 	var req models.CreateTaskRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
@@ -104,6 +117,10 @@ func (h *TaskHandler) Get(c *gin.Context) {
 		handleServiceError(c, err)
 		return
 	}
+	if task == nil {
+		handleServiceError(c, service.ErrTaskNotFound)
+		return
+	}
 
 	// ✅ Fetch subtasks for response
 	subtasks, _ := h.taskService.ListSubtasks(c.Request.Context(), task.ID, userID)
@@ -111,6 +128,33 @@ func (h *TaskHandler) Get(c *gin.Context) {
 	c.JSON(http.StatusOK, toTaskResponseWithSubtasks(task, subtasks))
 }
 
+// GetByKey resolves a task by its human key for deep links
+// GET /api/tasks/key/:key
+func (h *TaskHandler) GetByKey(c *gin.Context) {
+	userID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	key := c.Param("key")
+	task, err := h.taskService.GetByKey(c.Request.Context(), key, userID)
+	if err != nil {
+		logAPIError(c, "Task.GetByKey", err, map[string]interface{}{
+			"key": key,
+		})
+		handleServiceError(c, err)
+		return
+	}
+	if task == nil {
+		handleServiceError(c, service.ErrTaskNotFound)
+		return
+	}
+
+	subtasks, _ := h.taskService.ListSubtasks(c.Request.Context(), task.ID, userID)
+
+	c.JSON(http.StatusOK, toTaskResponseWithSubtasks(task, subtasks))
+}
+
 func (h *TaskHandler) Update(c *gin.Context) {
 	userID, ok := middleware.RequireUserID(c)
 	if !ok {
@@ -120,7 +164,7 @@ func (h *TaskHandler) Update(c *gin.Context) {
 	taskID := c.Param("id")
 	var req models.UpdateTaskRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
@@ -139,6 +183,11 @@ func (h *TaskHandler) Update(c *gin.Context) {
 		StartDate:      req.StartDate,
 		DueDate:        req.DueDate,
 	}
+	version, ok := requireTaskVersion(c, req.Version)
+	if !ok {
+		return
+	}
+	updateReq.Version = version
 
 	task, err := h.taskService.Update(c.Request.Context(), taskID, userID, updateReq)
 	if err != nil {
@@ -186,8 +235,34 @@ func (h *TaskHandler) ListByProject(c *gin.Context) {
 	}
 
 	projectID := c.Param("id")
-	fmt.Printf("DEBUG: projectID=%s, userID=%s\n", projectID, userID) // ADD THIS
-	
+	filters := &repository.TaskFilters{ProjectID: projectID}
+	if !bindTaskSort(c, filters) {
+		return
+	}
+
+	// Without a limit or sort every task is returned in board order, as before.
+	// A page is described by the pagination headers; the body stays an array.
+	if limit, _ := strconv.Atoi(c.Query("limit")); limit > 0 || filters.SortBy != "" {
+		if limit > 0 {
+			filters.Limit = limit
+			filters.Offset, _ = strconv.Atoi(c.Query("offset"))
+			if filters.Offset < 0 {
+				filters.Offset = 0
+			}
+		}
+		tasks, total, err := h.taskService.FilterTasks(c.Request.Context(), filters, userID)
+		if err != nil {
+			logAPIError(c, "Task.ListByProject", err, map[string]interface{}{
+				"projectID": projectID,
+			})
+			handleServiceError(c, err)
+			return
+		}
+		setPaginationHeaders(c, total, filters.Limit, filters.Offset)
+		c.JSON(http.StatusOK, toTaskResponseList(tasks))
+		return
+	}
+
 	tasks, err := h.taskService.ListByProject(c.Request.Context(), projectID, userID)
 if err != nil {
 	logAPIError(c, "Task.ListByProject", err, map[string]interface{}{
@@ -197,7 +272,7 @@ if err != nil {
 	return
 }
 
-
+	setTotalCountHeader(c, len(tasks))
 	c.JSON(http.StatusOK, toTaskResponseList(tasks))
 }
 
@@ -242,7 +317,7 @@ func (h *TaskHandler) ListMyTasks(c *gin.Context) {
 
 	tasks, err := h.taskService.ListMyTasks(c.Request.Context(), userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch tasks"})
+		handleServiceError(c, err)
 		return
 	}
 
@@ -279,14 +354,19 @@ func (h *TaskHandler) UpdateStatus(c *gin.Context) {
 
 	taskID := c.Param("id")
 	var req struct {
-		Status string `json:"status" binding:"required"`
+		Status  string `json:"status" binding:"required"`
+		Version *int   `json:"version"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindError(c, err)
+		return
+	}
+	version, ok := requireTaskVersion(c, req.Version)
+	if !ok {
 		return
 	}
 
-	err := h.taskService.UpdateStatus(c.Request.Context(), taskID, req.Status, userID)
+	err := h.taskService.UpdateStatus(c.Request.Context(), taskID, req.Status, userID, version)
 	if err != nil {
 		handleServiceError(c, err)
 		return
@@ -304,13 +384,18 @@ func (h *TaskHandler) UpdatePriority(c *gin.Context) {
 	taskID := c.Param("id")
 	var req struct {
 		Priority string `json:"priority" binding:"required"`
+		Version  *int   `json:"version"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindError(c, err)
+		return
+	}
+	version, ok := requireTaskVersion(c, req.Version)
+	if !ok {
 		return
 	}
 
-	err := h.taskService.UpdatePriority(c.Request.Context(), taskID, req.Priority, userID)
+	err := h.taskService.UpdatePriority(c.Request.Context(), taskID, req.Priority, userID, version)
 	if err != nil {
 		handleServiceError(c, err)
 		return
@@ -332,7 +417,7 @@ func (h *TaskHandler) AssignTask(c *gin.Context) {
 		AssigneeID string `json:"assigneeId" binding:"required"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
@@ -379,7 +464,7 @@ func (h *TaskHandler) AddWatcher(c *gin.Context) {
 		WatcherID string `json:"watcherId" binding:"required"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
@@ -410,6 +495,66 @@ func (h *TaskHandler) RemoveWatcher(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Watcher removed successfully"})
 }
 
+// GetLabels returns the resolved labels of a task
+// GET /api/tasks/:id/labels
+func (h *TaskHandler) GetLabels(c *gin.Context) {
+	userID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	labels, err := h.taskService.GetLabels(c.Request.Context(), c.Param("id"), userID)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	response := make([]models.LabelResponse, len(labels))
+	for i, l := range labels {
+		response[i] = toLabelResponse(l)
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// AddLabel attaches a project label to a task
+// POST /api/tasks/:id/labels/:labelId
+func (h *TaskHandler) AddLabel(c *gin.Context) {
+	userID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	taskID := c.Param("id")
+	labelID := c.Param("labelId")
+
+	if err := h.taskService.AddLabel(c.Request.Context(), taskID, labelID, userID); err != nil {
+		logAPIError(c, "Task.AddLabel", err, map[string]interface{}{
+			"taskID":  taskID,
+			"labelID": labelID,
+		})
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Label added successfully"})
+}
+
+// RemoveLabel detaches a label from a task
+// DELETE /api/tasks/:id/labels/:labelId
+func (h *TaskHandler) RemoveLabel(c *gin.Context) {
+	userID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.taskService.RemoveLabel(c.Request.Context(), c.Param("id"), c.Param("labelId"), userID); err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Label removed successfully"})
+}
+
 func (h *TaskHandler) MarkComplete(c *gin.Context) {
 	userID, ok := middleware.RequireUserID(c)
 	if !ok {
@@ -437,7 +582,7 @@ func (h *TaskHandler) MoveToSprint(c *gin.Context) {
 		SprintID string `json:"sprintId" binding:"required"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
@@ -450,6 +595,35 @@ func (h *TaskHandler) MoveToSprint(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Task moved to sprint successfully"})
 }
 
+// MoveToProject moves the task and its subtasks to another project, under new keys
+// POST /api/tasks/:id/move-project
+func (h *TaskHandler) MoveToProject(c *gin.Context) {
+	userID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	taskID := c.Param("id")
+	var req struct {
+		ProjectID string `json:"projectId" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	task, err := h.taskService.MoveToProject(c.Request.Context(), taskID, req.ProjectID, userID)
+	if err != nil {
+		logAPIError(c, "Task.MoveToProject", err, map[string]interface{}{
+			"taskID": taskID, "projectID": req.ProjectID,
+		})
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toTaskResponse(task))
+}
+
 func (h *TaskHandler) ConvertToSubtask(c *gin.Context) {
 	userID, ok := middleware.RequireUserID(c)
 	if !ok {
@@ -461,7 +635,7 @@ func (h *TaskHandler) ConvertToSubtask(c *gin.Context) {
 		ParentTaskID string `json:"parentTaskId" binding:"required"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
@@ -503,11 +677,11 @@ func (h *TaskHandler) AddComment(c *gin.Context) {
 	taskID := c.Param("id")
 	var req models.CreateCommentRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
-	comment, err := h.taskService.AddComment(c.Request.Context(), taskID, userID, req.Content, req.MentionedUsers)
+	comment, err := h.taskService.AddComment(c.Request.Context(), taskID, userID, req.Content, req.MentionedUsers, req.ParentCommentID)
 	if err != nil {
 	logAPIError(c, "Task.AddComment", err, map[string]interface{}{
 		"taskID": taskID,
@@ -520,6 +694,63 @@ func (h *TaskHandler) AddComment(c *gin.Context) {
 	c.JSON(http.StatusCreated, toCommentResponse(comment))
 }
 
+// AddCommentReaction reacts to a comment with an emoji
+// POST /api/tasks/comments/:commentId/reactions
+func (h *TaskHandler) AddCommentReaction(c *gin.Context) {
+	userID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	commentID := c.Param("commentId")
+
+	var req models.CommentReactionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	comment, err := h.taskService.AddCommentReaction(c.Request.Context(), commentID, userID, req.Emoji)
+	if err != nil {
+		logAPIError(c, "Task.AddCommentReaction", err, map[string]interface{}{
+			"commentID": commentID,
+		})
+		handleServiceError(c, err)
+		return
+	}
+	if comment == nil {
+		handleServiceError(c, service.ErrCommentNotFound)
+		return
+	}
+
+	c.JSON(http.StatusOK, toCommentResponse(comment))
+}
+
+// RemoveCommentReaction takes back an emoji reaction
+// DELETE /api/tasks/comments/:commentId/reactions?emoji=
+func (h *TaskHandler) RemoveCommentReaction(c *gin.Context) {
+	userID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	commentID := c.Param("commentId")
+	comment, err := h.taskService.RemoveCommentReaction(c.Request.Context(), commentID, userID, c.Query("emoji"))
+	if err != nil {
+		logAPIError(c, "Task.RemoveCommentReaction", err, map[string]interface{}{
+			"commentID": commentID,
+		})
+		handleServiceError(c, err)
+		return
+	}
+	if comment == nil {
+		handleServiceError(c, service.ErrCommentNotFound)
+		return
+	}
+
+	c.JSON(http.StatusOK, toCommentResponse(comment))
+}
+
 func (h *TaskHandler) ListComments(c *gin.Context) {
 	userID, ok := middleware.RequireUserID(c)
 	if !ok {
@@ -529,7 +760,10 @@ func (h *TaskHandler) ListComments(c *gin.Context) {
 	taskID := c.Param("id")
 	comments, err := h.taskService.ListComments(c.Request.Context(), taskID, userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch comments"})
+		logAPIError(c, "Task.ListComments", err, map[string]interface{}{
+			"taskID": taskID,
+		})
+		handleServiceError(c, err)
 		return
 	}
 
@@ -545,7 +779,7 @@ func (h *TaskHandler) UpdateComment(c *gin.Context) {
 	commentID := c.Param("commentId")
 	var req models.UpdateCommentRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
@@ -558,6 +792,38 @@ func (h *TaskHandler) UpdateComment(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Comment updated successfully"})
 }
 
+// GetCommentHistory lists the previous versions of an edited comment
+// GET /api/tasks/comments/:commentId/history
+func (h *TaskHandler) GetCommentHistory(c *gin.Context) {
+	userID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	commentID := c.Param("commentId")
+	edits, err := h.taskService.GetCommentHistory(c.Request.Context(), commentID, userID)
+	if err != nil {
+		logAPIError(c, "Task.GetCommentHistory", err, map[string]interface{}{
+			"commentID": commentID,
+		})
+		handleServiceError(c, err)
+		return
+	}
+
+	response := make([]models.CommentEditResponse, len(edits))
+	for i, e := range edits {
+		response[i] = models.CommentEditResponse{
+			ID:              e.ID,
+			CommentID:       e.CommentID,
+			PreviousContent: e.PreviousContent,
+			EditedBy:        e.EditedBy,
+			EditedAt:        e.EditedAt,
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
 func (h *TaskHandler) DeleteComment(c *gin.Context) {
 	userID, ok := middleware.RequireUserID(c)
 	if !ok {
@@ -587,7 +853,7 @@ func (h *TaskHandler) AddAttachment(c *gin.Context) {
 	taskID := c.Param("id")
 	var req models.CreateAttachmentRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
@@ -600,6 +866,42 @@ func (h *TaskHandler) AddAttachment(c *gin.Context) {
 	c.JSON(http.StatusCreated, toAttachmentResponse(attachment))
 }
 
+// UploadAttachment stores a multipart file ("file" field) as a task attachment
+// POST /api/tasks/:id/attachments/upload
+func (h *TaskHandler) UploadAttachment(c *gin.Context) {
+	userID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	taskID := c.Param("id")
+	header, err := c.FormFile("file")
+	if err != nil {
+		respondFormFileError(c, err)
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "Failed to read file")
+		return
+	}
+	defer file.Close()
+
+	attachment, err := h.taskService.UploadAttachment(c.Request.Context(), taskID, userID, header.Filename, file, header.Size)
+	if err != nil {
+		logAPIError(c, "Task.UploadAttachment", err, map[string]interface{}{
+			"taskID":   taskID,
+			"filename": header.Filename,
+			"size":     header.Size,
+		})
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, toAttachmentResponse(attachment))
+}
+
 func (h *TaskHandler) ListAttachments(c *gin.Context) {
 	userID, ok := middleware.RequireUserID(c)
 	if !ok {
@@ -609,7 +911,7 @@ func (h *TaskHandler) ListAttachments(c *gin.Context) {
 	taskID := c.Param("id")
 	attachments, err := h.taskService.ListAttachments(c.Request.Context(), taskID, userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch attachments"})
+		handleServiceError(c, err)
 		return
 	}
 
@@ -648,7 +950,7 @@ if err != nil {
 	logAPIError(c, "Task.StartTimer", err, map[string]interface{}{
 		"taskID": taskID,
 	})
-	c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start timer"})
+	handleServiceError(c, err)
 	return
 }
 
@@ -664,7 +966,7 @@ func (h *TaskHandler) StopTimer(c *gin.Context) {
 
 	entry, err := h.taskService.StopTimer(c.Request.Context(), userID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "No active timer"})
+		respondError(c, http.StatusNotFound, "No active timer")
 		return
 	}
 
@@ -679,7 +981,7 @@ func (h *TaskHandler) GetActiveTimer(c *gin.Context) {
 
 	entry, err := h.taskService.GetActiveTimer(c.Request.Context(), userID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "No active timer"})
+		respondError(c, http.StatusNotFound, "No active timer")
 		return
 	}
 
@@ -695,13 +997,13 @@ func (h *TaskHandler) LogTime(c *gin.Context) {
 	taskID := c.Param("id")
 	var req models.LogTimeRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
 	entry, err := h.taskService.LogTime(c.Request.Context(), taskID, userID, req.DurationSeconds, req.Description)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to log time"})
+		handleServiceError(c, err)
 		return
 	}
 
@@ -717,7 +1019,7 @@ func (h *TaskHandler) GetTimeEntries(c *gin.Context) {
 	taskID := c.Param("id")
 	entries, err := h.taskService.GetTimeEntries(c.Request.Context(), taskID, userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch time entries"})
+		handleServiceError(c, err)
 		return
 	}
 
@@ -728,7 +1030,7 @@ func (h *TaskHandler) GetTotalTime(c *gin.Context) {
 	taskID := c.Param("id")
 	totalSeconds, err := h.taskService.GetTotalTime(c.Request.Context(), taskID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to calculate total time"})
+		handleServiceError(c, err)
 		return
 	}
 
@@ -739,6 +1041,55 @@ func (h *TaskHandler) GetTotalTime(c *gin.Context) {
 	})
 }
 
+// GetTimeReport returns time logged per user and task in a project
+// GET /api/projects/:id/time-report?from=&to=
+// from and to accept RFC3339 or YYYY-MM-DD (to is exclusive); defaults to the last 7 days
+func (h *TaskHandler) GetTimeReport(c *gin.Context) {
+	userID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	projectID := c.Param("id")
+	to := time.Now()
+	from := to.AddDate(0, 0, -7)
+
+	if raw := c.Query("from"); raw != "" {
+		parsed, err := parseReportTime(raw)
+		if err != nil {
+			respondError(c, http.StatusBadRequest, "Invalid 'from' date, use RFC3339 or YYYY-MM-DD")
+			return
+		}
+		from = parsed
+	}
+	if raw := c.Query("to"); raw != "" {
+		parsed, err := parseReportTime(raw)
+		if err != nil {
+			respondError(c, http.StatusBadRequest, "Invalid 'to' date, use RFC3339 or YYYY-MM-DD")
+			return
+		}
+		to = parsed
+	}
+
+	report, err := h.taskService.GetTimeReport(c.Request.Context(), projectID, userID, from, to)
+	if err != nil {
+		logAPIError(c, "Task.GetTimeReport", err, map[string]interface{}{
+			"projectID": projectID,
+		})
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+func parseReportTime(raw string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", raw)
+}
+
 // ============================================
 // DEPENDENCIES
 // ============================================
@@ -752,7 +1103,7 @@ func (h *TaskHandler) AddDependency(c *gin.Context) {
 	taskID := c.Param("id")
 	var req models.CreateDependencyRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
@@ -792,7 +1143,7 @@ func (h *TaskHandler) ListDependencies(c *gin.Context) {
 	taskID := c.Param("id")
 	deps, err := h.taskService.ListDependencies(c.Request.Context(), taskID, userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch dependencies"})
+		handleServiceError(c, err)
 		return
 	}
 
@@ -808,7 +1159,7 @@ func (h *TaskHandler) ListBlockedBy(c *gin.Context) {
 	taskID := c.Param("id")
 	deps, err := h.taskService.ListBlockedBy(c.Request.Context(), taskID, userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch blocked by tasks"})
+		handleServiceError(c, err)
 		return
 	}
 
@@ -828,7 +1179,7 @@ func (h *TaskHandler) CreateChecklist(c *gin.Context) {
 	taskID := c.Param("id")
 	var req models.CreateChecklistRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
@@ -850,7 +1201,7 @@ func (h *TaskHandler) AddChecklistItem(c *gin.Context) {
 	checklistID := c.Param("checklistId")
 	var req models.CreateChecklistItemRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
@@ -863,6 +1214,52 @@ func (h *TaskHandler) AddChecklistItem(c *gin.Context) {
 	c.JSON(http.StatusCreated, toChecklistItemResponse(item))
 }
 
+// UpdateChecklistItem changes the content or assignee of a checklist item
+// PUT /api/tasks/checklists/items/:itemId
+func (h *TaskHandler) UpdateChecklistItem(c *gin.Context) {
+	userID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	itemID := c.Param("itemId")
+
+	var req models.UpdateChecklistItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	item, err := h.taskService.UpdateChecklistItem(c.Request.Context(), itemID, userID, req.Content, req.AssigneeID)
+	if err != nil {
+		logAPIError(c, "Task.UpdateChecklistItem", err, map[string]interface{}{
+			"itemID": itemID,
+		})
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toChecklistItemResponse(item))
+}
+
+// ListMyChecklistItems returns open checklist items assigned to the current user
+// GET /api/users/me/checklist-items
+func (h *TaskHandler) ListMyChecklistItems(c *gin.Context) {
+	userID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	items, err := h.taskService.ListMyChecklistItems(c.Request.Context(), userID)
+	if err != nil {
+		logAPIError(c, "Task.ListMyChecklistItems", err, nil)
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, items)
+}
+
 func (h *TaskHandler) ToggleChecklistItem(c *gin.Context) {
 	userID, ok := middleware.RequireUserID(c)
 	if !ok {
@@ -904,18 +1301,74 @@ func (h *TaskHandler) ListChecklists(c *gin.Context) {
 	taskID := c.Param("id")
 	checklists, err := h.taskService.ListChecklists(c.Request.Context(), taskID, userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch checklists"})
+		handleServiceError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, toChecklistResponseList(checklists))
 }
 
-// ============================================
-// ACTIVITY
-// ============================================
-
-func (h *TaskHandler) GetActivity(c *gin.Context) {
+// ReorderChecklist moves a checklist between two others of its task
+// PATCH /api/tasks/checklists/:checklistId/reorder
+func (h *TaskHandler) ReorderChecklist(c *gin.Context) {
+	userID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	checklistID := c.Param("checklistId")
+
+	var req models.ReorderChecklistRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	checklist, err := h.taskService.ReorderChecklist(c.Request.Context(), checklistID, userID, req.BeforeChecklistID, req.AfterChecklistID)
+	if err != nil {
+		logAPIError(c, "Task.ReorderChecklist", err, map[string]interface{}{
+			"checklistID": checklistID,
+		})
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toChecklistResponse(checklist))
+}
+
+// ReorderChecklistItem moves an item between two others of its checklist
+// PATCH /api/tasks/checklists/items/:itemId/reorder
+func (h *TaskHandler) ReorderChecklistItem(c *gin.Context) {
+	userID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	itemID := c.Param("itemId")
+
+	var req models.ReorderChecklistItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	item, err := h.taskService.ReorderChecklistItem(c.Request.Context(), itemID, userID, req.BeforeItemID, req.AfterItemID)
+	if err != nil {
+		logAPIError(c, "Task.ReorderChecklistItem", err, map[string]interface{}{
+			"itemID": itemID,
+		})
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toChecklistItemResponse(item))
+}
+
+// ============================================
+// ACTIVITY
+// ============================================
+
+func (h *TaskHandler) GetActivity(c *gin.Context) {
 	userID, ok := middleware.RequireUserID(c)
 	if !ok {
 		return
@@ -927,13 +1380,45 @@ func (h *TaskHandler) GetActivity(c *gin.Context) {
 
 	activities, err := h.taskService.GetActivity(c.Request.Context(), taskID, userID, limit)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch activity"})
+		handleServiceError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, toActivityResponseList(activities))
 }
 
+// GetHistory returns the task's activity described for display, with
+// same-actor changes made close together merged into one entry
+// GET /api/tasks/:id/history?limit=
+func (h *TaskHandler) GetHistory(c *gin.Context) {
+	userID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	taskID := c.Param("id")
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+
+	entries, err := h.taskService.GetHistory(c.Request.Context(), taskID, userID, limit)
+	if err != nil {
+		logAPIError(c, "Task.GetHistory", err, map[string]interface{}{"taskID": taskID})
+		handleServiceError(c, err)
+		return
+	}
+
+	response := make([]models.TaskHistoryEntryResponse, len(entries))
+	for i, e := range entries {
+		response[i] = models.TaskHistoryEntryResponse{
+			ID:        e.ID,
+			ActorID:   e.ActorID,
+			ActorName: e.ActorName,
+			Changes:   e.Changes,
+			CreatedAt: e.CreatedAt,
+		}
+	}
+	c.JSON(http.StatusOK, response)
+}
+
 // ============================================
 // ADVANCED FILTERING
 // ============================================
@@ -945,32 +1430,24 @@ func (h *TaskHandler) FilterTasks(c *gin.Context) {
 
 	var req models.TaskFiltersRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
-	filters := &repository.TaskFilters{
-		ProjectID:   req.ProjectID,
-		SprintID:    req.SprintID,
-		AssigneeIDs: req.AssigneeIDs,
-		Status:      req.Statuses,    // []string matches
-		Priority:    req.Priorities,  // []string matches
-		LabelIDs:    req.LabelIDs,
-		Search:      req.SearchQuery, // *string matches
-		DueBefore:   req.DueBefore,
-		DueAfter:    req.DueAfter,
-		Overdue:     req.Overdue,
-		Blocked:     req.Blocked,
-		Limit:       req.Limit,
-		Offset:      req.Offset,
+	filters := toTaskFilters(req.ProjectID, &req.TaskViewFilters)
+	filters.Limit = req.Limit
+	filters.Offset = req.Offset
+	if !bindTaskSort(c, filters) {
+		return
 	}
 
 	tasks, total, err := h.taskService.FilterTasks(c.Request.Context(), filters, userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to filter tasks"})
+		handleServiceError(c, err)
 		return
 	}
 
+	setTotalCountHeader(c, total)
 	c.JSON(http.StatusOK, gin.H{
 		"tasks":  toTaskResponseList(tasks),
 		"total":  total,
@@ -979,6 +1456,427 @@ func (h *TaskHandler) FilterTasks(c *gin.Context) {
 	})
 }
 
+// ExportTasks streams the project's tasks as a CSV or JSON download. The
+// FilterTasks filters are accepted as query parameters.
+// GET /api/projects/:id/export?format=csv|json
+func (h *TaskHandler) ExportTasks(c *gin.Context) {
+	userID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	projectID := c.Param("id")
+	format := c.DefaultQuery("format", "csv")
+	if format != "csv" && format != "json" {
+		respondError(c, http.StatusBadRequest, "format must be csv or json")
+		return
+	}
+
+	filters, err := taskFiltersFromQuery(c, projectID)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	out := &taskExportWriter{c: c, format: format, filename: "tasks-" + projectID + "." + format}
+	if err := h.taskService.ExportTasks(c.Request.Context(), filters, userID, out.write); err != nil {
+		logAPIError(c, "Task.ExportTasks", err, map[string]interface{}{
+			"projectID": projectID,
+			"rows":      out.rows,
+		})
+		// Once streaming has begun the status is sent; the download is just cut short
+		if !out.started {
+			handleServiceError(c, err)
+		}
+		return
+	}
+	if err := out.finish(); err != nil {
+		logAPIError(c, "Task.ExportTasks", err, map[string]interface{}{"projectID": projectID})
+	}
+}
+
+var taskExportColumns = []string{
+	"Key", "Title", "Status", "Priority", "Assignees", "Story Points", "Due Date", "Labels", "Description",
+}
+
+// taskExportWriter writes export rows to the response as they arrive. Headers
+// go out with the first row, so errors before it can still become a JSON error.
+type taskExportWriter struct {
+	c        *gin.Context
+	format   string
+	filename string
+	started  bool
+	rows     int
+	csv      *csv.Writer
+}
+
+func (w *taskExportWriter) start() error {
+	w.started = true
+	if w.format == "csv" {
+		w.c.Header("Content-Type", "text/csv; charset=utf-8")
+	} else {
+		w.c.Header("Content-Type", "application/json; charset=utf-8")
+	}
+	w.c.Header("Content-Disposition", `attachment; filename="`+w.filename+`"`)
+	w.c.Status(http.StatusOK)
+
+	if w.format == "csv" {
+		// encoding/csv quotes fields containing commas, quotes and newlines
+		w.csv = csv.NewWriter(w.c.Writer)
+		return w.csv.Write(taskExportColumns)
+	}
+	_, err := w.c.Writer.WriteString("[")
+	return err
+}
+
+func (w *taskExportWriter) write(row *service.TaskExportRow) error {
+	if !w.started {
+		if err := w.start(); err != nil {
+			return err
+		}
+	}
+
+	if w.format == "csv" {
+		storyPoints, dueDate := "", ""
+		if row.StoryPoints != nil {
+			storyPoints = strconv.Itoa(*row.StoryPoints)
+		}
+		if row.DueDate != nil {
+			dueDate = row.DueDate.Format("2006-01-02")
+		}
+		if err := w.csv.Write([]string{
+			row.Key, row.Title, row.Status, row.Priority,
+			strings.Join(row.Assignees, "; "), storyPoints, dueDate,
+			strings.Join(row.Labels, "; "), row.Description,
+		}); err != nil {
+			return err
+		}
+	} else {
+		data, err := json.Marshal(row)
+		if err != nil {
+			return err
+		}
+		if w.rows > 0 {
+			data = append([]byte(","), data...)
+		}
+		if _, err := w.c.Writer.Write(data); err != nil {
+			return err
+		}
+	}
+
+	w.rows++
+	if w.rows%exportFlushEvery == 0 {
+		w.flush()
+	}
+	return nil
+}
+
+// exportFlushEvery is how many rows are buffered before they are sent to the client
+const exportFlushEvery = 100
+
+func (w *taskExportWriter) flush() {
+	if w.csv != nil {
+		w.csv.Flush()
+	}
+	w.c.Writer.Flush()
+}
+
+func (w *taskExportWriter) finish() error {
+	if !w.started {
+		if err := w.start(); err != nil {
+			return err
+		}
+	}
+	if w.format == "json" {
+		if _, err := w.c.Writer.WriteString("]"); err != nil {
+			return err
+		}
+	}
+	w.flush()
+	if w.csv != nil {
+		return w.csv.Error()
+	}
+	return nil
+}
+
+// taskFiltersFromQuery reads the FilterTasks filters from query parameters.
+// List parameters may be repeated or comma-separated; dates are RFC 3339 or
+// YYYY-MM-DD.
+func taskFiltersFromQuery(c *gin.Context, projectID string) (*repository.TaskFilters, error) {
+	f := &models.TaskViewFilters{
+		AssigneeIDs: queryList(c, "assigneeIds"),
+		Statuses:    queryList(c, "statuses"),
+		Priorities:  queryList(c, "priorities"),
+		LabelIDs:    queryList(c, "labelIds"),
+	}
+	if v := c.Query("sprintId"); v != "" {
+		f.SprintID = &v
+	}
+	if v := c.Query("searchQuery"); v != "" {
+		f.SearchQuery = &v
+	}
+	for param, dest := range map[string]**time.Time{"dueBefore": &f.DueBefore, "dueAfter": &f.DueAfter} {
+		if v := c.Query(param); v != "" {
+			t, err := parseReportTime(v)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s date", param)
+			}
+			*dest = &t
+		}
+	}
+	for param, dest := range map[string]**bool{"overdue": &f.Overdue, "blocked": &f.Blocked} {
+		if v := c.Query(param); v != "" {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s flag", param)
+			}
+			*dest = &b
+		}
+	}
+	return toTaskFilters(projectID, f), nil
+}
+
+// queryList collects a repeated and/or comma-separated query parameter
+func queryList(c *gin.Context, param string) []string {
+	var values []string
+	for _, raw := range c.QueryArray(param) {
+		for _, v := range strings.Split(raw, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				values = append(values, v)
+			}
+		}
+	}
+	return values
+}
+
+// GetMyWork returns the user's assigned tasks bucketed by due date across projects
+// GET /api/users/me/work
+func (h *TaskHandler) GetMyWork(c *gin.Context) {
+	userID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	work, err := h.taskService.GetMyWork(c.Request.Context(), userID)
+	if err != nil {
+		logAPIError(c, "Task.GetMyWork", err, nil)
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.MyWorkResponse{
+		Overdue:           toWorkSectionResponse(work.Overdue),
+		DueToday:          toWorkSectionResponse(work.DueToday),
+		DueThisWeek:       toWorkSectionResponse(work.DueThisWeek),
+		NoDueDate:         toWorkSectionResponse(work.NoDueDate),
+		RecentlyCompleted: toWorkSectionResponse(work.RecentlyCompleted),
+	})
+}
+
+// ============================================
+// SAVED VIEWS
+// ============================================
+
+// CreateView saves a named filter set, optionally shared with the project
+// POST /api/projects/:id/views
+func (h *TaskHandler) CreateView(c *gin.Context) {
+	userID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	projectID := c.Param("id")
+
+	var req models.CreateSavedViewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	view, err := h.taskService.CreateView(c.Request.Context(), projectID, userID, req.Name, req.Shared, toTaskFilters(projectID, &req.Filters))
+	if err != nil {
+		logAPIError(c, "Task.CreateView", err, map[string]interface{}{"projectID": projectID})
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, toSavedViewResponse(view))
+}
+
+// ListViews lists the caller's views of a project and the ones shared with it
+// GET /api/projects/:id/views
+func (h *TaskHandler) ListViews(c *gin.Context) {
+	userID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	projectID := c.Param("id")
+
+	views, err := h.taskService.ListViews(c.Request.Context(), projectID, userID)
+	if err != nil {
+		logAPIError(c, "Task.ListViews", err, map[string]interface{}{"projectID": projectID})
+		handleServiceError(c, err)
+		return
+	}
+
+	response := make([]models.SavedViewResponse, len(views))
+	for i, v := range views {
+		response[i] = toSavedViewResponse(v)
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// GetView returns a saved view
+// GET /api/views/:id
+func (h *TaskHandler) GetView(c *gin.Context) {
+	userID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	viewID := c.Param("id")
+
+	view, err := h.taskService.GetView(c.Request.Context(), viewID, userID)
+	if err != nil {
+		logAPIError(c, "Task.GetView", err, map[string]interface{}{"viewID": viewID})
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toSavedViewResponse(view))
+}
+
+// UpdateView renames, re-filters or (un)shares a view
+// PUT /api/views/:id
+func (h *TaskHandler) UpdateView(c *gin.Context) {
+	userID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	viewID := c.Param("id")
+
+	var req models.UpdateSavedViewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	var filters *repository.TaskFilters
+	if req.Filters != nil {
+		filters = toTaskFilters("", req.Filters)
+	}
+
+	view, err := h.taskService.UpdateView(c.Request.Context(), viewID, userID, req.Name, req.Shared, filters)
+	if err != nil {
+		logAPIError(c, "Task.UpdateView", err, map[string]interface{}{"viewID": viewID})
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toSavedViewResponse(view))
+}
+
+// DeleteView removes a saved view
+// DELETE /api/views/:id
+func (h *TaskHandler) DeleteView(c *gin.Context) {
+	userID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	viewID := c.Param("id")
+
+	if err := h.taskService.DeleteView(c.Request.Context(), viewID, userID); err != nil {
+		logAPIError(c, "Task.DeleteView", err, map[string]interface{}{"viewID": viewID})
+		handleServiceError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ApplyView runs a saved view's filters
+// GET /api/views/:id/tasks?limit=&offset=
+func (h *TaskHandler) ApplyView(c *gin.Context) {
+	userID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	viewID := c.Param("id")
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	if limit <= 0 {
+		limit = service.DefaultViewPageSize
+	}
+	offset, _ := strconv.Atoi(c.Query("offset"))
+
+	tasks, total, err := h.taskService.ApplySavedView(c.Request.Context(), viewID, userID, limit, offset)
+	if err != nil {
+		logAPIError(c, "Task.ApplyView", err, map[string]interface{}{"viewID": viewID})
+		handleServiceError(c, err)
+		return
+	}
+
+	setPaginationHeaders(c, total, limit, offset)
+	c.JSON(http.StatusOK, gin.H{
+		"tasks":  toTaskResponseList(tasks),
+		"total":  total,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+// SearchTasks searches tasks by key or title across all accessible projects
+func (h *TaskHandler) SearchTasks(c *gin.Context) {
+	userID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	query := c.Query("q")
+	if query == "" {
+		respondError(c, http.StatusBadRequest, "Query parameter 'q' is required")
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	tasks, err := h.taskService.SearchTasks(c.Request.Context(), userID, query, limit)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toTaskResponseList(tasks))
+}
+
+// SearchComments finds comments by content across the user's projects
+// GET /api/search/comments?q=
+func (h *TaskHandler) SearchComments(c *gin.Context) {
+	userID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	query := strings.TrimSpace(c.Query("q"))
+	if query == "" {
+		respondError(c, http.StatusBadRequest, "Query parameter 'q' is required")
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	results, err := h.taskService.SearchComments(c.Request.Context(), userID, query, limit)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, results)
+}
+
 func (h *TaskHandler) FindOverdue(c *gin.Context) {
 	userID, ok := middleware.RequireUserID(c)
 	if !ok {
@@ -1015,6 +1913,10 @@ func (h *TaskHandler) FindBlocked(c *gin.Context) {
 // SCRUM SPECIFIC
 // ============================================
 
+// GetBacklog lists the tasks that are in no sprint, with their count, story
+// points and count per priority. It takes the same filters as the task
+// export, except sprintId, and an optional ?limit=&offset= page.
+// GET /api/projects/:id/backlog
 func (h *TaskHandler) GetBacklog(c *gin.Context) {
 	userID, ok := middleware.RequireUserID(c)
 	if !ok {
@@ -1022,13 +1924,41 @@ func (h *TaskHandler) GetBacklog(c *gin.Context) {
 	}
 
 	projectID := c.Param("id")
-	tasks, err := h.taskService.GetBacklog(c.Request.Context(), projectID, userID)
+	filters, err := taskFiltersFromQuery(c, projectID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch backlog"})
+		respondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if !bindTaskSort(c, filters) {
 		return
 	}
+	if limit, _ := strconv.Atoi(c.Query("limit")); limit > 0 {
+		filters.Limit = limit
+		filters.Offset, _ = strconv.Atoi(c.Query("offset"))
+		if filters.Offset < 0 {
+			filters.Offset = 0
+		}
+	}
 
-	c.JSON(http.StatusOK, toTaskResponseList(tasks))
+	backlog, err := h.taskService.FilterBacklog(c.Request.Context(), filters, userID)
+	if err != nil {
+		logAPIError(c, "Task.GetBacklog", err, map[string]interface{}{
+			"projectID": projectID,
+		})
+		handleServiceError(c, err)
+		return
+	}
+
+	setPaginationHeaders(c, backlog.Total, filters.Limit, filters.Offset)
+	c.JSON(http.StatusOK, models.BacklogResponse{
+		Tasks: toTaskResponseList(backlog.Tasks),
+		Summary: models.BacklogSummary{
+			TotalTasks:       backlog.Summary.Tasks,
+			TotalStoryPoints: backlog.Summary.StoryPoints,
+			UnestimatedTasks: backlog.Summary.Unestimated,
+			ByPriority:       backlog.Summary.ByPriority,
+		},
+	})
 }
 
 func (h *TaskHandler) GetSprintBoard(c *gin.Context) {
@@ -1037,24 +1967,204 @@ func (h *TaskHandler) GetSprintBoard(c *gin.Context) {
 		return
 	}
 
-	sprintID := c.Param("sprintId")
+	sprintID := c.Param("id")
+
+	// Per-person swimlanes
+	if c.DefaultQuery("groupBy", "status") == "assignee" {
+		lanes, err := h.taskService.GetSprintBoardByAssignee(c.Request.Context(), sprintID, userID)
+		if err != nil {
+			handleServiceError(c, err)
+			return
+		}
+
+		response := make(gin.H, len(lanes))
+		for assigneeID, lane := range lanes {
+			var assignee *models.UserResponse
+			if lane.Assignee != nil {
+				u := toUserResponse(lane.Assignee)
+				assignee = &u
+			}
+			response[assigneeID] = gin.H{
+				"assignee": assignee,
+				"tasks":    toTaskResponseList(lane.Tasks),
+			}
+		}
+
+		c.JSON(http.StatusOK, response)
+		return
+	}
+
 	board, err := h.taskService.GetSprintBoard(c.Request.Context(), sprintID, userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch board"})
+		handleServiceError(c, err)
 		return
 	}
 
-	// Convert board map to response
+	// Convert board map to response, one key per configured column
 	response := gin.H{
-		"todo":        toTaskResponseList(board["todo"]),
-		"in_progress": toTaskResponseList(board["in_progress"]),
-		"in_review":   toTaskResponseList(board["in_review"]),
-		"done":        toTaskResponseList(board["done"]),
+		"statuses": board.Statuses,
+		"wip":      board.WIP,
+	}
+	for _, status := range board.Statuses {
+		response[status.Key] = toTaskResponseList(board.Columns[status.Key])
 	}
 
 	c.JSON(http.StatusOK, response)
 }
 
+// CloneTask duplicates a task into the same or another project
+// POST /api/tasks/:id/clone
+func (h *TaskHandler) CloneTask(c *gin.Context) {
+	userID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	taskID := c.Param("id")
+
+	var req models.CloneTaskRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondBindError(c, err)
+			return
+		}
+	}
+
+	clone, err := h.taskService.CloneTask(c.Request.Context(), taskID, userID, service.CloneOptions{
+		TargetProjectID: req.ProjectID,
+		IncludeSubtasks: req.IncludeSubtasks,
+		CopyAssignees:   req.CopyAssignees,
+		CopyComments:    req.CopyComments,
+		CopyAttachments: req.CopyAttachments,
+	})
+	if err != nil {
+		logAPIError(c, "Task.CloneTask", err, map[string]interface{}{
+			"taskID":    taskID,
+			"projectID": req.ProjectID,
+		})
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, toTaskResponse(clone))
+}
+
+// ReorderTask moves a task between two neighbours within a board column
+// POST /api/tasks/:id/reorder
+func (h *TaskHandler) ReorderTask(c *gin.Context) {
+	userID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	taskID := c.Param("id")
+
+	var req models.ReorderTaskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	task, err := h.taskService.ReorderTask(c.Request.Context(), taskID, userID, req.Status, req.BeforeTaskID, req.AfterTaskID)
+	if err != nil {
+		logAPIError(c, "Task.ReorderTask", err, map[string]interface{}{
+			"taskID": taskID,
+			"status": req.Status,
+		})
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toTaskResponse(task))
+}
+
+// GetWIPLimits returns the WIP limits configured for a project
+// GET /api/projects/:id/wip-limits
+func (h *TaskHandler) GetWIPLimits(c *gin.Context) {
+	userID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	projectID := c.Param("id")
+	limits, err := h.taskService.GetWIPLimits(c.Request.Context(), projectID, userID)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, limits)
+}
+
+// SetWIPLimit sets or clears the WIP limit of a column
+// PUT /api/projects/:id/wip-limits
+func (h *TaskHandler) SetWIPLimit(c *gin.Context) {
+	userID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	projectID := c.Param("id")
+
+	var req models.SetWIPLimitRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	if err := h.taskService.SetWIPLimit(c.Request.Context(), projectID, req.Status, req.MaxTasks, userID); err != nil {
+		logAPIError(c, "Task.SetWIPLimit", err, map[string]interface{}{
+			"projectID": projectID,
+			"status":    req.Status,
+		})
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "WIP limit updated"})
+}
+
+// GetTaskSettings returns the project's task behaviour settings
+// GET /api/projects/:id/task-settings
+func (h *TaskHandler) GetTaskSettings(c *gin.Context) {
+	userID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	settings, err := h.taskService.GetTaskSettings(c.Request.Context(), c.Param("id"), userID)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
+
+// UpdateTaskSettings changes the project's task behaviour settings
+// PUT /api/projects/:id/task-settings
+func (h *TaskHandler) UpdateTaskSettings(c *gin.Context) {
+	userID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateTaskSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	settings, err := h.taskService.UpdateTaskSettings(c.Request.Context(), c.Param("id"), userID, &req)
+	if err != nil {
+		logAPIError(c, "Task.UpdateTaskSettings", err, map[string]interface{}{"projectID": c.Param("id")})
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
+
 func (h *TaskHandler) GetSprintVelocity(c *gin.Context) {
 	userID, ok := middleware.RequireUserID(c)
 	if !ok {
@@ -1064,7 +2174,7 @@ func (h *TaskHandler) GetSprintVelocity(c *gin.Context) {
 	sprintID := c.Param("sprintId")
 	velocity, err := h.taskService.GetSprintVelocity(c.Request.Context(), sprintID, userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to calculate velocity"})
+		handleServiceError(c, err)
 		return
 	}
 
@@ -1083,7 +2193,7 @@ func (h *TaskHandler) GetSprintBurndown(c *gin.Context) {
 	sprintID := c.Param("sprintId")
 	burndown, err := h.taskService.GetSprintBurndown(c.Request.Context(), sprintID, userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch burndown"})
+		handleServiceError(c, err)
 		return
 	}
 
@@ -1108,12 +2218,10 @@ func (h *TaskHandler) UpdatePositionAndStatus(c *gin.Context) {
 	}
 	
 	if err := c.ShouldBindJSON(&req); err != nil {
-		log.Printf("❌ Binding error: %v", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		slog.DebugContext(c.Request.Context(), "invalid reorder payload", "taskID", taskID, "error", err)
+		respondError(c, http.StatusBadRequest, err.Error())
 		return
 	}
-	
-	log.Printf("✅ Successfully parsed: status=%s, position=%d", req.Status, req.Position)
 
 	// Get current task state
 	task, err := h.taskService.GetByID(c.Request.Context(), taskID, userID)
@@ -1127,7 +2235,8 @@ func (h *TaskHandler) UpdatePositionAndStatus(c *gin.Context) {
 
 	// Update task status
 	updateReq := &models.UpdateTaskRequest{
-		Status: &req.Status,
+		Status:  &req.Status,
+		Version: &task.Version,
 	}
 	
 	task, err = h.taskService.Update(c.Request.Context(), taskID, userID, updateReq)
@@ -1141,7 +2250,8 @@ func (h *TaskHandler) UpdatePositionAndStatus(c *gin.Context) {
 		subtasks, _ := h.taskService.ListSubtasks(c.Request.Context(), taskID, userID)
 		for _, subtask := range subtasks {
 			subUpdateReq := &models.UpdateTaskRequest{
-				Status: &req.Status,
+				Status:  &req.Status,
+				Version: &subtask.Version,
 			}
 			h.taskService.Update(c.Request.Context(), subtask.ID, userID, subUpdateReq)
 		}
@@ -1178,7 +2288,7 @@ func (h *TaskHandler) BulkUpdateStatus(c *gin.Context) {
 
 	var req models.BulkUpdateStatusRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
@@ -1204,7 +2314,7 @@ func (h *TaskHandler) BulkAssign(c *gin.Context) {
 
 	var req models.BulkAssignRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
@@ -1225,7 +2335,7 @@ func (h *TaskHandler) BulkMoveToSprint(c *gin.Context) {
 
 	var req models.BulkMoveToSprintRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
@@ -1238,9 +2348,158 @@ func (h *TaskHandler) BulkMoveToSprint(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Tasks moved to sprint successfully"})
 }
 
+// BulkLabels adds a label to or removes it from several tasks at once
+// POST /api/tasks/bulk/labels
+func (h *TaskHandler) BulkLabels(c *gin.Context) {
+	userID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	var req models.BulkLabelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	var results []*service.BulkLabelResult
+	var err error
+	if req.Action == "add" {
+		results, err = h.taskService.BulkAddLabel(c.Request.Context(), req.TaskIDs, req.LabelID, userID)
+	} else {
+		results, err = h.taskService.BulkRemoveLabel(c.Request.Context(), req.TaskIDs, req.LabelID, userID)
+	}
+	if err != nil {
+		logAPIError(c, "Task.BulkLabels", err, map[string]interface{}{
+			"taskCount": len(req.TaskIDs),
+			"labelID":   req.LabelID,
+			"action":    req.Action,
+		})
+		handleServiceError(c, err)
+		return
+	}
+
+	response := models.BulkLabelResponse{Results: make([]models.BulkLabelResult, len(results))}
+	for i, r := range results {
+		response.Results[i] = models.BulkLabelResult{TaskID: r.TaskID, Status: r.Status, Reason: r.Reason}
+		switch r.Status {
+		case service.BulkLabelApplied:
+			response.Applied++
+		case service.BulkLabelSkipped:
+			response.Skipped++
+		default:
+			response.Failed++
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// BulkDelete deletes several tasks at once; subtasks are deleted with their parent
+// POST /api/tasks/bulk/delete
+func (h *TaskHandler) BulkDelete(c *gin.Context) {
+	userID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	var req models.BulkDeleteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	err := h.taskService.BulkDelete(c.Request.Context(), req.TaskIDs, userID)
+	if err != nil {
+		logAPIError(c, "Task.BulkDelete", err, map[string]interface{}{
+			"taskCount": len(req.TaskIDs),
+		})
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Tasks deleted successfully"})
+}
 
 
 
+
+func toWorkSectionResponse(section *repository.WorkSection) models.WorkSectionResponse {
+	if section == nil {
+		return models.WorkSectionResponse{Tasks: []models.TaskResponse{}}
+	}
+	return models.WorkSectionResponse{
+		Tasks: toTaskResponseList(section.Tasks),
+		Total: section.Total,
+	}
+}
+
+// bindTaskSort reads the ?sort=&dir= query into the filters. It rejects the
+// request and returns false for a field that is not sortable or a direction
+// other than asc or desc.
+func bindTaskSort(c *gin.Context, filters *repository.TaskFilters) bool {
+	sortBy := c.Query("sort")
+	if sortBy == "" {
+		return true
+	}
+	if !repository.IsValidTaskSort(sortBy) {
+		respondErrorCode(c, http.StatusBadRequest, models.CodeInvalidInput, "Invalid sort field",
+			[]models.FieldError{{Field: "sort", Rule: "oneof"}})
+		return false
+	}
+	switch c.DefaultQuery("dir", "asc") {
+	case "asc":
+	case "desc":
+		filters.SortDesc = true
+	default:
+		respondErrorCode(c, http.StatusBadRequest, models.CodeInvalidInput, "Sort direction must be asc or desc",
+			[]models.FieldError{{Field: "dir", Rule: "oneof"}})
+		return false
+	}
+	filters.SortBy = sortBy
+	return true
+}
+
+func toTaskFilters(projectID string, f *models.TaskViewFilters) *repository.TaskFilters {
+	return &repository.TaskFilters{
+		ProjectID:   projectID,
+		SprintID:    f.SprintID,
+		AssigneeIDs: f.AssigneeIDs,
+		Status:      f.Statuses,
+		Priority:    f.Priorities,
+		LabelIDs:    f.LabelIDs,
+		Search:      f.SearchQuery,
+		DueBefore:   f.DueBefore,
+		DueAfter:    f.DueAfter,
+		Overdue:     f.Overdue,
+		Blocked:     f.Blocked,
+	}
+}
+
+func toSavedViewResponse(v *repository.SavedView) models.SavedViewResponse {
+	return models.SavedViewResponse{
+		ID:        v.ID,
+		ProjectID: v.ProjectID,
+		UserID:    v.UserID,
+		Name:      v.Name,
+		Shared:    v.Shared,
+		Filters: models.TaskViewFilters{
+			SprintID:    v.Filters.SprintID,
+			AssigneeIDs: v.Filters.AssigneeIDs,
+			Statuses:    v.Filters.Status,
+			Priorities:  v.Filters.Priority,
+			LabelIDs:    v.Filters.LabelIDs,
+			SearchQuery: v.Filters.Search,
+			DueBefore:   v.Filters.DueBefore,
+			DueAfter:    v.Filters.DueAfter,
+			Overdue:     v.Filters.Overdue,
+			Blocked:     v.Filters.Blocked,
+		},
+		CreatedAt: v.CreatedAt,
+		UpdatedAt: v.UpdatedAt,
+	}
+}
+
 func toTaskResponseList(tasks []*repository.Task) []models.TaskResponse {
 	response := make([]models.TaskResponse, len(tasks))
 	for i, t := range tasks {
@@ -1250,14 +2509,36 @@ func toTaskResponseList(tasks []*repository.Task) []models.TaskResponse {
 }
 
 func toCommentResponse(c *repository.TaskComment) models.CommentResponse {
+	if c == nil {
+		return models.CommentResponse{}
+	}
+	var replies []models.CommentResponse
+	if len(c.Replies) > 0 {
+		replies = toCommentResponseList(c.Replies)
+	}
+	reactions := make([]models.CommentReaction, len(c.Reactions))
+	for i, r := range c.Reactions {
+		reactions[i] = models.CommentReaction{Emoji: r.Emoji, Count: r.Count, Reacted: r.Reacted}
+	}
+	var author *models.UserResponse
+	if c.User != nil {
+		user := toUserResponse(c.User)
+		author = &user
+	}
 	return models.CommentResponse{
-		ID:             c.ID,
-		TaskID:         c.TaskID,
-		UserID:         c.UserID,
-		Content:        c.Content,
-		MentionedUsers: c.MentionedUsers,
-		CreatedAt:      c.CreatedAt,
-		UpdatedAt:      c.UpdatedAt,
+		ID:              c.ID,
+		TaskID:          c.TaskID,
+		UserID:          c.UserID,
+		User:            author,
+		Content:         c.Content,
+		MentionedUsers:  c.MentionedUsers,
+		ParentCommentID: c.ParentCommentID,
+		Replies:         replies,
+		Reactions:       reactions,
+		Edited:          c.EditedAt != nil,
+		EditedAt:        c.EditedAt,
+		CreatedAt:       c.CreatedAt,
+		UpdatedAt:       c.UpdatedAt,
 	}
 }
 
@@ -1270,6 +2551,9 @@ func toCommentResponseList(comments []*repository.TaskComment) []models.CommentR
 }
 
 func toAttachmentResponse(a *repository.TaskAttachment) models.AttachmentResponse {
+	if a == nil {
+		return models.AttachmentResponse{}
+	}
 	return models.AttachmentResponse{
 		ID:        a.ID,
 		TaskID:    a.TaskID,
@@ -1335,6 +2619,7 @@ func toChecklistItemResponse(item *repository.ChecklistItem) models.ChecklistIte
 		ID:          item.ID,
 		ChecklistID: item.ChecklistID,
 		Content:     item.Content,
+		Completed:   item.Completed,
 		Position:    item.Position,
 		CreatedAt:   item.CreatedAt,
 		UpdatedAt:   item.UpdatedAt,
@@ -1358,6 +2643,7 @@ func toChecklistResponse(cl *repository.TaskChecklist) models.ChecklistResponse
 		ID:        cl.ID,
 		TaskID:    cl.TaskID,
 		Title:     cl.Title,
+		Position:  cl.Position,
 		Items:     items,
 		CreatedAt: cl.CreatedAt,
 		UpdatedAt: cl.UpdatedAt,  // ✅ Added missing field
@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/repository"
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// nilTaskService returns (nil, nil) from lookups, which handlers must treat
+// as not found, and not-found errors from the comment and attachment writes
+type nilTaskService struct {
+	service.TaskService
+}
+
+func (nilTaskService) GetByID(context.Context, string, string) (*repository.Task, error) {
+	return nil, nil
+}
+
+func (nilTaskService) GetByKey(context.Context, string, string) (*repository.Task, error) {
+	return nil, nil
+}
+
+func (nilTaskService) AddCommentReaction(context.Context, string, string, string) (*repository.TaskComment, error) {
+	return nil, nil
+}
+
+func (nilTaskService) RemoveCommentReaction(context.Context, string, string, string) (*repository.TaskComment, error) {
+	return nil, nil
+}
+
+func (nilTaskService) UpdateComment(context.Context, string, string, string) error {
+	return service.ErrCommentNotFound
+}
+
+func (nilTaskService) DeleteAttachment(context.Context, string, string) error {
+	return service.ErrAttachmentNotFound
+}
+
+func TestTaskHandlerNotFoundPaths(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewTaskHandler(nilTaskService{})
+	r := gin.New()
+	r.Use(func(c *gin.Context) { c.Set("userID", "user-1"); c.Next() })
+	r.GET("/tasks/:id", h.Get)
+	r.GET("/tasks/key/:key", h.GetByKey)
+	r.POST("/comments/:commentId/reactions", h.AddCommentReaction)
+	r.DELETE("/comments/:commentId/reactions", h.RemoveCommentReaction)
+	r.PUT("/comments/:commentId", h.UpdateComment)
+	r.DELETE("/attachments/:attachmentId", h.DeleteAttachment)
+
+	cases := []struct {
+		name, method, path, body string
+	}{
+		{"task by id", http.MethodGet, "/tasks/t1", ""},
+		{"task by key", http.MethodGet, "/tasks/key/ORA-1", ""},
+		{"add reaction", http.MethodPost, "/comments/c1/reactions", `{"emoji":"👍"}`},
+		{"remove reaction", http.MethodDelete, "/comments/c1/reactions?emoji=👍", ""},
+		{"update comment", http.MethodPut, "/comments/c1", `{"content":"edit"}`},
+		{"delete attachment", http.MethodDelete, "/attachments/a1", ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, tc.path, strings.NewReader(tc.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			if w.Code != http.StatusNotFound {
+				t.Fatalf("%s %s = %d, want 404 (body %s)", tc.method, tc.path, w.Code, w.Body.String())
+			}
+		})
+	}
+}
@@ -56,7 +56,7 @@ func (h *TeamHandler) Create(c *gin.Context) {
 
 	var req CreateTeamRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
@@ -121,7 +121,7 @@ func (h *TeamHandler) Update(c *gin.Context) {
 
 	var req UpdateTeamRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
@@ -160,7 +160,7 @@ func (h *TeamHandler) AddMember(c *gin.Context) {
 
 	var req AddTeamMemberRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
@@ -170,7 +170,7 @@ func (h *TeamHandler) AddMember(c *gin.Context) {
 	} else if req.Email != nil {
 		err = h.teamSvc.AddMemberByEmail(c.Request.Context(), teamID, *req.Email, req.Role, addedByID)
 	} else {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "userId or email is required"})
+		respondError(c, http.StatusBadRequest, "userId or email is required")
 		return
 	}
 
@@ -202,7 +202,7 @@ func (h *TeamHandler) UpdateMemberRole(c *gin.Context) {
 
 	var req UpdateTeamMemberRoleRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
@@ -25,7 +25,7 @@ func (h *UserHandler) GetCurrentUser(c *gin.Context) {
 
 	user, err := h.userService.GetByID(c.Request.Context(), userID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		respondError(c, http.StatusNotFound, "User not found")
 		return
 	}
 
@@ -40,19 +40,72 @@ func (h *UserHandler) UpdateCurrentUser(c *gin.Context) {
 
 	var req models.UpdateUserRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
 	user, err := h.userService.Update(c.Request.Context(), userID, req.Name, req.Avatar)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update user"})
+		handleServiceError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, toUserResponse(user))
 }
 
+// UploadAvatar stores a multipart image ("file" field) and sets it as the avatar
+// POST /api/users/me/avatar
+func (h *UserHandler) UploadAvatar(c *gin.Context) {
+	userID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		respondFormFileError(c, err)
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "Failed to read file")
+		return
+	}
+	defer file.Close()
+
+	user, err := h.userService.UploadAvatar(c.Request.Context(), userID, header.Filename, file, header.Size)
+	if err != nil {
+		logAPIError(c, "User.UploadAvatar", err, map[string]interface{}{
+			"filename": header.Filename,
+			"size":     header.Size,
+		})
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toUserResponse(user))
+}
+
+// DeleteCurrentUser deletes the caller's account. The user row is kept,
+// scrubbed of personal data, so comments and activity they left stay intact
+// and show them as "Deleted user".
+// DELETE /api/users/me
+func (h *UserHandler) DeleteCurrentUser(c *gin.Context) {
+	userID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.userService.Delete(c.Request.Context(), userID); err != nil {
+		logAPIError(c, "User.DeleteCurrentUser", err, nil)
+		handleServiceError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
 // SearchUsers searches for users by email or name
 func (h *UserHandler) SearchUsers(c *gin.Context) {
 	query := c.Query("q")
@@ -63,7 +116,7 @@ func (h *UserHandler) SearchUsers(c *gin.Context) {
 
 	users, err := h.userService.Search(c.Request.Context(), query)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search users"})
+		handleServiceError(c, err)
 		return
 	}
 
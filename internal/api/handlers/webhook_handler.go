@@ -0,0 +1,193 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/api/middleware"
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/models"
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/repository"
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// ============================================
+// Webhook Handler
+// ============================================
+
+type WebhookHandler struct {
+	webhookService service.WebhookService
+}
+
+// List returns the workspace's webhooks
+// GET /api/workspaces/:id/webhooks
+func (h *WebhookHandler) List(c *gin.Context) {
+	userID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	webhooks, err := h.webhookService.List(c.Request.Context(), c.Param("id"), userID)
+	if err != nil {
+		logAPIError(c, "Webhook.List", err, map[string]interface{}{"workspaceID": c.Param("id")})
+		handleServiceError(c, err)
+		return
+	}
+
+	response := make([]models.WebhookResponse, len(webhooks))
+	for i, w := range webhooks {
+		response[i] = toWebhookResponse(w, false)
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// Create registers a webhook. The response includes the signing secret.
+// POST /api/workspaces/:id/webhooks
+func (h *WebhookHandler) Create(c *gin.Context) {
+	userID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	var req models.CreateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	webhook, err := h.webhookService.Create(c.Request.Context(), c.Param("id"), userID, &req)
+	if err != nil {
+		logAPIError(c, "Webhook.Create", err, map[string]interface{}{"workspaceID": c.Param("id")})
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, toWebhookResponse(webhook, true))
+}
+
+// Get returns one webhook
+// GET /api/workspaces/:id/webhooks/:webhookId
+func (h *WebhookHandler) Get(c *gin.Context) {
+	userID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	webhook, err := h.webhookService.Get(c.Request.Context(), c.Param("id"), c.Param("webhookId"), userID)
+	if err != nil {
+		logAPIError(c, "Webhook.Get", err, map[string]interface{}{"webhookID": c.Param("webhookId")})
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toWebhookResponse(webhook, false))
+}
+
+// Update changes a webhook. A new secret is echoed back in the response.
+// PUT /api/workspaces/:id/webhooks/:webhookId
+func (h *WebhookHandler) Update(c *gin.Context) {
+	userID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	webhook, err := h.webhookService.Update(c.Request.Context(), c.Param("id"), c.Param("webhookId"), userID, &req)
+	if err != nil {
+		logAPIError(c, "Webhook.Update", err, map[string]interface{}{"webhookID": c.Param("webhookId")})
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toWebhookResponse(webhook, req.Secret != nil))
+}
+
+// Delete removes a webhook and its dead-letter log
+// DELETE /api/workspaces/:id/webhooks/:webhookId
+func (h *WebhookHandler) Delete(c *gin.Context) {
+	userID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.webhookService.Delete(c.Request.Context(), c.Param("id"), c.Param("webhookId"), userID); err != nil {
+		logAPIError(c, "Webhook.Delete", err, map[string]interface{}{"webhookID": c.Param("webhookId")})
+		handleServiceError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// Test sends a webhook.test event right away and reports how the endpoint answered
+// POST /api/workspaces/:id/webhooks/:webhookId/test
+func (h *WebhookHandler) Test(c *gin.Context) {
+	userID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.webhookService.TestDelivery(c.Request.Context(), c.Param("id"), c.Param("webhookId"), userID)
+	if err != nil {
+		logAPIError(c, "Webhook.Test", err, map[string]interface{}{"webhookID": c.Param("webhookId")})
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// ListDeadLetters returns the most recent deliveries that failed on every attempt
+// GET /api/workspaces/:id/webhooks/:webhookId/dead-letters
+func (h *WebhookHandler) ListDeadLetters(c *gin.Context) {
+	userID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	letters, err := h.webhookService.ListDeadLetters(c.Request.Context(), c.Param("id"), c.Param("webhookId"), userID)
+	if err != nil {
+		logAPIError(c, "Webhook.ListDeadLetters", err, map[string]interface{}{"webhookID": c.Param("webhookId")})
+		handleServiceError(c, err)
+		return
+	}
+
+	response := make([]models.WebhookDeadLetterResponse, len(letters))
+	for i, l := range letters {
+		response[i] = models.WebhookDeadLetterResponse{
+			ID:         l.ID,
+			Event:      l.Event,
+			Payload:    json.RawMessage(l.Payload),
+			Attempts:   l.Attempts,
+			StatusCode: l.StatusCode,
+			LastError:  l.LastError,
+			CreatedAt:  l.CreatedAt,
+		}
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+func toWebhookResponse(w *repository.Webhook, withSecret bool) models.WebhookResponse {
+	resp := models.WebhookResponse{
+		ID:          w.ID,
+		WorkspaceID: w.WorkspaceID,
+		URL:         w.URL,
+		Events:      w.Events,
+		Active:      w.Active,
+		CreatedBy:   w.CreatedBy,
+		CreatedAt:   w.CreatedAt,
+		UpdatedAt:   w.UpdatedAt,
+	}
+	if resp.Events == nil {
+		resp.Events = []string{}
+	}
+	if withSecret {
+		resp.Secret = w.Secret
+	}
+	return resp
+}
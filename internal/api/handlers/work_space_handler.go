@@ -27,7 +27,7 @@ func (h *WorkspaceHandler) List(c *gin.Context) {
 
 	workspaces, err := h.workspaceService.List(c.Request.Context(), userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch workspaces"})
+		handleServiceError(c, err)
 		return
 	}
 
@@ -47,7 +47,7 @@ func (h *WorkspaceHandler) Create(c *gin.Context) {
 
 	var req models.CreateWorkspaceRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
@@ -63,7 +63,7 @@ func (h *WorkspaceHandler) Create(c *gin.Context) {
 		req.AllowedTeams,
 	)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create workspace"})
+		handleServiceError(c, err)
 		return
 	}
 
@@ -75,7 +75,7 @@ func (h *WorkspaceHandler) Get(c *gin.Context) {
 
 	workspace, err := h.workspaceService.GetByID(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Workspace not found"})
+		respondError(c, http.StatusNotFound, "Workspace not found")
 		return
 	}
 
@@ -87,7 +87,7 @@ func (h *WorkspaceHandler) Update(c *gin.Context) {
 
 	var req models.UpdateWorkspaceRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
@@ -103,7 +103,7 @@ func (h *WorkspaceHandler) Update(c *gin.Context) {
 		req.AllowedTeams,
 	)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update workspace"})
+		handleServiceError(c, err)
 		return
 	}
 
@@ -114,9 +114,37 @@ func (h *WorkspaceHandler) Delete(c *gin.Context) {
 	id := c.Param("id")
 
 	if err := h.workspaceService.Delete(c.Request.Context(), id); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete workspace"})
+		handleServiceError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusNoContent, nil)
 }
+
+// GetPresence returns each member's status and last activity for presence dots
+// GET /api/workspaces/:id/presence
+func (h *WorkspaceHandler) GetPresence(c *gin.Context) {
+	userID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	users, err := h.workspaceService.GetPresence(c.Request.Context(), c.Param("id"), userID)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	presence := make([]models.UserPresenceResponse, 0, len(users))
+	for _, u := range users {
+		presence = append(presence, models.UserPresenceResponse{
+			UserID:       u.ID,
+			Name:         u.Name,
+			Avatar:       u.Avatar,
+			Status:       u.Status,
+			LastActiveAt: u.LastActiveAt,
+		})
+	}
+
+	c.JSON(http.StatusOK, presence)
+}
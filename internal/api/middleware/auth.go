@@ -1,11 +1,13 @@
 package middleware
 
 import (
-	"log"
+	"errors"
+	"log/slog"
 	"net/http"
 	"strings"
-	"time"
 
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/logger"
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/models"
 	"github.com/Marga-Ghale/ora-scrum-backend/internal/service"
 	"github.com/gin-gonic/gin"
 )
@@ -15,8 +17,8 @@ func AuthMiddleware(authService service.AuthService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
-			log.Printf("❌ [Auth] Missing Authorization header - Path: %s", c.Request.URL.Path)
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header required"})
+			slog.WarnContext(c.Request.Context(), "missing authorization header", "path", c.Request.URL.Path)
+			c.JSON(http.StatusUnauthorized, models.NewErrorResponse(models.CodeUnauthenticated, "Authorization header required", nil))
 			c.Abort()
 			return
 		}
@@ -24,8 +26,8 @@ func AuthMiddleware(authService service.AuthService) gin.HandlerFunc {
 		// Extract token from "Bearer <token>"
 		parts := strings.Split(authHeader, " ")
 		if len(parts) != 2 || parts[0] != "Bearer" {
-			log.Printf("❌ [Auth] Invalid header format - Path: %s", c.Request.URL.Path)
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid authorization header format"})
+			slog.WarnContext(c.Request.Context(), "invalid authorization header format", "path", c.Request.URL.Path)
+			c.JSON(http.StatusUnauthorized, models.NewErrorResponse(models.CodeUnauthenticated, "Invalid authorization header format", nil))
 			c.Abort()
 			return
 		}
@@ -35,8 +37,8 @@ func AuthMiddleware(authService service.AuthService) gin.HandlerFunc {
 		// Validate token
 		token, err := authService.ValidateToken(tokenString)
 		if err != nil || !token.Valid {
-			log.Printf("❌ [Auth] Invalid token - Path: %s, Error: %v", c.Request.URL.Path, err)
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
+			slog.WarnContext(c.Request.Context(), "invalid token", "path", c.Request.URL.Path, "error", err)
+			c.JSON(http.StatusUnauthorized, models.NewErrorResponse(models.CodeUnauthenticated, "Invalid or expired token", nil))
 			c.Abort()
 			return
 		}
@@ -44,15 +46,28 @@ func AuthMiddleware(authService service.AuthService) gin.HandlerFunc {
 		// Extract user ID from token
 		userID, err := authService.GetUserIDFromToken(token)
 		if err != nil {
-			log.Printf("❌ [Auth] Failed to extract userID - Path: %s, Error: %v", c.Request.URL.Path, err)
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token claims"})
+			slog.WarnContext(c.Request.Context(), "invalid token claims", "path", c.Request.URL.Path, "error", err)
+			c.JSON(http.StatusUnauthorized, models.NewErrorResponse(models.CodeUnauthenticated, "Invalid token claims", nil))
 			c.Abort()
 			return
 		}
 
-		// Set user ID in context for handlers
+		// The token outlives a deleted account
+		if err := authService.CheckActive(c.Request.Context(), userID); err != nil {
+			if errors.Is(err, service.ErrUserNotFound) {
+				slog.WarnContext(c.Request.Context(), "token of deleted user", "path", c.Request.URL.Path, "userID", userID)
+				c.JSON(http.StatusUnauthorized, models.NewErrorResponse(models.CodeUnauthenticated, "Account no longer exists", nil))
+			} else {
+				slog.ErrorContext(c.Request.Context(), "failed to check user", "path", c.Request.URL.Path, "error", err)
+				c.JSON(http.StatusInternalServerError, models.NewErrorResponse(models.CodeInternal, "Internal server error", nil))
+			}
+			c.Abort()
+			return
+		}
+
+		// Set user ID in context for handlers, and in the request context for logs
 		c.Set("userID", userID)
-		log.Printf("✅ [Auth] User authenticated - UserID: %s, Path: %s", userID, c.Request.URL.Path)
+		c.Request = c.Request.WithContext(logger.WithUserID(c.Request.Context(), userID))
 		c.Next()
 	}
 }
@@ -80,68 +95,17 @@ func OptionalAuthMiddleware(authService service.AuthService) gin.HandlerFunc {
 		}
 
 		userID, err := authService.GetUserIDFromToken(token)
-		if err != nil {
+		if err != nil || authService.CheckActive(c.Request.Context(), userID) != nil {
 			c.Next()
 			return
 		}
 
 		c.Set("userID", userID)
+		c.Request = c.Request.WithContext(logger.WithUserID(c.Request.Context(), userID))
 		c.Next()
 	}
 }
 
-// RequestLogger logs all incoming requests with details
-func RequestLogger() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		start := time.Now()
-		path := c.Request.URL.Path
-		method := c.Request.Method
-
-		// Process request
-		c.Next()
-
-		// Log after request
-		duration := time.Since(start)
-		status := c.Writer.Status()
-		
-		// Color code based on status
-		statusEmoji := "✅"
-		if status >= 400 && status < 500 {
-			statusEmoji = "⚠️"
-		} else if status >= 500 {
-			statusEmoji = "❌"
-		}
-
-		log.Printf("%s [%s] %s %d - %v", statusEmoji, method, path, status, duration)
-		
-		// Log errors if any
-		if len(c.Errors) > 0 {
-			for _, e := range c.Errors {
-				log.Printf("❌ [Error] %v", e.Err)
-			}
-		}
-	}
-}
-
-// ErrorLogger logs detailed error information
-func ErrorLogger() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		c.Next()
-
-		// Check for errors after request processing
-		if len(c.Errors) > 0 {
-			for _, err := range c.Errors {
-				log.Printf("❌ [Error Details] Path: %s, Method: %s, Error: %v, Type: %v", 
-					c.Request.URL.Path, 
-					c.Request.Method, 
-					err.Err, 
-					err.Type,
-				)
-			}
-		}
-	}
-}
-
 // GetUserID extracts user ID from gin context
 func GetUserID(c *gin.Context) string {
 	userID, exists := c.Get("userID")
@@ -155,8 +119,8 @@ func GetUserID(c *gin.Context) string {
 func RequireUserID(c *gin.Context) (string, bool) {
 	userID := GetUserID(c)
 	if userID == "" {
-		log.Printf("❌ [Auth] User not authenticated - Path: %s", c.Request.URL.Path)
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		slog.WarnContext(c.Request.Context(), "user not authenticated", "path", c.Request.URL.Path)
+		c.JSON(http.StatusUnauthorized, models.NewErrorResponse(models.CodeUnauthenticated, "User not authenticated", nil))
 		return "", false
 	}
 	return userID, true
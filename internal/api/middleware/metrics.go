@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/metrics"
+	"github.com/gin-gonic/gin"
+)
+
+// Metrics records the count and latency of every request. Requests are
+// labelled by route template (/api/tasks/:id), not by path, so IDs do not
+// create a series each; requests that match no route share "unmatched".
+func Metrics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		method := c.Request.Method
+		metrics.HTTPRequests.Inc(method, route, strconv.Itoa(c.Writer.Status()))
+		metrics.HTTPDuration.Observe(time.Since(start).Seconds(), method, route)
+	}
+}
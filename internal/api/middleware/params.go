@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ValidateUUIDParams rejects a request with 400 when its "id" path parameter,
+// or any parameter ending in "Id" (commentId, attachmentId, ...), is not a
+// UUID. Without it a malformed ID reaches Postgres and fails as a 500.
+func ValidateUUIDParams() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		for _, param := range c.Params {
+			if param.Key != "id" && !strings.HasSuffix(param.Key, "Id") {
+				continue
+			}
+			if !isUUID(param.Value) {
+				c.AbortWithStatusJSON(http.StatusBadRequest, models.NewErrorResponse(
+					models.CodeInvalidInput,
+					param.Key+" must be a valid UUID",
+					[]models.FieldError{{Field: param.Key, Rule: "uuid"}},
+				))
+				return
+			}
+		}
+		c.Next()
+	}
+}
+
+// isUUID accepts only the canonical 36-character form
+func isUUID(s string) bool {
+	if len(s) != 36 {
+		return false
+	}
+	_, err := uuid.Parse(s)
+	return err == nil
+}
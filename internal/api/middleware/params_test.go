@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newParamsRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	api := r.Group("/api", ValidateUUIDParams())
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	api.POST("/tasks/:id/comments", ok)
+	api.DELETE("/tasks/:id/comments/:commentId", ok)
+	api.GET("/tasks/key/:key", ok)
+	return r
+}
+
+func TestValidateUUIDParams(t *testing.T) {
+	const valid = "0b9c7a52-8f0e-4d4e-9a53-2f1c0d3e4b5a"
+	r := newParamsRouter()
+
+	cases := []struct {
+		name, method, path string
+		want               int
+	}{
+		{"non-UUID id", http.MethodPost, "/api/tasks/not-a-uuid/comments", http.StatusBadRequest},
+		{"non-UUID commentId", http.MethodDelete, "/api/tasks/" + valid + "/comments/42", http.StatusBadRequest},
+		{"UUID without hyphens", http.MethodPost, "/api/tasks/0b9c7a528f0e4d4e9a532f1c0d3e4b5a/comments", http.StatusBadRequest},
+		{"valid UUIDs", http.MethodDelete, "/api/tasks/" + valid + "/comments/" + valid, http.StatusOK},
+		{"non-ID parameter", http.MethodGet, "/api/tasks/key/ORA-12", http.StatusOK},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(tc.method, tc.path, nil))
+			if w.Code != tc.want {
+				t.Fatalf("%s %s = %d, want %d (body %s)", tc.method, tc.path, w.Code, tc.want, w.Body.String())
+			}
+		})
+	}
+}
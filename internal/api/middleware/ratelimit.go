@@ -0,0 +1,164 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/db"
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+)
+
+// RateLimiter hands out token buckets keyed by route and caller. Buckets live in
+// Redis when it is available so limits hold across instances, and in memory otherwise.
+type RateLimiter struct {
+	redis *db.RedisDB
+
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket
+}
+
+type tokenBucket struct {
+	tokens   float64
+	lastSeen time.Time
+}
+
+// tokenBucketScript refills and takes one token atomically; returns {allowed, waitMs}
+var tokenBucketScript = redis.NewScript(`
+local capacity = tonumber(ARGV[1])
+local rate = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local ttl = tonumber(ARGV[4])
+
+local bucket = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(bucket[1]) or capacity
+local ts = tonumber(bucket[2]) or now
+tokens = math.min(capacity, tokens + math.max(0, now - ts) * rate)
+
+local allowed = 0
+local wait = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+else
+	wait = math.ceil((1 - tokens) / rate)
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', now)
+redis.call('PEXPIRE', KEYS[1], ttl)
+return {allowed, wait}
+`)
+
+// NewRateLimiter creates a RateLimiter; redisDB may be nil
+func NewRateLimiter(redisDB *db.RedisDB) *RateLimiter {
+	rl := &RateLimiter{
+		redis:   redisDB,
+		buckets: make(map[string]*tokenBucket),
+	}
+	go rl.cleanup()
+	return rl
+}
+
+// RateLimit allows bursts of up to limit requests per window for each caller of
+// a route, refilling continuously. Callers are identified by user ID when the
+// auth middleware ran first, by client IP otherwise. A limit <= 0 disables it.
+func (rl *RateLimiter) RateLimit(limit int, window time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if limit <= 0 || window <= 0 {
+			c.Next()
+			return
+		}
+
+		caller := "ip:" + c.ClientIP()
+		if userID := GetUserID(c); userID != "" {
+			caller = "user:" + userID
+		}
+		key := fmt.Sprintf("ratelimit:%s %s:%s", c.Request.Method, c.FullPath(), caller)
+
+		allowed, retryAfter := rl.allow(c.Request.Context(), key, limit, window)
+		if !allowed {
+			seconds := int(math.Ceil(retryAfter.Seconds()))
+			if seconds < 1 {
+				seconds = 1
+			}
+			slog.WarnContext(c.Request.Context(), "rate limit exceeded", "key", key, "retryAfterSeconds", seconds)
+			c.Header("Retry-After", strconv.Itoa(seconds))
+			c.JSON(http.StatusTooManyRequests, models.NewErrorResponse(models.CodeRateLimited, "Too many requests, please try again later", nil))
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+func (rl *RateLimiter) allow(ctx context.Context, key string, limit int, window time.Duration) (bool, time.Duration) {
+	if rl.redis != nil {
+		allowed, retryAfter, err := rl.allowRedis(ctx, key, limit, window)
+		if err == nil {
+			return allowed, retryAfter
+		}
+		slog.WarnContext(ctx, "rate limit store unavailable, using in-memory limiter", "error", err)
+	}
+	return rl.allowMemory(key, limit, window)
+}
+
+func (rl *RateLimiter) allowRedis(ctx context.Context, key string, limit int, window time.Duration) (bool, time.Duration, error) {
+	ratePerMs := float64(limit) / float64(window.Milliseconds())
+	res, err := tokenBucketScript.Run(ctx, rl.redis.Client, []string{key},
+		limit, ratePerMs, time.Now().UnixMilli(), window.Milliseconds()).Int64Slice()
+	if err != nil {
+		return false, 0, err
+	}
+	if len(res) != 2 {
+		return false, 0, fmt.Errorf("unexpected rate limit script result: %v", res)
+	}
+	return res[0] == 1, time.Duration(res[1]) * time.Millisecond, nil
+}
+
+func (rl *RateLimiter) allowMemory(key string, limit int, window time.Duration) (bool, time.Duration) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	now := time.Now()
+	ratePerSec := float64(limit) / window.Seconds()
+
+	b, ok := rl.buckets[key]
+	if !ok {
+		b = &tokenBucket{tokens: float64(limit), lastSeen: now}
+		rl.buckets[key] = b
+	}
+
+	b.tokens = math.Min(float64(limit), b.tokens+now.Sub(b.lastSeen).Seconds()*ratePerSec)
+	b.lastSeen = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	return false, time.Duration((1 - b.tokens) / ratePerSec * float64(time.Second))
+}
+
+// cleanup drops in-memory buckets idle for an hour; limit windows are expected to be shorter
+func (rl *RateLimiter) cleanup() {
+	ticker := time.NewTicker(10 * time.Minute)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		cutoff := time.Now().Add(-time.Hour)
+		rl.mu.Lock()
+		for key, b := range rl.buckets {
+			if b.lastSeen.Before(cutoff) {
+				delete(rl.buckets, key)
+			}
+		}
+		rl.mu.Unlock()
+	}
+}
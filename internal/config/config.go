@@ -2,8 +2,13 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"strconv"
+	"strings"
+	"time"
+
+	cronlib "github.com/robfig/cron/v3"
 )
 
 type Config struct {
@@ -26,9 +31,111 @@ type Config struct {
 
 	// Frontend URL for email links
 	FrontendURL string
+
+	// Invitation reminders
+	InvitationReminderIntervalHours int // minimum hours between invite (or last reminder) and the next reminder
+	InvitationMaxReminders          int
+	InvitationResendCooldownMinutes int // shared by manual resends and the reminder cron
+
+	// Max pending invitations per target, keyed by invitation type (0 or missing = unlimited)
+	InvitationPendingLimits map[string]int
+
+	// Presence: minutes without activity before a user is shown away, then offline
+	PresenceAwayMinutes    int
+	PresenceOfflineMinutes int
+
+	// File storage: "local" or "s3"
+	StorageDriver    string
+	StorageLocalDir  string
+	StoragePublicURL string // base URL for locally stored files
+	S3Endpoint       string
+	S3Region         string
+	S3Bucket         string
+	S3AccessKey      string
+	S3SecretKey      string
+	S3PublicURL      string
+	AvatarMaxSizeMB  int
+
+	// Task attachment uploads (0 = unlimited)
+	AttachmentMaxSizeMB        int
+	WorkspaceAttachmentQuotaMB int
+
+	// Deepest level of subtask nesting; a top-level task's subtasks are level 1 (0 = unlimited)
+	MaxSubtaskDepth int
+
+	// Rate limits: requests per caller and route within RateLimitWindowSeconds (0 = disabled)
+	RateLimitWindowSeconds int
+	RateLimitAuth          int // login and register, keyed by client IP
+	RateLimitAPI           int // authenticated routes, keyed by user
+	RateLimitBulk          int // bulk task and invitation operations
+
+	// How long granted access checks stay cached in Redis
+	AccessCacheTTLSeconds int
+
+	// Safety-net TTL of cached sprint boards, project stats and member lists
+	ReadCacheTTLSeconds int
+
+	// Window in which repeated notifications of one type on one task are merged (0 = disabled)
+	NotificationCoalesceSeconds int
+
+	// Age in days at which the cleanup job deletes read and unread notifications (0 = keep)
+	NotificationRetentionDays       int
+	NotificationUnreadRetentionDays int
+	NotificationCleanupDryRun       bool // only log what would be deleted
+
+	// Outgoing webhook delivery
+	WebhookWorkers     int
+	WebhookMaxAttempts int
+
+	// Bearer token for GET /health/detailed; the endpoint is off when empty
+	HealthDetailToken string
+
+	// Serve Prometheus metrics on GET /metrics
+	MetricsEnabled bool
+
+	// Minimum level written to the JSON log: debug, info, warn or error
+	LogLevel string
+
+	// Schedule of each cron job, keyed by job name ("" = disabled)
+	CronSchedules map[string]string
+}
+
+// CronJobs lists the scheduler's jobs with their default schedules. A job's
+// schedule is overridden by CRON_<NAME>, e.g. CRON_USER_STATUS, holding a
+// cron expression ("*/5 * * * *"), a duration ("5m") or "off".
+var CronJobs = []struct {
+	Name     string
+	Schedule string
+}{
+	{"daily_reminders", "0 9 * * *"},
+	{"due_today", "0 * * * *"},
+	{"sprint_auto_complete", "0 * * * *"},
+	{"recurring_tasks", "*/15 * * * *"},
+	{"invitation_reminders", "30 * * * *"},
+	{"invitation_expiry", "*/30 * * * *"},
+	{"refresh_token_cleanup", "15 3 * * *"},
+	{"user_status", "*/10 * * * *"},
+	{"notification_cleanup", "0 0 * * 0"},
+	{"sprint_reports", "0 1 * * *"},
+	{"burndown_snapshots", "55 23 * * *"},
 }
 
-func Load() *Config {
+// Load reads the configuration from the environment. It fails on values
+// that would otherwise be ignored at runtime, such as a bad cron schedule.
+func Load() (*Config, error) {
+	cronSchedules, err := loadCronSchedules()
+	if err != nil {
+		return nil, err
+	}
+
+	// Unread notifications are deleted with everything older than their
+	// window, so it must not cut read retention short
+	readDays := getEnvInt("NOTIFICATION_RETENTION_DAYS", 30)
+	unreadDays := getEnvInt("NOTIFICATION_UNREAD_RETENTION_DAYS", 0)
+	if unreadDays > 0 && (readDays <= 0 || unreadDays < readDays) {
+		return nil, fmt.Errorf("NOTIFICATION_UNREAD_RETENTION_DAYS (%d) must not be shorter than NOTIFICATION_RETENTION_DAYS (%d), which must be set", unreadDays, readDays)
+	}
+
 	return &Config{
 		Port:          getEnv("API_PORT", "8080"),
 		Environment:   getEnv("ENVIRONMENT", "development"),
@@ -49,7 +156,87 @@ func Load() *Config {
 
 		// Frontend URL for email links
 		FrontendURL: getEnv("FRONTEND_URL", "http://localhost:3000"),
+
+		// Invitation reminders
+		InvitationReminderIntervalHours: getEnvInt("INVITATION_REMINDER_INTERVAL_HOURS", 72),
+		InvitationMaxReminders:          getEnvInt("INVITATION_MAX_REMINDERS", 2),
+		InvitationResendCooldownMinutes: getEnvInt("INVITATION_RESEND_COOLDOWN_MINUTES", 5),
+
+		InvitationPendingLimits: getEnvIntMap("INVITATION_PENDING_LIMITS", "workspace=200,space=100,folder=100,project=50,team=50,task=20"),
+
+		// Presence
+		PresenceAwayMinutes:    getEnvInt("PRESENCE_AWAY_MINUTES", 30),
+		PresenceOfflineMinutes: getEnvInt("PRESENCE_OFFLINE_MINUTES", 240),
+
+		// File storage
+		StorageDriver:    getEnv("STORAGE_DRIVER", "local"),
+		StorageLocalDir:  getEnv("STORAGE_LOCAL_DIR", "./uploads"),
+		StoragePublicURL: getEnv("STORAGE_PUBLIC_URL", "http://localhost:8080/uploads"),
+		S3Endpoint:       getEnv("S3_ENDPOINT", ""),
+		S3Region:         getEnv("S3_REGION", "us-east-1"),
+		S3Bucket:         getEnv("S3_BUCKET", ""),
+		S3AccessKey:      getEnv("S3_ACCESS_KEY", ""),
+		S3SecretKey:      getEnv("S3_SECRET_KEY", ""),
+		S3PublicURL:      getEnv("S3_PUBLIC_URL", ""),
+		AvatarMaxSizeMB:  getEnvInt("AVATAR_MAX_SIZE_MB", 5),
+
+		AttachmentMaxSizeMB:        getEnvInt("ATTACHMENT_MAX_SIZE_MB", 25),
+		WorkspaceAttachmentQuotaMB: getEnvInt("WORKSPACE_ATTACHMENT_QUOTA_MB", 5120),
+
+		MaxSubtaskDepth: getEnvInt("MAX_SUBTASK_DEPTH", 5),
+
+		// Rate limiting
+		RateLimitWindowSeconds: getEnvInt("RATE_LIMIT_WINDOW_SECONDS", 60),
+		RateLimitAuth:          getEnvInt("RATE_LIMIT_AUTH", 10),
+		RateLimitAPI:           getEnvInt("RATE_LIMIT_API", 300),
+		RateLimitBulk:          getEnvInt("RATE_LIMIT_BULK", 10),
+
+		AccessCacheTTLSeconds: getEnvInt("ACCESS_CACHE_TTL_SECONDS", 30),
+		ReadCacheTTLSeconds:   getEnvInt("READ_CACHE_TTL_SECONDS", 60),
+
+		NotificationCoalesceSeconds: getEnvInt("NOTIFICATION_COALESCE_SECONDS", 60),
+
+		NotificationRetentionDays:       readDays,
+		NotificationUnreadRetentionDays: unreadDays,
+		NotificationCleanupDryRun:       getEnvBool("NOTIFICATION_CLEANUP_DRY_RUN", false),
+
+		WebhookWorkers:     getEnvInt("WEBHOOK_WORKERS", 4),
+		WebhookMaxAttempts: getEnvInt("WEBHOOK_MAX_ATTEMPTS", 5),
+
+		HealthDetailToken: getEnv("HEALTH_DETAIL_TOKEN", ""),
+		MetricsEnabled:    getEnvBool("METRICS_ENABLED", false),
+		LogLevel:          getEnv("LOG_LEVEL", "info"),
+
+		CronSchedules: cronSchedules,
+	}, nil
+}
+
+// loadCronSchedules reads each job's schedule and checks that it parses.
+// Durations become "@every" schedules.
+func loadCronSchedules() (map[string]string, error) {
+	schedules := make(map[string]string, len(CronJobs))
+	for _, job := range CronJobs {
+		key := "CRON_" + strings.ToUpper(job.Name)
+		spec := strings.TrimSpace(getEnv(key, job.Schedule))
+
+		switch strings.ToLower(spec) {
+		case "off", "disabled", "false", "0":
+			schedules[job.Name] = ""
+			continue
+		}
+
+		if d, err := time.ParseDuration(spec); err == nil {
+			if d <= 0 {
+				return nil, fmt.Errorf("%s: duration must be positive, got %q", key, spec)
+			}
+			spec = "@every " + d.String()
+		}
+		if _, err := cronlib.ParseStandard(spec); err != nil {
+			return nil, fmt.Errorf("%s: invalid schedule %q: %w", key, spec, err)
+		}
+		schedules[job.Name] = spec
 	}
+	return schedules, nil
 }
 
 func getEnvBool(key string, defaultValue bool) bool {
@@ -74,3 +261,18 @@ func getEnvInt(key string, defaultValue int) int {
 	}
 	return defaultValue
 }
+
+// getEnvIntMap parses "key=value,key=value" pairs, skipping malformed entries
+func getEnvIntMap(key, defaultValue string) map[string]int {
+	result := map[string]int{}
+	for _, pair := range strings.Split(getEnv(key, defaultValue), ",") {
+		k, v, ok := strings.Cut(pair, "=")
+		if !ok {
+			continue
+		}
+		if intValue, err := strconv.Atoi(strings.TrimSpace(v)); err == nil {
+			result[strings.TrimSpace(k)] = intValue
+		}
+	}
+	return result
+}
@@ -5,6 +5,7 @@ import (
 	"log"
 	"time"
 
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/config"
 	"github.com/Marga-Ghale/ora-scrum-backend/internal/notification"
 	"github.com/Marga-Ghale/ora-scrum-backend/internal/repository"
 	"github.com/Marga-Ghale/ora-scrum-backend/internal/service"
@@ -22,6 +23,25 @@ type Scheduler struct {
 	userRepo           repository.UserRepository
 	notificationRepo   repository.NotificationRepository
 	sprintAnalyticsSvc service.SprintAnalyticsService
+
+	// Inactivity before users are marked away / offline
+	awayAfter    time.Duration
+	offlineAfter time.Duration
+
+	// Schedule of each job by name; a missing or empty one is disabled
+	schedules map[string]string
+
+	notificationCleanup NotificationCleanup
+
+	// Users with a live WebSocket connection, whose presence the hub owns
+	connectedUsers func() []string
+}
+
+// NotificationCleanup configures the notification_cleanup job
+type NotificationCleanup struct {
+	ReadAfter   time.Duration // age at which read notifications are deleted (0 = keep)
+	UnreadAfter time.Duration // age at which unread ones are deleted too (0 = keep)
+	DryRun      bool          // log the counts without deleting
 }
 
 // NewSchedulerWithRepos creates a scheduler with repositories
@@ -34,6 +54,9 @@ func NewSchedulerWithRepos(
 	userRepo repository.UserRepository,
 	notificationRepo repository.NotificationRepository,
 	sprintAnalyticsSvc service.SprintAnalyticsService,
+	awayAfter, offlineAfter time.Duration,
+	schedules map[string]string,
+	notificationCleanup NotificationCleanup,
 ) *Scheduler {
 	return &Scheduler{
 		cronJob:            cronlib.New(),
@@ -45,48 +68,91 @@ func NewSchedulerWithRepos(
 		userRepo:           userRepo,
 		notificationRepo:   notificationRepo,
 		sprintAnalyticsSvc: sprintAnalyticsSvc,
+		awayAfter:          awayAfter,
+		offlineAfter:       offlineAfter,
+		schedules:          schedules,
+
+		notificationCleanup: notificationCleanup,
 	}
 }
 
-// Start runs the cron scheduler
-func (s *Scheduler) Start() {
-	// Daily 9 AM
-	s.cronJob.AddFunc("0 9 * * *", func() {
-		log.Println("[Cron] Daily checks starting...")
-		s.checkDueDateReminders()
-		s.checkOverdueTasks()
-		s.checkSprintDeadlines()
-	})
-
-	// Hourly
-	s.cronJob.AddFunc("0 * * * *", func() {
-		log.Println("[Cron] Hourly checks starting...")
-		s.checkTasksDueToday()
-		s.autoCompleteExpiredSprints()
-	})
-
-	// Every 30 minutes: inactive user update
-	s.cronJob.AddFunc("*/30 * * * *", func() {
-		log.Println("[Cron] Updating user status...")
-		s.updateInactiveUserStatus()
-	})
-
-	// Weekly Sunday midnight: clean notifications
-	s.cronJob.AddFunc("0 0 * * 0", func() {
-		log.Println("[Cron] Cleaning up old notifications...")
-		s.cleanupOldNotifications()
-	})
+// jobs maps each job name in config.CronJobs to what it runs
+func (s *Scheduler) jobs() map[string]func() {
+	return map[string]func(){
+		"daily_reminders": func() {
+			log.Println("[Cron] Daily checks starting...")
+			s.checkDueDateReminders()
+			s.checkOverdueTasks()
+			s.checkSprintDeadlines()
+		},
+		"due_today":            s.checkTasksDueToday,
+		"sprint_auto_complete": s.autoCompleteExpiredSprints,
+		// Spawn tasks from recurring templates
+		"recurring_tasks": s.instantiateRecurringTasks,
+		// Remind invitees who haven't responded yet
+		"invitation_reminders": s.sendInvitationReminders,
+		// Expire pending invitations past their deadline
+		"invitation_expiry": s.expireInvitations,
+		// Purge refresh tokens past their expiry
+		"refresh_token_cleanup": s.cleanupExpiredRefreshTokens,
+		// Inactive users go away, long-inactive users go offline
+		"user_status": func() {
+			log.Println("[Cron] Updating user status...")
+			s.updateInactiveUserStatus()
+			s.updateOfflineUserStatus()
+		},
+		"notification_cleanup": func() {
+			log.Println("[Cron] Cleaning up old notifications...")
+			s.cleanupOldNotifications()
+		},
+		// Reports are generated on demand; caching them nightly helps dashboards
+		"sprint_reports": func() {
+			log.Println("[Cron] Generating sprint reports...")
+			s.generateActiveSprintReports()
+		},
+		// End-of-day burndown for active sprints
+		"burndown_snapshots": func() {
+			log.Println("[Cron] Recording burndown snapshots...")
+			s.recordBurndownSnapshots()
+		},
+	}
+}
 
-	// Optional: Daily at 1 AM - generate sprint reports (cached for performance)
-	s.cronJob.AddFunc("0 1 * * *", func() {
-		log.Println("[Cron] Generating sprint reports...")
-		s.generateActiveSprintReports()
-	})
+// Start schedules every enabled job and runs the cron scheduler. Schedules
+// are validated by config.Load, so a job that fails to schedule here is a bug
+// and is logged as such.
+func (s *Scheduler) Start() {
+	jobs := s.jobs()
+	for _, job := range config.CronJobs {
+		run, ok := jobs[job.Name]
+		if !ok {
+			log.Printf("[Cron] No handler for job %s", job.Name)
+			continue
+		}
+		spec := s.schedules[job.Name]
+		if spec == "" {
+			log.Printf("[Cron] %s disabled", job.Name)
+			continue
+		}
+		id, err := s.cronJob.AddFunc(spec, run)
+		if err != nil {
+			log.Printf("[Cron] Failed to schedule %s (%q): %v", job.Name, spec, err)
+			continue
+		}
+		next := s.cronJob.Entry(id).Schedule.Next(time.Now())
+		log.Printf("[Cron] %s scheduled %q, next run %s", job.Name, spec, next.Format(time.RFC3339))
+	}
 
 	s.cronJob.Start()
 	log.Println("[Cron] Scheduler started")
 }
 
+// SetConnectedUsers installs the lookup of users with a live connection, who
+// the user_status job leaves alone
+func (s *Scheduler) SetConnectedUsers(connected func() []string) {
+	s.connectedUsers = connected
+}
+
 func (s *Scheduler) Stop() {
 	s.cronJob.Stop()
 	log.Println("[Cron] Scheduler stopped")
@@ -94,6 +160,22 @@ func (s *Scheduler) Stop() {
 
 // ------------------- TASK METHODS -------------------
 
+// instantiateRecurringTasks creates tasks from recurring templates that are due
+func (s *Scheduler) instantiateRecurringTasks() {
+	if s.services == nil || s.services.RecurringTask == nil {
+		return
+	}
+
+	created, err := s.services.RecurringTask.InstantiateDueTasks(context.Background())
+	if err != nil {
+		log.Printf("[Cron] Error instantiating recurring tasks: %v", err)
+		return
+	}
+	if created > 0 {
+		log.Printf("[Cron] Created %d tasks from recurring templates", created)
+	}
+}
+
 // checkDueDateReminders sends reminders for tasks due in 3 days
 func (s *Scheduler) checkDueDateReminders() {
 	ctx := context.Background()
@@ -203,8 +285,14 @@ func (s *Scheduler) checkTasksDueToday() {
 	log.Printf("[Cron] Hourly due today reminders sent: %d", sent)
 }
 
-// autoCompleteExpiredSprints marks expired sprints as completed and records velocity
+// autoCompleteExpiredSprints completes active sprints whose end date has
+// passed. Unfinished tasks go to the backlog or the next planned sprint, as
+// the project's carry-over setting says, and the project lead gets a summary.
 func (s *Scheduler) autoCompleteExpiredSprints() {
+	if s.services == nil || s.services.Sprint == nil {
+		return
+	}
+
 	ctx := context.Background()
 	sprints, err := s.sprintRepo.FindExpiredSprints(ctx)
 	if err != nil {
@@ -213,55 +301,138 @@ func (s *Scheduler) autoCompleteExpiredSprints() {
 	}
 
 	for _, sp := range sprints {
-		totalPoints, _ := s.taskRepo.GetSprintVelocity(ctx, sp.ID)
-		completedPoints, _ := s.taskRepo.GetCompletedStoryPoints(ctx, sp.ID)
+		result, err := s.services.Sprint.CompleteExpiredSprint(ctx, sp.ID)
+		if err != nil {
+			log.Printf("[Cron] Error completing sprint %s: %v", sp.ID, err)
+			continue
+		}
+		if result == nil {
+			continue // completed or rescheduled since it was fetched
+		}
 
-		// ✅ Record velocity history BEFORE completing sprint
+		// Only the run that completed the sprint records its velocity, the
+		// same way a manual completion does
 		if s.sprintAnalyticsSvc != nil {
 			if err := s.sprintAnalyticsSvc.RecordSprintVelocity(ctx, sp.ID); err != nil {
 				log.Printf("[Cron] Failed to record velocity for sprint %s: %v", sp.ID, err)
-			} else {
-				log.Printf("[Cron] Recorded velocity for sprint %s: %d/%d points", sp.Name, completedPoints, totalPoints)
 			}
 		}
 
-		// Update sprint status to completed
-		sp.Status = "completed"
-		sp.EndDate = time.Now()
-		if err := s.sprintRepo.Update(ctx, sp); err != nil {
-			log.Printf("[Cron] Error completing sprint %s: %v", sp.ID, err)
-			continue
+		leadID := ""
+		if project, err := s.projectRepo.FindByID(ctx, sp.ProjectID); err == nil && project != nil && project.LeadID != nil {
+			leadID = *project.LeadID
+		}
+		if leadID == "" {
+			leadID = sp.CreatedBy
+		}
+		if err := s.notifSvc.SendSprintAutoCompleted(ctx, leadID, sp.Name, sp.ID, sp.ProjectID,
+			result.CompletedTasks, result.IncompleteTasks, result.CompletedPoints, result.IncompletePoints, result.TasksMovedTo); err != nil {
+			log.Printf("[Cron] Failed to notify lead of sprint %s: %v", sp.ID, err)
 		}
 
-		// Notify project members
+		// Everyone else gets the usual sprint completed notice
 		memberIDs, _ := s.projectRepo.FindMemberUserIDs(ctx, sp.ProjectID)
-		if len(memberIDs) > 0 {
-			s.notifSvc.SendSprintCompletedToMembers(ctx, memberIDs, sp.Name, sp.ID, sp.ProjectID, completedPoints, totalPoints)
+		others := make([]string, 0, len(memberIDs))
+		for _, id := range memberIDs {
+			if id != leadID {
+				others = append(others, id)
+			}
 		}
-		log.Printf("[Cron] Auto-completed sprint %s (%d/%d story points done)", sp.Name, completedPoints, totalPoints)
+		if len(others) > 0 {
+			totalPoints := result.CompletedPoints + result.IncompletePoints
+			s.notifSvc.SendSprintCompletedToMembers(ctx, others, sp.Name, sp.ID, sp.ProjectID, result.CompletedPoints, totalPoints)
+		}
+		log.Printf("[Cron] Auto-completed sprint %s (%d/%d tasks done, %d moved to %s)",
+			sp.Name, result.CompletedTasks, result.CompletedTasks+result.IncompleteTasks, result.IncompleteTasks, result.TasksMovedTo)
 	}
 }
 
-// cleanupOldNotifications deletes read notifications older than 30 days
+// cleanupOldNotifications deletes read notifications past their retention
+// and, when a window is set, unread ones past theirs. In dry-run mode it
+// only logs how many would go.
 func (s *Scheduler) cleanupOldNotifications() {
 	ctx := context.Background()
-	threshold := time.Now().AddDate(0, 0, -30)
-	deleted, err := s.notificationRepo.DeleteOlderThan(ctx, threshold, true)
-	if err != nil {
-		log.Printf("[Cron] Error cleaning notifications: %v", err)
+	cfg := s.notificationCleanup
+	now := time.Now()
+
+	if cfg.DryRun {
+		var read, unread int
+		if cfg.ReadAfter > 0 {
+			n, _, err := s.notificationRepo.CountOlderThan(ctx, now.Add(-cfg.ReadAfter))
+			if err != nil {
+				log.Printf("[Cron] Error counting old notifications: %v", err)
+				return
+			}
+			read = n
+		}
+		if cfg.UnreadAfter > 0 {
+			_, n, err := s.notificationRepo.CountOlderThan(ctx, now.Add(-cfg.UnreadAfter))
+			if err != nil {
+				log.Printf("[Cron] Error counting old notifications: %v", err)
+				return
+			}
+			unread = n
+		}
+		log.Printf("[Cron] Notification cleanup dry run: would delete %d read and %d unread", read, unread)
 		return
 	}
-	log.Printf("[Cron] Old notifications deleted: %d", deleted)
+
+	var read, unread int
+	if cfg.ReadAfter > 0 {
+		deleted, err := s.notificationRepo.DeleteOlderThan(ctx, now.Add(-cfg.ReadAfter), true)
+		if err != nil {
+			log.Printf("[Cron] Error cleaning notifications: %v", err)
+			return
+		}
+		read = deleted
+	}
+	// The unread window is never shorter than the read one, so only unread
+	// notifications are left this old
+	if cfg.UnreadAfter > 0 {
+		deleted, err := s.notificationRepo.DeleteOlderThan(ctx, now.Add(-cfg.UnreadAfter), false)
+		if err != nil {
+			log.Printf("[Cron] Error cleaning unread notifications: %v", err)
+			return
+		}
+		unread = deleted
+	}
+	log.Printf("[Cron] Old notifications deleted: %d read, %d unread", read, unread)
 }
 
 // updateInactiveUserStatus sets inactive users to away
 func (s *Scheduler) updateInactiveUserStatus() {
-	ctx := context.Background()
-	if err := s.userRepo.UpdateStatusForInactive(ctx, 30*time.Minute); err != nil {
+	if s.services == nil || s.services.User == nil {
+		return
+	}
+
+	away, err := s.services.User.MarkAway(context.Background(), s.awayAfter, s.connectedUserIDs())
+	if err != nil {
 		log.Printf("[Cron] Error updating inactive users: %v", err)
 		return
 	}
-	log.Println("[Cron] User status update complete")
+	log.Printf("[Cron] User status update complete, %d marked away", away)
+}
+
+// connectedUserIDs lists users the hub currently has connections for
+func (s *Scheduler) connectedUserIDs() []string {
+	if s.connectedUsers == nil {
+		return nil
+	}
+	return s.connectedUsers()
+}
+
+// updateOfflineUserStatus sets long-inactive users to offline
+func (s *Scheduler) updateOfflineUserStatus() {
+	if s.services == nil || s.services.User == nil {
+		return
+	}
+
+	offline, err := s.services.User.MarkOffline(context.Background(), s.offlineAfter, s.connectedUserIDs())
+	if err != nil {
+		log.Printf("[Cron] Error updating offline users: %v", err)
+		return
+	}
+	log.Printf("[Cron] Offline user status update complete, %d marked offline", offline)
 }
 
 // generateActiveSprintReports generates cached reports for active sprints
@@ -290,4 +461,77 @@ func (s *Scheduler) generateActiveSprintReports() {
 	}
 	
 	log.Printf("[Cron] Generated %d sprint reports", generated)
-}
\ No newline at end of file
+}
+
+// recordBurndownSnapshots stores remaining story points for every active sprint
+func (s *Scheduler) recordBurndownSnapshots() {
+	if s.sprintAnalyticsSvc == nil {
+		return
+	}
+
+	ctx := context.Background()
+	sprints, err := s.sprintRepo.FindActiveSprints(ctx)
+	if err != nil {
+		log.Printf("[Cron] Error fetching active sprints: %v", err)
+		return
+	}
+
+	recorded := 0
+	for _, sprint := range sprints {
+		if err := s.sprintAnalyticsSvc.RecordBurndownSnapshot(ctx, sprint.ID); err != nil {
+			log.Printf("[Cron] Failed to record burndown for sprint %s: %v", sprint.ID, err)
+			continue
+		}
+		recorded++
+	}
+
+	log.Printf("[Cron] Recorded burndown snapshots for %d sprints", recorded)
+}
+
+// ------------------- AUTH METHODS -------------------
+
+// cleanupExpiredRefreshTokens deletes expired refresh tokens, including rotated ones
+func (s *Scheduler) cleanupExpiredRefreshTokens() {
+	deleted, err := s.userRepo.DeleteExpiredRefreshTokens(context.Background())
+	if err != nil {
+		log.Printf("[Cron] Error deleting expired refresh tokens: %v", err)
+		return
+	}
+	if deleted > 0 {
+		log.Printf("[Cron] Deleted %d expired refresh tokens", deleted)
+	}
+}
+
+// ------------------- INVITATION METHODS -------------------
+
+// expireInvitations marks overdue pending invitations as expired, keeping their history
+func (s *Scheduler) expireInvitations() {
+	if s.services == nil || s.services.Invitation == nil {
+		return
+	}
+
+	expired, err := s.services.Invitation.ExpirePendingInvitations(context.Background())
+	if err != nil {
+		log.Printf("[Cron] Error expiring invitations: %v", err)
+		return
+	}
+	if expired > 0 {
+		log.Printf("[Cron] Marked %d invitations as expired", expired)
+	}
+}
+
+// sendInvitationReminders re-sends pending invitation emails per the configured cadence
+func (s *Scheduler) sendInvitationReminders() {
+	if s.services == nil || s.services.Invitation == nil {
+		return
+	}
+
+	sent, err := s.services.Invitation.SendPendingReminders(context.Background())
+	if err != nil {
+		log.Printf("[Cron] Error sending invitation reminders: %v", err)
+		return
+	}
+	if sent > 0 {
+		log.Printf("[Cron] Invitation reminders sent: %d", sent)
+	}
+}
@@ -0,0 +1,22 @@
+-- ============================================
+-- Recurring task templates
+-- ============================================
+CREATE TABLE IF NOT EXISTS recurring_tasks (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    title VARCHAR(500) NOT NULL,
+    description TEXT,
+    priority VARCHAR(50) NOT NULL DEFAULT 'medium',
+    type VARCHAR(50),
+    assignee_ids TEXT[] DEFAULT '{}',
+    cadence VARCHAR(100) NOT NULL, -- 'daily', 'weekly', 'monthly' or a cron expression
+    next_run_at TIMESTAMPTZ NOT NULL,
+    last_run_at TIMESTAMPTZ,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_recurring_tasks_project ON recurring_tasks(project_id);
+CREATE INDEX IF NOT EXISTS idx_recurring_tasks_next_run ON recurring_tasks(next_run_at) WHERE active = TRUE;
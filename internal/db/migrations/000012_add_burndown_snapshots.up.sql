@@ -0,0 +1,15 @@
+-- ============================================
+-- Daily burndown snapshots per sprint
+-- ============================================
+CREATE TABLE IF NOT EXISTS burndown_snapshots (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    sprint_id UUID NOT NULL REFERENCES sprints(id) ON DELETE CASCADE,
+    snapshot_date DATE NOT NULL,
+    total_points INTEGER NOT NULL DEFAULT 0,
+    completed_points INTEGER NOT NULL DEFAULT 0,
+    remaining_points INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE(sprint_id, snapshot_date)
+);
+
+CREATE INDEX IF NOT EXISTS idx_burndown_snapshots_sprint ON burndown_snapshots(sprint_id);
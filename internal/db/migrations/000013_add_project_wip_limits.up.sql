@@ -0,0 +1,14 @@
+-- ============================================
+-- Work-in-progress limits per board column
+-- ============================================
+CREATE TABLE IF NOT EXISTS project_wip_limits (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    status VARCHAR(50) NOT NULL,
+    max_tasks INTEGER NOT NULL CHECK (max_tasks > 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE(project_id, status)
+);
+
+CREATE INDEX IF NOT EXISTS idx_project_wip_limits_project ON project_wip_limits(project_id);
@@ -0,0 +1,17 @@
+-- ============================================
+-- Per-project task status workflows
+-- ============================================
+CREATE TABLE IF NOT EXISTS project_statuses (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    key VARCHAR(50) NOT NULL,          -- value stored in tasks.status
+    name VARCHAR(100) NOT NULL,
+    color VARCHAR(20),
+    position INTEGER NOT NULL DEFAULT 0,
+    allowed_transitions TEXT[] DEFAULT '{}', -- empty means any status may follow
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE(project_id, key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_project_statuses_project ON project_statuses(project_id, position);
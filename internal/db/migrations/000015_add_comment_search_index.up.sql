@@ -0,0 +1,6 @@
+-- ============================================
+-- Full-text search over comment content
+-- ============================================
+-- Queries must use the same to_tsvector('english', content) expression to hit this index
+CREATE INDEX IF NOT EXISTS idx_comments_content_fts
+    ON comments USING GIN (to_tsvector('english', content));
@@ -0,0 +1,47 @@
+-- ============================================
+-- Task <-> label join table
+-- ============================================
+CREATE TABLE IF NOT EXISTS task_labels (
+    task_id UUID NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    label_id UUID NOT NULL REFERENCES labels(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (task_id, label_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_labels_label ON task_labels(label_id);
+
+-- Legacy names without a matching project label become labels of their own,
+-- so the backfill below keeps them instead of dropping them. Values shaped
+-- like a UUID are dangling label IDs and are not turned into names.
+INSERT INTO labels (name, color, project_id)
+SELECT DISTINCT ON (t.project_id, LOWER(legacy.value)) legacy.value, '#6B7280', t.project_id
+FROM tasks t
+CROSS JOIN LATERAL unnest(t.label_ids) AS legacy(value)
+WHERE btrim(legacy.value) <> ''
+  AND legacy.value !~* '^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$'
+  AND NOT EXISTS (
+      SELECT 1 FROM labels l
+      WHERE l.project_id = t.project_id AND LOWER(l.name) = LOWER(legacy.value)
+  )
+ORDER BY t.project_id, LOWER(legacy.value), legacy.value
+ON CONFLICT (project_id, name) DO NOTHING;
+
+-- Backfill from the legacy tasks.label_ids array, which holds either label
+-- IDs or label names depending on the client that wrote it
+INSERT INTO task_labels (task_id, label_id)
+SELECT DISTINCT t.id, l.id
+FROM tasks t
+CROSS JOIN LATERAL unnest(t.label_ids) AS legacy(value)
+JOIN labels l
+  ON l.project_id = t.project_id
+ AND (l.id::text = legacy.value OR LOWER(l.name) = LOWER(legacy.value))
+ON CONFLICT DO NOTHING;
+
+-- Normalise the legacy array to label IDs so both stores agree
+UPDATE tasks t
+SET label_ids = COALESCE((
+    SELECT array_agg(tl.label_id::text ORDER BY tl.created_at)
+    FROM task_labels tl
+    WHERE tl.task_id = t.id
+), '{}')
+WHERE cardinality(t.label_ids) > 0;
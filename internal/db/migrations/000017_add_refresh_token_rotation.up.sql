@@ -0,0 +1,5 @@
+-- ============================================
+-- Refresh token rotation: rotated tokens are kept (marked used) until they
+-- expire so a replayed token can be detected
+-- ============================================
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS used_at TIMESTAMPTZ;
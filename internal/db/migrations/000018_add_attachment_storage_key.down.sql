@@ -0,0 +1 @@
+ALTER TABLE task_attachments DROP COLUMN IF EXISTS storage_key;
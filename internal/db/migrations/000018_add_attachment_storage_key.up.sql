@@ -0,0 +1,5 @@
+-- ============================================
+-- Attachments uploaded through the API keep the key of the stored object;
+-- NULL means an external link. Only stored files count towards quotas.
+-- ============================================
+ALTER TABLE task_attachments ADD COLUMN IF NOT EXISTS storage_key TEXT;
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_checklist_items_checklist_position;
+DROP INDEX IF EXISTS idx_checklists_task_position;
+
+ALTER TABLE checklist_items ALTER COLUMN position DROP NOT NULL;
+ALTER TABLE checklists ALTER COLUMN position DROP NOT NULL;
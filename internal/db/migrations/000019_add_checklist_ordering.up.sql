@@ -0,0 +1,24 @@
+-- ============================================
+-- Checklists and their items are ordered by position. Re-space existing
+-- positions by 1024 so items can be moved between neighbours without
+-- renumbering the whole list.
+-- ============================================
+UPDATE checklists c SET position = ordered.rn * 1024
+FROM (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY task_id ORDER BY position ASC, created_at ASC) AS rn
+    FROM checklists
+) ordered
+WHERE c.id = ordered.id;
+
+UPDATE checklist_items i SET position = ordered.rn * 1024
+FROM (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY checklist_id ORDER BY position ASC, created_at ASC) AS rn
+    FROM checklist_items
+) ordered
+WHERE i.id = ordered.id;
+
+ALTER TABLE checklists ALTER COLUMN position SET NOT NULL;
+ALTER TABLE checklist_items ALTER COLUMN position SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_checklists_task_position ON checklists(task_id, position);
+CREATE INDEX IF NOT EXISTS idx_checklist_items_checklist_position ON checklist_items(checklist_id, position);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_comments_parent_comment_id;
+ALTER TABLE comments DROP COLUMN IF EXISTS parent_comment_id;
@@ -0,0 +1,17 @@
+-- ============================================
+-- Workspace project templates: statuses, labels and seed tasks
+-- captured from a project and replayed into new ones
+-- ============================================
+CREATE TABLE IF NOT EXISTS template_definitions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    workspace_id UUID NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    definition JSONB NOT NULL DEFAULT '{}',
+    source_project_id UUID REFERENCES projects(id) ON DELETE SET NULL,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_template_definitions_workspace ON template_definitions(workspace_id, name);
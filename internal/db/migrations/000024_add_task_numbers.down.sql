@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_tasks_project_number;
+ALTER TABLE tasks DROP COLUMN IF EXISTS number;
+ALTER TABLE projects DROP COLUMN IF EXISTS task_seq;
@@ -0,0 +1,17 @@
+-- ============================================
+-- Per-project task numbers, giving tasks human keys like "PROJ-123"
+-- ============================================
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS task_seq INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS number INTEGER;
+
+-- Number existing tasks in creation order
+UPDATE tasks t SET number = ordered.rn
+FROM (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY project_id ORDER BY created_at, id) AS rn
+    FROM tasks
+) ordered
+WHERE t.id = ordered.id AND t.number IS NULL;
+
+UPDATE projects p SET task_seq = COALESCE((SELECT MAX(number) FROM tasks WHERE project_id = p.id), 0);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tasks_project_number ON tasks(project_id, number);
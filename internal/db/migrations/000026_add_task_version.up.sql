@@ -0,0 +1,4 @@
+-- ============================================
+-- Row version for optimistic concurrency on task updates
+-- ============================================
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_notifications_coalesce_key;
+ALTER TABLE notifications DROP COLUMN IF EXISTS event_count;
+ALTER TABLE notifications DROP COLUMN IF EXISTS coalesce_key;
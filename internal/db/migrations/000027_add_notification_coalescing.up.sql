@@ -0,0 +1,8 @@
+-- ============================================
+-- Coalescing of repeated task notifications within a time window
+-- ============================================
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS coalesce_key VARCHAR(255);
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS event_count INTEGER NOT NULL DEFAULT 1;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_notifications_coalesce_key
+    ON notifications(coalesce_key) WHERE coalesce_key IS NOT NULL;
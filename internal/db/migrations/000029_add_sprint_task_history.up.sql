@@ -0,0 +1,15 @@
+-- ============================================
+-- Sprint membership history: every time a task enters or leaves a sprint.
+-- Lets the sprint report tell committed work from work added mid-sprint.
+-- ============================================
+CREATE TABLE IF NOT EXISTS sprint_task_history (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    sprint_id UUID NOT NULL REFERENCES sprints(id) ON DELETE CASCADE,
+    task_id UUID NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    action VARCHAR(20) NOT NULL, -- 'added', 'removed' or 'carried_over'
+    story_points INTEGER NOT NULL DEFAULT 0,
+    changed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    changed_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_sprint_task_history_sprint ON sprint_task_history(sprint_id, changed_at);
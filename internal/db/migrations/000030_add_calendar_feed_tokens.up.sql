@@ -0,0 +1,11 @@
+-- ============================================
+-- Calendar feed tokens. Calendar clients cannot send an Authorization
+-- header, so each user gets one revocable token for the iCalendar feed URL.
+-- Only the SHA-256 hash of the token is stored.
+-- ============================================
+CREATE TABLE IF NOT EXISTS calendar_feed_tokens (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    token_hash CHAR(64) NOT NULL UNIQUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_used_at TIMESTAMPTZ
+);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS scm_task_references;
+DROP TABLE IF EXISTS project_scm_settings;
+DROP TABLE IF EXISTS workspace_integration_tokens;
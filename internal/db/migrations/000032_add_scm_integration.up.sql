@@ -0,0 +1,30 @@
+-- ============================================
+-- Source control integration. A workspace has one integration token that
+-- authenticates commit/branch events from its repositories; only the
+-- SHA-256 hash is stored. Events act on behalf of the admin who created it.
+-- ============================================
+CREATE TABLE IF NOT EXISTS workspace_integration_tokens (
+    workspace_id UUID PRIMARY KEY REFERENCES workspaces(id) ON DELETE CASCADE,
+    token_hash CHAR(64) NOT NULL UNIQUE,
+    created_by UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_used_at TIMESTAMPTZ
+);
+
+-- Per-project handling of "fixes KEY-123" style magic words; projects
+-- without a row use the defaults
+CREATE TABLE IF NOT EXISTS project_scm_settings (
+    project_id UUID PRIMARY KEY REFERENCES projects(id) ON DELETE CASCADE,
+    auto_transition BOOLEAN NOT NULL DEFAULT TRUE,
+    done_status VARCHAR(50) NOT NULL DEFAULT 'done',
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Commits and branches already linked to a task, so a commit pushed to
+-- several branches is only commented on once
+CREATE TABLE IF NOT EXISTS scm_task_references (
+    task_id UUID NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    ref VARCHAR(255) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (task_id, ref)
+);
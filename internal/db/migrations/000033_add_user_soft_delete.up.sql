@@ -0,0 +1,8 @@
+-- ============================================
+-- Soft-deleted users. Deleting an account sets deleted_at and scrubs the
+-- row instead of removing it, so comments, activity and other history that
+-- reference the user keep pointing at a row (shown as "Deleted user").
+-- ============================================
+ALTER TABLE users ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_users_active ON users(id) WHERE deleted_at IS NULL;
@@ -0,0 +1,12 @@
+-- ============================================
+-- Per-project task behaviour. parent_completion decides what happens when
+-- every subtask of a task is done: nothing ('off'), a notification
+-- suggesting to complete the parent ('suggest') or completing it ('auto').
+-- Projects without a row use 'off'.
+-- ============================================
+CREATE TABLE IF NOT EXISTS project_task_settings (
+    project_id UUID PRIMARY KEY REFERENCES projects(id) ON DELETE CASCADE,
+    parent_completion VARCHAR(20) NOT NULL DEFAULT 'off'
+        CHECK (parent_completion IN ('off', 'suggest', 'auto')),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
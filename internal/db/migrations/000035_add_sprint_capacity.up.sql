@@ -0,0 +1,12 @@
+-- ============================================
+-- Hours each member has available for a sprint, for capacity planning.
+-- Members without a row have no capacity set.
+-- ============================================
+CREATE TABLE IF NOT EXISTS sprint_capacity (
+    sprint_id UUID NOT NULL REFERENCES sprints(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    hours_available DECIMAL(10,2) NOT NULL CHECK (hours_available >= 0),
+    updated_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (sprint_id, user_id)
+);
@@ -0,0 +1 @@
+ALTER TABLE project_task_settings DROP COLUMN IF EXISTS sprint_carry_over;
@@ -0,0 +1,8 @@
+-- ============================================
+-- Where the unfinished tasks of a sprint that is closed automatically after
+-- its end date go: the next planned sprint, or the backlog when there is
+-- none ('next_sprint'), or always the backlog ('backlog').
+-- ============================================
+ALTER TABLE project_task_settings
+    ADD COLUMN IF NOT EXISTS sprint_carry_over VARCHAR(20) NOT NULL DEFAULT 'next_sprint'
+        CHECK (sprint_carry_over IN ('backlog', 'next_sprint'));
@@ -0,0 +1,6 @@
+-- ============================================
+-- Full-text search over chat message content
+-- ============================================
+-- Queries must use the same to_tsvector('english', content) expression to hit this index
+CREATE INDEX IF NOT EXISTS idx_chat_messages_content_fts
+    ON chat_messages USING GIN (to_tsvector('english', content));
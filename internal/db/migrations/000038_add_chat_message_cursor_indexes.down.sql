@@ -0,0 +1,5 @@
+CREATE INDEX IF NOT EXISTS idx_chat_messages_channel_created ON chat_messages(channel_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_chat_messages_parent ON chat_messages(parent_id);
+
+DROP INDEX IF EXISTS idx_chat_messages_parent_cursor;
+DROP INDEX IF EXISTS idx_chat_messages_channel_cursor;
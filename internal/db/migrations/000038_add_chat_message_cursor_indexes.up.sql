@@ -0,0 +1,11 @@
+-- ============================================
+-- Cursor pagination of chat messages and thread replies seeks on
+-- (created_at, id); these replace the created_at and parent_id indexes.
+-- ============================================
+CREATE INDEX IF NOT EXISTS idx_chat_messages_channel_cursor
+    ON chat_messages (channel_id, created_at, id);
+CREATE INDEX IF NOT EXISTS idx_chat_messages_parent_cursor
+    ON chat_messages (parent_id, created_at, id);
+
+DROP INDEX IF EXISTS idx_chat_messages_channel_created;
+DROP INDEX IF EXISTS idx_chat_messages_parent;
@@ -0,0 +1,4 @@
+-- Only the constraint is dropped. The up migration is one-way for data:
+-- duplicate channels it merged (and their messages) are not split again, and
+-- channels it renamed from 'direct' keep the 'dm' type and ordered target_id.
+ALTER TABLE chat_channels DROP CONSTRAINT IF EXISTS chat_channels_dm_target_ordered;
@@ -0,0 +1,40 @@
+-- ============================================
+-- One direct message channel per pair of users in a workspace. Channels of
+-- type 'dm' are keyed by target_id '<lower user id>_<higher user id>', and
+-- UNIQUE (workspace_id, type, target_id) keeps a pair to a single channel.
+-- ============================================
+
+-- Older rows used the 'direct' type or either order of the two IDs
+CREATE TEMP TABLE direct_channel_keys AS
+SELECT
+    id,
+    FIRST_VALUE(id) OVER (
+        PARTITION BY workspace_id,
+            LEAST(split_part(target_id, '_', 1), split_part(target_id, '_', 2)),
+            GREATEST(split_part(target_id, '_', 1), split_part(target_id, '_', 2))
+        ORDER BY created_at, id
+    ) AS keep_id,
+    LEAST(split_part(target_id, '_', 1), split_part(target_id, '_', 2)) || '_' ||
+        GREATEST(split_part(target_id, '_', 1), split_part(target_id, '_', 2)) AS pair
+FROM chat_channels
+WHERE type IN ('dm', 'direct') AND target_id LIKE '%\_%';
+
+-- Merge duplicates into the oldest channel of each pair
+UPDATE chat_messages m SET channel_id = k.keep_id
+FROM direct_channel_keys k
+WHERE m.channel_id = k.id AND k.id <> k.keep_id;
+
+DELETE FROM chat_channels c
+USING direct_channel_keys k
+WHERE c.id = k.id AND k.id <> k.keep_id;
+
+UPDATE chat_channels c SET type = 'dm', target_id = k.pair
+FROM direct_channel_keys k
+WHERE c.id = k.id;
+
+DROP TABLE direct_channel_keys;
+
+-- Self-DMs ('<id>_<id>') from before they were rejected are kept, hence <=
+ALTER TABLE chat_channels
+    ADD CONSTRAINT chat_channels_dm_target_ordered
+    CHECK (type <> 'dm' OR split_part(target_id, '_', 1) <= split_part(target_id, '_', 2));
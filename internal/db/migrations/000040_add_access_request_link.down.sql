@@ -0,0 +1 @@
+ALTER TABLE IF EXISTS access_requests DROP COLUMN IF EXISTS link_settings_id;
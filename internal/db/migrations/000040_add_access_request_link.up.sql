@@ -0,0 +1,7 @@
+-- ============================================
+-- Access requests filed through an approval-gated invitation link remember
+-- the link, so approving one consumes a use of that link and grants its role
+-- ============================================
+
+ALTER TABLE IF EXISTS access_requests
+    ADD COLUMN IF NOT EXISTS link_settings_id UUID;
@@ -0,0 +1,3 @@
+-- One-way: the original casing of project keys is not kept, so there is
+-- nothing to restore.
+SELECT 1;
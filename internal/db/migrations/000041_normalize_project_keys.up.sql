@@ -0,0 +1,21 @@
+-- ============================================
+-- Project keys are written upper-case; bring older rows in line so key
+-- lookups can compare exactly and use idx_projects_key. A key whose upper-case
+-- form is taken gets the lowest free numeric suffix instead.
+-- ============================================
+DO $$
+DECLARE
+    p RECORD;
+    candidate TEXT;
+    n INTEGER;
+BEGIN
+    FOR p IN SELECT id, key FROM projects WHERE key <> UPPER(key) ORDER BY created_at, id LOOP
+        candidate := UPPER(p.key);
+        n := 1;
+        WHILE EXISTS (SELECT 1 FROM projects WHERE key = candidate AND id <> p.id) LOOP
+            n := n + 1;
+            candidate := LEFT(UPPER(p.key), 10 - LENGTH(n::text)) || n;
+        END LOOP;
+        UPDATE projects SET key = candidate WHERE id = p.id;
+    END LOOP;
+END $$;
@@ -0,0 +1,2 @@
+-- Removes the bot and, through the foreign key, the comments it posted
+DELETE FROM users WHERE id = '00000000-0000-0000-0000-00000000b070';
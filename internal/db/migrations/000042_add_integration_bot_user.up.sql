@@ -0,0 +1,10 @@
+-- ============================================
+-- Comments posted for source control events are authored by this bot user
+-- rather than by the admin who created the integration token. Its password
+-- is not a bcrypt hash, so it can never sign in, and the 'bot' status keeps
+-- it out of user lists, search and mentions.
+-- ============================================
+
+INSERT INTO users (id, email, password, name, status)
+VALUES ('00000000-0000-0000-0000-00000000b070', 'scm-bot@ora.invalid', '!', 'Source Control', 'bot')
+ON CONFLICT (id) DO NOTHING;
@@ -41,6 +41,11 @@ func (r *RedisDB) Close() {
 	}
 }
 
+// Ping checks that Redis answers
+func (r *RedisDB) Ping(ctx context.Context) error {
+	return r.Client.Ping(ctx).Err()
+}
+
 // Session management
 func (r *RedisDB) SetSession(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
 	data, err := json.Marshal(value)
@@ -79,13 +84,57 @@ func (r *RedisDB) GetCache(ctx context.Context, key string, dest interface{}) er
 	return json.Unmarshal(data, dest)
 }
 
-func (r *RedisDB) InvalidateCache(ctx context.Context, pattern string) error {
-	keys, err := r.Client.Keys(ctx, "cache:"+pattern).Result()
+// SetIndexedCache stores a cache entry and records its key in the set at
+// indexKey, so InvalidateIndex can drop the group without scanning. The set
+// lives as long as its newest entry.
+func (r *RedisDB) SetIndexedCache(ctx context.Context, indexKey, key string, value interface{}, expiration time.Duration) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	pipe := r.Client.TxPipeline()
+	pipe.Set(ctx, "cache:"+key, data, expiration)
+	pipe.SAdd(ctx, "cache:"+indexKey, key)
+	pipe.Expire(ctx, "cache:"+indexKey, expiration)
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// InvalidateIndex deletes every cache entry recorded under indexKey by
+// SetIndexedCache, and the index itself
+func (r *RedisDB) InvalidateIndex(ctx context.Context, indexKey string) error {
+	members, err := r.Client.SMembers(ctx, "cache:"+indexKey).Result()
 	if err != nil {
 		return err
 	}
-	if len(keys) > 0 {
-		return r.Client.Del(ctx, keys...).Err()
+	keys := make([]string, 0, len(members)+1)
+	for _, m := range members {
+		keys = append(keys, "cache:"+m)
+	}
+	keys = append(keys, "cache:"+indexKey)
+	return r.Client.Del(ctx, keys...).Err()
+}
+
+// invalidateScanCount is the SCAN batch size hint used by InvalidateCache
+const invalidateScanCount = 500
+
+// InvalidateCache deletes the cache entries matching pattern. It walks the
+// keyspace with SCAN rather than KEYS so a large keyspace never blocks Redis.
+func (r *RedisDB) InvalidateCache(ctx context.Context, pattern string) error {
+	var cursor uint64
+	for {
+		keys, next, err := r.Client.Scan(ctx, cursor, "cache:"+pattern, invalidateScanCount).Result()
+		if err != nil {
+			return err
+		}
+		if len(keys) > 0 {
+			if err := r.Client.Del(ctx, keys...).Err(); err != nil {
+				return err
+			}
+		}
+		if next == 0 {
+			return nil
+		}
+		cursor = next
 	}
-	return nil
 }
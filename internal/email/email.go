@@ -7,9 +7,14 @@ import (
 	"fmt"
 	"html/template"
 	"log"
+	"net"
 	"net/smtp"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
+
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/metrics"
 )
 
 // Config holds email configuration
@@ -27,6 +32,17 @@ type Config struct {
 type Service struct {
 	config    *Config
 	templates map[string]*template.Template
+
+	// Outcome of the last send or background check, reported by Health
+	healthMu sync.Mutex
+	health   Health
+}
+
+// Health is the last known state of the SMTP server
+type Health struct {
+	CheckedAt time.Time
+	Latency   time.Duration
+	Err       error
 }
 
 // NewService creates a new email service
@@ -470,6 +486,18 @@ func (s *Service) SendInvitation(
 
 // Send sends an email
 func (s *Service) Send(email *Email) error {
+	start := time.Now()
+	err := s.send(email)
+	s.recordHealth(start, err)
+	if err != nil {
+		metrics.EmailsSent.Inc("failed")
+	} else {
+		metrics.EmailsSent.Inc("sent")
+	}
+	return err
+}
+
+func (s *Service) send(email *Email) error {
 	if s.config.Host == "" {
 		log.Println("Email not configured, skipping send")
 		return nil
@@ -560,6 +588,84 @@ func (s *Service) Send(email *Email) error {
 	return smtp.SendMail(addr, auth, s.config.From, recipients, msg.Bytes())
 }
 
+// healthCheckInterval is how often StartHealthChecks dials the SMTP server
+const healthCheckInterval = time.Minute
+
+// Health returns the outcome of the last send or background check, and false
+// when there has been neither yet. It never contacts the server, so health
+// probes cannot be used to load it.
+func (s *Service) Health() (Health, bool) {
+	s.healthMu.Lock()
+	defer s.healthMu.Unlock()
+	return s.health, !s.health.CheckedAt.IsZero()
+}
+
+func (s *Service) recordHealth(start time.Time, err error) {
+	s.healthMu.Lock()
+	defer s.healthMu.Unlock()
+	s.health = Health{CheckedAt: time.Now(), Latency: time.Since(start), Err: err}
+}
+
+// StartHealthChecks checks the SMTP server now and then every
+// healthCheckInterval: it dials, waits for the greeting and quits, without
+// authenticating or sending anything. timeout bounds each check. The
+// returned function stops the checks.
+func (s *Service) StartHealthChecks(timeout time.Duration) func() {
+	done := make(chan struct{})
+	check := func() {
+		start := time.Now()
+		err := s.dialSMTP(timeout)
+		if err != nil {
+			log.Printf("[Email] SMTP health check failed: %v", err)
+		}
+		s.recordHealth(start, err)
+	}
+	go func() {
+		check()
+		ticker := time.NewTicker(healthCheckInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				check()
+			case <-done:
+				return
+			}
+		}
+	}()
+	var once sync.Once
+	return func() { once.Do(func() { close(done) }) }
+}
+
+func (s *Service) dialSMTP(timeout time.Duration) error {
+	addr := net.JoinHostPort(s.config.Host, strconv.Itoa(s.config.Port))
+	dialer := &net.Dialer{Timeout: timeout}
+
+	var conn net.Conn
+	var err error
+	if s.config.UseTLS {
+		conn, err = tls.DialWithDialer(dialer, "tcp", addr, &tls.Config{ServerName: s.config.Host})
+	} else {
+		conn, err = dialer.Dial("tcp", addr)
+	}
+	if err != nil {
+		return fmt.Errorf("dial error: %w", err)
+	}
+	defer conn.Close()
+
+	if err := conn.SetDeadline(time.Now().Add(timeout)); err != nil {
+		return err
+	}
+
+	client, err := smtp.NewClient(conn, s.config.Host)
+	if err != nil {
+		return fmt.Errorf("SMTP client error: %w", err)
+	}
+	defer client.Close()
+
+	return client.Quit()
+}
+
 // SendWithTemplate sends an email using a template
 func (s *Service) SendWithTemplate(to []string, subject, templateName string, data interface{}) error {
 	tmpl, ok := s.templates[templateName]
@@ -32,3 +32,17 @@ type UnifiedMemberResponse struct {
     User          *UserResponse `json:"user,omitempty"`
 }
 
+
+// ============================================
+// Access Request Models
+// ============================================
+
+type CreateAccessRequestRequest struct {
+    Type     string  `json:"type,omitempty"`     // workspace, space, folder or project; defaults to workspace
+    TargetID string  `json:"targetId,omitempty"` // defaults to the workspace itself
+    Message  *string `json:"message,omitempty"`
+}
+
+type DenyAccessRequestRequest struct {
+    Reason *string `json:"reason,omitempty"`
+}
@@ -37,9 +37,19 @@ type UserResponse struct {
 	Name      string    `json:"name"`
 	Avatar    *string   `json:"avatar,omitempty"`
 	Status    string    `json:"status"`
+	Deleted   bool      `json:"deleted,omitempty"`
 	CreatedAt time.Time `json:"createdAt"`
 }
 
+// UserPresenceResponse is one member's entry in a workspace presence list
+type UserPresenceResponse struct {
+	UserID       string     `json:"userId"`
+	Name         string     `json:"name"`
+	Avatar       *string    `json:"avatar,omitempty"`
+	Status       string     `json:"status"`
+	LastActiveAt *time.Time `json:"lastActiveAt"`
+}
+
 type UpdateUserRequest struct {
 	Name   *string `json:"name,omitempty"`
 	Avatar *string `json:"avatar,omitempty"`
@@ -111,6 +121,11 @@ type CompleteSprintRequest struct {
 	MoveIncomplete string `json:"moveIncomplete"` // "backlog" or sprint ID
 }
 
+// SetSprintCapacityRequest sets the hours a member can work during a sprint
+type SetSprintCapacityRequest struct {
+	HoursAvailable *float64 `json:"hoursAvailable" binding:"required,min=0"`
+}
+
 type SprintResponse struct {
 	ID        string     `json:"id"`
 	ProjectID string     `json:"projectId"` // ✓ parent reference
@@ -139,6 +154,7 @@ type NotificationResponse struct {
 	Message   string                  `json:"message"`
 	Read      bool                    `json:"read"`
 	Data      *map[string]interface{} `json:"data,omitempty"`
+	Count     int                     `json:"count"`
 	CreatedAt time.Time               `json:"createdAt"`
 }
 
@@ -147,14 +163,32 @@ type NotificationCountResponse struct {
 	Unread int `json:"unread"`
 }
 
+// NotificationPreferenceResponse is the effective setting for one notification type
+type NotificationPreferenceResponse struct {
+	Type  string `json:"type"`
+	InApp bool   `json:"inApp"`
+	Email bool   `json:"email"`
+}
+
+// NotificationPreferenceUpdate changes one type; omitted channels keep their setting
+type NotificationPreferenceUpdate struct {
+	Type  string `json:"type" binding:"required"`
+	InApp *bool  `json:"inApp,omitempty"`
+	Email *bool  `json:"email,omitempty"`
+}
+
+type UpdateNotificationPreferencesRequest struct {
+	Preferences []NotificationPreferenceUpdate `json:"preferences" binding:"required,dive"`
+}
+
 // ============================================
 // Checklist DTOs (NEW - Phase 1)
 // ============================================
 
+// UpdateChecklistItemRequest changes an item; an empty assigneeId unassigns it
 type UpdateChecklistItemRequest struct {
-	Text      *string `json:"text"`
-	IsChecked *bool   `json:"isChecked"`
-	Position  *int    `json:"position"`
+	Content    *string `json:"content,omitempty"`
+	AssigneeID *string `json:"assigneeId,omitempty"`
 }
 
 
@@ -203,12 +237,31 @@ type TimeTrackingSummaryResponse struct {
 // Common Response Types
 // ============================================
 
+// ErrorResponse is the body of every error response. Code is a stable,
+// machine-readable identifier that clients can branch on; Message is meant
+// for people and may change between releases.
 type ErrorResponse struct {
-	Error   string `json:"error"`
-	Message string `json:"message,omitempty"`
-	Code    string `json:"code,omitempty"`
+	Code    string      `json:"code"`
+	Message string      `json:"message"`
+	Details interface{} `json:"details,omitempty"`
+
+	// Deprecated: Error repeats Message for clients written before error
+	// codes existed. It will be removed in the next major API version.
+	Error string `json:"error"`
+}
+
+// NewErrorResponse builds an error body; details may be nil
+func NewErrorResponse(code, message string, details interface{}) ErrorResponse {
+	return ErrorResponse{Code: code, Message: message, Details: details, Error: message}
+}
+
+// FieldError is one failed validation rule, reported in Details
+type FieldError struct {
+	Field string `json:"field"`
+	Rule  string `json:"rule"`
 }
 
+
 type SuccessResponse struct {
 	Success bool        `json:"success"`
 	Message string      `json:"message,omitempty"`
@@ -283,3 +336,69 @@ type LabelResponse struct {
 	ProjectID string    `json:"projectId"`
 	CreatedAt time.Time `json:"createdAt"`
 }
+
+// LabelUsageResponse is a label with the number of tasks carrying it
+type LabelUsageResponse struct {
+	LabelResponse
+	UsageCount int `json:"usageCount"`
+}
+
+type MergeLabelsRequest struct {
+	ProjectID     string `json:"projectId" binding:"required"`
+	SourceLabelID string `json:"sourceLabelId" binding:"required"` // deleted after the merge
+	TargetLabelID string `json:"targetLabelId" binding:"required"`
+}
+
+// ============================================
+// Error Codes
+// ============================================
+
+// Error codes. Once published a code never changes meaning; new failure
+// modes get new codes.
+const (
+	// Generic codes, one per HTTP status
+	CodeInvalidInput    = "INVALID_INPUT"     // 400
+	CodeUnauthenticated = "UNAUTHENTICATED"   // 401: missing or invalid session
+	CodeUnauthorized    = "UNAUTHORIZED"      // 403: authenticated but not allowed
+	CodeNotFound        = "NOT_FOUND"         // 404
+	CodeConflict        = "CONFLICT"          // 409
+	CodeGone            = "GONE"              // 410
+	CodePayloadTooLarge = "PAYLOAD_TOO_LARGE" // 413
+	CodeUnprocessable   = "UNPROCESSABLE"     // 422
+	CodeRateLimited     = "RATE_LIMITED"      // 429
+	CodeInternal        = "INTERNAL_ERROR"    // 500
+
+	// Authentication
+	CodeInvalidCredentials  = "INVALID_CREDENTIALS"
+	CodeInvalidToken        = "INVALID_TOKEN"
+	CodeRefreshTokenExpired = "REFRESH_TOKEN_EXPIRED"
+	CodeRefreshTokenReused  = "REFRESH_TOKEN_REUSED"
+
+	// Missing resources
+	CodeUserNotFound       = "USER_NOT_FOUND"
+	CodeTaskNotFound       = "TASK_NOT_FOUND"
+	CodeCommentNotFound    = "COMMENT_NOT_FOUND"
+	CodeAttachmentNotFound = "ATTACHMENT_NOT_FOUND"
+	CodeProjectNotFound    = "PROJECT_NOT_FOUND"
+	CodeSprintNotFound     = "SPRINT_NOT_FOUND"
+	CodeWorkspaceNotFound  = "WORKSPACE_NOT_FOUND"
+	CodeSpaceNotFound      = "SPACE_NOT_FOUND"
+	CodeFolderNotFound     = "FOLDER_NOT_FOUND"
+	CodeGoalNotFound       = "GOAL_NOT_FOUND"
+	CodeLabelNotFound      = "LABEL_NOT_FOUND"
+	CodeTeamNotFound       = "TEAM_NOT_FOUND"
+
+	// Business rules
+	CodeUserExists            = "USER_EXISTS"
+	CodeStaleVersion          = "STALE_VERSION"
+	CodeSprintAlreadyActive   = "SPRINT_ALREADY_ACTIVE"
+	CodeSprintNoTasks         = "SPRINT_NO_TASKS"
+	CodeWIPLimitExceeded      = "WIP_LIMIT_EXCEEDED"
+	CodeInvalidTransition     = "INVALID_TRANSITION"
+	CodeLastOwner             = "LAST_OWNER"
+	CodeHasSubtasks           = "HAS_SUBTASKS"
+	CodeLimitExceeded         = "LIMIT_EXCEEDED"
+	CodeFileTooLarge          = "FILE_TOO_LARGE"
+	CodeEmailDomainNotAllowed = "EMAIL_DOMAIN_NOT_ALLOWED"
+	CodeInvalidEntityType     = "INVALID_ENTITY_TYPE"
+)
@@ -8,7 +8,7 @@ import "time"
 // Request models
 type CreateProjectRequest struct {
     Name        string  `json:"name" binding:"required"`
-    Key         string  `json:"key" binding:"required"`
+    Key         string  `json:"key"`           // derived from the name when omitted
     FolderID    *string `json:"folderId"`      // ✅ Change to camelCase
     Description *string `json:"description"`
     Icon        *string `json:"icon"`
@@ -41,4 +41,38 @@ type ProjectResponse struct {
 	CreatedBy    *string    `json:"createdBy,omitempty"`
 	CreatedAt    time.Time  `json:"createdAt"`
 	UpdatedAt    time.Time  `json:"updatedAt"`
-}
\ No newline at end of file
+}
+// SaveProjectTemplateRequest captures a project as a workspace template
+type SaveProjectTemplateRequest struct {
+	Name        string  `json:"name" binding:"required"`
+	Description *string `json:"description"`
+}
+
+// CreateProjectFromTemplateRequest creates a project in a space from a template.
+// Name defaults to the template's name and Key is derived from the name when omitted.
+type CreateProjectFromTemplateRequest struct {
+	TemplateID string `json:"templateId" binding:"required"`
+	Name       string `json:"name"`
+	Key        string `json:"key"`
+}
+
+type ProjectTemplateResponse struct {
+	ID              string      `json:"id"`
+	WorkspaceID     string      `json:"workspaceId"`
+	Name            string      `json:"name"`
+	Description     *string     `json:"description,omitempty"`
+	SourceProjectID *string     `json:"sourceProjectId,omitempty"`
+	StatusCount     int         `json:"statusCount"`
+	LabelCount      int         `json:"labelCount"`
+	TaskCount       int         `json:"taskCount"`
+	Definition      interface{} `json:"definition"`
+	CreatedBy       *string     `json:"createdBy,omitempty"`
+	CreatedAt       time.Time   `json:"createdAt"`
+}
+
+// ProjectKeyAvailabilityResponse answers the key check on the create form
+type ProjectKeyAvailabilityResponse struct {
+	Key        string  `json:"key"`
+	Available  bool    `json:"available"`
+	Suggestion *string `json:"suggestion,omitempty"` // free key to offer instead, when taken
+}
@@ -0,0 +1,28 @@
+package models
+
+// SCMEventRequest is a push or branch event from a source control system.
+// Commit messages and branch names are scanned for task keys such as PROJ-123.
+type SCMEventRequest struct {
+	Repository string      `json:"repository"`
+	Ref        string      `json:"ref"`
+	Commits    []SCMCommit `json:"commits"`
+	Branches   []SCMBranch `json:"branches"`
+}
+
+type SCMCommit struct {
+	ID      string `json:"id"`
+	Message string `json:"message"`
+	URL     string `json:"url"`
+	Author  string `json:"author"`
+}
+
+type SCMBranch struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// UpdateSCMSettingsRequest changes a project's SCM settings; omitted fields are kept
+type UpdateSCMSettingsRequest struct {
+	AutoTransition *bool   `json:"autoTransition"`
+	DoneStatus     *string `json:"doneStatus"`
+}
@@ -5,6 +5,9 @@ import "time"
 // TaskResponse is the API response model
 type TaskResponse struct {
 	ID             string     `json:"id"`
+	Key            string     `json:"key"` // e.g. "PROJ-123"
+	Number         int        `json:"number"`
+	Version        int        `json:"version"` // send back on updates
 	Title          string     `json:"title"`
 	Description    *string    `json:"description,omitempty"`
 	Status         string     `json:"status"`
@@ -15,10 +18,15 @@ type TaskResponse struct {
 	ParentTaskID   *string    `json:"parentTaskId,omitempty"`
 	AssigneeIDs    []string   `json:"assigneeIds"`
 	WatcherIDs     []string   `json:"watcherIds"`
-	LabelIDs       []string   `json:"labelIds"`
+	// Deprecated: use Labels. Kept for clients that still read the raw array.
+	LabelIDs       []string        `json:"labelIds"`
+	Labels         []LabelResponse `json:"labels,omitempty"`
 	StoryPoints    *int       `json:"storyPoints,omitempty"`
 	EstimatedHours *float64   `json:"estimatedHours,omitempty"`
 	ActualHours    *float64   `json:"actualHours,omitempty"`
+	// Actual minus estimated hours; null when the task has no estimate
+	HoursVariance        *float64 `json:"hoursVariance"`
+	HoursVariancePercent *float64 `json:"hoursVariancePercent"`
 	StartDate      *time.Time `json:"startDate,omitempty"`
 	DueDate        *time.Time `json:"dueDate,omitempty"`
 	CompletedAt    *time.Time `json:"completedAt,omitempty"`
@@ -29,6 +37,14 @@ type TaskResponse struct {
 	UpdatedAt      time.Time  `json:"updatedAt"`
 	SubtaskCount   int        `json:"subtaskCount"`
 	Subtasks       []TaskResponse `json:"subtasks,omitempty"`
+	// Items across all checklists, e.g. for "3/5" badges
+	ChecklistTotal     int `json:"checklistTotal"`
+	ChecklistCompleted int `json:"checklistCompleted"`
+	// Direct subtasks and how many are done, e.g. for "2/4" badges
+	SubtaskTotal     int `json:"subtaskTotal"`
+	SubtaskCompleted int `json:"subtaskCompleted"`
+	// Sum of the subtasks' story points
+	SubtaskStoryPoints int `json:"subtaskStoryPoints"`
 	
 	// ✅ Cycle Time Tracking Fields
 	StartedAt        *time.Time `json:"startedAt,omitempty"`
@@ -65,7 +81,8 @@ type SubtaskRequest struct {
 	EstimatedHours *float64 `json:"estimatedHours"`
 	StoryPoints    *int     `json:"storyPoints"`
 }
-// UpdateTaskRequest for updating tasks
+// UpdateTaskRequest for updating tasks. Version is the task version the
+// client last read; it may instead be sent as an If-Match header.
 type UpdateTaskRequest struct {
 	Title          *string    `json:"title,omitempty"`
 	Description    *string    `json:"description,omitempty"`
@@ -80,12 +97,14 @@ type UpdateTaskRequest struct {
 	StoryPoints    *int       `json:"storyPoints,omitempty"`
 	StartDate      *time.Time `json:"startDate,omitempty"`
 	DueDate        *time.Time `json:"dueDate,omitempty"`
+	Version        *int       `json:"version,omitempty"`
 }
 
 // Comment models
 type CreateCommentRequest struct {
-	Content        string   `json:"content" binding:"required"`
-	MentionedUsers []string `json:"mentionedUsers,omitempty"`
+	Content         string   `json:"content" binding:"required"`
+	MentionedUsers  []string `json:"mentionedUsers,omitempty"`
+	ParentCommentID *string  `json:"parentCommentId,omitempty"` // reply to this comment
 }
 
 type UpdateCommentRequest struct {
@@ -93,13 +112,39 @@ type UpdateCommentRequest struct {
 }
 
 type CommentResponse struct {
-	ID             string    `json:"id"`
-	TaskID         string    `json:"taskId"`
-	UserID         string    `json:"userId"`
-	Content        string    `json:"content"`
-	MentionedUsers []string  `json:"mentionedUsers"`
-	CreatedAt      time.Time `json:"createdAt"`
-	UpdatedAt      time.Time `json:"updatedAt"`
+	ID              string            `json:"id"`
+	TaskID          string            `json:"taskId"`
+	UserID          string            `json:"userId"`
+	User            *UserResponse     `json:"user,omitempty"`
+	Content         string            `json:"content"`
+	MentionedUsers  []string          `json:"mentionedUsers"`
+	ParentCommentID *string           `json:"parentCommentId,omitempty"`
+	Replies         []CommentResponse `json:"replies,omitempty"`
+	Reactions       []CommentReaction `json:"reactions"`
+	Edited          bool              `json:"edited"`
+	EditedAt        *time.Time        `json:"editedAt,omitempty"`
+	CreatedAt       time.Time         `json:"createdAt"`
+	UpdatedAt       time.Time         `json:"updatedAt"`
+}
+
+// CommentEditResponse is a previous version of an edited comment
+type CommentEditResponse struct {
+	ID              string    `json:"id"`
+	CommentID       string    `json:"commentId"`
+	PreviousContent string    `json:"previousContent"`
+	EditedBy        *string   `json:"editedBy,omitempty"`
+	EditedAt        time.Time `json:"editedAt"`
+}
+
+// CommentReaction is the count of one emoji on a comment
+type CommentReaction struct {
+	Emoji   string `json:"emoji"`
+	Count   int    `json:"count"`
+	Reacted bool   `json:"reacted"` // the current user used this emoji
+}
+
+type CommentReactionRequest struct {
+	Emoji string `json:"emoji" binding:"required"`
 }
 
 // Attachment models
@@ -163,6 +208,18 @@ type CreateChecklistItemRequest struct {
 	AssigneeID *string `json:"assigneeId,omitempty"`
 }
 
+// ReorderChecklistRequest moves a checklist among the task's checklists
+type ReorderChecklistRequest struct {
+	BeforeChecklistID *string `json:"beforeChecklistId,omitempty"` // checklist directly above the new slot
+	AfterChecklistID  *string `json:"afterChecklistId,omitempty"`  // checklist directly below the new slot
+}
+
+// ReorderChecklistItemRequest moves an item within its checklist
+type ReorderChecklistItemRequest struct {
+	BeforeItemID *string `json:"beforeItemId,omitempty"` // item directly above the new slot
+	AfterItemID  *string `json:"afterItemId,omitempty"`  // item directly below the new slot
+}
+
 type ChecklistItemResponse struct {
 	ID          string    `json:"id"`
 	ChecklistID string    `json:"checklistId"`
@@ -178,6 +235,7 @@ type ChecklistResponse struct {
 	ID        string                  `json:"id"`
 	TaskID    string                  `json:"taskId"`
 	Title     string                  `json:"title"`
+	Position  int                     `json:"position"`
 	Items     []ChecklistItemResponse `json:"items"`
 	CreatedAt time.Time               `json:"createdAt"`
 	UpdatedAt time.Time               `json:"updatedAt"`
@@ -195,9 +253,41 @@ type ActivityResponse struct {
 	CreatedAt time.Time `json:"createdAt"`
 }
 
+// TaskHistoryEntryResponse groups activity by one actor made close together.
+// Changes are human-readable, oldest first.
+type TaskHistoryEntryResponse struct {
+	ID        string    `json:"id"`
+	ActorID   *string   `json:"actorId,omitempty"`
+	ActorName string    `json:"actorName"`
+	Changes   []string  `json:"changes"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// BacklogResponse is a project's backlog with totals for sprint planning.
+// The summary covers every backlog task matching the filters, not only the
+// page in tasks.
+type BacklogResponse struct {
+	Tasks   []TaskResponse `json:"tasks"`
+	Summary BacklogSummary `json:"summary"`
+}
+
+type BacklogSummary struct {
+	TotalTasks       int            `json:"totalTasks"`
+	TotalStoryPoints int            `json:"totalStoryPoints"`
+	UnestimatedTasks int            `json:"unestimatedTasks"` // tasks without story points
+	ByPriority       map[string]int `json:"byPriority"`
+}
+
 // Filter models
 type TaskFiltersRequest struct {
-	ProjectID   string     `json:"projectId" binding:"required"`
+	ProjectID string `json:"projectId" binding:"required"`
+	TaskViewFilters
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+}
+
+// TaskViewFilters are the task filters without project or paging, as saved in views
+type TaskViewFilters struct {
 	SprintID    *string    `json:"sprintId,omitempty"`
 	AssigneeIDs []string   `json:"assigneeIds,omitempty"`
 	Statuses    []string   `json:"statuses,omitempty"`
@@ -208,8 +298,30 @@ type TaskFiltersRequest struct {
 	DueAfter    *time.Time `json:"dueAfter,omitempty"`
 	Overdue     *bool      `json:"overdue,omitempty"`
 	Blocked     *bool      `json:"blocked,omitempty"`
-	Limit       int        `json:"limit"`
-	Offset      int        `json:"offset"`
+}
+
+// Saved views
+type CreateSavedViewRequest struct {
+	Name    string          `json:"name" binding:"required"`
+	Shared  bool            `json:"shared"`
+	Filters TaskViewFilters `json:"filters"`
+}
+
+type UpdateSavedViewRequest struct {
+	Name    *string          `json:"name"`
+	Shared  *bool            `json:"shared"`
+	Filters *TaskViewFilters `json:"filters"`
+}
+
+type SavedViewResponse struct {
+	ID        string          `json:"id"`
+	ProjectID string          `json:"projectId"`
+	UserID    string          `json:"userId"`
+	Name      string          `json:"name"`
+	Shared    bool            `json:"shared"`
+	Filters   TaskViewFilters `json:"filters"`
+	CreatedAt time.Time       `json:"createdAt"`
+	UpdatedAt time.Time       `json:"updatedAt"`
 }
 
 // Bulk operation models
@@ -228,6 +340,90 @@ type BulkMoveToSprintRequest struct {
 	SprintID string   `json:"sprintId" binding:"required"`
 }
 
+type BulkDeleteRequest struct {
+	TaskIDs []string `json:"taskIds" binding:"required"`
+}
+
+// BulkLabelRequest adds a label to or removes it from several tasks
+type BulkLabelRequest struct {
+	TaskIDs []string `json:"taskIds" binding:"required"`
+	LabelID string   `json:"labelId" binding:"required"`
+	Action  string   `json:"action" binding:"required,oneof=add remove"`
+}
+
+// BulkLabelResponse counts the outcomes and lists the one of each task:
+// applied, skipped (nothing to change) or failed, with the reason
+type BulkLabelResponse struct {
+	Applied int               `json:"applied"`
+	Skipped int               `json:"skipped"`
+	Failed  int               `json:"failed"`
+	Results []BulkLabelResult `json:"results"`
+}
+
+type BulkLabelResult struct {
+	TaskID string `json:"taskId"`
+	Status string `json:"status"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// Clone models
+type CloneTaskRequest struct {
+	ProjectID       *string `json:"projectId,omitempty"` // target project, defaults to the source task's
+	IncludeSubtasks bool    `json:"includeSubtasks"`
+	CopyAssignees   bool    `json:"copyAssignees"`
+	CopyComments    bool    `json:"copyComments"`
+	CopyAttachments bool    `json:"copyAttachments"`
+}
+
+// Recurring task models
+type CreateRecurringTaskRequest struct {
+	Title       string     `json:"title" binding:"required"`
+	Description *string    `json:"description,omitempty"`
+	Priority    string     `json:"priority,omitempty"`
+	Type        *string    `json:"type,omitempty"`
+	AssigneeIDs []string   `json:"assigneeIds,omitempty"`
+	Cadence     string     `json:"cadence" binding:"required"` // "daily", "weekly", "monthly" or cron expression
+	StartAt     *time.Time `json:"startAt,omitempty"`          // first run, defaults to now
+}
+
+// Reorder models
+type ReorderTaskRequest struct {
+	Status       string  `json:"status,omitempty"`       // target column, defaults to current status
+	BeforeTaskID *string `json:"beforeTaskId,omitempty"` // task directly above the new slot
+	AfterTaskID  *string `json:"afterTaskId,omitempty"`  // task directly below the new slot
+}
+
+// WIP limit models
+type SetWIPLimitRequest struct {
+	Status   string `json:"status" binding:"required"`
+	MaxTasks int    `json:"maxTasks"` // 0 removes the limit
+}
+
+// UpdateTaskSettingsRequest changes per-project task behaviour; omitted fields keep their value
+type UpdateTaskSettingsRequest struct {
+	// What happens when all subtasks of a task are done: off, suggest or auto
+	ParentCompletion *string `json:"parentCompletion,omitempty"`
+	// Where unfinished tasks go when an expired sprint is closed
+	// automatically: next_sprint or backlog
+	SprintCarryOver *string `json:"sprintCarryOver,omitempty"`
+}
+
+// Project status workflow models
+type CreateProjectStatusRequest struct {
+	Key                string   `json:"key" binding:"required"` // value stored on tasks, e.g. "in_progress"
+	Name               string   `json:"name" binding:"required"`
+	Color              *string  `json:"color,omitempty"`
+	Position           *int     `json:"position,omitempty"`           // defaults to the end of the board
+	AllowedTransitions []string `json:"allowedTransitions,omitempty"` // empty allows any status
+}
+
+type UpdateProjectStatusRequest struct {
+	Name               *string   `json:"name,omitempty"`
+	Color              *string   `json:"color,omitempty"`
+	Position           *int      `json:"position,omitempty"`
+	AllowedTransitions *[]string `json:"allowedTransitions,omitempty"`
+}
+
 // Sprint burndown models
 type BurndownPoint struct {
 	Date   time.Time `json:"date"`
@@ -406,4 +602,19 @@ type ProjectAnalyticsDashboardResponse struct {
 	OverdueTasks              int                   `json:"overdueTasks"`
 	TasksCompletedLast30Days  int                   `json:"tasksCompletedLast30Days"`
 	PointsCompletedLast30Days int                   `json:"pointsCompletedLast30Days"`
-}
\ No newline at end of file
+}
+
+// MyWorkResponse is the "my work" dashboard; each section lists its first
+// tasks and the total number of tasks in it
+type MyWorkResponse struct {
+	Overdue           WorkSectionResponse `json:"overdue"`
+	DueToday          WorkSectionResponse `json:"dueToday"`
+	DueThisWeek       WorkSectionResponse `json:"dueThisWeek"`
+	NoDueDate         WorkSectionResponse `json:"noDueDate"`
+	RecentlyCompleted WorkSectionResponse `json:"recentlyCompleted"`
+}
+
+type WorkSectionResponse struct {
+	Tasks []TaskResponse `json:"tasks"`
+	Total int            `json:"total"`
+}
@@ -0,0 +1,43 @@
+package models
+
+import "time"
+
+// CreateWebhookRequest registers an endpoint. A secret is generated when omitted.
+type CreateWebhookRequest struct {
+	URL    string   `json:"url" binding:"required"`
+	Secret string   `json:"secret"`
+	Events []string `json:"events" binding:"required"`
+	Active *bool    `json:"active"`
+}
+
+// UpdateWebhookRequest changes a webhook; omitted fields are kept
+type UpdateWebhookRequest struct {
+	URL    *string   `json:"url"`
+	Secret *string   `json:"secret"`
+	Events *[]string `json:"events"`
+	Active *bool     `json:"active"`
+}
+
+// WebhookResponse never includes the secret, except right after it was set
+type WebhookResponse struct {
+	ID          string    `json:"id"`
+	WorkspaceID string    `json:"workspaceId"`
+	URL         string    `json:"url"`
+	Events      []string  `json:"events"`
+	Active      bool      `json:"active"`
+	Secret      string    `json:"secret,omitempty"`
+	CreatedBy   *string   `json:"createdBy,omitempty"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+// WebhookDeadLetterResponse is a delivery that failed on every attempt
+type WebhookDeadLetterResponse struct {
+	ID         string      `json:"id"`
+	Event      string      `json:"event"`
+	Payload    interface{} `json:"payload"`
+	Attempts   int         `json:"attempts"`
+	StatusCode *int        `json:"statusCode,omitempty"`
+	LastError  string      `json:"lastError"`
+	CreatedAt  time.Time   `json:"createdAt"`
+}
@@ -0,0 +1,103 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/repository"
+)
+
+// coalescingRepo keeps notifications in memory and merges on the coalesce
+// key the way the SQL upsert does
+type coalescingRepo struct {
+	repository.NotificationRepository
+	rows  []*repository.Notification
+	byKey map[string]*repository.Notification
+}
+
+func newCoalescingRepo() *coalescingRepo {
+	return &coalescingRepo{byKey: map[string]*repository.Notification{}}
+}
+
+func (r *coalescingRepo) Create(_ context.Context, n *repository.Notification) error {
+	n.ID = "n" + strconv.Itoa(len(r.rows)+1)
+	n.Count = 1
+	n.CreatedAt = time.Now()
+	stored := *n
+	r.rows = append(r.rows, &stored)
+	return nil
+}
+
+func (r *coalescingRepo) Coalesce(ctx context.Context, n *repository.Notification, key, summaryFormat string) error {
+	existing, ok := r.byKey[key]
+	if !ok {
+		if err := r.Create(ctx, n); err != nil {
+			return err
+		}
+		r.byKey[key] = r.rows[len(r.rows)-1]
+		return nil
+	}
+
+	existing.Count++
+	// format() and Sprintf agree on %s and %%
+	existing.Message = fmt.Sprintf(summaryFormat, strconv.Itoa(existing.Count))
+	existing.Read = false
+	*n = *existing
+	return nil
+}
+
+func TestThreeCommentsInAMinuteCollapseToOne(t *testing.T) {
+	repo := newCoalescingRepo()
+	svc := NewService(repo)
+	svc.SetCoalesceWindow(time.Hour)
+	ctx := context.Background()
+
+	for _, commenter := range []string{"Ana", "Ben", "Cy"} {
+		if err := svc.SendTaskCommented(ctx, "user-1", commenter, "Fix 100% CPU", "task-1", "project-1"); err != nil {
+			t.Fatalf("SendTaskCommented(%s): %v", commenter, err)
+		}
+	}
+
+	if len(repo.rows) != 1 {
+		t.Fatalf("stored %d notifications, want 1", len(repo.rows))
+	}
+	got := repo.rows[0]
+	if got.Count != 3 {
+		t.Errorf("count = %d, want 3", got.Count)
+	}
+	if want := "3 new comments on: Fix 100% CPU"; got.Message != want {
+		t.Errorf("message = %q, want %q", got.Message, want)
+	}
+}
+
+func TestCoalescingKeepsUsersTasksAndTypesApart(t *testing.T) {
+	repo := newCoalescingRepo()
+	svc := NewService(repo)
+	svc.SetCoalesceWindow(time.Hour)
+	ctx := context.Background()
+
+	svc.SendTaskCommented(ctx, "user-1", "Ana", "Task one", "task-1", "project-1")
+	svc.SendTaskCommented(ctx, "user-2", "Ana", "Task one", "task-1", "project-1")
+	svc.SendTaskCommented(ctx, "user-1", "Ana", "Task two", "task-2", "project-1")
+	svc.SendTaskStatusChangedBy(ctx, "user-1", "user-9", "Task one", "task-1", "project-1", "todo", "done")
+
+	if len(repo.rows) != 4 {
+		t.Fatalf("stored %d notifications, want 4 separate ones", len(repo.rows))
+	}
+}
+
+func TestCoalescingDisabled(t *testing.T) {
+	repo := newCoalescingRepo()
+	svc := NewService(repo)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		svc.SendTaskCommented(ctx, "user-1", "Ana", "Task", "task-1", "project-1")
+	}
+	if len(repo.rows) != 3 {
+		t.Fatalf("stored %d notifications with coalescing off, want 3", len(repo.rows))
+	}
+}
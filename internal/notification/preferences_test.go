@@ -0,0 +1,62 @@
+package notification
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/repository"
+)
+
+type stubPreferenceRepo struct {
+	repository.NotificationPreferenceRepository
+	prefs map[string]*repository.NotificationPreference // userID + "/" + type
+}
+
+func (r stubPreferenceRepo) Find(_ context.Context, userID, notificationType string) (*repository.NotificationPreference, error) {
+	return r.prefs[userID+"/"+notificationType], nil
+}
+
+func TestDisabledAssignedNotificationsAreNotStored(t *testing.T) {
+	repo := newCoalescingRepo()
+	svc := NewService(repo)
+	svc.SetPreferenceRepo(stubPreferenceRepo{prefs: map[string]*repository.NotificationPreference{
+		"muted/" + TypeTaskAssigned: {UserID: "muted", Type: TypeTaskAssigned, InApp: false, Email: false},
+	}})
+	ctx := context.Background()
+
+	if err := svc.SendTaskAssignedBy(ctx, "muted", "lead", "Write docs", "task-1", "project-1"); err != nil {
+		t.Fatalf("SendTaskAssignedBy(muted): %v", err)
+	}
+	if len(repo.rows) != 0 {
+		t.Fatalf("user who disabled assignments got %d notifications", len(repo.rows))
+	}
+	if svc.EmailEnabled(ctx, "muted", TypeTaskAssigned) {
+		t.Error("EmailEnabled should honour the disabled email switch")
+	}
+
+	// Users without a preference row keep getting everything
+	if err := svc.SendTaskAssignedBy(ctx, "other", "lead", "Write docs", "task-1", "project-1"); err != nil {
+		t.Fatalf("SendTaskAssignedBy(other): %v", err)
+	}
+	if len(repo.rows) != 1 || repo.rows[0].UserID != "other" {
+		t.Fatalf("want one notification for other, got %+v", repo.rows)
+	}
+	if !svc.EmailEnabled(ctx, "other", TypeTaskAssigned) {
+		t.Error("email should default to enabled")
+	}
+}
+
+func TestDisablingOneTypeKeepsOthers(t *testing.T) {
+	repo := newCoalescingRepo()
+	svc := NewService(repo)
+	svc.SetPreferenceRepo(stubPreferenceRepo{prefs: map[string]*repository.NotificationPreference{
+		"muted/" + TypeTaskAssigned: {UserID: "muted", Type: TypeTaskAssigned, InApp: false, Email: true},
+	}})
+
+	if err := svc.SendTaskCommented(context.Background(), "muted", "Ana", "Write docs", "task-1", "project-1"); err != nil {
+		t.Fatalf("SendTaskCommented: %v", err)
+	}
+	if len(repo.rows) != 1 {
+		t.Fatalf("comment notification was suppressed by the assignment preference")
+	}
+}
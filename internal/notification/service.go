@@ -7,7 +7,9 @@ import (
 	"log"
 	"regexp"
 	"strings"
+	"time"
 
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/metrics"
 	"github.com/Marga-Ghale/ora-scrum-backend/internal/repository"
 	"github.com/Marga-Ghale/ora-scrum-backend/internal/socket"
 )
@@ -17,6 +19,7 @@ const (
 	TypeTaskAssigned          = "TASK_ASSIGNED"
 	TypeTaskUpdated           = "TASK_UPDATED"
 	TypeTaskCommented         = "TASK_COMMENTED"
+	TypeCommentReplied        = "COMMENT_REPLIED"
 	TypeTaskStatusChanged     = "TASK_STATUS_CHANGED"
 	TypeTaskDueSoon           = "TASK_DUE_SOON"
 	TypeTaskOverdue           = "TASK_OVERDUE"
@@ -31,8 +34,11 @@ const (
 	TypeTaskAttachmentAdded   = "TASK_ATTACHMENT_ADDED"
 	TypeTaskAttachmentDeleted = "TASK_ATTACHMENT_DELETED"
 	TypeChecklistItemComplete = "CHECKLIST_ITEM_COMPLETED"
+	TypeChecklistItemAssigned = "CHECKLIST_ITEM_ASSIGNED"
 	TypeDependencyAdded       = "DEPENDENCY_ADDED"
 	TypeDependencyBlocking    = "DEPENDENCY_BLOCKING"
+	TypeTaskUnblocked         = "TASK_UNBLOCKED"
+	TypeSubtasksCompleted     = "SUBTASKS_COMPLETED"
 	TypeTimeLoggedToTask      = "TIME_LOGGED_TO_TASK"
 	TypeSpaceInvitation       = "SPACE_INVITATION"
 	TypeFolderInvitation = "FOLDER_INVITATION"
@@ -42,6 +48,10 @@ const (
 	TypeFolderRoleUpdated    = "FOLDER_ROLE_UPDATED"
 	TypeProjectRoleUpdated   = "PROJECT_ROLE_UPDATED"
 
+	TypeAccessRequested       = "ACCESS_REQUESTED"
+	TypeAccessRequestApproved = "ACCESS_REQUEST_APPROVED"
+	TypeAccessRequestDenied   = "ACCESS_REQUEST_DENIED"
+
 
 	// ✅ NEW: Chat-related notification types
 	TypeChatAddedToChannel   = "CHAT_ADDED_TO_CHANNEL"
@@ -50,6 +60,31 @@ const (
 	TypeChatMention          = "CHAT_MENTION"
 )
 
+// Types lists every notification type users can set preferences for
+var Types = []string{
+	TypeTaskAssigned, TypeTaskUpdated, TypeTaskCommented, TypeCommentReplied,
+	TypeTaskStatusChanged, TypeTaskDueSoon, TypeTaskOverdue,
+	TypeSprintStarted, TypeSprintCompleted, TypeSprintEnding, TypeMention,
+	TypeProjectInvitation, TypeWorkspaceInvitation, TypeTaskCreated, TypeTaskDeleted,
+	TypeTaskAttachmentAdded, TypeTaskAttachmentDeleted,
+	TypeChecklistItemComplete, TypeChecklistItemAssigned,
+	TypeDependencyAdded, TypeDependencyBlocking, TypeTaskUnblocked, TypeSubtasksCompleted, TypeTimeLoggedToTask,
+	TypeSpaceInvitation, TypeFolderInvitation,
+	TypeWorkspaceRoleUpdated, TypeSpaceRoleUpdated, TypeFolderRoleUpdated, TypeProjectRoleUpdated,
+	TypeAccessRequested, TypeAccessRequestApproved, TypeAccessRequestDenied,
+	TypeChatAddedToChannel, TypeChatRemovedFromChannel, TypeChatDirectMessage, TypeChatMention,
+}
+
+// IsValidType reports whether t is a known notification type
+func IsValidType(t string) bool {
+	for _, known := range Types {
+		if known == t {
+			return true
+		}
+	}
+	return false
+}
+
 
 
 // Service handles sending notifications
@@ -58,6 +93,9 @@ type Service struct {
 	userRepo         repository.UserRepository
 	projectRepo      repository.ProjectRepository
 	broadcaster      *socket.Broadcaster
+	preferenceRepo   repository.NotificationPreferenceRepository
+	taskRepo         repository.TaskRepository
+	coalesceWindow   time.Duration
 }
 
 func (s *Service) SetBroadcaster(b *socket.Broadcaster) {
@@ -94,6 +132,161 @@ func (s *Service) SetProjectRepo(projectRepo repository.ProjectRepository) {
 	s.projectRepo = projectRepo
 }
 
+// SetPreferenceRepo enables per-user notification preferences
+func (s *Service) SetPreferenceRepo(preferenceRepo repository.NotificationPreferenceRepository) {
+	s.preferenceRepo = preferenceRepo
+}
+
+// SetTaskRepo lets notifications about a task carry its key for deep links
+func (s *Service) SetTaskRepo(taskRepo repository.TaskRepository) {
+	s.taskRepo = taskRepo
+}
+
+// SetCoalesceWindow enables merging of repeated task notifications; 0 disables it
+func (s *Service) SetCoalesceWindow(window time.Duration) {
+	s.coalesceWindow = window
+}
+
+// ============================================
+// Persistence & Coalescing
+// ============================================
+
+// coalesceSummaries lists the task notification types that are merged when
+// they repeat within the coalesce window, with the message shown once merged.
+// The count is substituted for %s.
+var coalesceSummaries = map[string]string{
+	TypeTaskCommented:         "%s new comments on: ",
+	TypeCommentReplied:        "%s new replies on: ",
+	TypeTaskUpdated:           "%s updates to: ",
+	TypeTaskStatusChanged:     "Status changed %s times on: ",
+	TypeTaskAttachmentAdded:   "%s attachments added to: ",
+	TypeChecklistItemComplete: "%s checklist items completed on: ",
+	TypeTimeLoggedToTask:      "Time logged %s times on: ",
+}
+
+// navigationKeys maps Data ID fields to the entity they identify, most
+// specific first; the first one present decides where a notification links to
+var navigationKeys = []struct{ key, entityType string }{
+	{"accessRequestId", "access_request"},
+	{"taskId", "task"},
+	{"sprintId", "sprint"},
+	{"channelId", "channel"},
+	{"teamId", "team"},
+	{"projectId", "project"},
+	{"folderId", "folder"},
+	{"spaceId", "space"},
+	{"workspaceId", "workspace"},
+}
+
+// addNavigation fills the entityType, entityId and reference fields every
+// notification carries (see "Notification data" in the README). Reference is
+// the task key for tasks and the entity ID otherwise; entityType is "none"
+// when the notification points at nothing that still exists.
+func (s *Service) addNavigation(ctx context.Context, notification *repository.Notification) {
+	if notification.Data == nil {
+		notification.Data = map[string]interface{}{}
+	}
+	data := notification.Data
+	if _, ok := data["entityType"]; ok {
+		return
+	}
+
+	entityType, entityID := "none", ""
+	if targetID, _ := data["targetId"].(string); targetID != "" {
+		entityType, _ = data["targetType"].(string)
+		entityID = targetID
+	}
+	if entityID == "" || data["accessRequestId"] != nil {
+		for _, nk := range navigationKeys {
+			if id, _ := data[nk.key].(string); id != "" {
+				entityType, entityID = nk.entityType, id
+				break
+			}
+		}
+	}
+
+	reference := entityID
+	if entityType == "task" {
+		if key, _ := data["taskKey"].(string); key != "" {
+			reference = key
+		} else if s.taskRepo != nil {
+			if task, err := s.taskRepo.FindByID(ctx, entityID); err == nil && task != nil && task.Key != "" {
+				reference = task.Key
+				data["taskKey"] = task.Key
+			}
+		}
+	}
+
+	data["entityType"] = entityType
+	data["entityId"] = entityID
+	data["reference"] = reference
+}
+
+// preference returns the user's setting for the type; nil means everything on.
+// A lookup failure is logged and treated as enabled so notifications are not lost.
+func (s *Service) preference(ctx context.Context, userID, notificationType string) *repository.NotificationPreference {
+	if s.preferenceRepo == nil {
+		return nil
+	}
+	pref, err := s.preferenceRepo.Find(ctx, userID, notificationType)
+	if err != nil {
+		log.Printf("⚠️ Failed to load notification preference for %s/%s: %v", userID, notificationType, err)
+		return nil
+	}
+	return pref
+}
+
+// InAppEnabled reports whether the user wants in-app notifications of the type
+func (s *Service) InAppEnabled(ctx context.Context, userID, notificationType string) bool {
+	pref := s.preference(ctx, userID, notificationType)
+	return pref == nil || pref.InApp
+}
+
+// EmailEnabled reports whether the user wants emails for the type. Anything
+// that emails a user about an event must check this first.
+func (s *Service) EmailEnabled(ctx context.Context, userID, notificationType string) bool {
+	pref := s.preference(ctx, userID, notificationType)
+	return pref == nil || pref.Email
+}
+
+// save stores the notification, merging it into a recent notification of the
+// same type on the same task for the same user when coalescing is enabled.
+// Nothing is stored when the user turned the type off; the notification then
+// keeps an empty ID and is not pushed over WebSocket either.
+func (s *Service) save(ctx context.Context, notification *repository.Notification) error {
+	if !s.InAppEnabled(ctx, notification.UserID, notification.Type) {
+		return nil
+	}
+	s.addNavigation(ctx, notification)
+
+	summary, ok := coalesceSummaries[notification.Type]
+	taskID, _ := notification.Data["taskId"].(string)
+	if s.coalesceWindow <= 0 || !ok || taskID == "" {
+		if err := s.notificationRepo.Create(ctx, notification); err != nil {
+			return err
+		}
+		metrics.NotificationsSent.Inc(notification.Type)
+		return nil
+	}
+
+	taskTitle, _ := notification.Data["taskTitle"].(string)
+	if taskTitle == "" {
+		taskTitle, _ = notification.Data["taskKey"].(string)
+	}
+	if taskTitle == "" {
+		taskTitle = "a task"
+	}
+	window := time.Now().UnixNano() / int64(s.coalesceWindow)
+	key := fmt.Sprintf("%s:%s:%s:%d", notification.UserID, notification.Type, taskID, window)
+	// Escape the title so it is not read as a format() placeholder
+	format := summary + strings.ReplaceAll(taskTitle, "%", "%%")
+	if err := s.notificationRepo.Coalesce(ctx, notification, key, format); err != nil {
+		return err
+	}
+	metrics.NotificationsSent.Inc(notification.Type)
+	return nil
+}
+
 // ============================================
 // Helper: Get User Name by ID
 // ============================================
@@ -117,7 +310,7 @@ func (s *Service) getUserName(ctx context.Context, userID string) string {
 
 // sendWebSocketNotification sends real-time notification via WebSocket
 func (s *Service) sendWebSocketNotification(notification *repository.Notification) {
-	if s.broadcaster == nil || notification == nil {
+	if s.broadcaster == nil || notification == nil || notification.ID == "" {
 		return
 	}
 
@@ -130,6 +323,17 @@ func (s *Service) sendWebSocketNotification(notification *repository.Notificatio
 		"read":      notification.Read,
 		"createdAt": notification.CreatedAt,
 	})
+	s.sendUnreadCount(notification.UserID)
+}
+
+// sendUnreadCount pushes the user's unread total so badges update without polling
+func (s *Service) sendUnreadCount(userID string) {
+	_, unread, err := s.notificationRepo.CountByUserID(context.Background(), userID)
+	if err != nil {
+		log.Printf("⚠️ Failed to count unread notifications for %s: %v", userID, err)
+		return
+	}
+	s.broadcaster.SendNotificationUnread(userID, unread)
 }
 
 // ============================================
@@ -165,7 +369,7 @@ func (s *Service) SendTaskCreated(ctx context.Context, userIDs []string, creator
 			},
 		}
 
-		if err := s.notificationRepo.Create(ctx, notification); err != nil {
+		if err := s.save(ctx, notification); err != nil {
 			errs = append(errs, fmt.Errorf("failed to notify user %s: %w", userID, err))
 		} else {
 			s.sendWebSocketNotification(notification)
@@ -213,7 +417,7 @@ func (s *Service) SendTaskAssignedBy(ctx context.Context, userID, assignedByID,
 		},
 	}
 
-	if err := s.notificationRepo.Create(ctx, notification); err != nil {
+	if err := s.save(ctx, notification); err != nil {
 		return err
 	}
 
@@ -261,7 +465,7 @@ func (s *Service) SendTaskUpdatedBy(ctx context.Context, userID, updatedByID, ta
 		},
 	}
 
-	if err := s.notificationRepo.Create(ctx, notification); err != nil {
+	if err := s.save(ctx, notification); err != nil {
 		return err
 	}
 
@@ -325,7 +529,7 @@ func (s *Service) SendTaskStatusChangedBy(ctx context.Context, userID, changedBy
 		},
 	}
 
-	if err := s.notificationRepo.Create(ctx, notification); err != nil {
+	if err := s.save(ctx, notification); err != nil {
 		return err
 	}
 
@@ -374,7 +578,7 @@ func (s *Service) SendTaskCommented(ctx context.Context, userID, commenterName,
 		},
 	}
 
-	if err := s.notificationRepo.Create(ctx, notification); err != nil {
+	if err := s.save(ctx, notification); err != nil {
 		return err
 	}
 
@@ -402,7 +606,7 @@ func (s *Service) SendTaskDeleted(ctx context.Context, userID, taskTitle, taskKe
 		},
 	}
 
-	if err := s.notificationRepo.Create(ctx, notification); err != nil {
+	if err := s.save(ctx, notification); err != nil {
 		return err
 	}
 
@@ -454,7 +658,7 @@ func (s *Service) SendSprintStarted(ctx context.Context, userID, sprintName, spr
 		},
 	}
 
-	if err := s.notificationRepo.Create(ctx, notification); err != nil {
+	if err := s.save(ctx, notification); err != nil {
 		return err
 	}
 
@@ -485,7 +689,7 @@ func (s *Service) SendSprintStartedToMembers(ctx context.Context, members []stri
 			},
 		}
 
-		if err := s.notificationRepo.Create(ctx, notification); err != nil {
+		if err := s.save(ctx, notification); err != nil {
 			errs = append(errs, fmt.Errorf("failed to notify user %s: %w", userID, err))
 		} else {
 			s.sendWebSocketNotification(notification)
@@ -518,7 +722,7 @@ func (s *Service) SendSprintCompleted(ctx context.Context, userID, sprintName, s
 		},
 	}
 
-	if err := s.notificationRepo.Create(ctx, notification); err != nil {
+	if err := s.save(ctx, notification); err != nil {
 		return err
 	}
 
@@ -551,7 +755,7 @@ func (s *Service) SendSprintCompletedToMembers(ctx context.Context, members []st
 			},
 		}
 
-		if err := s.notificationRepo.Create(ctx, notification); err != nil {
+		if err := s.save(ctx, notification); err != nil {
 			errs = append(errs, fmt.Errorf("failed to notify user %s: %w", userID, err))
 		} else {
 			s.sendWebSocketNotification(notification)
@@ -564,6 +768,47 @@ func (s *Service) SendSprintCompletedToMembers(ctx context.Context, members []st
 	return nil
 }
 
+// SendSprintAutoCompleted tells the project lead that an expired sprint was
+// closed automatically, with what got done and where unfinished tasks went
+func (s *Service) SendSprintAutoCompleted(ctx context.Context, userID, sprintName, sprintID, projectID string, completedTasks, incompleteTasks, completedPoints, incompletePoints int, movedTo string) error {
+	if userID == "" {
+		return nil
+	}
+
+	message := fmt.Sprintf("Sprint '%s' passed its end date and was closed: %d tasks (%d points) done",
+		sprintName, completedTasks, completedPoints)
+	if incompleteTasks > 0 {
+		message += fmt.Sprintf(", %d unfinished tasks (%d points) moved to %s", incompleteTasks, incompletePoints, movedTo)
+	}
+
+	notification := &repository.Notification{
+		UserID:  userID,
+		Type:    TypeSprintCompleted,
+		Title:   "Sprint Closed Automatically",
+		Message: message,
+		Read:    false,
+		Data: map[string]interface{}{
+			"sprintId":         sprintID,
+			"sprintName":       sprintName,
+			"projectId":        projectID,
+			"autoCompleted":    true,
+			"completedTasks":   completedTasks,
+			"completedPoints":  completedPoints,
+			"incompleteTasks":  incompleteTasks,
+			"incompletePoints": incompletePoints,
+			"tasksMovedTo":     movedTo,
+			"action":           "view_sprint",
+		},
+	}
+
+	if err := s.save(ctx, notification); err != nil {
+		return err
+	}
+
+	s.sendWebSocketNotification(notification)
+	return nil
+}
+
 // SendSprintEnding sends a notification when a sprint is about to end
 func (s *Service) SendSprintEnding(ctx context.Context, userID, sprintName, sprintID, projectID string, daysRemaining int) error {
 	if userID == "" {
@@ -595,7 +840,7 @@ func (s *Service) SendSprintEnding(ctx context.Context, userID, sprintName, spri
 		},
 	}
 
-	if err := s.notificationRepo.Create(ctx, notification); err != nil {
+	if err := s.save(ctx, notification); err != nil {
 		return err
 	}
 
@@ -648,7 +893,38 @@ func (s *Service) SendMention(ctx context.Context, userID, mentionedBy, taskTitl
 		},
 	}
 
-	if err := s.notificationRepo.Create(ctx, notification); err != nil {
+	if err := s.save(ctx, notification); err != nil {
+		return err
+	}
+
+	s.sendWebSocketNotification(notification)
+	return nil
+}
+
+// SendCommentMention notifies a user mentioned in a task comment, including a snippet of the comment
+func (s *Service) SendCommentMention(ctx context.Context, userID, mentionedBy, taskTitle, taskKey, taskID, projectID, snippet string) error {
+	if userID == "" {
+		return nil
+	}
+
+	notification := &repository.Notification{
+		UserID:  userID,
+		Type:    TypeMention,
+		Title:   "You were mentioned",
+		Message: fmt.Sprintf("%s mentioned you in %s: %s", mentionedBy, taskTitle, snippet),
+		Read:    false,
+		Data: map[string]interface{}{
+			"taskId":      taskID,
+			"taskKey":     taskKey,
+			"taskTitle":   taskTitle,
+			"projectId":   projectID,
+			"mentionedBy": mentionedBy,
+			"snippet":     snippet,
+			"action":      "view_task",
+		},
+	}
+
+	if err := s.save(ctx, notification); err != nil {
 		return err
 	}
 
@@ -746,7 +1022,7 @@ func (s *Service) SendDueDateReminder(ctx context.Context, userID, taskTitle, ta
 		},
 	}
 
-	if err := s.notificationRepo.Create(ctx, notification); err != nil {
+	if err := s.save(ctx, notification); err != nil {
 		return err
 	}
 
@@ -783,7 +1059,7 @@ func (s *Service) SendOverdueTaskReminder(ctx context.Context, userID, taskTitle
 		},
 	}
 
-	if err := s.notificationRepo.Create(ctx, notification); err != nil {
+	if err := s.save(ctx, notification); err != nil {
 		return err
 	}
 
@@ -819,7 +1095,7 @@ func (s *Service) SendProjectInvitation(ctx context.Context, userID, projectName
 		},
 	}
 
-	if err := s.notificationRepo.Create(ctx, notification); err != nil {
+	if err := s.save(ctx, notification); err != nil {
 		return err
 	}
 
@@ -851,7 +1127,68 @@ func (s *Service) SendWorkspaceInvitation(ctx context.Context, userID, workspace
 		},
 	}
 
-	if err := s.notificationRepo.Create(ctx, notification); err != nil {
+	if err := s.save(ctx, notification); err != nil {
+		return err
+	}
+
+	s.sendWebSocketNotification(notification)
+	return nil
+}
+
+// SendAccessRequestApproved tells a requester their access request was granted
+func (s *Service) SendAccessRequestApproved(ctx context.Context, userID, targetType, targetName, targetID, approverName string) error {
+	if userID == "" {
+		return nil
+	}
+
+	notification := &repository.Notification{
+		UserID:  userID,
+		Type:    TypeAccessRequestApproved,
+		Title:   "Access Request Approved",
+		Message: fmt.Sprintf("%s approved your request to join %s: %s", approverName, targetType, targetName),
+		Read:    false,
+		Data: map[string]interface{}{
+			"targetType": targetType,
+			"targetId":   targetID,
+			"targetName": targetName,
+			"action":     "view_" + targetType,
+		},
+	}
+
+	if err := s.save(ctx, notification); err != nil {
+		return err
+	}
+
+	s.sendWebSocketNotification(notification)
+	return nil
+}
+
+// SendAccessRequestDenied tells a requester their access request was refused
+func (s *Service) SendAccessRequestDenied(ctx context.Context, userID, targetType, targetName, targetID, reason string) error {
+	if userID == "" {
+		return nil
+	}
+
+	message := fmt.Sprintf("Your request to join %s: %s was denied", targetType, targetName)
+	if reason != "" {
+		message = fmt.Sprintf("%s (%s)", message, reason)
+	}
+
+	notification := &repository.Notification{
+		UserID:  userID,
+		Type:    TypeAccessRequestDenied,
+		Title:   "Access Request Denied",
+		Message: message,
+		Read:    false,
+		Data: map[string]interface{}{
+			"targetType": targetType,
+			"targetId":   targetID,
+			"targetName": targetName,
+			"reason":     reason,
+		},
+	}
+
+	if err := s.save(ctx, notification); err != nil {
 		return err
 	}
 
@@ -881,7 +1218,7 @@ func (s *Service) SendBatchNotifications(ctx context.Context, userIDs []string,
 			Data:    data,
 		}
 
-		if err := s.notificationRepo.Create(ctx, notification); err != nil {
+		if err := s.save(ctx, notification); err != nil {
 			errs = append(errs, fmt.Errorf("failed to notify user %s: %w", userID, err))
 		} else {
 			s.sendWebSocketNotification(notification)
@@ -920,7 +1257,7 @@ func (s *Service) SendSpaceInvitation(ctx context.Context, userID, spaceName, sp
 		},
 	}
 
-	if err := s.notificationRepo.Create(ctx, notification); err != nil {
+	if err := s.save(ctx, notification); err != nil {
 		return err
 	}
 
@@ -952,7 +1289,7 @@ func (s *Service) SendFolderInvitation(ctx context.Context, userID, folderName,
 		},
 	}
 
-	if err := s.notificationRepo.Create(ctx, notification); err != nil {
+	if err := s.save(ctx, notification); err != nil {
 		return err
 	}
 
@@ -1034,7 +1371,7 @@ func (s *Service) SendWorkspaceRemoval(
 		},
 	}
 
-	if err := s.notificationRepo.Create(ctx, notification); err != nil {
+	if err := s.save(ctx, notification); err != nil {
 		return err
 	}
 
@@ -1065,7 +1402,7 @@ func (s *Service) SendSpaceRemoval(
 		},
 	}
 
-	if err := s.notificationRepo.Create(ctx, notification); err != nil {
+	if err := s.save(ctx, notification); err != nil {
 		return err
 	}
 
@@ -1096,7 +1433,7 @@ func (s *Service) SendFolderRemoval(
 		},
 	}
 
-	if err := s.notificationRepo.Create(ctx, notification); err != nil {
+	if err := s.save(ctx, notification); err != nil {
 		return err
 	}
 
@@ -1127,7 +1464,7 @@ func (s *Service) SendProjectRemoval(
 		},
 	}
 
-	if err := s.notificationRepo.Create(ctx, notification); err != nil {
+	if err := s.save(ctx, notification); err != nil {
 		return err
 	}
 
@@ -1163,7 +1500,7 @@ func (s *Service) SendWorkspaceRoleUpdate(
 		},
 	}
 
-	if err := s.notificationRepo.Create(ctx, notification); err != nil {
+	if err := s.save(ctx, notification); err != nil {
 		return err
 	}
 
@@ -1196,7 +1533,7 @@ func (s *Service) SendSpaceRoleUpdate(
 		},
 	}
 
-	if err := s.notificationRepo.Create(ctx, notification); err != nil {
+	if err := s.save(ctx, notification); err != nil {
 		return err
 	}
 
@@ -1229,7 +1566,7 @@ func (s *Service) SendFolderRoleUpdate(
 		},
 	}
 
-	if err := s.notificationRepo.Create(ctx, notification); err != nil {
+	if err := s.save(ctx, notification); err != nil {
 		return err
 	}
 
@@ -1262,7 +1599,7 @@ func (s *Service) SendProjectRoleUpdate(
 		},
 	}
 
-	if err := s.notificationRepo.Create(ctx, notification); err != nil {
+	if err := s.save(ctx, notification); err != nil {
 		return err
 	}
 
@@ -1307,7 +1644,7 @@ func (s *Service) SendChatAddedToChannel(ctx context.Context, userID, channelID,
 },
 	}
 
-	if err := s.notificationRepo.Create(ctx, notification); err != nil {
+	if err := s.save(ctx, notification); err != nil {
 		return err
 	}
 
@@ -1333,7 +1670,7 @@ func (s *Service) SendChatRemovedFromChannel(ctx context.Context, userID, channe
 },
 	}
 
-	if err := s.notificationRepo.Create(ctx, notification); err != nil {
+	if err := s.save(ctx, notification); err != nil {
 		return err
 	}
 
@@ -1342,7 +1679,7 @@ func (s *Service) SendChatRemovedFromChannel(ctx context.Context, userID, channe
 }
 
 // SendChatMention notifies user they were mentioned in chat
-func (s *Service) SendChatMention(ctx context.Context, userID, mentionedByName, channelID, channelName, messagePreview string, isDirect bool) error {
+func (s *Service) SendChatMention(ctx context.Context, userID, mentionedByName, channelID, channelName, messageID, messagePreview string, isDirect bool) error {
 	if userID == "" {
 		return nil
 	}
@@ -1368,69 +1705,17 @@ func (s *Service) SendChatMention(ctx context.Context, userID, mentionedByName,
 		Data: map[string]interface{}{
 			"channelId":   channelID,
 			"channelName": channelName,
+			"messageId":   messageID,
 			"mentionedBy": mentionedByName,
 			"isDirect":    isDirect,
 			"action":      "view_chat",
 		},
 	}
 
-	if err := s.notificationRepo.Create(ctx, notification); err != nil {
+	if err := s.save(ctx, notification); err != nil {
 		return err
 	}
 
 	s.sendWebSocketNotification(notification)
 	return nil
 }
-
-// ParseChatMentions parses message for @mentions and sends notifications
-func (s *Service) ParseChatMentions(ctx context.Context, content, authorID, authorName, channelID, channelName string, isDirect bool) error {
-	if s.userRepo == nil {
-		return nil
-	}
-
-	mentionRegex := regexp.MustCompile(`@([a-zA-Z0-9._]+(?:@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,})?)`)
-	matches := mentionRegex.FindAllStringSubmatch(content, -1)
-
-	mentionedUsers := make(map[string]bool)
-	var errs []error
-
-	for _, match := range matches {
-		if len(match) < 2 {
-			continue
-		}
-
-		mention := match[1]
-		var user *repository.User
-		var err error
-
-		if strings.Contains(mention, "@") {
-			user, err = s.userRepo.FindByEmail(ctx, mention)
-		} else {
-			user, err = s.userRepo.FindByName(ctx, mention)
-		}
-
-		if err != nil || user == nil {
-			continue
-		}
-
-		// Don't notify yourself
-		if user.ID == authorID {
-			continue
-		}
-
-		// Don't notify same user twice
-		if mentionedUsers[user.ID] {
-			continue
-		}
-		mentionedUsers[user.ID] = true
-
-		if err := s.SendChatMention(ctx, user.ID, authorName, channelID, channelName, content, isDirect); err != nil {
-			errs = append(errs, err)
-		}
-	}
-
-	if len(errs) > 0 {
-		return fmt.Errorf("errors sending chat mentions: %v", errs)
-	}
-	return nil
-}
\ No newline at end of file
@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// CalendarFeedRepository stores the hashed calendar feed token of each user.
+// A user has at most one token; saving a new one replaces the old.
+type CalendarFeedRepository interface {
+	SaveToken(ctx context.Context, userID, tokenHash string) error
+	// FindUserByToken returns "" when no user has the token. It also records
+	// that the feed was used.
+	FindUserByToken(ctx context.Context, tokenHash string) (string, error)
+	DeleteToken(ctx context.Context, userID string) error
+}
+
+type pgCalendarFeedRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewCalendarFeedRepository(pool *pgxpool.Pool) CalendarFeedRepository {
+	return &pgCalendarFeedRepository{pool: pool}
+}
+
+func (r *pgCalendarFeedRepository) SaveToken(ctx context.Context, userID, tokenHash string) error {
+	query := `
+		INSERT INTO calendar_feed_tokens (user_id, token_hash)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE SET
+			token_hash = EXCLUDED.token_hash, created_at = NOW(), last_used_at = NULL
+	`
+	_, err := r.pool.Exec(ctx, query, userID, tokenHash)
+	return err
+}
+
+func (r *pgCalendarFeedRepository) FindUserByToken(ctx context.Context, tokenHash string) (string, error) {
+	query := `
+		UPDATE calendar_feed_tokens SET last_used_at = NOW()
+		WHERE token_hash = $1
+		RETURNING user_id
+	`
+	var userID string
+	err := r.pool.QueryRow(ctx, query, tokenHash).Scan(&userID)
+	if err == pgx.ErrNoRows {
+		return "", nil
+	}
+	return userID, err
+}
+
+func (r *pgCalendarFeedRepository) DeleteToken(ctx context.Context, userID string) error {
+	_, err := r.pool.Exec(ctx, `DELETE FROM calendar_feed_tokens WHERE user_id = $1`, userID)
+	return err
+}
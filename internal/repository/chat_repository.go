@@ -2,9 +2,12 @@ package repository
 
 import (
 	"context"
+	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -68,6 +71,37 @@ type ChatReaction struct {
 	User      *User     `json:"user,omitempty"`
 }
 
+// ChatSearchResult is a message matching a search, with the messages just
+// before and after it in the channel (or its thread)
+type ChatSearchResult struct {
+	Message *ChatMessage   `json:"message"`
+	Snippet string         `json:"snippet"` // matched terms wrapped in <mark></mark>
+	Rank    float64        `json:"rank"`
+	Before  []*ChatMessage `json:"before"` // oldest first
+	After   []*ChatMessage `json:"after"`  // oldest first
+}
+
+// DefaultChatPageLimit is the number of messages in a page when none is asked for
+const DefaultChatPageLimit = 50
+
+// ChatCursor is a position in a channel or thread: a message's creation time
+// and ID, or a bare timestamp when ID is empty
+type ChatCursor struct {
+	CreatedAt time.Time
+	ID        string
+}
+
+// ChatMessagePage selects up to Limit messages just before Before, just after
+// After, or the newest ones when neither is set. A Limit of 0 reads them all.
+// Offset skips messages like the deprecated offset parameter did; it only
+// applies without a cursor.
+type ChatMessagePage struct {
+	Before *ChatCursor
+	After  *ChatCursor
+	Limit  int
+	Offset int
+}
+
 // ============================================
 // Chat Repository Interface
 // ============================================
@@ -78,6 +112,10 @@ type ChatRepository interface {
 	CreateChannel(ctx context.Context, channel *ChatChannel) error
 	GetChannelByID(ctx context.Context, id string) (*ChatChannel, error)
 	GetChannelByTarget(ctx context.Context, targetType, targetID string) (*ChatChannel, error)
+	// CreateDirectChannel inserts the direct channel with both users as
+	// members, unless the workspace already has one with the same type and
+	// target, which is returned instead. created reports which happened.
+	CreateDirectChannel(ctx context.Context, channel *ChatChannel, user1ID, user2ID string) (result *ChatChannel, created bool, err error)
 	ListChannelsByWorkspace(ctx context.Context, workspaceID string) ([]*ChatChannel, error)
 	ListChannelsByUser(ctx context.Context, userID string) ([]*ChatChannel, error)
 	UpdateChannel(ctx context.Context, channel *ChatChannel) error
@@ -94,10 +132,17 @@ type ChatRepository interface {
 	// Message operations
 	CreateMessage(ctx context.Context, message *ChatMessage) error
 	GetMessageByID(ctx context.Context, id string) (*ChatMessage, error)
-	GetMessages(ctx context.Context, channelID string, limit, offset int) ([]*ChatMessage, error)
-	GetThreadMessages(ctx context.Context, parentID string) ([]*ChatMessage, error)
+	// GetMessages returns a page of the channel's top-level messages, newest first
+	GetMessages(ctx context.Context, channelID string, page ChatMessagePage) ([]*ChatMessage, bool, error)
+	// GetThreadMessages returns a page of a thread's replies, oldest first
+	GetThreadMessages(ctx context.Context, parentID string, page ChatMessagePage) ([]*ChatMessage, bool, error)
 	UpdateMessage(ctx context.Context, message *ChatMessage) error
 	DeleteMessage(ctx context.Context, id string) error
+	// SearchMessages full-text searches the channel's messages, best matches first
+	SearchMessages(ctx context.Context, channelID, query string, limit int) ([]*ChatSearchResult, error)
+	// GetMessagesAround returns up to n messages on each side of the message,
+	// from the same thread when it is a reply
+	GetMessagesAround(ctx context.Context, message *ChatMessage, n int) (before, after []*ChatMessage, err error)
 
 	// Reaction operations
 	AddReaction(ctx context.Context, reaction *ChatReaction) error
@@ -164,6 +209,57 @@ func (r *chatRepository) GetChannelByTarget(ctx context.Context, targetType, tar
 	return channel, nil
 }
 
+func (r *chatRepository) CreateDirectChannel(ctx context.Context, channel *ChatChannel, user1ID, user2ID string) (*ChatChannel, bool, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	defer tx.Rollback(ctx)
+
+	channel.ID = uuid.New().String()
+	channel.CreatedAt = time.Now()
+	channel.UpdatedAt = channel.CreatedAt
+
+	// A concurrent request for the same pair waits on the unique constraint
+	// and then finds the row inserted here
+	var id string
+	err = tx.QueryRow(ctx, `
+		INSERT INTO chat_channels (id, name, type, target_id, workspace_id, created_by, is_private, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (workspace_id, type, target_id) DO NOTHING
+		RETURNING id
+	`, channel.ID, channel.Name, channel.Type, channel.TargetID, channel.WorkspaceID, channel.CreatedBy, channel.IsPrivate, channel.CreatedAt, channel.UpdatedAt).Scan(&id)
+	if err == pgx.ErrNoRows {
+		existing := &ChatChannel{}
+		err = tx.QueryRow(ctx, `
+			SELECT id, name, type, target_id, workspace_id, created_by, is_private, created_at, updated_at, last_message
+			FROM chat_channels WHERE workspace_id = $1 AND type = $2 AND target_id = $3
+		`, channel.WorkspaceID, channel.Type, channel.TargetID).Scan(&existing.ID, &existing.Name, &existing.Type, &existing.TargetID, &existing.WorkspaceID, &existing.CreatedBy, &existing.IsPrivate, &existing.CreatedAt, &existing.UpdatedAt, &existing.LastMessage)
+		if err != nil {
+			return nil, false, err
+		}
+		return existing, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	for _, userID := range []string{user1ID, user2ID} {
+		if _, err := tx.Exec(ctx, `
+			INSERT INTO chat_channel_members (id, channel_id, user_id, joined_at, last_read)
+			VALUES ($1, $2, $3, NOW(), NOW())
+			ON CONFLICT (channel_id, user_id) DO NOTHING
+		`, uuid.New().String(), channel.ID, userID); err != nil {
+			return nil, false, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, false, err
+	}
+	return channel, true, nil
+}
+
 func (r *chatRepository) ListChannelsByWorkspace(ctx context.Context, workspaceID string) ([]*ChatChannel, error) {
 	rows, err := r.pool.Query(ctx, `
 		SELECT id, name, type, target_id, workspace_id, created_by, is_private, created_at, updated_at, last_message
@@ -386,29 +482,104 @@ func (r *chatRepository) GetMessageByID(ctx context.Context, id string) (*ChatMe
 	return message, nil
 }
 
-func (r *chatRepository) GetMessages(ctx context.Context, channelID string, limit, offset int) ([]*ChatMessage, error) {
-	if limit <= 0 {
-		limit = 50
+func (r *chatRepository) GetMessages(ctx context.Context, channelID string, page ChatMessagePage) ([]*ChatMessage, bool, error) {
+	return r.listMessages(ctx, "m.channel_id = $1 AND m.parent_id IS NULL", channelID, page, true)
+}
+
+func (r *chatRepository) GetThreadMessages(ctx context.Context, parentID string, page ChatMessagePage) ([]*ChatMessage, bool, error) {
+	return r.listMessages(ctx, "m.parent_id = $1", parentID, page, false)
+}
+
+// listMessages reads a page of the messages matching where, which takes key
+// as $1. Pages are found by seeking on (created_at, id), so the cost does not
+// grow with how far back the page is. hasMore reports whether further
+// messages lie beyond the page in the direction it was read.
+func (r *chatRepository) listMessages(ctx context.Context, where, key string, page ChatMessagePage, newestFirst bool) ([]*ChatMessage, bool, error) {
+	args := []interface{}{key}
+	cursor, op, order := page.Before, "<", "DESC"
+	if page.After != nil {
+		cursor, op, order = page.After, ">", "ASC"
+	}
+	if cursor != nil {
+		if cursor.ID != "" {
+			args = append(args, cursor.CreatedAt, cursor.ID)
+			where += fmt.Sprintf(" AND (m.created_at, m.id) %s ($2, $3)", op)
+		} else {
+			args = append(args, cursor.CreatedAt)
+			where += fmt.Sprintf(" AND m.created_at %s $2", op)
+		}
+	}
+	limit := ""
+	if page.Limit > 0 {
+		args = append(args, page.Limit+1)
+		limit = " LIMIT $" + strconv.Itoa(len(args))
+	}
+	if cursor == nil && page.Offset > 0 {
+		args = append(args, page.Offset)
+		limit += " OFFSET $" + strconv.Itoa(len(args))
 	}
 
 	rows, err := r.pool.Query(ctx, `
-		SELECT 
-			m.id, m.channel_id, m.user_id, m.content, m.message_type, 
-			m.metadata, m.parent_id, m.is_edited, m.created_at, m.updated_at,
-			u.id, u.name, u.email, u.avatar,
-			(SELECT COUNT(*) FROM chat_messages replies WHERE replies.parent_id = m.id) as reply_count
+		SELECT `+chatMessageColumns+`
 		FROM chat_messages m
 		LEFT JOIN users u ON m.user_id = u.id
-		WHERE m.channel_id = $1 AND m.parent_id IS NULL
-		ORDER BY m.created_at DESC
-		LIMIT $2 OFFSET $3
-	`, channelID, limit, offset)
+		WHERE `+where+`
+		ORDER BY m.created_at `+order+`, m.id `+order+limit, args...)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
+	messages, err := scanChatMessages(rows)
+	if err != nil {
+		return nil, false, err
+	}
+
+	hasMore := page.Limit > 0 && len(messages) > page.Limit
+	if hasMore {
+		messages = messages[:page.Limit]
+	}
+	if (order == "DESC") != newestFirst {
+		for i, j := 0, len(messages)-1; i < j; i, j = i+1, j-1 {
+			messages[i], messages[j] = messages[j], messages[i]
+		}
+	}
+
+	// Load reactions for each message
+	for _, msg := range messages {
+		reactions, err := r.GetReactions(ctx, msg.ID)
+		if err == nil {
+			msg.Reactions = reactions
+		}
+	}
+
+	return messages, hasMore, nil
+}
+
+func (r *chatRepository) UpdateMessage(ctx context.Context, message *ChatMessage) error {
+	message.UpdatedAt = time.Now()
+	message.IsEdited = true
+	_, err := r.pool.Exec(ctx, `
+		UPDATE chat_messages SET content = $2, is_edited = $3, updated_at = $4 WHERE id = $1
+	`, message.ID, message.Content, message.IsEdited, message.UpdatedAt)
+	return err
+}
+
+func (r *chatRepository) DeleteMessage(ctx context.Context, id string) error {
+	_, err := r.pool.Exec(ctx, `DELETE FROM chat_messages WHERE id = $1`, id)
+	return err
+}
+
+// chatMessageColumns are the columns scanned by scanChatMessages
+const chatMessageColumns = `
+	m.id, m.channel_id, m.user_id, m.content, m.message_type,
+	m.metadata, m.parent_id, m.is_edited, m.created_at, m.updated_at,
+	u.id, u.name, u.email, u.avatar,
+	(SELECT COUNT(*) FROM chat_messages replies WHERE replies.parent_id = m.id)`
+
+// scanChatMessages reads rows selected with chatMessageColumns
+func scanChatMessages(rows pgx.Rows) ([]*ChatMessage, error) {
 	defer rows.Close()
 
-	var messages []*ChatMessage
+	messages := []*ChatMessage{}
 	for rows.Next() {
 		message := &ChatMessage{}
 		var userID, userName, userEmail, userAvatar *string
@@ -423,53 +594,40 @@ func (r *chatRepository) GetMessages(ctx context.Context, channelID string, limi
 			return nil, err
 		}
 
-		// Populate user if exists
 		if userID != nil && userName != nil {
-			message.User = &User{
-				ID:   *userID,
-				Name: *userName,
-			}
+			message.User = &User{ID: *userID, Name: *userName, Avatar: userAvatar}
 			if userEmail != nil {
 				message.User.Email = *userEmail
 			}
-			if userAvatar != nil {
-				message.User.Avatar = userAvatar
-			}
 		}
-
 		messages = append(messages, message)
 	}
-
-	// Load reactions for each message
-	for _, msg := range messages {
-		reactions, err := r.GetReactions(ctx, msg.ID)
-		if err == nil {
-			msg.Reactions = reactions
-		}
-	}
-
-	return messages, nil
+	return messages, rows.Err()
 }
 
-func (r *chatRepository) GetThreadMessages(ctx context.Context, parentID string) ([]*ChatMessage, error) {
+func (r *chatRepository) SearchMessages(ctx context.Context, channelID, query string, limit int) ([]*ChatSearchResult, error) {
 	rows, err := r.pool.Query(ctx, `
-		SELECT 
-			m.id, m.channel_id, m.user_id, m.content, m.message_type, 
-			m.metadata, m.parent_id, m.is_edited, m.created_at, m.updated_at,
-			u.id, u.name, u.email, u.avatar
+		WITH q AS (SELECT websearch_to_tsquery('english', $2) AS tsq)
+		SELECT `+chatMessageColumns+`,
+			`+headlineSQL("m.content")+`,
+			ts_rank(to_tsvector('english', m.content), q.tsq) AS rank
 		FROM chat_messages m
 		LEFT JOIN users u ON m.user_id = u.id
-		WHERE m.parent_id = $1
-		ORDER BY m.created_at ASC
-	`, parentID)
+		CROSS JOIN q
+		WHERE m.channel_id = $1
+		  AND to_tsvector('english', m.content) @@ q.tsq
+		ORDER BY rank DESC, m.created_at DESC
+		LIMIT $3
+	`, channelID, query, limit)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var messages []*ChatMessage
+	results := []*ChatSearchResult{}
 	for rows.Next() {
 		message := &ChatMessage{}
+		res := &ChatSearchResult{Message: message}
 		var userID, userName, userEmail, userAvatar *string
 
 		if err := rows.Scan(
@@ -477,50 +635,64 @@ func (r *chatRepository) GetThreadMessages(ctx context.Context, parentID string)
 			&message.MessageType, &message.Metadata, &message.ParentID,
 			&message.IsEdited, &message.CreatedAt, &message.UpdatedAt,
 			&userID, &userName, &userEmail, &userAvatar,
+			&message.ReplyCount,
+			&res.Snippet, &res.Rank,
 		); err != nil {
 			return nil, err
 		}
+		res.Snippet = markSnippet(res.Snippet)
 
-		// Populate user if exists
 		if userID != nil && userName != nil {
-			message.User = &User{
-				ID:   *userID,
-				Name: *userName,
-			}
+			message.User = &User{ID: *userID, Name: *userName, Avatar: userAvatar}
 			if userEmail != nil {
 				message.User.Email = *userEmail
 			}
-			if userAvatar != nil {
-				message.User.Avatar = userAvatar
-			}
 		}
-
-		messages = append(messages, message)
+		results = append(results, res)
 	}
+	return results, rows.Err()
+}
 
-	// Load reactions for each message
-	for _, msg := range messages {
-		reactions, err := r.GetReactions(ctx, msg.ID)
-		if err == nil {
-			msg.Reactions = reactions
-		}
+func (r *chatRepository) GetMessagesAround(ctx context.Context, message *ChatMessage, n int) ([]*ChatMessage, []*ChatMessage, error) {
+	// Ties on created_at are broken by id so a message is never its own neighbour
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+chatMessageColumns+`
+		FROM chat_messages m
+		LEFT JOIN users u ON m.user_id = u.id
+		WHERE m.channel_id = $1 AND m.parent_id IS NOT DISTINCT FROM $2
+		  AND (m.created_at, m.id) < ($3, $4)
+		ORDER BY m.created_at DESC, m.id DESC
+		LIMIT $5
+	`, message.ChannelID, message.ParentID, message.CreatedAt, message.ID, n)
+	if err != nil {
+		return nil, nil, err
+	}
+	before, err := scanChatMessages(rows)
+	if err != nil {
+		return nil, nil, err
+	}
+	for i, j := 0, len(before)-1; i < j; i, j = i+1, j-1 {
+		before[i], before[j] = before[j], before[i]
 	}
 
-	return messages, nil
-}
-
-func (r *chatRepository) UpdateMessage(ctx context.Context, message *ChatMessage) error {
-	message.UpdatedAt = time.Now()
-	message.IsEdited = true
-	_, err := r.pool.Exec(ctx, `
-		UPDATE chat_messages SET content = $2, is_edited = $3, updated_at = $4 WHERE id = $1
-	`, message.ID, message.Content, message.IsEdited, message.UpdatedAt)
-	return err
-}
+	rows, err = r.pool.Query(ctx, `
+		SELECT `+chatMessageColumns+`
+		FROM chat_messages m
+		LEFT JOIN users u ON m.user_id = u.id
+		WHERE m.channel_id = $1 AND m.parent_id IS NOT DISTINCT FROM $2
+		  AND (m.created_at, m.id) > ($3, $4)
+		ORDER BY m.created_at ASC, m.id ASC
+		LIMIT $5
+	`, message.ChannelID, message.ParentID, message.CreatedAt, message.ID, n)
+	if err != nil {
+		return nil, nil, err
+	}
+	after, err := scanChatMessages(rows)
+	if err != nil {
+		return nil, nil, err
+	}
 
-func (r *chatRepository) DeleteMessage(ctx context.Context, id string) error {
-	_, err := r.pool.Exec(ctx, `DELETE FROM chat_messages WHERE id = $1`, id)
-	return err
+	return before, after, nil
 }
 
 // ============================================
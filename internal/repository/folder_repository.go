@@ -182,7 +182,7 @@ func (r *pgFolderRepository) FindMembers(ctx context.Context, folderID string) (
 		       u.id, u.email, u.name, u.avatar, u.status
 		FROM folder_members fm
 		JOIN users u ON fm.user_id = u.id
-		WHERE fm.folder_id = $1
+		WHERE fm.folder_id = $1 AND u.deleted_at IS NULL
 		ORDER BY fm.joined_at
 	`
 	rows, err := r.pool.Query(ctx, query, folderID)
@@ -224,7 +224,7 @@ func (r *pgFolderRepository) FindMember(ctx context.Context, folderID, userID st
 }
 
 func (r *pgFolderRepository) FindMemberUserIDs(ctx context.Context, folderID string) ([]string, error) {
-	query := `SELECT user_id FROM folder_members WHERE folder_id = $1`
+	query := `SELECT m.user_id FROM folder_members m JOIN users u ON m.user_id = u.id WHERE m.folder_id = $1 AND u.deleted_at IS NULL`
 	rows, err := r.pool.Query(ctx, query, folderID)
 	if err != nil {
 		return nil, err
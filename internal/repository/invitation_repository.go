@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -12,6 +13,9 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// ErrLinkUnavailable is returned when an invitation link is inactive, expired or out of uses
+var ErrLinkUnavailable = errors.New("invitation link is inactive, expired or has reached its max uses")
+
 // InvitationType represents what the invitation is for
 type InvitationType string
 
@@ -154,19 +158,20 @@ type InvitationLinkSettings struct {
 
 // AccessRequest for users requesting access to resources
 type AccessRequest struct {
-	ID           string         `json:"id" db:"id"`
-	WorkspaceID  string         `json:"workspace_id" db:"workspace_id"`
-	RequesterID  string         `json:"requester_id" db:"requester_id"`
-	Email        string         `json:"email" db:"email"`
-	Type         InvitationType `json:"type" db:"type"`
-	TargetID     string         `json:"target_id" db:"target_id"`
-	Message      *string        `json:"message,omitempty" db:"message"`
-	Status       string         `json:"status" db:"status"`
-	ProcessedBy  *string        `json:"processed_by,omitempty" db:"processed_by"`
-	ProcessedAt  *time.Time     `json:"processed_at,omitempty" db:"processed_at"`
-	DenialReason *string        `json:"denial_reason,omitempty" db:"denial_reason"`
-	CreatedAt    time.Time      `json:"created_at" db:"created_at"`
-	UpdatedAt    time.Time      `json:"updated_at" db:"updated_at"`
+	ID             string         `json:"id" db:"id"`
+	WorkspaceID    string         `json:"workspace_id" db:"workspace_id"`
+	RequesterID    string         `json:"requester_id" db:"requester_id"`
+	Email          string         `json:"email" db:"email"`
+	Type           InvitationType `json:"type" db:"type"`
+	TargetID       string         `json:"target_id" db:"target_id"`
+	Message        *string        `json:"message,omitempty" db:"message"`
+	Status         string         `json:"status" db:"status"`
+	ProcessedBy    *string        `json:"processed_by,omitempty" db:"processed_by"`
+	ProcessedAt    *time.Time     `json:"processed_at,omitempty" db:"processed_at"`
+	DenialReason   *string        `json:"denial_reason,omitempty" db:"denial_reason"`
+	LinkSettingsID *string        `json:"link_settings_id,omitempty" db:"link_settings_id"`
+	CreatedAt      time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at" db:"updated_at"`
 }
 
 // BulkInvitationResult for batch inviting results
@@ -359,8 +364,12 @@ type InvitationRepository interface {
 	MarkExpired(ctx context.Context, id string) error
 	MarkCancelled(ctx context.Context, id string) error
 	MarkRevoked(ctx context.Context, id string) error
+	MarkAllExpired(ctx context.Context) (int, error)
 
 	UpdateReminderSent(ctx context.Context, id string) error
+	// ClaimResend records a send only if the last one (or the creation) is at
+	// least cooldown old; false means the caller must not send
+	ClaimResend(ctx context.Context, id string, cooldown time.Duration) (bool, error)
 	ResetReminderCount(ctx context.Context, id string) error
 
 	IncrementLinkUseCount(ctx context.Context, id string) error
@@ -380,6 +389,9 @@ type InvitationRepository interface {
 	GetActivityByInvitation(ctx context.Context, invitationID string) ([]*InvitationActivity, error)
 
 	GetPermissions(ctx context.Context, invitationID string) (*InvitationPermissions, error)
+	// FindAcceptedPermissions returns the permissions of the user's latest accepted
+	// invitation to the target, or nil when they joined without a restricted invitation
+	FindAcceptedPermissions(ctx context.Context, userID string, targetType InvitationType, targetID string) (*InvitationPermissions, error)
 	CreatePermissions(ctx context.Context, perms *InvitationPermissions) error
 	UpdatePermissions(ctx context.Context, perms *InvitationPermissions) error
 	DeletePermissions(ctx context.Context, invitationID string) error
@@ -391,6 +403,13 @@ type InvitationRepository interface {
 	UpdateLinkSettings(ctx context.Context, settings *InvitationLinkSettings) error
 	DeactivateLinkSettings(ctx context.Context, id string) error
 	IncrementLinkSettingsUseCount(ctx context.Context, id string) error
+	// ConsumeLinkUse takes one use of a still usable link and returns its
+	// default role, or fails with ErrLinkUnavailable
+	ConsumeLinkUse(ctx context.Context, id string) (WorkspaceRole, error)
+	// ReleaseLinkUse gives back a use taken by ConsumeLinkUse
+	ReleaseLinkUse(ctx context.Context, id string) error
+	// CreateFromLink consumes one use of the link and creates the invitation in a single transaction
+	CreateFromLink(ctx context.Context, linkSettingsID string, inv *Invitation) error
 	DeleteLinkSettings(ctx context.Context, id string) error
 
 	CreateAccessRequest(ctx context.Context, req *AccessRequest) error
@@ -413,7 +432,16 @@ func NewInvitationRepository(pool *pgxpool.Pool) InvitationRepository {
 	return &pgInvitationRepository{pool: pool}
 }
 
+// invitationQuerier is satisfied by both *pgxpool.Pool and pgx.Tx
+type invitationQuerier interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
 func (r *pgInvitationRepository) Create(ctx context.Context, inv *Invitation) error {
+	return insertInvitation(ctx, r.pool, inv)
+}
+
+func insertInvitation(ctx context.Context, q invitationQuerier, inv *Invitation) error {
 	if inv.ID == "" {
 		inv.ID = uuid.New().String()
 	}
@@ -442,7 +470,7 @@ func (r *pgInvitationRepository) Create(ctx context.Context, inv *Invitation) er
 		) RETURNING created_at, updated_at
 	`
 
-	return r.pool.QueryRow(ctx, query,
+	return q.QueryRow(ctx, query,
 		inv.ID, inv.WorkspaceID, inv.Email, inv.Token, inv.LinkToken,
 		inv.Type, inv.TargetID, inv.TargetName, inv.Role, inv.Permission,
 		inv.InvitedByID, inv.InvitedByName, inv.InviteeUserID, inv.Status,
@@ -868,6 +896,26 @@ func (r *pgInvitationRepository) MarkExpired(ctx context.Context, id string) err
 	return err
 }
 
+// MarkAllExpired flips every pending invitation past expires_at to expired
+// and logs a system "expired" activity for each, in a single statement
+func (r *pgInvitationRepository) MarkAllExpired(ctx context.Context) (int, error) {
+	query := `
+		WITH expired AS (
+			UPDATE invitations
+			SET status = 'expired', updated_at = NOW()
+			WHERE status = 'pending' AND expires_at < NOW()
+			RETURNING id
+		)
+		INSERT INTO invitation_activities (id, invitation_id, action, actor_type, created_at)
+		SELECT gen_random_uuid(), id, 'expired', 'system', NOW() FROM expired
+	`
+	result, err := r.pool.Exec(ctx, query)
+	if err != nil {
+		return 0, err
+	}
+	return int(result.RowsAffected()), nil
+}
+
 func (r *pgInvitationRepository) MarkCancelled(ctx context.Context, id string) error {
 	query := `UPDATE invitations SET status = 'cancelled', updated_at = NOW() WHERE id = $1`
 	_, err := r.pool.Exec(ctx, query, id)
@@ -890,6 +938,25 @@ func (r *pgInvitationRepository) UpdateReminderSent(ctx context.Context, id stri
 	return err
 }
 
+func (r *pgInvitationRepository) ClaimResend(ctx context.Context, id string, cooldown time.Duration) (bool, error) {
+	query := `
+		UPDATE invitations
+		SET reminder_sent_at = NOW(), reminder_count = reminder_count + 1, updated_at = NOW()
+		WHERE id = $1
+		  AND COALESCE(reminder_sent_at, created_at) <= NOW() - make_interval(secs => $2)
+		RETURNING id
+	`
+	var claimedID string
+	err := r.pool.QueryRow(ctx, query, id, cooldown.Seconds()).Scan(&claimedID)
+	if err == pgx.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
 func (r *pgInvitationRepository) ResetReminderCount(ctx context.Context, id string) error {
 	query := `
 		UPDATE invitations 
@@ -1074,6 +1141,26 @@ func (r *pgInvitationRepository) GetPermissions(ctx context.Context, invitationI
 	return p, err
 }
 
+func (r *pgInvitationRepository) FindAcceptedPermissions(ctx context.Context, userID string, targetType InvitationType, targetID string) (*InvitationPermissions, error) {
+	query := `
+		SELECT p.invitation_id
+		FROM invitation_permissions p
+		JOIN invitations i ON i.id = p.invitation_id
+		WHERE i.invitee_user_id = $1 AND i.type = $2 AND i.target_id = $3 AND i.status = $4
+		ORDER BY i.accepted_at DESC NULLS LAST
+		LIMIT 1
+	`
+	var invitationID string
+	err := r.pool.QueryRow(ctx, query, userID, targetType, targetID, InvitationStatusAccepted).Scan(&invitationID)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return r.GetPermissions(ctx, invitationID)
+}
+
 func (r *pgInvitationRepository) CreatePermissions(ctx context.Context, perms *InvitationPermissions) error {
 	if perms.ID == "" {
 		perms.ID = uuid.New().String()
@@ -1231,12 +1318,75 @@ func (r *pgInvitationRepository) DeactivateLinkSettings(ctx context.Context, id
 	return err
 }
 
+// IncrementLinkSettingsUseCount consumes one use of a link, failing with
+// ErrLinkUnavailable instead of going past MaxUses
 func (r *pgInvitationRepository) IncrementLinkSettingsUseCount(ctx context.Context, id string) error {
-	query := `UPDATE invitation_link_settings SET use_count = use_count + 1, updated_at = NOW() WHERE id = $1`
+	return incrementLinkUse(ctx, r.pool, id)
+}
+
+func (r *pgInvitationRepository) ConsumeLinkUse(ctx context.Context, id string) (WorkspaceRole, error) {
+	query := `
+		UPDATE invitation_link_settings SET use_count = use_count + 1, updated_at = NOW()
+		WHERE id = $1
+		  AND is_active = true
+		  AND (expires_at IS NULL OR expires_at > NOW())
+		  AND (max_uses IS NULL OR use_count < max_uses)
+		RETURNING default_role
+	`
+	var role WorkspaceRole
+	err := r.pool.QueryRow(ctx, query, id).Scan(&role)
+	if err == pgx.ErrNoRows {
+		return "", ErrLinkUnavailable
+	}
+	return role, err
+}
+
+func (r *pgInvitationRepository) ReleaseLinkUse(ctx context.Context, id string) error {
+	query := `
+		UPDATE invitation_link_settings SET use_count = GREATEST(use_count - 1, 0), updated_at = NOW()
+		WHERE id = $1
+	`
 	_, err := r.pool.Exec(ctx, query, id)
 	return err
 }
 
+func (r *pgInvitationRepository) CreateFromLink(ctx context.Context, linkSettingsID string, inv *Invitation) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := incrementLinkUse(ctx, tx, linkSettingsID); err != nil {
+		return err
+	}
+	if err := insertInvitation(ctx, tx, inv); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// incrementLinkUse bumps use_count only while the link is still usable. The
+// check and the increment are one statement, so concurrent accepts serialize
+// on the row and cannot both take the last use.
+func incrementLinkUse(ctx context.Context, q invitationQuerier, id string) error {
+	query := `
+		UPDATE invitation_link_settings SET use_count = use_count + 1, updated_at = NOW()
+		WHERE id = $1
+		  AND is_active = true
+		  AND (expires_at IS NULL OR expires_at > NOW())
+		  AND (max_uses IS NULL OR use_count < max_uses)
+		RETURNING use_count
+	`
+	var useCount int
+	err := q.QueryRow(ctx, query, id).Scan(&useCount)
+	if err == pgx.ErrNoRows {
+		return ErrLinkUnavailable
+	}
+	return err
+}
+
 func (r *pgInvitationRepository) DeleteLinkSettings(ctx context.Context, id string) error {
 	query := `DELETE FROM invitation_link_settings WHERE id = $1`
 	_, err := r.pool.Exec(ctx, query, id)
@@ -1252,27 +1402,28 @@ func (r *pgInvitationRepository) CreateAccessRequest(ctx context.Context, req *A
 	}
 	query := `
 		INSERT INTO access_requests (
-			id, workspace_id, requester_id, email, type, target_id, message, status, created_at, updated_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW(), NOW())
+			id, workspace_id, requester_id, email, type, target_id, message, status,
+			link_settings_id, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NOW(), NOW())
 		RETURNING created_at, updated_at
 	`
 	return r.pool.QueryRow(ctx, query,
 		req.ID, req.WorkspaceID, req.RequesterID, req.Email, req.Type,
-		req.TargetID, req.Message, req.Status,
+		req.TargetID, req.Message, req.Status, req.LinkSettingsID,
 	).Scan(&req.CreatedAt, &req.UpdatedAt)
 }
 
 func (r *pgInvitationRepository) GetAccessRequest(ctx context.Context, id string) (*AccessRequest, error) {
 	query := `
 		SELECT id, workspace_id, requester_id, email, type, target_id, message, status,
-			   processed_by, processed_at, denial_reason, created_at, updated_at
+			   processed_by, processed_at, denial_reason, link_settings_id, created_at, updated_at
 		FROM access_requests WHERE id = $1
 	`
 	req := &AccessRequest{}
 	err := r.pool.QueryRow(ctx, query, id).Scan(
 		&req.ID, &req.WorkspaceID, &req.RequesterID, &req.Email, &req.Type, &req.TargetID,
 		&req.Message, &req.Status, &req.ProcessedBy, &req.ProcessedAt, &req.DenialReason,
-		&req.CreatedAt, &req.UpdatedAt,
+		&req.LinkSettingsID, &req.CreatedAt, &req.UpdatedAt,
 	)
 	if err == pgx.ErrNoRows {
 		return nil, nil
@@ -1283,7 +1434,7 @@ func (r *pgInvitationRepository) GetAccessRequest(ctx context.Context, id string
 func (r *pgInvitationRepository) GetAccessRequestsByTarget(ctx context.Context, targetType InvitationType, targetID string, status string) ([]*AccessRequest, error) {
 	query := `
 		SELECT id, workspace_id, requester_id, email, type, target_id, message, status,
-			   processed_by, processed_at, denial_reason, created_at, updated_at
+			   processed_by, processed_at, denial_reason, link_settings_id, created_at, updated_at
 		FROM access_requests WHERE type = $1 AND target_id = $2
 	`
 	args := []interface{}{targetType, targetID}
@@ -1305,7 +1456,7 @@ func (r *pgInvitationRepository) GetAccessRequestsByTarget(ctx context.Context,
 		if err := rows.Scan(
 			&req.ID, &req.WorkspaceID, &req.RequesterID, &req.Email, &req.Type, &req.TargetID,
 			&req.Message, &req.Status, &req.ProcessedBy, &req.ProcessedAt, &req.DenialReason,
-			&req.CreatedAt, &req.UpdatedAt,
+			&req.LinkSettingsID, &req.CreatedAt, &req.UpdatedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -1317,7 +1468,7 @@ func (r *pgInvitationRepository) GetAccessRequestsByTarget(ctx context.Context,
 func (r *pgInvitationRepository) GetAccessRequestsByRequester(ctx context.Context, requesterID string) ([]*AccessRequest, error) {
 	query := `
 		SELECT id, workspace_id, requester_id, email, type, target_id, message, status,
-			   processed_by, processed_at, denial_reason, created_at, updated_at
+			   processed_by, processed_at, denial_reason, link_settings_id, created_at, updated_at
 		FROM access_requests WHERE requester_id = $1
 		ORDER BY created_at DESC
 	`
@@ -1333,7 +1484,7 @@ func (r *pgInvitationRepository) GetAccessRequestsByRequester(ctx context.Contex
 		if err := rows.Scan(
 			&req.ID, &req.WorkspaceID, &req.RequesterID, &req.Email, &req.Type, &req.TargetID,
 			&req.Message, &req.Status, &req.ProcessedBy, &req.ProcessedAt, &req.DenialReason,
-			&req.CreatedAt, &req.UpdatedAt,
+			&req.LinkSettingsID, &req.CreatedAt, &req.UpdatedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -16,6 +16,12 @@ type Label struct {
 	CreatedAt time.Time
 }
 
+// LabelUsage is a label with the number of tasks carrying it
+type LabelUsage struct {
+	*Label
+	UsageCount int
+}
+
 type LabelRepository interface {
 	Create(ctx context.Context, label *Label) error
 	FindByID(ctx context.Context, id string) (*Label, error)
@@ -23,6 +29,10 @@ type LabelRepository interface {
 	FindByName(ctx context.Context, projectID, name string) (*Label, error)
 	Update(ctx context.Context, label *Label) error
 	Delete(ctx context.Context, id string) error
+	CountUsage(ctx context.Context, labelID string) (int, error)
+	FindUsageByProjectID(ctx context.Context, projectID string) ([]*LabelUsage, error)
+	// Merge moves every task from source to target and deletes source, in one transaction
+	Merge(ctx context.Context, sourceID, targetID string) error
 }
 
 type pgLabelRepository struct {
@@ -99,3 +109,69 @@ func (r *pgLabelRepository) Delete(ctx context.Context, id string) error {
 	_, err := r.pool.Exec(ctx, query, id)
 	return err
 }
+
+func (r *pgLabelRepository) CountUsage(ctx context.Context, labelID string) (int, error) {
+	var count int
+	err := r.pool.QueryRow(ctx, `SELECT COUNT(*) FROM task_labels WHERE label_id = $1`, labelID).Scan(&count)
+	return count, err
+}
+
+func (r *pgLabelRepository) FindUsageByProjectID(ctx context.Context, projectID string) ([]*LabelUsage, error) {
+	query := `
+		SELECT l.id, l.name, l.color, l.project_id, l.created_at, COUNT(tl.task_id)
+		FROM labels l
+		LEFT JOIN task_labels tl ON tl.label_id = l.id
+		WHERE l.project_id = $1
+		GROUP BY l.id
+		ORDER BY l.name`
+	rows, err := r.pool.Query(ctx, query, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var labels []*LabelUsage
+	for rows.Next() {
+		u := &LabelUsage{Label: &Label{}}
+		if err := rows.Scan(&u.ID, &u.Name, &u.Color, &u.ProjectID, &u.CreatedAt, &u.UsageCount); err != nil {
+			return nil, err
+		}
+		labels = append(labels, u)
+	}
+	return labels, rows.Err()
+}
+
+func (r *pgLabelRepository) Merge(ctx context.Context, sourceID, targetID string) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// Tasks that already carry the target keep a single row
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO task_labels (task_id, label_id, created_at)
+		SELECT task_id, $2, created_at FROM task_labels WHERE label_id = $1
+		ON CONFLICT DO NOTHING`, sourceID, targetID); err != nil {
+		return err
+	}
+
+	// Keep the legacy tasks.label_ids array in sync, without duplicates
+	if _, err := tx.Exec(ctx, `
+		UPDATE tasks
+		SET label_ids = CASE
+				WHEN $2::text = ANY(label_ids) THEN array_remove(label_ids, $1::text)
+				ELSE array_replace(label_ids, $1::text, $2::text)
+			END,
+			updated_at = NOW()
+		WHERE $1::text = ANY(label_ids)`, sourceID, targetID); err != nil {
+		return err
+	}
+
+	// Cascades to the source's task_labels rows
+	if _, err := tx.Exec(ctx, `DELETE FROM labels WHERE id = $1`, sourceID); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
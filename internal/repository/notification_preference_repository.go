@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// NotificationPreference controls delivery of one notification type to a user.
+// Types without a stored preference are delivered on every channel.
+type NotificationPreference struct {
+	UserID    string
+	Type      string
+	InApp     bool
+	Email     bool
+	UpdatedAt time.Time
+}
+
+type NotificationPreferenceRepository interface {
+	// Find returns nil when the user has not set a preference for the type
+	Find(ctx context.Context, userID, notificationType string) (*NotificationPreference, error)
+	FindByUserID(ctx context.Context, userID string) ([]*NotificationPreference, error)
+	Upsert(ctx context.Context, pref *NotificationPreference) error
+}
+
+type pgNotificationPreferenceRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewNotificationPreferenceRepository(pool *pgxpool.Pool) NotificationPreferenceRepository {
+	return &pgNotificationPreferenceRepository{pool: pool}
+}
+
+func (r *pgNotificationPreferenceRepository) Find(ctx context.Context, userID, notificationType string) (*NotificationPreference, error) {
+	query := `
+		SELECT user_id, type, in_app, email, updated_at
+		FROM notification_preferences WHERE user_id = $1 AND type = $2
+	`
+	p := &NotificationPreference{}
+	err := r.pool.QueryRow(ctx, query, userID, notificationType).Scan(
+		&p.UserID, &p.Type, &p.InApp, &p.Email, &p.UpdatedAt,
+	)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (r *pgNotificationPreferenceRepository) FindByUserID(ctx context.Context, userID string) ([]*NotificationPreference, error) {
+	query := `
+		SELECT user_id, type, in_app, email, updated_at
+		FROM notification_preferences WHERE user_id = $1 ORDER BY type
+	`
+	rows, err := r.pool.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var prefs []*NotificationPreference
+	for rows.Next() {
+		p := &NotificationPreference{}
+		if err := rows.Scan(&p.UserID, &p.Type, &p.InApp, &p.Email, &p.UpdatedAt); err != nil {
+			return nil, err
+		}
+		prefs = append(prefs, p)
+	}
+	return prefs, rows.Err()
+}
+
+func (r *pgNotificationPreferenceRepository) Upsert(ctx context.Context, pref *NotificationPreference) error {
+	query := `
+		INSERT INTO notification_preferences (user_id, type, in_app, email)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id, type) DO UPDATE SET
+			in_app = EXCLUDED.in_app,
+			email = EXCLUDED.email,
+			updated_at = NOW()
+		RETURNING updated_at
+	`
+	return r.pool.QueryRow(ctx, query, pref.UserID, pref.Type, pref.InApp, pref.Email).Scan(&pref.UpdatedAt)
+}
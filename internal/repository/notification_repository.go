@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"time"
 
 	"github.com/jackc/pgx/v5"
@@ -17,19 +18,45 @@ type Notification struct {
 	Message   string
 	Read      bool
 	Data      map[string]interface{}
+	Count     int // events merged into this notification
 	CreatedAt time.Time
 }
 
+// DefaultNotificationLimit is the page size when no limit is given, and the maximum
+const DefaultNotificationLimit = 100
+
+// NotificationFilter narrows and pages a user's notifications
+type NotificationFilter struct {
+	Type       string
+	UnreadOnly bool
+	Before     *time.Time // created strictly before, for loading older pages
+	After      *time.Time
+	Limit      int
+	Offset     int
+	Ascending  bool // oldest first; newest first by default
+}
+
 type NotificationRepository interface {
 	Create(ctx context.Context, notification *Notification) error
+	// Coalesce creates the notification, or merges it into the existing one
+	// with the same key: the count is bumped, the notification is marked
+	// unread again and its message becomes summaryFormat (a Postgres format()
+	// string) applied to the new count.
+	Coalesce(ctx context.Context, notification *Notification, key, summaryFormat string) error
 	FindByID(ctx context.Context, id string) (*Notification, error)
 	FindByUserID(ctx context.Context, userID string, unreadOnly bool) ([]*Notification, error)
+	// FindByFilter returns a page and the number of notifications matching the filter
+	FindByFilter(ctx context.Context, userID string, filter NotificationFilter) ([]*Notification, int, error)
 	CountByUserID(ctx context.Context, userID string) (total int, unread int, err error)
 	MarkAsRead(ctx context.Context, id string) error
+	MarkAsUnread(ctx context.Context, id string) error
 	MarkAllAsRead(ctx context.Context, userID string) error
 	Delete(ctx context.Context, id string) error
 	DeleteAll(ctx context.Context, userID string) error
 	DeleteOlderThan(ctx context.Context, olderThan time.Time, readOnly bool) (int, error)
+	// CountOlderThan counts the read and unread notifications DeleteOlderThan
+	// would see
+	CountOlderThan(ctx context.Context, olderThan time.Time) (read int, unread int, err error)
 }
 
 type pgNotificationRepository struct {
@@ -48,20 +75,42 @@ func (r *pgNotificationRepository) Create(ctx context.Context, notification *Not
 	query := `
 		INSERT INTO notifications (user_id, type, title, message, read, data)
 		VALUES ($1, $2, $3, $4, $5, $6)
-		RETURNING id, created_at
+		RETURNING id, event_count, created_at
 	`
 	return r.pool.QueryRow(ctx, query,
 		notification.UserID, notification.Type, notification.Title,
 		notification.Message, notification.Read, dataJSON,
-	).Scan(&notification.ID, &notification.CreatedAt)
+	).Scan(&notification.ID, &notification.Count, &notification.CreatedAt)
+}
+
+func (r *pgNotificationRepository) Coalesce(ctx context.Context, notification *Notification, key, summaryFormat string) error {
+	dataJSON, _ := json.Marshal(notification.Data)
+	if notification.Data == nil {
+		dataJSON = []byte("{}")
+	}
+	query := `
+		INSERT INTO notifications (user_id, type, title, message, read, data, coalesce_key)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (coalesce_key) WHERE coalesce_key IS NOT NULL DO UPDATE SET
+			event_count = notifications.event_count + 1,
+			message = format($8, notifications.event_count + 1),
+			data = EXCLUDED.data,
+			read = FALSE,
+			created_at = NOW()
+		RETURNING id, message, event_count, created_at
+	`
+	return r.pool.QueryRow(ctx, query,
+		notification.UserID, notification.Type, notification.Title,
+		notification.Message, notification.Read, dataJSON, key, summaryFormat,
+	).Scan(&notification.ID, &notification.Message, &notification.Count, &notification.CreatedAt)
 }
 
 func (r *pgNotificationRepository) FindByID(ctx context.Context, id string) (*Notification, error) {
-	query := `SELECT id, user_id, type, title, message, read, data, created_at FROM notifications WHERE id = $1`
+	query := `SELECT id, user_id, type, title, message, read, data, event_count, created_at FROM notifications WHERE id = $1`
 	n := &Notification{}
 	var dataJSON []byte
 	err := r.pool.QueryRow(ctx, query, id).Scan(
-		&n.ID, &n.UserID, &n.Type, &n.Title, &n.Message, &n.Read, &dataJSON, &n.CreatedAt,
+		&n.ID, &n.UserID, &n.Type, &n.Title, &n.Message, &n.Read, &dataJSON, &n.Count, &n.CreatedAt,
 	)
 	if err == pgx.ErrNoRows {
 		return nil, nil
@@ -74,18 +123,54 @@ func (r *pgNotificationRepository) FindByID(ctx context.Context, id string) (*No
 }
 
 func (r *pgNotificationRepository) FindByUserID(ctx context.Context, userID string, unreadOnly bool) ([]*Notification, error) {
-	query := `
-		SELECT id, user_id, type, title, message, read, data, created_at 
-		FROM notifications WHERE user_id = $1
-	`
-	if unreadOnly {
-		query += ` AND read = FALSE`
+	notifications, _, err := r.FindByFilter(ctx, userID, NotificationFilter{UnreadOnly: unreadOnly})
+	return notifications, err
+}
+
+func (r *pgNotificationRepository) FindByFilter(ctx context.Context, userID string, filter NotificationFilter) ([]*Notification, int, error) {
+	baseQuery := ` FROM notifications WHERE user_id = $1`
+	args := []interface{}{userID}
+	argNum := 2
+
+	if filter.Type != "" {
+		baseQuery += fmt.Sprintf(" AND type = $%d", argNum)
+		args = append(args, filter.Type)
+		argNum++
+	}
+	if filter.UnreadOnly {
+		baseQuery += ` AND read = FALSE`
+	}
+	if filter.Before != nil {
+		baseQuery += fmt.Sprintf(" AND created_at < $%d", argNum)
+		args = append(args, *filter.Before)
+		argNum++
+	}
+	if filter.After != nil {
+		baseQuery += fmt.Sprintf(" AND created_at > $%d", argNum)
+		args = append(args, *filter.After)
+		argNum++
+	}
+
+	var total int
+	if err := r.pool.QueryRow(ctx, "SELECT COUNT(*)"+baseQuery, args...).Scan(&total); err != nil {
+		return nil, 0, err
 	}
-	query += ` ORDER BY created_at DESC LIMIT 100`
 
-	rows, err := r.pool.Query(ctx, query, userID)
+	limit := filter.Limit
+	if limit <= 0 || limit > DefaultNotificationLimit {
+		limit = DefaultNotificationLimit
+	}
+	orderDir := "DESC"
+	if filter.Ascending {
+		orderDir = "ASC"
+	}
+	query := `SELECT id, user_id, type, title, message, read, data, event_count, created_at` + baseQuery +
+		fmt.Sprintf(" ORDER BY created_at %s, id %s LIMIT $%d OFFSET $%d", orderDir, orderDir, argNum, argNum+1)
+	args = append(args, limit, max(filter.Offset, 0))
+
+	rows, err := r.pool.Query(ctx, query, args...)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 	defer rows.Close()
 
@@ -94,14 +179,14 @@ func (r *pgNotificationRepository) FindByUserID(ctx context.Context, userID stri
 		n := &Notification{}
 		var dataJSON []byte
 		if err := rows.Scan(
-			&n.ID, &n.UserID, &n.Type, &n.Title, &n.Message, &n.Read, &dataJSON, &n.CreatedAt,
+			&n.ID, &n.UserID, &n.Type, &n.Title, &n.Message, &n.Read, &dataJSON, &n.Count, &n.CreatedAt,
 		); err != nil {
-			return nil, err
+			return nil, 0, err
 		}
 		json.Unmarshal(dataJSON, &n.Data)
 		notifications = append(notifications, n)
 	}
-	return notifications, nil
+	return notifications, total, rows.Err()
 }
 
 func (r *pgNotificationRepository) CountByUserID(ctx context.Context, userID string) (total int, unread int, err error) {
@@ -121,6 +206,12 @@ func (r *pgNotificationRepository) MarkAsRead(ctx context.Context, id string) er
 	return err
 }
 
+func (r *pgNotificationRepository) MarkAsUnread(ctx context.Context, id string) error {
+	query := `UPDATE notifications SET read = FALSE WHERE id = $1`
+	_, err := r.pool.Exec(ctx, query, id)
+	return err
+}
+
 func (r *pgNotificationRepository) MarkAllAsRead(ctx context.Context, userID string) error {
 	query := `UPDATE notifications SET read = TRUE WHERE user_id = $1`
 	_, err := r.pool.Exec(ctx, query, userID)
@@ -150,3 +241,13 @@ func (r *pgNotificationRepository) DeleteOlderThan(ctx context.Context, olderTha
 	}
 	return int(result.RowsAffected()), nil
 }
+
+func (r *pgNotificationRepository) CountOlderThan(ctx context.Context, olderThan time.Time) (int, int, error) {
+	query := `
+		SELECT COUNT(*) FILTER (WHERE read = TRUE), COUNT(*) FILTER (WHERE read = FALSE)
+		FROM notifications WHERE created_at < $1
+	`
+	var read, unread int
+	err := r.pool.QueryRow(ctx, query, olderThan).Scan(&read, &unread)
+	return read, unread, err
+}
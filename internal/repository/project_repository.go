@@ -38,6 +38,8 @@ type ProjectMember struct {
 type ProjectRepository interface {
 	Create(ctx context.Context, project *Project) error
 	FindByID(ctx context.Context, id string) (*Project, error)
+	// FindByKey matches the key case-insensitively; keys are unique across all spaces
+	FindByKey(ctx context.Context, key string) (*Project, error)
 	FindBySpaceID(ctx context.Context, spaceID string) ([]*Project, error)
 	FindByFolderID(ctx context.Context, folderID string) ([]*Project, error)
 	FindByUserID(ctx context.Context, userID string) ([]*Project, error)
@@ -52,6 +54,33 @@ type ProjectRepository interface {
 	UpdateMemberRole(ctx context.Context, projectID, userID, role string) error
 	RemoveMember(ctx context.Context, projectID, userID string) error
 	HasAccess(ctx context.Context, projectID, userID string) (bool, error)
+
+	// Stats
+	GetStats(ctx context.Context, projectID string) (*ProjectStats, error)
+}
+
+// ProjectStats aggregates a project's tasks. "Open" means neither done nor
+// cancelled; cancelled tasks are only counted in Cancelled and ByStatus.
+type ProjectStats struct {
+	ProjectID            string         `json:"projectId"`
+	TotalTasks           int            `json:"totalTasks"`
+	OpenTasks            int            `json:"openTasks"`
+	CompletedTasks       int            `json:"completedTasks"`
+	CancelledTasks       int            `json:"cancelledTasks"`
+	OverdueTasks         int            `json:"overdueTasks"`
+	TotalStoryPoints     int            `json:"totalStoryPoints"`
+	CompletedStoryPoints int            `json:"completedStoryPoints"`
+	ByStatus             map[string]int `json:"byStatus"`
+	ByPriority           map[string]int `json:"byPriority"` // open tasks only
+	ByAssignee           map[string]int `json:"byAssignee"` // open tasks only, "unassigned" for none
+
+	// Estimated vs actual hours over non-cancelled tasks that have an estimate;
+	// the variance is nil when no task is estimated
+	TotalEstimatedHours    float64  `json:"totalEstimatedHours"`
+	TotalActualHours       float64  `json:"totalActualHours"`
+	HoursVariance          *float64 `json:"hoursVariance"`        // actual - estimated
+	HoursVariancePercent   *float64 `json:"hoursVariancePercent"` // variance / estimated * 100
+	UnestimatedActualHours float64  `json:"unestimatedActualHours"`
 }
 
 type pgProjectRepository struct {
@@ -95,6 +124,27 @@ func (r *pgProjectRepository) FindByID(ctx context.Context, id string) (*Project
 	return p, nil
 }
 
+func (r *pgProjectRepository) FindByKey(ctx context.Context, key string) (*Project, error) {
+	query := `
+		SELECT id, space_id, folder_id, name, key, description, icon, color, lead_id, visibility, allowed_users, allowed_teams, created_by, created_at, updated_at
+		FROM projects WHERE key = UPPER($1)
+		LIMIT 1
+	`
+	p := &Project{}
+	err := r.pool.QueryRow(ctx, query, key).Scan(
+		&p.ID, &p.SpaceID, &p.FolderID, &p.Name, &p.Key, &p.Description,
+		&p.Icon, &p.Color, &p.LeadID, &p.Visibility, &p.AllowedUsers, &p.AllowedTeams,
+		&p.CreatedBy, &p.CreatedAt, &p.UpdatedAt,
+	)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
 func (r *pgProjectRepository) FindBySpaceID(ctx context.Context, spaceID string) ([]*Project, error) {
 	query := `
 		SELECT id, space_id, folder_id, name, key, description, icon, color, lead_id, visibility, allowed_users, allowed_teams, created_by, created_at, updated_at
@@ -217,7 +267,7 @@ func (r *pgProjectRepository) FindMembers(ctx context.Context, projectID string)
 		       u.id, u.email, u.name, u.avatar, u.status
 		FROM project_members pm
 		JOIN users u ON pm.user_id = u.id
-		WHERE pm.project_id = $1
+		WHERE pm.project_id = $1 AND u.deleted_at IS NULL
 		ORDER BY pm.joined_at
 	`
 	rows, err := r.pool.Query(ctx, query, projectID)
@@ -259,7 +309,7 @@ func (r *pgProjectRepository) FindMember(ctx context.Context, projectID, userID
 }
 
 func (r *pgProjectRepository) FindMemberUserIDs(ctx context.Context, projectID string) ([]string, error) {
-	query := `SELECT user_id FROM project_members WHERE project_id = $1`
+	query := `SELECT m.user_id FROM project_members m JOIN users u ON m.user_id = u.id WHERE m.project_id = $1 AND u.deleted_at IS NULL`
 	rows, err := r.pool.Query(ctx, query, projectID)
 	if err != nil {
 		return nil, err
@@ -299,4 +349,92 @@ func (r *pgProjectRepository) HasAccess(ctx context.Context, projectID, userID s
 	var exists bool
 	err := r.pool.QueryRow(ctx, query, projectID, userID).Scan(&exists)
 	return exists, err
-}
\ No newline at end of file
+}
+
+func (r *pgProjectRepository) GetStats(ctx context.Context, projectID string) (*ProjectStats, error) {
+	stats := &ProjectStats{
+		ProjectID:  projectID,
+		ByStatus:   make(map[string]int),
+		ByPriority: make(map[string]int),
+		ByAssignee: make(map[string]int),
+	}
+
+	totalsQuery := `
+		SELECT
+			COUNT(*),
+			COUNT(*) FILTER (WHERE status NOT IN ('done', 'cancelled')),
+			COUNT(*) FILTER (WHERE status = 'done'),
+			COUNT(*) FILTER (WHERE status = 'cancelled'),
+			COUNT(*) FILTER (WHERE status NOT IN ('done', 'cancelled') AND due_date < NOW()),
+			COALESCE(SUM(story_points) FILTER (WHERE status <> 'cancelled'), 0),
+			COALESCE(SUM(story_points) FILTER (WHERE status = 'done'), 0),
+			COUNT(*) FILTER (WHERE status <> 'cancelled' AND estimated_hours > 0),
+			COALESCE(SUM(estimated_hours) FILTER (WHERE status <> 'cancelled' AND estimated_hours > 0), 0),
+			COALESCE(SUM(actual_hours) FILTER (WHERE status <> 'cancelled' AND estimated_hours > 0), 0),
+			COALESCE(SUM(actual_hours) FILTER (WHERE status <> 'cancelled' AND COALESCE(estimated_hours, 0) <= 0), 0)
+		FROM tasks
+		WHERE project_id = $1
+	`
+	var estimatedTasks int
+	err := r.pool.QueryRow(ctx, totalsQuery, projectID).Scan(
+		&stats.TotalTasks, &stats.OpenTasks, &stats.CompletedTasks, &stats.CancelledTasks,
+		&stats.OverdueTasks, &stats.TotalStoryPoints, &stats.CompletedStoryPoints,
+		&estimatedTasks, &stats.TotalEstimatedHours, &stats.TotalActualHours, &stats.UnestimatedActualHours,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if estimatedTasks > 0 {
+		variance := stats.TotalActualHours - stats.TotalEstimatedHours
+		percent := variance / stats.TotalEstimatedHours * 100
+		stats.HoursVariance = &variance
+		stats.HoursVariancePercent = &percent
+	}
+
+	if err := r.scanGroupedCounts(ctx, stats.ByStatus, `
+		SELECT status, COUNT(*) FROM tasks
+		WHERE project_id = $1
+		GROUP BY status
+	`, projectID); err != nil {
+		return nil, err
+	}
+
+	if err := r.scanGroupedCounts(ctx, stats.ByPriority, `
+		SELECT priority, COUNT(*) FROM tasks
+		WHERE project_id = $1 AND status NOT IN ('done', 'cancelled')
+		GROUP BY priority
+	`, projectID); err != nil {
+		return nil, err
+	}
+
+	if err := r.scanGroupedCounts(ctx, stats.ByAssignee, `
+		SELECT COALESCE(a.assignee_id::text, 'unassigned'), COUNT(*)
+		FROM tasks t
+		LEFT JOIN LATERAL unnest(t.assignee_ids) AS a(assignee_id) ON TRUE
+		WHERE t.project_id = $1 AND t.status NOT IN ('done', 'cancelled')
+		GROUP BY 1
+	`, projectID); err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// scanGroupedCounts fills dest from a query returning (key, count) rows
+func (r *pgProjectRepository) scanGroupedCounts(ctx context.Context, dest map[string]int, query string, args ...interface{}) error {
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var key string
+		var count int
+		if err := rows.Scan(&key, &count); err != nil {
+			return err
+		}
+		dest[key] = count
+	}
+	return rows.Err()
+}
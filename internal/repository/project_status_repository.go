@@ -0,0 +1,190 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// ProjectStatus is one column of a project's task workflow
+type ProjectStatus struct {
+	ID                 string    `json:"id" db:"id"`
+	ProjectID          string    `json:"projectId" db:"project_id"`
+	Key                string    `json:"key" db:"key"`
+	Name               string    `json:"name" db:"name"`
+	Color              *string   `json:"color,omitempty" db:"color"`
+	Position           int       `json:"position" db:"position"`
+	AllowedTransitions []string  `json:"allowedTransitions" db:"allowed_transitions"`
+	CreatedAt          time.Time `json:"createdAt" db:"created_at"`
+	UpdatedAt          time.Time `json:"updatedAt" db:"updated_at"`
+}
+
+// CanTransitionTo reports whether a task in this status may move to the given status
+func (ps *ProjectStatus) CanTransitionTo(key string) bool {
+	if len(ps.AllowedTransitions) == 0 {
+		return true
+	}
+	for _, allowed := range ps.AllowedTransitions {
+		if allowed == key {
+			return true
+		}
+	}
+	return false
+}
+
+// ProjectStatusRepository interface
+type ProjectStatusRepository interface {
+	Create(ctx context.Context, status *ProjectStatus) error
+	FindByID(ctx context.Context, id string) (*ProjectStatus, error)
+	FindByProjectID(ctx context.Context, projectID string) ([]*ProjectStatus, error)
+	FindByKey(ctx context.Context, projectID, key string) (*ProjectStatus, error)
+	Update(ctx context.Context, status *ProjectStatus) error
+	// Delete removes a status and, in the same transaction, drops its key
+	// from the allowed transitions of the project's other statuses
+	Delete(ctx context.Context, id string) error
+}
+
+// projectStatusRepository implementation
+type projectStatusRepository struct {
+	db *sql.DB
+}
+
+// NewProjectStatusRepository creates a new ProjectStatusRepository
+func NewProjectStatusRepository(db *sql.DB) ProjectStatusRepository {
+	return &projectStatusRepository{db: db}
+}
+
+const projectStatusColumns = `
+	id, project_id, key, name, color, position, allowed_transitions, created_at, updated_at`
+
+// Create inserts a new status
+func (r *projectStatusRepository) Create(ctx context.Context, status *ProjectStatus) error {
+	query := `
+		INSERT INTO project_statuses (project_id, key, name, color, position, allowed_transitions)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at, updated_at`
+
+	return r.db.QueryRowContext(ctx, query,
+		status.ProjectID,
+		status.Key,
+		status.Name,
+		status.Color,
+		status.Position,
+		pq.Array(status.AllowedTransitions),
+	).Scan(&status.ID, &status.CreatedAt, &status.UpdatedAt)
+}
+
+// FindByID retrieves a status by ID
+func (r *projectStatusRepository) FindByID(ctx context.Context, id string) (*ProjectStatus, error) {
+	query := `SELECT ` + projectStatusColumns + ` FROM project_statuses WHERE id = $1`
+	return r.queryOne(ctx, query, id)
+}
+
+// FindByProjectID lists a project's statuses in board order
+func (r *projectStatusRepository) FindByProjectID(ctx context.Context, projectID string) ([]*ProjectStatus, error) {
+	query := `SELECT ` + projectStatusColumns + ` FROM project_statuses WHERE project_id = $1 ORDER BY position ASC, created_at ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var statuses []*ProjectStatus
+	for rows.Next() {
+		status, err := scanProjectStatus(rows)
+		if err != nil {
+			return nil, err
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, rows.Err()
+}
+
+// FindByKey retrieves a project's status by its key
+func (r *projectStatusRepository) FindByKey(ctx context.Context, projectID, key string) (*ProjectStatus, error) {
+	query := `SELECT ` + projectStatusColumns + ` FROM project_statuses WHERE project_id = $1 AND key = $2`
+	return r.queryOne(ctx, query, projectID, key)
+}
+
+// Update saves name, color, position and transitions
+func (r *projectStatusRepository) Update(ctx context.Context, status *ProjectStatus) error {
+	query := `
+		UPDATE project_statuses SET
+			name = $2, color = $3, position = $4, allowed_transitions = $5, updated_at = NOW()
+		WHERE id = $1
+		RETURNING updated_at`
+
+	return r.db.QueryRowContext(ctx, query,
+		status.ID,
+		status.Name,
+		status.Color,
+		status.Position,
+		pq.Array(status.AllowedTransitions),
+	).Scan(&status.UpdatedAt)
+}
+
+// Delete removes a status along with every transition into it. A status
+// whose only allowed target was the deleted one ends up with an empty list,
+// which allows any move.
+func (r *projectStatusRepository) Delete(ctx context.Context, id string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var projectID, key string
+	err = tx.QueryRowContext(ctx,
+		`DELETE FROM project_statuses WHERE id = $1 RETURNING project_id, key`, id,
+	).Scan(&projectID, &key)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE project_statuses
+		SET allowed_transitions = array_remove(allowed_transitions, $2), updated_at = NOW()
+		WHERE project_id = $1 AND $2 = ANY(allowed_transitions)`,
+		projectID, key,
+	); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *projectStatusRepository) queryOne(ctx context.Context, query string, args ...interface{}) (*ProjectStatus, error) {
+	status, err := scanProjectStatus(r.db.QueryRowContext(ctx, query, args...))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+func scanProjectStatus(row recurringTaskScanner) (*ProjectStatus, error) {
+	status := &ProjectStatus{}
+	err := row.Scan(
+		&status.ID,
+		&status.ProjectID,
+		&status.Key,
+		&status.Name,
+		&status.Color,
+		&status.Position,
+		pq.Array(&status.AllowedTransitions),
+		&status.CreatedAt,
+		&status.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return status, nil
+}
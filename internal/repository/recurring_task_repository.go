@@ -0,0 +1,189 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// RecurringTask is a template that spawns a real task on every cadence tick
+type RecurringTask struct {
+	ID          string     `json:"id" db:"id"`
+	ProjectID   string     `json:"projectId" db:"project_id"`
+	Title       string     `json:"title" db:"title"`
+	Description *string    `json:"description,omitempty" db:"description"`
+	Priority    string     `json:"priority" db:"priority"`
+	Type        *string    `json:"type,omitempty" db:"type"`
+	AssigneeIDs []string   `json:"assigneeIds" db:"assignee_ids"`
+	Cadence     string     `json:"cadence" db:"cadence"`
+	NextRunAt   time.Time  `json:"nextRunAt" db:"next_run_at"`
+	LastRunAt   *time.Time `json:"lastRunAt,omitempty" db:"last_run_at"`
+	Active      bool       `json:"active" db:"active"`
+	CreatedBy   *string    `json:"createdBy,omitempty" db:"created_by"`
+	CreatedAt   time.Time  `json:"createdAt" db:"created_at"`
+	UpdatedAt   time.Time  `json:"updatedAt" db:"updated_at"`
+}
+
+// RecurringTaskRepository interface
+type RecurringTaskRepository interface {
+	Create(ctx context.Context, rt *RecurringTask) error
+	FindByID(ctx context.Context, id string) (*RecurringTask, error)
+	FindByProjectID(ctx context.Context, projectID string) ([]*RecurringTask, error)
+	FindDue(ctx context.Context, now time.Time) ([]*RecurringTask, error)
+	// ClaimRun advances next_run_at only if it still equals expectedRunAt.
+	// Returns false when another run already claimed this window.
+	ClaimRun(ctx context.Context, id string, expectedRunAt, nextRunAt time.Time) (bool, error)
+	// ReleaseRun undoes a ClaimRun whose task could not be created, putting
+	// back runAt and lastRunAt if next_run_at is still claimedNextRunAt
+	ReleaseRun(ctx context.Context, id string, claimedNextRunAt, runAt time.Time, lastRunAt *time.Time) error
+	// Deactivate stops a template from producing further tasks
+	Deactivate(ctx context.Context, id string) error
+}
+
+// recurringTaskRepository implementation
+type recurringTaskRepository struct {
+	db *sql.DB
+}
+
+// NewRecurringTaskRepository creates a new RecurringTaskRepository
+func NewRecurringTaskRepository(db *sql.DB) RecurringTaskRepository {
+	return &recurringTaskRepository{db: db}
+}
+
+const recurringTaskColumns = `
+	id, project_id, title, description, priority, type, assignee_ids,
+	cadence, next_run_at, last_run_at, active, created_by, created_at, updated_at`
+
+// Create inserts a new recurring task template
+func (r *recurringTaskRepository) Create(ctx context.Context, rt *RecurringTask) error {
+	query := `
+		INSERT INTO recurring_tasks (
+			project_id, title, description, priority, type, assignee_ids,
+			cadence, next_run_at, active, created_by
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING id, created_at, updated_at`
+
+	return r.db.QueryRowContext(
+		ctx, query,
+		rt.ProjectID,
+		rt.Title,
+		rt.Description,
+		rt.Priority,
+		rt.Type,
+		pq.Array(rt.AssigneeIDs),
+		rt.Cadence,
+		rt.NextRunAt,
+		rt.Active,
+		rt.CreatedBy,
+	).Scan(&rt.ID, &rt.CreatedAt, &rt.UpdatedAt)
+}
+
+// FindByID retrieves a recurring task template by ID
+func (r *recurringTaskRepository) FindByID(ctx context.Context, id string) (*RecurringTask, error) {
+	query := `SELECT ` + recurringTaskColumns + ` FROM recurring_tasks WHERE id = $1`
+
+	rt, err := scanRecurringTask(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return rt, nil
+}
+
+// FindByProjectID lists all templates for a project
+func (r *recurringTaskRepository) FindByProjectID(ctx context.Context, projectID string) ([]*RecurringTask, error) {
+	query := `SELECT ` + recurringTaskColumns + ` FROM recurring_tasks WHERE project_id = $1 ORDER BY created_at ASC`
+	return r.query(ctx, query, projectID)
+}
+
+// FindDue lists active templates whose next run is at or before now
+func (r *recurringTaskRepository) FindDue(ctx context.Context, now time.Time) ([]*RecurringTask, error) {
+	query := `SELECT ` + recurringTaskColumns + ` FROM recurring_tasks WHERE active = TRUE AND next_run_at <= $1 ORDER BY next_run_at ASC`
+	return r.query(ctx, query, now)
+}
+
+// ClaimRun moves the template to its next run window
+func (r *recurringTaskRepository) ClaimRun(ctx context.Context, id string, expectedRunAt, nextRunAt time.Time) (bool, error) {
+	query := `
+		UPDATE recurring_tasks
+		SET next_run_at = $3, last_run_at = NOW(), updated_at = NOW()
+		WHERE id = $1 AND next_run_at = $2`
+
+	result, err := r.db.ExecContext(ctx, query, id, expectedRunAt, nextRunAt)
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows == 1, nil
+}
+
+// ReleaseRun hands a claimed run window back, so the next tick retries it
+func (r *recurringTaskRepository) ReleaseRun(ctx context.Context, id string, claimedNextRunAt, runAt time.Time, lastRunAt *time.Time) error {
+	query := `
+		UPDATE recurring_tasks
+		SET next_run_at = $3, last_run_at = $4, updated_at = NOW()
+		WHERE id = $1 AND next_run_at = $2`
+
+	_, err := r.db.ExecContext(ctx, query, id, claimedNextRunAt, runAt, lastRunAt)
+	return err
+}
+
+// Deactivate marks a template inactive so FindDue no longer returns it
+func (r *recurringTaskRepository) Deactivate(ctx context.Context, id string) error {
+	query := `UPDATE recurring_tasks SET active = FALSE, updated_at = NOW() WHERE id = $1`
+	_, err := r.db.ExecContext(ctx, query, id)
+	return err
+}
+
+func (r *recurringTaskRepository) query(ctx context.Context, query string, args ...interface{}) ([]*RecurringTask, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var templates []*RecurringTask
+	for rows.Next() {
+		rt, err := scanRecurringTask(rows)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, rt)
+	}
+	return templates, rows.Err()
+}
+
+type recurringTaskScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanRecurringTask(row recurringTaskScanner) (*RecurringTask, error) {
+	rt := &RecurringTask{}
+	err := row.Scan(
+		&rt.ID,
+		&rt.ProjectID,
+		&rt.Title,
+		&rt.Description,
+		&rt.Priority,
+		&rt.Type,
+		pq.Array(&rt.AssigneeIDs),
+		&rt.Cadence,
+		&rt.NextRunAt,
+		&rt.LastRunAt,
+		&rt.Active,
+		&rt.CreatedBy,
+		&rt.CreatedAt,
+		&rt.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return rt, nil
+}
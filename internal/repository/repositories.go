@@ -19,6 +19,13 @@ type Repositories struct {
 	ChatRepo         ChatRepository
 	LabelRepo        LabelRepository
 	NotificationRepo NotificationRepository
+	TemplateRepo     TemplateRepository
+
+	NotificationPreferenceRepo NotificationPreferenceRepository
+	CalendarFeedRepo           CalendarFeedRepository
+	WebhookRepo                WebhookRepository
+	SCMIntegrationRepo         SCMIntegrationRepository
+	TaskWatcherRepo            TaskWatcherRepository
 
 	GoalRepo            GoalRepository
 	SprintAnalyticsRepo SprintAnalyticsRepository
@@ -33,6 +40,13 @@ type Repositories struct {
 	TaskActivityRepo   TaskActivityRepository
 	TimeEntryRepo      TimeEntryRepository
 	SprintCommitmentRepo SprintCommitmentRepository
+	RecurringTaskRepo  RecurringTaskRepository
+	WIPLimitRepo       WIPLimitRepository
+	ProjectStatusRepo  ProjectStatusRepository
+	TaskLabelRepo      TaskLabelRepository
+	SavedViewRepo      SavedViewRepository
+	TaskSettingsRepo   TaskSettingsRepository
+	SprintCapacityRepo SprintCapacityRepository
 }
 
 func NewRepositories(pool *pgxpool.Pool, db *sql.DB) *Repositories {
@@ -49,6 +63,13 @@ func NewRepositories(pool *pgxpool.Pool, db *sql.DB) *Repositories {
 		ChatRepo:         NewChatRepository(pool),
 		LabelRepo:        NewLabelRepository(pool),
 		NotificationRepo: NewNotificationRepository(pool),
+		TemplateRepo:     NewTemplateRepository(pool),
+
+		NotificationPreferenceRepo: NewNotificationPreferenceRepository(pool),
+		CalendarFeedRepo:           NewCalendarFeedRepository(pool),
+		WebhookRepo:                NewWebhookRepository(pool),
+		SCMIntegrationRepo:         NewSCMIntegrationRepository(pool),
+		TaskWatcherRepo:            NewTaskWatcherRepository(pool),
 
 		// sql.DB repos (all task-related)
 		SprintRepo:         NewSprintRepository(db),
@@ -62,5 +83,12 @@ func NewRepositories(pool *pgxpool.Pool, db *sql.DB) *Repositories {
 		TaskActivityRepo:   NewTaskActivityRepository(db),
 		TimeEntryRepo:      NewTimeEntryRepository(db),
 		SprintCommitmentRepo: NewSprintCommitmentRepository(db),
+		RecurringTaskRepo:  NewRecurringTaskRepository(db),
+		WIPLimitRepo:       NewWIPLimitRepository(db),
+		ProjectStatusRepo:  NewProjectStatusRepository(db),
+		TaskLabelRepo:      NewTaskLabelRepository(db),
+		SavedViewRepo:      NewSavedViewRepository(db),
+		TaskSettingsRepo:   NewTaskSettingsRepository(db),
+		SprintCapacityRepo: NewSprintCapacityRepository(db),
 	}
 }
\ No newline at end of file
@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+)
+
+// SavedView is a named set of task filters. Private views are only visible to
+// their owner; shared views to every member of the project.
+type SavedView struct {
+	ID        string      `json:"id" db:"id"`
+	ProjectID string      `json:"projectId" db:"project_id"`
+	UserID    string      `json:"userId" db:"user_id"`
+	Name      string      `json:"name" db:"name"`
+	Filters   TaskFilters `json:"filters" db:"filters"`
+	Shared    bool        `json:"shared" db:"shared"`
+	CreatedAt time.Time   `json:"createdAt" db:"created_at"`
+	UpdatedAt time.Time   `json:"updatedAt" db:"updated_at"`
+}
+
+// SavedViewRepository interface
+type SavedViewRepository interface {
+	Create(ctx context.Context, view *SavedView) error
+	FindByID(ctx context.Context, id string) (*SavedView, error)
+	// FindVisible lists the user's own views of the project and the views shared with it
+	FindVisible(ctx context.Context, projectID, userID string) ([]*SavedView, error)
+	Update(ctx context.Context, view *SavedView) error
+	Delete(ctx context.Context, id string) error
+}
+
+// savedViewRepository implementation
+type savedViewRepository struct {
+	db *sql.DB
+}
+
+// NewSavedViewRepository creates a new SavedViewRepository
+func NewSavedViewRepository(db *sql.DB) SavedViewRepository {
+	return &savedViewRepository{db: db}
+}
+
+const savedViewColumns = `id, project_id, user_id, name, filters, shared, created_at, updated_at`
+
+// Create inserts a new view
+func (r *savedViewRepository) Create(ctx context.Context, view *SavedView) error {
+	filters, err := savedViewFiltersJSON(view.Filters)
+	if err != nil {
+		return err
+	}
+	query := `
+		INSERT INTO saved_views (project_id, user_id, name, filters, shared)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at, updated_at`
+
+	return r.db.QueryRowContext(ctx, query, view.ProjectID, view.UserID, view.Name, filters, view.Shared).
+		Scan(&view.ID, &view.CreatedAt, &view.UpdatedAt)
+}
+
+// FindByID retrieves a view by ID
+func (r *savedViewRepository) FindByID(ctx context.Context, id string) (*SavedView, error) {
+	query := `SELECT ` + savedViewColumns + ` FROM saved_views WHERE id = $1`
+	views, err := r.queryViews(ctx, query, id)
+	if err != nil || len(views) == 0 {
+		return nil, err
+	}
+	return views[0], nil
+}
+
+// FindVisible lists own and shared views, own views first
+func (r *savedViewRepository) FindVisible(ctx context.Context, projectID, userID string) ([]*SavedView, error) {
+	query := `
+		SELECT ` + savedViewColumns + `
+		FROM saved_views
+		WHERE project_id = $1 AND (user_id = $2 OR shared)
+		ORDER BY (user_id = $2) DESC, name ASC`
+	return r.queryViews(ctx, query, projectID, userID)
+}
+
+// Update saves the name, filters and sharing of a view
+func (r *savedViewRepository) Update(ctx context.Context, view *SavedView) error {
+	filters, err := savedViewFiltersJSON(view.Filters)
+	if err != nil {
+		return err
+	}
+	query := `
+		UPDATE saved_views SET name = $2, filters = $3, shared = $4, updated_at = NOW()
+		WHERE id = $1
+		RETURNING updated_at`
+
+	return r.db.QueryRowContext(ctx, query, view.ID, view.Name, filters, view.Shared).Scan(&view.UpdatedAt)
+}
+
+// Delete removes a view
+func (r *savedViewRepository) Delete(ctx context.Context, id string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM saved_views WHERE id = $1`, id)
+	return err
+}
+
+func (r *savedViewRepository) queryViews(ctx context.Context, query string, args ...interface{}) ([]*SavedView, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var views []*SavedView
+	for rows.Next() {
+		view := &SavedView{}
+		var filters []byte
+		if err := rows.Scan(
+			&view.ID, &view.ProjectID, &view.UserID, &view.Name, &filters,
+			&view.Shared, &view.CreatedAt, &view.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(filters, &view.Filters); err != nil {
+			return nil, err
+		}
+		views = append(views, view)
+	}
+	return views, rows.Err()
+}
+
+// savedViewFiltersJSON encodes filters without the project, which the view row already holds
+func savedViewFiltersJSON(filters TaskFilters) ([]byte, error) {
+	filters.ProjectID = ""
+	return json.Marshal(filters)
+}
@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// IntegrationToken authenticates source control events for a workspace
+type IntegrationToken struct {
+	WorkspaceID string
+	CreatedBy   string
+	CreatedAt   time.Time
+	LastUsedAt  *time.Time
+}
+
+// ProjectSCMSettings controls whether magic words in commit messages move
+// referenced tasks, and to which status
+type ProjectSCMSettings struct {
+	ProjectID      string    `json:"projectId"`
+	AutoTransition bool      `json:"autoTransition"`
+	DoneStatus     string    `json:"doneStatus"`
+	UpdatedAt      time.Time `json:"updatedAt"`
+}
+
+// SCMIntegrationRepository stores the workspace integration tokens (hashed),
+// per-project settings and the commits already linked to tasks
+type SCMIntegrationRepository interface {
+	// SaveToken replaces any previous token of the workspace
+	SaveToken(ctx context.Context, workspaceID, tokenHash, createdBy string) error
+	// FindByToken returns nil when no workspace has the token. It also records
+	// that the token was used.
+	FindByToken(ctx context.Context, tokenHash string) (*IntegrationToken, error)
+	DeleteToken(ctx context.Context, workspaceID string) error
+
+	// GetProjectSettings returns the defaults when the project has no row
+	GetProjectSettings(ctx context.Context, projectID string) (*ProjectSCMSettings, error)
+	SaveProjectSettings(ctx context.Context, settings *ProjectSCMSettings) error
+
+	// HasReference reports whether the ref is already linked to the task
+	HasReference(ctx context.Context, taskID, ref string) (bool, error)
+	// RecordReference reports false when the ref was already linked to the task
+	RecordReference(ctx context.Context, taskID, ref string) (bool, error)
+}
+
+type pgSCMIntegrationRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewSCMIntegrationRepository(pool *pgxpool.Pool) SCMIntegrationRepository {
+	return &pgSCMIntegrationRepository{pool: pool}
+}
+
+func (r *pgSCMIntegrationRepository) SaveToken(ctx context.Context, workspaceID, tokenHash, createdBy string) error {
+	query := `
+		INSERT INTO workspace_integration_tokens (workspace_id, token_hash, created_by)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (workspace_id) DO UPDATE SET
+			token_hash = EXCLUDED.token_hash, created_by = EXCLUDED.created_by,
+			created_at = NOW(), last_used_at = NULL
+	`
+	_, err := r.pool.Exec(ctx, query, workspaceID, tokenHash, createdBy)
+	return err
+}
+
+func (r *pgSCMIntegrationRepository) FindByToken(ctx context.Context, tokenHash string) (*IntegrationToken, error) {
+	query := `
+		UPDATE workspace_integration_tokens SET last_used_at = NOW()
+		WHERE token_hash = $1
+		RETURNING workspace_id, created_by, created_at, last_used_at
+	`
+	t := &IntegrationToken{}
+	err := r.pool.QueryRow(ctx, query, tokenHash).Scan(&t.WorkspaceID, &t.CreatedBy, &t.CreatedAt, &t.LastUsedAt)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (r *pgSCMIntegrationRepository) DeleteToken(ctx context.Context, workspaceID string) error {
+	_, err := r.pool.Exec(ctx, `DELETE FROM workspace_integration_tokens WHERE workspace_id = $1`, workspaceID)
+	return err
+}
+
+func (r *pgSCMIntegrationRepository) GetProjectSettings(ctx context.Context, projectID string) (*ProjectSCMSettings, error) {
+	query := `
+		SELECT project_id, auto_transition, done_status, updated_at
+		FROM project_scm_settings WHERE project_id = $1
+	`
+	s := &ProjectSCMSettings{}
+	err := r.pool.QueryRow(ctx, query, projectID).Scan(&s.ProjectID, &s.AutoTransition, &s.DoneStatus, &s.UpdatedAt)
+	if err == pgx.ErrNoRows {
+		return &ProjectSCMSettings{ProjectID: projectID, AutoTransition: true, DoneStatus: "done"}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (r *pgSCMIntegrationRepository) SaveProjectSettings(ctx context.Context, settings *ProjectSCMSettings) error {
+	query := `
+		INSERT INTO project_scm_settings (project_id, auto_transition, done_status)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (project_id) DO UPDATE SET
+			auto_transition = EXCLUDED.auto_transition, done_status = EXCLUDED.done_status, updated_at = NOW()
+		RETURNING updated_at
+	`
+	return r.pool.QueryRow(ctx, query, settings.ProjectID, settings.AutoTransition, settings.DoneStatus).
+		Scan(&settings.UpdatedAt)
+}
+
+func (r *pgSCMIntegrationRepository) HasReference(ctx context.Context, taskID, ref string) (bool, error) {
+	var exists bool
+	err := r.pool.QueryRow(ctx, `
+		SELECT EXISTS (SELECT 1 FROM scm_task_references WHERE task_id = $1 AND ref = $2)
+	`, taskID, ref).Scan(&exists)
+	return exists, err
+}
+
+func (r *pgSCMIntegrationRepository) RecordReference(ctx context.Context, taskID, ref string) (bool, error) {
+	tag, err := r.pool.Exec(ctx, `
+		INSERT INTO scm_task_references (task_id, ref) VALUES ($1, $2)
+		ON CONFLICT DO NOTHING
+	`, taskID, ref)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() == 1, nil
+}
@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"html"
+	"strings"
+)
+
+// Search snippets are highlighted by ts_headline with control characters
+// instead of <mark>. The snippet is HTML-escaped first and only then are the
+// markers turned into tags, so content such as <script> reaches clients
+// as text. Marker characters already in the content are stripped before
+// highlighting, so a message cannot forge a highlight.
+const (
+	headlineStart = "\x01"
+	headlineStop  = "\x02"
+)
+
+// headlineSQL returns the ts_headline call for column, matched against the
+// tsquery q.tsq
+func headlineSQL(column string) string {
+	return `ts_headline('english', translate(` + column + `, E'\x01\x02', ''), q.tsq,
+				'StartSel=` + headlineStart + `, StopSel=` + headlineStop + `, MaxWords=30, MinWords=10, MaxFragments=2')`
+}
+
+// markSnippet escapes a headline from headlineSQL and wraps the matches in
+// <mark> tags
+func markSnippet(headline string) string {
+	escaped := html.EscapeString(headline)
+	escaped = strings.ReplaceAll(escaped, headlineStart, "<mark>")
+	return strings.ReplaceAll(escaped, headlineStop, "</mark>")
+}
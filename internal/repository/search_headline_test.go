@@ -0,0 +1,11 @@
+package repository
+
+import "testing"
+
+func TestMarkSnippetEscapesContent(t *testing.T) {
+	headline := `fix the <script>alert("x")</script> ` + headlineStart + "login" + headlineStop + ` & signup`
+	want := `fix the &lt;script&gt;alert(&#34;x&#34;)&lt;/script&gt; <mark>login</mark> &amp; signup`
+	if got := markSnippet(headline); got != want {
+		t.Errorf("markSnippet() = %q, want %q", got, want)
+	}
+}
@@ -182,7 +182,7 @@ func (r *pgSpaceRepository) FindMembers(ctx context.Context, spaceID string) ([]
 		       u.id, u.email, u.name, u.avatar, u.status
 		FROM space_members sm
 		JOIN users u ON sm.user_id = u.id
-		WHERE sm.space_id = $1
+		WHERE sm.space_id = $1 AND u.deleted_at IS NULL
 		ORDER BY sm.joined_at
 	`
 	rows, err := r.pool.Query(ctx, query, spaceID)
@@ -224,7 +224,7 @@ func (r *pgSpaceRepository) FindMember(ctx context.Context, spaceID, userID stri
 }
 
 func (r *pgSpaceRepository) FindMemberUserIDs(ctx context.Context, spaceID string) ([]string, error) {
-	query := `SELECT user_id FROM space_members WHERE space_id = $1`
+	query := `SELECT m.user_id FROM space_members m JOIN users u ON m.user_id = u.id WHERE m.space_id = $1 AND u.deleted_at IS NULL`
 	rows, err := r.pool.Query(ctx, query, spaceID)
 	if err != nil {
 		return nil, err
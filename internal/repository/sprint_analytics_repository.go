@@ -69,6 +69,17 @@ type CycleTimeStats struct {
 	CreatedAt        time.Time  `json:"createdAt"`
 }
 
+// BurndownSnapshot records remaining story points of a sprint at the end of a day
+type BurndownSnapshot struct {
+	ID              string    `json:"id" db:"id"`
+	SprintID        string    `json:"sprintId" db:"sprint_id"`
+	SnapshotDate    time.Time `json:"snapshotDate" db:"snapshot_date"`
+	TotalPoints     int       `json:"totalPoints" db:"total_points"`
+	CompletedPoints int       `json:"completedPoints" db:"completed_points"`
+	RemainingPoints int       `json:"remainingPoints" db:"remaining_points"`
+	CreatedAt       time.Time `json:"createdAt" db:"created_at"`
+}
+
 type VelocityTrend struct {
 	Sprints         []VelocityHistory `json:"sprints"`
 	AverageVelocity float64           `json:"averageVelocity"`
@@ -126,6 +137,10 @@ type SprintAnalyticsRepository interface {
 	GetAverageLeadTime(ctx context.Context, projectID string, days int) (float64, error)
 	GetTaskStatusHistory(ctx context.Context, taskID string) ([]*TaskStatusHistory, error)
 
+	// Burndown
+	SaveBurndownSnapshot(ctx context.Context, snapshot *BurndownSnapshot) error
+	GetBurndownSnapshots(ctx context.Context, sprintID string) ([]*BurndownSnapshot, error)
+
 	// Gantt Chart
 	GetGanttData(ctx context.Context, projectID string, sprintID *string) (*GanttData, error)
 }
@@ -417,6 +432,57 @@ func (r *sprintAnalyticsRepository) SaveVelocityHistory(ctx context.Context, vh
 	).Scan(&vh.ID, &vh.CreatedAt)
 }
 
+// ============================================
+// BURNDOWN
+// ============================================
+
+// SaveBurndownSnapshot upserts the snapshot for a sprint and day
+func (r *sprintAnalyticsRepository) SaveBurndownSnapshot(ctx context.Context, snapshot *BurndownSnapshot) error {
+	query := `
+		INSERT INTO burndown_snapshots (
+			sprint_id, snapshot_date, total_points, completed_points, remaining_points
+		) VALUES ($1, $2::date, $3, $4, $5)
+		ON CONFLICT (sprint_id, snapshot_date) DO UPDATE SET
+			total_points = EXCLUDED.total_points,
+			completed_points = EXCLUDED.completed_points,
+			remaining_points = EXCLUDED.remaining_points,
+			created_at = NOW()
+		RETURNING id, created_at`
+
+	return r.db.QueryRowContext(ctx, query,
+		snapshot.SprintID, snapshot.SnapshotDate.Format("2006-01-02"),
+		snapshot.TotalPoints, snapshot.CompletedPoints, snapshot.RemainingPoints,
+	).Scan(&snapshot.ID, &snapshot.CreatedAt)
+}
+
+// GetBurndownSnapshots returns all snapshots of a sprint ordered by day
+func (r *sprintAnalyticsRepository) GetBurndownSnapshots(ctx context.Context, sprintID string) ([]*BurndownSnapshot, error) {
+	query := `
+		SELECT id, sprint_id, snapshot_date, total_points, completed_points, remaining_points, created_at
+		FROM burndown_snapshots
+		WHERE sprint_id = $1
+		ORDER BY snapshot_date ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, sprintID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var snapshots []*BurndownSnapshot
+	for rows.Next() {
+		snap := &BurndownSnapshot{}
+		if err := rows.Scan(
+			&snap.ID, &snap.SprintID, &snap.SnapshotDate,
+			&snap.TotalPoints, &snap.CompletedPoints, &snap.RemainingPoints, &snap.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, snap)
+	}
+	return snapshots, rows.Err()
+}
+
 // ============================================
 // CYCLE TIME
 // ============================================
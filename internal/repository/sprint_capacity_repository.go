@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// SprintCapacity is the number of hours a member can work during a sprint
+type SprintCapacity struct {
+	SprintID       string    `json:"sprintId" db:"sprint_id"`
+	UserID         string    `json:"userId" db:"user_id"`
+	HoursAvailable float64   `json:"hoursAvailable" db:"hours_available"`
+	UpdatedBy      *string   `json:"updatedBy,omitempty" db:"updated_by"`
+	UpdatedAt      time.Time `json:"updatedAt" db:"updated_at"`
+}
+
+// SprintCapacityRepository interface
+type SprintCapacityRepository interface {
+	FindBySprintID(ctx context.Context, sprintID string) ([]*SprintCapacity, error)
+	// Set creates or replaces the member's capacity for the sprint
+	Set(ctx context.Context, capacity *SprintCapacity) error
+	Delete(ctx context.Context, sprintID, userID string) error
+}
+
+// sprintCapacityRepository implementation
+type sprintCapacityRepository struct {
+	db *sql.DB
+}
+
+// NewSprintCapacityRepository creates a new SprintCapacityRepository
+func NewSprintCapacityRepository(db *sql.DB) SprintCapacityRepository {
+	return &sprintCapacityRepository{db: db}
+}
+
+func (r *sprintCapacityRepository) FindBySprintID(ctx context.Context, sprintID string) ([]*SprintCapacity, error) {
+	query := `
+		SELECT sprint_id, user_id, hours_available, updated_by, updated_at
+		FROM sprint_capacity
+		WHERE sprint_id = $1
+		ORDER BY updated_at`
+
+	rows, err := r.db.QueryContext(ctx, query, sprintID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var capacities []*SprintCapacity
+	for rows.Next() {
+		c := &SprintCapacity{}
+		if err := rows.Scan(&c.SprintID, &c.UserID, &c.HoursAvailable, &c.UpdatedBy, &c.UpdatedAt); err != nil {
+			return nil, err
+		}
+		capacities = append(capacities, c)
+	}
+	return capacities, rows.Err()
+}
+
+func (r *sprintCapacityRepository) Set(ctx context.Context, capacity *SprintCapacity) error {
+	query := `
+		INSERT INTO sprint_capacity (sprint_id, user_id, hours_available, updated_by)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (sprint_id, user_id) DO UPDATE SET
+			hours_available = EXCLUDED.hours_available,
+			updated_by = EXCLUDED.updated_by,
+			updated_at = NOW()
+		RETURNING updated_at`
+
+	return r.db.QueryRowContext(ctx, query,
+		capacity.SprintID, capacity.UserID, capacity.HoursAvailable, capacity.UpdatedBy,
+	).Scan(&capacity.UpdatedAt)
+}
+
+func (r *sprintCapacityRepository) Delete(ctx context.Context, sprintID, userID string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM sprint_capacity WHERE sprint_id = $1 AND user_id = $2`, sprintID, userID)
+	return err
+}
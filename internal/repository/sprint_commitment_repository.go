@@ -31,6 +31,24 @@ type SprintScopeChange struct {
 	ChangedAt   time.Time `json:"changedAt" db:"changed_at"`
 }
 
+// Sprint membership actions
+const (
+	SprintMembershipAdded       = "added"
+	SprintMembershipRemoved     = "removed"
+	SprintMembershipCarriedOver = "carried_over" // left unfinished when the sprint was completed
+)
+
+// SprintMembershipChange records a task entering or leaving a sprint
+type SprintMembershipChange struct {
+	ID          string    `json:"id" db:"id"`
+	SprintID    string    `json:"sprintId" db:"sprint_id"`
+	TaskID      string    `json:"taskId" db:"task_id"`
+	Action      string    `json:"action" db:"action"`
+	StoryPoints int       `json:"storyPoints" db:"story_points"`
+	ChangedBy   *string   `json:"changedBy,omitempty" db:"changed_by"`
+	ChangedAt   time.Time `json:"changedAt" db:"changed_at"`
+}
+
 // ============================================
 // INTERFACE
 // ============================================
@@ -45,6 +63,10 @@ type SprintCommitmentRepository interface {
 	GetScopeChanges(ctx context.Context, sprintID string) ([]*SprintScopeChange, error)
 	GetAddedTasksCount(ctx context.Context, sprintID string) (tasks int, points int, err error)
 	GetRemovedTasksCount(ctx context.Context, sprintID string) (tasks int, points int, err error)
+
+	// Membership history
+	RecordMembershipChanges(ctx context.Context, changes []*SprintMembershipChange) error
+	GetMembershipHistory(ctx context.Context, sprintID string) ([]*SprintMembershipChange, error)
 	
 	// Status history
 	RecordStatusChange(ctx context.Context, taskID, fromStatus, toStatus string, changedBy *string) error
@@ -196,4 +218,56 @@ func (r *sprintCommitmentRepository) GetTaskStatusHistory(ctx context.Context, t
 		history = append(history, h)
 	}
 	return history, rows.Err()
-}
\ No newline at end of file
+}
+
+// RecordMembershipChanges inserts the changes in one statement
+func (r *sprintCommitmentRepository) RecordMembershipChanges(ctx context.Context, changes []*SprintMembershipChange) error {
+	if len(changes) == 0 {
+		return nil
+	}
+	sprintIDs := make([]string, len(changes))
+	taskIDs := make([]string, len(changes))
+	actions := make([]string, len(changes))
+	points := make([]int64, len(changes))
+	changedBy := make([]sql.NullString, len(changes))
+	for i, c := range changes {
+		sprintIDs[i], taskIDs[i], actions[i], points[i] = c.SprintID, c.TaskID, c.Action, int64(c.StoryPoints)
+		if c.ChangedBy != nil {
+			changedBy[i] = sql.NullString{String: *c.ChangedBy, Valid: true}
+		}
+	}
+
+	query := `
+		INSERT INTO sprint_task_history (sprint_id, task_id, action, story_points, changed_by)
+		SELECT * FROM UNNEST($1::uuid[], $2::uuid[], $3::varchar[], $4::int[], $5::uuid[])`
+
+	_, err := r.db.ExecContext(ctx, query,
+		pq.Array(sprintIDs), pq.Array(taskIDs), pq.Array(actions), pq.Array(points), pq.Array(changedBy),
+	)
+	return err
+}
+
+// GetMembershipHistory lists a sprint's membership changes, oldest first
+func (r *sprintCommitmentRepository) GetMembershipHistory(ctx context.Context, sprintID string) ([]*SprintMembershipChange, error) {
+	query := `
+		SELECT id, sprint_id, task_id, action, story_points, changed_by, changed_at
+		FROM sprint_task_history
+		WHERE sprint_id = $1
+		ORDER BY changed_at ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, sprintID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var history []*SprintMembershipChange
+	for rows.Next() {
+		c := &SprintMembershipChange{}
+		if err := rows.Scan(&c.ID, &c.SprintID, &c.TaskID, &c.Action, &c.StoryPoints, &c.ChangedBy, &c.ChangedAt); err != nil {
+			return nil, err
+		}
+		history = append(history, c)
+	}
+	return history, rows.Err()
+}
@@ -27,6 +27,9 @@ type SprintRepository interface {
 	FindByProjectID(ctx context.Context, projectID string) ([]*Sprint, error)
 	Update(ctx context.Context, sprint *Sprint) error
 	UpdateStatus(ctx context.Context, id, status string) error
+	// CompleteIfExpired marks the sprint completed only while it is active and
+	// past its end date, and reports whether it did
+	CompleteIfExpired(ctx context.Context, id string) (bool, error)
 	Delete(ctx context.Context, id string) error
 	FindActiveSprint(ctx context.Context, projectID string) (*Sprint, error)
 	querySprints(ctx context.Context, query string, args ...interface{}) ([]*Sprint, error)
@@ -189,6 +192,24 @@ func (r *sprintRepository) UpdateStatus(ctx context.Context, id, status string)
 	return err
 }
 
+// CompleteIfExpired completes an expired active sprint. The condition is
+// checked in the UPDATE itself, so of two concurrent callers only one wins.
+func (r *sprintRepository) CompleteIfExpired(ctx context.Context, id string) (bool, error) {
+	query := `
+		UPDATE sprints SET status = 'completed', updated_at = NOW()
+		WHERE id = $1 AND status = 'active' AND end_date < NOW()
+		RETURNING id`
+	var completedID string
+	err := r.db.QueryRowContext(ctx, query, id).Scan(&completedID)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
 // Delete removes a sprint
 func (r *sprintRepository) Delete(ctx context.Context, id string) error {
 	query := `DELETE FROM sprints WHERE id = $1`
@@ -218,9 +239,10 @@ func (r *sprintRepository) FindSprintsEndingSoon(ctx context.Context, within tim
 	return r.querySprints(ctx, query, within.String())
 }
 
-// FindExpiredSprints returns sprints whose end_date has passed but not completed
+// FindExpiredSprints returns active sprints whose end_date has passed.
+// Sprints still in planning are left for their team to start or reschedule.
 func (r *sprintRepository) FindExpiredSprints(ctx context.Context) ([]*Sprint, error) {
-	query := `SELECT id, name, goal, project_id, status, start_date, end_date, created_at, updated_at, created_by FROM sprints WHERE end_date < NOW() AND status != 'completed' ORDER BY end_date ASC`
+	query := `SELECT id, name, goal, project_id, status, start_date, end_date, created_at, updated_at, created_by FROM sprints WHERE end_date < NOW() AND status = 'active' ORDER BY end_date ASC`
 	return r.querySprints(ctx, query)
 }
 
@@ -18,6 +18,7 @@ type TaskAttachment struct {
 	FileURL    string    `json:"fileUrl" db:"file_url"`
 	FileSize   int64     `json:"fileSize" db:"file_size"`
 	MimeType   string    `json:"mimeType" db:"mime_type"`
+	StorageKey *string   `json:"-" db:"storage_key"` // nil for external links
 	CreatedAt  time.Time `json:"createdAt" db:"created_at"`
 	User       *User     `json:"user,omitempty"` // populated via join
 }
@@ -27,6 +28,13 @@ type TaskAttachmentRepository interface {
 	FindByTaskID(ctx context.Context, taskID string) ([]*TaskAttachment, error)
 	FindByID(ctx context.Context, id string) (*TaskAttachment, error)
 	Delete(ctx context.Context, id string) error
+	// TotalStoredSizeInTaskWorkspace sums the stored (uploaded) attachments of
+	// every task in the same workspace as taskID
+	TotalStoredSizeInTaskWorkspace(ctx context.Context, taskID string) (int64, error)
+	// TotalStoredSizeInProjectWorkspace is the same sum for the workspace
+	// that owns projectID
+	TotalStoredSizeInProjectWorkspace(ctx context.Context, projectID string) (int64, error)
+	CountByFileURL(ctx context.Context, fileURL string) (int, error)
 }
 
 // ============================================
@@ -43,19 +51,19 @@ func NewTaskAttachmentRepository(db *sql.DB) TaskAttachmentRepository {
 
 func (r *taskAttachmentRepository) Create(ctx context.Context, attachment *TaskAttachment) error {
 	query := `
-		INSERT INTO task_attachments (id, task_id, user_id, filename, file_url, file_size, mime_type, created_at)
-		VALUES (gen_random_uuid(), $1, $2, $3, $4, $5, $6, NOW())
+		INSERT INTO task_attachments (id, task_id, user_id, filename, file_url, file_size, mime_type, storage_key, created_at)
+		VALUES (gen_random_uuid(), $1, $2, $3, $4, $5, $6, $7, NOW())
 		RETURNING id, created_at`
 	
 	return r.db.QueryRowContext(ctx, query,
 		attachment.TaskID, attachment.UserID, attachment.Filename,
-		attachment.FileURL, attachment.FileSize, attachment.MimeType,
+		attachment.FileURL, attachment.FileSize, attachment.MimeType, attachment.StorageKey,
 	).Scan(&attachment.ID, &attachment.CreatedAt)
 }
 
 func (r *taskAttachmentRepository) FindByTaskID(ctx context.Context, taskID string) ([]*TaskAttachment, error) {
 	query := `
-		SELECT a.id, a.task_id, a.user_id, a.filename, a.file_url, a.file_size, a.mime_type, a.created_at
+		SELECT a.id, a.task_id, a.user_id, a.filename, a.file_url, a.file_size, a.mime_type, a.storage_key, a.created_at
 		FROM task_attachments a
 		WHERE a.task_id = $1
 		ORDER BY a.created_at DESC`
@@ -71,7 +79,7 @@ func (r *taskAttachmentRepository) FindByTaskID(ctx context.Context, taskID stri
 		a := &TaskAttachment{}
 		err := rows.Scan(
 			&a.ID, &a.TaskID, &a.UserID, &a.Filename,
-			&a.FileURL, &a.FileSize, &a.MimeType, &a.CreatedAt,
+			&a.FileURL, &a.FileSize, &a.MimeType, &a.StorageKey, &a.CreatedAt,
 		)
 		if err != nil {
 			return nil, err
@@ -83,14 +91,14 @@ func (r *taskAttachmentRepository) FindByTaskID(ctx context.Context, taskID stri
 
 func (r *taskAttachmentRepository) FindByID(ctx context.Context, id string) (*TaskAttachment, error) {
 	query := `
-		SELECT id, task_id, user_id, filename, file_url, file_size, mime_type, created_at
+		SELECT id, task_id, user_id, filename, file_url, file_size, mime_type, storage_key, created_at
 		FROM task_attachments
 		WHERE id = $1`
 	
 	a := &TaskAttachment{}
 	err := r.db.QueryRowContext(ctx, query, id).Scan(
 		&a.ID, &a.TaskID, &a.UserID, &a.Filename,
-		&a.FileURL, &a.FileSize, &a.MimeType, &a.CreatedAt,
+		&a.FileURL, &a.FileSize, &a.MimeType, &a.StorageKey, &a.CreatedAt,
 	)
 	
 	if err == sql.ErrNoRows {
@@ -106,4 +114,51 @@ func (r *taskAttachmentRepository) Delete(ctx context.Context, id string) error
 	query := `DELETE FROM task_attachments WHERE id = $1`
 	_, err := r.db.ExecContext(ctx, query, id)
 	return err
-}
\ No newline at end of file
+}
+
+func (r *taskAttachmentRepository) TotalStoredSizeInTaskWorkspace(ctx context.Context, taskID string) (int64, error) {
+	query := `
+		SELECT COALESCE(SUM(a.file_size), 0)
+		FROM task_attachments a
+		JOIN tasks t ON t.id = a.task_id
+		JOIN projects p ON p.id = t.project_id
+		JOIN spaces s ON s.id = p.space_id
+		WHERE a.storage_key IS NOT NULL
+		  AND s.workspace_id = (
+			SELECT s2.workspace_id
+			FROM tasks t2
+			JOIN projects p2 ON p2.id = t2.project_id
+			JOIN spaces s2 ON s2.id = p2.space_id
+			WHERE t2.id = $1
+		  )`
+
+	var total int64
+	err := r.db.QueryRowContext(ctx, query, taskID).Scan(&total)
+	return total, err
+}
+
+func (r *taskAttachmentRepository) TotalStoredSizeInProjectWorkspace(ctx context.Context, projectID string) (int64, error) {
+	query := `
+		SELECT COALESCE(SUM(a.file_size), 0)
+		FROM task_attachments a
+		JOIN tasks t ON t.id = a.task_id
+		JOIN projects p ON p.id = t.project_id
+		JOIN spaces s ON s.id = p.space_id
+		WHERE a.storage_key IS NOT NULL
+		  AND s.workspace_id = (
+			SELECT s2.workspace_id
+			FROM projects p2
+			JOIN spaces s2 ON s2.id = p2.space_id
+			WHERE p2.id = $1
+		  )`
+
+	var total int64
+	err := r.db.QueryRowContext(ctx, query, projectID).Scan(&total)
+	return total, err
+}
+
+func (r *taskAttachmentRepository) CountByFileURL(ctx context.Context, fileURL string) (int, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM task_attachments WHERE file_url = $1`, fileURL).Scan(&count)
+	return count, err
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"database/sql"
 	"time"
+
+	"github.com/lib/pq"
 )
 
 // TaskChecklist model
@@ -11,6 +13,7 @@ type TaskChecklist struct {
 	ID        string            `json:"id" db:"id"`
 	TaskID    string            `json:"taskId" db:"task_id"`
 	Title     string            `json:"title" db:"title"`
+	Position  int               `json:"position" db:"position"`
 	Items     []*ChecklistItem  `json:"items,omitempty"`
 	CreatedAt time.Time         `json:"createdAt" db:"created_at"`
 	UpdatedAt time.Time         `json:"updatedAt" db:"updated_at"`
@@ -21,13 +24,23 @@ type ChecklistItem struct {
 	ID          string     `json:"id" db:"id"`
 	ChecklistID string     `json:"checklistId" db:"checklist_id"`
 	Content     string     `json:"content" db:"content"`
-	Completed   bool       `json:"completed" db:"completed"`
+	Completed   bool       `json:"completed" db:"is_completed"`
 	AssigneeID  *string    `json:"assigneeId,omitempty" db:"assignee_id"`
 	Position    int        `json:"position" db:"position"`
 	CreatedAt   time.Time  `json:"createdAt" db:"created_at"`
 	UpdatedAt   time.Time  `json:"updatedAt" db:"updated_at"`
 }
 
+// AssignedChecklistItem is a checklist item with the checklist and task it belongs to
+type AssignedChecklistItem struct {
+	ChecklistItem
+	ChecklistTitle string `json:"checklistTitle"`
+	TaskID         string `json:"taskId"`
+	TaskTitle      string `json:"taskTitle"`
+	TaskKey        string `json:"taskKey"`
+	ProjectID      string `json:"projectId"`
+}
+
 // TaskChecklistRepository interface
 type TaskChecklistRepository interface {
 	// Checklist operations
@@ -44,8 +57,24 @@ type TaskChecklistRepository interface {
 	UpdateItem(ctx context.Context, item *ChecklistItem) error
 	ToggleItem(ctx context.Context, id string) error
 	DeleteItem(ctx context.Context, id string) error
+	FindOpenItemsByAssignee(ctx context.Context, assigneeID string, projectIDs []string) ([]*AssignedChecklistItem, error)
+
+	// Ordering
+	UpdateChecklistPosition(ctx context.Context, id string, position int) error
+	UpdateItemPosition(ctx context.Context, id string, position int) error
+	RenumberChecklists(ctx context.Context, taskID string, gap int) error
+	RenumberItems(ctx context.Context, checklistID string, gap int) error
 }
 
+// ChecklistPositionGap spaces new checklists and items so they can be moved
+// between neighbours without renumbering
+const ChecklistPositionGap = 1024
+
+const (
+	checklistColumns     = `id, task_id, title, position, created_at, updated_at`
+	checklistItemColumns = `id, checklist_id, content, is_completed, assignee_id, position, created_at, updated_at`
+)
+
 // taskChecklistRepository implementation
 type taskChecklistRepository struct {
 	db *sql.DB
@@ -60,31 +89,35 @@ func NewTaskChecklistRepository(db *sql.DB) TaskChecklistRepository {
 // CHECKLIST OPERATIONS
 // ============================================
 
-// CreateChecklist inserts a new checklist
+// CreateChecklist inserts a new checklist at the end of the task's checklists
 func (r *taskChecklistRepository) CreateChecklist(ctx context.Context, checklist *TaskChecklist) error {
 	query := `
-		INSERT INTO task_checklists (
-			id, task_id, title, created_at, updated_at
+		INSERT INTO checklists (
+			id, task_id, title, position, created_at, updated_at
 		) VALUES (
-			gen_random_uuid(), $1, $2, NOW(), NOW()
-		) RETURNING id, created_at, updated_at`
+			gen_random_uuid(), $1, $2,
+			COALESCE((SELECT MAX(position) FROM checklists WHERE task_id = $1), 0) + $3,
+			NOW(), NOW()
+		) RETURNING id, position, created_at, updated_at`
 
 	return r.db.QueryRowContext(
 		ctx, query,
 		checklist.TaskID,
 		checklist.Title,
-	).Scan(&checklist.ID, &checklist.CreatedAt, &checklist.UpdatedAt)
+		ChecklistPositionGap,
+	).Scan(&checklist.ID, &checklist.Position, &checklist.CreatedAt, &checklist.UpdatedAt)
 }
 
 // FindChecklistByID retrieves a checklist by ID
 func (r *taskChecklistRepository) FindChecklistByID(ctx context.Context, id string) (*TaskChecklist, error) {
-	query := `SELECT * FROM task_checklists WHERE id = $1`
+	query := `SELECT ` + checklistColumns + ` FROM checklists WHERE id = $1`
 
 	checklist := &TaskChecklist{}
 	err := r.db.QueryRowContext(ctx, query, id).Scan(
 		&checklist.ID,
 		&checklist.TaskID,
 		&checklist.Title,
+		&checklist.Position,
 		&checklist.CreatedAt,
 		&checklist.UpdatedAt,
 	)
@@ -108,7 +141,7 @@ func (r *taskChecklistRepository) FindChecklistByID(ctx context.Context, id stri
 
 // FindByTaskID retrieves all checklists for a task
 func (r *taskChecklistRepository) FindByTaskID(ctx context.Context, taskID string) ([]*TaskChecklist, error) {
-	query := `SELECT * FROM task_checklists WHERE task_id = $1 ORDER BY created_at ASC`
+	query := `SELECT ` + checklistColumns + ` FROM checklists WHERE task_id = $1 ORDER BY position ASC, created_at ASC`
 
 	rows, err := r.db.QueryContext(ctx, query, taskID)
 	if err != nil {
@@ -123,6 +156,7 @@ func (r *taskChecklistRepository) FindByTaskID(ctx context.Context, taskID strin
 			&checklist.ID,
 			&checklist.TaskID,
 			&checklist.Title,
+			&checklist.Position,
 			&checklist.CreatedAt,
 			&checklist.UpdatedAt,
 		)
@@ -146,7 +180,7 @@ func (r *taskChecklistRepository) FindByTaskID(ctx context.Context, taskID strin
 // UpdateChecklist updates an existing checklist
 func (r *taskChecklistRepository) UpdateChecklist(ctx context.Context, checklist *TaskChecklist) error {
 	query := `
-		UPDATE task_checklists SET
+		UPDATE checklists SET
 			title = $2,
 			updated_at = NOW()
 		WHERE id = $1
@@ -168,7 +202,7 @@ func (r *taskChecklistRepository) DeleteChecklist(ctx context.Context, id string
 	}
 
 	// Delete checklist
-	query := `DELETE FROM task_checklists WHERE id = $1`
+	query := `DELETE FROM checklists WHERE id = $1`
 	_, err = r.db.ExecContext(ctx, query, id)
 	return err
 }
@@ -177,14 +211,14 @@ func (r *taskChecklistRepository) DeleteChecklist(ctx context.Context, id string
 // CHECKLIST ITEM OPERATIONS
 // ============================================
 
-// CreateItem inserts a new checklist item
+// CreateItem inserts a new checklist item at the end of its checklist
 func (r *taskChecklistRepository) CreateItem(ctx context.Context, item *ChecklistItem) error {
 	query := `
 		INSERT INTO checklist_items (
-			id, checklist_id, content, completed, assignee_id, position, created_at, updated_at
+			id, checklist_id, content, is_completed, assignee_id, position, created_at, updated_at
 		) VALUES (
-			gen_random_uuid(), $1, $2, false, $3, 
-			COALESCE((SELECT MAX(position) + 1 FROM checklist_items WHERE checklist_id = $1), 0),
+			gen_random_uuid(), $1, $2, false, $3,
+			COALESCE((SELECT MAX(position) FROM checklist_items WHERE checklist_id = $1), 0) + $4,
 			NOW(), NOW()
 		) RETURNING id, position, created_at, updated_at`
 
@@ -193,12 +227,13 @@ func (r *taskChecklistRepository) CreateItem(ctx context.Context, item *Checklis
 		item.ChecklistID,
 		item.Content,
 		item.AssigneeID,
+		ChecklistPositionGap,
 	).Scan(&item.ID, &item.Position, &item.CreatedAt, &item.UpdatedAt)
 }
 
 // FindItemByID retrieves a checklist item by ID
 func (r *taskChecklistRepository) FindItemByID(ctx context.Context, id string) (*ChecklistItem, error) {
-	query := `SELECT * FROM checklist_items WHERE id = $1`
+	query := `SELECT ` + checklistItemColumns + ` FROM checklist_items WHERE id = $1`
 
 	item := &ChecklistItem{}
 	err := r.db.QueryRowContext(ctx, query, id).Scan(
@@ -224,7 +259,7 @@ func (r *taskChecklistRepository) FindItemByID(ctx context.Context, id string) (
 
 // FindItemsByChecklistID retrieves all items for a checklist
 func (r *taskChecklistRepository) FindItemsByChecklistID(ctx context.Context, checklistID string) ([]*ChecklistItem, error) {
-	query := `SELECT * FROM checklist_items WHERE checklist_id = $1 ORDER BY position ASC`
+	query := `SELECT ` + checklistItemColumns + ` FROM checklist_items WHERE checklist_id = $1 ORDER BY position ASC, created_at ASC`
 
 	rows, err := r.db.QueryContext(ctx, query, checklistID)
 	if err != nil {
@@ -259,7 +294,8 @@ func (r *taskChecklistRepository) UpdateItem(ctx context.Context, item *Checklis
 	query := `
 		UPDATE checklist_items SET
 			content = $2,
-			completed = $3,
+			is_completed = $3,
+			completed_at = CASE WHEN $3 THEN COALESCE(completed_at, NOW()) END,
 			assignee_id = $4,
 			position = $5,
 			updated_at = NOW()
@@ -280,7 +316,8 @@ func (r *taskChecklistRepository) UpdateItem(ctx context.Context, item *Checklis
 func (r *taskChecklistRepository) ToggleItem(ctx context.Context, id string) error {
 	query := `
 		UPDATE checklist_items SET
-			completed = NOT completed,
+			is_completed = NOT is_completed,
+			completed_at = CASE WHEN is_completed THEN NULL ELSE NOW() END,
 			updated_at = NOW()
 		WHERE id = $1`
 
@@ -293,4 +330,104 @@ func (r *taskChecklistRepository) DeleteItem(ctx context.Context, id string) err
 	query := `DELETE FROM checklist_items WHERE id = $1`
 	_, err := r.db.ExecContext(ctx, query, id)
 	return err
-}
\ No newline at end of file
+}
+
+// FindOpenItemsByAssignee returns the incomplete items assigned to a user on tasks of the given projects
+func (r *taskChecklistRepository) FindOpenItemsByAssignee(ctx context.Context, assigneeID string, projectIDs []string) ([]*AssignedChecklistItem, error) {
+	if len(projectIDs) == 0 {
+		return []*AssignedChecklistItem{}, nil
+	}
+
+	query := `
+		SELECT
+			i.id, i.checklist_id, i.content, i.is_completed, i.assignee_id, i.position,
+			i.created_at, i.updated_at,
+			c.title, t.id, t.title, t.project_id,
+			COALESCE(p.key || '-' || t.number, t.id::text)
+		FROM checklist_items i
+		JOIN checklists c ON c.id = i.checklist_id
+		JOIN tasks t ON t.id = c.task_id
+		JOIN projects p ON p.id = t.project_id
+		WHERE i.assignee_id = $1
+		  AND NOT i.is_completed
+		  AND t.project_id = ANY($2)
+		ORDER BY t.due_date ASC NULLS LAST, t.id, c.position ASC, i.position ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, assigneeID, pq.Array(projectIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []*AssignedChecklistItem{}
+	for rows.Next() {
+		item := &AssignedChecklistItem{}
+		err := rows.Scan(
+			&item.ID,
+			&item.ChecklistID,
+			&item.Content,
+			&item.Completed,
+			&item.AssigneeID,
+			&item.Position,
+			&item.CreatedAt,
+			&item.UpdatedAt,
+			&item.ChecklistTitle,
+			&item.TaskID,
+			&item.TaskTitle,
+			&item.ProjectID,
+			&item.TaskKey,
+		)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	return items, rows.Err()
+}
+
+// ============================================
+// ORDERING
+// ============================================
+
+// UpdateChecklistPosition moves a checklist within its task
+func (r *taskChecklistRepository) UpdateChecklistPosition(ctx context.Context, id string, position int) error {
+	query := `UPDATE checklists SET position = $2, updated_at = NOW() WHERE id = $1`
+	_, err := r.db.ExecContext(ctx, query, id, position)
+	return err
+}
+
+// UpdateItemPosition moves an item within its checklist
+func (r *taskChecklistRepository) UpdateItemPosition(ctx context.Context, id string, position int) error {
+	query := `UPDATE checklist_items SET position = $2, updated_at = NOW() WHERE id = $1`
+	_, err := r.db.ExecContext(ctx, query, id, position)
+	return err
+}
+
+// RenumberChecklists re-spaces the positions of a task's checklists by gap, keeping their order
+func (r *taskChecklistRepository) RenumberChecklists(ctx context.Context, taskID string, gap int) error {
+	query := `
+		UPDATE checklists c SET position = ordered.rn * $2, updated_at = NOW()
+		FROM (
+			SELECT id, ROW_NUMBER() OVER (ORDER BY position ASC, created_at ASC) AS rn
+			FROM checklists
+			WHERE task_id = $1
+		) ordered
+		WHERE c.id = ordered.id`
+	_, err := r.db.ExecContext(ctx, query, taskID, gap)
+	return err
+}
+
+// RenumberItems re-spaces the positions of a checklist's items by gap, keeping their order
+func (r *taskChecklistRepository) RenumberItems(ctx context.Context, checklistID string, gap int) error {
+	query := `
+		UPDATE checklist_items i SET position = ordered.rn * $2, updated_at = NOW()
+		FROM (
+			SELECT id, ROW_NUMBER() OVER (ORDER BY position ASC, created_at ASC) AS rn
+			FROM checklist_items
+			WHERE checklist_id = $1
+		) ordered
+		WHERE i.id = ordered.id`
+	_, err := r.db.ExecContext(ctx, query, checklistID, gap)
+	return err
+}
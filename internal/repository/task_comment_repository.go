@@ -10,13 +10,40 @@ import (
 
 // TaskComment model
 type TaskComment struct {
-	ID             string    `json:"id" db:"id"`
-	TaskID         string    `json:"taskId" db:"task_id"`
-	UserID         string    `json:"userId" db:"user_id"`
-	Content        string    `json:"content" db:"content"`
-	MentionedUsers []string  `json:"mentionedUsers" db:"mentioned_users"`
-	CreatedAt      time.Time `json:"createdAt" db:"created_at"`
-	UpdatedAt      time.Time `json:"updatedAt" db:"updated_at"`
+	ID              string     `json:"id" db:"id"`
+	TaskID          string     `json:"taskId" db:"task_id"`
+	UserID          string     `json:"userId" db:"user_id"`
+	Content         string     `json:"content" db:"content"`
+	MentionedUsers  []string   `json:"mentionedUsers" db:"mentioned_users"`
+	ParentCommentID *string    `json:"parentCommentId,omitempty" db:"parent_comment_id"`
+	EditedAt        *time.Time `json:"editedAt,omitempty" db:"edited_at"` // last content change
+	CreatedAt       time.Time  `json:"createdAt" db:"created_at"`
+	UpdatedAt       time.Time  `json:"updatedAt" db:"updated_at"`
+
+	// Replies to a top-level comment, oldest first; filled by the service when listing
+	Replies []*TaskComment `json:"replies,omitempty" db:"-"`
+
+	// Reaction counts as seen by the requesting user; filled by the service
+	Reactions []*CommentReactionSummary `json:"reactions,omitempty" db:"-"`
+
+	// Author, deleted accounts included; filled by the service when listing
+	User *User `json:"user,omitempty" db:"-"`
+}
+
+// CommentEdit is a previous version of an edited comment
+type CommentEdit struct {
+	ID              string    `json:"id" db:"id"`
+	CommentID       string    `json:"commentId" db:"comment_id"`
+	PreviousContent string    `json:"previousContent" db:"previous_content"`
+	EditedBy        *string   `json:"editedBy,omitempty" db:"edited_by"`
+	EditedAt        time.Time `json:"editedAt" db:"edited_at"`
+}
+
+// CommentReactionSummary counts one emoji on a comment
+type CommentReactionSummary struct {
+	Emoji   string `json:"emoji"`
+	Count   int    `json:"count"`
+	Reacted bool   `json:"reacted"` // the requesting user used this emoji
 }
 
 // TaskCommentRepository interface
@@ -25,7 +52,24 @@ type TaskCommentRepository interface {
 	FindByID(ctx context.Context, id string) (*TaskComment, error)
 	FindByTaskID(ctx context.Context, taskID string) ([]*TaskComment, error)
 	Update(ctx context.Context, comment *TaskComment) error
+	FindEdits(ctx context.Context, commentID string) ([]*CommentEdit, error)
 	Delete(ctx context.Context, id string) error
+	SearchByContent(ctx context.Context, projectIDs []string, query string, limit int) ([]*CommentSearchResult, error)
+
+	// Reactions
+	AddReaction(ctx context.Context, commentID, userID, emoji string) error
+	RemoveReaction(ctx context.Context, commentID, userID, emoji string) error
+	SummarizeReactions(ctx context.Context, commentIDs []string, userID string) (map[string][]*CommentReactionSummary, error)
+}
+
+// CommentSearchResult is a comment matched by full-text search
+type CommentSearchResult struct {
+	TaskComment
+	TaskKey   string  `json:"taskKey"`
+	TaskTitle string  `json:"taskTitle"`
+	ProjectID string  `json:"projectId"`
+	Snippet   string  `json:"snippet"` // matched terms wrapped in <mark></mark>
+	Rank      float64 `json:"rank"`
 }
 
 // taskCommentRepository implementation
@@ -42,9 +86,9 @@ func NewTaskCommentRepository(db *sql.DB) TaskCommentRepository {
 func (r *taskCommentRepository) Create(ctx context.Context, comment *TaskComment) error {
 	query := `
 		INSERT INTO comments (
-			id, task_id, user_id, content, mentioned_users, created_at, updated_at
+			id, task_id, user_id, content, mentioned_users, parent_comment_id, created_at, updated_at
 		) VALUES (
-			gen_random_uuid(), $1, $2, $3, $4, NOW(), NOW()
+			gen_random_uuid(), $1, $2, $3, $4, $5, NOW(), NOW()
 		) RETURNING id, created_at, updated_at`
 
 	return r.db.QueryRowContext(
@@ -53,6 +97,7 @@ func (r *taskCommentRepository) Create(ctx context.Context, comment *TaskComment
 		comment.UserID,
 		comment.Content,
 		pq.Array(comment.MentionedUsers),
+		comment.ParentCommentID,
 	).Scan(&comment.ID, &comment.CreatedAt, &comment.UpdatedAt)
 }
 
@@ -65,6 +110,8 @@ func (r *taskCommentRepository) FindByID(ctx context.Context, id string) (*TaskC
 			user_id,
 			content,
 			mentioned_users,
+			parent_comment_id,
+			edited_at,
 			created_at,
 			updated_at
 		FROM comments
@@ -78,6 +125,8 @@ func (r *taskCommentRepository) FindByID(ctx context.Context, id string) (*TaskC
 		&comment.UserID,
 		&comment.Content,
 		pq.Array(&comment.MentionedUsers),
+		&comment.ParentCommentID,
+		&comment.EditedAt,
 		&comment.CreatedAt,
 		&comment.UpdatedAt,
 	)
@@ -101,11 +150,13 @@ func (r *taskCommentRepository) FindByTaskID(ctx context.Context, taskID string)
 			user_id,
 			content,
 			mentioned_users,
+			parent_comment_id,
+			edited_at,
 			created_at,
 			updated_at
 		FROM comments
 		WHERE task_id = $1
-		ORDER BY created_at ASC
+		ORDER BY created_at ASC, id ASC
 	`
 
 	rows, err := r.db.QueryContext(ctx, query, taskID)
@@ -123,6 +174,8 @@ func (r *taskCommentRepository) FindByTaskID(ctx context.Context, taskID string)
 			&comment.UserID,
 			&comment.Content,
 			pq.Array(&comment.MentionedUsers),
+			&comment.ParentCommentID,
+			&comment.EditedAt,
 			&comment.CreatedAt,
 			&comment.UpdatedAt,
 		)
@@ -135,22 +188,77 @@ func (r *taskCommentRepository) FindByTaskID(ctx context.Context, taskID string)
 	return comments, rows.Err()
 }
 
-// Update updates an existing comment
+// Update saves new content of a comment by its author, keeping the previous
+// content in comment_edits
 func (r *taskCommentRepository) Update(ctx context.Context, comment *TaskComment) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var previous string
+	err = tx.QueryRowContext(ctx, `SELECT content FROM comments WHERE id = $1 FOR UPDATE`, comment.ID).Scan(&previous)
+	if err != nil {
+		return err
+	}
+
+	if previous != comment.Content {
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO comment_edits (comment_id, previous_content, edited_by, edited_at)
+			VALUES ($1, $2, $3, NOW())`,
+			comment.ID, previous, comment.UserID,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
 	query := `
 		UPDATE comments SET
 			content = $2,
 			mentioned_users = $3,
+			edited_at = CASE WHEN content <> $2 THEN NOW() ELSE edited_at END,
 			updated_at = NOW()
 		WHERE id = $1
-		RETURNING updated_at`
+		RETURNING edited_at, updated_at`
 
-	return r.db.QueryRowContext(
+	err = tx.QueryRowContext(
 		ctx, query,
 		comment.ID,
 		comment.Content,
 		pq.Array(comment.MentionedUsers),
-	).Scan(&comment.UpdatedAt)
+	).Scan(&comment.EditedAt, &comment.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// FindEdits returns the previous versions of a comment, oldest first
+func (r *taskCommentRepository) FindEdits(ctx context.Context, commentID string) ([]*CommentEdit, error) {
+	query := `
+		SELECT id, comment_id, previous_content, edited_by, edited_at
+		FROM comment_edits
+		WHERE comment_id = $1
+		ORDER BY edited_at ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, commentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	edits := []*CommentEdit{}
+	for rows.Next() {
+		edit := &CommentEdit{}
+		if err := rows.Scan(&edit.ID, &edit.CommentID, &edit.PreviousContent, &edit.EditedBy, &edit.EditedAt); err != nil {
+			return nil, err
+		}
+		edits = append(edits, edit)
+	}
+	return edits, rows.Err()
 }
 
 // Delete removes a comment
@@ -159,3 +267,113 @@ func (r *taskCommentRepository) Delete(ctx context.Context, id string) error {
 	_, err := r.db.ExecContext(ctx, query, id)
 	return err
 }
+
+// SearchByContent full-text searches comments of the given projects, best matches first
+func (r *taskCommentRepository) SearchByContent(ctx context.Context, projectIDs []string, query string, limit int) ([]*CommentSearchResult, error) {
+	if len(projectIDs) == 0 {
+		return []*CommentSearchResult{}, nil
+	}
+
+	sqlQuery := `
+		WITH q AS (SELECT websearch_to_tsquery('english', $2) AS tsq)
+		SELECT
+			c.id,
+			c.task_id,
+			c.user_id,
+			c.content,
+			c.mentioned_users,
+			c.created_at,
+			c.updated_at,
+			t.title,
+			t.project_id,
+			COALESCE(p.key || '-' || t.number, t.id::text),
+			` + headlineSQL("c.content") + `,
+			ts_rank(to_tsvector('english', c.content), q.tsq) AS rank
+		FROM comments c
+		JOIN tasks t ON t.id = c.task_id
+		JOIN projects p ON p.id = t.project_id
+		CROSS JOIN q
+		WHERE t.project_id = ANY($1)
+		  AND to_tsvector('english', c.content) @@ q.tsq
+		ORDER BY rank DESC, c.created_at DESC
+		LIMIT $3`
+
+	rows, err := r.db.QueryContext(ctx, sqlQuery, pq.Array(projectIDs), query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := []*CommentSearchResult{}
+	for rows.Next() {
+		res := &CommentSearchResult{}
+		if err := rows.Scan(
+			&res.ID,
+			&res.TaskID,
+			&res.UserID,
+			&res.Content,
+			pq.Array(&res.MentionedUsers),
+			&res.CreatedAt,
+			&res.UpdatedAt,
+			&res.TaskTitle,
+			&res.ProjectID,
+			&res.TaskKey,
+			&res.Snippet,
+			&res.Rank,
+		); err != nil {
+			return nil, err
+		}
+		res.Snippet = markSnippet(res.Snippet)
+		results = append(results, res)
+	}
+	return results, rows.Err()
+}
+
+// AddReaction records an emoji reaction; reacting twice with the same emoji is a no-op
+func (r *taskCommentRepository) AddReaction(ctx context.Context, commentID, userID, emoji string) error {
+	query := `
+		INSERT INTO comment_reactions (comment_id, user_id, emoji)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (comment_id, user_id, emoji) DO NOTHING`
+	_, err := r.db.ExecContext(ctx, query, commentID, userID, emoji)
+	return err
+}
+
+// RemoveReaction deletes the user's reaction with the emoji, if any
+func (r *taskCommentRepository) RemoveReaction(ctx context.Context, commentID, userID, emoji string) error {
+	query := `DELETE FROM comment_reactions WHERE comment_id = $1 AND user_id = $2 AND emoji = $3`
+	_, err := r.db.ExecContext(ctx, query, commentID, userID, emoji)
+	return err
+}
+
+// SummarizeReactions counts reactions per emoji for each comment with a single
+// query, flagging the emojis userID used. Emojis are ordered by first use.
+func (r *taskCommentRepository) SummarizeReactions(ctx context.Context, commentIDs []string, userID string) (map[string][]*CommentReactionSummary, error) {
+	summaries := make(map[string][]*CommentReactionSummary, len(commentIDs))
+	if len(commentIDs) == 0 {
+		return summaries, nil
+	}
+
+	query := `
+		SELECT comment_id, emoji, COUNT(*), BOOL_OR(user_id::text = $2)
+		FROM comment_reactions
+		WHERE comment_id = ANY($1)
+		GROUP BY comment_id, emoji
+		ORDER BY comment_id, MIN(created_at) ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(commentIDs), userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var commentID string
+		summary := &CommentReactionSummary{}
+		if err := rows.Scan(&commentID, &summary.Emoji, &summary.Count, &summary.Reacted); err != nil {
+			return nil, err
+		}
+		summaries[commentID] = append(summaries[commentID], summary)
+	}
+	return summaries, rows.Err()
+}
@@ -0,0 +1,248 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/lib/pq"
+)
+
+// TaskLabelRepository manages the task_labels join table. The legacy
+// tasks.label_ids array is kept in sync so existing filters keep working.
+type TaskLabelRepository interface {
+	AddLabel(ctx context.Context, taskID, labelID string) error
+	RemoveLabel(ctx context.Context, taskID, labelID string) error
+	// BulkAddLabel attaches the label to the tasks in one transaction and
+	// returns the IDs of the tasks that did not have it yet
+	BulkAddLabel(ctx context.Context, taskIDs []string, labelID string) ([]string, error)
+	// BulkRemoveLabel detaches the label from the tasks in one transaction and
+	// returns the IDs of the tasks that had it
+	BulkRemoveLabel(ctx context.Context, taskIDs []string, labelID string) ([]string, error)
+	// ReplaceLabels sets the task's labels to the given label IDs or names.
+	// Names without a project label get one; unknown IDs are dropped
+	ReplaceLabels(ctx context.Context, taskID string, labelIDs []string) error
+	FindLabelsByTaskID(ctx context.Context, taskID string) ([]*Label, error)
+}
+
+// taskLabelRepository implementation
+type taskLabelRepository struct {
+	db *sql.DB
+}
+
+// NewTaskLabelRepository creates a new TaskLabelRepository
+func NewTaskLabelRepository(db *sql.DB) TaskLabelRepository {
+	return &taskLabelRepository{db: db}
+}
+
+// AddLabel attaches a label to a task
+func (r *taskLabelRepository) AddLabel(ctx context.Context, taskID, labelID string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO task_labels (task_id, label_id)
+		VALUES ($1, $2)
+		ON CONFLICT DO NOTHING`, taskID, labelID); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE tasks
+		SET label_ids = array_append(label_ids, $2::text),
+		    updated_at = NOW()
+		WHERE id = $1 AND NOT ($2::text = ANY(label_ids))`, taskID, labelID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// RemoveLabel detaches a label from a task
+func (r *taskLabelRepository) RemoveLabel(ctx context.Context, taskID, labelID string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM task_labels WHERE task_id = $1 AND label_id = $2`, taskID, labelID); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE tasks
+		SET label_ids = array_remove(label_ids, $2::text),
+		    updated_at = NOW()
+		WHERE id = $1`, taskID, labelID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// BulkAddLabel attaches a label to several tasks at once
+func (r *taskLabelRepository) BulkAddLabel(ctx context.Context, taskIDs []string, labelID string) ([]string, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	changed, err := queryTaskIDs(ctx, tx, `
+		INSERT INTO task_labels (task_id, label_id)
+		SELECT unnest($1::uuid[]), $2
+		ON CONFLICT DO NOTHING
+		RETURNING task_id`, pq.Array(taskIDs), labelID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(changed) > 0 {
+		if _, err := tx.ExecContext(ctx, `
+			UPDATE tasks
+			SET label_ids = array_append(label_ids, $2::text),
+			    updated_at = NOW()
+			WHERE id = ANY($1::uuid[]) AND NOT ($2::text = ANY(label_ids))`, pq.Array(changed), labelID); err != nil {
+			return nil, err
+		}
+	}
+
+	return changed, tx.Commit()
+}
+
+// BulkRemoveLabel detaches a label from several tasks at once
+func (r *taskLabelRepository) BulkRemoveLabel(ctx context.Context, taskIDs []string, labelID string) ([]string, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	changed, err := queryTaskIDs(ctx, tx, `
+		DELETE FROM task_labels
+		WHERE task_id = ANY($1::uuid[]) AND label_id = $2
+		RETURNING task_id`, pq.Array(taskIDs), labelID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(changed) > 0 {
+		if _, err := tx.ExecContext(ctx, `
+			UPDATE tasks
+			SET label_ids = array_remove(label_ids, $2::text),
+			    updated_at = NOW()
+			WHERE id = ANY($1::uuid[])`, pq.Array(changed), labelID); err != nil {
+			return nil, err
+		}
+	}
+
+	return changed, tx.Commit()
+}
+
+// queryTaskIDs runs a statement returning one task_id column
+func queryTaskIDs(ctx context.Context, tx *sql.Tx, query string, args ...interface{}) ([]string, error) {
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// legacyLabelColor is the color given to labels created from a bare name
+const legacyLabelColor = "#6B7280"
+
+// ReplaceLabels rewrites the join rows of a task from a list of label IDs or
+// names, the latter being what older clients still send in labelIds
+func (r *taskLabelRepository) ReplaceLabels(ctx context.Context, taskID string, labelIDs []string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM task_labels WHERE task_id = $1`, taskID); err != nil {
+		return err
+	}
+
+	if len(labelIDs) > 0 {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO labels (name, color, project_id)
+			SELECT DISTINCT ON (LOWER(v.value)) v.value, $3, t.project_id
+			FROM tasks t
+			CROSS JOIN unnest($2::text[]) AS v(value)
+			WHERE t.id = $1
+			  AND btrim(v.value) <> ''
+			  AND v.value !~* '^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$'
+			  AND NOT EXISTS (
+			      SELECT 1 FROM labels l
+			      WHERE l.project_id = t.project_id AND LOWER(l.name) = LOWER(v.value)
+			  )
+			ON CONFLICT (project_id, name) DO NOTHING`, taskID, pq.Array(labelIDs), legacyLabelColor); err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO task_labels (task_id, label_id)
+			SELECT t.id, l.id
+			FROM tasks t
+			JOIN labels l ON l.project_id = t.project_id
+			WHERE t.id = $1
+			  AND EXISTS (
+			      SELECT 1 FROM unnest($2::text[]) AS v(value)
+			      WHERE l.id::text = v.value OR LOWER(l.name) = LOWER(v.value)
+			  )
+			ON CONFLICT DO NOTHING`, taskID, pq.Array(labelIDs)); err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE tasks
+		SET label_ids = COALESCE((
+			SELECT array_agg(label_id::text ORDER BY created_at) FROM task_labels WHERE task_id = $1
+		), '{}')
+		WHERE id = $1`, taskID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// FindLabelsByTaskID returns the labels attached to a task, ordered by name
+func (r *taskLabelRepository) FindLabelsByTaskID(ctx context.Context, taskID string) ([]*Label, error) {
+	query := `
+		SELECT l.id, l.name, l.color, l.project_id, l.created_at
+		FROM task_labels tl
+		JOIN labels l ON l.id = tl.label_id
+		WHERE tl.task_id = $1
+		ORDER BY l.name ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, taskID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	labels := []*Label{}
+	for rows.Next() {
+		l := &Label{}
+		if err := rows.Scan(&l.ID, &l.Name, &l.Color, &l.ProjectID, &l.CreatedAt); err != nil {
+			return nil, err
+		}
+		labels = append(labels, l)
+	}
+	return labels, rows.Err()
+}
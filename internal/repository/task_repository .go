@@ -3,12 +3,18 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/lib/pq"
 )
 
+// ErrVersionConflict is returned when a task write expected a version that is no longer current
+var ErrVersionConflict = errors.New("task version conflict")
+
 // Task model
 
 // Task struct - ADD Type field after Priority
@@ -33,6 +39,9 @@ type Task struct {
 	CompletedAt    *time.Time `json:"completedAt,omitempty" db:"completed_at"`
 	Blocked        bool       `json:"blocked" db:"blocked"`
 	Position       int        `json:"position" db:"position"`
+	Number         int        `json:"number" db:"number"`
+	Key            string     `json:"key" db:"-"` // "<project key>-<number>"
+	Version        int        `json:"version" db:"version"` // bumped on every write, for optimistic concurrency
 	CreatedBy      *string    `json:"createdBy,omitempty" db:"created_by"`
 	CreatedAt      time.Time  `json:"createdAt" db:"created_at"`
 	UpdatedAt      time.Time  `json:"updatedAt" db:"updated_at"`
@@ -41,23 +50,131 @@ type Task struct {
 	StartedAt        *time.Time `json:"startedAt,omitempty" db:"started_at"`
 	CycleTimeSeconds *int       `json:"cycleTimeSeconds,omitempty" db:"cycle_time_seconds"`
 	LeadTimeSeconds  *int       `json:"leadTimeSeconds,omitempty" db:"lead_time_seconds"`
+
+	// Resolved from task_labels; nil when the query didn't load them
+	Labels []*Label `json:"labels,omitempty" db:"-"`
+
+	// Items across all of the task's checklists; zero when the query didn't load them
+	ChecklistTotal     int `json:"checklistTotal" db:"-"`
+	ChecklistCompleted int `json:"checklistCompleted" db:"-"`
+
+	// Direct subtasks, their story points summed; zero when the query didn't load them
+	SubtaskTotal       int `json:"subtaskTotal" db:"-"`
+	SubtaskCompleted   int `json:"subtaskCompleted" db:"-"`
+	SubtaskStoryPoints int `json:"subtaskStoryPoints" db:"-"`
 }
 
-// TaskFilters for advanced filtering
+// taskSelectColumns is the column list every task query selects, in the order queryTasks scans it.
+// Listing methods select full rows in one query instead of loading IDs and calling FindByID per task.
+const taskSelectColumns = `
+			id, project_id, sprint_id, parent_task_id, title, description,
+			status, priority, type, assignee_ids, watcher_ids, label_ids,
+			story_points, estimated_hours, actual_hours, start_date, due_date,
+			completed_at, blocked, position, created_by, created_at, updated_at,
+			COALESCE(number, 0),
+			COALESCE(` + taskKeyExpr + `, ''),
+			version`
+
+// taskKeyExpr builds a task's "PROJ-123" key inside a query on tasks
+const taskKeyExpr = `((SELECT key FROM projects WHERE projects.id = tasks.project_id) || '-' || number)`
+
+// UnassignedFilter can be passed in TaskFilters.AssigneeIDs to match tasks with no assignee
+const UnassignedFilter = "unassigned"
+
+// TaskFilters for advanced filtering. The JSON form is what saved views store.
 type TaskFilters struct {
-	ProjectID   string
-	SprintID    *string
-	AssigneeIDs []string
-	Status      []string
-	Priority    []string
-	LabelIDs    []string
-	Search      *string
-	DueBefore   *time.Time
-	DueAfter    *time.Time
-	Overdue     *bool
-	Blocked     *bool
-	Limit       int
-	Offset      int
+	ProjectID   string     `json:"projectId,omitempty"`
+	SprintID    *string    `json:"sprintId,omitempty"`
+	AssigneeIDs []string   `json:"assigneeIds,omitempty"`
+	Status      []string   `json:"statuses,omitempty"`
+	Priority    []string   `json:"priorities,omitempty"`
+	LabelIDs    []string   `json:"labelIds,omitempty"`
+	Search      *string    `json:"searchQuery,omitempty"`
+	DueBefore   *time.Time `json:"dueBefore,omitempty"`
+	DueAfter    *time.Time `json:"dueAfter,omitempty"`
+	Overdue     *bool      `json:"overdue,omitempty"`
+	Blocked     *bool      `json:"blocked,omitempty"`
+	// Backlog keeps only top-level tasks that are in no sprint
+	Backlog bool `json:"-"`
+	Limit   int  `json:"-"`
+	Offset      int        `json:"-"`
+	// SortBy is one of the TaskSort* fields; empty keeps the board order
+	SortBy   string `json:"-"`
+	SortDesc bool   `json:"-"`
+}
+
+// TaskSummary holds totals over all tasks matching some filters
+type TaskSummary struct {
+	Tasks       int
+	StoryPoints int
+	// Unestimated counts the tasks without story points
+	Unestimated int
+	ByPriority  map[string]int
+}
+
+// Fields tasks can be sorted by
+const (
+	TaskSortCreatedAt = "created_at"
+	TaskSortUpdatedAt = "updated_at"
+	TaskSortDueDate   = "due_date"
+	TaskSortPriority  = "priority"
+	TaskSortTitle     = "title"
+)
+
+// taskSortExpressions maps each sort field to its ORDER BY expression. Only
+// these expressions are ever put into a query. Priority ranks urgent highest
+// rather than sorting alphabetically.
+var taskSortExpressions = map[string]string{
+	TaskSortCreatedAt: "created_at",
+	TaskSortUpdatedAt: "updated_at",
+	TaskSortDueDate:   "due_date",
+	TaskSortPriority:  "CASE priority WHEN 'urgent' THEN 4 WHEN 'high' THEN 3 WHEN 'medium' THEN 2 WHEN 'low' THEN 1 ELSE 0 END",
+	TaskSortTitle:     "LOWER(title)",
+}
+
+// IsValidTaskSort reports whether tasks can be sorted by the field
+func IsValidTaskSort(field string) bool {
+	_, ok := taskSortExpressions[field]
+	return ok
+}
+
+// taskOrderBy is the ORDER BY clause (without the keyword) for the filters.
+// Tasks without a value for the sort field come last either way, and ties
+// keep the board order.
+func taskOrderBy(filters *TaskFilters) string {
+	const boardOrder = "position ASC, created_at DESC"
+	expr, ok := taskSortExpressions[filters.SortBy]
+	if !ok {
+		return boardOrder
+	}
+	dir := "ASC"
+	if filters.SortDesc {
+		dir = "DESC"
+	}
+	return expr + " " + dir + " NULLS LAST, " + boardOrder
+}
+
+// "My work" sections
+const (
+	WorkSectionOverdue           = "overdue"
+	WorkSectionDueToday          = "due_today"
+	WorkSectionDueThisWeek       = "due_this_week"
+	WorkSectionNoDueDate         = "no_due_date"
+	WorkSectionRecentlyCompleted = "recently_completed"
+)
+
+// WorkBounds are the instants separating the "my work" sections
+type WorkBounds struct {
+	Now            time.Time // due before this is overdue
+	EndOfToday     time.Time
+	EndOfWeek      time.Time
+	CompletedSince time.Time // done tasks completed after this are "recently completed"
+}
+
+// WorkSection is one "my work" bucket: its first tasks and the full count
+type WorkSection struct {
+	Tasks []*Task `json:"tasks"`
+	Total int     `json:"total"`
 }
 
 // TaskRepository interface
@@ -65,7 +182,16 @@ type TaskRepository interface {
 	// Basic CRUD
 	Create(ctx context.Context, task *Task) error
 	FindByID(ctx context.Context, id string) (*Task, error)
+	FindByKey(ctx context.Context, projectKey string, number int) (*Task, error)
+	// Update saves the task if its stored version still equals task.Version,
+	// returning ErrVersionConflict otherwise, and bumps task.Version
 	Update(ctx context.Context, task *Task) error
+	// MoveToProject moves the tasks, in order, to their ProjectID under that
+	// project's next task numbers, at the end of its board, and saves their
+	// sprint, parent, status, labels, assignees and watchers. WatcherIDs is
+	// the complete watcher list. Like Update it checks each task's Version;
+	// all tasks move or none do.
+	MoveToProject(ctx context.Context, tasks []*Task) error
 	Delete(ctx context.Context, id string) error
 
 	// Listing methods
@@ -73,15 +199,20 @@ type TaskRepository interface {
 	FindBySprintID(ctx context.Context, sprintID string) ([]*Task, error)
 	FindByParentTaskID(ctx context.Context, parentTaskID string) ([]*Task, error)
 	FindByAssigneeID(ctx context.Context, assigneeID string) ([]*Task, error)
+	// FindMyWork buckets the user's assigned tasks in the given projects into
+	// WorkSection* sections, returning at most perSection tasks of each
+	FindMyWork(ctx context.Context, assigneeID string, projectIDs []string, bounds WorkBounds, perSection int) (map[string]*WorkSection, error)
 	FindByStatus(ctx context.Context, projectID, status string) ([]*Task, error)
 	FindBacklog(ctx context.Context, projectID string) ([]*Task, error)
 
 	GetSubtaskCount(ctx context.Context, taskID string) (int, error)
+	CountByStatus(ctx context.Context, projectID, status string) (int, error)
 
 
-	// Quick updates
-	UpdateStatus(ctx context.Context, taskID, status string) error
-	UpdatePriority(ctx context.Context, taskID, priority string) error
+	// Quick updates. A non-nil expectedVersion must match the stored version,
+	// or ErrVersionConflict is returned.
+	UpdateStatus(ctx context.Context, taskID, status string, expectedVersion *int) error
+	UpdatePriority(ctx context.Context, taskID, priority string, expectedVersion *int) error
 	MarkComplete(ctx context.Context, taskID string) error
 
 	// Assignee/Watcher management
@@ -92,19 +223,30 @@ type TaskRepository interface {
 
 	// Advanced filtering
 	FindWithFilters(ctx context.Context, filters *TaskFilters) ([]*Task, int, error)
+	// SummarizeWithFilters totals the tasks matching the filters in SQL;
+	// Limit, Offset and sorting are ignored
+	SummarizeWithFilters(ctx context.Context, filters *TaskFilters) (*TaskSummary, error)
+	// StreamWithFilters hands all matching tasks to fn batch by batch, for exports
+	StreamWithFilters(ctx context.Context, filters *TaskFilters, batchSize int, fn func([]*Task) error) error
 	FindOverdue(ctx context.Context, projectID string) ([]*Task, error)
+	// FindDueForAssignee returns the open tasks with a due date assigned to the user in the projects
+	FindDueForAssignee(ctx context.Context, assigneeID string, projectIDs []string) ([]*Task, error)
 	FindBlocked(ctx context.Context, projectID string) ([]*Task, error)
+	Search(ctx context.Context, projectIDs []string, query string, limit int) ([]*Task, error)
 
 	// Sprint/Scrum specific
 	GetSprintVelocity(ctx context.Context, sprintID string) (int, error)
 	GetCompletedStoryPoints(ctx context.Context, sprintID string) (int, error)
 
 	UpdatePosition(ctx context.Context, taskID string, position int) error
+	FindColumnTasks(ctx context.Context, projectID string, sprintID *string, status string) ([]*Task, error)
+	RenumberColumn(ctx context.Context, projectID string, sprintID *string, status string, gap int) error
 
 
 	// Bulk operations
 	BulkUpdateStatus(ctx context.Context, taskIDs []string, status string) error
 	BulkMoveToSprint(ctx context.Context, taskIDs []string, sprintID string) error
+	BulkDelete(ctx context.Context, taskIDs []string, deletedBy string) ([]*Task, error)
 }
 
 // taskRepository implementation
@@ -121,17 +263,21 @@ func NewTaskRepository(db *sql.DB) TaskRepository {
 // Fix the Create method to include Type field
 func (r *taskRepository) Create(ctx context.Context, task *Task) error {
 	query := `
+		WITH seq AS (
+			UPDATE projects SET task_seq = task_seq + 1 WHERE id = $1
+			RETURNING key, task_seq
+		)
 		INSERT INTO tasks (
 			id, project_id, sprint_id, parent_task_id, title, description,
 			status, priority, type, assignee_ids, watcher_ids, label_ids,
 			estimated_hours, actual_hours, story_points, start_date, due_date,
-			blocked, position, created_by, created_at, updated_at
+			blocked, position, created_by, created_at, updated_at, number
 		) VALUES (
 			gen_random_uuid(), $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11,
 			$12, $13, $14, $15, $16, $17, 
 			COALESCE((SELECT MAX(position) + 1 FROM tasks WHERE project_id = $1), 0),
-			$18, NOW(), NOW()
-		) RETURNING id, created_at, updated_at, position`
+			$18, NOW(), NOW(), (SELECT task_seq FROM seq)
+		) RETURNING id, created_at, updated_at, position, number, (SELECT key || '-' || task_seq FROM seq), version`
 
 	return r.db.QueryRowContext(
 		ctx, query,
@@ -140,7 +286,7 @@ func (r *taskRepository) Create(ctx context.Context, task *Task) error {
 		pq.Array(task.AssigneeIDs), pq.Array(task.WatcherIDs),
 		pq.Array(task.LabelIDs), task.EstimatedHours, task.ActualHours, task.StoryPoints,
 		task.StartDate, task.DueDate, task.Blocked, task.CreatedBy,
-	).Scan(&task.ID, &task.CreatedAt, &task.UpdatedAt, &task.Position)
+	).Scan(&task.ID, &task.CreatedAt, &task.UpdatedAt, &task.Position, &task.Number, &task.Key, &task.Version)
 }
 
 
@@ -153,21 +299,82 @@ func (r *taskRepository) Update(ctx context.Context, task *Task) error {
 			status = $6, priority = $7, type = $8, assignee_ids = $9, watcher_ids = $10,
 			label_ids = $11, estimated_hours = $12, actual_hours = $13,
 			story_points = $14, start_date = $15, due_date = $16,
-			completed_at = $17, blocked = $18, updated_at = NOW()
-		WHERE id = $1
-		RETURNING updated_at`
+			completed_at = $17, blocked = $18, updated_at = NOW(), version = version + 1
+		WHERE id = $1 AND version = $19
+		RETURNING updated_at, version`
 
-	return r.db.QueryRowContext(
+	err := r.db.QueryRowContext(
 		ctx, query,
 		task.ID, task.SprintID, task.ParentTaskID, task.Title, task.Description,
 		task.Status, task.Priority, task.Type, // Added Type here
 		pq.Array(task.AssigneeIDs), pq.Array(task.WatcherIDs),
 		pq.Array(task.LabelIDs), task.EstimatedHours, task.ActualHours, task.StoryPoints,
-		task.StartDate, task.DueDate, task.CompletedAt, task.Blocked,
-	).Scan(&task.UpdatedAt)
+		task.StartDate, task.DueDate, task.CompletedAt, task.Blocked, task.Version,
+	).Scan(&task.UpdatedAt, &task.Version)
+	if err == sql.ErrNoRows {
+		return ErrVersionConflict
+	}
+	return err
 }
 
 
+// MoveToProject re-keys tasks into another project in one transaction. The
+// numbers come from the target's task_seq, as in Create, and the task_labels
+// and task_watchers rows are rewritten to match the moved tasks.
+func (r *taskRepository) MoveToProject(ctx context.Context, tasks []*Task) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	moveQuery := `
+		WITH seq AS (
+			UPDATE projects SET task_seq = task_seq + 1 WHERE id = $2
+			RETURNING key, task_seq
+		)
+		UPDATE tasks SET
+			project_id = $2, number = (SELECT task_seq FROM seq),
+			sprint_id = $3, parent_task_id = $4, status = $5, label_ids = $6,
+			assignee_ids = $7, watcher_ids = $8,
+			position = COALESCE((SELECT MAX(position) + 1 FROM tasks WHERE project_id = $2), 0),
+			updated_at = NOW(), version = version + 1
+		WHERE id = $1 AND version = $9
+		RETURNING number, (SELECT key || '-' || task_seq FROM seq), position, updated_at, version`
+
+	for _, task := range tasks {
+		err := tx.QueryRowContext(
+			ctx, moveQuery,
+			task.ID, task.ProjectID, task.SprintID, task.ParentTaskID, task.Status,
+			pq.Array(task.LabelIDs), pq.Array(task.AssigneeIDs), pq.Array(task.WatcherIDs), task.Version,
+		).Scan(&task.Number, &task.Key, &task.Position, &task.UpdatedAt, &task.Version)
+		if err == sql.ErrNoRows {
+			return ErrVersionConflict
+		}
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(ctx, `DELETE FROM task_labels WHERE task_id = $1`, task.ID); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO task_labels (task_id, label_id)
+			SELECT $1, l.id FROM labels l
+			WHERE l.project_id = $2 AND l.id::text = ANY($3)
+			ON CONFLICT DO NOTHING`, task.ID, task.ProjectID, pq.Array(task.LabelIDs)); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, `
+			DELETE FROM task_watchers
+			WHERE task_id = $1 AND NOT (user_id::text = ANY($2))`, task.ID, pq.Array(task.WatcherIDs)); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
 // Delete removes a task
 func (r *taskRepository) Delete(ctx context.Context, id string) error {
 	query := `DELETE FROM tasks WHERE id = $1`
@@ -175,42 +382,38 @@ func (r *taskRepository) Delete(ctx context.Context, id string) error {
 	return err
 }
 
+// FindByKey finds a task by its project key (case-insensitive, as keys are
+// stored upper-case) and number
+func (r *taskRepository) FindByKey(ctx context.Context, projectKey string, number int) (*Task, error) {
+	query := `
+		SELECT ` + taskSelectColumns + `
+		FROM tasks
+		WHERE number = $2
+		  AND project_id = (SELECT id FROM projects WHERE key = UPPER($1))`
+	tasks, err := r.queryTasks(ctx, query, projectKey, number)
+	if err != nil || len(tasks) == 0 {
+		return nil, err
+	}
+	if err := r.attachLabels(ctx, tasks); err != nil {
+		return nil, err
+	}
+	if err := r.attachChecklistProgress(ctx, tasks); err != nil {
+		return nil, err
+	}
+	if err := r.attachSubtaskProgress(ctx, tasks); err != nil {
+		return nil, err
+	}
+	return tasks[0], nil
+}
+
 func (r *taskRepository) FindByID(ctx context.Context, id string) (*Task, error) {
 	query := `
-		SELECT 
-			id, project_id, sprint_id, parent_task_id, title, description,
-			status, priority, type, assignee_ids, watcher_ids, label_ids,
-			story_points, estimated_hours, actual_hours, start_date, due_date,
-			completed_at, blocked, position, created_by, created_at, updated_at
+		SELECT ` + taskSelectColumns + `
 		FROM tasks 
 		WHERE id = $1`
 	
 	task := &Task{}
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&task.ID,
-		&task.ProjectID,
-		&task.SprintID,
-		&task.ParentTaskID,
-		&task.Title,
-		&task.Description,
-		&task.Status,
-		&task.Priority,
-		&task.Type,
-		pq.Array(&task.AssigneeIDs),
-		pq.Array(&task.WatcherIDs),
-		pq.Array(&task.LabelIDs),
-		&task.StoryPoints,
-		&task.EstimatedHours,
-		&task.ActualHours,
-		&task.StartDate,
-		&task.DueDate,
-		&task.CompletedAt,
-		&task.Blocked,
-		&task.Position,
-		&task.CreatedBy,
-		&task.CreatedAt,
-		&task.UpdatedAt,
-	)
+	err := scanTask(r.db.QueryRowContext(ctx, query, id), task)
 	
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -218,45 +421,188 @@ func (r *taskRepository) FindByID(ctx context.Context, id string) (*Task, error)
 	if err != nil {
 		return nil, err
 	}
+
+	if err := r.attachLabels(ctx, []*Task{task}); err != nil {
+		return nil, err
+	}
+	if err := r.attachChecklistProgress(ctx, []*Task{task}); err != nil {
+		return nil, err
+	}
+	if err := r.attachSubtaskProgress(ctx, []*Task{task}); err != nil {
+		return nil, err
+	}
 	
 	return task, nil
 }
 // FindByProjectID retrieves all tasks for a project
 func (r *taskRepository) FindByProjectID(ctx context.Context, projectID string) ([]*Task, error) {
 	query := `
-		SELECT 
-			id, project_id, sprint_id, parent_task_id, title, description,
-			status, priority, type, assignee_ids, watcher_ids, label_ids,
-			story_points, estimated_hours, actual_hours, start_date, due_date,
-			completed_at, blocked, position, created_by, created_at, updated_at
+		SELECT ` + taskSelectColumns + `
 		FROM tasks 
 		WHERE project_id = $1 
 		ORDER BY position ASC, created_at DESC`
-	return r.queryTasks(ctx, query, projectID)
+	tasks, err := r.queryTasks(ctx, query, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.attachLabels(ctx, tasks); err != nil {
+		return nil, err
+	}
+	if err := r.attachChecklistProgress(ctx, tasks); err != nil {
+		return nil, err
+	}
+	if err := r.attachSubtaskProgress(ctx, tasks); err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// attachLabels resolves the labels of all given tasks with a single query
+func (r *taskRepository) attachLabels(ctx context.Context, tasks []*Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	byID := make(map[string]*Task, len(tasks))
+	ids := make([]string, 0, len(tasks))
+	for _, t := range tasks {
+		t.Labels = []*Label{}
+		byID[t.ID] = t
+		ids = append(ids, t.ID)
+	}
+
+	query := `
+		SELECT tl.task_id, l.id, l.name, l.color, l.project_id, l.created_at
+		FROM task_labels tl
+		JOIN labels l ON l.id = tl.label_id
+		WHERE tl.task_id = ANY($1)
+		ORDER BY l.name ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var taskID string
+		l := &Label{}
+		if err := rows.Scan(&taskID, &l.ID, &l.Name, &l.Color, &l.ProjectID, &l.CreatedAt); err != nil {
+			return err
+		}
+		if t, ok := byID[taskID]; ok {
+			t.Labels = append(t.Labels, l)
+		}
+	}
+	return rows.Err()
+}
+
+// attachChecklistProgress counts the checklist items of all given tasks with a single query
+func (r *taskRepository) attachChecklistProgress(ctx context.Context, tasks []*Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	byID := make(map[string]*Task, len(tasks))
+	ids := make([]string, 0, len(tasks))
+	for _, t := range tasks {
+		byID[t.ID] = t
+		ids = append(ids, t.ID)
+	}
+
+	query := `
+		SELECT c.task_id, COUNT(i.id), COUNT(i.id) FILTER (WHERE i.is_completed)
+		FROM checklists c
+		JOIN checklist_items i ON i.checklist_id = c.id
+		WHERE c.task_id = ANY($1)
+		GROUP BY c.task_id`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var taskID string
+		var total, completed int
+		if err := rows.Scan(&taskID, &total, &completed); err != nil {
+			return err
+		}
+		if t, ok := byID[taskID]; ok {
+			t.ChecklistTotal = total
+			t.ChecklistCompleted = completed
+		}
+	}
+	return rows.Err()
+}
+
+// attachSubtaskProgress counts the direct subtasks of all given tasks, and
+// sums their story points, with a single query
+func (r *taskRepository) attachSubtaskProgress(ctx context.Context, tasks []*Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	byID := make(map[string]*Task, len(tasks))
+	ids := make([]string, 0, len(tasks))
+	for _, t := range tasks {
+		byID[t.ID] = t
+		ids = append(ids, t.ID)
+	}
+
+	query := `
+		SELECT parent_task_id, COUNT(*), COUNT(*) FILTER (WHERE status = 'done'),
+		       COALESCE(SUM(story_points), 0)
+		FROM tasks
+		WHERE parent_task_id = ANY($1)
+		GROUP BY parent_task_id`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var taskID string
+		var total, completed, points int
+		if err := rows.Scan(&taskID, &total, &completed, &points); err != nil {
+			return err
+		}
+		if t, ok := byID[taskID]; ok {
+			t.SubtaskTotal = total
+			t.SubtaskCompleted = completed
+			t.SubtaskStoryPoints = points
+		}
+	}
+	return rows.Err()
 }
 
 // FindBySprintID retrieves all tasks for a sprint
 func (r *taskRepository) FindBySprintID(ctx context.Context, sprintID string) ([]*Task, error) {
 	query := `
-		SELECT 
-			id, project_id, sprint_id, parent_task_id, title, description,
-			status, priority, type, assignee_ids, watcher_ids, label_ids,
-			story_points, estimated_hours, actual_hours, start_date, due_date,
-			completed_at, blocked, position, created_by, created_at, updated_at
+		SELECT ` + taskSelectColumns + `
 		FROM tasks 
 		WHERE sprint_id = $1 
 		ORDER BY position ASC, created_at DESC`
-	return r.queryTasks(ctx, query, sprintID)
+	tasks, err := r.queryTasks(ctx, query, sprintID)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.attachChecklistProgress(ctx, tasks); err != nil {
+		return nil, err
+	}
+	if err := r.attachSubtaskProgress(ctx, tasks); err != nil {
+		return nil, err
+	}
+	return tasks, nil
 }
 
 // FindByParentTaskID retrieves all subtasks
 func (r *taskRepository) FindByParentTaskID(ctx context.Context, parentTaskID string) ([]*Task, error) {
 	query := `
-		SELECT 
-			id, project_id, sprint_id, parent_task_id, title, description,
-			status, priority, type, assignee_ids, watcher_ids, label_ids,
-			story_points, estimated_hours, actual_hours, start_date, due_date,
-			completed_at, blocked, position, created_by, created_at, updated_at
+		SELECT ` + taskSelectColumns + `
 		FROM tasks 
 		WHERE parent_task_id = $1 
 		ORDER BY position ASC, created_at DESC`
@@ -266,24 +612,101 @@ func (r *taskRepository) FindByParentTaskID(ctx context.Context, parentTaskID st
 // FindByAssigneeID retrieves tasks assigned to a user
 func (r *taskRepository) FindByAssigneeID(ctx context.Context, assigneeID string) ([]*Task, error) {
 	query := `
-		SELECT 
-			id, project_id, sprint_id, parent_task_id, title, description,
-			status, priority, type, assignee_ids, watcher_ids, label_ids,
-			story_points, estimated_hours, actual_hours, start_date, due_date,
-			completed_at, blocked, position, created_by, created_at, updated_at
+		SELECT ` + taskSelectColumns + `
 		FROM tasks 
 		WHERE $1 = ANY(assignee_ids) 
 		ORDER BY due_date ASC NULLS LAST, created_at DESC`
 	return r.queryTasks(ctx, query, assigneeID)
 }
 
+// FindMyWork sections and caps the tasks in SQL so the dashboard is one round trip
+func (r *taskRepository) FindMyWork(ctx context.Context, assigneeID string, projectIDs []string, bounds WorkBounds, perSection int) (map[string]*WorkSection, error) {
+	query := `
+		WITH sectioned AS (
+			SELECT id AS task_id, due_date AS section_due, completed_at AS section_completed, created_at AS section_created,
+				CASE
+					WHEN status = 'done' THEN
+						CASE WHEN completed_at >= $6 THEN '` + WorkSectionRecentlyCompleted + `' END
+					WHEN due_date IS NULL THEN '` + WorkSectionNoDueDate + `'
+					WHEN due_date < $3 THEN '` + WorkSectionOverdue + `'
+					WHEN due_date < $4 THEN '` + WorkSectionDueToday + `'
+					WHEN due_date < $5 THEN '` + WorkSectionDueThisWeek + `'
+				END AS section
+			FROM tasks
+			WHERE $1 = ANY(assignee_ids) AND project_id = ANY($2)
+		),
+		ranked AS (
+			SELECT task_id, section,
+				COUNT(*) OVER (PARTITION BY section) AS section_total,
+				ROW_NUMBER() OVER (
+					PARTITION BY section
+					ORDER BY
+						CASE WHEN section = '` + WorkSectionRecentlyCompleted + `' THEN section_completed END DESC,
+						section_due ASC,
+						section_created DESC
+				) AS section_rank
+			FROM sectioned
+			WHERE section IS NOT NULL
+		)
+		SELECT ranked.section, ranked.section_total, ` + taskSelectColumns + `
+		FROM ranked
+		JOIN tasks ON tasks.id = ranked.task_id
+		WHERE ranked.section_rank <= $7
+		ORDER BY ranked.section, ranked.section_rank`
+
+	sections := map[string]*WorkSection{}
+	for _, name := range []string{
+		WorkSectionOverdue, WorkSectionDueToday, WorkSectionDueThisWeek,
+		WorkSectionNoDueDate, WorkSectionRecentlyCompleted,
+	} {
+		sections[name] = &WorkSection{Tasks: []*Task{}}
+	}
+	if len(projectIDs) == 0 {
+		return sections, nil
+	}
+
+	rows, err := r.db.QueryContext(ctx, query,
+		assigneeID, pq.Array(projectIDs),
+		bounds.Now, bounds.EndOfToday, bounds.EndOfWeek, bounds.CompletedSince,
+		perSection,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var all []*Task
+	for rows.Next() {
+		var name string
+		var total int
+		task := &Task{}
+		if err := scanTask(rows, task, &name, &total); err != nil {
+			return nil, err
+		}
+		section := sections[name]
+		section.Total = total
+		section.Tasks = append(section.Tasks, task)
+		all = append(all, task)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := r.attachLabels(ctx, all); err != nil {
+		return nil, err
+	}
+	if err := r.attachChecklistProgress(ctx, all); err != nil {
+		return nil, err
+	}
+	if err := r.attachSubtaskProgress(ctx, all); err != nil {
+		return nil, err
+	}
+	return sections, nil
+}
+
 func (r *taskRepository) FindByStatus(ctx context.Context, projectID, status string) ([]*Task, error) {
 	query := `
-		SELECT 
-			id, project_id, sprint_id, parent_task_id, title, description,
-			status, priority, type, assignee_ids, watcher_ids, label_ids,
-			story_points, estimated_hours, actual_hours, start_date, due_date,
-			completed_at, blocked, position, created_by, created_at, updated_at
+		SELECT ` + taskSelectColumns + `
 		FROM tasks 
 		WHERE project_id = $1 AND status = $2 
 		ORDER BY position ASC`
@@ -292,11 +715,7 @@ func (r *taskRepository) FindByStatus(ctx context.Context, projectID, status str
 
 func (r *taskRepository) FindBacklog(ctx context.Context, projectID string) ([]*Task, error) {
 	query := `
-		SELECT 
-			id, project_id, sprint_id, parent_task_id, title, description,
-			status, priority, type, assignee_ids, watcher_ids, label_ids,
-			story_points, estimated_hours, actual_hours, start_date, due_date,
-			completed_at, blocked, position, created_by, created_at, updated_at
+		SELECT ` + taskSelectColumns + `
 		FROM tasks 
 		WHERE project_id = $1 AND sprint_id IS NULL AND parent_task_id IS NULL 
 		ORDER BY position ASC`
@@ -317,7 +736,7 @@ func (r *taskRepository) FindBacklog(ctx context.Context, projectID string) ([]*
 // }
 
 
-func (r *taskRepository) UpdateStatus(ctx context.Context, taskID, status string) error {
+func (r *taskRepository) UpdateStatus(ctx context.Context, taskID, status string, expectedVersion *int) error {
 	query := `
 		UPDATE tasks SET 
 			status = $2::varchar,
@@ -346,17 +765,41 @@ func (r *taskRepository) UpdateStatus(ctx context.Context, taskID, status string
 				WHEN $2::varchar != 'done' THEN NULL
 				ELSE lead_time_seconds
 			END,
-			updated_at = NOW()
-		WHERE id = $1`
-	_, err := r.db.ExecContext(ctx, query, taskID, status)
-	return err
+			updated_at = NOW(),
+			version = version + 1
+		WHERE id = $1 AND ($3::int IS NULL OR version = $3)`
+	result, err := r.db.ExecContext(ctx, query, taskID, status, expectedVersion)
+	if err != nil {
+		return err
+	}
+	return checkVersionedWrite(result, expectedVersion)
 }
 
 // UpdatePriority updates task priority
-func (r *taskRepository) UpdatePriority(ctx context.Context, taskID, priority string) error {
-	query := `UPDATE tasks SET priority = $2, updated_at = NOW() WHERE id = $1`
-	_, err := r.db.ExecContext(ctx, query, taskID, priority)
-	return err
+func (r *taskRepository) UpdatePriority(ctx context.Context, taskID, priority string, expectedVersion *int) error {
+	query := `
+		UPDATE tasks SET priority = $2, updated_at = NOW(), version = version + 1
+		WHERE id = $1 AND ($3::int IS NULL OR version = $3)`
+	result, err := r.db.ExecContext(ctx, query, taskID, priority, expectedVersion)
+	if err != nil {
+		return err
+	}
+	return checkVersionedWrite(result, expectedVersion)
+}
+
+// checkVersionedWrite reports a conflict when a version-checked UPDATE matched no row
+func checkVersionedWrite(result sql.Result, expectedVersion *int) error {
+	if expectedVersion == nil {
+		return nil
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrVersionConflict
+	}
+	return nil
 }
 
 
@@ -368,9 +811,17 @@ func (r *taskRepository) GetSubtaskCount(ctx context.Context, taskID string) (in
 	return count, err
 }
 
+// CountByStatus counts top-level tasks of a project in a given status
+func (r *taskRepository) CountByStatus(ctx context.Context, projectID, status string) (int, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM tasks WHERE project_id = $1 AND status = $2 AND parent_task_id IS NULL`
+	err := r.db.QueryRowContext(ctx, query, projectID, status).Scan(&count)
+	return count, err
+}
+
 // MarkComplete marks a task as complete
 func (r *taskRepository) MarkComplete(ctx context.Context, taskID string) error {
-	query := `UPDATE tasks SET status = 'done', completed_at = NOW(), updated_at = NOW() WHERE id = $1`
+	query := `UPDATE tasks SET status = 'done', completed_at = NOW(), updated_at = NOW(), version = version + 1 WHERE id = $1`
 	_, err := r.db.ExecContext(ctx, query, taskID)
 	return err
 }
@@ -380,7 +831,7 @@ func (r *taskRepository) AddAssignee(ctx context.Context, taskID, assigneeID str
 	query := `
 		UPDATE tasks 
 		SET assignee_ids = array_append(assignee_ids, $2),
-		    updated_at = NOW()
+		    updated_at = NOW(), version = version + 1
 		WHERE id = $1 AND NOT ($2 = ANY(assignee_ids))`
 	_, err := r.db.ExecContext(ctx, query, taskID, assigneeID)
 	return err
@@ -391,7 +842,7 @@ func (r *taskRepository) RemoveAssignee(ctx context.Context, taskID, assigneeID
 	query := `
 		UPDATE tasks 
 		SET assignee_ids = array_remove(assignee_ids, $2),
-		    updated_at = NOW()
+		    updated_at = NOW(), version = version + 1
 		WHERE id = $1`
 	_, err := r.db.ExecContext(ctx, query, taskID, assigneeID)
 	return err
@@ -421,88 +872,264 @@ func (r *taskRepository) RemoveWatcher(ctx context.Context, taskID, watcherID st
 
 // FindWithFilters performs advanced filtering
 func (r *taskRepository) FindWithFilters(ctx context.Context, filters *TaskFilters) ([]*Task, int, error) {
-	// Build dynamic query based on filters
-baseQuery := `
-	SELECT 
-		id, project_id, sprint_id, parent_task_id, title, description,
-		status, priority, type, assignee_ids, watcher_ids, label_ids,
-		story_points, estimated_hours, actual_hours, start_date, due_date,
-		completed_at, blocked, position, created_by, created_at, updated_at
-	FROM tasks 
-	WHERE project_id = $1
-`
-	countQuery := `SELECT COUNT(*) FROM tasks WHERE project_id = $1`
+	where, args := taskFilterWhere(filters)
+
+	// Get total count
+	var total int
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM tasks WHERE `+where, args...).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Add sorting and pagination; without a limit every match is returned
+	query := `SELECT ` + taskSelectColumns + ` FROM tasks WHERE ` + where + ` ORDER BY ` + taskOrderBy(filters)
+	if filters.Limit > 0 {
+		argIndex := len(args) + 1
+		query += ` LIMIT $` + strconv.Itoa(argIndex) + ` OFFSET $` + strconv.Itoa(argIndex+1)
+		args = append(args, filters.Limit, filters.Offset)
+	}
+
+	tasks, err := r.queryTasks(ctx, query, args...)
+	return tasks, total, err
+}
+
+// SummarizeWithFilters counts tasks and sums story points per priority
+func (r *taskRepository) SummarizeWithFilters(ctx context.Context, filters *TaskFilters) (*TaskSummary, error) {
+	where, args := taskFilterWhere(filters)
+	query := `
+		SELECT priority, COUNT(*), COALESCE(SUM(story_points), 0), COUNT(*) FILTER (WHERE story_points IS NULL)
+		FROM tasks WHERE ` + where + `
+		GROUP BY priority`
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	summary := &TaskSummary{ByPriority: make(map[string]int)}
+	for rows.Next() {
+		var priority string
+		var count, points, unestimated int
+		if err := rows.Scan(&priority, &count, &points, &unestimated); err != nil {
+			return nil, err
+		}
+		summary.Tasks += count
+		summary.StoryPoints += points
+		summary.Unestimated += unestimated
+		summary.ByPriority[priority] = count
+	}
+	return summary, rows.Err()
+}
+
+// StreamWithFilters passes every task matching the filters to fn in batches of
+// up to batchSize, with labels attached, without holding the full result in
+// memory. Limit and Offset are ignored.
+func (r *taskRepository) StreamWithFilters(ctx context.Context, filters *TaskFilters, batchSize int, fn func([]*Task) error) error {
+	where, args := taskFilterWhere(filters)
+	query := `SELECT ` + taskSelectColumns + ` FROM tasks WHERE ` + where + ` ORDER BY position ASC, created_at ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	flush := func(batch []*Task) error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := r.attachLabels(ctx, batch); err != nil {
+			return err
+		}
+		return fn(batch)
+	}
+
+	batch := make([]*Task, 0, batchSize)
+	for rows.Next() {
+		task := &Task{}
+		if err := scanTask(rows, task); err != nil {
+			return err
+		}
+		batch = append(batch, task)
+		if len(batch) == batchSize {
+			if err := flush(batch); err != nil {
+				return err
+			}
+			batch = make([]*Task, 0, batchSize)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	return flush(batch)
+}
+
+// taskFilterWhere builds the WHERE clause (without the keyword) and its
+// arguments for the given filters
+func taskFilterWhere(filters *TaskFilters) (string, []interface{}) {
+	where := `project_id = $1`
 	args := []interface{}{filters.ProjectID}
 	argIndex := 2
 
 	// Apply filters
 	if filters.SprintID != nil {
-		baseQuery += " AND sprint_id = $" + strconv.Itoa(argIndex)
-countQuery += " AND sprint_id = $" + strconv.Itoa(argIndex)
-
+		where += " AND sprint_id = $" + strconv.Itoa(argIndex)
 		args = append(args, *filters.SprintID)
 		argIndex++
 	}
 
+	if filters.Backlog {
+		where += " AND sprint_id IS NULL AND parent_task_id IS NULL"
+	}
+
 	if len(filters.Status) > 0 {
-		baseQuery += ` AND status = ANY($` + string(rune(argIndex)) + `)`
-		countQuery += ` AND status = ANY($` + string(rune(argIndex)) + `)`
+		where += ` AND status = ANY($` + strconv.Itoa(argIndex) + `)`
 		args = append(args, pq.Array(filters.Status))
 		argIndex++
 	}
 
 	if len(filters.Priority) > 0 {
-		baseQuery += ` AND priority = ANY($` + string(rune(argIndex)) + `)`
-		countQuery += ` AND priority = ANY($` + string(rune(argIndex)) + `)`
+		where += ` AND priority = ANY($` + strconv.Itoa(argIndex) + `)`
 		args = append(args, pq.Array(filters.Priority))
 		argIndex++
 	}
 
+	// Assignees: "unassigned" matches tasks with no assignee at all
+	if len(filters.AssigneeIDs) > 0 {
+		var assigneeIDs []string
+		includeUnassigned := false
+		for _, id := range filters.AssigneeIDs {
+			if id == UnassignedFilter {
+				includeUnassigned = true
+				continue
+			}
+			assigneeIDs = append(assigneeIDs, id)
+		}
+
+		switch {
+		case len(assigneeIDs) > 0 && includeUnassigned:
+			where += ` AND (assignee_ids && $` + strconv.Itoa(argIndex) + ` OR COALESCE(cardinality(assignee_ids), 0) = 0)`
+		case len(assigneeIDs) > 0:
+			where += ` AND assignee_ids && $` + strconv.Itoa(argIndex)
+		default:
+			where += ` AND COALESCE(cardinality(assignee_ids), 0) = 0`
+		}
+		if len(assigneeIDs) > 0 {
+			args = append(args, pq.Array(assigneeIDs))
+			argIndex++
+		}
+	}
+
+	// Labels: match tasks carrying at least one of the given labels
+	if len(filters.LabelIDs) > 0 {
+		where += ` AND label_ids && $` + strconv.Itoa(argIndex)
+		args = append(args, pq.Array(filters.LabelIDs))
+		argIndex++
+	}
+
+	// Due date window (inclusive); tasks without a due date never match
+	if filters.DueAfter != nil || filters.DueBefore != nil {
+		where += ` AND due_date IS NOT NULL`
+	}
+
+	if filters.DueAfter != nil {
+		where += ` AND due_date >= $` + strconv.Itoa(argIndex)
+		args = append(args, *filters.DueAfter)
+		argIndex++
+	}
+
+	if filters.DueBefore != nil {
+		where += ` AND due_date <= $` + strconv.Itoa(argIndex)
+		args = append(args, *filters.DueBefore)
+		argIndex++
+	}
+
 	if filters.Overdue != nil && *filters.Overdue {
-		baseQuery += ` AND due_date < NOW() AND status != 'done'`
-		countQuery += ` AND due_date < NOW() AND status != 'done'`
+		where += ` AND due_date < NOW() AND status != 'done'`
 	}
 
 	if filters.Blocked != nil && *filters.Blocked {
-		baseQuery += ` AND blocked = true`
-		countQuery += ` AND blocked = true`
+		where += ` AND blocked = true`
 	}
 
-	// Get total count
-	var total int
-	err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&total)
-	if err != nil {
-		return nil, 0, err
+	if filters.Search != nil && strings.TrimSpace(*filters.Search) != "" {
+		escaped := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(strings.TrimSpace(*filters.Search))
+		where += ` AND title ILIKE $` + strconv.Itoa(argIndex)
+		args = append(args, "%"+escaped+"%")
 	}
 
-	// Add pagination
-	baseQuery += ` ORDER BY position ASC LIMIT $` + string(rune(argIndex)) + ` OFFSET $` + string(rune(argIndex+1))
-	args = append(args, filters.Limit, filters.Offset)
+	return where, args
+}
 
-	tasks, err := r.queryTasks(ctx, baseQuery, args...)
-	return tasks, total, err
+// Search finds tasks across the given projects by key or title.
+// Exact key matches come first, then title prefix matches, then substring matches.
+func (r *taskRepository) Search(ctx context.Context, projectIDs []string, query string, limit int) ([]*Task, error) {
+	if len(projectIDs) == 0 {
+		return []*Task{}, nil
+	}
+
+	escaped := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(query)
+
+	sqlQuery := `
+		SELECT ` + taskSelectColumns + `
+		FROM tasks 
+		WHERE project_id = ANY($1)
+		  AND (title ILIKE $2 OR ` + taskKeyExpr + ` ILIKE $3)
+		ORDER BY
+			CASE
+				WHEN UPPER(` + taskKeyExpr + `) = UPPER($4) THEN 0
+				WHEN title ILIKE $5 THEN 1
+				ELSE 2
+			END,
+			updated_at DESC
+		LIMIT $6`
+
+	return r.queryTasks(ctx, sqlQuery,
+		pq.Array(projectIDs),
+		"%"+escaped+"%",
+		escaped+"%",
+		query,
+		escaped+"%",
+		limit,
+	)
 }
 
+// FindOverdue returns overdue tasks of a project, or of all projects when projectID is empty
 func (r *taskRepository) FindOverdue(ctx context.Context, projectID string) ([]*Task, error) {
+	if projectID == "" {
+		query := `
+			SELECT ` + taskSelectColumns + `
+			FROM tasks 
+			WHERE due_date < NOW() AND status != 'done'
+			ORDER BY due_date ASC`
+		return r.queryTasks(ctx, query)
+	}
+
 	query := `
-		SELECT 
-			id, project_id, sprint_id, parent_task_id, title, description,
-			status, priority, type, assignee_ids, watcher_ids, label_ids,
-			story_points, estimated_hours, actual_hours, start_date, due_date,
-			completed_at, blocked, position, created_by, created_at, updated_at
+		SELECT ` + taskSelectColumns + `
 		FROM tasks 
 		WHERE project_id = $1 AND due_date < NOW() AND status != 'done'
 		ORDER BY due_date ASC`
 	return r.queryTasks(ctx, query, projectID)
 }
 
+func (r *taskRepository) FindDueForAssignee(ctx context.Context, assigneeID string, projectIDs []string) ([]*Task, error) {
+	if len(projectIDs) == 0 {
+		return nil, nil
+	}
+	query := `
+		SELECT ` + taskSelectColumns + `
+		FROM tasks
+		WHERE $1 = ANY(assignee_ids) AND project_id = ANY($2)
+			AND due_date IS NOT NULL AND status != 'done'
+		ORDER BY due_date ASC`
+	return r.queryTasks(ctx, query, assigneeID, pq.Array(projectIDs))
+}
+
 func (r *taskRepository) FindBlocked(ctx context.Context, projectID string) ([]*Task, error) {
 	query := `
-		SELECT 
-			id, project_id, sprint_id, parent_task_id, title, description,
-			status, priority, type, assignee_ids, watcher_ids, label_ids,
-			story_points, estimated_hours, actual_hours, start_date, due_date,
-			completed_at, blocked, position, created_by, created_at, updated_at
+		SELECT ` + taskSelectColumns + `
 		FROM tasks 
 		WHERE project_id = $1 AND blocked = true 
 		ORDER BY created_at DESC`
@@ -532,7 +1159,7 @@ func (r *taskRepository) BulkUpdateStatus(ctx context.Context, taskIDs []string,
 		UPDATE tasks SET 
 			status = $2,
 			completed_at = CASE WHEN $2 = 'done' THEN NOW() ELSE completed_at END,
-			updated_at = NOW()
+			updated_at = NOW(), version = version + 1
 		WHERE id = ANY($1)`
 	_, err := r.db.ExecContext(ctx, query, pq.Array(taskIDs), status)
 	return err
@@ -540,12 +1167,102 @@ func (r *taskRepository) BulkUpdateStatus(ctx context.Context, taskIDs []string,
 
 // BulkMoveToSprint moves multiple tasks to a sprint
 func (r *taskRepository) BulkMoveToSprint(ctx context.Context, taskIDs []string, sprintID string) error {
-	query := `UPDATE tasks SET sprint_id = $2, updated_at = NOW() WHERE id = ANY($1)`
+	query := `UPDATE tasks SET sprint_id = $2, updated_at = NOW(), version = version + 1 WHERE id = ANY($1)`
 	_, err := r.db.ExecContext(ctx, query, pq.Array(taskIDs), sprintID)
 	return err
 }
 
 
+// BulkDelete deletes the tasks and all their subtasks in one transaction.
+// Every deleted task gets a "task_deleted" entry in the project activity log,
+// since its own task_activities rows are removed along with it. Only ID,
+// ProjectID, ParentTaskID and Title are set on the returned tasks.
+func (r *taskRepository) BulkDelete(ctx context.Context, taskIDs []string, deletedBy string) ([]*Task, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Walk down the subtask tree, remembering which selected task pulled each subtask in
+	collectQuery := `
+		WITH RECURSIVE doomed AS (
+			SELECT id, project_id, parent_task_id, title, NULL::uuid AS cascaded_from
+			FROM tasks
+			WHERE id = ANY($1)
+			UNION ALL
+			SELECT t.id, t.project_id, t.parent_task_id, t.title, COALESCE(d.cascaded_from, d.id)
+			FROM tasks t
+			JOIN doomed d ON t.parent_task_id = d.id
+		)
+		SELECT id, project_id, parent_task_id, title, cascaded_from FROM doomed`
+
+	rows, err := tx.QueryContext(ctx, collectQuery, pq.Array(taskIDs))
+	if err != nil {
+		return nil, err
+	}
+
+	var deleted []*Task
+	cascadedFrom := make(map[string]*string)
+	for rows.Next() {
+		task := &Task{}
+		var origin *string
+		if err := rows.Scan(&task.ID, &task.ProjectID, &task.ParentTaskID, &task.Title, &origin); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		prev, seen := cascadedFrom[task.ID]
+		if !seen {
+			deleted = append(deleted, task)
+		}
+		// A subtask that was selected explicitly is logged as a direct delete
+		if !seen || (prev != nil && origin == nil) {
+			cascadedFrom[task.ID] = origin
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	activityQuery := `
+		INSERT INTO activities (type, description, user_id, project_id, metadata)
+		VALUES ('task_deleted', $1, $2, $3, $4)`
+
+	ids := make([]string, 0, len(deleted))
+	for _, task := range deleted {
+		ids = append(ids, task.ID)
+
+		description := "Deleted task \"" + task.Title + "\""
+		meta := map[string]interface{}{
+			"taskId":       task.ID,
+			"title":        task.Title,
+			"parentTaskId": task.ParentTaskID,
+			"bulk":         true,
+		}
+		if origin := cascadedFrom[task.ID]; origin != nil {
+			description = "Deleted subtask \"" + task.Title + "\" together with its parent task"
+			meta["cascadedFrom"] = *origin
+		}
+
+		metadata, err := json.Marshal(meta)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := tx.ExecContext(ctx, activityQuery, description, deletedBy, task.ProjectID, metadata); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM tasks WHERE id = ANY($1)`, pq.Array(ids)); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return deleted, nil
+}
 
 // UpdatePosition updates only the position field
 func (r *taskRepository) UpdatePosition(ctx context.Context, taskID string, position int) error {
@@ -555,6 +1272,59 @@ func (r *taskRepository) UpdatePosition(ctx context.Context, taskID string, posi
 }
 
 
+// FindColumnTasks returns top-level tasks of one board column (same project, sprint/backlog and status)
+func (r *taskRepository) FindColumnTasks(ctx context.Context, projectID string, sprintID *string, status string) ([]*Task, error) {
+	query := `
+		SELECT ` + taskSelectColumns + `
+		FROM tasks 
+		WHERE project_id = $1
+		  AND sprint_id IS NOT DISTINCT FROM $2::uuid
+		  AND status = $3
+		  AND parent_task_id IS NULL
+		ORDER BY position ASC, created_at ASC`
+	return r.queryTasks(ctx, query, projectID, sprintID, status)
+}
+
+// RenumberColumn re-spaces positions of a column by gap, keeping the current order
+func (r *taskRepository) RenumberColumn(ctx context.Context, projectID string, sprintID *string, status string, gap int) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Lock the column so concurrent reorders don't interleave
+	lockQuery := `
+		SELECT id FROM tasks
+		WHERE project_id = $1
+		  AND sprint_id IS NOT DISTINCT FROM $2::uuid
+		  AND status = $3
+		  AND parent_task_id IS NULL
+		FOR UPDATE`
+	rows, err := tx.QueryContext(ctx, lockQuery, projectID, sprintID, status)
+	if err != nil {
+		return err
+	}
+	rows.Close()
+
+	updateQuery := `
+		UPDATE tasks t SET position = ordered.rn * $4, updated_at = NOW()
+		FROM (
+			SELECT id, ROW_NUMBER() OVER (ORDER BY position ASC, created_at ASC) AS rn
+			FROM tasks
+			WHERE project_id = $1
+			  AND sprint_id IS NOT DISTINCT FROM $2::uuid
+			  AND status = $3
+			  AND parent_task_id IS NULL
+		) ordered
+		WHERE t.id = ordered.id`
+	if _, err := tx.ExecContext(ctx, updateQuery, projectID, sprintID, status, gap); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
 // queryTasks - FIXED with correct column order matching database
 func (r *taskRepository) queryTasks(ctx context.Context, query string, args ...interface{}) ([]*Task, error) {
 	rows, err := r.db.QueryContext(ctx, query, args...)
@@ -566,37 +1336,7 @@ func (r *taskRepository) queryTasks(ctx context.Context, query string, args ...i
 	var tasks []*Task
 	for rows.Next() {
 		task := &Task{}
-		// SCAN IN DATABASE COLUMN ORDER (from \d tasks):
-		// id, project_id, sprint_id, parent_task_id, title, description,
-		// status, priority, type, assignee_ids, watcher_ids, label_ids,
-		// story_points, estimated_hours, actual_hours, start_date, due_date,
-		// completed_at, blocked, position, created_by, created_at, updated_at
-		err := rows.Scan(
-			&task.ID,                    // 1. id
-			&task.ProjectID,             // 2. project_id
-			&task.SprintID,              // 3. sprint_id
-			&task.ParentTaskID,          // 4. parent_task_id
-			&task.Title,                 // 5. title
-			&task.Description,           // 6. description
-			&task.Status,                // 7. status
-			&task.Priority,              // 8. priority
-			&task.Type,                  // 9. type
-			pq.Array(&task.AssigneeIDs), // 10. assignee_ids
-			pq.Array(&task.WatcherIDs),  // 11. watcher_ids
-			pq.Array(&task.LabelIDs),    // 12. label_ids
-			&task.StoryPoints,           // 13. story_points
-			&task.EstimatedHours,        // 14. estimated_hours
-			&task.ActualHours,           // 15. actual_hours
-			&task.StartDate,             // 16. start_date
-			&task.DueDate,               // 17. due_date
-			&task.CompletedAt,           // 18. completed_at
-			&task.Blocked,               // 19. blocked
-			&task.Position,              // 20. position
-			&task.CreatedBy,             // 21. created_by
-			&task.CreatedAt,             // 22. created_at
-			&task.UpdatedAt,             // 23. updated_at
-		)
-		if err != nil {
+		if err := scanTask(rows, task); err != nil {
 			return nil, err
 		}
 		tasks = append(tasks, task)
@@ -605,3 +1345,42 @@ func (r *taskRepository) queryTasks(ctx context.Context, query string, args ...i
 	return tasks, rows.Err()
 }
 
+// scanTask reads the taskSelectColumns of one row. Queries that select extra
+// columns before taskSelectColumns pass their destinations as leading.
+func scanTask(row interface{ Scan(dest ...interface{}) error }, task *Task, leading ...interface{}) error {
+	// SCAN IN DATABASE COLUMN ORDER (from \d tasks):
+	// id, project_id, sprint_id, parent_task_id, title, description,
+	// status, priority, type, assignee_ids, watcher_ids, label_ids,
+	// story_points, estimated_hours, actual_hours, start_date, due_date,
+	// completed_at, blocked, position, created_by, created_at, updated_at,
+	// number, key, version
+	return row.Scan(append(leading,
+		&task.ID,                    // 1. id
+		&task.ProjectID,             // 2. project_id
+		&task.SprintID,              // 3. sprint_id
+		&task.ParentTaskID,          // 4. parent_task_id
+		&task.Title,                 // 5. title
+		&task.Description,           // 6. description
+		&task.Status,                // 7. status
+		&task.Priority,              // 8. priority
+		&task.Type,                  // 9. type
+			pq.Array(&task.AssigneeIDs), // 10. assignee_ids
+			pq.Array(&task.WatcherIDs),  // 11. watcher_ids
+			pq.Array(&task.LabelIDs),    // 12. label_ids
+		&task.StoryPoints,           // 13. story_points
+		&task.EstimatedHours,        // 14. estimated_hours
+		&task.ActualHours,           // 15. actual_hours
+		&task.StartDate,             // 16. start_date
+		&task.DueDate,               // 17. due_date
+		&task.CompletedAt,           // 18. completed_at
+		&task.Blocked,               // 19. blocked
+		&task.Position,              // 20. position
+		&task.CreatedBy,             // 21. created_by
+		&task.CreatedAt,             // 22. created_at
+		&task.UpdatedAt,             // 23. updated_at
+		&task.Number,                // 24. number
+		&task.Key,                   // 25. project key || '-' || number
+		&task.Version,               // 26. version
+	)...)
+}
+
@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// What happens to a parent task once all of its subtasks are done
+const (
+	ParentCompletionOff     = "off"
+	ParentCompletionSuggest = "suggest" // notify the parent's assignees
+	ParentCompletionAuto    = "auto"    // move the parent to done
+)
+
+// IsValidParentCompletion reports whether mode is one of the ParentCompletion values
+func IsValidParentCompletion(mode string) bool {
+	switch mode {
+	case ParentCompletionOff, ParentCompletionSuggest, ParentCompletionAuto:
+		return true
+	}
+	return false
+}
+
+// Where unfinished tasks go when an expired sprint is closed automatically
+const (
+	SprintCarryOverNextSprint = "next_sprint" // the next planned sprint, else the backlog
+	SprintCarryOverBacklog    = "backlog"
+)
+
+// IsValidSprintCarryOver reports whether mode is one of the SprintCarryOver values
+func IsValidSprintCarryOver(mode string) bool {
+	return mode == SprintCarryOverNextSprint || mode == SprintCarryOverBacklog
+}
+
+// ProjectTaskSettings holds per-project task behaviour
+type ProjectTaskSettings struct {
+	ProjectID        string    `json:"projectId" db:"project_id"`
+	ParentCompletion string    `json:"parentCompletion" db:"parent_completion"`
+	SprintCarryOver  string    `json:"sprintCarryOver" db:"sprint_carry_over"`
+	UpdatedAt        time.Time `json:"updatedAt" db:"updated_at"`
+}
+
+// TaskSettingsRepository interface
+type TaskSettingsRepository interface {
+	// Get returns the defaults when the project has no row
+	Get(ctx context.Context, projectID string) (*ProjectTaskSettings, error)
+	Save(ctx context.Context, settings *ProjectTaskSettings) error
+}
+
+// taskSettingsRepository implementation
+type taskSettingsRepository struct {
+	db *sql.DB
+}
+
+// NewTaskSettingsRepository creates a new TaskSettingsRepository
+func NewTaskSettingsRepository(db *sql.DB) TaskSettingsRepository {
+	return &taskSettingsRepository{db: db}
+}
+
+func (r *taskSettingsRepository) Get(ctx context.Context, projectID string) (*ProjectTaskSettings, error) {
+	query := `
+		SELECT project_id, parent_completion, sprint_carry_over, updated_at
+		FROM project_task_settings
+		WHERE project_id = $1`
+
+	s := &ProjectTaskSettings{}
+	err := r.db.QueryRowContext(ctx, query, projectID).Scan(&s.ProjectID, &s.ParentCompletion, &s.SprintCarryOver, &s.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return &ProjectTaskSettings{
+			ProjectID:        projectID,
+			ParentCompletion: ParentCompletionOff,
+			SprintCarryOver:  SprintCarryOverNextSprint,
+		}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (r *taskSettingsRepository) Save(ctx context.Context, settings *ProjectTaskSettings) error {
+	query := `
+		INSERT INTO project_task_settings (project_id, parent_completion, sprint_carry_over)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (project_id) DO UPDATE SET
+			parent_completion = EXCLUDED.parent_completion,
+			sprint_carry_over = EXCLUDED.sprint_carry_over,
+			updated_at = NOW()
+		RETURNING updated_at`
+
+	return r.db.QueryRowContext(ctx, query, settings.ProjectID, settings.ParentCompletion, settings.SprintCarryOver).
+		Scan(&settings.UpdatedAt)
+}
@@ -169,7 +169,7 @@ func (r *pgTeamRepository) FindMembers(ctx context.Context, teamID string) ([]*T
 		       u.id, u.email, u.name, u.avatar, u.status
 		FROM team_members tm
 		INNER JOIN users u ON tm.user_id = u.id
-		WHERE tm.team_id = $1
+		WHERE tm.team_id = $1 AND u.deleted_at IS NULL
 		ORDER BY tm.joined_at
 	`
 	rows, err := r.pool.Query(ctx, query, teamID)
@@ -211,7 +211,7 @@ func (r *pgTeamRepository) FindMember(ctx context.Context, teamID, userID string
 }
 
 func (r *pgTeamRepository) FindMemberUserIDs(ctx context.Context, teamID string) ([]string, error) {
-	query := `SELECT user_id FROM team_members WHERE team_id = $1`
+	query := `SELECT m.user_id FROM team_members m JOIN users u ON m.user_id = u.id WHERE m.team_id = $1 AND u.deleted_at IS NULL`
 	rows, err := r.pool.Query(ctx, query, teamID)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ProjectTemplate is a reusable project structure stored per workspace
+type ProjectTemplate struct {
+	ID              string
+	WorkspaceID     string
+	Name            string
+	Description     *string
+	Definition      TemplateDefinition
+	SourceProjectID *string
+	CreatedBy       *string
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+// TemplateDefinition is what a template provisions into a new project
+type TemplateDefinition struct {
+	Statuses []TemplateStatus `json:"statuses"`
+	Labels   []TemplateLabel  `json:"labels"`
+	Tasks    []TemplateTask   `json:"tasks"`
+}
+
+type TemplateStatus struct {
+	Key                string   `json:"key"`
+	Name               string   `json:"name"`
+	Color              *string  `json:"color,omitempty"`
+	AllowedTransitions []string `json:"allowedTransitions,omitempty"`
+}
+
+type TemplateLabel struct {
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+// TemplateTask is a seed task. Ref identifies it within the template so
+// subtasks can point at their parent; labels are referenced by name.
+type TemplateTask struct {
+	Ref            string              `json:"ref"`
+	ParentRef      *string             `json:"parentRef,omitempty"`
+	Title          string              `json:"title"`
+	Description    *string             `json:"description,omitempty"`
+	Status         string              `json:"status"`
+	Priority       string              `json:"priority"`
+	Type           *string             `json:"type,omitempty"`
+	StoryPoints    *int                `json:"storyPoints,omitempty"`
+	EstimatedHours *float64            `json:"estimatedHours,omitempty"`
+	Labels         []string            `json:"labels,omitempty"`
+	Checklists     []TemplateChecklist `json:"checklists,omitempty"`
+}
+
+type TemplateChecklist struct {
+	Title string   `json:"title"`
+	Items []string `json:"items"`
+}
+
+type TemplateRepository interface {
+	Create(ctx context.Context, template *ProjectTemplate) error
+	FindByID(ctx context.Context, id string) (*ProjectTemplate, error)
+	FindByWorkspaceID(ctx context.Context, workspaceID string) ([]*ProjectTemplate, error)
+	Delete(ctx context.Context, id string) error
+}
+
+type pgTemplateRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewTemplateRepository(pool *pgxpool.Pool) TemplateRepository {
+	return &pgTemplateRepository{pool: pool}
+}
+
+const templateColumns = `
+	id, workspace_id, name, description, definition, source_project_id, created_by, created_at, updated_at`
+
+func (r *pgTemplateRepository) Create(ctx context.Context, template *ProjectTemplate) error {
+	definition, err := json.Marshal(template.Definition)
+	if err != nil {
+		return err
+	}
+	query := `
+		INSERT INTO template_definitions (workspace_id, name, description, definition, source_project_id, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at, updated_at
+	`
+	return r.pool.QueryRow(ctx, query,
+		template.WorkspaceID, template.Name, template.Description, definition,
+		template.SourceProjectID, template.CreatedBy,
+	).Scan(&template.ID, &template.CreatedAt, &template.UpdatedAt)
+}
+
+func (r *pgTemplateRepository) FindByID(ctx context.Context, id string) (*ProjectTemplate, error) {
+	query := `SELECT ` + templateColumns + ` FROM template_definitions WHERE id = $1`
+	t, err := scanTemplate(r.pool.QueryRow(ctx, query, id))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	return t, err
+}
+
+func (r *pgTemplateRepository) FindByWorkspaceID(ctx context.Context, workspaceID string) ([]*ProjectTemplate, error) {
+	query := `SELECT ` + templateColumns + ` FROM template_definitions WHERE workspace_id = $1 ORDER BY name`
+	rows, err := r.pool.Query(ctx, query, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var templates []*ProjectTemplate
+	for rows.Next() {
+		t, err := scanTemplate(rows)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, t)
+	}
+	return templates, rows.Err()
+}
+
+func (r *pgTemplateRepository) Delete(ctx context.Context, id string) error {
+	_, err := r.pool.Exec(ctx, `DELETE FROM template_definitions WHERE id = $1`, id)
+	return err
+}
+
+func scanTemplate(row pgx.Row) (*ProjectTemplate, error) {
+	t := &ProjectTemplate{}
+	var definition []byte
+	if err := row.Scan(
+		&t.ID, &t.WorkspaceID, &t.Name, &t.Description, &definition,
+		&t.SourceProjectID, &t.CreatedBy, &t.CreatedAt, &t.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(definition, &t.Definition); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
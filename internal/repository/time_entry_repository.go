@@ -19,6 +19,14 @@ type TimeEntry struct {
 	CreatedAt       time.Time  `json:"createdAt" db:"created_at"`
 }
 
+// TaskTimeTotal is the time one user logged on one task
+type TaskTimeTotal struct {
+	UserID  string `json:"userId"`
+	TaskID  string `json:"taskId"`
+	Title   string `json:"title"`
+	Seconds int    `json:"seconds"`
+}
+
 // TimeEntryRepository interface
 type TimeEntryRepository interface {
 	Create(ctx context.Context, entry *TimeEntry) error
@@ -28,6 +36,8 @@ type TimeEntryRepository interface {
 	FindActiveTimer(ctx context.Context, userID string) (*TimeEntry, error)
 	StopTimer(ctx context.Context, id string) error
 	GetTotalTime(ctx context.Context, taskID string) (int, error)
+	SumByUserInRange(ctx context.Context, projectID string, from, to time.Time) (map[string]int, error)
+	SumByUserAndTaskInRange(ctx context.Context, projectID string, from, to time.Time) ([]*TaskTimeTotal, error)
 	Delete(ctx context.Context, id string) error
 }
 
@@ -212,6 +222,71 @@ func (r *timeEntryRepository) GetTotalTime(ctx context.Context, taskID string) (
 	return totalSeconds, err
 }
 
+// timeInRangeSeconds counts running timers up to now, like GetTotalTime
+const timeInRangeSeconds = `
+	COALESCE(SUM(
+		CASE
+			WHEN te.end_time IS NOT NULL THEN te.duration_seconds
+			ELSE EXTRACT(EPOCH FROM (NOW() - te.start_time))::INTEGER
+		END
+	), 0)`
+
+// SumByUserInRange totals seconds per user for entries in a project that
+// started in [from, to)
+func (r *timeEntryRepository) SumByUserInRange(ctx context.Context, projectID string, from, to time.Time) (map[string]int, error) {
+	query := `
+		SELECT te.user_id,` + timeInRangeSeconds + `
+		FROM time_entries te
+		JOIN tasks t ON t.id = te.task_id
+		WHERE t.project_id = $1 AND te.start_time >= $2 AND te.start_time < $3
+		GROUP BY te.user_id`
+
+	rows, err := r.db.QueryContext(ctx, query, projectID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	totals := make(map[string]int)
+	for rows.Next() {
+		var userID string
+		var seconds int
+		if err := rows.Scan(&userID, &seconds); err != nil {
+			return nil, err
+		}
+		totals[userID] = seconds
+	}
+	return totals, rows.Err()
+}
+
+// SumByUserAndTaskInRange totals seconds per user and task for entries in a
+// project that started in [from, to), largest first
+func (r *timeEntryRepository) SumByUserAndTaskInRange(ctx context.Context, projectID string, from, to time.Time) ([]*TaskTimeTotal, error) {
+	query := `
+		SELECT te.user_id, t.id, t.title,` + timeInRangeSeconds + ` AS seconds
+		FROM time_entries te
+		JOIN tasks t ON t.id = te.task_id
+		WHERE t.project_id = $1 AND te.start_time >= $2 AND te.start_time < $3
+		GROUP BY te.user_id, t.id, t.title
+		ORDER BY te.user_id, seconds DESC`
+
+	rows, err := r.db.QueryContext(ctx, query, projectID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var totals []*TaskTimeTotal
+	for rows.Next() {
+		total := &TaskTimeTotal{}
+		if err := rows.Scan(&total.UserID, &total.TaskID, &total.Title, &total.Seconds); err != nil {
+			return nil, err
+		}
+		totals = append(totals, total)
+	}
+	return totals, rows.Err()
+}
+
 // Delete removes a time entry
 func (r *timeEntryRepository) Delete(ctx context.Context, id string) error {
 	query := `DELETE FROM time_entries WHERE id = $1`
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/jackc/pgx/v5"
@@ -18,30 +19,60 @@ type User struct {
 	LastActiveAt *time.Time
 	CreatedAt    time.Time
 	UpdatedAt    time.Time
+	DeletedAt    *time.Time // set once the account is deleted; the row stays for history
 }
 
+// IntegrationBotUserID is the user that authors comments posted for source
+// control events. It cannot sign in and is left out of user lists.
+const IntegrationBotUserID = "00000000-0000-0000-0000-00000000b070"
+
+// ErrRefreshTokenUsed is returned when rotating a refresh token that was already rotated
+var ErrRefreshTokenUsed = errors.New("refresh token already used")
+
 type RefreshToken struct {
 	ID        string
 	Token     string
 	UserID    string
 	ExpiresAt time.Time
+	UsedAt    *time.Time // set once the token has been rotated away
 	CreatedAt time.Time
 }
 
 type UserRepository interface {
 	Create(ctx context.Context, user *User) error
 	FindByID(ctx context.Context, id string) (*User, error)
+	// FindByIDs returns the users that exist among ids, deleted ones included,
+	// in no particular order
+	FindByIDs(ctx context.Context, ids []string) ([]*User, error)
 	FindByEmail(ctx context.Context, email string) (*User, error)
 	FindByName(ctx context.Context, name string) (*User, error)
 	FindAll(ctx context.Context) ([]*User, error)
 	Search(ctx context.Context, query string) ([]*User, error)
 	Update(ctx context.Context, user *User) error
 	UpdateLastActive(ctx context.Context, userID string) error
-	UpdateStatusForInactive(ctx context.Context, inactiveDuration time.Duration) error
+	UpdateStatus(ctx context.Context, userID, status string) error
+	// UpdateStatusForInactive marks online users away after inactiveDuration,
+	// except those in exclude, and returns the IDs it changed
+	UpdateStatusForInactive(ctx context.Context, inactiveDuration time.Duration, exclude []string) ([]string, error)
+	// UpdateStatusForOffline marks online or away users offline after
+	// inactiveDuration, except those in exclude, and returns the IDs it changed
+	UpdateStatusForOffline(ctx context.Context, inactiveDuration time.Duration, exclude []string) ([]string, error)
 	SaveRefreshToken(ctx context.Context, token *RefreshToken) error
 	FindRefreshToken(ctx context.Context, token string) (*RefreshToken, error)
 	DeleteRefreshToken(ctx context.Context, token string) error
+	// RotateRefreshToken marks oldToken used and saves next in one transaction;
+	// returns ErrRefreshTokenUsed if oldToken was already rotated
+	RotateRefreshToken(ctx context.Context, oldToken string, next *RefreshToken) error
 	DeleteUserRefreshTokens(ctx context.Context, userID string) error
+	// DeleteExpiredRefreshTokens removes tokens past their expiry, used or
+	// not, and returns how many were deleted
+	DeleteExpiredRefreshTokens(ctx context.Context) (int64, error)
+	// SoftDelete marks the user deleted and scrubs their personal data. The
+	// row is kept so that history referencing the user still resolves. In the
+	// same transaction it removes the user's memberships and revokes every
+	// credential they hold: refresh tokens, the calendar feed token and the
+	// source control integration tokens they created.
+	SoftDelete(ctx context.Context, userID string) error
 }
 
 type pgUserRepository struct {
@@ -70,13 +101,13 @@ func (r *pgUserRepository) Create(ctx context.Context, user *User) error {
 
 func (r *pgUserRepository) FindByID(ctx context.Context, id string) (*User, error) {
 	query := `
-		SELECT id, email, password, name, avatar, status, last_active_at, created_at, updated_at
+		SELECT id, email, password, name, avatar, status, last_active_at, created_at, updated_at, deleted_at
 		FROM users WHERE id = $1
 	`
 	user := &User{}
 	err := r.pool.QueryRow(ctx, query, id).Scan(
 		&user.ID, &user.Email, &user.Password, &user.Name, &user.Avatar,
-		&user.Status, &user.LastActiveAt, &user.CreatedAt, &user.UpdatedAt,
+		&user.Status, &user.LastActiveAt, &user.CreatedAt, &user.UpdatedAt, &user.DeletedAt,
 	)
 	if err == pgx.ErrNoRows {
 		return nil, nil
@@ -87,15 +118,43 @@ func (r *pgUserRepository) FindByID(ctx context.Context, id string) (*User, erro
 	return user, nil
 }
 
+func (r *pgUserRepository) FindByIDs(ctx context.Context, ids []string) ([]*User, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	query := `
+		SELECT id, email, password, name, avatar, status, last_active_at, created_at, updated_at, deleted_at
+		FROM users WHERE id = ANY($1::uuid[])
+	`
+	rows, err := r.pool.Query(ctx, query, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []*User
+	for rows.Next() {
+		user := &User{}
+		if err := rows.Scan(
+			&user.ID, &user.Email, &user.Password, &user.Name, &user.Avatar,
+			&user.Status, &user.LastActiveAt, &user.CreatedAt, &user.UpdatedAt, &user.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	return users, rows.Err()
+}
+
 func (r *pgUserRepository) FindByEmail(ctx context.Context, email string) (*User, error) {
 	query := `
-		SELECT id, email, password, name, avatar, status, last_active_at, created_at, updated_at
-		FROM users WHERE LOWER(email) = LOWER($1)
+		SELECT id, email, password, name, avatar, status, last_active_at, created_at, updated_at, deleted_at
+		FROM users WHERE LOWER(email) = LOWER($1) AND deleted_at IS NULL
 	`
 	user := &User{}
 	err := r.pool.QueryRow(ctx, query, email).Scan(
 		&user.ID, &user.Email, &user.Password, &user.Name, &user.Avatar,
-		&user.Status, &user.LastActiveAt, &user.CreatedAt, &user.UpdatedAt,
+		&user.Status, &user.LastActiveAt, &user.CreatedAt, &user.UpdatedAt, &user.DeletedAt,
 	)
 	if err == pgx.ErrNoRows {
 		return nil, nil
@@ -108,14 +167,14 @@ func (r *pgUserRepository) FindByEmail(ctx context.Context, email string) (*User
 
 func (r *pgUserRepository) FindByName(ctx context.Context, name string) (*User, error) {
 	query := `
-		SELECT id, email, password, name, avatar, status, last_active_at, created_at, updated_at
-		FROM users WHERE LOWER(name) LIKE LOWER($1)
+		SELECT id, email, password, name, avatar, status, last_active_at, created_at, updated_at, deleted_at
+		FROM users WHERE LOWER(name) LIKE LOWER($1) AND deleted_at IS NULL AND status IS DISTINCT FROM 'bot'
 		LIMIT 1
 	`
 	user := &User{}
 	err := r.pool.QueryRow(ctx, query, "%"+name+"%").Scan(
 		&user.ID, &user.Email, &user.Password, &user.Name, &user.Avatar,
-		&user.Status, &user.LastActiveAt, &user.CreatedAt, &user.UpdatedAt,
+		&user.Status, &user.LastActiveAt, &user.CreatedAt, &user.UpdatedAt, &user.DeletedAt,
 	)
 	if err == pgx.ErrNoRows {
 		return nil, nil
@@ -128,8 +187,8 @@ func (r *pgUserRepository) FindByName(ctx context.Context, name string) (*User,
 
 func (r *pgUserRepository) FindAll(ctx context.Context) ([]*User, error) {
 	query := `
-		SELECT id, email, password, name, avatar, status, last_active_at, created_at, updated_at
-		FROM users ORDER BY name
+		SELECT id, email, password, name, avatar, status, last_active_at, created_at, updated_at, deleted_at
+		FROM users WHERE deleted_at IS NULL AND status IS DISTINCT FROM 'bot' ORDER BY name
 	`
 	rows, err := r.pool.Query(ctx, query)
 	if err != nil {
@@ -142,7 +201,7 @@ func (r *pgUserRepository) FindAll(ctx context.Context) ([]*User, error) {
 		user := &User{}
 		if err := rows.Scan(
 			&user.ID, &user.Email, &user.Password, &user.Name, &user.Avatar,
-			&user.Status, &user.LastActiveAt, &user.CreatedAt, &user.UpdatedAt,
+			&user.Status, &user.LastActiveAt, &user.CreatedAt, &user.UpdatedAt, &user.DeletedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -154,9 +213,9 @@ func (r *pgUserRepository) FindAll(ctx context.Context) ([]*User, error) {
 func (r *pgUserRepository) Search(ctx context.Context, query string) ([]*User, error) {
 	searchQuery := "%" + query + "%"
 	sqlQuery := `
-		SELECT id, email, password, name, avatar, status, last_active_at, created_at, updated_at
+		SELECT id, email, password, name, avatar, status, last_active_at, created_at, updated_at, deleted_at
 		FROM users
-		WHERE LOWER(name) LIKE LOWER($1) OR LOWER(email) LIKE LOWER($1)
+		WHERE (LOWER(name) LIKE LOWER($1) OR LOWER(email) LIKE LOWER($1)) AND deleted_at IS NULL AND status IS DISTINCT FROM 'bot'
 		ORDER BY name
 		LIMIT 20
 	`
@@ -171,7 +230,7 @@ func (r *pgUserRepository) Search(ctx context.Context, query string) ([]*User, e
 		user := &User{}
 		if err := rows.Scan(
 			&user.ID, &user.Email, &user.Password, &user.Name, &user.Avatar,
-			&user.Status, &user.LastActiveAt, &user.CreatedAt, &user.UpdatedAt,
+			&user.Status, &user.LastActiveAt, &user.CreatedAt, &user.UpdatedAt, &user.DeletedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -180,6 +239,44 @@ func (r *pgUserRepository) Search(ctx context.Context, query string) ([]*User, e
 	return users, nil
 }
 
+// DeletedUserName replaces the name of a deleted user
+const DeletedUserName = "Deleted user"
+
+func (r *pgUserRepository) SoftDelete(ctx context.Context, userID string) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		UPDATE users
+		SET deleted_at = NOW(), name = $2, email = 'deleted-' || id || '@deleted.invalid',
+		    password = '', avatar = NULL, status = 'offline', updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL
+	`
+	if _, err := tx.Exec(ctx, query, userID, DeletedUserName); err != nil {
+		return err
+	}
+
+	for _, query := range []string{
+		`DELETE FROM workspace_members WHERE user_id = $1`,
+		`DELETE FROM space_members WHERE user_id = $1`,
+		`DELETE FROM folder_members WHERE user_id = $1`,
+		`DELETE FROM project_members WHERE user_id = $1`,
+		`DELETE FROM team_members WHERE user_id = $1`,
+		`DELETE FROM refresh_tokens WHERE user_id = $1`,
+		`DELETE FROM calendar_feed_tokens WHERE user_id = $1`,
+		`DELETE FROM workspace_integration_tokens WHERE created_by = $1`,
+	} {
+		if _, err := tx.Exec(ctx, query, userID); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
 func (r *pgUserRepository) Update(ctx context.Context, user *User) error {
 	query := `
 		UPDATE users SET email = $2, name = $3, avatar = $4, status = $5, updated_at = NOW()
@@ -195,14 +292,56 @@ func (r *pgUserRepository) UpdateLastActive(ctx context.Context, userID string)
 	return err
 }
 
-func (r *pgUserRepository) UpdateStatusForInactive(ctx context.Context, inactiveDuration time.Duration) error {
+func (r *pgUserRepository) UpdateStatus(ctx context.Context, userID, status string) error {
+	query := `UPDATE users SET status = $2 WHERE id = $1`
+	_, err := r.pool.Exec(ctx, query, userID, status)
+	return err
+}
+
+func (r *pgUserRepository) UpdateStatusForInactive(ctx context.Context, inactiveDuration time.Duration, exclude []string) ([]string, error) {
 	query := `
 		UPDATE users SET status = 'away'
-		WHERE status = 'online' AND last_active_at < $1
+		WHERE status = 'online' AND last_active_at < $1 AND id::text <> ALL($2)
+		RETURNING id
 	`
 	threshold := time.Now().Add(-inactiveDuration)
-	_, err := r.pool.Exec(ctx, query, threshold)
-	return err
+	return r.updateStatusReturning(ctx, query, threshold, exclude)
+}
+
+// updateStatusReturning runs a status UPDATE ... RETURNING id whose $2 is a
+// list of user IDs to leave alone
+func (r *pgUserRepository) updateStatusReturning(ctx context.Context, query string, threshold time.Time, exclude []string) ([]string, error) {
+	// A nil slice is sent as NULL, which would make "<> ALL" match nothing
+	if exclude == nil {
+		exclude = []string{}
+	}
+
+	rows, err := r.pool.Query(ctx, query, threshold, exclude)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// UpdateStatusForOffline marks online or away users offline after a longer inactivity
+func (r *pgUserRepository) UpdateStatusForOffline(ctx context.Context, inactiveDuration time.Duration, exclude []string) ([]string, error) {
+	query := `
+		UPDATE users SET status = 'offline'
+		WHERE status IN ('online', 'away') AND last_active_at < $1 AND id::text <> ALL($2)
+		RETURNING id
+	`
+	threshold := time.Now().Add(-inactiveDuration)
+	return r.updateStatusReturning(ctx, query, threshold, exclude)
 }
 
 func (r *pgUserRepository) SaveRefreshToken(ctx context.Context, token *RefreshToken) error {
@@ -217,12 +356,12 @@ func (r *pgUserRepository) SaveRefreshToken(ctx context.Context, token *RefreshT
 
 func (r *pgUserRepository) FindRefreshToken(ctx context.Context, token string) (*RefreshToken, error) {
 	query := `
-		SELECT id, token, user_id, expires_at, created_at
+		SELECT id, token, user_id, expires_at, used_at, created_at
 		FROM refresh_tokens WHERE token = $1
 	`
 	rt := &RefreshToken{}
 	err := r.pool.QueryRow(ctx, query, token).Scan(
-		&rt.ID, &rt.Token, &rt.UserID, &rt.ExpiresAt, &rt.CreatedAt,
+		&rt.ID, &rt.Token, &rt.UserID, &rt.ExpiresAt, &rt.UsedAt, &rt.CreatedAt,
 	)
 	if err == pgx.ErrNoRows {
 		return nil, nil
@@ -239,8 +378,49 @@ func (r *pgUserRepository) DeleteRefreshToken(ctx context.Context, token string)
 	return err
 }
 
+func (r *pgUserRepository) RotateRefreshToken(ctx context.Context, oldToken string, next *RefreshToken) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// The used_at guard makes concurrent rotations of the same token race safely
+	tag, err := tx.Exec(ctx, `
+		UPDATE refresh_tokens SET used_at = NOW()
+		WHERE token = $1 AND used_at IS NULL
+	`, oldToken)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrRefreshTokenUsed
+	}
+
+	err = tx.QueryRow(ctx, `
+		INSERT INTO refresh_tokens (token, user_id, expires_at)
+		VALUES ($1, $2, $3)
+		RETURNING id, created_at
+	`, next.Token, next.UserID, next.ExpiresAt).Scan(&next.ID, &next.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
 func (r *pgUserRepository) DeleteUserRefreshTokens(ctx context.Context, userID string) error {
 	query := `DELETE FROM refresh_tokens WHERE user_id = $1`
 	_, err := r.pool.Exec(ctx, query, userID)
 	return err
 }
+
+// Used tokens are only kept to detect replays, which an expired token can no
+// longer be used for, so both kinds go once they expire
+func (r *pgUserRepository) DeleteExpiredRefreshTokens(ctx context.Context) (int64, error) {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM refresh_tokens WHERE expires_at < NOW()`)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Webhook is an external endpoint that receives a workspace's events
+type Webhook struct {
+	ID          string
+	WorkspaceID string
+	URL         string
+	Secret      string
+	Events      []string
+	Active      bool
+	CreatedBy   *string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// WebhookDeadLetter is a delivery that failed on every attempt
+type WebhookDeadLetter struct {
+	ID         string
+	WebhookID  string
+	Event      string
+	Payload    []byte
+	Attempts   int
+	StatusCode *int
+	LastError  string
+	CreatedAt  time.Time
+}
+
+type WebhookRepository interface {
+	Create(ctx context.Context, webhook *Webhook) error
+	FindByID(ctx context.Context, id string) (*Webhook, error)
+	FindByWorkspaceID(ctx context.Context, workspaceID string) ([]*Webhook, error)
+	// FindSubscribed returns the active webhooks of the workspace subscribed to the event
+	FindSubscribed(ctx context.Context, workspaceID, event string) ([]*Webhook, error)
+	Update(ctx context.Context, webhook *Webhook) error
+	Delete(ctx context.Context, id string) error
+
+	CreateDeadLetter(ctx context.Context, letter *WebhookDeadLetter) error
+	// FindDeadLetters returns the webhook's most recent failed deliveries first
+	FindDeadLetters(ctx context.Context, webhookID string, limit int) ([]*WebhookDeadLetter, error)
+}
+
+type pgWebhookRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewWebhookRepository(pool *pgxpool.Pool) WebhookRepository {
+	return &pgWebhookRepository{pool: pool}
+}
+
+const webhookColumns = `id, workspace_id, url, secret, events, active, created_by, created_at, updated_at`
+
+func (r *pgWebhookRepository) Create(ctx context.Context, webhook *Webhook) error {
+	query := `
+		INSERT INTO webhooks (workspace_id, url, secret, events, active, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at, updated_at
+	`
+	return r.pool.QueryRow(ctx, query,
+		webhook.WorkspaceID, webhook.URL, webhook.Secret, webhook.Events, webhook.Active, webhook.CreatedBy,
+	).Scan(&webhook.ID, &webhook.CreatedAt, &webhook.UpdatedAt)
+}
+
+func (r *pgWebhookRepository) FindByID(ctx context.Context, id string) (*Webhook, error) {
+	query := `SELECT ` + webhookColumns + ` FROM webhooks WHERE id = $1`
+	w, err := scanWebhook(r.pool.QueryRow(ctx, query, id))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	return w, err
+}
+
+func (r *pgWebhookRepository) FindByWorkspaceID(ctx context.Context, workspaceID string) ([]*Webhook, error) {
+	query := `SELECT ` + webhookColumns + ` FROM webhooks WHERE workspace_id = $1 ORDER BY created_at`
+	return r.queryWebhooks(ctx, query, workspaceID)
+}
+
+func (r *pgWebhookRepository) FindSubscribed(ctx context.Context, workspaceID, event string) ([]*Webhook, error) {
+	query := `
+		SELECT ` + webhookColumns + ` FROM webhooks
+		WHERE workspace_id = $1 AND active AND $2 = ANY(events)
+	`
+	return r.queryWebhooks(ctx, query, workspaceID, event)
+}
+
+func (r *pgWebhookRepository) Update(ctx context.Context, webhook *Webhook) error {
+	query := `
+		UPDATE webhooks SET url = $2, secret = $3, events = $4, active = $5, updated_at = NOW()
+		WHERE id = $1
+		RETURNING updated_at
+	`
+	return r.pool.QueryRow(ctx, query,
+		webhook.ID, webhook.URL, webhook.Secret, webhook.Events, webhook.Active,
+	).Scan(&webhook.UpdatedAt)
+}
+
+func (r *pgWebhookRepository) Delete(ctx context.Context, id string) error {
+	_, err := r.pool.Exec(ctx, `DELETE FROM webhooks WHERE id = $1`, id)
+	return err
+}
+
+func (r *pgWebhookRepository) CreateDeadLetter(ctx context.Context, letter *WebhookDeadLetter) error {
+	query := `
+		INSERT INTO webhook_dead_letters (webhook_id, event, payload, attempts, status_code, last_error)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at
+	`
+	return r.pool.QueryRow(ctx, query,
+		letter.WebhookID, letter.Event, string(letter.Payload), letter.Attempts, letter.StatusCode, letter.LastError,
+	).Scan(&letter.ID, &letter.CreatedAt)
+}
+
+func (r *pgWebhookRepository) FindDeadLetters(ctx context.Context, webhookID string, limit int) ([]*WebhookDeadLetter, error) {
+	query := `
+		SELECT id, webhook_id, event, payload, attempts, status_code, last_error, created_at
+		FROM webhook_dead_letters
+		WHERE webhook_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2
+	`
+	rows, err := r.pool.Query(ctx, query, webhookID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var letters []*WebhookDeadLetter
+	for rows.Next() {
+		l := &WebhookDeadLetter{}
+		if err := rows.Scan(
+			&l.ID, &l.WebhookID, &l.Event, &l.Payload, &l.Attempts, &l.StatusCode, &l.LastError, &l.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		letters = append(letters, l)
+	}
+	return letters, rows.Err()
+}
+
+func (r *pgWebhookRepository) queryWebhooks(ctx context.Context, query string, args ...interface{}) ([]*Webhook, error) {
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var webhooks []*Webhook
+	for rows.Next() {
+		w, err := scanWebhook(rows)
+		if err != nil {
+			return nil, err
+		}
+		webhooks = append(webhooks, w)
+	}
+	return webhooks, rows.Err()
+}
+
+func scanWebhook(row pgx.Row) (*Webhook, error) {
+	w := &Webhook{}
+	if err := row.Scan(
+		&w.ID, &w.WorkspaceID, &w.URL, &w.Secret, &w.Events, &w.Active,
+		&w.CreatedBy, &w.CreatedAt, &w.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
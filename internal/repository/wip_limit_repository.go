@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// WIPLimit caps the number of tasks a project allows in a given status
+type WIPLimit struct {
+	ID        string    `json:"id" db:"id"`
+	ProjectID string    `json:"projectId" db:"project_id"`
+	Status    string    `json:"status" db:"status"`
+	MaxTasks  int       `json:"maxTasks" db:"max_tasks"`
+	CreatedAt time.Time `json:"createdAt" db:"created_at"`
+	UpdatedAt time.Time `json:"updatedAt" db:"updated_at"`
+}
+
+// WIPLimitRepository interface
+type WIPLimitRepository interface {
+	Upsert(ctx context.Context, limit *WIPLimit) error
+	Delete(ctx context.Context, projectID, status string) error
+	FindByProjectID(ctx context.Context, projectID string) ([]*WIPLimit, error)
+	FindByStatus(ctx context.Context, projectID, status string) (*WIPLimit, error)
+}
+
+// wipLimitRepository implementation
+type wipLimitRepository struct {
+	db *sql.DB
+}
+
+// NewWIPLimitRepository creates a new WIPLimitRepository
+func NewWIPLimitRepository(db *sql.DB) WIPLimitRepository {
+	return &wipLimitRepository{db: db}
+}
+
+// Upsert creates or updates the limit for a project column
+func (r *wipLimitRepository) Upsert(ctx context.Context, limit *WIPLimit) error {
+	query := `
+		INSERT INTO project_wip_limits (project_id, status, max_tasks)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (project_id, status) DO UPDATE SET
+			max_tasks = EXCLUDED.max_tasks,
+			updated_at = NOW()
+		RETURNING id, created_at, updated_at`
+
+	return r.db.QueryRowContext(ctx, query, limit.ProjectID, limit.Status, limit.MaxTasks).
+		Scan(&limit.ID, &limit.CreatedAt, &limit.UpdatedAt)
+}
+
+// Delete removes the limit for a project column
+func (r *wipLimitRepository) Delete(ctx context.Context, projectID, status string) error {
+	query := `DELETE FROM project_wip_limits WHERE project_id = $1 AND status = $2`
+	_, err := r.db.ExecContext(ctx, query, projectID, status)
+	return err
+}
+
+// FindByProjectID lists all column limits of a project
+func (r *wipLimitRepository) FindByProjectID(ctx context.Context, projectID string) ([]*WIPLimit, error) {
+	query := `
+		SELECT id, project_id, status, max_tasks, created_at, updated_at
+		FROM project_wip_limits
+		WHERE project_id = $1
+		ORDER BY status ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var limits []*WIPLimit
+	for rows.Next() {
+		limit := &WIPLimit{}
+		if err := rows.Scan(
+			&limit.ID, &limit.ProjectID, &limit.Status, &limit.MaxTasks,
+			&limit.CreatedAt, &limit.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		limits = append(limits, limit)
+	}
+	return limits, rows.Err()
+}
+
+// FindByStatus retrieves the limit of a single column, nil if none is set
+func (r *wipLimitRepository) FindByStatus(ctx context.Context, projectID, status string) (*WIPLimit, error) {
+	query := `
+		SELECT id, project_id, status, max_tasks, created_at, updated_at
+		FROM project_wip_limits
+		WHERE project_id = $1 AND status = $2`
+
+	limit := &WIPLimit{}
+	err := r.db.QueryRowContext(ctx, query, projectID, status).Scan(
+		&limit.ID, &limit.ProjectID, &limit.Status, &limit.MaxTasks,
+		&limit.CreatedAt, &limit.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return limit, nil
+}
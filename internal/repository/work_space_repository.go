@@ -148,10 +148,10 @@ func (r *pgWorkspaceRepository) AddMember(ctx context.Context, member *Workspace
 func (r *pgWorkspaceRepository) FindMembers(ctx context.Context, workspaceID string) ([]*WorkspaceMember, error) {
 	query := `
 		SELECT wm.id, wm.workspace_id, wm.user_id, wm.role, wm.joined_at,
-		       u.id, u.email, u.name, u.avatar, u.status
+		       u.id, u.email, u.name, u.avatar, u.status, u.last_active_at
 		FROM workspace_members wm
 		JOIN users u ON wm.user_id = u.id
-		WHERE wm.workspace_id = $1
+		WHERE wm.workspace_id = $1 AND u.deleted_at IS NULL
 		ORDER BY wm.joined_at
 	`
 	rows, err := r.pool.Query(ctx, query, workspaceID)
@@ -165,7 +165,7 @@ func (r *pgWorkspaceRepository) FindMembers(ctx context.Context, workspaceID str
 		m := &WorkspaceMember{User: &User{}}
 		if err := rows.Scan(
 			&m.ID, &m.WorkspaceID, &m.UserID, &m.Role, &m.JoinedAt,
-			&m.User.ID, &m.User.Email, &m.User.Name, &m.User.Avatar, &m.User.Status,
+			&m.User.ID, &m.User.Email, &m.User.Name, &m.User.Avatar, &m.User.Status, &m.User.LastActiveAt,
 		); err != nil {
 			return nil, err
 		}
@@ -193,7 +193,7 @@ func (r *pgWorkspaceRepository) FindMember(ctx context.Context, workspaceID, use
 }
 
 func (r *pgWorkspaceRepository) FindMemberUserIDs(ctx context.Context, workspaceID string) ([]string, error) {
-	query := `SELECT user_id FROM workspace_members WHERE workspace_id = $1`
+	query := `SELECT m.user_id FROM workspace_members m JOIN users u ON m.user_id = u.id WHERE m.workspace_id = $1 AND u.deleted_at IS NULL`
 	rows, err := r.pool.Query(ctx, query, workspaceID)
 	if err != nil {
 		return nil, err
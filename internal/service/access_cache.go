@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/db"
+	"github.com/redis/go-redis/v9"
+)
+
+// DefaultAccessCacheTTL bounds how long a cached access grant can outlive a
+// membership change made outside MemberService
+const DefaultAccessCacheTTL = 30 * time.Second
+
+// AccessCache memoizes HasEffectiveAccess grants in Redis, keyed by user and
+// entity. Only grants are cached, so a user who just joined through any path
+// is never turned away by a stale entry. Without Redis every lookup misses
+// and access is computed directly.
+type AccessCache struct {
+	redis *db.RedisDB
+	ttl   time.Duration
+
+	hits   atomic.Int64
+	misses atomic.Int64
+}
+
+// cachedAccess is the stored result of a granted access check
+type cachedAccess struct {
+	InheritedFrom string `json:"inheritedFrom"`
+}
+
+// CacheStats is reported on the health endpoint
+type CacheStats struct {
+	Enabled bool    `json:"enabled"`
+	Hits    int64   `json:"hits"`
+	Misses  int64   `json:"misses"`
+	HitRate float64 `json:"hitRate"`
+}
+
+// NewAccessCache creates an AccessCache; redisDB may be nil
+func NewAccessCache(redisDB *db.RedisDB, ttl time.Duration) *AccessCache {
+	if ttl <= 0 {
+		ttl = DefaultAccessCacheTTL
+	}
+	return &AccessCache{redis: redisDB, ttl: ttl}
+}
+
+func accessCacheKey(userID, entityType, entityID string) string {
+	return "access:" + userID + ":" + entityType + ":" + entityID
+}
+
+// accessIndexKey names the set holding every cached grant key of a user
+func accessIndexKey(userID string) string {
+	return "access-keys:" + userID
+}
+
+// get returns the cached grant, if any
+func (c *AccessCache) get(ctx context.Context, userID, entityType, entityID string) (string, bool) {
+	if c == nil || c.redis == nil {
+		return "", false
+	}
+	var entry cachedAccess
+	if err := c.redis.GetCache(ctx, accessCacheKey(userID, entityType, entityID), &entry); err != nil {
+		if err != redis.Nil {
+			slog.WarnContext(ctx, "access cache read failed", "error", err)
+		}
+		c.misses.Add(1)
+		return "", false
+	}
+	c.hits.Add(1)
+	return entry.InheritedFrom, true
+}
+
+// put stores a grant
+func (c *AccessCache) put(ctx context.Context, userID, entityType, entityID, inheritedFrom string) {
+	if c == nil || c.redis == nil {
+		return
+	}
+	entry := cachedAccess{InheritedFrom: inheritedFrom}
+	if err := c.redis.SetIndexedCache(ctx, accessIndexKey(userID), accessCacheKey(userID, entityType, entityID), entry, c.ttl); err != nil {
+		slog.WarnContext(ctx, "access cache write failed", "error", err)
+	}
+}
+
+// InvalidateUser drops every cached grant of the user. A membership change
+// can affect the user's access to the whole subtree below the entity, so the
+// grants are tracked in a per-user set rather than found by pattern.
+func (c *AccessCache) InvalidateUser(ctx context.Context, userID string) {
+	if c == nil || c.redis == nil {
+		return
+	}
+	if err := c.redis.InvalidateIndex(ctx, accessIndexKey(userID)); err != nil {
+		slog.WarnContext(ctx, "access cache invalidation failed", "userID", userID, "error", err)
+	}
+}
+
+// Stats returns the hit counts since startup
+func (c *AccessCache) Stats() CacheStats {
+	if c == nil {
+		return CacheStats{}
+	}
+	return newCacheStats(c.redis != nil, c.hits.Load(), c.misses.Load())
+}
+
+func newCacheStats(enabled bool, hits, misses int64) CacheStats {
+	stats := CacheStats{Enabled: enabled, Hits: hits, Misses: misses}
+	if total := hits + misses; total > 0 {
+		stats.HitRate = float64(hits) / float64(total)
+	}
+	return stats
+}
@@ -0,0 +1,272 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/models"
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/notification"
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/repository"
+)
+
+// ============================================
+// Access Request Service
+// ============================================
+
+// Access request statuses
+const (
+	AccessRequestPending  = "pending"
+	AccessRequestApproved = "approved"
+	AccessRequestDenied   = "denied"
+)
+
+type AccessRequestService interface {
+	RequestAccess(ctx context.Context, workspaceID, requesterID string, req *models.CreateAccessRequestRequest) (*repository.AccessRequest, error)
+	ListPending(ctx context.Context, targetType, targetID, userID string) ([]*repository.AccessRequest, error)
+	Approve(ctx context.Context, requestID, approverID string) (*repository.AccessRequest, error)
+	Deny(ctx context.Context, requestID, approverID string, reason *string) (*repository.AccessRequest, error)
+}
+
+type accessRequestService struct {
+	invRepo       repository.InvitationRepository
+	userRepo      repository.UserRepository
+	workspaceRepo repository.WorkspaceRepository
+	spaceRepo     repository.SpaceRepository
+	folderRepo    repository.FolderRepository
+	projectRepo   repository.ProjectRepository
+	memberService MemberService
+	notifSvc      *notification.Service
+}
+
+func NewAccessRequestService(
+	invRepo repository.InvitationRepository,
+	userRepo repository.UserRepository,
+	workspaceRepo repository.WorkspaceRepository,
+	spaceRepo repository.SpaceRepository,
+	folderRepo repository.FolderRepository,
+	projectRepo repository.ProjectRepository,
+	memberService MemberService,
+	notifSvc *notification.Service,
+) AccessRequestService {
+	return &accessRequestService{
+		invRepo:       invRepo,
+		userRepo:      userRepo,
+		workspaceRepo: workspaceRepo,
+		spaceRepo:     spaceRepo,
+		folderRepo:    folderRepo,
+		projectRepo:   projectRepo,
+		memberService: memberService,
+		notifSvc:      notifSvc,
+	}
+}
+
+func (s *accessRequestService) RequestAccess(ctx context.Context, workspaceID, requesterID string, req *models.CreateAccessRequestRequest) (*repository.AccessRequest, error) {
+	targetType := req.Type
+	if targetType == "" {
+		targetType = EntityTypeWorkspace
+	}
+	targetID := req.TargetID
+	if targetType == EntityTypeWorkspace && targetID == "" {
+		targetID = workspaceID
+	}
+	if !isMemberEntityType(targetType) || targetID == "" {
+		return nil, fmt.Errorf("%w: access can be requested to a workspace, space, folder or project", ErrInvalidInput)
+	}
+
+	if _, err := s.targetName(ctx, targetType, targetID); err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.FindByID(ctx, requesterID)
+	if err != nil || user == nil {
+		return nil, ErrUserNotFound
+	}
+
+	hasAccess, _, err := s.memberService.HasEffectiveAccess(ctx, targetType, targetID, requesterID)
+	if err == nil && hasAccess {
+		return nil, fmt.Errorf("%w: you already have access to this %s", ErrConflict, targetType)
+	}
+
+	existing, err := s.invRepo.GetAccessRequestsByRequester(ctx, requesterID)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range existing {
+		if r.Status == AccessRequestPending && string(r.Type) == targetType && r.TargetID == targetID {
+			return nil, fmt.Errorf("%w: an access request is already pending", ErrConflict)
+		}
+	}
+
+	accessReq := &repository.AccessRequest{
+		WorkspaceID: workspaceID,
+		RequesterID: requesterID,
+		Email:       user.Email,
+		Type:        repository.InvitationType(targetType),
+		TargetID:    targetID,
+		Message:     req.Message,
+		Status:      AccessRequestPending,
+	}
+	if err := s.invRepo.CreateAccessRequest(ctx, accessReq); err != nil {
+		return nil, err
+	}
+	return accessReq, nil
+}
+
+func (s *accessRequestService) ListPending(ctx context.Context, targetType, targetID, userID string) ([]*repository.AccessRequest, error) {
+	if !isMemberEntityType(targetType) || targetID == "" {
+		return nil, ErrInvalidInput
+	}
+	if err := s.requireAdmin(ctx, targetType, targetID, userID); err != nil {
+		return nil, err
+	}
+
+	requests, err := s.invRepo.GetAccessRequestsByTarget(ctx, repository.InvitationType(targetType), targetID, AccessRequestPending)
+	if err != nil {
+		return nil, err
+	}
+	if requests == nil {
+		requests = []*repository.AccessRequest{}
+	}
+	return requests, nil
+}
+
+// Approve grants membership and notifies the requester. A request filed
+// through an invitation link consumes one use of that link and gets its
+// default role; any other request joins as a member.
+func (s *accessRequestService) Approve(ctx context.Context, requestID, approverID string) (*repository.AccessRequest, error) {
+	req, err := s.findPending(ctx, requestID, approverID)
+	if err != nil {
+		return nil, err
+	}
+
+	targetType := string(req.Type)
+	role := PermissionMember
+	if req.LinkSettingsID != nil {
+		linkRole, err := s.invRepo.ConsumeLinkUse(ctx, *req.LinkSettingsID)
+		if errors.Is(err, repository.ErrLinkUnavailable) {
+			return nil, fmt.Errorf("%w: the invitation link is inactive, expired or has no uses left", ErrConflict)
+		}
+		if err != nil {
+			return nil, err
+		}
+		role = invitedMemberRole(linkRole)
+	}
+
+	if err := s.memberService.AddMember(ctx, targetType, req.TargetID, req.RequesterID, role, approverID); err != nil {
+		if req.LinkSettingsID != nil {
+			if releaseErr := s.invRepo.ReleaseLinkUse(ctx, *req.LinkSettingsID); releaseErr != nil {
+				slog.WarnContext(ctx, "failed to release invitation link use", "linkID", *req.LinkSettingsID, "error", releaseErr)
+			}
+		}
+		return nil, err
+	}
+
+	if err := s.invRepo.UpdateAccessRequestStatus(ctx, req.ID, AccessRequestApproved, &approverID, nil); err != nil {
+		return nil, err
+	}
+	req.Status = AccessRequestApproved
+	req.ProcessedBy = &approverID
+
+	if s.notifSvc != nil {
+		approverName := "An admin"
+		if approver, _ := s.userRepo.FindByID(ctx, approverID); approver != nil {
+			approverName = approver.Name
+		}
+		name, _ := s.targetName(ctx, targetType, req.TargetID)
+		if err := s.notifSvc.SendAccessRequestApproved(ctx, req.RequesterID, targetType, name, req.TargetID, approverName); err != nil {
+			slog.WarnContext(ctx, "failed to notify access requester", "requesterID", req.RequesterID, "error", err)
+		}
+	}
+
+	return req, nil
+}
+
+// Deny stores the denial reason and notifies the requester
+func (s *accessRequestService) Deny(ctx context.Context, requestID, approverID string, reason *string) (*repository.AccessRequest, error) {
+	req, err := s.findPending(ctx, requestID, approverID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.invRepo.UpdateAccessRequestStatus(ctx, req.ID, AccessRequestDenied, &approverID, reason); err != nil {
+		return nil, err
+	}
+	req.Status = AccessRequestDenied
+	req.ProcessedBy = &approverID
+	req.DenialReason = reason
+
+	if s.notifSvc != nil {
+		targetType := string(req.Type)
+		name, _ := s.targetName(ctx, targetType, req.TargetID)
+		reasonText := ""
+		if reason != nil {
+			reasonText = *reason
+		}
+		if err := s.notifSvc.SendAccessRequestDenied(ctx, req.RequesterID, targetType, name, req.TargetID, reasonText); err != nil {
+			slog.WarnContext(ctx, "failed to notify access requester", "requesterID", req.RequesterID, "error", err)
+		}
+	}
+
+	return req, nil
+}
+
+// findPending loads a request that is still pending, after checking the actor administers its target
+func (s *accessRequestService) findPending(ctx context.Context, requestID, userID string) (*repository.AccessRequest, error) {
+	req, err := s.invRepo.GetAccessRequest(ctx, requestID)
+	if err != nil {
+		return nil, err
+	}
+	if req == nil {
+		return nil, ErrNotFound
+	}
+
+	if err := s.requireAdmin(ctx, string(req.Type), req.TargetID, userID); err != nil {
+		return nil, err
+	}
+
+	if req.Status != AccessRequestPending {
+		return nil, fmt.Errorf("%w: access request is already %s", ErrConflict, req.Status)
+	}
+	return req, nil
+}
+
+// requireAdmin allows only admins and owners of the entity (direct or inherited)
+func (s *accessRequestService) requireAdmin(ctx context.Context, entityType, entityID, userID string) error {
+	role, _, err := s.memberService.GetAccessLevel(ctx, entityType, entityID, userID)
+	if err != nil || getRoleLevel(role) < 4 {
+		return ErrUnauthorized
+	}
+	return nil
+}
+
+func (s *accessRequestService) targetName(ctx context.Context, entityType, entityID string) (string, error) {
+	switch entityType {
+	case EntityTypeWorkspace:
+		if ws, err := s.workspaceRepo.FindByID(ctx, entityID); err == nil && ws != nil {
+			return ws.Name, nil
+		}
+	case EntityTypeSpace:
+		if space, err := s.spaceRepo.FindByID(ctx, entityID); err == nil && space != nil {
+			return space.Name, nil
+		}
+	case EntityTypeFolder:
+		if folder, err := s.folderRepo.FindByID(ctx, entityID); err == nil && folder != nil {
+			return folder.Name, nil
+		}
+	case EntityTypeProject:
+		if project, err := s.projectRepo.FindByID(ctx, entityID); err == nil && project != nil {
+			return project.Name, nil
+		}
+	}
+	return "", ErrNotFound
+}
+
+func isMemberEntityType(entityType string) bool {
+	switch entityType {
+	case EntityTypeWorkspace, EntityTypeSpace, EntityTypeFolder, EntityTypeProject:
+		return true
+	}
+	return false
+}
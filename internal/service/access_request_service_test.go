@@ -0,0 +1,264 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/repository"
+)
+
+// linkInvitationRepo keeps link settings, invitations and access requests in
+// memory. Link uses are consumed under the same rules as the SQL statement.
+type linkInvitationRepo struct {
+	repository.InvitationRepository
+	links       map[string]*repository.InvitationLinkSettings
+	invitations map[string]*repository.Invitation
+	requests    map[string]*repository.AccessRequest
+	released    int
+}
+
+func newLinkInvitationRepo(links ...*repository.InvitationLinkSettings) *linkInvitationRepo {
+	r := &linkInvitationRepo{
+		links:       map[string]*repository.InvitationLinkSettings{},
+		invitations: map[string]*repository.Invitation{},
+		requests:    map[string]*repository.AccessRequest{},
+	}
+	for _, l := range links {
+		r.links[l.ID] = l
+	}
+	return r
+}
+
+func (r *linkInvitationRepo) GetLinkSettingsByToken(_ context.Context, token string) (*repository.InvitationLinkSettings, error) {
+	for _, l := range r.links {
+		if l.LinkToken == token {
+			copied := *l
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *linkInvitationRepo) ConsumeLinkUse(_ context.Context, id string) (repository.WorkspaceRole, error) {
+	l := r.links[id]
+	if l == nil || !l.IsValid() {
+		return "", repository.ErrLinkUnavailable
+	}
+	l.UseCount++
+	return l.DefaultRole, nil
+}
+
+func (r *linkInvitationRepo) ReleaseLinkUse(_ context.Context, id string) error {
+	r.links[id].UseCount--
+	r.released++
+	return nil
+}
+
+func (r *linkInvitationRepo) CreateFromLink(ctx context.Context, linkID string, inv *repository.Invitation) error {
+	if _, err := r.ConsumeLinkUse(ctx, linkID); err != nil {
+		return err
+	}
+	inv.ID = "inv-1"
+	r.invitations[inv.ID] = inv
+	return nil
+}
+
+func (r *linkInvitationRepo) FindByID(_ context.Context, id string) (*repository.Invitation, error) {
+	return r.invitations[id], nil
+}
+
+func (r *linkInvitationRepo) MarkAccepted(_ context.Context, id, _ string) error {
+	r.invitations[id].Status = repository.InvitationStatusAccepted
+	return nil
+}
+
+func (r *linkInvitationRepo) IncrementLinkUseCount(context.Context, string) error { return nil }
+
+func (r *linkInvitationRepo) LogActivity(context.Context, *repository.InvitationActivity) error {
+	return nil
+}
+
+func (r *linkInvitationRepo) GetAccessRequestsByRequester(_ context.Context, requesterID string) ([]*repository.AccessRequest, error) {
+	var result []*repository.AccessRequest
+	for _, req := range r.requests {
+		if req.RequesterID == requesterID {
+			result = append(result, req)
+		}
+	}
+	return result, nil
+}
+
+func (r *linkInvitationRepo) CreateAccessRequest(_ context.Context, req *repository.AccessRequest) error {
+	req.ID = "request-" + req.RequesterID
+	r.requests[req.ID] = req
+	return nil
+}
+
+func (r *linkInvitationRepo) GetAccessRequest(_ context.Context, id string) (*repository.AccessRequest, error) {
+	return r.requests[id], nil
+}
+
+func (r *linkInvitationRepo) UpdateAccessRequestStatus(_ context.Context, id, status string, processedBy, _ *string) error {
+	r.requests[id].Status = status
+	r.requests[id].ProcessedBy = processedBy
+	return nil
+}
+
+type addedMember struct {
+	entityType, entityID, userID, role string
+}
+
+// approverMemberService treats "admin-1" as workspace admin and records adds
+type approverMemberService struct {
+	MemberService
+	added  []addedMember
+	addErr error
+}
+
+func (m *approverMemberService) GetAccessLevel(_ context.Context, _, _, userID string) (string, string, error) {
+	if userID == "admin-1" {
+		return PermissionAdmin, "", nil
+	}
+	return "", "", nil
+}
+
+func (m *approverMemberService) AddMember(_ context.Context, entityType, entityID, userID, role, _ string) error {
+	if m.addErr != nil {
+		return m.addErr
+	}
+	m.added = append(m.added, addedMember{entityType, entityID, userID, role})
+	return nil
+}
+
+type linkWorkspaceRepo struct {
+	repository.WorkspaceRepository
+	added []*repository.WorkspaceMember
+}
+
+func (r *linkWorkspaceRepo) AddMember(_ context.Context, member *repository.WorkspaceMember) error {
+	r.added = append(r.added, member)
+	return nil
+}
+
+func approvalLink(maxUses int) *repository.InvitationLinkSettings {
+	return &repository.InvitationLinkSettings{
+		ID:               "link-1",
+		WorkspaceID:      "ws-1",
+		LinkToken:        "token-1",
+		Type:             repository.InvitationTypeWorkspace,
+		TargetID:         "ws-1",
+		DefaultRole:      repository.WorkspaceRoleGuest,
+		IsActive:         true,
+		RequiresApproval: true,
+		MaxUses:          &maxUses,
+	}
+}
+
+func TestAcceptLinkRequiringApprovalFilesRequest(t *testing.T) {
+	repo := newLinkInvitationRepo(approvalLink(1))
+	workspaces := &linkWorkspaceRepo{}
+	svc := &invitationService{invRepo: repo, workspaceRepo: workspaces}
+
+	result, err := svc.AcceptLink(context.Background(), "token-1", "user-1", "user@example.com")
+	if err != nil {
+		t.Fatalf("AcceptLink: %v", err)
+	}
+	if !result.PendingApproval || result.AccessRequest == nil {
+		t.Fatalf("want a pending access request, got %+v", result)
+	}
+	if id := result.AccessRequest.LinkSettingsID; id == nil || *id != "link-1" {
+		t.Errorf("request should remember link-1, got %v", id)
+	}
+	if repo.links["link-1"].UseCount != 0 {
+		t.Errorf("filing the request consumed a link use")
+	}
+	if len(workspaces.added) != 0 {
+		t.Errorf("user joined before approval")
+	}
+}
+
+func TestAcceptLinkWithoutApprovalJoins(t *testing.T) {
+	link := approvalLink(1)
+	link.RequiresApproval = false
+	repo := newLinkInvitationRepo(link)
+	workspaces := &linkWorkspaceRepo{}
+	svc := &invitationService{invRepo: repo, workspaceRepo: workspaces}
+
+	result, err := svc.AcceptLink(context.Background(), "token-1", "user-1", "user@example.com")
+	if err != nil {
+		t.Fatalf("AcceptLink: %v", err)
+	}
+	if result.PendingApproval {
+		t.Fatal("link without approval should join immediately")
+	}
+	if len(workspaces.added) != 1 || workspaces.added[0].UserID != "user-1" {
+		t.Fatalf("want user-1 added to the workspace, got %+v", workspaces.added)
+	}
+	if repo.links["link-1"].UseCount != 1 {
+		t.Errorf("use count = %d, want 1", repo.links["link-1"].UseCount)
+	}
+}
+
+func TestApproveLinkRequestConsumesUseAndGrantsLinkRole(t *testing.T) {
+	repo := newLinkInvitationRepo(approvalLink(1))
+	// Another usable link on the same target must not decide the role
+	other := approvalLink(10)
+	other.ID, other.LinkToken, other.DefaultRole = "link-2", "token-2", repository.WorkspaceRoleAdmin
+	repo.links[other.ID] = other
+
+	invitations := &invitationService{invRepo: repo, workspaceRepo: &linkWorkspaceRepo{}}
+	ctx := context.Background()
+	first, err := invitations.AcceptLink(ctx, "token-1", "user-1", "one@example.com")
+	if err != nil {
+		t.Fatalf("AcceptLink: %v", err)
+	}
+	second, err := invitations.AcceptLink(ctx, "token-1", "user-2", "two@example.com")
+	if err != nil {
+		t.Fatalf("AcceptLink: %v", err)
+	}
+
+	members := &approverMemberService{}
+	svc := &accessRequestService{invRepo: repo, memberService: members}
+
+	if _, err := svc.Approve(ctx, first.AccessRequest.ID, "admin-1"); err != nil {
+		t.Fatalf("Approve: %v", err)
+	}
+	if len(members.added) != 1 || members.added[0].role != PermissionViewer {
+		t.Fatalf("want the guest link role (viewer), got %+v", members.added)
+	}
+	if repo.links["link-1"].UseCount != 1 {
+		t.Errorf("use count = %d, want 1", repo.links["link-1"].UseCount)
+	}
+
+	// The link had one use; the second approval must not exceed it
+	if _, err := svc.Approve(ctx, second.AccessRequest.ID, "admin-1"); !errors.Is(err, ErrConflict) {
+		t.Fatalf("second approval: got %v, want ErrConflict", err)
+	}
+	if len(members.added) != 1 {
+		t.Errorf("second requester was added past the link's max uses")
+	}
+	if repo.requests[second.AccessRequest.ID].Status != AccessRequestPending {
+		t.Errorf("rejected approval changed the request status")
+	}
+}
+
+func TestApproveReleasesLinkUseWhenJoinFails(t *testing.T) {
+	repo := newLinkInvitationRepo(approvalLink(1))
+	invitations := &invitationService{invRepo: repo, workspaceRepo: &linkWorkspaceRepo{}}
+	ctx := context.Background()
+	result, err := invitations.AcceptLink(ctx, "token-1", "user-1", "one@example.com")
+	if err != nil {
+		t.Fatalf("AcceptLink: %v", err)
+	}
+
+	members := &approverMemberService{addErr: errors.New("insert failed")}
+	svc := &accessRequestService{invRepo: repo, memberService: members}
+
+	if _, err := svc.Approve(ctx, result.AccessRequest.ID, "admin-1"); err == nil {
+		t.Fatal("Approve should fail when the member cannot be added")
+	}
+	if repo.released != 1 || repo.links["link-1"].UseCount != 0 {
+		t.Errorf("link use was not given back: released=%d useCount=%d", repo.released, repo.links["link-1"].UseCount)
+	}
+}
@@ -2,7 +2,9 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
 	"time"
 
 	"github.com/Marga-Ghale/ora-scrum-backend/internal/config"
@@ -23,6 +25,9 @@ type AuthService interface {
 	Logout(ctx context.Context, refreshToken string) error
 	ValidateToken(token string) (*jwt.Token, error)
 	GetUserIDFromToken(token *jwt.Token) (string, error)
+	// CheckActive fails with ErrUserNotFound once the account is deleted, so
+	// access tokens issued before the deletion stop working
+	CheckActive(ctx context.Context, userID string) error
 }
 
 type authService struct {
@@ -86,26 +91,49 @@ func (s *authService) Login(ctx context.Context, email, password string) (*repos
 	return user, accessToken, refreshToken, nil
 }
 
+// RefreshToken rotates the presented token: it is marked used and a new one is
+// issued in the same transaction. Presenting a used token again means it leaked,
+// so every refresh token of the user is revoked.
 func (s *authService) RefreshToken(ctx context.Context, refreshToken string) (string, string, error) {
 	rt, err := s.userRepo.FindRefreshToken(ctx, refreshToken)
 	if err != nil || rt == nil {
 		return "", "", ErrInvalidToken
 	}
 
+	if rt.UsedAt != nil {
+		return "", "", s.revokeOnReuse(ctx, rt.UserID)
+	}
+
 	if time.Now().After(rt.ExpiresAt) {
 		s.userRepo.DeleteRefreshToken(ctx, refreshToken)
-		return "", "", ErrInvalidToken
+		return "", "", ErrRefreshTokenExpired
 	}
 
-	s.userRepo.DeleteRefreshToken(ctx, refreshToken)
-	s.userRepo.UpdateLastActive(ctx, rt.UserID)
-
-	accessToken, newRefreshToken, err := s.generateTokens(ctx, rt.UserID)
+	accessToken, err := s.signAccessToken(rt.UserID)
 	if err != nil {
 		return "", "", fmt.Errorf("failed to generate tokens: %w", err)
 	}
 
-	return accessToken, newRefreshToken, nil
+	next := s.newRefreshToken(rt.UserID)
+	if err := s.userRepo.RotateRefreshToken(ctx, refreshToken, next); err != nil {
+		if errors.Is(err, repository.ErrRefreshTokenUsed) {
+			// Lost a race with another use of the same token
+			return "", "", s.revokeOnReuse(ctx, rt.UserID)
+		}
+		return "", "", fmt.Errorf("failed to rotate refresh token: %w", err)
+	}
+
+	s.userRepo.UpdateLastActive(ctx, rt.UserID)
+
+	return accessToken, next.Token, nil
+}
+
+func (s *authService) revokeOnReuse(ctx context.Context, userID string) error {
+	slog.WarnContext(ctx, "refresh token reuse detected, revoking all sessions", "userID", userID)
+	if err := s.userRepo.DeleteUserRefreshTokens(ctx, userID); err != nil {
+		slog.ErrorContext(ctx, "failed to revoke refresh tokens", "userID", userID, "error", err)
+	}
+	return ErrRefreshTokenReused
 }
 
 func (s *authService) Logout(ctx context.Context, refreshToken string) error {
@@ -137,30 +165,44 @@ func (s *authService) GetUserIDFromToken(token *jwt.Token) (string, error) {
 	return userID, nil
 }
 
-func (s *authService) generateTokens(ctx context.Context, userID string) (string, string, error) {
-	accessToken := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"sub": userID,
-		"exp": time.Now().Add(time.Hour * time.Duration(s.cfg.JWTExpiry)).Unix(),
-		"iat": time.Now().Unix(),
-	})
-
-	accessTokenString, err := accessToken.SignedString([]byte(s.cfg.JWTSecret))
+func (s *authService) CheckActive(ctx context.Context, userID string) error {
+	user, err := s.userRepo.FindByID(ctx, userID)
 	if err != nil {
-		return "", "", err
+		return err
 	}
+	if user == nil || user.DeletedAt != nil {
+		return ErrUserNotFound
+	}
+	return nil
+}
 
-	refreshTokenString := uuid.New().String()
-	refreshTokenExpiry := time.Now().Add(time.Hour * 24 * time.Duration(s.cfg.RefreshExpiry))
-
-	rt := &repository.RefreshToken{
-		Token:     refreshTokenString,
-		UserID:    userID,
-		ExpiresAt: refreshTokenExpiry,
+func (s *authService) generateTokens(ctx context.Context, userID string) (string, string, error) {
+	accessTokenString, err := s.signAccessToken(userID)
+	if err != nil {
+		return "", "", err
 	}
 
+	rt := s.newRefreshToken(userID)
 	if err := s.userRepo.SaveRefreshToken(ctx, rt); err != nil {
 		return "", "", err
 	}
 
-	return accessTokenString, refreshTokenString, nil
+	return accessTokenString, rt.Token, nil
+}
+
+func (s *authService) signAccessToken(userID string) (string, error) {
+	accessToken := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub": userID,
+		"exp": time.Now().Add(time.Hour * time.Duration(s.cfg.JWTExpiry)).Unix(),
+		"iat": time.Now().Unix(),
+	})
+	return accessToken.SignedString([]byte(s.cfg.JWTSecret))
+}
+
+func (s *authService) newRefreshToken(userID string) *repository.RefreshToken {
+	return &repository.RefreshToken{
+		Token:     uuid.New().String(),
+		UserID:    userID,
+		ExpiresAt: time.Now().Add(time.Hour * 24 * time.Duration(s.cfg.RefreshExpiry)),
+	}
 }
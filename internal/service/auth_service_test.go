@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/config"
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/repository"
+)
+
+// tokenUserRepo keeps refresh tokens in memory with the rotation semantics
+// of the SQL repository
+type tokenUserRepo struct {
+	repository.UserRepository
+	tokens map[string]*repository.RefreshToken
+}
+
+func (r *tokenUserRepo) SaveRefreshToken(_ context.Context, rt *repository.RefreshToken) error {
+	copied := *rt
+	r.tokens[rt.Token] = &copied
+	return nil
+}
+
+func (r *tokenUserRepo) FindRefreshToken(_ context.Context, token string) (*repository.RefreshToken, error) {
+	rt, ok := r.tokens[token]
+	if !ok {
+		return nil, nil
+	}
+	copied := *rt
+	return &copied, nil
+}
+
+func (r *tokenUserRepo) RotateRefreshToken(ctx context.Context, oldToken string, next *repository.RefreshToken) error {
+	old, ok := r.tokens[oldToken]
+	if !ok || old.UsedAt != nil {
+		return repository.ErrRefreshTokenUsed
+	}
+	now := time.Now()
+	old.UsedAt = &now
+	return r.SaveRefreshToken(ctx, next)
+}
+
+func (r *tokenUserRepo) DeleteRefreshToken(_ context.Context, token string) error {
+	delete(r.tokens, token)
+	return nil
+}
+
+func (r *tokenUserRepo) DeleteUserRefreshTokens(_ context.Context, userID string) error {
+	for token, rt := range r.tokens {
+		if rt.UserID == userID {
+			delete(r.tokens, token)
+		}
+	}
+	return nil
+}
+
+func (r *tokenUserRepo) UpdateLastActive(context.Context, string) error { return nil }
+
+func newAuthFixture() (*authService, *tokenUserRepo) {
+	repo := &tokenUserRepo{tokens: map[string]*repository.RefreshToken{}}
+	cfg := &config.Config{JWTSecret: "test-secret", JWTExpiry: 1, RefreshExpiry: 7}
+	return &authService{cfg: cfg, userRepo: repo}, repo
+}
+
+func TestRefreshTokenReuseRevokesAllSessions(t *testing.T) {
+	svc, repo := newAuthFixture()
+	ctx := context.Background()
+
+	// Two sessions of the same user, and one of someone else
+	_, stolen, _ := svc.generateTokens(ctx, "user-1")
+	_, otherDevice, _ := svc.generateTokens(ctx, "user-1")
+	_, unrelated, _ := svc.generateTokens(ctx, "user-2")
+
+	_, rotated, err := svc.RefreshToken(ctx, stolen)
+	if err != nil {
+		t.Fatalf("first RefreshToken: %v", err)
+	}
+	if rotated == stolen {
+		t.Fatal("refresh returned the presented token instead of a new one")
+	}
+
+	// The old token comes back: it leaked
+	if _, _, err := svc.RefreshToken(ctx, stolen); !errors.Is(err, ErrRefreshTokenReused) {
+		t.Fatalf("reused RefreshToken error = %v, want ErrRefreshTokenReused", err)
+	}
+	for _, token := range []string{stolen, rotated, otherDevice} {
+		if _, ok := repo.tokens[token]; ok {
+			t.Errorf("token %s of user-1 survived the reuse", token)
+		}
+	}
+	if _, _, err := svc.RefreshToken(ctx, rotated); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("rotated token after revocation error = %v, want ErrInvalidToken", err)
+	}
+	if _, ok := repo.tokens[unrelated]; !ok {
+		t.Error("another user's token was revoked")
+	}
+}
+
+func TestRefreshTokenRejectsExpiredToken(t *testing.T) {
+	svc, repo := newAuthFixture()
+	repo.tokens["old"] = &repository.RefreshToken{Token: "old", UserID: "user-1", ExpiresAt: time.Now().Add(-time.Minute)}
+
+	if _, _, err := svc.RefreshToken(context.Background(), "old"); !errors.Is(err, ErrRefreshTokenExpired) {
+		t.Fatalf("expired RefreshToken error = %v, want ErrRefreshTokenExpired", err)
+	}
+}
@@ -0,0 +1,172 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/repository"
+)
+
+// ============================================
+// Calendar Service
+// ============================================
+
+// CalendarService publishes each user's task due dates as an iCalendar
+// (RFC 5545) feed. Calendar clients cannot authenticate with a JWT, so the
+// feed URL carries a per-user token that the user can regenerate or revoke.
+type CalendarService interface {
+	// GenerateFeedToken returns a new token, invalidating any previous one
+	GenerateFeedToken(ctx context.Context, userID string) (string, error)
+	RevokeFeedToken(ctx context.Context, userID string) error
+	// Feed renders the calendar of the token's owner; ErrInvalidToken if no user has it
+	Feed(ctx context.Context, token string) ([]byte, error)
+}
+
+type calendarService struct {
+	feedRepo      repository.CalendarFeedRepository
+	taskRepo      repository.TaskRepository
+	memberService MemberService
+}
+
+func NewCalendarService(
+	feedRepo repository.CalendarFeedRepository,
+	taskRepo repository.TaskRepository,
+	memberService MemberService,
+) CalendarService {
+	return &calendarService{
+		feedRepo:      feedRepo,
+		taskRepo:      taskRepo,
+		memberService: memberService,
+	}
+}
+
+func (s *calendarService) GenerateFeedToken(ctx context.Context, userID string) (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	token := hex.EncodeToString(buf)
+	if err := s.feedRepo.SaveToken(ctx, userID, hashFeedToken(token)); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+func (s *calendarService) RevokeFeedToken(ctx context.Context, userID string) error {
+	return s.feedRepo.DeleteToken(ctx, userID)
+}
+
+func (s *calendarService) Feed(ctx context.Context, token string) ([]byte, error) {
+	if token == "" {
+		return nil, ErrInvalidToken
+	}
+	userID, err := s.feedRepo.FindUserByToken(ctx, hashFeedToken(token))
+	if err != nil {
+		return nil, err
+	}
+	if userID == "" {
+		return nil, ErrInvalidToken
+	}
+
+	projects, err := s.memberService.GetAccessibleProjects(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	projectIDs := make([]string, 0, len(projects))
+	projectNames := make(map[string]string, len(projects))
+	for _, p := range projects {
+		projectIDs = append(projectIDs, p.ID)
+		projectNames[p.ID] = p.Name
+	}
+
+	tasks, err := s.taskRepo.FindDueForAssignee(ctx, userID, projectIDs)
+	if err != nil {
+		return nil, err
+	}
+	return renderCalendar(tasks, projectNames), nil
+}
+
+func hashFeedToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// renderCalendar writes one all-day VEVENT per task on its due date
+func renderCalendar(tasks []*repository.Task, projectNames map[string]string) []byte {
+	var b bytes.Buffer
+	line := func(content string) {
+		writeICSLine(&b, content)
+	}
+
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//ORA Scrum//Task Due Dates//EN")
+	line("CALSCALE:GREGORIAN")
+	line("METHOD:PUBLISH")
+	line("X-WR-CALNAME:ORA Scrum tasks")
+
+	for _, task := range tasks {
+		if task.DueDate == nil {
+			continue
+		}
+		due := *task.DueDate
+		day := time.Date(due.Year(), due.Month(), due.Day(), 0, 0, 0, 0, time.UTC)
+
+		summary := task.Title
+		if task.Key != "" {
+			summary = task.Key + ": " + summary
+		}
+		if name := projectNames[task.ProjectID]; name != "" {
+			summary += " (" + name + ")"
+		}
+
+		line("BEGIN:VEVENT")
+		line("UID:" + task.ID + "@ora-scrum")
+		line("DTSTAMP:" + task.UpdatedAt.UTC().Format("20060102T150405Z"))
+		line("DTSTART;VALUE=DATE:" + day.Format("20060102"))
+		line("DTEND;VALUE=DATE:" + day.AddDate(0, 0, 1).Format("20060102"))
+		line("SUMMARY:" + escapeICSText(summary))
+		line("DESCRIPTION:" + escapeICSText(fmt.Sprintf("Status: %s\nPriority: %s", task.Status, task.Priority)))
+		line("END:VEVENT")
+	}
+
+	line("END:VCALENDAR")
+	return b.Bytes()
+}
+
+var icsTextEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`)
+
+// escapeICSText escapes a TEXT value (RFC 5545 section 3.3.11)
+func escapeICSText(s string) string {
+	return icsTextEscaper.Replace(s)
+}
+
+// writeICSLine ends a content line with CRLF, folding it so no line exceeds
+// 75 octets (RFC 5545 section 3.1). Folds never split a UTF-8 sequence.
+func writeICSLine(b *bytes.Buffer, content string) {
+	const maxOctets = 75
+	limit := maxOctets
+	for len(content) > limit {
+		cut := limit
+		for cut > 0 && !isUTF8Start(content[cut]) {
+			cut--
+		}
+		b.WriteString(content[:cut])
+		b.WriteString("\r\n ")
+		content = content[cut:]
+		// Continuation lines start with a space, which counts toward the limit
+		limit = maxOctets - 1
+	}
+	b.WriteString(content)
+	b.WriteString("\r\n")
+}
+
+func isUTF8Start(c byte) bool {
+	return c&0xC0 != 0x80
+}
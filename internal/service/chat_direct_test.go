@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/repository"
+)
+
+// directChatRepo enforces the (workspace_id, type, target_id) unique
+// constraint the way the SQL insert's ON CONFLICT does
+type directChatRepo struct {
+	repository.ChatRepository
+	channels map[string]*repository.ChatChannel
+	members  map[string][]string // channel ID -> user IDs
+}
+
+func (r *directChatRepo) CreateDirectChannel(_ context.Context, channel *repository.ChatChannel, user1ID, user2ID string) (*repository.ChatChannel, bool, error) {
+	key := channel.WorkspaceID + "/" + channel.Type + "/" + channel.TargetID
+	if existing, ok := r.channels[key]; ok {
+		copied := *existing
+		return &copied, false, nil
+	}
+	channel.ID = fmt.Sprintf("channel-%d", len(r.channels)+1)
+	stored := *channel
+	r.channels[key] = &stored
+	r.members[channel.ID] = []string{user1ID, user2ID}
+	return channel, true, nil
+}
+
+func (r *directChatRepo) GetMembers(_ context.Context, channelID string) ([]*repository.ChatChannelMember, error) {
+	var members []*repository.ChatChannelMember
+	for _, id := range r.members[channelID] {
+		members = append(members, &repository.ChatChannelMember{ChannelID: channelID, UserID: id, User: &repository.User{ID: id}})
+	}
+	return members, nil
+}
+
+func (r *directChatRepo) GetMemberCount(_ context.Context, channelID string) (int, error) {
+	return len(r.members[channelID]), nil
+}
+
+func TestCreateDirectChannelFromBothSidesReturnsOneChannel(t *testing.T) {
+	chats := &directChatRepo{channels: map[string]*repository.ChatChannel{}, members: map[string][]string{}}
+	svc := &chatService{
+		chatRepo: chats,
+		userRepo: historyUserRepo{users: map[string]string{"user-a": "Ana", "user-b": "Ben"}},
+	}
+	ctx := context.Background()
+
+	ab, err := svc.CreateDirectChannel(ctx, "user-a", "user-b", "ws-1")
+	if err != nil {
+		t.Fatalf("CreateDirectChannel(a, b): %v", err)
+	}
+	ba, err := svc.CreateDirectChannel(ctx, "user-b", "user-a", "ws-1")
+	if err != nil {
+		t.Fatalf("CreateDirectChannel(b, a): %v", err)
+	}
+
+	if ab.ID != ba.ID {
+		t.Fatalf("a->b gave %s, b->a gave %s, want one channel", ab.ID, ba.ID)
+	}
+	if len(chats.channels) != 1 {
+		t.Fatalf("%d channels stored, want 1", len(chats.channels))
+	}
+	if ba.OtherUser == nil || ba.OtherUser.ID != "user-a" {
+		t.Errorf("b sees other user %+v, want user-a", ba.OtherUser)
+	}
+	if ba.MemberCount != 2 {
+		t.Errorf("member count = %d, want 2", ba.MemberCount)
+	}
+}
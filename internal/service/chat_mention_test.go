@@ -0,0 +1,49 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/repository"
+)
+
+func chatMember(userID, name, email string) *repository.ChatChannelMember {
+	return &repository.ChatChannelMember{
+		UserID: userID,
+		User:   &repository.User{ID: userID, Name: name, Email: email},
+	}
+}
+
+func TestMentionedMember(t *testing.T) {
+	members := []*repository.ChatChannelMember{
+		chatMember("u-ann", "Ann", "ann.lee@example.com"),
+		chatMember("u-annie", "Annie", "annie@example.com"),
+		chatMember("u-sam1", "Sam", "sam.one@example.com"),
+		chatMember("u-sam2", "Sam", "sam.two@example.com"),
+	}
+
+	tests := []struct {
+		mention string
+		want    string
+	}{
+		{"ann", "u-ann"},
+		{"ANNIE", "u-annie"},
+		{"ann.lee", "u-ann"},
+		{"annie@example.com", "u-annie"},
+		{"an", ""},              // no partial matches
+		{"sam", ""},             // two members share the display name
+		{"sam.two", "u-sam2"},   // the username still tells them apart
+		{"bob", ""},             // not a channel member
+		{"bob@example.com", ""}, // not a channel member
+		{"ann@example.com", ""}, // emails must match in full
+	}
+	for _, tt := range tests {
+		got := mentionedMember(members, tt.mention)
+		gotID := ""
+		if got != nil {
+			gotID = got.UserID
+		}
+		if gotID != tt.want {
+			t.Errorf("mentionedMember(%q) = %q, want %q", tt.mention, gotID, tt.want)
+		}
+	}
+}
@@ -3,7 +3,10 @@ package service
 import (
 	"context"
 	"fmt"
+	"log/slog"
+	"regexp"
 	"strings"
+	"time"
 
 	"github.com/Marga-Ghale/ora-scrum-backend/internal/notification"
 	"github.com/Marga-Ghale/ora-scrum-backend/internal/repository"
@@ -38,11 +41,21 @@ type ChatService interface {
 	RemoveMemberFromChannel(ctx context.Context, channelID, userID, removedByID string) error
 	GetChannelMembers(ctx context.Context, channelID string) ([]*repository.ChatChannelMember, error)
 	MarkChannelAsRead(ctx context.Context, channelID, userID string) error
+	IsChannelMember(ctx context.Context, channelID, userID string) (bool, error)
+
+	// Presence
+	GetChannelPresence(ctx context.Context, channelID, userID string) ([]string, error)
 
 	// Messages
 	SendMessage(ctx context.Context, channelID, userID, content, messageType string, parentID *string) (*repository.ChatMessage, error)
-	GetMessages(ctx context.Context, channelID string, limit, offset int) ([]*repository.ChatMessage, error)
-	GetThreadMessages(ctx context.Context, parentID string) ([]*repository.ChatMessage, error)
+	// GetMessages and GetThreadMessages return a page of messages and whether
+	// there are more beyond it. before and after are a message ID or an RFC
+	// 3339 timestamp; with neither, the newest messages are returned. offset
+	// is deprecated and only applies without a cursor. A thread read with no
+	// cursor and no limit returns every reply, as before paging existed.
+	GetMessages(ctx context.Context, channelID, before, after string, limit, offset int) ([]*repository.ChatMessage, bool, error)
+	GetThreadMessages(ctx context.Context, parentID, before, after string, limit int) ([]*repository.ChatMessage, bool, error)
+	SearchMessages(ctx context.Context, channelID, userID, query string, limit int) ([]*repository.ChatSearchResult, error)
 	EditMessage(ctx context.Context, messageID, userID, content string) (*repository.ChatMessage, error)
 	DeleteMessage(ctx context.Context, messageID, userID string) error
 
@@ -95,7 +108,7 @@ const (
 
 // populateDirectChannelUser populates the OtherUser field for direct message channels
 func (s *chatService) populateDirectChannelUser(ctx context.Context, channel *repository.ChatChannel, currentUserID string) {
-	if channel.Type != "direct" {
+	if channel.Type != ChannelTypeDM && channel.Type != "direct" {
 		return
 	}
 
@@ -299,9 +312,7 @@ func (s *chatService) ListChannels(ctx context.Context, userID string) ([]*repos
 
 	// Populate OtherUser for direct message channels and member counts
 	for _, channel := range channels {
-		if channel.Type == "direct" {
-			s.populateDirectChannelUser(ctx, channel, userID)
-		}
+		s.populateDirectChannelUser(ctx, channel, userID)
 		s.populateMemberCount(ctx, channel)
 	}
 
@@ -422,13 +433,21 @@ func (s *chatService) ArchiveChannel(ctx context.Context, channelID, userID stri
 // Direct Messages
 // ============================================
 
+// directChannelTargetID is the target ID of the DM between two users. The IDs
+// are ordered so that both users get the same one.
+func directChannelTargetID(user1ID, user2ID string) string {
+	if user1ID > user2ID {
+		user1ID, user2ID = user2ID, user1ID
+	}
+	return user1ID + "_" + user2ID
+}
+
+// CreateDirectChannel returns the workspace's DM between the two users,
+// creating it if there is none. Asking again, from either side, returns the
+// same channel.
 func (s *chatService) CreateDirectChannel(ctx context.Context, user1ID, user2ID, workspaceID string) (*repository.ChatChannel, error) {
-	// Check if direct channel already exists
-	existing, err := s.GetDirectChannel(ctx, user1ID, user2ID)
-	if err == nil && existing != nil {
-		s.populateDirectChannelUser(ctx, existing, user1ID)
-		s.populateMemberCount(ctx, existing)
-		return existing, nil
+	if user1ID == user2ID {
+		return nil, fmt.Errorf("%w: cannot start a direct message with yourself", ErrInvalidInput)
 	}
 
 	// Get user names
@@ -440,27 +459,23 @@ func (s *chatService) CreateDirectChannel(ctx context.Context, user1ID, user2ID,
 		name = fmt.Sprintf("%s & %s", user1.Name, user2.Name)
 	}
 
-	targetID := user1ID + "_" + user2ID
-	if user1ID > user2ID {
-		targetID = user2ID + "_" + user1ID
-	}
-
-	channel := &repository.ChatChannel{
+	channel, created, err := s.chatRepo.CreateDirectChannel(ctx, &repository.ChatChannel{
 		Name:        name,
 		Type:        ChannelTypeDM,
-		TargetID:    targetID,
+		TargetID:    directChannelTargetID(user1ID, user2ID),
 		WorkspaceID: workspaceID,
 		CreatedBy:   user1ID,
 		IsPrivate:   true,
-	}
-
-	if err := s.chatRepo.CreateChannel(ctx, channel); err != nil {
+	}, user1ID, user2ID)
+	if err != nil {
 		return nil, err
 	}
 
-	// Add both users as members
-	s.chatRepo.AddMember(ctx, &repository.ChatChannelMember{ChannelID: channel.ID, UserID: user1ID})
-	s.chatRepo.AddMember(ctx, &repository.ChatChannelMember{ChannelID: channel.ID, UserID: user2ID})
+	if !created {
+		s.populateDirectChannelUser(ctx, channel, user1ID)
+		s.populateMemberCount(ctx, channel)
+		return channel, nil
+	}
 
 	// Populate OtherUser for response
 	channel.OtherUser = user2
@@ -475,16 +490,7 @@ func (s *chatService) CreateDirectChannel(ctx context.Context, user1ID, user2ID,
 }
 
 func (s *chatService) GetDirectChannel(ctx context.Context, user1ID, user2ID string) (*repository.ChatChannel, error) {
-	// Try user1_user2 format
-	targetID := user1ID + "_" + user2ID
-	channel, err := s.chatRepo.GetChannelByTarget(ctx, "direct", targetID)
-	if err == nil && channel != nil {
-		return channel, nil
-	}
-
-	// Try user2_user1 format (reverse order)
-	targetID = user2ID + "_" + user1ID
-	return s.chatRepo.GetChannelByTarget(ctx, "direct", targetID)
+	return s.chatRepo.GetChannelByTarget(ctx, ChannelTypeDM, directChannelTargetID(user1ID, user2ID))
 }
 
 // ============================================
@@ -708,7 +714,13 @@ func (s *chatService) GetChannelMembers(ctx context.Context, channelID string) (
 }
 
 func (s *chatService) MarkChannelAsRead(ctx context.Context, channelID, userID string) error {
-	return s.chatRepo.UpdateLastRead(ctx, channelID, userID)
+	if err := s.chatRepo.UpdateLastRead(ctx, channelID, userID); err != nil {
+		return err
+	}
+	if s.broadcaster != nil {
+		s.sendChatUnread(ctx, channelID, userID)
+	}
+	return nil
 }
 
 // ============================================
@@ -745,22 +757,108 @@ if s.broadcaster != nil && channel != nil {
     }, userID) // ✅ CHANGE: was "" now userID - excludes sender
 }
 
-	// ✅ NEW: Parse and send @mention notifications
-	if s.notifSvc != nil && channel != nil && message.User != nil {
-		s.notifSvc.ParseChatMentions(
-			ctx,
-			content,
-			userID,
-			message.User.Name,
-			channelID,
-			channel.Name,
-			channel.Type == "direct",
-		)
+	// Update the unread badges of the other channel members
+	if s.broadcaster != nil && channel != nil {
+		go s.broadcastChatUnread(channelID, userID)
+	}
+
+	if channel != nil && message.User != nil {
+		s.notifyMentions(ctx, channel, message)
 	}
 
 	return message, nil
 }
 
+// chatMentionRegex matches @name and @email mentions, as in task comments
+var chatMentionRegex = regexp.MustCompile(`@([a-zA-Z0-9._]+(?:@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,})?)`)
+
+// notifyMentions tells the channel members mentioned in the message about
+// it. Mentions that match no member are ignored. Each mentioned member's
+// sockets get a chat_mention event, and members not connected to the
+// channel also get a notification.
+func (s *chatService) notifyMentions(ctx context.Context, channel *repository.ChatChannel, message *repository.ChatMessage) {
+	mentions := chatMentionRegex.FindAllStringSubmatch(message.Content, -1)
+	if len(mentions) == 0 {
+		return
+	}
+
+	members, err := s.chatRepo.GetMembers(ctx, channel.ID)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to load chat members for mentions", "channelID", channel.ID, "error", err)
+		return
+	}
+
+	present := make(map[string]bool)
+	if s.broadcaster != nil {
+		for _, id := range s.broadcaster.GetChannelPresence(channel.ID) {
+			present[id] = true
+		}
+	}
+
+	snippet := message.Content
+	if runes := []rune(snippet); len(runes) > 100 {
+		snippet = string(runes[:97]) + "..."
+	}
+	isDirect := channel.Type == ChannelTypeDM || channel.Type == "direct"
+
+	notified := map[string]bool{message.UserID: true}
+	for _, match := range mentions {
+		member := mentionedMember(members, match[1])
+		if member == nil || notified[member.UserID] {
+			continue
+		}
+		notified[member.UserID] = true
+
+		if s.broadcaster != nil {
+			s.broadcaster.SendToUsers([]string{member.UserID}, socket.MessageChatMention, map[string]interface{}{
+				"channelId":   channel.ID,
+				"channelName": channel.Name,
+				"messageId":   message.ID,
+				"mentionedBy": message.User.Name,
+				"snippet":     snippet,
+			})
+		}
+
+		// Someone with the channel open sees the message already
+		if present[member.UserID] || s.notifSvc == nil {
+			continue
+		}
+		if err := s.notifSvc.SendChatMention(ctx, member.UserID, message.User.Name, channel.ID, channel.Name, message.ID, snippet, isDirect); err != nil {
+			slog.WarnContext(ctx, "failed to send chat mention notification", "channelID", channel.ID, "userID", member.UserID, "error", err)
+		}
+	}
+}
+
+// mentionedMember finds the member a mention refers to: by email when it
+// has an @, otherwise by the username (the email's local part) or the
+// display name. Matches are exact and case-insensitive. A mention that fits
+// more than one member is ambiguous and notifies no one.
+func mentionedMember(members []*repository.ChatChannelMember, mention string) *repository.ChatChannelMember {
+	mention = strings.ToLower(mention)
+	var found *repository.ChatChannelMember
+	for _, m := range members {
+		if m.User == nil {
+			continue
+		}
+		email := strings.ToLower(m.User.Email)
+		var matches bool
+		if strings.Contains(mention, "@") {
+			matches = email == mention
+		} else {
+			username, _, _ := strings.Cut(email, "@")
+			matches = username == mention || strings.ToLower(m.User.Name) == mention
+		}
+		if !matches {
+			continue
+		}
+		if found != nil && found.UserID != m.UserID {
+			return nil
+		}
+		found = m
+	}
+	return found
+}
+
 // sendSystemMessage sends a system message to the channel
 func (s *chatService) sendSystemMessage(ctx context.Context, channelID, content string) {
 	message := &repository.ChatMessage{
@@ -772,12 +870,116 @@ func (s *chatService) sendSystemMessage(ctx context.Context, channelID, content
 	s.chatRepo.CreateMessage(ctx, message)
 }
 
-func (s *chatService) GetMessages(ctx context.Context, channelID string, limit, offset int) ([]*repository.ChatMessage, error) {
-	return s.chatRepo.GetMessages(ctx, channelID, limit, offset)
+const maxChatPageLimit = 100
+
+func (s *chatService) GetMessages(ctx context.Context, channelID, before, after string, limit, offset int) ([]*repository.ChatMessage, bool, error) {
+	inChannel := func(m *repository.ChatMessage) bool {
+		return m.ChannelID == channelID && m.ParentID == nil
+	}
+	page, err := s.messagePage(ctx, before, after, limit, inChannel)
+	if err != nil {
+		return nil, false, err
+	}
+	if offset > 0 && page.Before == nil && page.After == nil {
+		page.Offset = offset
+	}
+	return s.chatRepo.GetMessages(ctx, channelID, page)
+}
+
+func (s *chatService) GetThreadMessages(ctx context.Context, parentID, before, after string, limit int) ([]*repository.ChatMessage, bool, error) {
+	inThread := func(m *repository.ChatMessage) bool {
+		return m.ParentID != nil && *m.ParentID == parentID
+	}
+	if before == "" && after == "" && limit <= 0 {
+		return s.chatRepo.GetThreadMessages(ctx, parentID, repository.ChatMessagePage{})
+	}
+	page, err := s.messagePage(ctx, before, after, limit, inThread)
+	if err != nil {
+		return nil, false, err
+	}
+	return s.chatRepo.GetThreadMessages(ctx, parentID, page)
+}
+
+// messagePage turns the before and after query values into a page. A cursor
+// message must belong to the list being paged, which belongs reports.
+func (s *chatService) messagePage(ctx context.Context, before, after string, limit int, belongs func(*repository.ChatMessage) bool) (repository.ChatMessagePage, error) {
+	page := repository.ChatMessagePage{Limit: limit}
+	if page.Limit <= 0 {
+		page.Limit = repository.DefaultChatPageLimit
+	}
+	if page.Limit > maxChatPageLimit {
+		page.Limit = maxChatPageLimit
+	}
+	if before != "" && after != "" {
+		return page, fmt.Errorf("%w: before and after cannot be combined", ErrInvalidInput)
+	}
+
+	cursor := func(value string) (*repository.ChatCursor, error) {
+		if value == "" {
+			return nil, nil
+		}
+		if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+			return &repository.ChatCursor{CreatedAt: t}, nil
+		}
+		message, err := s.chatRepo.GetMessageByID(ctx, value)
+		if err != nil || !belongs(message) {
+			return nil, fmt.Errorf("%w: cursor must be a message of this list or an RFC 3339 timestamp", ErrInvalidInput)
+		}
+		return &repository.ChatCursor{CreatedAt: message.CreatedAt, ID: message.ID}, nil
+	}
+
+	var err error
+	if page.Before, err = cursor(before); err != nil {
+		return page, err
+	}
+	if page.After, err = cursor(after); err != nil {
+		return page, err
+	}
+	return page, nil
 }
 
-func (s *chatService) GetThreadMessages(ctx context.Context, parentID string) ([]*repository.ChatMessage, error) {
-	return s.chatRepo.GetThreadMessages(ctx, parentID)
+// Chat search page size, and how many messages around each match are returned
+const (
+	defaultChatSearchLimit = 20
+	maxChatSearchLimit     = 50
+	chatSearchContext      = 2
+)
+
+// SearchMessages full-text searches a channel the user is a member of. Each
+// match comes with the messages around it. Deleted messages are removed from
+// the table, so they never match or show up as context.
+func (s *chatService) SearchMessages(ctx context.Context, channelID, userID, query string, limit int) ([]*repository.ChatSearchResult, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, fmt.Errorf("%w: search query is required", ErrInvalidInput)
+	}
+
+	isMember, err := s.chatRepo.IsMember(ctx, channelID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !isMember {
+		return nil, ErrUnauthorized
+	}
+
+	if limit <= 0 {
+		limit = defaultChatSearchLimit
+	}
+	if limit > maxChatSearchLimit {
+		limit = maxChatSearchLimit
+	}
+
+	results, err := s.chatRepo.SearchMessages(ctx, channelID, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	for _, res := range results {
+		res.Before, res.After, err = s.chatRepo.GetMessagesAround(ctx, res.Message, chatSearchContext)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return results, nil
 }
 
 func (s *chatService) EditMessage(ctx context.Context, messageID, userID, content string) (*repository.ChatMessage, error) {
@@ -928,6 +1130,34 @@ func (s *chatService) GetAllUnreadCounts(ctx context.Context, userID string) (ma
 	return counts, nil
 }
 
+// broadcastChatUnread pushes fresh unread counts to every channel member except the sender
+func (s *chatService) broadcastChatUnread(channelID, senderID string) {
+	ctx := context.Background()
+	members, err := s.chatRepo.GetMembers(ctx, channelID)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to load chat members for unread update", "channelID", channelID, "error", err)
+		return
+	}
+	for _, member := range members {
+		if member.UserID != senderID {
+			s.sendChatUnread(ctx, channelID, member.UserID)
+		}
+	}
+}
+
+func (s *chatService) sendChatUnread(ctx context.Context, channelID, userID string) {
+	counts, err := s.GetAllUnreadCounts(ctx, userID)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to count unread chat messages", "userID", userID, "error", err)
+		return
+	}
+	total := 0
+	for _, count := range counts {
+		total += count
+	}
+	s.broadcaster.SendChatUnread(userID, channelID, counts[channelID], total)
+}
+
 // Helper function
 func getNameOrUnknown(user *repository.User) string {
 	if user != nil && user.Name != "" {
@@ -935,3 +1165,27 @@ func getNameOrUnknown(user *repository.User) string {
 	}
 	return "Unknown User"
 }
+
+// ============================================
+// Presence
+// ============================================
+
+func (s *chatService) IsChannelMember(ctx context.Context, channelID, userID string) (bool, error) {
+	return s.chatRepo.IsMember(ctx, channelID, userID)
+}
+
+// GetChannelPresence lists the members currently connected to the channel's socket room
+func (s *chatService) GetChannelPresence(ctx context.Context, channelID, userID string) ([]string, error) {
+	isMember, err := s.chatRepo.IsMember(ctx, channelID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !isMember {
+		return nil, ErrUnauthorized
+	}
+
+	if s.broadcaster == nil {
+		return []string{}, nil
+	}
+	return s.broadcaster.GetChannelPresence(channelID), nil
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/repository"
+)
+
+type listedCommentRepo struct {
+	repository.TaskCommentRepository
+	comments []*repository.TaskComment
+}
+
+func (r *listedCommentRepo) FindByTaskID(context.Context, string) ([]*repository.TaskComment, error) {
+	return r.comments, nil
+}
+
+func (r *listedCommentRepo) SummarizeReactions(context.Context, []string, string) (map[string][]*repository.CommentReactionSummary, error) {
+	return nil, nil
+}
+
+type authorUserRepo struct {
+	repository.UserRepository
+	users []*repository.User
+	calls int
+}
+
+func (r *authorUserRepo) FindByIDs(context.Context, []string) ([]*repository.User, error) {
+	r.calls++
+	return r.users, nil
+}
+
+type readOnlyPermissions struct {
+	PermissionService
+}
+
+func (readOnlyPermissions) CanAccessTask(context.Context, string, string) bool { return true }
+
+func TestListCommentsKeepsDeletedAuthor(t *testing.T) {
+	deletedAt := time.Now().Add(-time.Hour)
+	reply := "c1"
+	users := &authorUserRepo{users: []*repository.User{
+		{ID: "gone", Name: repository.DeletedUserName, DeletedAt: &deletedAt},
+		{ID: "ana", Name: "Ana"},
+	}}
+	svc := &taskService{
+		commentRepo: &listedCommentRepo{comments: []*repository.TaskComment{
+			{ID: "c1", TaskID: "t1", UserID: "gone", Content: "Written before I left"},
+			{ID: "c2", TaskID: "t1", UserID: "ana", Content: "Thanks!", ParentCommentID: &reply},
+		}},
+		userRepo:    users,
+		permService: readOnlyPermissions{},
+	}
+
+	comments, err := svc.ListComments(context.Background(), "t1", "ana")
+	if err != nil {
+		t.Fatalf("ListComments: %v", err)
+	}
+	if len(comments) != 1 || len(comments[0].Replies) != 1 {
+		t.Fatalf("got %d threads, want the deleted user's comment with its reply", len(comments))
+	}
+	author := comments[0].User
+	if author == nil || author.ID != "gone" || author.DeletedAt == nil {
+		t.Fatalf("author = %+v, want the deleted user", author)
+	}
+	if got := comments[0].Replies[0].User; got == nil || got.Name != "Ana" {
+		t.Errorf("reply author = %+v, want Ana", got)
+	}
+	if users.calls != 1 {
+		t.Errorf("loaded authors with %d queries, want 1", users.calls)
+	}
+}
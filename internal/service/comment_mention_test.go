@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/notification"
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/repository"
+)
+
+type mentionCommentRepo struct {
+	repository.TaskCommentRepository
+	comments []*repository.TaskComment
+}
+
+func (r *mentionCommentRepo) Create(_ context.Context, c *repository.TaskComment) error {
+	c.ID = "comment-1"
+	r.comments = append(r.comments, c)
+	return nil
+}
+
+type mentionUserRepo struct {
+	repository.UserRepository
+	users map[string]*repository.User // by ID
+}
+
+func (r mentionUserRepo) FindByID(_ context.Context, id string) (*repository.User, error) {
+	return r.users[id], nil
+}
+
+func (r mentionUserRepo) FindByName(_ context.Context, name string) (*repository.User, error) {
+	for _, u := range r.users {
+		if u.Name == name {
+			return u, nil
+		}
+	}
+	return nil, nil
+}
+
+// mentionMembers gives project access to the listed users only
+type mentionMembers struct {
+	MemberService
+	members map[string]bool
+}
+
+func (m mentionMembers) HasEffectiveAccess(_ context.Context, _, _, userID string) (bool, string, error) {
+	return m.members[userID], PermissionMember, nil
+}
+
+type mentionPermissions struct {
+	PermissionService
+}
+
+func (mentionPermissions) CanCommentOnTask(context.Context, string, string) bool { return true }
+
+type storedNotificationRepo struct {
+	repository.NotificationRepository
+	rows []*repository.Notification
+}
+
+func (r *storedNotificationRepo) Create(_ context.Context, n *repository.Notification) error {
+	r.rows = append(r.rows, n)
+	return nil
+}
+
+func TestAddCommentNotifiesValidMentionsOnly(t *testing.T) {
+	notifications := &storedNotificationRepo{}
+	comments := &mentionCommentRepo{}
+	svc := &taskService{
+		taskRepo: &depTaskRepo{tasks: map[string]*repository.Task{
+			"t1": {ID: "t1", ProjectID: "p1", Key: "ORA-1", Title: "Fix login", Status: "todo"},
+		}},
+		commentRepo: comments,
+		userRepo: mentionUserRepo{users: map[string]*repository.User{
+			"alice":   {ID: "alice", Name: "alice"},
+			"bob":     {ID: "bob", Name: "bob"},
+			"carol":   {ID: "carol", Name: "carol"},
+			"mallory": {ID: "mallory", Name: "mallory"},
+		}},
+		memberService:   mentionMembers{members: map[string]bool{"alice": true, "bob": true, "carol": true}},
+		permService:     mentionPermissions{},
+		activityRepo:    &depActivityRepo{},
+		notificationSvc: notification.NewService(notifications),
+	}
+
+	// bob by ID, carol by handle, mallory has no access, alice is the author
+	_, err := svc.AddComment(context.Background(), "t1", "alice",
+		"@carol @mallory @alice can you look at this?", []string{"bob", "mallory"}, nil)
+	if err != nil {
+		t.Fatalf("AddComment: %v", err)
+	}
+
+	got := map[string]int{}
+	for _, n := range notifications.rows {
+		if n.Type != notification.TypeMention {
+			t.Fatalf("unexpected %s notification for %s", n.Type, n.UserID)
+		}
+		if n.Data["taskKey"] != "ORA-1" {
+			t.Errorf("mention for %s has task key %v, want ORA-1", n.UserID, n.Data["taskKey"])
+		}
+		got[n.UserID]++
+	}
+	if len(got) != 2 || got["bob"] != 1 || got["carol"] != 1 {
+		t.Fatalf("mention notifications per user = %v, want one each for bob and carol", got)
+	}
+
+	stored := comments.comments[0].MentionedUsers
+	if len(stored) != 2 || contains(stored, "mallory") {
+		t.Errorf("comment stored mentions %v, want only bob and carol", stored)
+	}
+}
@@ -51,7 +51,7 @@ func (s *folderService) Create(ctx context.Context, spaceID, creatorID, name str
 	// ✅ Verify space exists
 	space, err := s.spaceRepo.FindByID(ctx, spaceID)
 	if err != nil || space == nil {
-		return nil, ErrNotFound
+		return nil, ErrSpaceNotFound
 	}
 
 	// ✅ Verify creator has access to space
@@ -109,7 +109,7 @@ func (s *folderService) GetByID(ctx context.Context, id string) (*repository.Fol
 		return nil, err
 	}
 	if folder == nil {
-		return nil, ErrNotFound
+		return nil, ErrFolderNotFound
 	}
 	return folder, nil
 }
@@ -125,7 +125,7 @@ func (s *folderService) ListByUser(ctx context.Context, userID string) ([]*repos
 func (s *folderService) Update(ctx context.Context, id string, name, description, icon, color, visibility *string, allowedUsers, allowedTeams *[]string) (*repository.Folder, error) {
 	folder, err := s.folderRepo.FindByID(ctx, id)
 	if err != nil || folder == nil {
-		return nil, ErrNotFound
+		return nil, ErrFolderNotFound
 	}
 
 	// Update name if provided
@@ -176,7 +176,7 @@ func (s *folderService) Delete(ctx context.Context, id string) error {
 	// ✅ Get folder first to know space ID for broadcasting
 	folder, err := s.folderRepo.FindByID(ctx, id)
 	if err != nil || folder == nil {
-		return ErrNotFound
+		return ErrFolderNotFound
 	}
 
 	spaceID := folder.SpaceID
@@ -199,7 +199,7 @@ func (s *folderService) Delete(ctx context.Context, id string) error {
 func (s *folderService) UpdateVisibility(ctx context.Context, folderID, visibility string, allowedUsers, allowedTeams []string) error {
 	folder, err := s.folderRepo.FindByID(ctx, folderID)
 	if err != nil || folder == nil {
-		return ErrNotFound
+		return ErrFolderNotFound
 	}
 
 	folder.Visibility = &visibility
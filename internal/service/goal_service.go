@@ -184,7 +184,7 @@ func (s *goalService) GetByID(ctx context.Context, goalID, userID string) (*repo
 		return nil, err
 	}
 	if goal == nil {
-		return nil, ErrNotFound
+		return nil, ErrGoalNotFound
 	}
 
 	// Verify access
@@ -217,7 +217,7 @@ func (s *goalService) ListByProject(ctx context.Context, projectID, userID strin
 func (s *goalService) ListBySprint(ctx context.Context, sprintID, userID string) ([]*repository.Goal, error) {
 	sprint, err := s.sprintRepo.FindByID(ctx, sprintID)
 	if err != nil || sprint == nil {
-		return nil, ErrNotFound
+		return nil, ErrSprintNotFound
 	}
 
 	hasAccess, _, err := s.memberService.HasEffectiveAccess(ctx, EntityTypeProject, sprint.ProjectID, userID)
@@ -231,7 +231,7 @@ func (s *goalService) ListBySprint(ctx context.Context, sprintID, userID string)
 func (s *goalService) Update(ctx context.Context, goalID, userID string, req *UpdateGoalRequest) (*repository.Goal, error) {
 	goal, err := s.goalRepo.FindByID(ctx, goalID)
 	if err != nil || goal == nil {
-		return nil, ErrNotFound
+		return nil, ErrGoalNotFound
 	}
 
 	hasAccess, _, err := s.memberService.HasEffectiveAccess(ctx, EntityTypeWorkspace, goal.WorkspaceID, userID)
@@ -289,7 +289,7 @@ func (s *goalService) Update(ctx context.Context, goalID, userID string, req *Up
 func (s *goalService) UpdateProgress(ctx context.Context, goalID, userID string, currentValue float64) error {
 	goal, err := s.goalRepo.FindByID(ctx, goalID)
 	if err != nil || goal == nil {
-		return ErrNotFound
+		return ErrGoalNotFound
 	}
 
 	hasAccess, _, err := s.memberService.HasEffectiveAccess(ctx, EntityTypeWorkspace, goal.WorkspaceID, userID)
@@ -312,7 +312,7 @@ func (s *goalService) UpdateProgress(ctx context.Context, goalID, userID string,
 func (s *goalService) UpdateStatus(ctx context.Context, goalID, userID, status string) error {
 	goal, err := s.goalRepo.FindByID(ctx, goalID)
 	if err != nil || goal == nil {
-		return ErrNotFound
+		return ErrGoalNotFound
 	}
 
 	hasAccess, _, err := s.memberService.HasEffectiveAccess(ctx, EntityTypeWorkspace, goal.WorkspaceID, userID)
@@ -326,7 +326,7 @@ func (s *goalService) UpdateStatus(ctx context.Context, goalID, userID, status s
 func (s *goalService) Delete(ctx context.Context, goalID, userID string) error {
 	goal, err := s.goalRepo.FindByID(ctx, goalID)
 	if err != nil || goal == nil {
-		return ErrNotFound
+		return ErrGoalNotFound
 	}
 
 	hasAccess, _, err := s.memberService.HasEffectiveAccess(ctx, EntityTypeWorkspace, goal.WorkspaceID, userID)
@@ -344,7 +344,7 @@ func (s *goalService) Delete(ctx context.Context, goalID, userID string) error {
 func (s *goalService) AddKeyResult(ctx context.Context, goalID, userID string, req *CreateKeyResultRequest) (*repository.KeyResult, error) {
 	goal, err := s.goalRepo.FindByID(ctx, goalID)
 	if err != nil || goal == nil {
-		return nil, ErrNotFound
+		return nil, ErrGoalNotFound
 	}
 
 	hasAccess, _, err := s.memberService.HasEffectiveAccess(ctx, EntityTypeWorkspace, goal.WorkspaceID, userID)
@@ -432,12 +432,12 @@ func (s *goalService) DeleteKeyResult(ctx context.Context, keyResultID, userID s
 func (s *goalService) LinkTask(ctx context.Context, goalID, taskID, userID string) error {
 	goal, err := s.goalRepo.FindByID(ctx, goalID)
 	if err != nil || goal == nil {
-		return ErrNotFound
+		return ErrGoalNotFound
 	}
 
 	task, err := s.taskRepo.FindByID(ctx, taskID)
 	if err != nil || task == nil {
-		return ErrNotFound
+		return ErrTaskNotFound
 	}
 
 	hasAccess, _, err := s.memberService.HasEffectiveAccess(ctx, EntityTypeWorkspace, goal.WorkspaceID, userID)
@@ -451,7 +451,7 @@ func (s *goalService) LinkTask(ctx context.Context, goalID, taskID, userID strin
 func (s *goalService) UnlinkTask(ctx context.Context, goalID, taskID, userID string) error {
 	goal, err := s.goalRepo.FindByID(ctx, goalID)
 	if err != nil || goal == nil {
-		return ErrNotFound
+		return ErrGoalNotFound
 	}
 
 	hasAccess, _, err := s.memberService.HasEffectiveAccess(ctx, EntityTypeWorkspace, goal.WorkspaceID, userID)
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/repository"
+)
+
+// bulkInvitationRepo counts created invitations as pending and fails Create
+// for the addresses in failCreate
+type bulkInvitationRepo struct {
+	repository.InvitationRepository
+	pending    int
+	failCreate map[string]error
+	result     *repository.BulkInvitationResult
+}
+
+func (r *bulkInvitationRepo) ExistsPendingForEmail(context.Context, string, repository.InvitationType, string) (bool, error) {
+	return false, nil
+}
+
+func (r *bulkInvitationRepo) CountPendingByTarget(context.Context, repository.InvitationType, string) (int, error) {
+	return r.pending, nil
+}
+
+func (r *bulkInvitationRepo) Create(_ context.Context, inv *repository.Invitation) error {
+	if err := r.failCreate[inv.Email]; err != nil {
+		return err
+	}
+	r.pending++
+	return nil
+}
+
+func (r *bulkInvitationRepo) LogActivity(context.Context, *repository.InvitationActivity) error {
+	return nil
+}
+
+func (r *bulkInvitationRepo) UpdateBulkResult(_ context.Context, result *repository.BulkInvitationResult) error {
+	r.result = result
+	return nil
+}
+
+func TestBulkInviteFailuresHideInternalErrors(t *testing.T) {
+	repo := &bulkInvitationRepo{
+		pending: 1,
+		failCreate: map[string]error{
+			"broken@example.com": errors.New(`pq: relation "invitations" does not exist`),
+		},
+	}
+	svc := &invitationService{
+		invRepo:       repo,
+		pendingLimits: map[string]int{string(repository.InvitationTypeProject): 2},
+	}
+
+	svc.processBulkInvite(
+		repository.BulkInvitationResult{ID: "bulk-1", TotalCount: 4},
+		repository.Invitation{WorkspaceID: "w1", Type: repository.InvitationTypeProject, TargetID: "p1", Role: repository.WorkspaceRoleMember},
+		[]string{"not-an-address", "broken@example.com", "ok@example.com", "over@example.com"},
+	)
+
+	if repo.result == nil || repo.result.FailedEmails == nil {
+		t.Fatalf("bulk result not saved with failures: %+v", repo.result)
+	}
+	var failures []bulkInviteFailure
+	if err := json.Unmarshal([]byte(*repo.result.FailedEmails), &failures); err != nil {
+		t.Fatalf("failed_emails is not JSON: %v", err)
+	}
+	want := []bulkInviteFailure{
+		{Email: "not-an-address", Error: "invalid email address"},
+		{Email: "broken@example.com", Error: "internal error"},
+		{Email: "over@example.com", Error: "pending invitation limit reached"},
+	}
+	if !reflect.DeepEqual(failures, want) {
+		t.Fatalf("failed_emails = %+v, want %+v", failures, want)
+	}
+	if repo.result.SuccessCount != 1 || repo.result.FailedCount != 3 {
+		t.Errorf("success/failed = %d/%d, want 1/3", repo.result.SuccessCount, repo.result.FailedCount)
+	}
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/repository"
+)
+
+func openLink(maxUses int) *repository.InvitationLinkSettings {
+	link := approvalLink(maxUses)
+	link.RequiresApproval = false
+	return link
+}
+
+func newLinkService(repo repository.InvitationRepository) (*invitationService, *linkWorkspaceRepo) {
+	workspaces := &linkWorkspaceRepo{}
+	return &invitationService{invRepo: repo, workspaceRepo: workspaces}, workspaces
+}
+
+func TestAcceptLinkRejectsDomainOutsideAllowlist(t *testing.T) {
+	link := openLink(10)
+	link.AllowedDomains = strPtr(`["acme.com"]`)
+	repo := newLinkInvitationRepo(link)
+	svc, workspaces := newLinkService(repo)
+
+	_, err := svc.AcceptLink(context.Background(), "token-1", "user-1", "someone@other.com")
+	if !errors.Is(err, ErrEmailDomainNotAllowed) {
+		t.Fatalf("AcceptLink error = %v, want ErrEmailDomainNotAllowed", err)
+	}
+	if len(workspaces.added) != 0 || repo.links["link-1"].UseCount != 0 {
+		t.Errorf("rejected accept joined or consumed a use: added=%d useCount=%d", len(workspaces.added), repo.links["link-1"].UseCount)
+	}
+
+	if _, err := svc.AcceptLink(context.Background(), "token-1", "user-1", "someone@ACME.com"); err != nil {
+		t.Fatalf("allowlisted domain rejected: %v", err)
+	}
+}
+
+func TestAcceptLinkRejectsBlockedDomain(t *testing.T) {
+	link := openLink(10)
+	link.BlockedDomains = strPtr(`["spam.example"]`)
+	repo := newLinkInvitationRepo(link)
+	svc, workspaces := newLinkService(repo)
+
+	_, err := svc.AcceptLink(context.Background(), "token-1", "user-1", "bot@spam.example")
+	if !errors.Is(err, ErrEmailDomainNotAllowed) {
+		t.Fatalf("AcceptLink error = %v, want ErrEmailDomainNotAllowed", err)
+	}
+	if len(workspaces.added) != 0 {
+		t.Errorf("user with a blocked domain joined the workspace")
+	}
+}
+
+func TestAcceptLinkRejectsExhaustedLink(t *testing.T) {
+	repo := newLinkInvitationRepo(openLink(1))
+	svc, workspaces := newLinkService(repo)
+	ctx := context.Background()
+
+	if _, err := svc.AcceptLink(ctx, "token-1", "user-1", "one@example.com"); err != nil {
+		t.Fatalf("first AcceptLink: %v", err)
+	}
+	_, err := svc.AcceptLink(ctx, "token-1", "user-2", "two@example.com")
+	if !errors.Is(err, repository.ErrLinkUnavailable) {
+		t.Fatalf("second AcceptLink error = %v, want ErrLinkUnavailable", err)
+	}
+	if len(workspaces.added) != 1 {
+		t.Errorf("%d users joined a single-use link", len(workspaces.added))
+	}
+}
+
+// staleLinkRepo serves the link as it was before any use, like a concurrent
+// accept that read the settings before the other one committed
+type staleLinkRepo struct {
+	*linkInvitationRepo
+	snapshot repository.InvitationLinkSettings
+}
+
+func (r *staleLinkRepo) GetLinkSettingsByToken(context.Context, string) (*repository.InvitationLinkSettings, error) {
+	copied := r.snapshot
+	return &copied, nil
+}
+
+func TestAcceptLinkEnforcesMaxUsesPastStaleRead(t *testing.T) {
+	link := openLink(1)
+	inner := newLinkInvitationRepo(link)
+	repo := &staleLinkRepo{linkInvitationRepo: inner, snapshot: *link}
+	svc, workspaces := newLinkService(repo)
+	ctx := context.Background()
+
+	if _, err := svc.AcceptLink(ctx, "token-1", "user-1", "one@example.com"); err != nil {
+		t.Fatalf("first AcceptLink: %v", err)
+	}
+	// The stale read still shows a free use; consuming it must fail
+	_, err := svc.AcceptLink(ctx, "token-1", "user-2", "two@example.com")
+	if !errors.Is(err, repository.ErrLinkUnavailable) {
+		t.Fatalf("second AcceptLink error = %v, want ErrLinkUnavailable", err)
+	}
+	if inner.links["link-1"].UseCount != 1 || len(workspaces.added) != 1 {
+		t.Errorf("link went past max uses: useCount=%d added=%d", inner.links["link-1"].UseCount, len(workspaces.added))
+	}
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/email"
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/repository"
+)
+
+// resendInvitationRepo holds one pending invitation and claims sends under
+// the same cooldown rule as the SQL statement
+type resendInvitationRepo struct {
+	repository.InvitationRepository
+	inv      *repository.Invitation
+	lastSent time.Time
+	actions  []string
+}
+
+func (r *resendInvitationRepo) FindByID(_ context.Context, id string) (*repository.Invitation, error) {
+	if id != r.inv.ID {
+		return nil, nil
+	}
+	copied := *r.inv
+	return &copied, nil
+}
+
+func (r *resendInvitationRepo) ClaimResend(_ context.Context, _ string, cooldown time.Duration) (bool, error) {
+	if time.Since(r.lastSent) < cooldown {
+		return false, nil
+	}
+	r.lastSent = time.Now()
+	r.inv.ReminderCount++
+	return true, nil
+}
+
+func (r *resendInvitationRepo) FindPendingForReminder(context.Context, time.Duration, int) ([]*repository.Invitation, error) {
+	copied := *r.inv
+	return []*repository.Invitation{&copied}, nil
+}
+
+func (r *resendInvitationRepo) LogActivity(_ context.Context, a *repository.InvitationActivity) error {
+	r.actions = append(r.actions, a.Action)
+	return nil
+}
+
+type resendWorkspaceRepo struct {
+	repository.WorkspaceRepository
+}
+
+func (resendWorkspaceRepo) FindByID(context.Context, string) (*repository.Workspace, error) {
+	return nil, nil
+}
+
+// newResendFixture returns an invitation created long enough ago that the
+// first send is allowed
+func newResendFixture() (*invitationService, *resendInvitationRepo) {
+	repo := &resendInvitationRepo{inv: &repository.Invitation{
+		ID:          "inv-1",
+		WorkspaceID: "ws-1",
+		Email:       "invitee@example.com",
+		Method:      repository.InvitationMethodEmail,
+		Status:      repository.InvitationStatusPending,
+	}}
+	svc := &invitationService{
+		invRepo:          repo,
+		workspaceRepo:    resendWorkspaceRepo{},
+		emailSvc:         email.NewService(&email.Config{}),
+		resendCooldown:   5 * time.Minute,
+		reminderInterval: time.Hour,
+		maxReminders:     3,
+	}
+	return svc, repo
+}
+
+func countActions(actions []string, action string) int {
+	n := 0
+	for _, a := range actions {
+		if a == action {
+			n++
+		}
+	}
+	return n
+}
+
+func TestQuickSecondResendIsThrottled(t *testing.T) {
+	svc, repo := newResendFixture()
+	ctx := context.Background()
+
+	if _, err := svc.ResendInvitation(ctx, "inv-1", nil); err != nil {
+		t.Fatalf("first resend: %v", err)
+	}
+	_, err := svc.ResendInvitation(ctx, "inv-1", nil)
+	if !errors.Is(err, ErrTooManyRequests) {
+		t.Fatalf("second resend error = %v, want ErrTooManyRequests", err)
+	}
+	if sent := countActions(repo.actions, "resent"); sent != 1 {
+		t.Fatalf("%d resends went out, want 1", sent)
+	}
+}
+
+func TestReminderSharesResendCooldown(t *testing.T) {
+	svc, repo := newResendFixture()
+	ctx := context.Background()
+
+	if _, err := svc.ResendInvitation(ctx, "inv-1", nil); err != nil {
+		t.Fatalf("resend: %v", err)
+	}
+	sent, err := svc.SendPendingReminders(ctx)
+	if err != nil {
+		t.Fatalf("SendPendingReminders: %v", err)
+	}
+	if sent != 0 || countActions(repo.actions, "reminder_sent") != 0 {
+		t.Fatalf("reminder sent %d emails right after a manual resend", sent)
+	}
+
+	// Once the cooldown has passed the reminder goes out
+	repo.lastSent = time.Now().Add(-svc.resendCooldown)
+	if sent, err = svc.SendPendingReminders(ctx); err != nil || sent != 1 {
+		t.Fatalf("SendPendingReminders after cooldown = %d, %v; want 1 send", sent, err)
+	}
+}
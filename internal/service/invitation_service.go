@@ -4,11 +4,14 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
-	"log"
+	"fmt"
+	"log/slog"
+	"net/mail"
 	"strings"
 	"time"
 
 	"github.com/Marga-Ghale/ora-scrum-backend/internal/email"
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/notification"
 	"github.com/Marga-Ghale/ora-scrum-backend/internal/repository"
 )
 
@@ -35,6 +38,12 @@ type InvitationService interface {
 	CreateWorkspaceInvitation(ctx context.Context, workspaceID, email, role, inviterID string) (*repository.Invitation, error)
 	CreateProjectInvitation(ctx context.Context, workspaceID, projectID, email, role, inviterID string) (*repository.Invitation, error)
 
+	// Bulk invitations
+	BulkInvite(ctx context.Context, workspaceID, inviterID string, emails []string, typ repository.InvitationType, targetID string, role repository.WorkspaceRole) (*repository.BulkInvitationResult, error)
+	// GetBulkResult returns a batch of the workspace to the user who started
+	// it or a workspace admin; anyone else gets ErrNotFound
+	GetBulkResult(ctx context.Context, workspaceID, id, userID string) (*repository.BulkInvitationResult, error)
+
 	// List operations
 	ListByWorkspace(ctx context.Context, workspaceID string, limit, offset int) ([]*repository.Invitation, int, error)
 	ListByProject(ctx context.Context, projectID string, limit, offset int) ([]*repository.Invitation, int, error)
@@ -42,11 +51,16 @@ type InvitationService interface {
 	// Link invitations
 	CreateLinkSettings(ctx context.Context, settings *repository.InvitationLinkSettings) error
 	UseLink(ctx context.Context, linkToken string, emailAddr string) (*repository.Invitation, *repository.InvitationLinkSettings, error)
+	AcceptLink(ctx context.Context, linkToken, userID, emailAddr string) (*LinkAcceptance, error)
 	GetLinkSettingsByToken(ctx context.Context, token string) (*repository.InvitationLinkSettings, error)
 
 	// Stats and analytics
 	GetStatsByWorkspace(ctx context.Context, workspaceID string) (*repository.InvitationStats, error)
 
+	// Maintenance
+	ExpirePendingInvitations(ctx context.Context) (int, error)
+	SendPendingReminders(ctx context.Context) (int, error)
+
 	// Token management
 	RegenerateToken(ctx context.Context, id string) (string, error)
 
@@ -62,6 +76,36 @@ type InvitationService interface {
 	CreateAccessRequest(ctx context.Context, req *repository.AccessRequest) error
 }
 
+// InvitationLimitError reports a target that already has as many pending
+// invitations as its type allows. It unwraps to ErrLimitExceeded.
+type InvitationLimitError struct {
+	Type         repository.InvitationType
+	TargetID     string
+	PendingCount int
+	Limit        int
+}
+
+func (e *InvitationLimitError) Error() string {
+	return fmt.Sprintf("%s: this %s already has %d of %d allowed pending invitations",
+		ErrLimitExceeded, e.Type, e.PendingCount, e.Limit)
+}
+
+func (e *InvitationLimitError) Unwrap() error { return ErrLimitExceeded }
+
+var (
+	errInvitationRole      = errors.New("invalid role for invitation type")
+	errInvitationDuplicate = errors.New("pending invitation already exists for this email and target")
+)
+
+// LinkAcceptance is the outcome of accepting an invitation link: either the
+// accepted invitation, or a pending access request when the link requires approval
+type LinkAcceptance struct {
+	Invitation      *repository.Invitation
+	Settings        *repository.InvitationLinkSettings
+	AccessRequest   *repository.AccessRequest
+	PendingApproval bool
+}
+
 type invitationService struct {
 	invRepo      repository.InvitationRepository
 	workspaceRepo repository.WorkspaceRepository
@@ -69,8 +113,15 @@ type invitationService struct {
 	projectRepo  repository.ProjectRepository
 	userRepo     repository.UserRepository
 	spaceRepo    repository.SpaceRepository
+	memberService MemberService
 	emailSvc     *email.Service
+	notifSvc     *notification.Service
 	defaultTTL   time.Duration
+
+	reminderInterval time.Duration
+	maxReminders     int
+	resendCooldown   time.Duration
+	pendingLimits    map[string]int // by invitation type
 }
 
 func NewInvitationService(
@@ -80,7 +131,13 @@ func NewInvitationService(
 	projectRepo repository.ProjectRepository,
 	userRepo repository.UserRepository,
 	spaceRepo repository.SpaceRepository,
+	memberService MemberService,
 	emailSvc *email.Service,
+	notifSvc *notification.Service,
+	reminderInterval time.Duration,
+	maxReminders int,
+	resendCooldown time.Duration,
+	pendingLimits map[string]int,
 ) InvitationService {
 	return &invitationService{
 		invRepo:      invRepo,
@@ -89,8 +146,15 @@ func NewInvitationService(
 		projectRepo:  projectRepo,
 		userRepo:     userRepo,
 		spaceRepo:    spaceRepo,
+		memberService: memberService,
 		emailSvc:     emailSvc,
+		notifSvc:     notifSvc,
 		defaultTTL:   30 * 24 * time.Hour,
+
+		reminderInterval: reminderInterval,
+		maxReminders:     maxReminders,
+		resendCooldown:   resendCooldown,
+		pendingLimits:    pendingLimits,
 	}
 }
 
@@ -128,7 +192,7 @@ func (s *invitationService) CreateInvitation(ctx context.Context, inv *repositor
 		inv.Role = repository.WorkspaceRoleMember
 	}
 	if !allowedRoleForType(inv.Type, inv.Role) {
-		return errors.New("invalid role for invitation type")
+		return errInvitationRole
 	}
 	if inv.Permission == "" {
 		inv.Permission = repository.DefaultPermissionForRole(inv.Role)
@@ -147,10 +211,14 @@ func (s *invitationService) CreateInvitation(ctx context.Context, inv *repositor
 	if inv.TargetID != "" && inv.Email != "" {
 		exists, err := s.invRepo.ExistsPendingForEmail(ctx, inv.Email, inv.Type, inv.TargetID)
 		if err == nil && exists {
-			return errors.New("pending invitation already exists for this email and target")
+			return errInvitationDuplicate
 		}
 	}
 
+	if err := s.checkPendingCapacity(ctx, inv.Type, inv.TargetID); err != nil {
+		return err
+	}
+
 	if err := s.invRepo.Create(ctx, inv); err != nil {
 		return err
 	}
@@ -163,25 +231,41 @@ func (s *invitationService) CreateInvitation(ctx context.Context, inv *repositor
 	})
 
 	if s.emailSvc != nil && inv.Method == repository.InvitationMethodEmail {
-	go func(inv *repository.Invitation) {
+	// The email outlives the request, but keeps its request ID for the logs
+	go func(ctx context.Context, inv *repository.Invitation) {
 		workspaceName := inv.WorkspaceID
-		if ws, err := s.workspaceRepo.FindByID(context.Background(), inv.WorkspaceID); err == nil && ws != nil {
+		if ws, err := s.workspaceRepo.FindByID(ctx, inv.WorkspaceID); err == nil && ws != nil {
 			workspaceName = ws.Name
 		}
-		
-		// ✅ ADD ERROR LOGGING
-		log.Printf("📧 Sending invitation email to: %s", inv.Email)
+
 		if err := s.emailSvc.SendInvitation(workspaceName, inv.Email, inv.InvitedByName, inv.Token); err != nil {
-			log.Printf("❌ Failed to send invitation email: %v", err)
+			slog.ErrorContext(ctx, "failed to send invitation email", "invitationID", inv.ID, "email", inv.Email, "error", err)
 		} else {
-			log.Printf("✅ Invitation email sent successfully to: %s", inv.Email)
+			slog.InfoContext(ctx, "invitation email sent", "invitationID", inv.ID, "email", inv.Email)
 		}
-	}(inv)
+	}(context.WithoutCancel(ctx), inv)
 }
 
 	return nil
 }
 
+// checkPendingCapacity refuses a new invitation once the target is at its pending limit
+func (s *invitationService) checkPendingCapacity(ctx context.Context, typ repository.InvitationType, targetID string) error {
+	limit := s.pendingLimits[string(typ)]
+	if limit <= 0 || targetID == "" {
+		return nil
+	}
+
+	count, err := s.invRepo.CountPendingByTarget(ctx, typ, targetID)
+	if err != nil {
+		return err
+	}
+	if count >= limit {
+		return &InvitationLimitError{Type: typ, TargetID: targetID, PendingCount: count, Limit: limit}
+	}
+	return nil
+}
+
 func (s *invitationService) CreateWithPermissions(ctx context.Context, inv *repository.Invitation, perms *repository.InvitationPermissions) error {
 	if inv == nil {
 		return errors.New("invitation is nil")
@@ -306,7 +390,19 @@ func (s *invitationService) AcceptByToken(ctx context.Context, token string, use
 	return s.addUserToTarget(ctx, inv, userID)
 }
 
+// addUserToTarget writes the membership granted by inv and drops the caches
+// that would otherwise hide it until their TTL runs out
 func (s *invitationService) addUserToTarget(ctx context.Context, inv *repository.Invitation, userID string) error {
+	if err := s.addMembership(ctx, inv, userID); err != nil {
+		return err
+	}
+	if s.memberService != nil {
+		s.memberService.InvalidateMembership(ctx, userID)
+	}
+	return nil
+}
+
+func (s *invitationService) addMembership(ctx context.Context, inv *repository.Invitation, userID string) error {
 	switch inv.Type {
 	case repository.InvitationTypeWorkspace:
 		member := &repository.WorkspaceMember{
@@ -399,9 +495,13 @@ func (s *invitationService) ResendInvitation(ctx context.Context, id string, act
 	if !inv.CanResend() {
 		return nil, errors.New("invitation cannot be resent")
 	}
-	if err := s.invRepo.UpdateReminderSent(ctx, id); err != nil {
+	claimed, err := s.invRepo.ClaimResend(ctx, id, s.resendCooldown)
+	if err != nil {
 		return nil, err
 	}
+	if !claimed {
+		return nil, fmt.Errorf("%w: invitation was sent less than %s ago", ErrTooManyRequests, s.resendCooldown)
+	}
 	_ = s.invRepo.LogActivity(ctx, &repository.InvitationActivity{
 		InvitationID: id,
 		Action:       "resent",
@@ -482,6 +582,236 @@ func (s *invitationService) CreateProjectInvitation(ctx context.Context, workspa
 	return inv, nil
 }
 
+// BulkInvite records a batch result and sends the invitations in the background.
+// The returned result starts as "processing"; poll GetBulkResult for the counts.
+func (s *invitationService) BulkInvite(ctx context.Context, workspaceID, inviterID string, emails []string, typ repository.InvitationType, targetID string, role repository.WorkspaceRole) (*repository.BulkInvitationResult, error) {
+	if len(emails) == 0 {
+		return nil, fmt.Errorf("%w: at least one email required", ErrInvalidInput)
+	}
+	if typ == "" {
+		typ = repository.InvitationTypeWorkspace
+	}
+	if typ == repository.InvitationTypeWorkspace && targetID == "" {
+		targetID = workspaceID
+	}
+	if targetID == "" {
+		return nil, fmt.Errorf("%w: target_id required", ErrInvalidInput)
+	}
+	if role == "" {
+		role = repository.WorkspaceRoleMember
+	}
+	if !allowedRoleForType(typ, role) {
+		return nil, fmt.Errorf("%w: invalid role for invitation type", ErrInvalidInput)
+	}
+
+	workspace, err := s.workspaceRepo.FindByID(ctx, workspaceID)
+	if err != nil || workspace == nil {
+		return nil, ErrWorkspaceNotFound
+	}
+
+	targetName, err := s.authorizeBulkInvite(ctx, workspace, inviterID, typ, targetID, role)
+	if err != nil {
+		return nil, err
+	}
+
+	inviterName := "Someone"
+	if inviter, _ := s.userRepo.FindByID(ctx, inviterID); inviter != nil {
+		inviterName = inviter.Name
+	}
+
+	// Deduplicate while keeping the submitted order
+	seen := make(map[string]bool, len(emails))
+	unique := make([]string, 0, len(emails))
+	for _, e := range emails {
+		e = normalizeEmail(e)
+		if e == "" || seen[e] {
+			continue
+		}
+		seen[e] = true
+		unique = append(unique, e)
+	}
+
+	result := &repository.BulkInvitationResult{
+		WorkspaceID: workspaceID,
+		InvitedByID: inviterID,
+		Type:        typ,
+		TargetID:    targetID,
+		Role:        role,
+		TotalCount:  len(unique),
+		Status:      "processing",
+	}
+	if err := s.invRepo.CreateBulkResult(ctx, result); err != nil {
+		return nil, err
+	}
+
+	template := repository.Invitation{
+		WorkspaceID:   workspaceID,
+		Type:          typ,
+		TargetID:      targetID,
+		TargetName:    targetName,
+		Role:          role,
+		InvitedByID:   inviterID,
+		InvitedByName: inviterName,
+	}
+	go s.processBulkInvite(*result, template, unique)
+
+	return result, nil
+}
+
+// authorizeBulkInvite checks the target belongs to the workspace and that the
+// inviter may invite to it: workspace admins for workspace and team targets,
+// project leads for projects. Nobody may invite with a role above their own.
+// It returns the target's name for the invitation emails.
+func (s *invitationService) authorizeBulkInvite(ctx context.Context, workspace *repository.Workspace, inviterID string, typ repository.InvitationType, targetID string, role repository.WorkspaceRole) (string, error) {
+	entityType, entityID, minRole := EntityTypeWorkspace, workspace.ID, PermissionAdmin
+	targetName := workspace.Name
+
+	switch typ {
+	case repository.InvitationTypeWorkspace:
+		if targetID != workspace.ID {
+			return "", fmt.Errorf("%w: target_id must be the workspace", ErrInvalidInput)
+		}
+	case repository.InvitationTypeProject:
+		project, err := s.projectRepo.FindByID(ctx, targetID)
+		if err != nil || project == nil {
+			return "", ErrProjectNotFound
+		}
+		space, err := s.spaceRepo.FindByID(ctx, project.SpaceID)
+		if err != nil || space == nil || space.WorkspaceID != workspace.ID {
+			return "", ErrProjectNotFound
+		}
+		entityType, entityID, minRole = EntityTypeProject, project.ID, PermissionLead
+		targetName = project.Name
+	case repository.InvitationTypeTeam:
+		team, err := s.teamRepo.FindByID(ctx, targetID)
+		if err != nil || team == nil || team.WorkspaceID != workspace.ID {
+			return "", ErrTeamNotFound
+		}
+		targetName = team.Name
+	default:
+		return "", fmt.Errorf("%w: bulk invitations support workspace, project and team targets", ErrInvalidInput)
+	}
+
+	hasAccess, inviterRole, err := s.memberService.HasEffectiveAccess(ctx, entityType, entityID, inviterID)
+	if err != nil || !hasAccess {
+		return "", ErrUnauthorized
+	}
+	inviterRole = normalizeRole(inviterRole)
+	if !hasMinimumRole(inviterRole, minRole) {
+		return "", ErrUnauthorized
+	}
+	if err := checkRoleGrant(inviterRole, invitedMemberRole(role)); err != nil {
+		return "", err
+	}
+	return targetName, nil
+}
+
+// invitedMemberRole places an invitation role in the member role hierarchy
+func invitedMemberRole(role repository.WorkspaceRole) string {
+	switch role {
+	case repository.WorkspaceRoleOwner:
+		return PermissionOwner
+	case repository.WorkspaceRoleAdmin:
+		return PermissionAdmin
+	case repository.WorkspaceRoleMember:
+		return PermissionMember
+	default:
+		return PermissionViewer
+	}
+}
+
+// bulkInviteFailure is one entry of BulkInvitationResult.FailedEmails
+type bulkInviteFailure struct {
+	Email string `json:"email"`
+	Error string `json:"error"`
+}
+
+// processBulkInvite creates one invitation per email. A failing email is recorded
+// and the batch moves on, so one bad address never aborts the rest.
+func (s *invitationService) processBulkInvite(result repository.BulkInvitationResult, template repository.Invitation, emails []string) {
+	ctx := context.Background()
+	var failures []bulkInviteFailure
+
+	for _, addr := range emails {
+		if _, err := mail.ParseAddress(addr); err != nil {
+			failures = append(failures, bulkInviteFailure{Email: addr, Error: "invalid email address"})
+			continue
+		}
+
+		exists, err := s.invRepo.ExistsPendingForEmail(ctx, addr, template.Type, template.TargetID)
+		if err != nil {
+			failures = append(failures, bulkInviteFailure{Email: addr, Error: bulkInviteError(ctx, result.ID, addr, err)})
+			continue
+		}
+		if exists {
+			result.SkippedCount++
+			continue
+		}
+
+		inv := template
+		inv.Email = addr
+		if err := s.CreateInvitation(ctx, &inv); err != nil {
+			failures = append(failures, bulkInviteFailure{Email: addr, Error: bulkInviteError(ctx, result.ID, addr, err)})
+			continue
+		}
+		result.SuccessCount++
+	}
+
+	result.FailedCount = len(failures)
+	if len(failures) > 0 {
+		if data, err := json.Marshal(failures); err == nil {
+			result.FailedEmails = strPtr(string(data))
+		}
+	}
+
+	result.Status = "completed"
+	if result.SuccessCount == 0 && result.FailedCount > 0 {
+		result.Status = "failed"
+	}
+	now := time.Now()
+	result.CompletedAt = &now
+
+	if err := s.invRepo.UpdateBulkResult(ctx, &result); err != nil {
+		slog.ErrorContext(ctx, "failed to finalize bulk invitation", "resultID", result.ID, "error", err)
+	}
+}
+
+// bulkInviteError is the message stored for a failed bulk invite. Any admin
+// polling the result can read it, so only known failures are described;
+// anything else is logged and reported as an internal error.
+func bulkInviteError(ctx context.Context, resultID, addr string, err error) string {
+	switch {
+	case errors.Is(err, ErrLimitExceeded):
+		return "pending invitation limit reached"
+	case errors.Is(err, errInvitationDuplicate):
+		return "pending invitation already exists"
+	case errors.Is(err, errInvitationRole):
+		return "invalid role for invitation type"
+	}
+	slog.ErrorContext(ctx, "bulk invitation failed", "resultID", resultID, "email", addr, "error", err)
+	return "internal error"
+}
+
+func (s *invitationService) GetBulkResult(ctx context.Context, workspaceID, id, userID string) (*repository.BulkInvitationResult, error) {
+	if id == "" {
+		return nil, fmt.Errorf("%w: id required", ErrInvalidInput)
+	}
+	result, err := s.invRepo.GetBulkResult(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if result == nil || result.WorkspaceID != workspaceID {
+		return nil, ErrNotFound
+	}
+	if result.InvitedByID != userID {
+		hasAccess, role, err := s.memberService.HasEffectiveAccess(ctx, EntityTypeWorkspace, workspaceID, userID)
+		if err != nil || !hasAccess || !hasMinimumRole(normalizeRole(role), PermissionAdmin) {
+			return nil, ErrNotFound
+		}
+	}
+	return result, nil
+}
+
 func (s *invitationService) ListByWorkspace(ctx context.Context, workspaceID string, limit, offset int) ([]*repository.Invitation, int, error) {
 	return s.invRepo.FindByWorkspace(ctx, workspaceID, limit, offset)
 }
@@ -536,30 +866,128 @@ func (s *invitationService) CreateLinkSettings(ctx context.Context, settings *re
 	return s.invRepo.CreateLinkSettings(ctx, settings)
 }
 
-func (s *invitationService) UseLink(ctx context.Context, linkToken string, emailAddr string) (*repository.Invitation, *repository.InvitationLinkSettings, error) {
+// checkLink loads link settings and verifies the link is usable by the given email
+func (s *invitationService) checkLink(ctx context.Context, linkToken, emailAddr string) (*repository.InvitationLinkSettings, error) {
 	if linkToken == "" {
-		return nil, nil, errors.New("link token required")
+		return nil, errors.New("link token required")
 	}
 	if emailAddr == "" {
-		return nil, nil, errors.New("email required")
+		return nil, errors.New("email required")
 	}
-	emailAddr = normalizeEmail(emailAddr)
 
 	ls, err := s.invRepo.GetLinkSettingsByToken(ctx, linkToken)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 	if ls == nil {
-		return nil, nil, errors.New("link not found")
+		return nil, errors.New("link not found")
 	}
 	if !ls.IsValid() {
-		return nil, nil, errors.New("link is not valid (inactive/expired/max-uses)")
+		return nil, repository.ErrLinkUnavailable
 	}
 	if !ls.CheckDomain(emailAddr) {
-		return nil, nil, errors.New("email domain not allowed by link settings")
+		return nil, fmt.Errorf("%w: %s", ErrEmailDomainNotAllowed, emailAddr[strings.LastIndex(emailAddr, "@")+1:])
 	}
+	return ls, nil
+}
+
+// AcceptLink joins the link's target right away, or files a pending access
+// request for the workspace admins when the link requires approval
+func (s *invitationService) AcceptLink(ctx context.Context, linkToken, userID, emailAddr string) (*LinkAcceptance, error) {
+	if userID == "" {
+		return nil, errors.New("user_id required")
+	}
+	emailAddr = normalizeEmail(emailAddr)
 
-	if err := s.invRepo.IncrementLinkSettingsUseCount(ctx, ls.ID); err != nil {
+	ls, err := s.checkLink(ctx, linkToken, emailAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	if ls.RequiresApproval {
+		req, err := s.requestLinkApproval(ctx, ls, userID, emailAddr)
+		if err != nil {
+			return nil, err
+		}
+		return &LinkAcceptance{Settings: ls, AccessRequest: req, PendingApproval: true}, nil
+	}
+
+	inv, ls, err := s.UseLink(ctx, linkToken, emailAddr)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.AcceptByID(ctx, inv.ID, userID); err != nil {
+		return nil, err
+	}
+	return &LinkAcceptance{Invitation: inv, Settings: ls}, nil
+}
+
+// requestLinkApproval records a pending access request for an approval-gated
+// link and notifies the workspace admins. Link uses are not consumed until an
+// admin approves.
+func (s *invitationService) requestLinkApproval(ctx context.Context, ls *repository.InvitationLinkSettings, userID, emailAddr string) (*repository.AccessRequest, error) {
+	existing, err := s.invRepo.GetAccessRequestsByRequester(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range existing {
+		if r.Status == AccessRequestPending && r.Type == ls.Type && r.TargetID == ls.TargetID {
+			return r, nil
+		}
+	}
+
+	req := &repository.AccessRequest{
+		WorkspaceID:    ls.WorkspaceID,
+		RequesterID:    userID,
+		Email:          emailAddr,
+		Type:           ls.Type,
+		TargetID:       ls.TargetID,
+		Message:        strPtr("requested via invitation link"),
+		Status:         AccessRequestPending,
+		LinkSettingsID: &ls.ID,
+	}
+	if err := s.invRepo.CreateAccessRequest(ctx, req); err != nil {
+		return nil, err
+	}
+
+	if s.notifSvc != nil {
+		members, err := s.workspaceRepo.FindMembers(ctx, ls.WorkspaceID)
+		if err != nil {
+			slog.WarnContext(ctx, "failed to load workspace admins", "workspaceID", ls.WorkspaceID, "error", err)
+			return req, nil
+		}
+		var adminIDs []string
+		for _, m := range members {
+			if getRoleLevel(m.Role) >= 4 {
+				adminIDs = append(adminIDs, m.UserID)
+			}
+		}
+
+		requesterName := emailAddr
+		if user, _ := s.userRepo.FindByID(ctx, userID); user != nil {
+			requesterName = user.Name
+		}
+		_ = s.notifSvc.SendBatchNotifications(ctx, adminIDs, userID,
+			notification.TypeAccessRequested,
+			"Access Request",
+			fmt.Sprintf("%s requested access to %s via an invitation link", requesterName, ls.Type),
+			map[string]interface{}{
+				"accessRequestId": req.ID,
+				"workspaceId":     ls.WorkspaceID,
+				"targetType":      string(ls.Type),
+				"targetId":        ls.TargetID,
+				"action":          "review_access_request",
+			},
+		)
+	}
+
+	return req, nil
+}
+
+func (s *invitationService) UseLink(ctx context.Context, linkToken string, emailAddr string) (*repository.Invitation, *repository.InvitationLinkSettings, error) {
+	emailAddr = normalizeEmail(emailAddr)
+	ls, err := s.checkLink(ctx, linkToken, emailAddr)
+	if err != nil {
 		return nil, nil, err
 	}
 
@@ -578,9 +1006,12 @@ func (s *invitationService) UseLink(ctx context.Context, linkToken string, email
 		InvitedByID:   ls.CreatedByID,
 		InvitedByName: "",
 	}
-	if err := s.invRepo.Create(ctx, inv); err != nil {
+	// IsValid above is only a fast path; the use count is re-checked and
+	// consumed atomically together with the insert
+	if err := s.invRepo.CreateFromLink(ctx, ls.ID, inv); err != nil {
 		return nil, nil, err
 	}
+	ls.UseCount++
 
 	_ = s.invRepo.LogActivity(ctx, &repository.InvitationActivity{
 		InvitationID: inv.ID,
@@ -617,6 +1048,60 @@ func (s *invitationService) GetStatsByWorkspace(ctx context.Context, workspaceID
 	return s.invRepo.GetStatsByWorkspace(ctx, workspaceID)
 }
 
+// ExpirePendingInvitations marks pending invitations past their expiry as expired
+func (s *invitationService) ExpirePendingInvitations(ctx context.Context) (int, error) {
+	return s.invRepo.MarkAllExpired(ctx)
+}
+
+// SendPendingReminders re-sends the email of pending invitations that have
+// waited at least the reminder interval, up to the configured max reminders
+func (s *invitationService) SendPendingReminders(ctx context.Context) (int, error) {
+	if s.emailSvc == nil || s.maxReminders <= 0 {
+		return 0, nil
+	}
+
+	invitations, err := s.invRepo.FindPendingForReminder(ctx, s.reminderInterval, s.maxReminders)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, inv := range invitations {
+		// Link-only invitations have nobody to remind
+		if inv.Method == repository.InvitationMethodLink || inv.Email == "" {
+			continue
+		}
+
+		workspaceName := inv.WorkspaceID
+		if ws, err := s.workspaceRepo.FindByID(ctx, inv.WorkspaceID); err == nil && ws != nil {
+			workspaceName = ws.Name
+		}
+
+		// Shares the resend cooldown, so a manual resend just now skips the reminder
+		claimed, err := s.invRepo.ClaimResend(ctx, inv.ID, s.resendCooldown)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to record invitation reminder", "invitationID", inv.ID, "error", err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+
+		if err := s.emailSvc.SendInvitation(workspaceName, inv.Email, inv.InvitedByName, inv.Token); err != nil {
+			slog.ErrorContext(ctx, "failed to send invitation reminder", "invitationID", inv.ID, "email", inv.Email, "error", err)
+			continue
+		}
+		_ = s.invRepo.LogActivity(ctx, &repository.InvitationActivity{
+			InvitationID: inv.ID,
+			Action:       "reminder_sent",
+			ActorType:    "system",
+		})
+		sent++
+	}
+
+	return sent, nil
+}
+
 func (s *invitationService) RegenerateToken(ctx context.Context, id string) (string, error) {
 	if id == "" {
 		return "", errors.New("id required")
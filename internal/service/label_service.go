@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/Marga-Ghale/ora-scrum-backend/internal/repository"
 )
@@ -16,14 +17,18 @@ type LabelService interface {
 	ListByProject(ctx context.Context, projectID string) ([]*repository.Label, error)
 	Update(ctx context.Context, id string, name, color *string) (*repository.Label, error)
 	Delete(ctx context.Context, id string) error
+	ListUsageByProject(ctx context.Context, projectID string) ([]*repository.LabelUsage, error)
+	MergeLabels(ctx context.Context, projectID, sourceLabelID, targetLabelID, userID string) (*repository.LabelUsage, error)
 }
 
 type labelService struct {
-	labelRepo repository.LabelRepository
+	labelRepo   repository.LabelRepository
+	permService PermissionService
+	readCache   *ReadCache
 }
 
-func NewLabelService(labelRepo repository.LabelRepository) LabelService {
-	return &labelService{labelRepo: labelRepo}
+func NewLabelService(labelRepo repository.LabelRepository, permService PermissionService, readCache *ReadCache) LabelService {
+	return &labelService{labelRepo: labelRepo, permService: permService, readCache: readCache}
 }
 
 func (s *labelService) Create(ctx context.Context, projectID, name, color string) (*repository.Label, error) {
@@ -51,7 +56,7 @@ func (s *labelService) GetByID(ctx context.Context, id string) (*repository.Labe
 		return nil, err
 	}
 	if label == nil {
-		return nil, ErrNotFound
+		return nil, ErrLabelNotFound
 	}
 	return label, nil
 }
@@ -63,7 +68,7 @@ func (s *labelService) ListByProject(ctx context.Context, projectID string) ([]*
 func (s *labelService) Update(ctx context.Context, id string, name, color *string) (*repository.Label, error) {
 	label, err := s.labelRepo.FindByID(ctx, id)
 	if err != nil || label == nil {
-		return nil, ErrNotFound
+		return nil, ErrLabelNotFound
 	}
 
 	if name != nil {
@@ -87,3 +92,43 @@ func (s *labelService) Update(ctx context.Context, id string, name, color *strin
 func (s *labelService) Delete(ctx context.Context, id string) error {
 	return s.labelRepo.Delete(ctx, id)
 }
+
+func (s *labelService) ListUsageByProject(ctx context.Context, projectID string) ([]*repository.LabelUsage, error) {
+	return s.labelRepo.FindUsageByProjectID(ctx, projectID)
+}
+
+// MergeLabels folds a duplicate label into another label of the same project:
+// tasks carrying the source get the target instead and the source is deleted
+func (s *labelService) MergeLabels(ctx context.Context, projectID, sourceLabelID, targetLabelID, userID string) (*repository.LabelUsage, error) {
+	if sourceLabelID == targetLabelID {
+		return nil, fmt.Errorf("%w: cannot merge a label into itself", ErrInvalidInput)
+	}
+
+	if !s.permService.CanManageProject(ctx, userID, projectID) {
+		return nil, ErrUnauthorized
+	}
+
+	source, err := s.labelRepo.FindByID(ctx, sourceLabelID)
+	if err != nil {
+		return nil, err
+	}
+	target, err := s.labelRepo.FindByID(ctx, targetLabelID)
+	if err != nil {
+		return nil, err
+	}
+	if source == nil || target == nil || source.ProjectID != projectID || target.ProjectID != projectID {
+		return nil, ErrNotFound
+	}
+
+	if err := s.labelRepo.Merge(ctx, sourceLabelID, targetLabelID); err != nil {
+		return nil, err
+	}
+	// Boards and stats carry the tasks' label IDs, which the merge rewrote
+	s.readCache.InvalidateProject(ctx, projectID)
+
+	count, err := s.labelRepo.CountUsage(ctx, targetLabelID)
+	if err != nil {
+		return nil, err
+	}
+	return &repository.LabelUsage{Label: target, UsageCount: count}, nil
+}
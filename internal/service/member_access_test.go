@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/repository"
+)
+
+// hierarchy is an in-memory workspace tree plus the user's direct memberships
+type hierarchy struct {
+	spaces   []*repository.Space
+	folders  []*repository.Folder
+	projects []*repository.Project
+	member   map[string][]string // entity type -> IDs the user is a direct member of
+}
+
+func (h *hierarchy) isMember(entityType, id string) bool {
+	for _, m := range h.member[entityType] {
+		if m == id {
+			return true
+		}
+	}
+	return false
+}
+
+type treeWorkspaceRepo struct {
+	repository.WorkspaceRepository
+	h *hierarchy
+}
+
+func (r treeWorkspaceRepo) FindByUserID(context.Context, string) ([]*repository.Workspace, error) {
+	var result []*repository.Workspace
+	for _, id := range r.h.member[EntityTypeWorkspace] {
+		result = append(result, &repository.Workspace{ID: id})
+	}
+	return result, nil
+}
+
+type treeSpaceRepo struct {
+	repository.SpaceRepository
+	h *hierarchy
+}
+
+func (r treeSpaceRepo) FindByWorkspaceID(_ context.Context, workspaceID string) ([]*repository.Space, error) {
+	var result []*repository.Space
+	for _, sp := range r.h.spaces {
+		if sp.WorkspaceID == workspaceID {
+			result = append(result, sp)
+		}
+	}
+	return result, nil
+}
+
+func (r treeSpaceRepo) FindByUserID(context.Context, string) ([]*repository.Space, error) {
+	var result []*repository.Space
+	for _, sp := range r.h.spaces {
+		if r.h.isMember(EntityTypeSpace, sp.ID) {
+			result = append(result, sp)
+		}
+	}
+	return result, nil
+}
+
+type treeFolderRepo struct {
+	repository.FolderRepository
+	h *hierarchy
+}
+
+func (r treeFolderRepo) FindBySpaceID(_ context.Context, spaceID string) ([]*repository.Folder, error) {
+	var result []*repository.Folder
+	for _, f := range r.h.folders {
+		if f.SpaceID == spaceID {
+			result = append(result, f)
+		}
+	}
+	return result, nil
+}
+
+func (r treeFolderRepo) FindByUserID(context.Context, string) ([]*repository.Folder, error) {
+	var result []*repository.Folder
+	for _, f := range r.h.folders {
+		if r.h.isMember(EntityTypeFolder, f.ID) {
+			result = append(result, f)
+		}
+	}
+	return result, nil
+}
+
+type treeProjectRepo struct {
+	repository.ProjectRepository
+	h *hierarchy
+}
+
+func (r treeProjectRepo) FindBySpaceID(_ context.Context, spaceID string) ([]*repository.Project, error) {
+	var result []*repository.Project
+	for _, p := range r.h.projects {
+		if p.SpaceID == spaceID {
+			result = append(result, p)
+		}
+	}
+	return result, nil
+}
+
+func (r treeProjectRepo) FindByFolderID(_ context.Context, folderID string) ([]*repository.Project, error) {
+	var result []*repository.Project
+	for _, p := range r.h.projects {
+		if p.FolderID != nil && *p.FolderID == folderID {
+			result = append(result, p)
+		}
+	}
+	return result, nil
+}
+
+func (r treeProjectRepo) FindByUserID(context.Context, string) ([]*repository.Project, error) {
+	var result []*repository.Project
+	for _, p := range r.h.projects {
+		if r.h.isMember(EntityTypeProject, p.ID) {
+			result = append(result, p)
+		}
+	}
+	return result, nil
+}
+
+func TestGetUserAllAccessIncludesInheritedEntities(t *testing.T) {
+	inFolder := func(id string) *string { return &id }
+	h := &hierarchy{
+		spaces: []*repository.Space{
+			{ID: "s1", WorkspaceID: "ws1"},
+			{ID: "s2", WorkspaceID: "ws1"},
+			{ID: "s3", WorkspaceID: "ws2"},
+		},
+		folders: []*repository.Folder{
+			{ID: "f1", SpaceID: "s1"},
+			{ID: "f2", SpaceID: "s2"},
+			{ID: "f3", SpaceID: "s3"},
+		},
+		projects: []*repository.Project{
+			{ID: "p1", SpaceID: "s1", FolderID: inFolder("f1")},
+			{ID: "p2", SpaceID: "s1"},
+			{ID: "p3", SpaceID: "s2", FolderID: inFolder("f2")},
+			{ID: "p4", SpaceID: "s3", FolderID: inFolder("f3")},
+			{ID: "p5", SpaceID: "s3", FolderID: inFolder("f3")},
+			{ID: "p6", SpaceID: "s3"},
+		},
+		// Member of workspace ws1 and, in ws2, only of folder f3. The
+		// direct memberships in s1 and p1 are also inherited from ws1.
+		member: map[string][]string{
+			EntityTypeWorkspace: {"ws1"},
+			EntityTypeSpace:     {"s1"},
+			EntityTypeFolder:    {"f3"},
+			EntityTypeProject:   {"p1"},
+		},
+	}
+	svc := &memberService{
+		workspaceRepo: treeWorkspaceRepo{h: h},
+		spaceRepo:     treeSpaceRepo{h: h},
+		folderRepo:    treeFolderRepo{h: h},
+		projectRepo:   treeProjectRepo{h: h},
+	}
+
+	access, err := svc.GetUserAllAccess(context.Background(), "user-1")
+	if err != nil {
+		t.Fatalf("GetUserAllAccess: %v", err)
+	}
+
+	assertIDs(t, "workspaces", access.Workspaces, "ws1")
+	assertIDs(t, "spaces", access.Spaces, "s1", "s2")
+	assertIDs(t, "folders", access.Folders, "f1", "f2", "f3")
+	// p6 sits in ws2's space s3 outside folder f3, so it stays hidden
+	assertIDs(t, "projects", access.Projects, "p1", "p2", "p3", "p4", "p5")
+}
+
+func assertIDs(t *testing.T, kind string, got []string, want ...string) {
+	t.Helper()
+	sorted := append([]string(nil), got...)
+	sort.Strings(sorted)
+	if len(sorted) != len(want) {
+		t.Fatalf("%s = %v, want %v", kind, got, want)
+	}
+	for i := range want {
+		if sorted[i] != want[i] {
+			t.Fatalf("%s = %v, want %v", kind, got, want)
+		}
+	}
+}
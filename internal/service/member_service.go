@@ -2,7 +2,8 @@ package service
 
 import (
 	"context"
-	"log"
+	"fmt"
+	"log/slog"
 	"time"
 
 	"github.com/Marga-Ghale/ora-scrum-backend/internal/notification"
@@ -18,6 +19,9 @@ type MemberService interface {
 	// UpdateMemberRole(ctx context.Context, entityType, entityID, userID, role string) error
 
 	RemoveMember(ctx context.Context, entityType, entityID, userID, requesterID string) error
+	// InvalidateMembership drops cached access and member lists after a
+	// membership was written outside AddMember, e.g. by invitation acceptance
+	InvalidateMembership(ctx context.Context, userID string)
 	UpdateMemberRole(ctx context.Context, entityType, entityID, userID, role, requesterID string) error
 	
 	GetMember(ctx context.Context, entityType, entityID, userID string) (*UnifiedMember, error)
@@ -29,6 +33,9 @@ type MemberService interface {
 	// Access control
 	HasDirectAccess(ctx context.Context, entityType, entityID, userID string) (bool, error)
 	HasEffectiveAccess(ctx context.Context, entityType, entityID, userID string) (bool, string, error)
+	// FilterAccessibleProjects resolves access once per distinct project;
+	// projects that could not be checked map to false
+	FilterAccessibleProjects(ctx context.Context, userID string, projectIDs []string) map[string]bool
 	GetAccessLevel(ctx context.Context, entityType, entityID, userID string) (string, string, error)
 	
 	// ✅ NEW: Detailed access information
@@ -115,6 +122,8 @@ type memberService struct {
 	userRepo      repository.UserRepository
 	notifSvc      *notification.Service
 	broadcaster   *socket.Broadcaster 
+	accessCache   *AccessCache
+	readCache     *ReadCache
 }
 
 func NewMemberService(
@@ -125,6 +134,8 @@ func NewMemberService(
 	userRepo repository.UserRepository,
 	notifSvc *notification.Service,
 	broadcaster *socket.Broadcaster,
+	accessCache *AccessCache, // may be nil
+	readCache *ReadCache, // may be nil
 ) MemberService {
 	return &memberService{
 		workspaceRepo: workspaceRepo,
@@ -134,11 +145,27 @@ func NewMemberService(
 		userRepo:      userRepo,
 		notifSvc:      notifSvc,
 		broadcaster:   broadcaster,
+		accessCache:   accessCache,
+		readCache:     readCache,
 	}
 }
 
-// AddMember - UNCHANGED (keeping your existing permission logic)
+// AddMember adds a direct member and drops the user's cached access
 func (s *memberService) AddMember(ctx context.Context, entityType, entityID, userID, role, inviterID string) error {
+	if err := s.addMember(ctx, entityType, entityID, userID, role, inviterID); err != nil {
+		return err
+	}
+	s.InvalidateMembership(ctx, userID)
+	return nil
+}
+
+func (s *memberService) InvalidateMembership(ctx context.Context, userID string) {
+	s.accessCache.InvalidateUser(ctx, userID)
+	s.readCache.InvalidateMemberLists(ctx)
+}
+
+// addMember - UNCHANGED (keeping your existing permission logic)
+func (s *memberService) addMember(ctx context.Context, entityType, entityID, userID, role, inviterID string) error {
 	// Verify user exists first
 	user, err := s.userRepo.FindByID(ctx, userID)
 	if err != nil || user == nil {
@@ -154,10 +181,11 @@ func (s *memberService) AddMember(ctx context.Context, entityType, entityID, use
 	// ✅ FIXED: Permission check logic
 	hasPermission := false
 	isCreatorAddingSelf := inviterID == userID
+	// Only the creator's first membership of a new entity skips the checks
+	isBootstrap := isCreatorAddingSelf && s.isBootstrapAdd(ctx, entityType, entityID, userID)
 
-	// ✅ CRITICAL FIX: For workspace creation, skip permission check
-	if entityType == EntityTypeWorkspace && isCreatorAddingSelf {
-		// When creating workspace, creator adds themselves - ALWAYS ALLOW
+	if entityType == EntityTypeWorkspace && isBootstrap {
+		// When creating workspace, creator adds themselves
 		hasPermission = true
 	} else {
 		// Check permissions for adding OTHER users
@@ -320,11 +348,24 @@ func (s *memberService) AddMember(ctx context.Context, entityType, entityID, use
 	}
 
 	if !hasPermission {
-		log.Printf("[AddMember] DENIED: entityType=%s entityID=%s userID=%s inviterID=%s", 
-			entityType, entityID, userID, inviterID)
+		slog.InfoContext(ctx, "add member denied", "entityType", entityType, "entityID", entityID,
+			"memberID", userID, "inviterID", inviterID)
 		return ErrUnauthorized
 	}
 
+	// Nobody may hand out more than they hold, themselves included
+	if !isBootstrap {
+		inviterRole, _, err := s.GetAccessLevel(ctx, entityType, entityID, inviterID)
+		if err != nil {
+			return ErrUnauthorized
+		}
+		if err := checkRoleGrant(inviterRole, role); err != nil {
+			slog.InfoContext(ctx, "add member denied by role hierarchy", "entityType", entityType,
+				"entityID", entityID, "memberID", userID, "inviterID", inviterID, "role", role, "error", err)
+			return err
+		}
+	}
+
 	// Delegate to appropriate repository
 	switch entityType {
 	case EntityTypeWorkspace:
@@ -431,6 +472,85 @@ func (s *memberService) AddMember(ctx context.Context, entityType, entityID, use
 	}
 }
 
+// checkRoleChange enforces the role hierarchy when an actor moves a member
+// from currentRole to newRole: the actor must be an admin or owner and rank
+// at least as high as both roles, and only owners may grant owner. The
+// last-owner rule needs the member list and is checked by the callers.
+func checkRoleChange(actorRole, currentRole, newRole string) error {
+	actorLevel := getRoleLevel(actorRole)
+	newLevel := getRoleLevel(newRole)
+
+	if newLevel == 0 {
+		return fmt.Errorf("%w: unknown role %q", ErrInvalidInput, newRole)
+	}
+	if actorLevel < getRoleLevel("admin") {
+		return fmt.Errorf("%w: only admins and owners can change member roles", ErrUnauthorized)
+	}
+	if newRole == "owner" && actorRole != "owner" {
+		return fmt.Errorf("%w: only owners can grant the owner role", ErrUnauthorized)
+	}
+	if getRoleLevel(currentRole) > actorLevel {
+		return fmt.Errorf("%w: cannot change the role of a %s as %s", ErrUnauthorized, currentRole, actorRole)
+	}
+	if newLevel > actorLevel {
+		return fmt.Errorf("%w: cannot grant %s as %s", ErrUnauthorized, newRole, actorRole)
+	}
+	return nil
+}
+
+// isBootstrapAdd reports whether userID created the entity and it has no
+// direct members yet, i.e. the creator is being added while it is set up
+func (s *memberService) isBootstrapAdd(ctx context.Context, entityType, entityID, userID string) bool {
+	switch entityType {
+	case EntityTypeWorkspace:
+		ws, _ := s.workspaceRepo.FindByID(ctx, entityID)
+		if ws == nil || ws.OwnerID != userID {
+			return false
+		}
+		members, err := s.workspaceRepo.FindMembers(ctx, entityID)
+		return err == nil && len(members) == 0
+	case EntityTypeSpace:
+		space, _ := s.spaceRepo.FindByID(ctx, entityID)
+		if space == nil || space.OwnerID != userID {
+			return false
+		}
+		members, err := s.spaceRepo.FindMembers(ctx, entityID)
+		return err == nil && len(members) == 0
+	case EntityTypeFolder:
+		folder, _ := s.folderRepo.FindByID(ctx, entityID)
+		if folder == nil || folder.OwnerID != userID {
+			return false
+		}
+		members, err := s.folderRepo.FindMembers(ctx, entityID)
+		return err == nil && len(members) == 0
+	case EntityTypeProject:
+		project, _ := s.projectRepo.FindByID(ctx, entityID)
+		if project == nil || project.CreatedBy == nil || *project.CreatedBy != userID {
+			return false
+		}
+		members, err := s.projectRepo.FindMembers(ctx, entityID)
+		return err == nil && len(members) == 0
+	}
+	return false
+}
+
+// checkRoleGrant enforces the role hierarchy when an actor adds a member with
+// role: the role must exist, only owners may grant owner, and nobody may
+// grant a role above their own
+func checkRoleGrant(actorRole, role string) error {
+	level := getRoleLevel(role)
+	if level == 0 {
+		return fmt.Errorf("%w: unknown role %q", ErrInvalidInput, role)
+	}
+	if role == "owner" && actorRole != "owner" {
+		return fmt.Errorf("%w: only owners can grant the owner role", ErrUnauthorized)
+	}
+	if level > getRoleLevel(actorRole) {
+		return fmt.Errorf("%w: cannot grant %s as %s", ErrUnauthorized, role, actorRole)
+	}
+	return nil
+}
+
 func getRoleLevel(role string) int {
 	roleMap := map[string]int{
 		"owner":  5,
@@ -450,8 +570,8 @@ func (s *memberService) RemoveMember(ctx context.Context, entityType, entityID,
 	// ✅ Get requester's role
 	requesterRole, _, err := s.GetAccessLevel(ctx, entityType, entityID, requesterID)
 	if err != nil {
-		log.Printf("[RemoveMember] DENIED: requester has no access. entityType=%s entityID=%s requesterID=%s",
-			entityType, entityID, requesterID)
+		slog.InfoContext(ctx, "remove member denied: requester has no access", "entityType", entityType,
+			"entityID", entityID, "requesterID", requesterID)
 		return ErrUnauthorized
 	}
 
@@ -459,8 +579,7 @@ func (s *memberService) RemoveMember(ctx context.Context, entityType, entityID,
 
 	// ✅ Only admin (4) or owner (5) can remove members
 	if requesterLevel < 4 {
-		log.Printf("[RemoveMember] DENIED: insufficient role. requesterRole=%s requesterLevel=%d",
-			requesterRole, requesterLevel)
+		slog.InfoContext(ctx, "remove member denied: insufficient role", "requesterRole", requesterRole)
 		return ErrUnauthorized
 	}
 
@@ -474,8 +593,8 @@ func (s *memberService) RemoveMember(ctx context.Context, entityType, entityID,
 
 	// ✅ Cannot remove someone with equal or higher role (except self-removal)
 	if requesterID != userID && targetLevel >= requesterLevel {
-		log.Printf("[RemoveMember] DENIED: cannot remove equal/higher role. requester=%s(%d) target=%s(%d)",
-			requesterRole, requesterLevel, targetMember.Role, targetLevel)
+		slog.InfoContext(ctx, "remove member denied: target role not lower", "requesterRole", requesterRole,
+			"targetRole", targetMember.Role)
 		return ErrUnauthorized
 	}
 
@@ -486,7 +605,7 @@ func (s *memberService) RemoveMember(ctx context.Context, entityType, entityID,
 			return err
 		}
 		if owners <= 1 {
-			log.Printf("[RemoveMember] DENIED: cannot remove last owner")
+			slog.InfoContext(ctx, "remove member denied: last owner", "entityType", entityType, "entityID", entityID)
 			return ErrLastOwner
 		}
 	}
@@ -512,6 +631,8 @@ func (s *memberService) RemoveMember(ctx context.Context, entityType, entityID,
 	if removeErr != nil {
 		return removeErr
 	}
+	s.accessCache.InvalidateUser(ctx, userID)
+	s.readCache.InvalidateMemberLists(ctx)
 
 	// ✅ NEW: Send notification to the removed user (unless they removed themselves)
 	if userID != requesterID {
@@ -521,6 +642,12 @@ func (s *memberService) RemoveMember(ctx context.Context, entityType, entityID,
 	// ✅ BROADCAST MEMBER REMOVED (after successful removal)
 	if s.broadcaster != nil {
 		s.broadcaster.BroadcastMemberRemoved(entityType, entityID, userID, workspaceID, requesterID)
+
+		// Drop the removed user's sockets so they stop receiving workspace rooms;
+		// on reconnect they can only rejoin rooms they are still authorized for
+		if entityType == EntityTypeWorkspace {
+			s.broadcaster.DisconnectUser(userID)
+		}
 	}
 
 	return nil
@@ -611,15 +738,8 @@ func (s *memberService) UpdateMemberRole(ctx context.Context, entityType, entity
 	// ✅ Get requester's role
 	requesterRole, _, err := s.GetAccessLevel(ctx, entityType, entityID, requesterID)
 	if err != nil {
-		log.Printf("[UpdateMemberRole] DENIED: requester has no access")
-		return ErrUnauthorized
-	}
-
-	requesterLevel := getRoleLevel(requesterRole)
-
-	// ✅ Only admin (4) or owner (5) can update roles
-	if requesterLevel < 4 {
-		log.Printf("[UpdateMemberRole] DENIED: insufficient role. requesterRole=%s", requesterRole)
+		slog.InfoContext(ctx, "update member role denied: requester has no access", "entityType", entityType,
+			"entityID", entityID, "requesterID", requesterID)
 		return ErrUnauthorized
 	}
 
@@ -630,19 +750,10 @@ func (s *memberService) UpdateMemberRole(ctx context.Context, entityType, entity
 	}
 
 	oldRole := targetMember.Role
-	targetLevel := getRoleLevel(targetMember.Role)
-	newLevel := getRoleLevel(newRole)
-
-	// ✅ Cannot modify someone with equal or higher role (except self)
-	if requesterID != userID && targetLevel >= requesterLevel {
-		log.Printf("[UpdateMemberRole] DENIED: cannot modify equal/higher role")
-		return ErrUnauthorized
-	}
 
-	// ✅ Cannot assign a role higher than or equal to your own (except owner can assign owner)
-	if newLevel >= requesterLevel && requesterLevel < 5 {
-		log.Printf("[UpdateMemberRole] DENIED: cannot assign role >= own role")
-		return ErrUnauthorized
+	if err := checkRoleChange(requesterRole, oldRole, newRole); err != nil {
+		slog.InfoContext(ctx, "update member role denied", "requesterRole", requesterRole, "error", err)
+		return err
 	}
 
 	// ✅ Prevent demoting the last owner
@@ -652,7 +763,7 @@ func (s *memberService) UpdateMemberRole(ctx context.Context, entityType, entity
 			return err
 		}
 		if owners <= 1 {
-			log.Printf("[UpdateMemberRole] DENIED: cannot demote last owner")
+			slog.InfoContext(ctx, "update member role denied: last owner", "entityType", entityType, "entityID", entityID)
 			return ErrLastOwner
 		}
 	}
@@ -678,6 +789,8 @@ func (s *memberService) UpdateMemberRole(ctx context.Context, entityType, entity
 	if updateErr != nil {
 		return updateErr
 	}
+	s.accessCache.InvalidateUser(ctx, userID)
+	s.readCache.InvalidateMemberLists(ctx)
 
 	// ✅ NEW: Send notification to updated user (unless they updated themselves)
 	if userID != requesterID {
@@ -839,19 +952,46 @@ func (s *memberService) ListDirectMembers(ctx context.Context, entityType, entit
 		return s.convertFolderMembers(members, entityID, false), nil
 
 	case EntityTypeProject:
-		members, err := s.projectRepo.FindMembers(ctx, entityID)
-		if err != nil {
-			return nil, err
-		}
-		return s.convertProjectMembers(members, entityID, false), nil
+		return s.cachedProjectMembers(ctx, entityID, false, func() ([]*UnifiedMember, error) {
+			members, err := s.projectRepo.FindMembers(ctx, entityID)
+			if err != nil {
+				return nil, err
+			}
+			return s.convertProjectMembers(members, entityID, false), nil
+		})
 
 	default:
 		return nil, ErrInvalidEntityType
 	}
 }
 
+// cachedProjectMembers serves a project member list from the read cache,
+// loading and storing it on a miss
+func (s *memberService) cachedProjectMembers(ctx context.Context, projectID string, effective bool, load func() ([]*UnifiedMember, error)) ([]*UnifiedMember, error) {
+	key := projectMembersCacheKey(projectID, effective)
+	var members []*UnifiedMember
+	if s.readCache.get(ctx, key, &members) {
+		return members, nil
+	}
+	members, err := load()
+	if err != nil {
+		return nil, err
+	}
+	s.readCache.put(ctx, key, members)
+	return members, nil
+}
+
 // ListEffectiveMembers returns direct + inherited members (from parent entities)
 func (s *memberService) ListEffectiveMembers(ctx context.Context, entityType, entityID string) ([]*UnifiedMember, error) {
+	if entityType == EntityTypeProject {
+		return s.cachedProjectMembers(ctx, entityID, true, func() ([]*UnifiedMember, error) {
+			return s.listEffectiveMembers(ctx, entityType, entityID)
+		})
+	}
+	return s.listEffectiveMembers(ctx, entityType, entityID)
+}
+
+func (s *memberService) listEffectiveMembers(ctx context.Context, entityType, entityID string) ([]*UnifiedMember, error) {
 	// Start with direct members
 	directMembers, err := s.ListDirectMembers(ctx, entityType, entityID)
 	if err != nil {
@@ -1051,6 +1191,32 @@ func (s *memberService) HasDirectAccess(ctx context.Context, entityType, entityI
 // HasEffectiveAccess checks if user has access (direct or inherited)
 // Returns: (hasAccess, inheritedFrom, error)
 func (s *memberService) HasEffectiveAccess(ctx context.Context, entityType, entityID, userID string) (bool, string, error) {
+	if inheritedFrom, ok := s.accessCache.get(ctx, userID, entityType, entityID); ok {
+		return true, inheritedFrom, nil
+	}
+	hasAccess, inheritedFrom, err := s.computeEffectiveAccess(ctx, entityType, entityID, userID)
+	if err == nil && hasAccess {
+		s.accessCache.put(ctx, userID, entityType, entityID, inheritedFrom)
+	}
+	return hasAccess, inheritedFrom, err
+}
+
+// FilterAccessibleProjects checks each distinct project once, so callers can
+// filter a task list without a hierarchy walk per task
+func (s *memberService) FilterAccessibleProjects(ctx context.Context, userID string, projectIDs []string) map[string]bool {
+	access := make(map[string]bool, len(projectIDs))
+	for _, projectID := range projectIDs {
+		if _, checked := access[projectID]; checked {
+			continue
+		}
+		hasAccess, _, err := s.HasEffectiveAccess(ctx, EntityTypeProject, projectID, userID)
+		access[projectID] = err == nil && hasAccess
+	}
+	return access
+}
+
+// computeEffectiveAccess walks up the hierarchy for HasEffectiveAccess
+func (s *memberService) computeEffectiveAccess(ctx context.Context, entityType, entityID, userID string) (bool, string, error) {
 	// Check direct access first
 	hasDirect, err := s.HasDirectAccess(ctx, entityType, entityID, userID)
 	if err != nil {
@@ -1387,18 +1553,82 @@ func (s *memberService) GetUserAllAccess(ctx context.Context, userID string) (*U
 		return nil, err
 	}
 
-	accessMap.Workspaces = memberships[EntityTypeWorkspace]
-	accessMap.Spaces = memberships[EntityTypeSpace]
-	accessMap.Folders = memberships[EntityTypeFolder]
-	accessMap.Projects = memberships[EntityTypeProject]
+	// Inherited access, matching HasEffectiveAccess: workspace members reach
+	// every space of the workspace, space members every folder and project of
+	// the space, folder members every project of the folder. Descendants are
+	// loaded once per container rather than checked entity by entity.
+	spaces := newIDSet(memberships[EntityTypeSpace])
+	folders := newIDSet(memberships[EntityTypeFolder])
+	projects := newIDSet(memberships[EntityTypeProject])
+
+	for _, workspaceID := range memberships[EntityTypeWorkspace] {
+		workspaceSpaces, err := s.spaceRepo.FindByWorkspaceID(ctx, workspaceID)
+		if err != nil {
+			return nil, err
+		}
+		for _, space := range workspaceSpaces {
+			spaces.add(space.ID)
+		}
+	}
+
+	for _, spaceID := range spaces.ids {
+		spaceFolders, err := s.folderRepo.FindBySpaceID(ctx, spaceID)
+		if err != nil {
+			return nil, err
+		}
+		for _, folder := range spaceFolders {
+			folders.add(folder.ID)
+		}
+		// Includes the projects inside the space's folders
+		spaceProjects, err := s.projectRepo.FindBySpaceID(ctx, spaceID)
+		if err != nil {
+			return nil, err
+		}
+		for _, project := range spaceProjects {
+			projects.add(project.ID)
+		}
+	}
 
-	// TODO: Add inherited access (spaces from workspace membership, etc.)
-	// This would require iterating through all entities which could be expensive
-	// Better to check access on-demand when needed
+	// Folders reached through a space already had their projects added above
+	for _, folderID := range memberships[EntityTypeFolder] {
+		folderProjects, err := s.projectRepo.FindByFolderID(ctx, folderID)
+		if err != nil {
+			return nil, err
+		}
+		for _, project := range folderProjects {
+			projects.add(project.ID)
+		}
+	}
+
+	accessMap.Workspaces = newIDSet(memberships[EntityTypeWorkspace]).ids
+	accessMap.Spaces = spaces.ids
+	accessMap.Folders = folders.ids
+	accessMap.Projects = projects.ids
 
 	return accessMap, nil
 }
 
+// idSet collects IDs without duplicates, keeping first-seen order
+type idSet struct {
+	ids  []string
+	seen map[string]bool
+}
+
+func newIDSet(ids []string) *idSet {
+	set := &idSet{ids: []string{}, seen: make(map[string]bool)}
+	for _, id := range ids {
+		set.add(id)
+	}
+	return set
+}
+
+func (s *idSet) add(id string) {
+	if !s.seen[id] {
+		s.seen[id] = true
+		s.ids = append(s.ids, id)
+	}
+}
+
 // ============================================
 // ✅ UPDATED: ACCESSIBLE ENTITIES (CONTENT ACCESS)
 // ============================================
@@ -2,8 +2,13 @@ package service
 
 import (
 	"context"
+	"fmt"
+	"log/slog"
 
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/models"
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/notification"
 	"github.com/Marga-Ghale/ora-scrum-backend/internal/repository"
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/socket"
 )
 
 // ============================================
@@ -11,24 +16,43 @@ import (
 // ============================================
 
 type NotificationService interface {
-	List(ctx context.Context, userID string, unreadOnly bool) ([]*repository.Notification, error)
+	// List returns a page of notifications, the number matching the filter and the user's unread total
+	List(ctx context.Context, userID string, filter repository.NotificationFilter) ([]*repository.Notification, int, int, error)
 	Count(ctx context.Context, userID string) (total int, unread int, err error)
 	MarkAsRead(ctx context.Context, id string) error
+	// MarkAsUnread flags one of the user's notifications as unread again
+	MarkAsUnread(ctx context.Context, userID, id string) error
 	MarkAllAsRead(ctx context.Context, userID string) error
 	Delete(ctx context.Context, id string) error
 	DeleteAll(ctx context.Context, userID string) error
+	// GetPreferences returns the effective setting of every notification type
+	GetPreferences(ctx context.Context, userID string) ([]models.NotificationPreferenceResponse, error)
+	UpdatePreferences(ctx context.Context, userID string, updates []models.NotificationPreferenceUpdate) ([]models.NotificationPreferenceResponse, error)
 }
 
 type notificationService struct {
 	notificationRepo repository.NotificationRepository
+	preferenceRepo   repository.NotificationPreferenceRepository
+	broadcaster      *socket.Broadcaster
 }
 
-func NewNotificationService(notificationRepo repository.NotificationRepository) NotificationService {
-	return &notificationService{notificationRepo: notificationRepo}
+func NewNotificationService(notificationRepo repository.NotificationRepository, preferenceRepo repository.NotificationPreferenceRepository, broadcaster *socket.Broadcaster) NotificationService {
+	return &notificationService{notificationRepo: notificationRepo, preferenceRepo: preferenceRepo, broadcaster: broadcaster}
 }
 
-func (s *notificationService) List(ctx context.Context, userID string, unreadOnly bool) ([]*repository.Notification, error) {
-	return s.notificationRepo.FindByUserID(ctx, userID, unreadOnly)
+func (s *notificationService) List(ctx context.Context, userID string, filter repository.NotificationFilter) ([]*repository.Notification, int, int, error) {
+	if filter.Before != nil && filter.After != nil && !filter.After.Before(*filter.Before) {
+		return nil, 0, 0, fmt.Errorf("%w: after must be earlier than before", ErrInvalidInput)
+	}
+	notifications, total, err := s.notificationRepo.FindByFilter(ctx, userID, filter)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	_, unread, err := s.notificationRepo.CountByUserID(ctx, userID)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	return notifications, total, unread, nil
 }
 
 func (s *notificationService) Count(ctx context.Context, userID string) (total int, unread int, err error) {
@@ -36,11 +60,55 @@ func (s *notificationService) Count(ctx context.Context, userID string) (total i
 }
 
 func (s *notificationService) MarkAsRead(ctx context.Context, id string) error {
-	return s.notificationRepo.MarkAsRead(ctx, id)
+	if err := s.notificationRepo.MarkAsRead(ctx, id); err != nil {
+		return err
+	}
+
+	if s.broadcaster != nil {
+		if n, err := s.notificationRepo.FindByID(ctx, id); err == nil && n != nil {
+			s.sendUnreadCount(ctx, n.UserID)
+		}
+	}
+	return nil
+}
+
+func (s *notificationService) MarkAsUnread(ctx context.Context, userID, id string) error {
+	n, err := s.notificationRepo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if n == nil || n.UserID != userID {
+		return ErrNotFound
+	}
+	if err := s.notificationRepo.MarkAsUnread(ctx, id); err != nil {
+		return err
+	}
+
+	if s.broadcaster != nil {
+		s.sendUnreadCount(ctx, userID)
+	}
+	return nil
 }
 
+// MarkAllAsRead sends a single zero-count update instead of one per notification
 func (s *notificationService) MarkAllAsRead(ctx context.Context, userID string) error {
-	return s.notificationRepo.MarkAllAsRead(ctx, userID)
+	if err := s.notificationRepo.MarkAllAsRead(ctx, userID); err != nil {
+		return err
+	}
+
+	if s.broadcaster != nil {
+		s.broadcaster.SendNotificationUnread(userID, 0)
+	}
+	return nil
+}
+
+func (s *notificationService) sendUnreadCount(ctx context.Context, userID string) {
+	_, unread, err := s.notificationRepo.CountByUserID(ctx, userID)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to count unread notifications", "userID", userID, "error", err)
+		return
+	}
+	s.broadcaster.SendNotificationUnread(userID, unread)
 }
 
 func (s *notificationService) Delete(ctx context.Context, id string) error {
@@ -50,3 +118,54 @@ func (s *notificationService) Delete(ctx context.Context, id string) error {
 func (s *notificationService) DeleteAll(ctx context.Context, userID string) error {
 	return s.notificationRepo.DeleteAll(ctx, userID)
 }
+
+// GetPreferences fills in types the user never changed as fully enabled
+func (s *notificationService) GetPreferences(ctx context.Context, userID string) ([]models.NotificationPreferenceResponse, error) {
+	stored, err := s.preferenceRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	byType := make(map[string]*repository.NotificationPreference, len(stored))
+	for _, p := range stored {
+		byType[p.Type] = p
+	}
+
+	prefs := make([]models.NotificationPreferenceResponse, 0, len(notification.Types))
+	for _, t := range notification.Types {
+		pref := models.NotificationPreferenceResponse{Type: t, InApp: true, Email: true}
+		if p, ok := byType[t]; ok {
+			pref.InApp, pref.Email = p.InApp, p.Email
+		}
+		prefs = append(prefs, pref)
+	}
+	return prefs, nil
+}
+
+// UpdatePreferences validates every type before saving any of them
+func (s *notificationService) UpdatePreferences(ctx context.Context, userID string, updates []models.NotificationPreferenceUpdate) ([]models.NotificationPreferenceResponse, error) {
+	for _, u := range updates {
+		if !notification.IsValidType(u.Type) {
+			return nil, fmt.Errorf("%w: unknown notification type %q", ErrInvalidInput, u.Type)
+		}
+	}
+
+	for _, u := range updates {
+		pref, err := s.preferenceRepo.Find(ctx, userID, u.Type)
+		if err != nil {
+			return nil, err
+		}
+		if pref == nil {
+			pref = &repository.NotificationPreference{UserID: userID, Type: u.Type, InApp: true, Email: true}
+		}
+		if u.InApp != nil {
+			pref.InApp = *u.InApp
+		}
+		if u.Email != nil {
+			pref.Email = *u.Email
+		}
+		if err := s.preferenceRepo.Upsert(ctx, pref); err != nil {
+			return nil, err
+		}
+	}
+	return s.GetPreferences(ctx, userID)
+}
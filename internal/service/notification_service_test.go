@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/repository"
+)
+
+type memNotificationRepo struct {
+	repository.NotificationRepository
+	notifications map[string]*repository.Notification
+}
+
+func (r *memNotificationRepo) FindByID(_ context.Context, id string) (*repository.Notification, error) {
+	n, ok := r.notifications[id]
+	if !ok {
+		return nil, nil
+	}
+	copied := *n
+	return &copied, nil
+}
+
+func (r *memNotificationRepo) MarkAsRead(_ context.Context, id string) error {
+	r.notifications[id].Read = true
+	return nil
+}
+
+func (r *memNotificationRepo) MarkAsUnread(_ context.Context, id string) error {
+	r.notifications[id].Read = false
+	return nil
+}
+
+func (r *memNotificationRepo) CountByUserID(_ context.Context, userID string) (int, int, error) {
+	total, unread := 0, 0
+	for _, n := range r.notifications {
+		if n.UserID != userID {
+			continue
+		}
+		total++
+		if !n.Read {
+			unread++
+		}
+	}
+	return total, unread, nil
+}
+
+func TestMarkAsUnreadFlipsReadAndCount(t *testing.T) {
+	repo := &memNotificationRepo{notifications: map[string]*repository.Notification{
+		"n1": {ID: "n1", UserID: "user-1", Read: true},
+		"n2": {ID: "n2", UserID: "user-1", Read: false},
+	}}
+	svc := NewNotificationService(repo, nil, nil)
+	ctx := context.Background()
+
+	if _, unread, _ := svc.Count(ctx, "user-1"); unread != 1 {
+		t.Fatalf("unread before = %d, want 1", unread)
+	}
+	if err := svc.MarkAsUnread(ctx, "user-1", "n1"); err != nil {
+		t.Fatalf("MarkAsUnread: %v", err)
+	}
+	if repo.notifications["n1"].Read {
+		t.Fatal("n1 is still read")
+	}
+	if _, unread, _ := svc.Count(ctx, "user-1"); unread != 2 {
+		t.Fatalf("unread after = %d, want 2", unread)
+	}
+}
+
+func TestMarkAsUnreadRejectsOtherUsersNotification(t *testing.T) {
+	repo := &memNotificationRepo{notifications: map[string]*repository.Notification{
+		"n1": {ID: "n1", UserID: "user-1", Read: true},
+	}}
+	svc := NewNotificationService(repo, nil, nil)
+
+	err := svc.MarkAsUnread(context.Background(), "user-2", "n1")
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("MarkAsUnread error = %v, want ErrNotFound", err)
+	}
+	if !repo.notifications["n1"].Read {
+		t.Error("another user's notification was marked unread")
+	}
+}
@@ -19,11 +19,12 @@ const (
 )
 
 const (
-	ActionView   = "view"
-	ActionEdit   = "edit"
-	ActionDelete = "delete"
-	ActionManage = "manage"
-	ActionCreate = "create"
+	ActionView    = "view"
+	ActionEdit    = "edit"
+	ActionDelete  = "delete"
+	ActionManage  = "manage"
+	ActionCreate  = "create"
+	ActionComment = "comment"
 )
 
 type PermissionService interface {
@@ -46,12 +47,18 @@ type PermissionService interface {
 	CanAccessProject(ctx context.Context, userID, projectID string) bool
 	CanManageProject(ctx context.Context, userID, projectID string) bool
 	CanEditProject(ctx context.Context, userID, projectID string) bool
+	CanViewReports(ctx context.Context, userID, projectID string) bool
+	CanCreateViews(ctx context.Context, userID, projectID string) bool
+	CanExport(ctx context.Context, userID, projectID string) bool
+	CanCreateTask(ctx context.Context, userID, projectID string) bool
 	GetProjectRole(ctx context.Context, userID, projectID string) string
 
-	// Task permissions
+	// Task permissions. Members below lead who joined through an invitation
+	// with explicit permissions are limited to what that invitation grants.
 	CanAccessTask(ctx context.Context, userID, taskID string) bool
 	CanEditTask(ctx context.Context, userID, taskID string) bool
 	CanDeleteTask(ctx context.Context, userID, taskID string) bool
+	CanCommentOnTask(ctx context.Context, userID, taskID string) bool
 
 	// Team permissions
 	CanAccessTeam(ctx context.Context, userID, teamID string) bool
@@ -68,6 +75,7 @@ type permissionService struct {
 	taskRepo      repository.TaskRepository
 	teamRepo      repository.TeamRepository
 	folderRepo    repository.FolderRepository
+	invRepo       repository.InvitationRepository
 	memberService MemberService // ✅ ADD THIS
 }
 
@@ -78,6 +86,7 @@ func NewPermissionService(
 	taskRepo repository.TaskRepository,
 	teamRepo repository.TeamRepository,
 	folderRepo repository.FolderRepository,
+	invRepo repository.InvitationRepository,
 	memberService MemberService, // ✅ ADD THIS PARAMETER
 ) PermissionService {
 	return &permissionService{
@@ -87,6 +96,7 @@ func NewPermissionService(
 		taskRepo:      taskRepo,
 		teamRepo:      teamRepo,
 		folderRepo:    folderRepo,
+		invRepo:       invRepo,
 		memberService: memberService, // ✅ ADD THIS
 	}
 }
@@ -249,6 +259,97 @@ func (s *permissionService) CanEditProject(ctx context.Context, userID, projectI
 	return hasMinimumRole(role, PermissionMember)
 }
 
+// CanViewReports allows leads and admins to see team-wide reports such as time spent
+func (s *permissionService) CanViewReports(ctx context.Context, userID, projectID string) bool {
+	role := s.GetProjectRole(ctx, userID, projectID)
+	return hasMinimumRole(role, PermissionLead)
+}
+
+// CanCreateViews allows members to share views with the project unless the
+// invitation they joined through withheld it; leads and above always can
+func (s *permissionService) CanCreateViews(ctx context.Context, userID, projectID string) bool {
+	role := s.GetProjectRole(ctx, userID, projectID)
+	if !hasMinimumRole(role, PermissionMember) {
+		return false
+	}
+	if hasMinimumRole(role, PermissionLead) {
+		return true
+	}
+
+	perms, err := s.invitationPermissions(ctx, userID, projectID)
+	if err != nil {
+		return false
+	}
+	return perms == nil || perms.CanCreateViews
+}
+
+// CanExport allows anyone who can see the project to export its tasks unless
+// the invitation they joined through withheld it; leads and above always can
+func (s *permissionService) CanExport(ctx context.Context, userID, projectID string) bool {
+	if !s.CanAccessProject(ctx, userID, projectID) {
+		return false
+	}
+	perms, err := s.taskLimits(ctx, userID, projectID)
+	if err != nil {
+		return false
+	}
+	return perms == nil || perms.CanExport
+}
+
+// CanCreateTask allows members to add tasks to the project unless the
+// invitation they joined through withheld it
+func (s *permissionService) CanCreateTask(ctx context.Context, userID, projectID string) bool {
+	limits, err := s.taskLimits(ctx, userID, projectID)
+	if err != nil {
+		return false
+	}
+	if limits != nil {
+		return limits.CanCreateTasks && s.CanAccessProject(ctx, userID, projectID)
+	}
+	return s.CanEditProject(ctx, userID, projectID)
+}
+
+// invitationPermissions returns the permissions of the accepted invitation
+// that let the user into the project, looking at the project and then its
+// folder, space and workspace. Nil means the user is not limited by one.
+func (s *permissionService) invitationPermissions(ctx context.Context, userID, projectID string) (*repository.InvitationPermissions, error) {
+	project, err := s.projectRepo.FindByID(ctx, projectID)
+	if err != nil || project == nil {
+		return nil, err
+	}
+
+	type target struct {
+		kind repository.InvitationType
+		id   string
+	}
+	targets := []target{{repository.InvitationTypeProject, project.ID}}
+	if project.FolderID != nil {
+		targets = append(targets, target{repository.InvitationTypeFolder, *project.FolderID})
+	}
+	targets = append(targets, target{repository.InvitationTypeSpace, project.SpaceID})
+	if space, _ := s.spaceRepo.FindByID(ctx, project.SpaceID); space != nil {
+		targets = append(targets, target{repository.InvitationTypeWorkspace, space.WorkspaceID})
+	}
+
+	for _, t := range targets {
+		perms, err := s.invRepo.FindAcceptedPermissions(ctx, userID, t.kind, t.id)
+		if err != nil || perms != nil {
+			return perms, err
+		}
+	}
+	return nil, nil
+}
+
+// taskLimits returns the invitation permissions that restrict what the user
+// may do with the project's tasks, or nil when role defaults apply. Leads
+// and above are never limited.
+func (s *permissionService) taskLimits(ctx context.Context, userID, projectID string) (*repository.InvitationPermissions, error) {
+	if hasMinimumRole(s.GetProjectRole(ctx, userID, projectID), PermissionLead) {
+		return nil, nil
+	}
+	return s.invitationPermissions(ctx, userID, projectID)
+}
+
 func (s *permissionService) GetProjectRole(ctx context.Context, userID, projectID string) string {
 	// Check direct project membership
 	member, err := s.projectRepo.FindMember(ctx, projectID, userID)
@@ -291,6 +392,14 @@ func (s *permissionService) CanEditTask(ctx context.Context, userID, taskID stri
 		return false
 	}
 
+	limits, err := s.taskLimits(ctx, userID, task.ProjectID)
+	if err != nil {
+		return false
+	}
+	if limits != nil {
+		return limits.CanEditTasks && s.CanAccessProject(ctx, userID, task.ProjectID)
+	}
+
 	// Check if user is one of the assignees
 	for _, assigneeID := range task.AssigneeIDs {
 		if assigneeID == userID {
@@ -313,6 +422,14 @@ func (s *permissionService) CanDeleteTask(ctx context.Context, userID, taskID st
 		return false
 	}
 
+	limits, err := s.taskLimits(ctx, userID, task.ProjectID)
+	if err != nil {
+		return false
+	}
+	if limits != nil {
+		return limits.CanDeleteTasks && s.CanAccessProject(ctx, userID, task.ProjectID)
+	}
+
 	// Creator can delete
 	if task.CreatedBy != nil && *task.CreatedBy == userID {
 		return true
@@ -322,6 +439,24 @@ func (s *permissionService) CanDeleteTask(ctx context.Context, userID, taskID st
 	return s.CanManageProject(ctx, userID, task.ProjectID)
 }
 
+// CanCommentOnTask allows anyone who can see the task to comment, unless
+// their invitation withheld commenting
+func (s *permissionService) CanCommentOnTask(ctx context.Context, userID, taskID string) bool {
+	task, err := s.taskRepo.FindByID(ctx, taskID)
+	if err != nil || task == nil {
+		return false
+	}
+	if !s.CanAccessProject(ctx, userID, task.ProjectID) {
+		return false
+	}
+
+	limits, err := s.taskLimits(ctx, userID, task.ProjectID)
+	if err != nil {
+		return false
+	}
+	return limits == nil || limits.CanComment
+}
+
 // ============================================
 // Team Permissions
 // ============================================
@@ -405,6 +540,8 @@ func (s *permissionService) CheckPermission(ctx context.Context, userID, entityT
 			return s.CanEditTask(ctx, userID, entityID)
 		case ActionDelete:
 			return s.CanDeleteTask(ctx, userID, entityID)
+		case ActionComment:
+			return s.CanCommentOnTask(ctx, userID, entityID)
 		}
 
 	case "team":
@@ -2,6 +2,9 @@ package service
 
 import (
 	"context"
+	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/Marga-Ghale/ora-scrum-backend/internal/repository"
 	"github.com/Marga-Ghale/ora-scrum-backend/internal/socket"
@@ -16,6 +19,8 @@ type ProjectService interface {
 	Create(ctx context.Context, spaceID string, folderID *string, creatorID, name, key string, description, icon, color, leadID *string) (*repository.Project, error)
 	GetByID(ctx context.Context, id string) (*repository.Project, error)
 	GetByKey(ctx context.Context, spaceID, key string) (*repository.Project, error)
+	IsKeyAvailable(ctx context.Context, key string) (bool, error)
+	SuggestKey(ctx context.Context, name string) (string, error)
 	ListBySpace(ctx context.Context, spaceID string) ([]*repository.Project, error)
 	ListByFolder(ctx context.Context, folderID string) ([]*repository.Project, error)
 	Update(ctx context.Context, id string, name, key, description, icon, color, leadID *string, folderID *string) (*repository.Project, error)
@@ -25,14 +30,39 @@ type ProjectService interface {
 	MoveToFolder(ctx context.Context, projectID string, folderID *string) error
 	SetLead(ctx context.Context, projectID, leadID string) error
 	UpdateVisibility(ctx context.Context, projectID, visibility string, allowedUsers, allowedTeams []string) error
+
+	// Stats
+	GetStats(ctx context.Context, projectID, userID string) (*repository.ProjectStats, error)
+
+	// Templates
+	SaveAsTemplate(ctx context.Context, projectID, userID, name string, description *string) (*repository.ProjectTemplate, error)
+	ListTemplates(ctx context.Context, workspaceID, userID string) ([]*repository.ProjectTemplate, error)
+	DeleteTemplate(ctx context.Context, templateID, userID string) error
+	CreateFromTemplate(ctx context.Context, templateID, targetSpaceID, userID, name, key string) (*repository.Project, error)
 }
 
+const (
+	minProjectKeyLength = 2
+	maxProjectKeyLength = 10 // projects.key is VARCHAR(10)
+	maxKeySuggestions   = 100
+)
+
 type projectService struct {
 	projectRepo   repository.ProjectRepository
 	spaceRepo     repository.SpaceRepository
 	folderRepo    repository.FolderRepository
 	memberService MemberService
 	broadcaster   *socket.Broadcaster // ✅ NEW: Added broadcaster
+
+	// Used to capture and replay project templates
+	templateRepo  repository.TemplateRepository
+	statusRepo    repository.ProjectStatusRepository
+	labelRepo     repository.LabelRepository
+	taskRepo      repository.TaskRepository
+	taskLabelRepo repository.TaskLabelRepository
+	checklistRepo repository.TaskChecklistRepository
+
+	readCache *ReadCache
 }
 
 func NewProjectService(
@@ -41,7 +71,13 @@ func NewProjectService(
 	folderRepo repository.FolderRepository,
 	memberService MemberService,
 		broadcaster   *socket.Broadcaster, // ✅ NEW: Added broadcaster
-
+	templateRepo repository.TemplateRepository,
+	statusRepo repository.ProjectStatusRepository,
+	labelRepo repository.LabelRepository,
+	taskRepo repository.TaskRepository,
+	taskLabelRepo repository.TaskLabelRepository,
+	checklistRepo repository.TaskChecklistRepository,
+	readCache *ReadCache, // may be nil
 ) ProjectService {
 	return &projectService{
 		projectRepo:   projectRepo,
@@ -49,6 +85,13 @@ func NewProjectService(
 		folderRepo:    folderRepo,
 		memberService: memberService,
 			broadcaster:   broadcaster,
+		templateRepo:  templateRepo,
+		statusRepo:    statusRepo,
+		labelRepo:     labelRepo,
+		taskRepo:      taskRepo,
+		taskLabelRepo: taskLabelRepo,
+		checklistRepo: checklistRepo,
+		readCache:     readCache,
 	}
 }
 
@@ -61,7 +104,7 @@ func (s *projectService) Create(ctx context.Context, spaceID string, folderID *s
 	// Verify space exists
 	space, err := s.spaceRepo.FindByID(ctx, spaceID)
 	if err != nil || space == nil {
-		return nil, ErrNotFound
+		return nil, ErrSpaceNotFound
 	}
 
 	// Verify folder exists if provided
@@ -71,7 +114,7 @@ func (s *projectService) Create(ctx context.Context, spaceID string, folderID *s
 			return nil, err
 		}
 		if folder == nil {
-			return nil, ErrNotFound
+			return nil, ErrFolderNotFound
 		}
 		// Verify folder belongs to the same space
 		if folder.SpaceID != spaceID {
@@ -79,10 +122,22 @@ func (s *projectService) Create(ctx context.Context, spaceID string, folderID *s
 		}
 	}
 
-	// Check if key already exists in this space
-	projects, _ := s.projectRepo.FindBySpaceID(ctx, spaceID)
-	for _, p := range projects {
-		if p.Key == key {
+	// Keys are unique across all spaces; derive one from the name when omitted
+	key = normalizeProjectKey(key)
+	if key == "" {
+		key, err = s.SuggestKey(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		if !validProjectKey(key) {
+			return nil, fmt.Errorf("%w: project key must be %d-%d letters or digits starting with a letter", ErrInvalidInput, minProjectKeyLength, maxProjectKeyLength)
+		}
+		existing, err := s.projectRepo.FindByKey(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		if existing != nil {
 			return nil, ErrConflict
 		}
 	}
@@ -148,24 +203,58 @@ func (s *projectService) GetByID(ctx context.Context, id string) (*repository.Pr
 		return nil, err
 	}
 	if project == nil {
-		return nil, ErrNotFound
+		return nil, ErrProjectNotFound
 	}
 	return project, nil
 }
 
 func (s *projectService) GetByKey(ctx context.Context, spaceID, key string) (*repository.Project, error) {
-	projects, err := s.projectRepo.FindBySpaceID(ctx, spaceID)
+	project, err := s.projectRepo.FindByKey(ctx, key)
 	if err != nil {
 		return nil, err
 	}
+	if project == nil || project.SpaceID != spaceID {
+		return nil, ErrProjectNotFound
+	}
+	return project, nil
+}
 
-	for _, p := range projects {
-		if p.Key == key {
-			return p, nil
-		}
+// IsKeyAvailable reports whether no project uses the key yet
+func (s *projectService) IsKeyAvailable(ctx context.Context, key string) (bool, error) {
+	key = normalizeProjectKey(key)
+	if !validProjectKey(key) {
+		return false, fmt.Errorf("%w: project key must be %d-%d letters or digits starting with a letter", ErrInvalidInput, minProjectKeyLength, maxProjectKeyLength)
 	}
+	existing, err := s.projectRepo.FindByKey(ctx, key)
+	if err != nil {
+		return false, err
+	}
+	return existing == nil, nil
+}
 
-	return nil, ErrNotFound
+// SuggestKey derives a 2-4 letter key from the project name (initials of a
+// multi-word name, otherwise the name's first letters) and appends a number
+// while the key is taken: "Mobile App" -> "MA", "MA2", "MA3", ...
+func (s *projectService) SuggestKey(ctx context.Context, name string) (string, error) {
+	base := projectKeyBase(name)
+	for n := 1; n <= maxKeySuggestions; n++ {
+		candidate := base
+		if n > 1 {
+			suffix := strconv.Itoa(n)
+			if len(base)+len(suffix) > maxProjectKeyLength {
+				candidate = base[:maxProjectKeyLength-len(suffix)]
+			}
+			candidate += suffix
+		}
+		existing, err := s.projectRepo.FindByKey(ctx, candidate)
+		if err != nil {
+			return "", err
+		}
+		if existing == nil {
+			return candidate, nil
+		}
+	}
+	return "", ErrConflict
 }
 
 func (s *projectService) ListBySpace(ctx context.Context, spaceID string) ([]*repository.Project, error) {
@@ -179,7 +268,7 @@ func (s *projectService) ListByFolder(ctx context.Context, folderID string) ([]*
 func (s *projectService) Update(ctx context.Context, id string, name, key, description, icon, color, leadID *string, folderID *string) (*repository.Project, error) {
 	project, err := s.projectRepo.FindByID(ctx, id)
 	if err != nil || project == nil {
-		return nil, ErrNotFound
+		return nil, ErrProjectNotFound
 	}
 
 	// Update name if provided
@@ -187,15 +276,20 @@ func (s *projectService) Update(ctx context.Context, id string, name, key, descr
 		project.Name = *name
 	}
 
-	// Update key if provided (check uniqueness in space)
-	if key != nil && *key != project.Key {
-		projects, _ := s.projectRepo.FindBySpaceID(ctx, project.SpaceID)
-		for _, p := range projects {
-			if p.Key == *key && p.ID != id {
-				return nil, ErrConflict
-			}
+	// Update key if provided (keys are unique across all spaces)
+	if key != nil && normalizeProjectKey(*key) != project.Key {
+		newKey := normalizeProjectKey(*key)
+		if !validProjectKey(newKey) {
+			return nil, fmt.Errorf("%w: project key must be %d-%d letters or digits starting with a letter", ErrInvalidInput, minProjectKeyLength, maxProjectKeyLength)
 		}
-		project.Key = *key
+		existing, err := s.projectRepo.FindByKey(ctx, newKey)
+		if err != nil {
+			return nil, err
+		}
+		if existing != nil && existing.ID != id {
+			return nil, ErrConflict
+		}
+		project.Key = newKey
 	}
 
 	// Update folder if provided (verify it belongs to same space)
@@ -203,7 +297,7 @@ func (s *projectService) Update(ctx context.Context, id string, name, key, descr
 		if *folderID != "" {
 			folder, err := s.folderRepo.FindByID(ctx, *folderID)
 			if err != nil || folder == nil {
-				return nil, ErrNotFound
+				return nil, ErrFolderNotFound
 			}
 			if folder.SpaceID != project.SpaceID {
 				return nil, ErrInvalidInput
@@ -249,7 +343,7 @@ func (s *projectService) Delete(ctx context.Context, id string) error {
 	// ✅ Get project first to know space ID for broadcasting
 	project, err := s.projectRepo.FindByID(ctx, id)
 	if err != nil || project == nil {
-		return ErrNotFound
+		return ErrProjectNotFound
 	}
 
 	spaceID := project.SpaceID
@@ -273,14 +367,14 @@ func (s *projectService) Delete(ctx context.Context, id string) error {
 func (s *projectService) MoveToFolder(ctx context.Context, projectID string, folderID *string) error {
 	project, err := s.projectRepo.FindByID(ctx, projectID)
 	if err != nil || project == nil {
-		return ErrNotFound
+		return ErrProjectNotFound
 	}
 
 	// If moving to a folder, verify it exists and belongs to same space
 	if folderID != nil && *folderID != "" {
 		folder, err := s.folderRepo.FindByID(ctx, *folderID)
 		if err != nil || folder == nil {
-			return ErrNotFound
+			return ErrFolderNotFound
 		}
 		if folder.SpaceID != project.SpaceID {
 			return ErrInvalidInput
@@ -314,7 +408,7 @@ func (s *projectService) MoveToFolder(ctx context.Context, projectID string, fol
 func (s *projectService) SetLead(ctx context.Context, projectID, leadID string) error {
 	project, err := s.projectRepo.FindByID(ctx, projectID)
 	if err != nil || project == nil {
-		return ErrNotFound
+		return ErrProjectNotFound
 	}
 
 	// Verify user is a member of the project
@@ -350,7 +444,7 @@ func (s *projectService) SetLead(ctx context.Context, projectID, leadID string)
 func (s *projectService) UpdateVisibility(ctx context.Context, projectID, visibility string, allowedUsers, allowedTeams []string) error {
 	project, err := s.projectRepo.FindByID(ctx, projectID)
 	if err != nil || project == nil {
-		return ErrNotFound
+		return ErrProjectNotFound
 	}
 
 	project.Visibility = &visibility
@@ -377,4 +471,388 @@ func (s *projectService) UpdateVisibility(ctx context.Context, projectID, visibi
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+
+// GetStats returns task aggregates for the project
+func (s *projectService) GetStats(ctx context.Context, projectID, userID string) (*repository.ProjectStats, error) {
+	hasAccess, _, err := s.memberService.HasEffectiveAccess(ctx, EntityTypeProject, projectID, userID)
+	if err != nil || !hasAccess {
+		return nil, ErrUnauthorized
+	}
+
+	project, err := s.projectRepo.FindByID(ctx, projectID)
+	if err != nil || project == nil {
+		return nil, ErrProjectNotFound
+	}
+
+	stats := &repository.ProjectStats{}
+	if s.readCache.get(ctx, projectStatsCacheKey(projectID), stats) {
+		return stats, nil
+	}
+	stats, err = s.projectRepo.GetStats(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	s.readCache.put(ctx, projectStatsCacheKey(projectID), stats)
+	return stats, nil
+}
+
+func normalizeProjectKey(key string) string {
+	return strings.ToUpper(strings.TrimSpace(key))
+}
+
+func validProjectKey(key string) bool {
+	if len(key) < minProjectKeyLength || len(key) > maxProjectKeyLength {
+		return false
+	}
+	for i, r := range key {
+		isLetter := r >= 'A' && r <= 'Z'
+		isDigit := r >= '0' && r <= '9'
+		if !isLetter && !(isDigit && i > 0) {
+			return false
+		}
+	}
+	return true
+}
+
+// projectKeyBase builds the 2-4 letter stem for a suggested key
+func projectKeyBase(name string) string {
+	words := strings.FieldsFunc(strings.ToUpper(name), func(r rune) bool {
+		return !(r >= 'A' && r <= 'Z') && !(r >= '0' && r <= '9')
+	})
+
+	var letters []string
+	for _, w := range words {
+		if w[0] >= 'A' && w[0] <= 'Z' {
+			letters = append(letters, w)
+		}
+	}
+
+	var base string
+	if len(letters) > 1 {
+		for _, w := range letters {
+			if len(base) == 4 {
+				break
+			}
+			base += w[:1]
+		}
+	} else if len(letters) == 1 {
+		base = letters[0]
+		if len(base) > 4 {
+			base = base[:4]
+		}
+	}
+
+	// Pad short stems ("X", "" for names without letters) to the minimum length
+	if len(base) < minProjectKeyLength {
+		base += strings.Repeat("P", minProjectKeyLength-len(base))
+	}
+	return base
+}
+
+// ============================================
+// Templates
+// ============================================
+
+// SaveAsTemplate captures the project's statuses, labels, tasks and checklists
+// as a template in the project's workspace. Assignees, sprints, dates and
+// progress are left out so the template describes structure only.
+func (s *projectService) SaveAsTemplate(ctx context.Context, projectID, userID, name string, description *string) (*repository.ProjectTemplate, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, fmt.Errorf("%w: template name is required", ErrInvalidInput)
+	}
+
+	hasAccess, role, err := s.memberService.HasEffectiveAccess(ctx, EntityTypeProject, projectID, userID)
+	if err != nil || !hasAccess || !hasMinimumRole(normalizeRole(role), PermissionLead) {
+		return nil, ErrUnauthorized
+	}
+
+	project, err := s.projectRepo.FindByID(ctx, projectID)
+	if err != nil || project == nil {
+		return nil, ErrProjectNotFound
+	}
+	space, err := s.spaceRepo.FindByID(ctx, project.SpaceID)
+	if err != nil || space == nil {
+		return nil, ErrSpaceNotFound
+	}
+
+	definition, err := s.captureDefinition(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	template := &repository.ProjectTemplate{
+		WorkspaceID:     space.WorkspaceID,
+		Name:            name,
+		Description:     description,
+		Definition:      *definition,
+		SourceProjectID: &projectID,
+		CreatedBy:       &userID,
+	}
+	if err := s.templateRepo.Create(ctx, template); err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+// ListTemplates returns the templates saved in a workspace
+func (s *projectService) ListTemplates(ctx context.Context, workspaceID, userID string) ([]*repository.ProjectTemplate, error) {
+	hasAccess, _, err := s.memberService.HasEffectiveAccess(ctx, EntityTypeWorkspace, workspaceID, userID)
+	if err != nil || !hasAccess {
+		return nil, ErrUnauthorized
+	}
+
+	templates, err := s.templateRepo.FindByWorkspaceID(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	if templates == nil {
+		templates = []*repository.ProjectTemplate{}
+	}
+	return templates, nil
+}
+
+// DeleteTemplate removes a template; allowed for its creator and workspace admins
+func (s *projectService) DeleteTemplate(ctx context.Context, templateID, userID string) error {
+	template, err := s.templateRepo.FindByID(ctx, templateID)
+	if err != nil {
+		return err
+	}
+	if template == nil {
+		return ErrNotFound
+	}
+
+	if template.CreatedBy == nil || *template.CreatedBy != userID {
+		hasAccess, role, err := s.memberService.HasEffectiveAccess(ctx, EntityTypeWorkspace, template.WorkspaceID, userID)
+		if err != nil || !hasAccess || !hasMinimumRole(normalizeRole(role), PermissionAdmin) {
+			return ErrUnauthorized
+		}
+	}
+
+	return s.templateRepo.Delete(ctx, templateID)
+}
+
+// CreateFromTemplate creates a project in the target space and provisions the
+// template's statuses, labels and seed tasks into it. If provisioning fails
+// the half-built project is deleted.
+func (s *projectService) CreateFromTemplate(ctx context.Context, templateID, targetSpaceID, userID, name, key string) (*repository.Project, error) {
+	template, err := s.templateRepo.FindByID(ctx, templateID)
+	if err != nil {
+		return nil, err
+	}
+	if template == nil {
+		return nil, ErrNotFound
+	}
+
+	space, err := s.spaceRepo.FindByID(ctx, targetSpaceID)
+	if err != nil || space == nil {
+		return nil, ErrSpaceNotFound
+	}
+	if space.WorkspaceID != template.WorkspaceID {
+		return nil, fmt.Errorf("%w: template belongs to another workspace", ErrInvalidInput)
+	}
+
+	hasAccess, _, err := s.memberService.HasEffectiveAccess(ctx, EntityTypeSpace, targetSpaceID, userID)
+	if err != nil || !hasAccess {
+		return nil, ErrUnauthorized
+	}
+
+	if strings.TrimSpace(name) == "" {
+		name = template.Name
+	}
+
+	project, err := s.Create(ctx, targetSpaceID, nil, userID, name, key, template.Description, nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.provisionFromTemplate(ctx, project.ID, userID, &template.Definition); err != nil {
+		s.projectRepo.Delete(ctx, project.ID)
+		return nil, err
+	}
+
+	return project, nil
+}
+
+// captureDefinition reads a project's structure into a template definition.
+// Tasks are listed parents first so provisioning can resolve parentRef.
+func (s *projectService) captureDefinition(ctx context.Context, projectID string) (*repository.TemplateDefinition, error) {
+	definition := &repository.TemplateDefinition{
+		Statuses: []repository.TemplateStatus{},
+		Labels:   []repository.TemplateLabel{},
+		Tasks:    []repository.TemplateTask{},
+	}
+
+	statuses, err := s.statusRepo.FindByProjectID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	for _, st := range statuses {
+		definition.Statuses = append(definition.Statuses, repository.TemplateStatus{
+			Key:                st.Key,
+			Name:               st.Name,
+			Color:              st.Color,
+			AllowedTransitions: st.AllowedTransitions,
+		})
+	}
+
+	labels, err := s.labelRepo.FindByProjectID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	for _, l := range labels {
+		definition.Labels = append(definition.Labels, repository.TemplateLabel{Name: l.Name, Color: l.Color})
+	}
+
+	tasks, err := s.taskRepo.FindByProjectID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Refs are positional so templates never carry IDs of the source project
+	refs := make(map[string]string, len(tasks))
+	for i, t := range tasks {
+		refs[t.ID] = strconv.Itoa(i + 1)
+	}
+
+	children := make(map[string][]*repository.Task)
+	var roots []*repository.Task
+	for _, t := range tasks {
+		if t.ParentTaskID != nil && refs[*t.ParentTaskID] != "" {
+			children[*t.ParentTaskID] = append(children[*t.ParentTaskID], t)
+		} else {
+			roots = append(roots, t)
+		}
+	}
+
+	var visit func(t *repository.Task) error
+	visit = func(t *repository.Task) error {
+		seed := repository.TemplateTask{
+			Ref:            refs[t.ID],
+			Title:          t.Title,
+			Description:    t.Description,
+			Status:         t.Status,
+			Priority:       t.Priority,
+			Type:           t.Type,
+			StoryPoints:    t.StoryPoints,
+			EstimatedHours: t.EstimatedHours,
+		}
+		if t.ParentTaskID != nil {
+			if ref, ok := refs[*t.ParentTaskID]; ok {
+				seed.ParentRef = &ref
+			}
+		}
+		for _, l := range t.Labels {
+			seed.Labels = append(seed.Labels, l.Name)
+		}
+
+		checklists, err := s.checklistRepo.FindByTaskID(ctx, t.ID)
+		if err != nil {
+			return err
+		}
+		for _, cl := range checklists {
+			items := make([]string, 0, len(cl.Items))
+			for _, item := range cl.Items {
+				items = append(items, item.Content)
+			}
+			seed.Checklists = append(seed.Checklists, repository.TemplateChecklist{Title: cl.Title, Items: items})
+		}
+
+		definition.Tasks = append(definition.Tasks, seed)
+		for _, child := range children[t.ID] {
+			if err := visit(child); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for _, t := range roots {
+		if err := visit(t); err != nil {
+			return nil, err
+		}
+	}
+
+	return definition, nil
+}
+
+// provisionFromTemplate copies a template definition into a project, giving
+// every seed task a fresh ID and re-linking subtasks through their refs
+func (s *projectService) provisionFromTemplate(ctx context.Context, projectID, userID string, definition *repository.TemplateDefinition) error {
+	for i, st := range definition.Statuses {
+		status := &repository.ProjectStatus{
+			ProjectID:          projectID,
+			Key:                st.Key,
+			Name:               st.Name,
+			Color:              st.Color,
+			Position:           i,
+			AllowedTransitions: st.AllowedTransitions,
+		}
+		if err := s.statusRepo.Create(ctx, status); err != nil {
+			return err
+		}
+	}
+
+	labelIDs := make(map[string]string, len(definition.Labels))
+	for _, l := range definition.Labels {
+		label := &repository.Label{ProjectID: projectID, Name: l.Name, Color: l.Color}
+		if err := s.labelRepo.Create(ctx, label); err != nil {
+			return err
+		}
+		labelIDs[l.Name] = label.ID
+	}
+
+	taskIDs := make(map[string]string, len(definition.Tasks))
+	for _, seed := range definition.Tasks {
+		task := &repository.Task{
+			ProjectID:      projectID,
+			Title:          seed.Title,
+			Description:    seed.Description,
+			Status:         seed.Status,
+			Priority:       seed.Priority,
+			Type:           seed.Type,
+			StoryPoints:    seed.StoryPoints,
+			EstimatedHours: seed.EstimatedHours,
+			AssigneeIDs:    []string{},
+			WatcherIDs:     []string{},
+			LabelIDs:       []string{},
+			CreatedBy:      &userID,
+		}
+		if seed.ParentRef != nil {
+			if parentID, ok := taskIDs[*seed.ParentRef]; ok {
+				task.ParentTaskID = &parentID
+			}
+		}
+		for _, name := range seed.Labels {
+			if id, ok := labelIDs[name]; ok {
+				task.LabelIDs = append(task.LabelIDs, id)
+			}
+		}
+
+		if err := s.taskRepo.Create(ctx, task); err != nil {
+			return err
+		}
+		taskIDs[seed.Ref] = task.ID
+
+		if len(task.LabelIDs) > 0 {
+			if err := s.taskLabelRepo.ReplaceLabels(ctx, task.ID, task.LabelIDs); err != nil {
+				return err
+			}
+		}
+
+		for _, cl := range seed.Checklists {
+			checklist := &repository.TaskChecklist{TaskID: task.ID, Title: cl.Title}
+			if err := s.checklistRepo.CreateChecklist(ctx, checklist); err != nil {
+				return err
+			}
+			for _, content := range cl.Items {
+				item := &repository.ChecklistItem{ChecklistID: checklist.ID, Content: content}
+				if err := s.checklistRepo.CreateItem(ctx, item); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,201 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/models"
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/repository"
+)
+
+// ============================================
+// Project Status Service
+// ============================================
+
+type ProjectStatusService interface {
+	ListStatuses(ctx context.Context, projectID, userID string) ([]*repository.ProjectStatus, error)
+	CreateStatus(ctx context.Context, projectID, userID string, req *models.CreateProjectStatusRequest) (*repository.ProjectStatus, error)
+	UpdateStatus(ctx context.Context, projectID, statusID, userID string, req *models.UpdateProjectStatusRequest) (*repository.ProjectStatus, error)
+	DeleteStatus(ctx context.Context, projectID, statusID, userID string) error
+}
+
+type projectStatusService struct {
+	statusRepo    repository.ProjectStatusRepository
+	taskRepo      repository.TaskRepository
+	memberService MemberService
+	permService   PermissionService
+	readCache     *ReadCache
+}
+
+func NewProjectStatusService(
+	statusRepo repository.ProjectStatusRepository,
+	taskRepo repository.TaskRepository,
+	memberService MemberService,
+	permService PermissionService,
+	readCache *ReadCache, // may be nil
+) ProjectStatusService {
+	return &projectStatusService{
+		statusRepo:    statusRepo,
+		taskRepo:      taskRepo,
+		memberService: memberService,
+		permService:   permService,
+		readCache:     readCache,
+	}
+}
+
+func (s *projectStatusService) ListStatuses(ctx context.Context, projectID, userID string) ([]*repository.ProjectStatus, error) {
+	hasAccess, _, err := s.memberService.HasEffectiveAccess(ctx, EntityTypeProject, projectID, userID)
+	if err != nil || !hasAccess {
+		return nil, ErrUnauthorized
+	}
+
+	statuses, err := s.statusRepo.FindByProjectID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if statuses == nil {
+		statuses = []*repository.ProjectStatus{}
+	}
+	return statuses, nil
+}
+
+func (s *projectStatusService) CreateStatus(ctx context.Context, projectID, userID string, req *models.CreateProjectStatusRequest) (*repository.ProjectStatus, error) {
+	if !s.permService.CanManageProject(ctx, userID, projectID) {
+		return nil, ErrUnauthorized
+	}
+
+	key := strings.ToLower(strings.TrimSpace(req.Key))
+	if key == "" || strings.TrimSpace(req.Name) == "" {
+		return nil, ErrInvalidInput
+	}
+
+	existing, err := s.statusRepo.FindByProjectID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	for _, st := range existing {
+		if st.Key == key {
+			return nil, ErrConflict
+		}
+	}
+
+	if err := validateTransitions(existing, key, req.AllowedTransitions); err != nil {
+		return nil, err
+	}
+
+	position := len(existing)
+	if req.Position != nil {
+		position = *req.Position
+	}
+
+	transitions := req.AllowedTransitions
+	if transitions == nil {
+		transitions = []string{}
+	}
+
+	status := &repository.ProjectStatus{
+		ProjectID:          projectID,
+		Key:                key,
+		Name:               strings.TrimSpace(req.Name),
+		Color:              req.Color,
+		Position:           position,
+		AllowedTransitions: transitions,
+	}
+
+	if err := s.statusRepo.Create(ctx, status); err != nil {
+		return nil, err
+	}
+	// Cached boards are laid out by the project's columns
+	s.readCache.InvalidateProject(ctx, projectID)
+	return status, nil
+}
+
+func (s *projectStatusService) UpdateStatus(ctx context.Context, projectID, statusID, userID string, req *models.UpdateProjectStatusRequest) (*repository.ProjectStatus, error) {
+	status, err := s.findProjectStatus(ctx, projectID, statusID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		if strings.TrimSpace(*req.Name) == "" {
+			return nil, ErrInvalidInput
+		}
+		status.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Color != nil {
+		status.Color = req.Color
+	}
+	if req.Position != nil {
+		status.Position = *req.Position
+	}
+	if req.AllowedTransitions != nil {
+		existing, err := s.statusRepo.FindByProjectID(ctx, projectID)
+		if err != nil {
+			return nil, err
+		}
+		if err := validateTransitions(existing, status.Key, *req.AllowedTransitions); err != nil {
+			return nil, err
+		}
+		status.AllowedTransitions = *req.AllowedTransitions
+	}
+
+	if err := s.statusRepo.Update(ctx, status); err != nil {
+		return nil, err
+	}
+	s.readCache.InvalidateProject(ctx, projectID)
+	return status, nil
+}
+
+func (s *projectStatusService) DeleteStatus(ctx context.Context, projectID, statusID, userID string) error {
+	status, err := s.findProjectStatus(ctx, projectID, statusID, userID)
+	if err != nil {
+		return err
+	}
+
+	// Refuse to orphan tasks that still sit in this column
+	tasks, err := s.taskRepo.FindByStatus(ctx, projectID, status.Key)
+	if err != nil {
+		return err
+	}
+	if len(tasks) > 0 {
+		return fmt.Errorf("%w: %d task(s) still use status %q", ErrConflict, len(tasks), status.Key)
+	}
+
+	if err := s.statusRepo.Delete(ctx, status.ID); err != nil {
+		return err
+	}
+	s.readCache.InvalidateProject(ctx, projectID)
+	return nil
+}
+
+// findProjectStatus loads a status for a change, after checking the caller
+// manages the project and that the status belongs to it
+func (s *projectStatusService) findProjectStatus(ctx context.Context, projectID, statusID, userID string) (*repository.ProjectStatus, error) {
+	if !s.permService.CanManageProject(ctx, userID, projectID) {
+		return nil, ErrUnauthorized
+	}
+
+	status, err := s.statusRepo.FindByID(ctx, statusID)
+	if err != nil {
+		return nil, err
+	}
+	if status == nil || status.ProjectID != projectID {
+		return nil, ErrNotFound
+	}
+	return status, nil
+}
+
+// validateTransitions checks every allowed target is a status of the project (or the status itself)
+func validateTransitions(existing []*repository.ProjectStatus, self string, transitions []string) error {
+	known := map[string]bool{self: true}
+	for _, st := range existing {
+		known[st.Key] = true
+	}
+	for _, t := range transitions {
+		if !known[t] {
+			return fmt.Errorf("%w: unknown status %q in allowed transitions", ErrInvalidInput, t)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,225 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/db"
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/repository"
+	"github.com/redis/go-redis/v9"
+)
+
+// DefaultReadCacheTTL bounds how stale a cached read can get when a write
+// slips past invalidation
+const DefaultReadCacheTTL = 60 * time.Second
+
+// ReadCache keeps expensive read results in Redis: sprint boards and stats
+// per project, and project member lists. Writes invalidate explicitly; the
+// TTL is only a safety net. Without Redis every read goes to the database.
+type ReadCache struct {
+	redis *db.RedisDB
+	ttl   time.Duration
+
+	hits   atomic.Int64
+	misses atomic.Int64
+}
+
+// NewReadCache creates a ReadCache; redisDB may be nil
+func NewReadCache(redisDB *db.RedisDB, ttl time.Duration) *ReadCache {
+	if ttl <= 0 {
+		ttl = DefaultReadCacheTTL
+	}
+	return &ReadCache{redis: redisDB, ttl: ttl}
+}
+
+func sprintBoardCacheKey(projectID, sprintID string) string {
+	return "read:project:" + projectID + ":board:" + sprintID
+}
+
+func projectStatsCacheKey(projectID string) string {
+	return "read:project:" + projectID + ":stats"
+}
+
+func projectMembersCacheKey(projectID string, effective bool) string {
+	if effective {
+		return "read:members:project:" + projectID + ":effective"
+	}
+	return "read:members:project:" + projectID + ":direct"
+}
+
+func (c *ReadCache) enabled() bool {
+	return c != nil && c.redis != nil
+}
+
+// get loads a cached value into dest and reports whether it was found
+func (c *ReadCache) get(ctx context.Context, key string, dest interface{}) bool {
+	if !c.enabled() {
+		return false
+	}
+	if err := c.redis.GetCache(ctx, key, dest); err != nil {
+		if err != redis.Nil {
+			slog.WarnContext(ctx, "read cache get failed", "key", key, "error", err)
+		}
+		c.misses.Add(1)
+		return false
+	}
+	c.hits.Add(1)
+	return true
+}
+
+func (c *ReadCache) put(ctx context.Context, key string, value interface{}) {
+	if !c.enabled() {
+		return
+	}
+	if err := c.redis.SetCache(ctx, key, value, c.ttl); err != nil {
+		slog.WarnContext(ctx, "read cache set failed", "key", key, "error", err)
+	}
+}
+
+func (c *ReadCache) invalidate(ctx context.Context, pattern string) {
+	if !c.enabled() {
+		return
+	}
+	if err := c.redis.InvalidateCache(ctx, pattern); err != nil {
+		slog.WarnContext(ctx, "read cache invalidation failed", "pattern", pattern, "error", err)
+	}
+}
+
+// InvalidateProject drops the project's cached boards and stats
+func (c *ReadCache) InvalidateProject(ctx context.Context, projectID string) {
+	if projectID == "" {
+		return
+	}
+	c.invalidate(ctx, "read:project:"+projectID+":*")
+}
+
+// InvalidateMemberLists drops every cached member list. Effective lists
+// inherit from workspaces, spaces and folders, so one membership change can
+// affect many projects.
+func (c *ReadCache) InvalidateMemberLists(ctx context.Context) {
+	c.invalidate(ctx, "read:members:*")
+}
+
+// Stats returns the hit counts since startup
+func (c *ReadCache) Stats() CacheStats {
+	if c == nil {
+		return CacheStats{}
+	}
+	return newCacheStats(c.redis != nil, c.hits.Load(), c.misses.Load())
+}
+
+// invalidatingTaskRepository drops cached project reads after every task
+// write, so boards and stats stay fresh whichever service or job wrote
+type invalidatingTaskRepository struct {
+	repository.TaskRepository
+	cache *ReadCache
+}
+
+// newInvalidatingTaskRepository wraps repo only when the cache is enabled
+func newInvalidatingTaskRepository(repo repository.TaskRepository, cache *ReadCache) repository.TaskRepository {
+	if !cache.enabled() {
+		return repo
+	}
+	return &invalidatingTaskRepository{TaskRepository: repo, cache: cache}
+}
+
+// projectOf looks the task's project up before a write that only has the ID
+func (r *invalidatingTaskRepository) projectOf(ctx context.Context, taskID string) string {
+	task, err := r.TaskRepository.FindByID(ctx, taskID)
+	if err != nil || task == nil {
+		return ""
+	}
+	return task.ProjectID
+}
+
+// afterWrite invalidates on success and passes the error through
+func (r *invalidatingTaskRepository) afterWrite(ctx context.Context, projectID string, err error) error {
+	if err == nil {
+		r.cache.InvalidateProject(ctx, projectID)
+	}
+	return err
+}
+
+func (r *invalidatingTaskRepository) Create(ctx context.Context, task *repository.Task) error {
+	return r.afterWrite(ctx, task.ProjectID, r.TaskRepository.Create(ctx, task))
+}
+
+func (r *invalidatingTaskRepository) Update(ctx context.Context, task *repository.Task) error {
+	return r.afterWrite(ctx, task.ProjectID, r.TaskRepository.Update(ctx, task))
+}
+
+// MoveToProject drops the cached reads of both the old and the new project
+func (r *invalidatingTaskRepository) MoveToProject(ctx context.Context, tasks []*repository.Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+	fromProjectID := r.projectOf(ctx, tasks[0].ID)
+	if err := r.afterWrite(ctx, tasks[0].ProjectID, r.TaskRepository.MoveToProject(ctx, tasks)); err != nil {
+		return err
+	}
+	r.cache.InvalidateProject(ctx, fromProjectID)
+	return nil
+}
+
+func (r *invalidatingTaskRepository) Delete(ctx context.Context, id string) error {
+	projectID := r.projectOf(ctx, id)
+	return r.afterWrite(ctx, projectID, r.TaskRepository.Delete(ctx, id))
+}
+
+func (r *invalidatingTaskRepository) UpdateStatus(ctx context.Context, taskID, status string, expectedVersion *int) error {
+	projectID := r.projectOf(ctx, taskID)
+	return r.afterWrite(ctx, projectID, r.TaskRepository.UpdateStatus(ctx, taskID, status, expectedVersion))
+}
+
+func (r *invalidatingTaskRepository) UpdatePriority(ctx context.Context, taskID, priority string, expectedVersion *int) error {
+	projectID := r.projectOf(ctx, taskID)
+	return r.afterWrite(ctx, projectID, r.TaskRepository.UpdatePriority(ctx, taskID, priority, expectedVersion))
+}
+
+func (r *invalidatingTaskRepository) MarkComplete(ctx context.Context, taskID string) error {
+	projectID := r.projectOf(ctx, taskID)
+	return r.afterWrite(ctx, projectID, r.TaskRepository.MarkComplete(ctx, taskID))
+}
+
+func (r *invalidatingTaskRepository) AddAssignee(ctx context.Context, taskID, assigneeID string) error {
+	projectID := r.projectOf(ctx, taskID)
+	return r.afterWrite(ctx, projectID, r.TaskRepository.AddAssignee(ctx, taskID, assigneeID))
+}
+
+func (r *invalidatingTaskRepository) RemoveAssignee(ctx context.Context, taskID, assigneeID string) error {
+	projectID := r.projectOf(ctx, taskID)
+	return r.afterWrite(ctx, projectID, r.TaskRepository.RemoveAssignee(ctx, taskID, assigneeID))
+}
+
+func (r *invalidatingTaskRepository) UpdatePosition(ctx context.Context, taskID string, position int) error {
+	projectID := r.projectOf(ctx, taskID)
+	return r.afterWrite(ctx, projectID, r.TaskRepository.UpdatePosition(ctx, taskID, position))
+}
+
+func (r *invalidatingTaskRepository) RenumberColumn(ctx context.Context, projectID string, sprintID *string, status string, gap int) error {
+	return r.afterWrite(ctx, projectID, r.TaskRepository.RenumberColumn(ctx, projectID, sprintID, status, gap))
+}
+
+// Bulk writes can span projects; they drop every project's cached reads
+
+func (r *invalidatingTaskRepository) BulkUpdateStatus(ctx context.Context, taskIDs []string, status string) error {
+	return r.afterBulkWrite(ctx, r.TaskRepository.BulkUpdateStatus(ctx, taskIDs, status))
+}
+
+func (r *invalidatingTaskRepository) BulkMoveToSprint(ctx context.Context, taskIDs []string, sprintID string) error {
+	return r.afterBulkWrite(ctx, r.TaskRepository.BulkMoveToSprint(ctx, taskIDs, sprintID))
+}
+
+func (r *invalidatingTaskRepository) BulkDelete(ctx context.Context, taskIDs []string, deletedBy string) ([]*repository.Task, error) {
+	deleted, err := r.TaskRepository.BulkDelete(ctx, taskIDs, deletedBy)
+	return deleted, r.afterBulkWrite(ctx, err)
+}
+
+func (r *invalidatingTaskRepository) afterBulkWrite(ctx context.Context, err error) error {
+	if err == nil {
+		r.cache.invalidate(ctx, "read:project:*")
+	}
+	return err
+}
@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/models"
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/repository"
+	cronlib "github.com/robfig/cron/v3"
+)
+
+// ============================================
+// Recurring Task Service
+// ============================================
+
+type RecurringTaskService interface {
+	CreateRecurringTask(ctx context.Context, projectID, userID string, req *models.CreateRecurringTaskRequest) (*repository.RecurringTask, error)
+	ListRecurringTasks(ctx context.Context, projectID, userID string) ([]*repository.RecurringTask, error)
+	// InstantiateDueTasks creates real tasks from every template whose next run has passed
+	InstantiateDueTasks(ctx context.Context) (int, error)
+}
+
+type recurringTaskService struct {
+	recurringRepo repository.RecurringTaskRepository
+	taskService   TaskService
+	memberService MemberService
+	permService   PermissionService
+}
+
+func NewRecurringTaskService(
+	recurringRepo repository.RecurringTaskRepository,
+	taskService TaskService,
+	memberService MemberService,
+	permService PermissionService,
+) RecurringTaskService {
+	return &recurringTaskService{
+		recurringRepo: recurringRepo,
+		taskService:   taskService,
+		memberService: memberService,
+		permService:   permService,
+	}
+}
+
+func (s *recurringTaskService) CreateRecurringTask(ctx context.Context, projectID, userID string, req *models.CreateRecurringTaskRequest) (*repository.RecurringTask, error) {
+	// Tasks are later created as this user, so they must be allowed to create them
+	if !s.permService.CanCreateTask(ctx, userID, projectID) {
+		return nil, ErrUnauthorized
+	}
+
+	cadence := strings.TrimSpace(req.Cadence)
+	if _, err := nextRecurrence(cadence, time.Now()); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+
+	priority := req.Priority
+	if priority == "" {
+		priority = "medium"
+	}
+
+	nextRun := time.Now()
+	if req.StartAt != nil {
+		nextRun = *req.StartAt
+	}
+
+	assigneeIDs := req.AssigneeIDs
+	if assigneeIDs == nil {
+		assigneeIDs = []string{}
+	}
+
+	rt := &repository.RecurringTask{
+		ProjectID:   projectID,
+		Title:       req.Title,
+		Description: req.Description,
+		Priority:    priority,
+		Type:        req.Type,
+		AssigneeIDs: assigneeIDs,
+		Cadence:     cadence,
+		NextRunAt:   nextRun,
+		Active:      true,
+		CreatedBy:   &userID,
+	}
+
+	if err := s.recurringRepo.Create(ctx, rt); err != nil {
+		return nil, err
+	}
+	return rt, nil
+}
+
+func (s *recurringTaskService) ListRecurringTasks(ctx context.Context, projectID, userID string) ([]*repository.RecurringTask, error) {
+	hasAccess, _, err := s.memberService.HasEffectiveAccess(ctx, EntityTypeProject, projectID, userID)
+	if err != nil || !hasAccess {
+		return nil, ErrUnauthorized
+	}
+
+	return s.recurringRepo.FindByProjectID(ctx, projectID)
+}
+
+func (s *recurringTaskService) InstantiateDueTasks(ctx context.Context) (int, error) {
+	now := time.Now()
+	templates, err := s.recurringRepo.FindDue(ctx, now)
+	if err != nil {
+		return 0, err
+	}
+
+	created := 0
+	for _, rt := range templates {
+		// Skip missed windows so a long outage only produces a single task
+		next := rt.NextRunAt
+		for !next.After(now) {
+			next, err = nextRecurrence(rt.Cadence, next)
+			if err != nil {
+				break
+			}
+		}
+		if err != nil {
+			slog.ErrorContext(ctx, "invalid recurring task cadence", "templateID", rt.ID, "cadence", rt.Cadence, "error", err)
+			continue
+		}
+
+		// Claiming the window first makes a second tick in the same window a no-op
+		claimed, err := s.recurringRepo.ClaimRun(ctx, rt.ID, rt.NextRunAt, next)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to claim recurring task template", "templateID", rt.ID, "error", err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+
+		_, err = s.taskService.Create(ctx, &models.CreateTaskRequest{
+			ProjectID:   rt.ProjectID,
+			Title:       rt.Title,
+			Description: rt.Description,
+			Priority:    rt.Priority,
+			Type:        rt.Type,
+			AssigneeIDs: rt.AssigneeIDs,
+			CreatedBy:   rt.CreatedBy,
+		})
+		if errors.Is(err, ErrUnauthorized) {
+			// The creator lost the right to create tasks here; retrying can't
+			// succeed, so stop the template instead of handing the window back
+			slog.WarnContext(ctx, "recurring task creator can no longer create tasks, deactivating template", "templateID", rt.ID, "projectID", rt.ProjectID)
+			if err := s.recurringRepo.Deactivate(ctx, rt.ID); err != nil {
+				slog.ErrorContext(ctx, "failed to deactivate recurring task template", "templateID", rt.ID, "error", err)
+			}
+			continue
+		}
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to create task from recurring template", "templateID", rt.ID, "error", err)
+			// Give the window back so this occurrence is not lost
+			if err := s.recurringRepo.ReleaseRun(ctx, rt.ID, next, rt.NextRunAt, rt.LastRunAt); err != nil {
+				slog.ErrorContext(ctx, "failed to release recurring task claim", "templateID", rt.ID, "error", err)
+			}
+			continue
+		}
+		created++
+	}
+
+	return created, nil
+}
+
+// nextRecurrence returns the first run time after from for the given cadence
+func nextRecurrence(cadence string, from time.Time) (time.Time, error) {
+	switch strings.ToLower(cadence) {
+	case "daily":
+		return from.AddDate(0, 0, 1), nil
+	case "weekly":
+		return from.AddDate(0, 0, 7), nil
+	case "monthly":
+		return from.AddDate(0, 1, 0), nil
+	}
+
+	schedule, err := cronlib.ParseStandard(cadence)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid cadence %q", cadence)
+	}
+	return schedule.Next(from), nil
+}
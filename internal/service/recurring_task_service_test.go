@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/models"
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/repository"
+)
+
+type fakeRecurringRepo struct {
+	repository.RecurringTaskRepository
+	templates map[string]*repository.RecurringTask
+}
+
+func (r *fakeRecurringRepo) FindDue(_ context.Context, now time.Time) ([]*repository.RecurringTask, error) {
+	var due []*repository.RecurringTask
+	for _, rt := range r.templates {
+		if rt.Active && !rt.NextRunAt.After(now) {
+			copied := *rt
+			due = append(due, &copied)
+		}
+	}
+	return due, nil
+}
+
+func (r *fakeRecurringRepo) ClaimRun(_ context.Context, id string, expectedRunAt, nextRunAt time.Time) (bool, error) {
+	rt := r.templates[id]
+	if !rt.NextRunAt.Equal(expectedRunAt) {
+		return false, nil
+	}
+	now := time.Now()
+	rt.NextRunAt, rt.LastRunAt = nextRunAt, &now
+	return true, nil
+}
+
+func (r *fakeRecurringRepo) ReleaseRun(_ context.Context, id string, claimedNextRunAt, runAt time.Time, lastRunAt *time.Time) error {
+	rt := r.templates[id]
+	if rt.NextRunAt.Equal(claimedNextRunAt) {
+		rt.NextRunAt, rt.LastRunAt = runAt, lastRunAt
+	}
+	return nil
+}
+
+func (r *fakeRecurringRepo) Deactivate(_ context.Context, id string) error {
+	r.templates[id].Active = false
+	return nil
+}
+
+func (r *fakeRecurringRepo) Create(_ context.Context, rt *repository.RecurringTask) error {
+	rt.ID = "new"
+	r.templates[rt.ID] = rt
+	return nil
+}
+
+type flakyTaskCreator struct {
+	TaskService
+	err     error
+	created int
+}
+
+func (s *flakyTaskCreator) Create(context.Context, *models.CreateTaskRequest) (*repository.Task, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	s.created++
+	return &repository.Task{}, nil
+}
+
+func TestInstantiateDueTasksReleasesClaimOnFailure(t *testing.T) {
+	runAt := time.Now().Add(-time.Minute).Truncate(time.Second)
+	repo := &fakeRecurringRepo{templates: map[string]*repository.RecurringTask{
+		"standup": {ID: "standup", Title: "Standup notes", Cadence: "daily", NextRunAt: runAt, Active: true},
+	}}
+	tasks := &flakyTaskCreator{err: errors.New("database unavailable")}
+	svc := &recurringTaskService{recurringRepo: repo, taskService: tasks}
+	ctx := context.Background()
+
+	if n, _ := svc.InstantiateDueTasks(ctx); n != 0 {
+		t.Fatalf("created %d tasks while Create failed", n)
+	}
+	if got := repo.templates["standup"]; !got.NextRunAt.Equal(runAt) || got.LastRunAt != nil {
+		t.Fatalf("claim kept after failed create: next %v, last %v", got.NextRunAt, got.LastRunAt)
+	}
+
+	tasks.err = nil
+	if n, _ := svc.InstantiateDueTasks(ctx); n != 1 {
+		t.Fatalf("retry created %d tasks, want 1", n)
+	}
+	if got := repo.templates["standup"].NextRunAt; !got.After(time.Now()) {
+		t.Errorf("next run %v not moved past now after a successful run", got)
+	}
+}
+
+func TestInstantiateDueTasksDeactivatesTemplateOnUnauthorized(t *testing.T) {
+	runAt := time.Now().Add(-time.Minute).Truncate(time.Second)
+	repo := &fakeRecurringRepo{templates: map[string]*repository.RecurringTask{
+		"standup": {ID: "standup", Title: "Standup notes", Cadence: "daily", NextRunAt: runAt, Active: true},
+	}}
+	tasks := &flakyTaskCreator{err: ErrUnauthorized}
+	svc := &recurringTaskService{recurringRepo: repo, taskService: tasks}
+	ctx := context.Background()
+
+	if n, _ := svc.InstantiateDueTasks(ctx); n != 0 {
+		t.Fatalf("created %d tasks for an unauthorized creator", n)
+	}
+	got := repo.templates["standup"]
+	if got.Active {
+		t.Fatal("template still active after an unauthorized run")
+	}
+	if got.NextRunAt.Equal(runAt) {
+		t.Fatal("window was handed back, so the next tick would retry it")
+	}
+	if due, _ := repo.FindDue(ctx, time.Now()); len(due) != 0 {
+		t.Fatalf("deactivated template still due: %+v", due)
+	}
+}
+
+type recurringPermissions struct {
+	PermissionService
+	canCreate bool
+}
+
+func (p recurringPermissions) CanCreateTask(context.Context, string, string) bool { return p.canCreate }
+
+func TestCreateRecurringTaskRequiresCreatePermission(t *testing.T) {
+	repo := &fakeRecurringRepo{templates: map[string]*repository.RecurringTask{}}
+	req := &models.CreateRecurringTaskRequest{Title: "Standup notes", Cadence: "daily"}
+	ctx := context.Background()
+
+	viewer := &recurringTaskService{recurringRepo: repo, permService: recurringPermissions{canCreate: false}}
+	if _, err := viewer.CreateRecurringTask(ctx, "p1", "viewer", req); !errors.Is(err, ErrUnauthorized) {
+		t.Fatalf("viewer CreateRecurringTask error = %v, want ErrUnauthorized", err)
+	}
+	if len(repo.templates) != 0 {
+		t.Fatal("template stored for a user who cannot create tasks")
+	}
+
+	member := &recurringTaskService{recurringRepo: repo, permService: recurringPermissions{canCreate: true}}
+	if _, err := member.CreateRecurringTask(ctx, "p1", "member", req); err != nil {
+		t.Fatalf("member CreateRecurringTask: %v", err)
+	}
+}
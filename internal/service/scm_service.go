@@ -0,0 +1,398 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/models"
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/repository"
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/types"
+)
+
+// ============================================
+// SCM Integration Service
+// ============================================
+
+// maxSCMCommits bounds the work done for a single event
+const maxSCMCommits = 100
+
+var (
+	// scmTaskKeyPattern matches task keys such as PROJ-123; project keys are
+	// 2-10 letters or digits starting with a letter
+	scmTaskKeyPattern = regexp.MustCompile(`\b([A-Za-z][A-Za-z0-9]{1,9})-(\d+)\b`)
+	// scmCloseKeyPattern matches a magic word directly before a key, e.g. "fixes PROJ-123"
+	scmCloseKeyPattern = regexp.MustCompile(`(?i)\b(?:close[sd]?|fix(?:e[sd])?|resolve[sd]?):?\s+([A-Za-z][A-Za-z0-9]{1,9}-\d+)\b`)
+)
+
+// SCMService links commits and branches to the tasks they mention. Events
+// are authenticated with a workspace integration token rather than a user
+// session. Comments are posted by the integration bot, and transitions are
+// made on behalf of the admin who created the token.
+type SCMService interface {
+	// GenerateIntegrationToken returns a new token, invalidating any previous one
+	GenerateIntegrationToken(ctx context.Context, workspaceID, userID string) (string, error)
+	RevokeIntegrationToken(ctx context.Context, workspaceID, userID string) error
+
+	GetProjectSettings(ctx context.Context, projectID, userID string) (*repository.ProjectSCMSettings, error)
+	UpdateProjectSettings(ctx context.Context, projectID, userID string, req *models.UpdateSCMSettingsRequest) (*repository.ProjectSCMSettings, error)
+
+	// HandleEvent comments on every referenced task of the token's workspace
+	// and moves tasks named after a magic word to the project's done status.
+	// ErrInvalidToken if no workspace has the token.
+	HandleEvent(ctx context.Context, token string, event *models.SCMEventRequest) (*SCMEventResult, error)
+}
+
+// SCMEventResult lists what was done for each referenced task
+type SCMEventResult struct {
+	References []SCMReference `json:"references"`
+}
+
+// SCMReference is one task mentioned by a commit or branch
+type SCMReference struct {
+	TaskKey      string `json:"taskKey"`
+	TaskID       string `json:"taskId"`
+	Ref          string `json:"ref"` // "commit:<sha>" or "branch:<name>"
+	Commented    bool   `json:"commented"`
+	Transitioned bool   `json:"transitioned"`
+	Error        string `json:"error,omitempty"`
+}
+
+type scmService struct {
+	scmRepo           repository.SCMIntegrationRepository
+	taskRepo          repository.TaskRepository
+	projectRepo       repository.ProjectRepository
+	spaceRepo         repository.SpaceRepository
+	projectStatusRepo repository.ProjectStatusRepository
+	taskService       TaskService
+	memberService     MemberService
+}
+
+func NewSCMService(
+	scmRepo repository.SCMIntegrationRepository,
+	taskRepo repository.TaskRepository,
+	projectRepo repository.ProjectRepository,
+	spaceRepo repository.SpaceRepository,
+	projectStatusRepo repository.ProjectStatusRepository,
+	taskService TaskService,
+	memberService MemberService,
+) SCMService {
+	return &scmService{
+		scmRepo:           scmRepo,
+		taskRepo:          taskRepo,
+		projectRepo:       projectRepo,
+		spaceRepo:         spaceRepo,
+		projectStatusRepo: projectStatusRepo,
+		taskService:       taskService,
+		memberService:     memberService,
+	}
+}
+
+// ============================================
+// Token and settings management
+// ============================================
+
+func (s *scmService) GenerateIntegrationToken(ctx context.Context, workspaceID, userID string) (string, error) {
+	if err := s.requireRole(ctx, EntityTypeWorkspace, workspaceID, userID, PermissionAdmin); err != nil {
+		return "", err
+	}
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	token := hex.EncodeToString(buf)
+	if err := s.scmRepo.SaveToken(ctx, workspaceID, hashFeedToken(token), userID); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+func (s *scmService) RevokeIntegrationToken(ctx context.Context, workspaceID, userID string) error {
+	if err := s.requireRole(ctx, EntityTypeWorkspace, workspaceID, userID, PermissionAdmin); err != nil {
+		return err
+	}
+	return s.scmRepo.DeleteToken(ctx, workspaceID)
+}
+
+func (s *scmService) GetProjectSettings(ctx context.Context, projectID, userID string) (*repository.ProjectSCMSettings, error) {
+	hasAccess, _, err := s.memberService.HasEffectiveAccess(ctx, EntityTypeProject, projectID, userID)
+	if err != nil || !hasAccess {
+		return nil, ErrForbidden
+	}
+	return s.scmRepo.GetProjectSettings(ctx, projectID)
+}
+
+func (s *scmService) UpdateProjectSettings(ctx context.Context, projectID, userID string, req *models.UpdateSCMSettingsRequest) (*repository.ProjectSCMSettings, error) {
+	if err := s.requireRole(ctx, EntityTypeProject, projectID, userID, PermissionAdmin); err != nil {
+		return nil, err
+	}
+	settings, err := s.scmRepo.GetProjectSettings(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.AutoTransition != nil {
+		settings.AutoTransition = *req.AutoTransition
+	}
+	if req.DoneStatus != nil {
+		status := strings.TrimSpace(*req.DoneStatus)
+		if err := s.validateDoneStatus(ctx, projectID, status); err != nil {
+			return nil, err
+		}
+		settings.DoneStatus = status
+	}
+
+	if err := s.scmRepo.SaveProjectSettings(ctx, settings); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// validateDoneStatus accepts the project's custom statuses, or the default
+// statuses when it has none
+func (s *scmService) validateDoneStatus(ctx context.Context, projectID, status string) error {
+	statuses, err := s.projectStatusRepo.FindByProjectID(ctx, projectID)
+	if err != nil {
+		return err
+	}
+	if len(statuses) == 0 && types.IsValidTaskStatus(status) {
+		return nil
+	}
+	for _, st := range statuses {
+		if st.Key == status {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: unknown status %q", ErrInvalidInput, status)
+}
+
+func (s *scmService) requireRole(ctx context.Context, entityType, entityID, userID, minRole string) error {
+	hasAccess, role, err := s.memberService.HasEffectiveAccess(ctx, entityType, entityID, userID)
+	if err != nil || !hasAccess || !hasMinimumRole(normalizeRole(role), minRole) {
+		return ErrForbidden
+	}
+	return nil
+}
+
+// ============================================
+// Event processing
+// ============================================
+
+// scmEventContext caches lookups made while processing one event
+type scmEventContext struct {
+	workspaceID string
+	actorID     string
+	tasks       map[string]*repository.Task // by upper-case key; nil when not in the workspace
+	workspaces  map[string]string           // project ID -> workspace ID
+	settings    map[string]*repository.ProjectSCMSettings
+}
+
+func (s *scmService) HandleEvent(ctx context.Context, token string, event *models.SCMEventRequest) (*SCMEventResult, error) {
+	if token == "" {
+		return nil, ErrInvalidToken
+	}
+	integration, err := s.scmRepo.FindByToken(ctx, hashFeedToken(token))
+	if err != nil {
+		return nil, err
+	}
+	if integration == nil {
+		return nil, ErrInvalidToken
+	}
+	if len(event.Commits) > maxSCMCommits {
+		return nil, fmt.Errorf("%w: at most %d commits per event", ErrInvalidInput, maxSCMCommits)
+	}
+
+	ec := &scmEventContext{
+		workspaceID: integration.WorkspaceID,
+		actorID:     integration.CreatedBy,
+		tasks:       make(map[string]*repository.Task),
+		workspaces:  make(map[string]string),
+		settings:    make(map[string]*repository.ProjectSCMSettings),
+	}
+	result := &SCMEventResult{References: []SCMReference{}}
+
+	for _, commit := range event.Commits {
+		if commit.ID == "" {
+			continue
+		}
+		closes := make(map[string]bool)
+		for _, m := range scmCloseKeyPattern.FindAllStringSubmatch(commit.Message, -1) {
+			closes[strings.ToUpper(m[1])] = true
+		}
+		content := commitComment(commit, event.Repository)
+		for _, key := range scmTaskKeys(commit.Message) {
+			if ref := s.reference(ctx, ec, key, "commit:"+commit.ID, content, closes[key]); ref != nil {
+				result.References = append(result.References, *ref)
+			}
+		}
+	}
+
+	for _, branch := range event.Branches {
+		if branch.Name == "" {
+			continue
+		}
+		content := branchComment(branch, event.Repository)
+		for _, key := range scmTaskKeys(branch.Name) {
+			if ref := s.reference(ctx, ec, key, "branch:"+branch.Name, content, false); ref != nil {
+				result.References = append(result.References, *ref)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// reference links one task to a commit or branch. It returns nil when the key
+// is not a task of the workspace or the ref was already linked to the task.
+// The link is recorded only once the comment is posted, so a failed comment
+// is retried when the event is redelivered.
+func (s *scmService) reference(ctx context.Context, ec *scmEventContext, key, ref, content string, close bool) *SCMReference {
+	task := s.resolveTask(ctx, ec, key)
+	if task == nil {
+		return nil
+	}
+	linked, err := s.scmRepo.HasReference(ctx, task.ID, ref)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to check SCM reference", "ref", ref, "taskID", task.ID, "error", err)
+		return &SCMReference{TaskKey: key, TaskID: task.ID, Ref: ref, Error: "failed to check reference"}
+	}
+	if linked {
+		return nil
+	}
+
+	result := &SCMReference{TaskKey: key, TaskID: task.ID, Ref: ref}
+	if _, err := s.taskService.AddSystemComment(ctx, task.ID, content); err != nil {
+		slog.WarnContext(ctx, "failed to comment SCM reference", "taskID", task.ID, "error", err)
+		result.Error = err.Error()
+		return result
+	}
+	result.Commented = true
+
+	if isNew, err := s.scmRepo.RecordReference(ctx, task.ID, ref); err != nil {
+		slog.WarnContext(ctx, "failed to record SCM reference", "ref", ref, "taskID", task.ID, "error", err)
+	} else if !isNew {
+		// A concurrent delivery of the same ref linked it first
+		slog.InfoContext(ctx, "SCM reference linked concurrently", "ref", ref, "taskID", task.ID)
+	}
+
+	if !close {
+		return result
+	}
+	settings := s.projectSettings(ctx, ec, task.ProjectID)
+	if settings == nil || !settings.AutoTransition {
+		return result
+	}
+	if err := s.taskService.UpdateStatus(ctx, task.ID, settings.DoneStatus, ec.actorID, nil); err != nil {
+		slog.WarnContext(ctx, "failed to move task on SCM reference", "taskID", task.ID, "status", settings.DoneStatus, "error", err)
+		result.Error = err.Error()
+		return result
+	}
+	result.Transitioned = true
+	return result
+}
+
+// resolveTask finds the task with the key, if it belongs to the token's workspace
+func (s *scmService) resolveTask(ctx context.Context, ec *scmEventContext, key string) *repository.Task {
+	if task, ok := ec.tasks[key]; ok {
+		return task
+	}
+	ec.tasks[key] = nil
+
+	dash := strings.LastIndex(key, "-")
+	number, err := strconv.Atoi(key[dash+1:])
+	if err != nil {
+		return nil
+	}
+	task, err := s.taskRepo.FindByKey(ctx, key[:dash], number)
+	if err != nil || task == nil {
+		return nil
+	}
+
+	workspaceID, ok := ec.workspaces[task.ProjectID]
+	if !ok {
+		workspaceID = s.projectWorkspaceID(ctx, task.ProjectID)
+		ec.workspaces[task.ProjectID] = workspaceID
+	}
+	if workspaceID != ec.workspaceID {
+		return nil
+	}
+	ec.tasks[key] = task
+	return task
+}
+
+func (s *scmService) projectSettings(ctx context.Context, ec *scmEventContext, projectID string) *repository.ProjectSCMSettings {
+	if settings, ok := ec.settings[projectID]; ok {
+		return settings
+	}
+	settings, err := s.scmRepo.GetProjectSettings(ctx, projectID)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to load SCM settings", "projectID", projectID, "error", err)
+		settings = nil
+	}
+	ec.settings[projectID] = settings
+	return settings
+}
+
+func (s *scmService) projectWorkspaceID(ctx context.Context, projectID string) string {
+	project, err := s.projectRepo.FindByID(ctx, projectID)
+	if err != nil || project == nil {
+		return ""
+	}
+	space, err := s.spaceRepo.FindByID(ctx, project.SpaceID)
+	if err != nil || space == nil {
+		return ""
+	}
+	return space.WorkspaceID
+}
+
+// scmTaskKeys returns the distinct task keys in text, upper-cased, in order
+func scmTaskKeys(text string) []string {
+	var keys []string
+	seen := make(map[string]bool)
+	for _, m := range scmTaskKeyPattern.FindAllString(text, -1) {
+		key := strings.ToUpper(m)
+		if !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+func commitComment(commit models.SCMCommit, repository string) string {
+	sha := commit.ID
+	if len(sha) > 7 {
+		sha = sha[:7]
+	}
+	var b strings.Builder
+	b.WriteString("Referenced in commit " + sha)
+	if repository != "" {
+		b.WriteString(" in " + repository)
+	}
+	if commit.Author != "" {
+		b.WriteString(" by " + commit.Author)
+	}
+	if summary, _, _ := strings.Cut(strings.TrimSpace(commit.Message), "\n"); summary != "" {
+		b.WriteString(": " + summary)
+	}
+	if commit.URL != "" {
+		b.WriteString("\n" + commit.URL)
+	}
+	return b.String()
+}
+
+func branchComment(branch models.SCMBranch, repository string) string {
+	content := "Referenced in branch " + branch.Name
+	if repository != "" {
+		content += " in " + repository
+	}
+	if branch.URL != "" {
+		content += "\n" + branch.URL
+	}
+	return content
+}
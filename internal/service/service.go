@@ -2,12 +2,16 @@ package service
 
 import (
 	"errors"
+	"fmt"
+	"time"
 
 	"github.com/Marga-Ghale/ora-scrum-backend/internal/config"
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/db"
 	"github.com/Marga-Ghale/ora-scrum-backend/internal/email"
 	"github.com/Marga-Ghale/ora-scrum-backend/internal/notification"
 	"github.com/Marga-Ghale/ora-scrum-backend/internal/repository"
 	"github.com/Marga-Ghale/ora-scrum-backend/internal/socket"
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/storage"
 )
 
 var (
@@ -15,6 +19,8 @@ var (
 	ErrUserExists         = errors.New("user already exists")
 	ErrUserNotFound       = errors.New("user not found")
 	ErrInvalidToken       = errors.New("invalid token")
+	ErrRefreshTokenExpired = errors.New("refresh token expired")
+	ErrRefreshTokenReused  = errors.New("refresh token reuse detected")
 	ErrNotFound           = errors.New("resource not found")
 	ErrUnauthorized       = errors.New("unauthorized")
 	ErrForbidden          = errors.New("forbidden")
@@ -26,6 +32,28 @@ var (
 	ErrLastOwner          = errors.New("cannot remove or demote the last owner")
 	ErrSprintAlreadyActive = errors.New("another sprint is already active in this project")
 	ErrSprintNoTasks      = errors.New("cannot start sprint with no tasks")
+	ErrWIPLimitExceeded   = errors.New("work-in-progress limit reached for this column")
+	ErrInvalidTransition  = errors.New("invalid status transition")
+	ErrEmailDomainNotAllowed = errors.New("email domain is not allowed for this invitation link")
+	ErrTooManyRequests    = errors.New("too many requests")
+	ErrLimitExceeded      = errors.New("limit exceeded")
+	ErrFileTooLarge       = errors.New("file too large")
+
+	// ErrStaleVersion is a conflict: the task changed since the client read it
+	ErrStaleVersion = fmt.Errorf("%w: task was modified by someone else, reload it and retry", ErrConflict)
+
+	// Not-found errors naming the missing resource; errors.Is(err, ErrNotFound) holds for each
+	ErrTaskNotFound       = fmt.Errorf("%w: task not found", ErrNotFound)
+	ErrCommentNotFound    = fmt.Errorf("%w: comment not found", ErrNotFound)
+	ErrAttachmentNotFound = fmt.Errorf("%w: attachment not found", ErrNotFound)
+	ErrProjectNotFound    = fmt.Errorf("%w: project not found", ErrNotFound)
+	ErrSprintNotFound     = fmt.Errorf("%w: sprint not found", ErrNotFound)
+	ErrWorkspaceNotFound  = fmt.Errorf("%w: workspace not found", ErrNotFound)
+	ErrSpaceNotFound      = fmt.Errorf("%w: space not found", ErrNotFound)
+	ErrFolderNotFound     = fmt.Errorf("%w: folder not found", ErrNotFound)
+	ErrGoalNotFound       = fmt.Errorf("%w: goal not found", ErrNotFound)
+	ErrLabelNotFound      = fmt.Errorf("%w: label not found", ErrNotFound)
+	ErrTeamNotFound       = fmt.Errorf("%w: team not found", ErrNotFound)
 )
 
 // ============================================
@@ -53,6 +81,14 @@ type Services struct {
 	Goal         GoalService
 	SprintAnalytics SprintAnalyticsService
 	Sprint 	 	SprintService
+	RecurringTask RecurringTaskService
+	ProjectStatus ProjectStatusService
+	AccessRequest AccessRequestService
+	Calendar      CalendarService
+	Webhook       WebhookService
+	SCM           SCMService
+	AccessCache   *AccessCache
+	ReadCache     *ReadCache
 }
 
 // ServiceDeps contains all dependencies needed to create services
@@ -62,11 +98,20 @@ type ServiceDeps struct {
 	NotifSvc    *notification.Service
 	EmailSvc    *email.Service
 	Broadcaster *socket.Broadcaster
+	Storage     storage.Storage
+	Redis       *db.RedisDB // optional
 }
 
 
 
 func NewServices(deps *ServiceDeps) *Services {
+	accessCache := NewAccessCache(deps.Redis, time.Duration(deps.Config.AccessCacheTTLSeconds)*time.Second)
+	readCache := NewReadCache(deps.Redis, time.Duration(deps.Config.ReadCacheTTLSeconds)*time.Second)
+
+	// Replaced in place so cron jobs built from the same repositories
+	// invalidate cached boards and stats too
+	deps.Repos.TaskRepo = newInvalidatingTaskRepository(deps.Repos.TaskRepo, readCache)
+
 	// ✅ Create MemberService first (needed by other services)
 	memberService := NewMemberService(
 		deps.Repos.WorkspaceRepo,
@@ -76,6 +121,8 @@ func NewServices(deps *ServiceDeps) *Services {
 		deps.Repos.UserRepo,
 		deps.NotifSvc,
 		deps.Broadcaster,
+		accessCache,
+		readCache,
 	)
 
 	// ✅ Create PermissionService (needed by TaskService)
@@ -86,6 +133,7 @@ func NewServices(deps *ServiceDeps) *Services {
 		deps.Repos.TaskRepo,
 		deps.Repos.TeamRepo,
 		deps.Repos.FolderRepo,
+		deps.Repos.InvitationRepo,
 		memberService,
 	)
 
@@ -99,9 +147,57 @@ func NewServices(deps *ServiceDeps) *Services {
 		deps.Broadcaster,
 	)
 
+	// ✅ CORRECTED TaskService with ALL required repos and services
+	taskService := NewTaskService(
+		deps.Repos.TaskRepo,
+		deps.Repos.TaskCommentRepo,
+		deps.Repos.TaskAttachmentRepo,
+		deps.Repos.TimeEntryRepo,
+		deps.Repos.TaskDependencyRepo,
+		deps.Repos.TaskChecklistRepo,
+		deps.Repos.TaskActivityRepo,
+		deps.Repos.ProjectRepo,
+		deps.Repos.SprintRepo,
+		deps.Repos.UserRepo,
+		deps.Repos.SprintAnalyticsRepo,
+		deps.Repos.SprintCommitmentRepo,
+		deps.Repos.WIPLimitRepo,
+		deps.Repos.ProjectStatusRepo,
+		deps.Repos.LabelRepo,
+		deps.Repos.TaskLabelRepo,
+		deps.Repos.SavedViewRepo,
+		deps.Repos.TaskSettingsRepo,
+		deps.Repos.TaskWatcherRepo,
+		memberService,
+		permissionService,
+		deps.NotifSvc,
+		deps.Broadcaster,
+		goalService, // ✅ FIXED: Pass goalService instead of deps.Repos.GoalRepo
+		deps.Storage,
+		AttachmentLimits{
+			MaxFileSize:    int64(deps.Config.AttachmentMaxSizeMB) << 20,
+			WorkspaceQuota: int64(deps.Config.WorkspaceAttachmentQuotaMB) << 20,
+		},
+		deps.Config.MaxSubtaskDepth,
+		readCache,
+	)
+
+	webhookService := NewWebhookService(
+		deps.Repos.WebhookRepo,
+		deps.Repos.ProjectRepo,
+		deps.Repos.SpaceRepo,
+		memberService,
+		deps.Config.WebhookWorkers,
+		deps.Config.WebhookMaxAttempts,
+	)
+	// Webhooks fire on the same task and sprint events as the WebSocket broadcasts
+	if deps.Broadcaster != nil {
+		deps.Broadcaster.AddListener(webhookService.HandleEvent)
+	}
+
 	return &Services{
 		Auth:      NewAuthService(deps.Config, deps.Repos.UserRepo),
-		User:      NewUserService(deps.Repos.UserRepo),
+		User:      NewUserService(deps.Repos.UserRepo, deps.Repos.WorkspaceRepo, deps.Broadcaster, deps.Storage, accessCache, int64(deps.Config.AvatarMaxSizeMB)<<20),
 		Workspace: NewWorkspaceService(deps.Repos.WorkspaceRepo, deps.Repos.UserRepo, deps.NotifSvc, deps.Broadcaster),
 		Space: NewSpaceService(
 			deps.Repos.SpaceRepo,
@@ -121,30 +217,22 @@ func NewServices(deps *ServiceDeps) *Services {
 			deps.Repos.FolderRepo,
 			memberService,
 			deps.Broadcaster,
-		),
-		// ✅ CORRECTED TaskService with ALL required repos and services
-		Task: NewTaskService(
+			deps.Repos.TemplateRepo,
+			deps.Repos.ProjectStatusRepo,
+			deps.Repos.LabelRepo,
 			deps.Repos.TaskRepo,
-			deps.Repos.TaskCommentRepo,
-			deps.Repos.TaskAttachmentRepo,
-			deps.Repos.TimeEntryRepo,
-			deps.Repos.TaskDependencyRepo,
+			deps.Repos.TaskLabelRepo,
 			deps.Repos.TaskChecklistRepo,
-			deps.Repos.TaskActivityRepo,
-			deps.Repos.ProjectRepo,
-			deps.Repos.SprintRepo,
-			deps.Repos.UserRepo,
-			memberService,
-			permissionService,
-			deps.NotifSvc,
-			deps.Broadcaster,
-			goalService, // ✅ FIXED: Pass goalService instead of deps.Repos.GoalRepo
+			readCache,
 		),
+		Task:          taskService,
+		RecurringTask: NewRecurringTaskService(deps.Repos.RecurringTaskRepo, taskService, memberService, permissionService),
+		ProjectStatus: NewProjectStatusService(deps.Repos.ProjectStatusRepo, deps.Repos.TaskRepo, memberService, permissionService, readCache),
 		Goal:            goalService, // ✅ Use the same goalService instance
 		SprintAnalytics: NewSprintAnalyticsService(deps.Repos.SprintAnalyticsRepo, deps.Repos.SprintRepo, deps.Repos.TaskRepo, deps.Repos.ProjectRepo, deps.Repos.GoalRepo, memberService),
-		Sprint: NewSprintService(deps.Repos.SprintRepo,deps.Repos.ProjectRepo,deps.Repos.TaskRepo,deps.Repos.SprintCommitmentRepo,deps.Repos.GoalRepo, memberService),
-		Label:           NewLabelService(deps.Repos.LabelRepo),
-		Notification:    NewNotificationService(deps.Repos.NotificationRepo),
+		Sprint: NewSprintService(deps.Repos.SprintRepo,deps.Repos.ProjectRepo,deps.Repos.TaskRepo,deps.Repos.SprintCommitmentRepo,deps.Repos.GoalRepo, deps.Repos.SprintCapacityRepo, deps.Repos.TaskSettingsRepo, memberService, deps.Broadcaster),
+		Label:           NewLabelService(deps.Repos.LabelRepo, permissionService, readCache),
+		Notification:    NewNotificationService(deps.Repos.NotificationRepo, deps.Repos.NotificationPreferenceRepo, deps.Broadcaster),
 		Team:            NewTeamService(deps.Repos.TeamRepo, deps.Repos.UserRepo, deps.Repos.WorkspaceRepo, deps.NotifSvc, deps.EmailSvc, deps.Broadcaster),
 		Invitation: NewInvitationService(
 			deps.Repos.InvitationRepo,
@@ -153,12 +241,41 @@ func NewServices(deps *ServiceDeps) *Services {
 			deps.Repos.ProjectRepo,
 			deps.Repos.UserRepo,
 			deps.Repos.SpaceRepo,
+			memberService,
 			deps.EmailSvc,
+			deps.NotifSvc,
+			time.Duration(deps.Config.InvitationReminderIntervalHours)*time.Hour,
+			deps.Config.InvitationMaxReminders,
+			time.Duration(deps.Config.InvitationResendCooldownMinutes)*time.Minute,
+			deps.Config.InvitationPendingLimits,
+		),
+		AccessRequest: NewAccessRequestService(
+			deps.Repos.InvitationRepo,
+			deps.Repos.UserRepo,
+			deps.Repos.WorkspaceRepo,
+			deps.Repos.SpaceRepo,
+			deps.Repos.FolderRepo,
+			deps.Repos.ProjectRepo,
+			memberService,
+			deps.NotifSvc,
+		),
+		Calendar:    NewCalendarService(deps.Repos.CalendarFeedRepo, deps.Repos.TaskRepo, memberService),
+		Webhook:     webhookService,
+		SCM: NewSCMService(
+			deps.Repos.SCMIntegrationRepo,
+			deps.Repos.TaskRepo,
+			deps.Repos.ProjectRepo,
+			deps.Repos.SpaceRepo,
+			deps.Repos.ProjectStatusRepo,
+			taskService,
+			memberService,
 		),
 		Activity:    NewActivityService(deps.Repos.ActivityRepo),
 		Chat:        NewChatService(deps.Repos.ChatRepo, deps.Repos.UserRepo, deps.NotifSvc, deps.Broadcaster),
 		Permission:  permissionService,
 		Member:      memberService,
+		AccessCache: accessCache,
+		ReadCache:   readCache,
 		Broadcaster: deps.Broadcaster,
 	}
 }
\ No newline at end of file
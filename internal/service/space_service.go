@@ -51,7 +51,7 @@ func (s *spaceService) Create(ctx context.Context, workspaceID, creatorID, name
 	// ✅ Verify workspace exists
 	workspace, err := s.workspaceRepo.FindByID(ctx, workspaceID)
 	if err != nil || workspace == nil {
-		return nil, ErrNotFound
+		return nil, ErrWorkspaceNotFound
 	}
 
 	// ✅ Verify creator has access to workspace
@@ -109,7 +109,7 @@ func (s *spaceService) GetByID(ctx context.Context, id string) (*repository.Spac
 		return nil, err
 	}
 	if space == nil {
-		return nil, ErrNotFound
+		return nil, ErrSpaceNotFound
 	}
 	return space, nil
 }
@@ -125,7 +125,7 @@ func (s *spaceService) ListByUser(ctx context.Context, userID string) ([]*reposi
 func (s *spaceService) Update(ctx context.Context, id string, name, description, icon, color, visibility *string, allowedUsers, allowedTeams *[]string) (*repository.Space, error) {
 	space, err := s.spaceRepo.FindByID(ctx, id)
 	if err != nil || space == nil {
-		return nil, ErrNotFound
+		return nil, ErrSpaceNotFound
 	}
 
 	// Update name if provided
@@ -172,7 +172,7 @@ func (s *spaceService) Delete(ctx context.Context, id string) error {
 	// ✅ Get space first to know workspace ID for broadcasting
 	space, err := s.spaceRepo.FindByID(ctx, id)
 	if err != nil || space == nil {
-		return ErrNotFound
+		return ErrSpaceNotFound
 	}
 
 	workspaceID := space.WorkspaceID
@@ -192,7 +192,7 @@ func (s *spaceService) Delete(ctx context.Context, id string) error {
 func (s *spaceService) UpdateVisibility(ctx context.Context, spaceID, visibility string, allowedUsers, allowedTeams []string) error {
 	space, err := s.spaceRepo.FindByID(ctx, spaceID)
 	if err != nil || space == nil {
-		return ErrNotFound
+		return ErrSpaceNotFound
 	}
 
 	space.Visibility = &visibility
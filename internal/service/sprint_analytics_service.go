@@ -21,6 +21,9 @@ type SprintAnalyticsService interface {
 	GetVelocityTrend(ctx context.Context, projectID, userID string, sprintCount int) (*repository.VelocityTrend, error)
 	RecordSprintVelocity(ctx context.Context, sprintID string) error
 
+	// Burndown
+	RecordBurndownSnapshot(ctx context.Context, sprintID string) error
+
 	// Cycle Time
 	GetCycleTimeStats(ctx context.Context, sprintID, userID string) ([]*repository.CycleTimeStats, error)
 	GetProjectCycleTimeAvg(ctx context.Context, projectID, userID string, days int) (*CycleTimeAverage, error)
@@ -139,7 +142,7 @@ func NewSprintAnalyticsService(
 func (s *sprintAnalyticsService) GenerateSprintReport(ctx context.Context, sprintID, userID string) (*repository.SprintReport, error) {
 	sprint, err := s.sprintRepo.FindByID(ctx, sprintID)
 	if err != nil || sprint == nil {
-		return nil, ErrNotFound
+		return nil, ErrSprintNotFound
 	}
 
 	hasAccess, _, err := s.memberService.HasEffectiveAccess(ctx, EntityTypeProject, sprint.ProjectID, userID)
@@ -163,7 +166,7 @@ func (s *sprintAnalyticsService) GenerateSprintReport(ctx context.Context, sprin
 func (s *sprintAnalyticsService) GetSprintReport(ctx context.Context, sprintID, userID string) (*repository.SprintReport, error) {
 	sprint, err := s.sprintRepo.FindByID(ctx, sprintID)
 	if err != nil || sprint == nil {
-		return nil, ErrNotFound
+		return nil, ErrSprintNotFound
 	}
 
 	hasAccess, _, err := s.memberService.HasEffectiveAccess(ctx, EntityTypeProject, sprint.ProjectID, userID)
@@ -218,7 +221,7 @@ func (s *sprintAnalyticsService) GetVelocityTrend(ctx context.Context, projectID
 func (s *sprintAnalyticsService) RecordSprintVelocity(ctx context.Context, sprintID string) error {
 	sprint, err := s.sprintRepo.FindByID(ctx, sprintID)
 	if err != nil || sprint == nil {
-		return ErrNotFound
+		return ErrSprintNotFound
 	}
 
 	// Get velocity data
@@ -243,6 +246,31 @@ func (s *sprintAnalyticsService) RecordSprintVelocity(ctx context.Context, sprin
 	return s.analyticsRepo.SaveVelocityHistory(ctx, vh)
 }
 
+// RecordBurndownSnapshot stores today's remaining story points for a sprint
+func (s *sprintAnalyticsService) RecordBurndownSnapshot(ctx context.Context, sprintID string) error {
+	totalPoints, err := s.taskRepo.GetSprintVelocity(ctx, sprintID)
+	if err != nil {
+		return err
+	}
+	completedPoints, err := s.taskRepo.GetCompletedStoryPoints(ctx, sprintID)
+	if err != nil {
+		return err
+	}
+
+	remaining := totalPoints - completedPoints
+	if remaining < 0 {
+		remaining = 0
+	}
+
+	return s.analyticsRepo.SaveBurndownSnapshot(ctx, &repository.BurndownSnapshot{
+		SprintID:        sprintID,
+		SnapshotDate:    time.Now(),
+		TotalPoints:     totalPoints,
+		CompletedPoints: completedPoints,
+		RemainingPoints: remaining,
+	})
+}
+
 // ============================================
 // CYCLE TIME
 // ============================================
@@ -250,7 +278,7 @@ func (s *sprintAnalyticsService) RecordSprintVelocity(ctx context.Context, sprin
 func (s *sprintAnalyticsService) GetCycleTimeStats(ctx context.Context, sprintID, userID string) ([]*repository.CycleTimeStats, error) {
 	sprint, err := s.sprintRepo.FindByID(ctx, sprintID)
 	if err != nil || sprint == nil {
-		return nil, ErrNotFound
+		return nil, ErrSprintNotFound
 	}
 
 	hasAccess, _, err := s.memberService.HasEffectiveAccess(ctx, EntityTypeProject, sprint.ProjectID, userID)
@@ -292,7 +320,7 @@ func (s *sprintAnalyticsService) GetProjectCycleTimeAvg(ctx context.Context, pro
 func (s *sprintAnalyticsService) GetTaskStatusHistory(ctx context.Context, taskID, userID string) ([]*repository.TaskStatusHistory, error) {
 	task, err := s.taskRepo.FindByID(ctx, taskID)
 	if err != nil || task == nil {
-		return nil, ErrNotFound
+		return nil, ErrTaskNotFound
 	}
 
 	hasAccess, _, err := s.memberService.HasEffectiveAccess(ctx, EntityTypeProject, task.ProjectID, userID)
@@ -323,7 +351,7 @@ func (s *sprintAnalyticsService) GetGanttData(ctx context.Context, projectID, us
 func (s *sprintAnalyticsService) GetSprintAnalyticsDashboard(ctx context.Context, sprintID, userID string) (*SprintAnalyticsDashboard, error) {
 	sprint, err := s.sprintRepo.FindByID(ctx, sprintID)
 	if err != nil || sprint == nil {
-		return nil, ErrNotFound
+		return nil, ErrSprintNotFound
 	}
 
 	hasAccess, _, err := s.memberService.HasEffectiveAccess(ctx, EntityTypeProject, sprint.ProjectID, userID)
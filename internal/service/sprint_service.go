@@ -3,10 +3,14 @@ package service
 
 import (
 	"context"
-	"log"
+	"fmt"
+	"log/slog"
+	"math"
+	"sort"
 	"time"
 
 	"github.com/Marga-Ghale/ora-scrum-backend/internal/repository"
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/socket"
 )
 
 type SprintService interface {
@@ -19,7 +23,16 @@ type SprintService interface {
 	StartSprint(ctx context.Context, sprintID, userID string) (*SprintStartResponse, error)
 	CompleteSprint(ctx context.Context, sprintID, userID string) error
 	CompleteSprintWithOptions(ctx context.Context, sprintID, userID string, options *SprintCompleteOptions) (*SprintCompleteResponse, error)
+	// CompleteExpiredSprint closes an active sprint whose end date has passed,
+	// on behalf of no user. It returns nil when the sprint is not both.
+	CompleteExpiredSprint(ctx context.Context, sprintID string) (*SprintCompleteResponse, error)
 	GetSprintSummary(ctx context.Context, sprintID, userID string) (*SprintSummary, error)
+	GetSprintReport(ctx context.Context, sprintID, userID string) (*SprintReport, error)
+
+	// Capacity planning
+	GetCapacity(ctx context.Context, sprintID, userID, multiAssignee string) (*SprintCapacityPlan, error)
+	SetMemberCapacity(ctx context.Context, sprintID, memberID string, hours float64, userID string) (*repository.SprintCapacity, error)
+	RemoveMemberCapacity(ctx context.Context, sprintID, memberID, userID string) error
 }
 
 // New types for sprint operations
@@ -60,14 +73,48 @@ type SprintSummary struct {
 	DaysElapsed      int    `json:"daysElapsed"`
 }
 
+// SprintReport is the close-out report of a sprint: the commitment taken at
+// start, what got done, the scope added and removed mid-sprint and the
+// unfinished work carried over. Only parent tasks are counted.
+type SprintReport struct {
+	SprintID          string             `json:"sprintId"`
+	SprintName        string             `json:"sprintName"`
+	Status            string             `json:"status"`
+	StartDate         time.Time          `json:"startDate"`
+	EndDate           time.Time          `json:"endDate"`
+	StartedAt         *time.Time         `json:"startedAt,omitempty"`
+	CommittedTasks    int                `json:"committedTasks"`
+	CommittedPoints   int                `json:"committedPoints"`
+	CompletedPoints   int                `json:"completedPoints"`
+	AddedPoints       int                `json:"addedPoints"`
+	RemovedPoints     int                `json:"removedPoints"`
+	CarriedOverPoints int                `json:"carriedOverPoints"`
+	Completed         []SprintReportTask `json:"completed"`
+	Incomplete        []SprintReportTask `json:"incomplete"` // unfinished work of a sprint that is still open
+	Added             []SprintReportTask `json:"added"`
+	Removed           []SprintReportTask `json:"removed"`
+	CarriedOver       []SprintReportTask `json:"carriedOver"`
+}
+
+type SprintReportTask struct {
+	ID          string     `json:"id"`
+	Key         string     `json:"key"`
+	Title       string     `json:"title"`
+	Status      string     `json:"status"`
+	StoryPoints int        `json:"storyPoints"`
+	ChangedAt   *time.Time `json:"changedAt,omitempty"` // when the task was added, removed or carried over
+}
+
 type sprintService struct {
 	sprintRepo     repository.SprintRepository
 	projectRepo    repository.ProjectRepository
 	taskRepo       repository.TaskRepository
 	commitmentRepo repository.SprintCommitmentRepository
 	goalRepo       repository.GoalRepository  
+	capacityRepo   repository.SprintCapacityRepository
+	settingsRepo   repository.TaskSettingsRepository
 	memberSvc      MemberService
-
+	broadcaster    *socket.Broadcaster
 }
 
 func NewSprintService(
@@ -76,7 +123,10 @@ func NewSprintService(
 	taskRepo repository.TaskRepository,
 	commitmentRepo repository.SprintCommitmentRepository,
 	goalRepo repository.GoalRepository,  
+	capacityRepo repository.SprintCapacityRepository,
+	settingsRepo repository.TaskSettingsRepository,
 	memberSvc MemberService,
+	broadcaster *socket.Broadcaster,
 ) SprintService {
 	return &sprintService{
 		sprintRepo:     sprintRepo,
@@ -84,7 +134,10 @@ func NewSprintService(
 		taskRepo:       taskRepo,
 		commitmentRepo: commitmentRepo,
 		goalRepo:       goalRepo, 
+		capacityRepo:   capacityRepo,
+		settingsRepo:   settingsRepo,
 		memberSvc:      memberSvc,
+		broadcaster:    broadcaster,
 	}
 }
 
@@ -94,14 +147,34 @@ func (s *sprintService) Create(ctx context.Context, sprint *repository.Sprint, u
 		return ErrUnauthorized
 	}
 
+	// Only one sprint per project may be active
+	if sprint.Status == "active" {
+		if err := s.ensureNoOtherActiveSprint(ctx, sprint.ProjectID, ""); err != nil {
+			return err
+		}
+	}
+
 	sprint.CreatedBy = userID
 	return s.sprintRepo.Create(ctx, sprint)
 }
 
+// ensureNoOtherActiveSprint returns ErrSprintAlreadyActive when a sprint other than
+// exceptSprintID is already active in the project
+func (s *sprintService) ensureNoOtherActiveSprint(ctx context.Context, projectID, exceptSprintID string) error {
+	activeSprint, err := s.sprintRepo.FindActiveSprint(ctx, projectID)
+	if err != nil {
+		return err
+	}
+	if activeSprint != nil && activeSprint.ID != exceptSprintID {
+		return ErrSprintAlreadyActive
+	}
+	return nil
+}
+
 func (s *sprintService) Get(ctx context.Context, sprintID, userID string) (*repository.Sprint, error) {
 	sprint, err := s.sprintRepo.FindByID(ctx, sprintID)
 	if err != nil || sprint == nil {
-		return nil, ErrNotFound
+		return nil, ErrSprintNotFound
 	}
 
 	hasAccess, _, err := s.memberSvc.HasEffectiveAccess(ctx, EntityTypeProject, sprint.ProjectID, userID)
@@ -138,7 +211,7 @@ func (s *sprintService) Update(
 
 	existing, err := s.sprintRepo.FindByID(ctx, sprint.ID)
 	if err != nil || existing == nil {
-		return ErrNotFound
+		return ErrSprintNotFound
 	}
 
 	hasAccess, _, err := s.memberSvc.HasEffectiveAccess(
@@ -172,6 +245,11 @@ func (s *sprintService) Update(
 
 	// ✅ Status
 	if sprint.Status != "" {
+		if sprint.Status == "active" && existing.Status != "active" {
+			if err := s.ensureNoOtherActiveSprint(ctx, existing.ProjectID, existing.ID); err != nil {
+				return err
+			}
+		}
 		existing.Status = sprint.Status
 	}
 
@@ -182,7 +260,7 @@ func (s *sprintService) Update(
 func (s *sprintService) Delete(ctx context.Context, sprintID, userID string) error {
 	sprint, err := s.sprintRepo.FindByID(ctx, sprintID)
 	if err != nil || sprint == nil {
-		return ErrNotFound
+		return ErrSprintNotFound
 	}
 
 	hasAccess, _, err := s.memberSvc.HasEffectiveAccess(ctx, EntityTypeProject, sprint.ProjectID, userID)
@@ -197,7 +275,7 @@ func (s *sprintService) Delete(ctx context.Context, sprintID, userID string) err
 func (s *sprintService) StartSprint(ctx context.Context, sprintID, userID string) (*SprintStartResponse, error) {
 	sprint, err := s.sprintRepo.FindByID(ctx, sprintID)
 	if err != nil || sprint == nil {
-		return nil, ErrNotFound
+		return nil, ErrSprintNotFound
 	}
 
 	hasAccess, _, err := s.memberSvc.HasEffectiveAccess(ctx, EntityTypeProject, sprint.ProjectID, userID)
@@ -206,13 +284,9 @@ func (s *sprintService) StartSprint(ctx context.Context, sprintID, userID string
 	}
 
 	// Check if another sprint is already active
-	activeSprint, err := s.sprintRepo.FindActiveSprint(ctx, sprint.ProjectID)
-	if err != nil {
+	if err := s.ensureNoOtherActiveSprint(ctx, sprint.ProjectID, sprintID); err != nil {
 		return nil, err
 	}
-	if activeSprint != nil && activeSprint.ID != sprintID {
-		return nil, ErrSprintAlreadyActive
-	}
 
 	// Get tasks in this sprint for commitment snapshot
 	tasks, err := s.taskRepo.FindBySprintID(ctx, sprintID)
@@ -243,7 +317,7 @@ func (s *sprintService) StartSprint(ctx context.Context, sprintID, userID string
 		TaskIDs:         taskIDs,
 	}
 	if err := s.commitmentRepo.SaveCommitment(ctx, commitment); err != nil {
-		log.Printf("⚠️ Failed to save commitment snapshot: %v", err)
+		slog.WarnContext(ctx, "failed to save sprint commitment snapshot", "sprintID", sprintID, "error", err)
 	}
 
 	// Check if over-committing (compare with average velocity)
@@ -280,7 +354,7 @@ func (s *sprintService) CompleteSprint(ctx context.Context, sprintID, userID str
 func (s *sprintService) CompleteSprintWithOptions(ctx context.Context, sprintID, userID string, options *SprintCompleteOptions) (*SprintCompleteResponse, error) {
 	sprint, err := s.sprintRepo.FindByID(ctx, sprintID)
 	if err != nil || sprint == nil {
-		return nil, ErrNotFound
+		return nil, ErrSprintNotFound
 	}
 
 	hasAccess, _, err := s.memberSvc.HasEffectiveAccess(ctx, EntityTypeProject, sprint.ProjectID, userID)
@@ -288,6 +362,71 @@ func (s *sprintService) CompleteSprintWithOptions(ctx context.Context, sprintID,
 		return nil, ErrUnauthorized
 	}
 
+	return s.completeSprint(ctx, sprint, options, &userID)
+}
+
+// CompleteExpiredSprint is used by the scheduler. The sprint is claimed with
+// a conditional update first, so one completed by hand or by another run in
+// the meantime is left alone and nil is returned. Unfinished tasks go where
+// the project's sprintCarryOver setting says.
+func (s *sprintService) CompleteExpiredSprint(ctx context.Context, sprintID string) (*SprintCompleteResponse, error) {
+	sprint, err := s.sprintRepo.FindByID(ctx, sprintID)
+	if err != nil {
+		return nil, err
+	}
+	if sprint == nil {
+		return nil, nil
+	}
+
+	carryOver := repository.SprintCarryOverNextSprint
+	if s.settingsRepo != nil {
+		settings, err := s.settingsRepo.Get(ctx, sprint.ProjectID)
+		if err != nil {
+			return nil, err
+		}
+		carryOver = settings.SprintCarryOver
+	}
+
+	claimed, err := s.sprintRepo.CompleteIfExpired(ctx, sprintID)
+	if err != nil || !claimed {
+		return nil, err
+	}
+
+	result, err := s.completeSprint(ctx, sprint, &SprintCompleteOptions{MoveIncompleteTo: carryOver}, nil)
+	if err != nil {
+		// Hand the sprint back so the next run retries it
+		if rerr := s.sprintRepo.UpdateStatus(ctx, sprintID, "active"); rerr != nil {
+			slog.WarnContext(ctx, "failed to reopen sprint after failed completion", "sprintID", sprintID, "error", rerr)
+		}
+		return nil, err
+	}
+	return result, nil
+}
+
+// completeSprint moves the sprint's unfinished tasks as the options say and
+// marks it completed. changedBy is nil when no user asked for it.
+func (s *sprintService) completeSprint(ctx context.Context, sprint *repository.Sprint, options *SprintCompleteOptions, changedBy *string) (*SprintCompleteResponse, error) {
+	sprintID := sprint.ID
+
+	// Unfinished work goes back to the backlog unless told otherwise
+	if options == nil {
+		options = &SprintCompleteOptions{}
+	}
+	if options.MoveIncompleteTo == "" {
+		options.MoveIncompleteTo = "backlog"
+	}
+
+	// Validate an explicit target sprint before touching anything
+	if options.MoveIncompleteTo != "backlog" && options.MoveIncompleteTo != "next_sprint" {
+		targetSprint, err := s.sprintRepo.FindByID(ctx, options.MoveIncompleteTo)
+		if err != nil || targetSprint == nil {
+			return nil, ErrSprintNotFound
+		}
+		if targetSprint.ProjectID != sprint.ProjectID || targetSprint.ID == sprintID || targetSprint.Status == "completed" {
+			return nil, ErrInvalidInput
+		}
+	}
+
 	// Get all tasks in sprint
 	tasks, err := s.taskRepo.FindBySprintID(ctx, sprintID)
 	if err != nil {
@@ -297,6 +436,7 @@ func (s *sprintService) CompleteSprintWithOptions(ctx context.Context, sprintID,
 	var completedTasks, completedPoints int
 	var incompleteTasks, incompletePoints int
 	var incompleteTaskIDs []string
+	incompletePointsByTask := make(map[string]int)
 
 	for _, task := range tasks {
 		if task.ParentTaskID != nil {
@@ -315,12 +455,13 @@ func (s *sprintService) CompleteSprintWithOptions(ctx context.Context, sprintID,
 			incompleteTasks++
 			incompletePoints += points
 			incompleteTaskIDs = append(incompleteTaskIDs, task.ID)
+			incompletePointsByTask[task.ID] = points
 		}
 	}
 
 	// Handle incomplete tasks based on option
-	var movedTo string
-	if len(incompleteTaskIDs) > 0 && options != nil {
+	var movedTo, movedToSprintID string
+	if len(incompleteTaskIDs) > 0 {
 		switch options.MoveIncompleteTo {
 		case "backlog":
 			// Move to backlog (set sprint_id to NULL)
@@ -346,6 +487,7 @@ func (s *sprintService) CompleteSprintWithOptions(ctx context.Context, sprintID,
 			if nextSprint != nil {
 				s.taskRepo.BulkMoveToSprint(ctx, incompleteTaskIDs, nextSprint.ID)
 				movedTo = nextSprint.Name
+				movedToSprintID = nextSprint.ID
 			} else {
 				// No next sprint, move to backlog
 				for _, taskID := range incompleteTaskIDs {
@@ -365,11 +507,14 @@ func (s *sprintService) CompleteSprintWithOptions(ctx context.Context, sprintID,
 				if targetSprint != nil {
 					s.taskRepo.BulkMoveToSprint(ctx, incompleteTaskIDs, options.MoveIncompleteTo)
 					movedTo = targetSprint.Name
+					movedToSprintID = targetSprint.ID
 				}
 			}
 		}
 	}
 
+	s.recordCarryOver(ctx, sprintID, movedToSprintID, incompleteTaskIDs, incompletePointsByTask, changedBy)
+
 	// Complete the sprint
 	if err := s.sprintRepo.UpdateStatus(ctx, sprintID, "completed"); err != nil {
 		return nil, err
@@ -381,6 +526,16 @@ func (s *sprintService) CompleteSprintWithOptions(ctx context.Context, sprintID,
 	// Refresh sprint data
 	sprint, _ = s.sprintRepo.FindByID(ctx, sprintID)
 
+	if s.broadcaster != nil && sprint != nil {
+		s.broadcaster.BroadcastSprintCompleted(sprint.ProjectID, sprintToMap(sprint), map[string]interface{}{
+			"completedTasks":   completedTasks,
+			"completedPoints":  completedPoints,
+			"incompleteTasks":  incompleteTasks,
+			"incompletePoints": incompletePoints,
+			"tasksMovedTo":     movedTo,
+		})
+	}
+
 	return &SprintCompleteResponse{
 		Sprint:           sprint,
 		CompletedTasks:   completedTasks,
@@ -395,7 +550,7 @@ func (s *sprintService) CompleteSprintWithOptions(ctx context.Context, sprintID,
 func (s *sprintService) GetSprintSummary(ctx context.Context, sprintID, userID string) (*SprintSummary, error) {
 	sprint, err := s.sprintRepo.FindByID(ctx, sprintID)
 	if err != nil || sprint == nil {
-		return nil, ErrNotFound
+		return nil, ErrSprintNotFound
 	}
 
 	hasAccess, _, err := s.memberSvc.HasEffectiveAccess(ctx, EntityTypeProject, sprint.ProjectID, userID)
@@ -469,6 +624,175 @@ func (s *sprintService) GetSprintSummary(ctx context.Context, sprintID, userID s
 
 
 
+// GetSprintReport builds the sprint report from the commitment snapshot taken
+// at start and the sprint membership history. Tasks that entered the sprint
+// after it started count as added, even if they later left it again.
+func (s *sprintService) GetSprintReport(ctx context.Context, sprintID, userID string) (*SprintReport, error) {
+	sprint, err := s.sprintRepo.FindByID(ctx, sprintID)
+	if err != nil || sprint == nil {
+		return nil, ErrSprintNotFound
+	}
+
+	hasAccess, _, err := s.memberSvc.HasEffectiveAccess(ctx, EntityTypeProject, sprint.ProjectID, userID)
+	if err != nil || !hasAccess {
+		return nil, ErrUnauthorized
+	}
+
+	commitment, err := s.commitmentRepo.GetCommitment(ctx, sprintID)
+	if err != nil {
+		return nil, err
+	}
+	history, err := s.commitmentRepo.GetMembershipHistory(ctx, sprintID)
+	if err != nil {
+		return nil, err
+	}
+	tasks, err := s.taskRepo.FindBySprintID(ctx, sprintID)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &SprintReport{
+		SprintID:    sprintID,
+		SprintName:  sprint.Name,
+		Status:      sprint.Status,
+		StartDate:   sprint.StartDate,
+		EndDate:     sprint.EndDate,
+		Completed:   []SprintReportTask{},
+		Incomplete:  []SprintReportTask{},
+		Added:       []SprintReportTask{},
+		Removed:     []SprintReportTask{},
+		CarriedOver: []SprintReportTask{},
+	}
+
+	byID := make(map[string]*repository.Task)
+	for _, task := range tasks {
+		if task.ParentTaskID != nil {
+			continue
+		}
+		byID[task.ID] = task
+		if task.Status == "done" {
+			report.Completed = append(report.Completed, sprintReportTask(task, nil))
+			report.CompletedPoints += taskPoints(task)
+		} else {
+			report.Incomplete = append(report.Incomplete, sprintReportTask(task, nil))
+		}
+	}
+
+	// A sprint that never started has no commitment and no mid-sprint changes
+	if commitment == nil {
+		return report, nil
+	}
+	report.StartedAt = &commitment.SnapshotAt
+	report.CommittedTasks = commitment.CommittedTasks
+	report.CommittedPoints = commitment.CommittedPoints
+
+	committed := make(map[string]bool, len(commitment.TaskIDs))
+	for _, id := range commitment.TaskIDs {
+		committed[id] = true
+	}
+
+	// The last change of each task decides whether it ended up removed or
+	// carried over; any addition after the start counts as added scope
+	var order []string
+	last := make(map[string]*repository.SprintMembershipChange)
+	addedAt := make(map[string]time.Time)
+	for _, change := range history {
+		if change.ChangedAt.Before(commitment.SnapshotAt) {
+			continue
+		}
+		if _, seen := last[change.TaskID]; !seen {
+			order = append(order, change.TaskID)
+		}
+		last[change.TaskID] = change
+		if change.Action == repository.SprintMembershipAdded && !committed[change.TaskID] {
+			if _, ok := addedAt[change.TaskID]; !ok {
+				addedAt[change.TaskID] = change.ChangedAt
+			}
+		}
+	}
+
+	for _, taskID := range order {
+		task := byID[taskID]
+		if task == nil {
+			task, err = s.taskRepo.FindByID(ctx, taskID)
+			if err != nil {
+				return nil, err
+			}
+			if task == nil || task.ParentTaskID != nil {
+				continue
+			}
+		}
+
+		if at, ok := addedAt[taskID]; ok {
+			report.Added = append(report.Added, sprintReportTask(task, &at))
+			report.AddedPoints += taskPoints(task)
+		}
+
+		change := last[taskID]
+		switch {
+		case change.Action == repository.SprintMembershipCarriedOver:
+			report.CarriedOver = append(report.CarriedOver, sprintReportTask(task, &change.ChangedAt))
+			report.CarriedOverPoints += taskPoints(task)
+		case change.Action == repository.SprintMembershipRemoved && byID[taskID] == nil:
+			report.Removed = append(report.Removed, sprintReportTask(task, &change.ChangedAt))
+			report.RemovedPoints += taskPoints(task)
+		}
+	}
+
+	return report, nil
+}
+
+func sprintReportTask(task *repository.Task, changedAt *time.Time) SprintReportTask {
+	return SprintReportTask{
+		ID:          task.ID,
+		Key:         task.Key,
+		Title:       task.Title,
+		Status:      task.Status,
+		StoryPoints: taskPoints(task),
+		ChangedAt:   changedAt,
+	}
+}
+
+func taskPoints(task *repository.Task) int {
+	if task.StoryPoints == nil {
+		return 0
+	}
+	return *task.StoryPoints
+}
+
+func sprintToMap(sprint *repository.Sprint) map[string]interface{} {
+	return map[string]interface{}{
+		"id":        sprint.ID,
+		"projectId": sprint.ProjectID,
+		"name":      sprint.Name,
+		"goal":      sprint.Goal,
+		"status":    sprint.Status,
+		"startDate": sprint.StartDate,
+		"endDate":   sprint.EndDate,
+	}
+}
+
+// recordCarryOver writes the membership history of unfinished tasks leaving
+// a completed sprint, and their addition to the sprint they moved to, if any
+func (s *sprintService) recordCarryOver(ctx context.Context, sprintID, targetSprintID string, taskIDs []string, points map[string]int, changedBy *string) {
+	var changes []*repository.SprintMembershipChange
+	for _, taskID := range taskIDs {
+		changes = append(changes, &repository.SprintMembershipChange{
+			SprintID: sprintID, TaskID: taskID, Action: repository.SprintMembershipCarriedOver,
+			StoryPoints: points[taskID], ChangedBy: changedBy,
+		})
+		if targetSprintID != "" {
+			changes = append(changes, &repository.SprintMembershipChange{
+				SprintID: targetSprintID, TaskID: taskID, Action: repository.SprintMembershipAdded,
+				StoryPoints: points[taskID], ChangedBy: changedBy,
+			})
+		}
+	}
+	if err := s.commitmentRepo.RecordMembershipChanges(ctx, changes); err != nil {
+		slog.WarnContext(ctx, "failed to record sprint carry-over", "sprintID", sprintID, "error", err)
+	}
+}
+
 func (s *sprintService) updateSprintGoalsStatus(ctx context.Context, sprintID string) {
 	if s.goalRepo == nil {
 		return
@@ -476,7 +800,7 @@ func (s *sprintService) updateSprintGoalsStatus(ctx context.Context, sprintID st
 
 	goals, err := s.goalRepo.FindBySprint(ctx, sprintID)
 	if err != nil {
-		log.Printf("⚠️ Failed to get sprint goals: %v", err)
+		slog.WarnContext(ctx, "failed to load sprint goals", "sprintID", sprintID, "error", err)
 		return
 	}
 
@@ -494,9 +818,198 @@ func (s *sprintService) updateSprintGoalsStatus(ctx context.Context, sprintID st
 
 		// Update goal status
 		if err := s.goalRepo.UpdateStatus(ctx, goal.ID, newStatus); err != nil {
-			log.Printf("⚠️ Failed to update goal %s status: %v", goal.ID, err)
+			slog.WarnContext(ctx, "failed to update goal status", "goalID", goal.ID, "error", err)
 		} else {
-			log.Printf("✅ Goal '%s' marked as %s (%.0f%% complete)", goal.Title, newStatus, goal.Progress)
+			slog.InfoContext(ctx, "goal status updated", "goalID", goal.ID, "status", newStatus, "progress", goal.Progress)
+		}
+	}
+}
+
+// ============================================
+// CAPACITY PLANNING
+// ============================================
+
+// How the estimate of a task with several assignees counts against them
+const (
+	CapacitySplit = "split" // divided evenly between the assignees
+	CapacityFull  = "full"  // charged in full to each assignee
+)
+
+// SprintCapacityPlan compares the estimated hours of the sprint's tasks with
+// the hours each member has available
+type SprintCapacityPlan struct {
+	SprintID         string                `json:"sprintId"`
+	MultiAssignee    string                `json:"multiAssignee"`
+	Members          []*MemberCapacityPlan `json:"members"`
+	AvailableHours   float64               `json:"availableHours"`
+	CommittedHours   float64               `json:"committedHours"`
+	UnassignedHours  float64               `json:"unassignedHours"`  // estimates of tasks nobody is assigned to
+	UnestimatedTasks int                   `json:"unestimatedTasks"` // tasks without estimated hours
+	OverAllocated    bool                  `json:"overAllocated"`    // at least one member is
+}
+
+// MemberCapacityPlan is one member's line of the plan. Available and
+// remaining hours are null for members whose capacity was not set, and
+// remaining hours are negative when the member is over-allocated.
+type MemberCapacityPlan struct {
+	UserID         string   `json:"userId"`
+	AvailableHours *float64 `json:"availableHours"`
+	CommittedHours float64  `json:"committedHours"`
+	RemainingHours *float64 `json:"remainingHours"`
+	Tasks          int      `json:"tasks"`
+	OverAllocated  bool     `json:"overAllocated"`
+}
+
+// GetCapacity sums the estimated hours of the sprint's tasks per assignee and
+// compares them with the members' available hours. multiAssignee is
+// CapacitySplit or CapacityFull; empty means split.
+func (s *sprintService) GetCapacity(ctx context.Context, sprintID, userID, multiAssignee string) (*SprintCapacityPlan, error) {
+	if multiAssignee == "" {
+		multiAssignee = CapacitySplit
+	}
+	if multiAssignee != CapacitySplit && multiAssignee != CapacityFull {
+		return nil, fmt.Errorf("%w: multiAssignee must be %s or %s", ErrInvalidInput, CapacitySplit, CapacityFull)
+	}
+
+	sprint, err := s.sprintRepo.FindByID(ctx, sprintID)
+	if err != nil || sprint == nil {
+		return nil, ErrSprintNotFound
+	}
+
+	hasAccess, _, err := s.memberSvc.HasEffectiveAccess(ctx, EntityTypeProject, sprint.ProjectID, userID)
+	if err != nil || !hasAccess {
+		return nil, ErrUnauthorized
+	}
+
+	capacities, err := s.capacityRepo.FindBySprintID(ctx, sprintID)
+	if err != nil {
+		return nil, err
+	}
+	tasks, err := s.taskRepo.FindBySprintID(ctx, sprintID)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &SprintCapacityPlan{SprintID: sprintID, MultiAssignee: multiAssignee, Members: []*MemberCapacityPlan{}}
+	members := make(map[string]*MemberCapacityPlan)
+	member := func(id string) *MemberCapacityPlan {
+		m, ok := members[id]
+		if !ok {
+			m = &MemberCapacityPlan{UserID: id}
+			members[id] = m
+			plan.Members = append(plan.Members, m)
 		}
+		return m
+	}
+
+	for _, c := range capacities {
+		hours := c.HoursAvailable
+		member(c.UserID).AvailableHours = &hours
+		plan.AvailableHours += hours
 	}
-}
\ No newline at end of file
+
+	for _, task := range tasks {
+		if task.EstimatedHours == nil {
+			plan.UnestimatedTasks++
+		}
+		estimate := 0.0
+		if task.EstimatedHours != nil {
+			estimate = *task.EstimatedHours
+		}
+		plan.CommittedHours += estimate
+
+		if len(task.AssigneeIDs) == 0 {
+			plan.UnassignedHours += estimate
+			continue
+		}
+		share := estimate
+		if multiAssignee == CapacitySplit {
+			share = estimate / float64(len(task.AssigneeIDs))
+		}
+		for _, assigneeID := range task.AssigneeIDs {
+			m := member(assigneeID)
+			m.CommittedHours += share
+			m.Tasks++
+		}
+	}
+
+	for _, m := range plan.Members {
+		m.CommittedHours = roundHours(m.CommittedHours)
+		if m.AvailableHours != nil {
+			remaining := roundHours(*m.AvailableHours - m.CommittedHours)
+			m.RemainingHours = &remaining
+			m.OverAllocated = remaining < 0
+			plan.OverAllocated = plan.OverAllocated || m.OverAllocated
+		}
+	}
+	sort.Slice(plan.Members, func(i, j int) bool { return plan.Members[i].UserID < plan.Members[j].UserID })
+
+	plan.AvailableHours = roundHours(plan.AvailableHours)
+	plan.CommittedHours = roundHours(plan.CommittedHours)
+	plan.UnassignedHours = roundHours(plan.UnassignedHours)
+	return plan, nil
+}
+
+// SetMemberCapacity records how many hours a member of the sprint's project
+// can work during the sprint
+func (s *sprintService) SetMemberCapacity(ctx context.Context, sprintID, memberID string, hours float64, userID string) (*repository.SprintCapacity, error) {
+	if hours < 0 || math.IsNaN(hours) || math.IsInf(hours, 0) {
+		return nil, fmt.Errorf("%w: available hours must be zero or more", ErrInvalidInput)
+	}
+
+	sprint, err := s.capacitySprint(ctx, sprintID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	isMember, _, err := s.memberSvc.HasEffectiveAccess(ctx, EntityTypeProject, sprint.ProjectID, memberID)
+	if err != nil {
+		return nil, err
+	}
+	if !isMember {
+		return nil, fmt.Errorf("%w: the user is not a member of the sprint's project", ErrInvalidInput)
+	}
+
+	capacity := &repository.SprintCapacity{
+		SprintID:       sprintID,
+		UserID:         memberID,
+		HoursAvailable: roundHours(hours),
+		UpdatedBy:      &userID,
+	}
+	if err := s.capacityRepo.Set(ctx, capacity); err != nil {
+		return nil, err
+	}
+	return capacity, nil
+}
+
+// RemoveMemberCapacity clears a member's capacity for the sprint
+func (s *sprintService) RemoveMemberCapacity(ctx context.Context, sprintID, memberID, userID string) error {
+	if _, err := s.capacitySprint(ctx, sprintID, userID); err != nil {
+		return err
+	}
+	return s.capacityRepo.Delete(ctx, sprintID, memberID)
+}
+
+// capacitySprint loads a sprint whose capacity the user is changing. The
+// capacity of a completed sprint is history and can't be changed.
+func (s *sprintService) capacitySprint(ctx context.Context, sprintID, userID string) (*repository.Sprint, error) {
+	sprint, err := s.sprintRepo.FindByID(ctx, sprintID)
+	if err != nil || sprint == nil {
+		return nil, ErrSprintNotFound
+	}
+
+	hasAccess, _, err := s.memberSvc.HasEffectiveAccess(ctx, EntityTypeProject, sprint.ProjectID, userID)
+	if err != nil || !hasAccess {
+		return nil, ErrUnauthorized
+	}
+
+	if sprint.Status == "completed" {
+		return nil, fmt.Errorf("%w: the sprint is completed", ErrInvalidInput)
+	}
+	return sprint, nil
+}
+
+// roundHours rounds to hundredths, as hours are stored
+func roundHours(hours float64) float64 {
+	return math.Round(hours*100) / 100
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/models"
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/repository"
+)
+
+type fieldActivityRepo struct {
+	repository.TaskActivityRepository
+	rows []repository.TaskActivity
+}
+
+func (r *fieldActivityRepo) Create(_ context.Context, a *repository.TaskActivity) error {
+	r.rows = append(r.rows, *a)
+	return nil
+}
+
+func TestUpdateLogsOneActivityPerChangedField(t *testing.T) {
+	svc, _ := newVersionFixture()
+	activities := &fieldActivityRepo{}
+	svc.activityRepo = activities
+
+	read := 1
+	title, priority := "Renamed", "high"
+	if _, err := svc.Update(context.Background(), "t1", "user-1", &models.UpdateTaskRequest{
+		Title: &title, Priority: &priority, Version: &read,
+	}); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+
+	if len(activities.rows) != 2 {
+		t.Fatalf("got %d activity rows, want 2: %+v", len(activities.rows), activities.rows)
+	}
+	want := map[string][2]string{
+		"title":    {"Original", "Renamed"},
+		"priority": {"medium", "high"},
+	}
+	for _, row := range activities.rows {
+		if row.Action != "updated" || row.FieldName == nil {
+			t.Fatalf("unexpected activity %+v", row)
+		}
+		values, ok := want[*row.FieldName]
+		if !ok {
+			t.Fatalf("unexpected field %q", *row.FieldName)
+		}
+		if row.OldValue == nil || row.NewValue == nil || *row.OldValue != values[0] || *row.NewValue != values[1] {
+			t.Errorf("%s: old/new = %v/%v, want %s/%s", *row.FieldName, row.OldValue, row.NewValue, values[0], values[1])
+		}
+		delete(want, *row.FieldName)
+	}
+}
+
+func TestUpdateSkipsUnchangedFields(t *testing.T) {
+	svc, _ := newVersionFixture()
+	activities := &fieldActivityRepo{}
+	svc.activityRepo = activities
+
+	read := 1
+	title, priority := "Original", "medium"
+	if _, err := svc.Update(context.Background(), "t1", "user-1", &models.UpdateTaskRequest{
+		Title: &title, Priority: &priority, Version: &read,
+	}); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if len(activities.rows) != 0 {
+		t.Fatalf("no-op update logged %d activities: %+v", len(activities.rows), activities.rows)
+	}
+}
+
+type statusHistoryRepo struct {
+	repository.SprintCommitmentRepository
+	changes [][2]string
+}
+
+func (r *statusHistoryRepo) RecordStatusChange(_ context.Context, _ string, fromStatus, toStatus string, _ *string) error {
+	r.changes = append(r.changes, [2]string{fromStatus, toStatus})
+	return nil
+}
+
+func TestUpdateStatusLogsActivityAndStatusHistory(t *testing.T) {
+	svc, _, _ := newDependencyFixture()
+	activities := &fieldActivityRepo{}
+	history := &statusHistoryRepo{}
+	svc.activityRepo = activities
+	svc.commitmentRepo = history
+
+	if err := svc.UpdateStatus(context.Background(), "api", "done", "user-1", nil); err != nil {
+		t.Fatalf("UpdateStatus: %v", err)
+	}
+
+	if len(activities.rows) != 1 {
+		t.Fatalf("got %d activity rows, want 1: %+v", len(activities.rows), activities.rows)
+	}
+	row := activities.rows[0]
+	if row.Action != "status_changed" || *row.FieldName != "status" || *row.OldValue != "in_progress" || *row.NewValue != "done" {
+		t.Errorf("unexpected activity %+v", row)
+	}
+	if len(history.changes) != 1 || history.changes[0] != [2]string{"in_progress", "done"} {
+		t.Errorf("status history = %v, want one in_progress -> done", history.changes)
+	}
+}
+
+func TestUpdatePriorityLogsActivity(t *testing.T) {
+	svc, _ := newVersionFixture()
+	activities := &fieldActivityRepo{}
+	svc.activityRepo = activities
+	ctx := context.Background()
+
+	if err := svc.UpdatePriority(ctx, "t1", "urgent", "user-1", nil); err != nil {
+		t.Fatalf("UpdatePriority: %v", err)
+	}
+	if len(activities.rows) != 1 {
+		t.Fatalf("got %d activity rows, want 1: %+v", len(activities.rows), activities.rows)
+	}
+	if row := activities.rows[0]; *row.FieldName != "priority" || *row.OldValue != "medium" || *row.NewValue != "urgent" {
+		t.Errorf("unexpected activity %+v", row)
+	}
+
+	// Setting the same priority again is not a change
+	if err := svc.UpdatePriority(ctx, "t1", "urgent", "user-1", nil); err != nil {
+		t.Fatalf("second UpdatePriority: %v", err)
+	}
+	if len(activities.rows) != 1 {
+		t.Errorf("no-op priority update logged an activity: %+v", activities.rows)
+	}
+}
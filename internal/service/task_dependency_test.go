@@ -0,0 +1,217 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/repository"
+)
+
+type depTaskRepo struct {
+	repository.TaskRepository
+	tasks map[string]*repository.Task
+}
+
+func (r *depTaskRepo) FindByID(_ context.Context, id string) (*repository.Task, error) {
+	t, ok := r.tasks[id]
+	if !ok {
+		return nil, nil
+	}
+	copied := *t
+	return &copied, nil
+}
+
+func (r *depTaskRepo) UpdateStatus(_ context.Context, id, status string, _ *int) error {
+	r.tasks[id].Status = status
+	return nil
+}
+
+func (r *depTaskRepo) CountByStatus(_ context.Context, projectID, status string) (int, error) {
+	count := 0
+	for _, t := range r.tasks {
+		if t.ProjectID == projectID && t.Status == status {
+			count++
+		}
+	}
+	return count, nil
+}
+
+type depDependencyRepo struct {
+	repository.TaskDependencyRepository
+	deps []*repository.TaskDependency
+}
+
+func (r *depDependencyRepo) Create(_ context.Context, dep *repository.TaskDependency) error {
+	r.deps = append(r.deps, dep)
+	return nil
+}
+
+func (r *depDependencyRepo) FindByTaskID(_ context.Context, taskID string) ([]*repository.TaskDependency, error) {
+	var result []*repository.TaskDependency
+	for _, d := range r.deps {
+		if d.TaskID == taskID {
+			result = append(result, d)
+		}
+	}
+	return result, nil
+}
+
+func (r *depDependencyRepo) FindBlockedBy(_ context.Context, taskID string) ([]*repository.TaskDependency, error) {
+	var result []*repository.TaskDependency
+	for _, d := range r.deps {
+		if d.DependsOnTaskID == taskID {
+			result = append(result, d)
+		}
+	}
+	return result, nil
+}
+
+type depStatusRepo struct {
+	repository.ProjectStatusRepository
+	statuses []*repository.ProjectStatus
+}
+
+func (r *depStatusRepo) FindByProjectID(context.Context, string) ([]*repository.ProjectStatus, error) {
+	return r.statuses, nil
+}
+
+type depWIPLimitRepo struct {
+	repository.WIPLimitRepository
+	limits map[string]int
+}
+
+func (r *depWIPLimitRepo) FindByStatus(_ context.Context, projectID, status string) (*repository.WIPLimit, error) {
+	max, ok := r.limits[status]
+	if !ok {
+		return nil, nil
+	}
+	return &repository.WIPLimit{ProjectID: projectID, Status: status, MaxTasks: max}, nil
+}
+
+type depActivityRepo struct {
+	repository.TaskActivityRepository
+	actions []string
+}
+
+func (r *depActivityRepo) Create(_ context.Context, a *repository.TaskActivity) error {
+	r.actions = append(r.actions, a.Action)
+	return nil
+}
+
+type allowEditPermissions struct {
+	PermissionService
+}
+
+func (allowEditPermissions) CanEditTask(context.Context, string, string) bool { return true }
+
+// newDependencyFixture builds a project whose workflow starts at "ready"
+// rather than "todo", with "review" blocked by both "api" and "ui"
+func newDependencyFixture() (*taskService, *depTaskRepo, *depWIPLimitRepo) {
+	tasks := &depTaskRepo{tasks: map[string]*repository.Task{
+		"api":    {ID: "api", ProjectID: "p1", Status: "in_progress", Title: "API"},
+		"ui":     {ID: "ui", ProjectID: "p1", Status: "in_progress", Title: "UI"},
+		"review": {ID: "review", ProjectID: "p1", Status: "blocked", Title: "Review"},
+	}}
+	deps := &depDependencyRepo{deps: []*repository.TaskDependency{
+		{TaskID: "review", DependsOnTaskID: "api", DependencyType: "blocks"},
+		{TaskID: "review", DependsOnTaskID: "ui", DependencyType: "blocks"},
+	}}
+	statuses := &depStatusRepo{statuses: []*repository.ProjectStatus{
+		{Key: "ready"}, {Key: "in_progress"}, {Key: "blocked"}, {Key: "done"},
+	}}
+	wip := &depWIPLimitRepo{limits: map[string]int{}}
+	svc := &taskService{
+		taskRepo:       tasks,
+		dependencyRepo: deps,
+		statusRepo:     statuses,
+		wipLimitRepo:   wip,
+		activityRepo:   &depActivityRepo{},
+		permService:    allowEditPermissions{},
+	}
+	return svc, tasks, wip
+}
+
+func TestCompletingOneOfTwoBlockersKeepsTaskBlocked(t *testing.T) {
+	svc, tasks, _ := newDependencyFixture()
+	ctx := context.Background()
+
+	if err := svc.MarkComplete(ctx, "api", "user-1"); err != nil {
+		t.Fatalf("MarkComplete(api): %v", err)
+	}
+	if got := tasks.tasks["review"].Status; got != "blocked" {
+		t.Fatalf("review with an open blocker is %q, want blocked", got)
+	}
+
+	if err := svc.MarkComplete(ctx, "ui", "user-1"); err != nil {
+		t.Fatalf("MarkComplete(ui): %v", err)
+	}
+	if got := tasks.tasks["review"].Status; got != "ready" {
+		t.Fatalf("unblocked review is %q, want the workflow's first status ready", got)
+	}
+}
+
+func TestMarkCompleteRespectsWIPLimit(t *testing.T) {
+	svc, tasks, wip := newDependencyFixture()
+	tasks.tasks["shipped"] = &repository.Task{ID: "shipped", ProjectID: "p1", Status: "done"}
+	wip.limits["done"] = 1
+
+	err := svc.MarkComplete(context.Background(), "api", "user-1")
+	if !errors.Is(err, ErrWIPLimitExceeded) {
+		t.Fatalf("got %v, want ErrWIPLimitExceeded", err)
+	}
+	if got := tasks.tasks["api"].Status; got != "in_progress" {
+		t.Errorf("api moved to %q despite the limit", got)
+	}
+}
+
+func TestAddDependencyChecksBlockedMove(t *testing.T) {
+	svc, tasks, _ := newDependencyFixture()
+	tasks.tasks["docs"] = &repository.Task{ID: "docs", ProjectID: "p1", Status: "ready"}
+	ctx := context.Background()
+
+	// The workflow does not allow leaving "ready" for "blocked"
+	svc.statusRepo.(*depStatusRepo).statuses[0].AllowedTransitions = []string{"in_progress"}
+	err := svc.AddDependency(ctx, "docs", "api", "blocks", "user-1")
+	if !errors.Is(err, ErrInvalidTransition) {
+		t.Fatalf("got %v, want ErrInvalidTransition", err)
+	}
+	if deps, _ := svc.dependencyRepo.FindByTaskID(ctx, "docs"); len(deps) != 0 {
+		t.Errorf("dependency stored although the task could not be blocked")
+	}
+
+	svc.statusRepo.(*depStatusRepo).statuses[0].AllowedTransitions = nil
+	if err := svc.AddDependency(ctx, "docs", "api", "blocks", "user-1"); err != nil {
+		t.Fatalf("AddDependency: %v", err)
+	}
+	if got := tasks.tasks["docs"].Status; got != "blocked" {
+		t.Errorf("docs is %q, want blocked", got)
+	}
+}
+
+func TestAddDependencyRejectsThreeTaskCycle(t *testing.T) {
+	svc, tasks, _ := newDependencyFixture()
+	for _, id := range []string{"a", "b", "c"} {
+		tasks.tasks[id] = &repository.Task{ID: id, ProjectID: "p1", Status: "in_progress", Title: id}
+	}
+	ctx := context.Background()
+
+	if err := svc.AddDependency(ctx, "a", "b", "blocks", "user-1"); err != nil {
+		t.Fatalf("AddDependency(a -> b): %v", err)
+	}
+	if err := svc.AddDependency(ctx, "b", "c", "blocks", "user-1"); err != nil {
+		t.Fatalf("AddDependency(b -> c): %v", err)
+	}
+
+	err := svc.AddDependency(ctx, "c", "a", "blocks", "user-1")
+	if !errors.Is(err, ErrInvalidInput) {
+		t.Fatalf("AddDependency(c -> a) error = %v, want ErrInvalidInput", err)
+	}
+	if !strings.Contains(err.Error(), "c -> a -> b -> c") {
+		t.Errorf("error %q does not name the loop c -> a -> b -> c", err)
+	}
+	if deps, _ := svc.dependencyRepo.FindByTaskID(ctx, "c"); len(deps) != 0 {
+		t.Errorf("cyclic dependency was stored: %+v", deps)
+	}
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/notification"
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/repository"
+)
+
+// historyTaskRepo adds the assignee writes AssignTask and UnassignTask make
+type historyTaskRepo struct {
+	depTaskRepo
+}
+
+func (r *historyTaskRepo) AddAssignee(_ context.Context, taskID, userID string) error {
+	r.tasks[taskID].AssigneeIDs = append(r.tasks[taskID].AssigneeIDs, userID)
+	return nil
+}
+
+func (r *historyTaskRepo) RemoveAssignee(_ context.Context, taskID, userID string) error {
+	var kept []string
+	for _, id := range r.tasks[taskID].AssigneeIDs {
+		if id != userID {
+			kept = append(kept, id)
+		}
+	}
+	r.tasks[taskID].AssigneeIDs = kept
+	return nil
+}
+
+func (r *historyTaskRepo) AddWatcher(context.Context, string, string) error { return nil }
+
+func (r *historyTaskRepo) UpdatePriority(_ context.Context, id, priority string, _ *int) error {
+	r.tasks[id].Priority = priority
+	return nil
+}
+
+// historyActivityRepo keeps activities and returns them newest first
+type historyActivityRepo struct {
+	repository.TaskActivityRepository
+	rows []*repository.TaskActivity
+}
+
+func (r *historyActivityRepo) Create(_ context.Context, a *repository.TaskActivity) error {
+	copied := *a
+	copied.ID = fmt.Sprintf("activity-%d", len(r.rows)+1)
+	copied.CreatedAt = time.Now()
+	r.rows = append(r.rows, &copied)
+	return nil
+}
+
+func (r *historyActivityRepo) FindByTaskID(_ context.Context, taskID string, limit int) ([]*repository.TaskActivity, error) {
+	var result []*repository.TaskActivity
+	for i := len(r.rows) - 1; i >= 0 && len(result) < limit; i-- {
+		if r.rows[i].TaskID == taskID {
+			result = append(result, r.rows[i])
+		}
+	}
+	return result, nil
+}
+
+type historyUserRepo struct {
+	repository.UserRepository
+	users map[string]string // ID -> name
+}
+
+func (r historyUserRepo) FindByID(_ context.Context, id string) (*repository.User, error) {
+	return &repository.User{ID: id, Name: r.users[id]}, nil
+}
+
+func (r historyUserRepo) FindByIDs(_ context.Context, ids []string) ([]*repository.User, error) {
+	var users []*repository.User
+	for _, id := range ids {
+		if name, ok := r.users[id]; ok {
+			users = append(users, &repository.User{ID: id, Name: name})
+		}
+	}
+	return users, nil
+}
+
+type historyNotificationRepo struct {
+	repository.NotificationRepository
+}
+
+func (historyNotificationRepo) Create(context.Context, *repository.Notification) error { return nil }
+
+type allowAllMembers struct {
+	MemberService
+}
+
+func (allowAllMembers) HasEffectiveAccess(context.Context, string, string, string) (bool, string, error) {
+	return true, PermissionMember, nil
+}
+
+type historyPermissions struct {
+	allowEditPermissions
+}
+
+func (historyPermissions) CanAccessTask(context.Context, string, string) bool { return true }
+
+func TestHistoryIncludesChangesFromDedicatedEndpoints(t *testing.T) {
+	tasks := &historyTaskRepo{depTaskRepo{tasks: map[string]*repository.Task{
+		"t1": {ID: "t1", ProjectID: "p1", Title: "Ship it", Status: "in_progress", Priority: "medium", AssigneeIDs: []string{"carol"}},
+	}}}
+	activities := &historyActivityRepo{}
+	svc := &taskService{
+		taskRepo:        tasks,
+		activityRepo:    activities,
+		userRepo:        historyUserRepo{users: map[string]string{"alice": "Alice", "bob": "Bob", "carol": "Carol"}},
+		memberService:   allowAllMembers{},
+		permService:     historyPermissions{},
+		notificationSvc: notification.NewService(historyNotificationRepo{}),
+	}
+	ctx := context.Background()
+
+	if err := svc.UpdatePriority(ctx, "t1", "high", "alice", nil); err != nil {
+		t.Fatalf("UpdatePriority: %v", err)
+	}
+	if err := svc.AssignTask(ctx, "t1", "bob", "alice"); err != nil {
+		t.Fatalf("AssignTask: %v", err)
+	}
+	if err := svc.UnassignTask(ctx, "t1", "carol", "alice"); err != nil {
+		t.Fatalf("UnassignTask: %v", err)
+	}
+	// Assigning someone who is already assigned changes nothing
+	if err := svc.AssignTask(ctx, "t1", "bob", "alice"); err != nil {
+		t.Fatalf("repeat AssignTask: %v", err)
+	}
+
+	history, err := svc.GetHistory(ctx, "t1", "alice", 10)
+	if err != nil {
+		t.Fatalf("GetHistory: %v", err)
+	}
+	if len(history) != 1 {
+		t.Fatalf("got %d history entries, want the changes merged into 1: %+v", len(history), history)
+	}
+	want := []string{"changed priority from medium to high", "assigned Bob", "unassigned Carol"}
+	if entry := history[0]; entry.ActorName != "Alice" || !reflect.DeepEqual(entry.Changes, want) {
+		t.Fatalf("history entry = %s %q, want Alice %q", entry.ActorName, entry.Changes, want)
+	}
+}
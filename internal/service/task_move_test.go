@@ -0,0 +1,47 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/repository"
+)
+
+type moveProjectRepo struct {
+	repository.ProjectRepository
+}
+
+func (moveProjectRepo) FindByID(_ context.Context, id string) (*repository.Project, error) {
+	return &repository.Project{ID: id}, nil
+}
+
+// viewerOnTarget can edit tasks in the source project but only view the target
+type viewerOnTarget struct {
+	allowEditPermissions
+}
+
+func (viewerOnTarget) CanAccessProject(context.Context, string, string) bool { return true }
+
+func (viewerOnTarget) CanCreateTask(_ context.Context, _, projectID string) bool {
+	return projectID != "target"
+}
+
+func TestMoveToProjectRequiresCreateOnTarget(t *testing.T) {
+	tasks := &depTaskRepo{tasks: map[string]*repository.Task{
+		"t1": {ID: "t1", ProjectID: "source", Title: "Move me", Status: "todo"},
+	}}
+	svc := &taskService{
+		taskRepo:    tasks,
+		projectRepo: moveProjectRepo{},
+		permService: viewerOnTarget{},
+	}
+
+	_, err := svc.MoveToProject(context.Background(), "t1", "target", "viewer")
+	if !errors.Is(err, ErrUnauthorized) {
+		t.Fatalf("MoveToProject by a target viewer error = %v, want ErrUnauthorized", err)
+	}
+	if got := tasks.tasks["t1"].ProjectID; got != "source" {
+		t.Fatalf("task moved to %q", got)
+	}
+}
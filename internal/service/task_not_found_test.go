@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/repository"
+)
+
+type missingTaskRepo struct {
+	repository.TaskRepository
+}
+
+func (missingTaskRepo) FindByID(context.Context, string) (*repository.Task, error) { return nil, nil }
+
+func (missingTaskRepo) FindByKey(context.Context, string, int) (*repository.Task, error) {
+	return nil, nil
+}
+
+type missingCommentRepo struct {
+	repository.TaskCommentRepository
+}
+
+func (missingCommentRepo) FindByID(context.Context, string) (*repository.TaskComment, error) {
+	return nil, nil
+}
+
+type missingAttachmentRepo struct {
+	repository.TaskAttachmentRepository
+}
+
+func (missingAttachmentRepo) FindByID(context.Context, string) (*repository.TaskAttachment, error) {
+	return nil, nil
+}
+
+// Lookups of rows that do not exist must return a not-found error, never (nil, nil)
+func TestMissingRowsReturnNotFound(t *testing.T) {
+	svc := &taskService{
+		taskRepo:       missingTaskRepo{},
+		commentRepo:    missingCommentRepo{},
+		attachmentRepo: missingAttachmentRepo{},
+		permService:    allowEditPermissions{},
+	}
+	ctx := context.Background()
+
+	cases := []struct {
+		name string
+		call func() (interface{}, error)
+		want error
+	}{
+		{"GetByID", func() (interface{}, error) { return svc.GetByID(ctx, "t1", "user-1") }, ErrTaskNotFound},
+		{"GetByKey", func() (interface{}, error) { return svc.GetByKey(ctx, "ORA-7", "user-1") }, ErrTaskNotFound},
+		{"GetByKey malformed", func() (interface{}, error) { return svc.GetByKey(ctx, "ORA", "user-1") }, ErrTaskNotFound},
+		{"AddCommentReaction", func() (interface{}, error) { return svc.AddCommentReaction(ctx, "c1", "user-1", "👍") }, ErrCommentNotFound},
+		{"RemoveCommentReaction", func() (interface{}, error) { return svc.RemoveCommentReaction(ctx, "c1", "user-1", "👍") }, ErrCommentNotFound},
+		{"UpdateComment", func() (interface{}, error) { return nil, svc.UpdateComment(ctx, "c1", "user-1", "edit") }, ErrCommentNotFound},
+		{"DeleteComment", func() (interface{}, error) { return nil, svc.DeleteComment(ctx, "c1", "user-1") }, ErrCommentNotFound},
+		{"DeleteAttachment", func() (interface{}, error) { return nil, svc.DeleteAttachment(ctx, "a1", "user-1") }, ErrAttachmentNotFound},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := tc.call()
+			if !errors.Is(err, tc.want) {
+				t.Fatalf("error = %v, want %v", err, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,230 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/models"
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/repository"
+)
+
+// Fakes embed the repository interface so only the methods these checks
+// reach need an implementation; anything else panics on the nil embed.
+
+type permTaskRepo struct {
+	repository.TaskRepository
+	tasks map[string]*repository.Task
+}
+
+func (r *permTaskRepo) FindByID(_ context.Context, id string) (*repository.Task, error) {
+	return r.tasks[id], nil
+}
+
+type permProjectRepo struct {
+	repository.ProjectRepository
+	project *repository.Project
+	members map[string]string // userID -> role
+}
+
+func (r *permProjectRepo) FindByID(_ context.Context, id string) (*repository.Project, error) {
+	if id != r.project.ID {
+		return nil, nil
+	}
+	return r.project, nil
+}
+
+func (r *permProjectRepo) FindMember(_ context.Context, projectID, userID string) (*repository.ProjectMember, error) {
+	role, ok := r.members[userID]
+	if !ok || projectID != r.project.ID {
+		return nil, nil
+	}
+	return &repository.ProjectMember{ProjectID: projectID, UserID: userID, Role: role}, nil
+}
+
+type permSpaceRepo struct {
+	repository.SpaceRepository
+}
+
+func (r *permSpaceRepo) FindByID(_ context.Context, id string) (*repository.Space, error) {
+	return &repository.Space{ID: id, WorkspaceID: "ws-1"}, nil
+}
+
+type permInvitationRepo struct {
+	repository.InvitationRepository
+	perms map[string]*repository.InvitationPermissions // userID -> project invitation
+}
+
+func (r *permInvitationRepo) FindAcceptedPermissions(_ context.Context, userID string, targetType repository.InvitationType, _ string) (*repository.InvitationPermissions, error) {
+	if targetType != repository.InvitationTypeProject {
+		return nil, nil
+	}
+	return r.perms[userID], nil
+}
+
+type permMemberService struct {
+	MemberService
+	project *permProjectRepo
+}
+
+func (m *permMemberService) HasEffectiveAccess(_ context.Context, _, _, userID string) (bool, string, error) {
+	role, ok := m.project.members[userID]
+	return ok, role, nil
+}
+
+type permChecklistRepo struct {
+	repository.TaskChecklistRepository
+}
+
+func (r *permChecklistRepo) FindChecklistByID(_ context.Context, id string) (*repository.TaskChecklist, error) {
+	return &repository.TaskChecklist{ID: id, TaskID: "task-1"}, nil
+}
+
+func (r *permChecklistRepo) FindItemByID(_ context.Context, id string) (*repository.ChecklistItem, error) {
+	return &repository.ChecklistItem{ID: id, ChecklistID: "checklist-1"}, nil
+}
+
+const (
+	commentOnlyGuest = "guest-1"
+	fullMember       = "member-1"
+	taskCreator      = "creator-1"
+)
+
+func newPermissionFixture() (*permissionService, *taskService) {
+	creator := taskCreator
+	projects := &permProjectRepo{
+		project: &repository.Project{ID: "project-1", SpaceID: "space-1"},
+		members: map[string]string{
+			commentOnlyGuest: PermissionMember,
+			fullMember:       PermissionMember,
+			taskCreator:      PermissionMember,
+		},
+	}
+	tasks := &permTaskRepo{tasks: map[string]*repository.Task{
+		"task-1": {ID: "task-1", ProjectID: "project-1", CreatedBy: &creator},
+	}}
+	perm := &permissionService{
+		spaceRepo:   &permSpaceRepo{},
+		projectRepo: projects,
+		taskRepo:    tasks,
+		invRepo: &permInvitationRepo{perms: map[string]*repository.InvitationPermissions{
+			commentOnlyGuest: {CanComment: true},
+		}},
+		memberService: &permMemberService{project: projects},
+	}
+	tasksSvc := &taskService{
+		taskRepo:      tasks,
+		projectRepo:   projects,
+		checklistRepo: &permChecklistRepo{},
+		permService:   perm,
+	}
+	return perm, tasksSvc
+}
+
+func TestCommentOnlyGuestPermissions(t *testing.T) {
+	perm, _ := newPermissionFixture()
+	ctx := context.Background()
+
+	checks := []struct {
+		name string
+		got  bool
+		want bool
+	}{
+		{"access", perm.CanAccessTask(ctx, commentOnlyGuest, "task-1"), true},
+		{"comment", perm.CanCommentOnTask(ctx, commentOnlyGuest, "task-1"), true},
+		{"edit", perm.CanEditTask(ctx, commentOnlyGuest, "task-1"), false},
+		{"delete", perm.CanDeleteTask(ctx, commentOnlyGuest, "task-1"), false},
+		{"create", perm.CanCreateTask(ctx, commentOnlyGuest, "project-1"), false},
+	}
+	for _, c := range checks {
+		if c.got != c.want {
+			t.Errorf("comment-only guest %s = %v, want %v", c.name, c.got, c.want)
+		}
+	}
+}
+
+func TestFullMemberKeepsRoleDefaults(t *testing.T) {
+	perm, _ := newPermissionFixture()
+	ctx := context.Background()
+
+	if !perm.CanEditTask(ctx, fullMember, "task-1") {
+		t.Error("member without invitation limits should edit tasks")
+	}
+	if !perm.CanCreateTask(ctx, fullMember, "project-1") {
+		t.Error("member without invitation limits should create tasks")
+	}
+	if perm.CanDeleteTask(ctx, fullMember, "task-1") {
+		t.Error("member should not delete a task they did not create")
+	}
+	if !perm.CanDeleteTask(ctx, taskCreator, "task-1") {
+		t.Error("creator should delete their task")
+	}
+}
+
+func TestCommentOnlyGuestCannotChangeTask(t *testing.T) {
+	_, svc := newPermissionFixture()
+	ctx := context.Background()
+	user := commentOnlyGuest
+
+	operations := map[string]func() error{
+		"Create": func() error {
+			_, err := svc.Create(ctx, &models.CreateTaskRequest{
+				ProjectID: "project-1", Title: "t", Status: "todo", Priority: "medium", CreatedBy: &user,
+			})
+			return err
+		},
+		"CreateChecklist": func() error {
+			_, err := svc.CreateChecklist(ctx, "task-1", user, "Checklist")
+			return err
+		},
+		"AddChecklistItem": func() error {
+			_, err := svc.AddChecklistItem(ctx, "checklist-1", user, "item", nil)
+			return err
+		},
+		"UpdateChecklistItem": func() error {
+			content := "changed"
+			_, err := svc.UpdateChecklistItem(ctx, "item-1", user, &content, nil)
+			return err
+		},
+		"ToggleChecklistItem": func() error {
+			return svc.ToggleChecklistItem(ctx, "item-1", user)
+		},
+		"ReorderChecklist": func() error {
+			_, err := svc.ReorderChecklist(ctx, "checklist-1", user, nil, nil)
+			return err
+		},
+		"ReorderChecklistItem": func() error {
+			_, err := svc.ReorderChecklistItem(ctx, "item-1", user, nil, nil)
+			return err
+		},
+		"AddAttachment": func() error {
+			_, err := svc.AddAttachment(ctx, "task-1", user, "spec", "https://example.com/spec", 0, "")
+			return err
+		},
+		"UploadAttachment": func() error {
+			_, err := svc.UploadAttachment(ctx, "task-1", user, "a.txt", strings.NewReader("x"), 1)
+			return err
+		},
+		"StartTimer": func() error {
+			_, err := svc.StartTimer(ctx, "task-1", user)
+			return err
+		},
+		"LogTime": func() error {
+			_, err := svc.LogTime(ctx, "task-1", user, 60, nil)
+			return err
+		},
+		"CloneTask": func() error {
+			_, err := svc.CloneTask(ctx, "task-1", user, CloneOptions{})
+			return err
+		},
+	}
+
+	for name, op := range operations {
+		t.Run(name, func(t *testing.T) {
+			if err := op(); !errors.Is(err, ErrUnauthorized) {
+				t.Fatalf("got %v, want ErrUnauthorized", err)
+			}
+		})
+	}
+}
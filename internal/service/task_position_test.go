@@ -0,0 +1,39 @@
+package service
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSlotPosition(t *testing.T) {
+	list := []positionedItem{{id: "a", position: 1024}, {id: "b", position: 2048}, {id: "c", position: 2049}}
+	id := func(s string) *string { return &s }
+
+	tests := []struct {
+		name          string
+		before, after *string
+		want          int
+		wantErr       error
+	}{
+		{name: "end of list", want: 2049 + 1024},
+		{name: "top of list", after: id("a"), want: 512},
+		{name: "between neighbours", before: id("a"), after: id("b"), want: 1536},
+		{name: "below only", before: id("a"), want: 1536},
+		{name: "adjacent positions", before: id("b"), after: id("c"), wantErr: errPositionGapExhausted},
+		{name: "inverted neighbours", before: id("b"), after: id("a"), wantErr: ErrInvalidInput},
+		{name: "neighbours apart", before: id("a"), after: id("c"), wantErr: ErrInvalidInput},
+		{name: "unknown neighbour", before: id("x"), wantErr: ErrInvalidInput},
+	}
+	for _, tt := range tests {
+		got, err := slotPosition(list, tt.before, tt.after, 1024)
+		if tt.wantErr != nil {
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("%s: got error %v, want %v", tt.name, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("%s: got %d, %v; want %d", tt.name, got, err, tt.want)
+		}
+	}
+}
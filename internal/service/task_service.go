@@ -2,9 +2,16 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"log"
+	"io"
+	"log/slog"
+	"mime"
+	"net/url"
+	"path"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -12,12 +19,15 @@ import (
 	"github.com/Marga-Ghale/ora-scrum-backend/internal/notification"
 	"github.com/Marga-Ghale/ora-scrum-backend/internal/repository"
 	"github.com/Marga-Ghale/ora-scrum-backend/internal/socket"
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/storage"
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/types"
 )
 
 type TaskService interface {
 	// Task CRUD
 	Create(ctx context.Context, req *models.CreateTaskRequest) (*repository.Task, error)
 	GetByID(ctx context.Context, taskID, userID string) (*repository.Task, error)
+	GetByKey(ctx context.Context, key, userID string) (*repository.Task, error)
 	Update(ctx context.Context, taskID, userID string, req *models.UpdateTaskRequest) (*repository.Task, error)
 	Delete(ctx context.Context, taskID, userID string) error
 	
@@ -26,28 +36,39 @@ type TaskService interface {
 	ListBySprint(ctx context.Context, sprintID, userID string) ([]*repository.Task, error)
 	ListSubtasks(ctx context.Context, parentTaskID, userID string) ([]*repository.Task, error)
 	ListMyTasks(ctx context.Context, userID string) ([]*repository.Task, error)
+	GetMyWork(ctx context.Context, userID string) (*MyWork, error)
 	ListByStatus(ctx context.Context, projectID, status, userID string) ([]*repository.Task, error)
 	
 	// Task operations
-	UpdateStatus(ctx context.Context, taskID, status, userID string) error
-	UpdatePriority(ctx context.Context, taskID, priority, userID string) error
+	// UpdateStatus and UpdatePriority return ErrStaleVersion when version is
+	// set and no longer matches the task; nil skips the check
+	UpdateStatus(ctx context.Context, taskID, status, userID string, version *int) error
+	UpdatePriority(ctx context.Context, taskID, priority, userID string, version *int) error
 	AssignTask(ctx context.Context, taskID, assigneeID, actorID string) error
 	UnassignTask(ctx context.Context, taskID, assigneeID, actorID string) error
 	AddWatcher(ctx context.Context, taskID, watcherID, actorID string) error
 	RemoveWatcher(ctx context.Context, taskID, watcherID, actorID string) error
 	MarkComplete(ctx context.Context, taskID, userID string) error
 	MoveToSprint(ctx context.Context, taskID, sprintID, userID string) error
+	MoveToProject(ctx context.Context, taskID, targetProjectID, userID string) (*repository.Task, error)
 	ConvertToSubtask(ctx context.Context, taskID, parentTaskID, userID string) error
 	PromoteToTask(ctx context.Context, taskID, userID string) error
 
 	// COMMENTS
-	AddComment(ctx context.Context, taskID, userID, content string, mentionedUsers []string) (*repository.TaskComment, error)
+	AddComment(ctx context.Context, taskID, userID, content string, mentionedUsers []string, parentCommentID *string) (*repository.TaskComment, error)
+	// AddSystemComment posts a comment authored by the integration bot
+	AddSystemComment(ctx context.Context, taskID, content string) (*repository.TaskComment, error)
 	ListComments(ctx context.Context, taskID, userID string) ([]*repository.TaskComment, error)
 	UpdateComment(ctx context.Context, commentID, userID, content string) error
 	DeleteComment(ctx context.Context, commentID, userID string) error
+	AddCommentReaction(ctx context.Context, commentID, userID, emoji string) (*repository.TaskComment, error)
+	RemoveCommentReaction(ctx context.Context, commentID, userID, emoji string) (*repository.TaskComment, error)
+	GetCommentHistory(ctx context.Context, commentID, userID string) ([]*repository.CommentEdit, error)
 	
 	// ATTACHMENTS
 	AddAttachment(ctx context.Context, taskID, userID, filename, fileURL string, fileSize int64, mimeType string) (*repository.TaskAttachment, error)
+	// UploadAttachment stores the file itself; size and type are derived server-side
+	UploadAttachment(ctx context.Context, taskID, userID, filename string, file io.Reader, size int64) (*repository.TaskAttachment, error)
 	ListAttachments(ctx context.Context, taskID, userID string) ([]*repository.TaskAttachment, error)
 	DeleteAttachment(ctx context.Context, attachmentID, userID string) error
 	
@@ -58,6 +79,7 @@ type TaskService interface {
 	LogTime(ctx context.Context, taskID, userID string, durationSeconds int, description *string) (*repository.TimeEntry, error)
 	GetTimeEntries(ctx context.Context, taskID, userID string) ([]*repository.TimeEntry, error)
 	GetTotalTime(ctx context.Context, taskID string) (int, error)
+	GetTimeReport(ctx context.Context, projectID, userID string, from, to time.Time) (*TimeReport, error)
 	
 	// DEPENDENCIES
 	AddDependency(ctx context.Context, taskID, dependsOnTaskID, depType, userID string) error
@@ -68,31 +90,71 @@ type TaskService interface {
 	// CHECKLISTS
 	CreateChecklist(ctx context.Context, taskID, userID, title string) (*repository.TaskChecklist, error)
 	AddChecklistItem(ctx context.Context, checklistID, userID, content string, assigneeID *string) (*repository.ChecklistItem, error)
+	UpdateChecklistItem(ctx context.Context, itemID, userID string, content, assigneeID *string) (*repository.ChecklistItem, error)
+	ListMyChecklistItems(ctx context.Context, userID string) ([]*repository.AssignedChecklistItem, error)
 	ToggleChecklistItem(ctx context.Context, itemID, userID string) error
 	DeleteChecklistItem(ctx context.Context, itemID, userID string) error
 	ListChecklists(ctx context.Context, taskID, userID string) ([]*repository.TaskChecklist, error)
+	ReorderChecklist(ctx context.Context, checklistID, userID string, beforeID, afterID *string) (*repository.TaskChecklist, error)
+	ReorderChecklistItem(ctx context.Context, itemID, userID string, beforeID, afterID *string) (*repository.ChecklistItem, error)
 	
 	// ACTIVITY
 	GetActivity(ctx context.Context, taskID, userID string, limit int) ([]*repository.TaskActivity, error)
+	// GetHistory describes the task's activity for display, newest first
+	GetHistory(ctx context.Context, taskID, userID string, limit int) ([]*TaskHistoryEntry, error)
 	
 	// ADVANCED FILTERING
 	FilterTasks(ctx context.Context, filters *repository.TaskFilters, userID string) ([]*repository.Task, int, error)
+	// ExportTasks hands every task matching the filters to emit, one at a time
+	ExportTasks(ctx context.Context, filters *repository.TaskFilters, userID string, emit func(*TaskExportRow) error) error
 	FindOverdue(ctx context.Context, projectID, userID string) ([]*repository.Task, error)
 	FindBlocked(ctx context.Context, projectID, userID string) ([]*repository.Task, error)
+	SearchTasks(ctx context.Context, userID, query string, limit int) ([]*repository.Task, error)
+	SearchComments(ctx context.Context, userID, query string, limit int) ([]*repository.CommentSearchResult, error)
+
+	// SAVED VIEWS
+	CreateView(ctx context.Context, projectID, userID, name string, shared bool, filters *repository.TaskFilters) (*repository.SavedView, error)
+	ListViews(ctx context.Context, projectID, userID string) ([]*repository.SavedView, error)
+	GetView(ctx context.Context, viewID, userID string) (*repository.SavedView, error)
+	UpdateView(ctx context.Context, viewID, userID string, name *string, shared *bool, filters *repository.TaskFilters) (*repository.SavedView, error)
+	DeleteView(ctx context.Context, viewID, userID string) error
+	ApplySavedView(ctx context.Context, viewID, userID string, limit, offset int) ([]*repository.Task, int, error)
 	
 	// SCRUM SPECIFIC
 	GetBacklog(ctx context.Context, projectID, userID string) ([]*repository.Task, error)
-	GetSprintBoard(ctx context.Context, sprintID, userID string) (map[string][]*repository.Task, error)
+	FilterBacklog(ctx context.Context, filters *repository.TaskFilters, userID string) (*Backlog, error)
+	GetSprintBoard(ctx context.Context, sprintID, userID string) (*SprintBoard, error)
+	GetSprintBoardByAssignee(ctx context.Context, sprintID, userID string) (map[string]*AssigneeBucket, error)
 	GetSprintVelocity(ctx context.Context, sprintID, userID string) (int, error)
 	GetSprintBurndown(ctx context.Context, sprintID, userID string) (*SprintBurndown, error)
 	UpdatePosition(ctx context.Context, taskID string, position int, userID string) error
 
 	ReorderTasksInColumn(ctx context.Context, projectID, status, movedTaskID string, newPosition int, userID string) error
+	ReorderTask(ctx context.Context, taskID, userID string, targetStatus string, beforeTaskID, afterTaskID *string) (*repository.Task, error)
+
+	// WIP LIMITS
+	SetWIPLimit(ctx context.Context, projectID, status string, maxTasks int, userID string) error
+	GetWIPLimits(ctx context.Context, projectID, userID string) ([]*repository.WIPLimit, error)
+
+	// TASK SETTINGS
+	GetTaskSettings(ctx context.Context, projectID, userID string) (*repository.ProjectTaskSettings, error)
+	UpdateTaskSettings(ctx context.Context, projectID, userID string, req *models.UpdateTaskSettingsRequest) (*repository.ProjectTaskSettings, error)
 	
 	// BULK OPERATIONS
 	BulkUpdateStatus(ctx context.Context, taskIDs []string, status, userID string) error
 	BulkAssign(ctx context.Context, taskIDs []string, assigneeID, actorID string) error
 	BulkMoveToSprint(ctx context.Context, taskIDs []string, sprintID, userID string) error
+	BulkDelete(ctx context.Context, taskIDs []string, userID string) error
+	BulkAddLabel(ctx context.Context, taskIDs []string, labelID, userID string) ([]*BulkLabelResult, error)
+	BulkRemoveLabel(ctx context.Context, taskIDs []string, labelID, userID string) ([]*BulkLabelResult, error)
+
+	// Labels
+	AddLabel(ctx context.Context, taskID, labelID, userID string) error
+	RemoveLabel(ctx context.Context, taskID, labelID, userID string) error
+	GetLabels(ctx context.Context, taskID, userID string) ([]*repository.Label, error)
+
+	// Cloning
+	CloneTask(ctx context.Context, taskID, userID string, opts CloneOptions) (*repository.Task, error)
 
 }
 
@@ -108,11 +170,55 @@ type SprintBurndown struct {
 	CompletionRate      float64                  `json:"completionRate"`
 }
 
+// TimeReport is the time logged in a project over [From, To), per user
+type TimeReport struct {
+	ProjectID    string            `json:"projectId"`
+	From         time.Time         `json:"from"`
+	To           time.Time         `json:"to"`
+	TotalSeconds int               `json:"totalSeconds"`
+	Users        []*UserTimeReport `json:"users"`
+}
+
+type UserTimeReport struct {
+	UserID       string                      `json:"userId"`
+	TotalSeconds int                         `json:"totalSeconds"`
+	Tasks        []*repository.TaskTimeTotal `json:"tasks"`
+}
+
 type BurndownPoint struct {
 	Date   time.Time `json:"date"`
 	Points int       `json:"points"`
 }
 
+// SprintBoard groups sprint tasks by status along with each column's WIP state
+type SprintBoard struct {
+	Statuses []*repository.ProjectStatus   `json:"statuses"` // column order
+	Columns  map[string][]*repository.Task `json:"columns"`
+	WIP      map[string]*ColumnWIP         `json:"wip"`
+}
+
+// CloneOptions controls what CloneTask copies besides the task's core fields,
+// labels and checklists
+type CloneOptions struct {
+	TargetProjectID *string // defaults to the source task's project
+	IncludeSubtasks bool
+	CopyAssignees   bool
+	CopyComments    bool
+	CopyAttachments bool
+}
+
+// AssigneeBucket is one swimlane of the assignee board; Assignee is nil for the unassigned lane
+type AssigneeBucket struct {
+	Assignee *repository.User
+	Tasks    []*repository.Task
+}
+
+// ColumnWIP is the configured limit (nil when unlimited) and current task count of a column
+type ColumnWIP struct {
+	Limit *int `json:"limit"`
+	Count int  `json:"count"`
+}
+
 
 // GoalRecalculator interface to avoid circular dependency
 type GoalRecalculator interface {
@@ -135,11 +241,29 @@ type taskService struct {
 	sprintRepo      repository.SprintRepository
 	userRepo        repository.UserRepository
 	commitmentRepo  repository.SprintCommitmentRepository  
+	analyticsRepo   repository.SprintAnalyticsRepository
+	wipLimitRepo    repository.WIPLimitRepository
+	statusRepo      repository.ProjectStatusRepository
+	labelRepo       repository.LabelRepository
+	taskLabelRepo   repository.TaskLabelRepository
+	savedViewRepo   repository.SavedViewRepository
+	settingsRepo    repository.TaskSettingsRepository
+	watcherRepo     repository.TaskWatcherRepository
 	memberService   MemberService
 	permService     PermissionService
 	notificationSvc *notification.Service
 	broadcaster     *socket.Broadcaster
 	goalService     GoalService
+	store           storage.Storage
+	uploadLimits    AttachmentLimits
+	maxSubtaskDepth int // 0 = unlimited
+	readCache       *ReadCache
+}
+
+// AttachmentLimits bounds uploaded attachments; zero values disable a limit
+type AttachmentLimits struct {
+	MaxFileSize    int64 // bytes per file
+	WorkspaceQuota int64 // bytes of stored attachments per workspace
 }
 
 // Constructor
@@ -154,11 +278,24 @@ func NewTaskService(
 	projectRepo repository.ProjectRepository,
 	sprintRepo repository.SprintRepository,
 	userRepo repository.UserRepository,
+	analyticsRepo repository.SprintAnalyticsRepository,
+	commitmentRepo repository.SprintCommitmentRepository,
+	wipLimitRepo repository.WIPLimitRepository,
+	statusRepo repository.ProjectStatusRepository,
+	labelRepo repository.LabelRepository,
+	taskLabelRepo repository.TaskLabelRepository,
+	savedViewRepo repository.SavedViewRepository,
+	settingsRepo repository.TaskSettingsRepository,
+	watcherRepo repository.TaskWatcherRepository,
 	memberService MemberService,
 	permService PermissionService,
 	notificationSvc *notification.Service,
 	broadcaster *socket.Broadcaster,
 	goalService GoalService,
+	store storage.Storage,
+	attachmentLimits AttachmentLimits,
+	maxSubtaskDepth int,
+	readCache *ReadCache, // may be nil
 ) TaskService {
 	return &taskService{
 		taskRepo:        taskRepo,
@@ -171,11 +308,24 @@ func NewTaskService(
 		projectRepo:     projectRepo,
 		sprintRepo:      sprintRepo,
 		userRepo:        userRepo,
+		analyticsRepo:   analyticsRepo,
+		commitmentRepo:  commitmentRepo,
+		wipLimitRepo:    wipLimitRepo,
+		statusRepo:      statusRepo,
+		labelRepo:       labelRepo,
+		taskLabelRepo:   taskLabelRepo,
+		savedViewRepo:   savedViewRepo,
+		settingsRepo:    settingsRepo,
+		watcherRepo:     watcherRepo,
 		memberService:   memberService,
 		permService:     permService,
 		notificationSvc: notificationSvc,
 		broadcaster:     broadcaster,
 		goalService:     goalService,
+		store:           store,
+		uploadLimits:    attachmentLimits,
+		maxSubtaskDepth: maxSubtaskDepth,
+		readCache:       readCache,
 	}
 }
 
@@ -187,12 +337,12 @@ func (s *taskService) Create(ctx context.Context, req *models.CreateTaskRequest)
 	// Verify project exists
 	project, err := s.projectRepo.FindByID(ctx, req.ProjectID)
 	if err != nil || project == nil {
-		return nil, ErrNotFound
+		return nil, ErrProjectNotFound
 	}
 
 	// Set defaults
 	if req.Status == "" {
-		req.Status = "todo"
+		req.Status = s.defaultStatus(ctx, req.ProjectID)
 	}
 	if req.Priority == "" {
 		req.Priority = "medium"
@@ -202,11 +352,18 @@ func (s *taskService) Create(ctx context.Context, req *models.CreateTaskRequest)
 	if req.ParentTaskID != nil {
 		parentTask, err := s.taskRepo.FindByID(ctx, *req.ParentTaskID)
 		if err != nil || parentTask == nil {
-			return nil, ErrNotFound
+			return nil, ErrTaskNotFound
 		}
 		if parentTask.ProjectID != req.ProjectID {
 			return nil, ErrInvalidInput
 		}
+		height := 0
+		if len(req.Subtasks) > 0 {
+			height = 1
+		}
+		if err := s.checkSubtaskPlacement(ctx, "", parentTask, height); err != nil {
+			return nil, err
+		}
 	}
 
 	// Verify assignees have access to project
@@ -217,14 +374,9 @@ func (s *taskService) Create(ctx context.Context, req *models.CreateTaskRequest)
 		}
 	}
 
-	// Verify creator has access to project
-	if req.CreatedBy != nil {
-		hasAccess, _, err := s.memberService.HasEffectiveAccess(
-			ctx, EntityTypeProject, req.ProjectID, *req.CreatedBy,
-		)
-		if err != nil || !hasAccess {
-			return nil, ErrUnauthorized
-		}
+	// Verify creator may add tasks to the project
+	if req.CreatedBy != nil && !s.permService.CanCreateTask(ctx, *req.CreatedBy, req.ProjectID) {
+		return nil, ErrUnauthorized
 	}
 
 	task := &repository.Task{
@@ -255,6 +407,16 @@ func (s *taskService) Create(ctx context.Context, req *models.CreateTaskRequest)
 		return nil, err
 	}
 
+	if len(task.LabelIDs) > 0 {
+		if err := s.replaceTaskLabels(ctx, task, task.LabelIDs); err != nil {
+			return nil, err
+		}
+	}
+
+	if task.SprintID != nil {
+		s.recordSprintEntry(ctx, task, req.CreatedBy)
+	}
+
 	// ✅ CREATE SUBTASKS
 	if len(req.Subtasks) > 0 {
 		for _, subtaskReq := range req.Subtasks {
@@ -298,9 +460,12 @@ func (s *taskService) Create(ctx context.Context, req *models.CreateTaskRequest)
 			
 			// Create the subtask
 			if err := s.taskRepo.Create(ctx, subtask); err != nil {
-				log.Printf("Failed to create subtask: %v", err)
+				slog.WarnContext(ctx, "failed to create subtask", "parentTaskID", task.ID, "error", err)
 				continue // Continue creating other subtasks even if one fails
 			}
+			if subtask.SprintID != nil {
+				s.recordSprintEntry(ctx, subtask, req.CreatedBy)
+			}
 		}
 	}
 	// ✅ END SUBTASK CREATION
@@ -358,17 +523,16 @@ func (s *taskService) Create(ctx context.Context, req *models.CreateTaskRequest)
 		}
 	}
 
-	// // 3. Broadcast to project room via WebSocket
-	// if s.broadcaster != nil {
-	// 	s.broadcaster.BroadcastTaskCreated(
-	// 		task.ProjectID,
-	// 		s.taskToMap(task),
-	// 					creatorID,
-
-	// 	)
-	// }
+	// 3. Broadcast to project room via WebSocket
+	if s.broadcaster != nil {
+		s.broadcaster.BroadcastTaskCreated(task.ProjectID, s.taskToMap(task), creatorID)
+	}
 	// ✅ NOTIFICATIONS END
 
+	if task.ParentTaskID != nil {
+		s.broadcastSubtaskProgress(ctx, *task.ParentTaskID, creatorID)
+	}
+
 	return task, nil
 }
 
@@ -382,7 +546,7 @@ func (s *taskService) GetByID(ctx context.Context, taskID, userID string) (*repo
 		return nil, err
 	}
 	if task == nil {
-		return nil, ErrNotFound
+		return nil, ErrTaskNotFound
 	}
 
 	// ✅ Check access via PermissionService
@@ -393,6 +557,32 @@ func (s *taskService) GetByID(ctx context.Context, taskID, userID string) (*repo
 	return task, nil
 }
 
+// GetByKey resolves a human task key like "PROJ-123"; the project key is matched case-insensitively
+func (s *taskService) GetByKey(ctx context.Context, key, userID string) (*repository.Task, error) {
+	sep := strings.LastIndex(key, "-")
+	if sep <= 0 {
+		return nil, ErrTaskNotFound
+	}
+	number, err := strconv.Atoi(key[sep+1:])
+	if err != nil || number <= 0 {
+		return nil, ErrTaskNotFound
+	}
+
+	task, err := s.taskRepo.FindByKey(ctx, key[:sep], number)
+	if err != nil {
+		return nil, err
+	}
+	if task == nil {
+		return nil, ErrTaskNotFound
+	}
+
+	if !s.permService.CanAccessTask(ctx, userID, task.ID) {
+		return nil, ErrUnauthorized
+	}
+
+	return task, nil
+}
+
 func (s *taskService) ListByProject(ctx context.Context, projectID, userID string) ([]*repository.Task, error) {
 	// ✅ Check project access
 	hasAccess, _, err := s.memberService.HasEffectiveAccess(ctx, EntityTypeProject, projectID, userID)
@@ -410,23 +600,32 @@ func (s *taskService) ListBySprint(ctx context.Context, sprintID, userID string)
 		return nil, err
 	}
 
-	// Filter by project access
+	return s.filterAccessibleTasks(ctx, userID, tasks), nil
+}
+
+// filterAccessibleTasks keeps the tasks whose project the user can access,
+// checking each distinct project once
+func (s *taskService) filterAccessibleTasks(ctx context.Context, userID string, tasks []*repository.Task) []*repository.Task {
+	projectIDs := make([]string, len(tasks))
+	for i, task := range tasks {
+		projectIDs[i] = task.ProjectID
+	}
+	access := s.memberService.FilterAccessibleProjects(ctx, userID, projectIDs)
+
 	var accessibleTasks []*repository.Task
 	for _, task := range tasks {
-		hasAccess, _, _ := s.memberService.HasEffectiveAccess(ctx, EntityTypeProject, task.ProjectID, userID)
-		if hasAccess {
+		if access[task.ProjectID] {
 			accessibleTasks = append(accessibleTasks, task)
 		}
 	}
-
-	return accessibleTasks, nil
+	return accessibleTasks
 }
 
 func (s *taskService) ListSubtasks(ctx context.Context, parentTaskID, userID string) ([]*repository.Task, error) {
 	// Verify user can access parent task
 	parentTask, err := s.taskRepo.FindByID(ctx, parentTaskID)
 	if err != nil || parentTask == nil {
-		return nil, ErrNotFound
+		return nil, ErrTaskNotFound
 	}
 
 	if !s.permService.CanAccessTask(ctx, userID, parentTaskID) {
@@ -440,6 +639,59 @@ func (s *taskService) ListMyTasks(ctx context.Context, userID string) ([]*reposi
 	return s.taskRepo.FindByAssigneeID(ctx, userID)
 }
 
+// MyWork is the cross-project dashboard of a user's assigned tasks
+type MyWork struct {
+	Overdue           *repository.WorkSection `json:"overdue"`
+	DueToday          *repository.WorkSection `json:"dueToday"`
+	DueThisWeek       *repository.WorkSection `json:"dueThisWeek"`
+	NoDueDate         *repository.WorkSection `json:"noDueDate"`
+	RecentlyCompleted *repository.WorkSection `json:"recentlyCompleted"`
+}
+
+const (
+	myWorkSectionLimit      = 20
+	myWorkCompletedLookback = 7 * 24 * time.Hour
+)
+
+// GetMyWork buckets the user's assigned tasks across all accessible projects.
+// Days and weeks (Monday to Sunday) follow the server's time zone.
+func (s *taskService) GetMyWork(ctx context.Context, userID string) (*MyWork, error) {
+	projects, err := s.memberService.GetAccessibleProjects(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	projectIDs := make([]string, 0, len(projects))
+	for _, p := range projects {
+		projectIDs = append(projectIDs, p.ID)
+	}
+
+	now := time.Now()
+	startOfToday := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	daysToMonday := (8 - int(startOfToday.Weekday())) % 7
+	if daysToMonday == 0 {
+		daysToMonday = 7
+	}
+	bounds := repository.WorkBounds{
+		Now:            now,
+		EndOfToday:     startOfToday.AddDate(0, 0, 1),
+		EndOfWeek:      startOfToday.AddDate(0, 0, daysToMonday),
+		CompletedSince: now.Add(-myWorkCompletedLookback),
+	}
+
+	sections, err := s.taskRepo.FindMyWork(ctx, userID, projectIDs, bounds, myWorkSectionLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MyWork{
+		Overdue:           sections[repository.WorkSectionOverdue],
+		DueToday:          sections[repository.WorkSectionDueToday],
+		DueThisWeek:       sections[repository.WorkSectionDueThisWeek],
+		NoDueDate:         sections[repository.WorkSectionNoDueDate],
+		RecentlyCompleted: sections[repository.WorkSectionRecentlyCompleted],
+	}, nil
+}
+
 func (s *taskService) ListByStatus(ctx context.Context, projectID, status, userID string) ([]*repository.Task, error) {
 	// Check project access
 	hasAccess, _, err := s.memberService.HasEffectiveAccess(ctx, EntityTypeProject, projectID, userID)
@@ -462,13 +714,24 @@ func (s *taskService) ListByStatus(ctx context.Context, projectID, status, userI
 func (s *taskService) Update(ctx context.Context, taskID, userID string, req *models.UpdateTaskRequest) (*repository.Task, error) {
 	task, err := s.taskRepo.FindByID(ctx, taskID)
 	if err != nil || task == nil {
-		return nil, ErrNotFound
+		return nil, ErrTaskNotFound
 	}
 
 	if !s.permService.CanEditTask(ctx, userID, taskID) {
 		return nil, ErrUnauthorized
 	}
 
+	// Optimistic concurrency: reject writes based on a stale read
+	if req.Version == nil {
+		return nil, fmt.Errorf("%w: version is required", ErrInvalidInput)
+	}
+	if *req.Version != task.Version {
+		return nil, ErrStaleVersion
+	}
+	if err := s.validateTaskUpdate(ctx, task.ProjectID, req); err != nil {
+		return nil, err
+	}
+
 	// Track old values
 	oldStatus := task.Status
 	oldPriority := task.Priority
@@ -479,6 +742,7 @@ func (s *taskService) Update(ctx context.Context, taskID, userID string, req *mo
 	oldStoryPoints := task.StoryPoints
 	oldAssignees := make([]string, len(task.AssigneeIDs))
 	copy(oldAssignees, task.AssigneeIDs)
+	before := *task
 
 	// Track changes with detailed info
 	var changes []string
@@ -496,6 +760,14 @@ func (s *taskService) Update(ctx context.Context, taskID, userID string, req *mo
 		changeDetails = append(changeDetails, "updated description")
 	}
 	if req.Status != nil && *req.Status != task.Status {
+	if err := s.checkStatusTransition(ctx, task.ProjectID, task.Status, *req.Status); err != nil {
+		return nil, err
+	}
+	if task.ParentTaskID == nil {
+		if err := s.checkWIPLimit(ctx, task.ProjectID, *req.Status, 1); err != nil {
+			return nil, err
+		}
+	}
 	task.Status = *req.Status
 	changes = append(changes, "status")
 	changeDetails = append(changeDetails,
@@ -655,7 +927,22 @@ func (s *taskService) Update(ctx context.Context, taskID, userID string, req *mo
 	}
 
 	if err := s.taskRepo.Update(ctx, task); err != nil {
-		return nil, err
+		return nil, taskWriteError(err)
+	}
+
+	if req.LabelIDs != nil {
+		if err := s.replaceTaskLabels(ctx, task, *req.LabelIDs); err != nil {
+			return nil, err
+		}
+	}
+
+	if req.SprintID != nil {
+		s.recordSprintMoves(ctx, []*repository.Task{&before}, task.SprintID, &userID)
+	}
+
+	s.recordFieldChanges(ctx, &before, task, userID)
+	if task.ParentTaskID != nil && task.Status != before.Status {
+		s.onSubtaskStatusChanged(ctx, task, userID)
 	}
 
 	// ✅ SMART NOTIFICATIONS
@@ -865,7 +1152,7 @@ func (s *taskService) sendSpecificUpdateNotification(ctx context.Context, userID
 func (s *taskService) Delete(ctx context.Context, taskID, userID string) error {
 	task, err := s.taskRepo.FindByID(ctx, taskID)
 	if err != nil || task == nil {
-		return ErrNotFound
+		return ErrTaskNotFound
 	}
 
 	if !s.permService.CanDeleteTask(ctx, userID, taskID) {
@@ -918,16 +1205,23 @@ func (s *taskService) Delete(ctx context.Context, taskID, userID string) error {
 // UPDATE STATUS - With History, Cycle Time & Notifications
 // ============================================
 
-func (s *taskService) UpdateStatus(ctx context.Context, taskID, status, userID string) error {
+func (s *taskService) UpdateStatus(ctx context.Context, taskID, status, userID string, version *int) error {
 	task, err := s.taskRepo.FindByID(ctx, taskID)
 	if err != nil || task == nil {
-		return ErrNotFound
+		return ErrTaskNotFound
 	}
 
 	if !s.permService.CanEditTask(ctx, userID, taskID) {
 		return ErrUnauthorized
 	}
 
+	if version != nil && *version != task.Version {
+		return ErrStaleVersion
+	}
+	if err := s.validateStatus(ctx, task.ProjectID, status); err != nil {
+		return err
+	}
+
 	oldStatus := task.Status
 
 	// Don't process if status hasn't changed
@@ -935,21 +1229,34 @@ func (s *taskService) UpdateStatus(ctx context.Context, taskID, status, userID s
 		return nil
 	}
 
+	if err := s.checkStatusMove(ctx, task, status); err != nil {
+		return err
+	}
+
+	// Update task with cycle time calculation (handled in repository)
+	if err := s.taskRepo.UpdateStatus(ctx, taskID, status, version); err != nil {
+		return taskWriteError(err)
+	}
+
 	// Record status history for analytics
 	if s.commitmentRepo != nil {
 		if err := s.commitmentRepo.RecordStatusChange(ctx, taskID, oldStatus, status, &userID); err != nil {
-			log.Printf("⚠️ Failed to record status history: %v", err)
+			slog.WarnContext(ctx, "failed to record task status history", "taskID", task.ID, "error", err)
 		}
 	}
 
-	// Update task with cycle time calculation (handled in repository)
-	if err := s.taskRepo.UpdateStatus(ctx, taskID, status); err != nil {
-		return err
-	}
+	after := *task
+	after.Status = status
+	s.recordFieldChanges(ctx, task, &after, userID)
 
 	// ✅ Recalculate linked goal progress when task completes
 	if status == "done" {
 		s.recalculateLinkedGoals(ctx, taskID)
+		s.unblockDependents(ctx, task, userID)
+	}
+	if task.ParentTaskID != nil {
+		task.Status = status
+		s.onSubtaskStatusChanged(ctx, task, userID)
 	}
 
 	// ============================================
@@ -974,20 +1281,12 @@ func (s *taskService) UpdateStatus(ctx context.Context, taskID, status, userID s
 		}
 	}
 
-	for _, watcherID := range task.WatcherIDs {
-		if watcherID != userID && !notifiedUsers[watcherID] {
-			s.notificationSvc.SendTaskStatusChangedBy(
-				ctx,
-				watcherID,
-				userID,
-				task.Title,
-				task.ID,
-				task.ProjectID,
-				oldStatus,
-				status,
-			)
-		}
-	}
+	s.notifyWatchers(ctx, task, userID, notifiedUsers,
+		notification.TypeTaskStatusChanged,
+		"Task Status Changed",
+		fmt.Sprintf("'%s' moved from %s to %s", task.Title, formatStatus(oldStatus), formatStatus(status)),
+		"status", status,
+	)
 
 	// ============================================
 	// REALTIME BROADCAST
@@ -1030,6 +1329,97 @@ func (s *taskService) recordStatusHistory(
 	})
 }
 
+// recordFieldChanges logs an "updated" activity for each field that differs
+// between before and after, so the history is the same whichever endpoint
+// made the change. A status change is logged as status history.
+func (s *taskService) recordFieldChanges(ctx context.Context, before, after *repository.Task, userID string) {
+	if s.activityRepo == nil {
+		return
+	}
+
+	if before.Status != after.Status {
+		s.recordStatusHistory(ctx, after.ID, before.Status, after.Status, userID)
+	}
+	for _, change := range taskFieldChanges(before, after) {
+		if err := s.activityRepo.Create(ctx, &repository.TaskActivity{
+			TaskID:    after.ID,
+			UserID:    &userID,
+			Action:    "updated",
+			FieldName: strPtr(change.field),
+			OldValue:  change.oldValue,
+			NewValue:  change.newValue,
+		}); err != nil {
+			slog.WarnContext(ctx, "failed to log task activity", "taskID", after.ID, "field", change.field, "error", err)
+		}
+	}
+}
+
+// taskFieldChange is one changed field, with values as stored in the activity
+// log: nil for no value, dates as YYYY-MM-DD and ID lists sorted and comma
+// separated
+type taskFieldChange struct {
+	field    string
+	oldValue *string
+	newValue *string
+}
+
+func taskFieldChanges(before, after *repository.Task) []taskFieldChange {
+	var changes []taskFieldChange
+	add := func(field string, oldValue, newValue *string) {
+		if oldValue == nil && newValue == nil {
+			return
+		}
+		if oldValue != nil && newValue != nil && *oldValue == *newValue {
+			return
+		}
+		changes = append(changes, taskFieldChange{field, oldValue, newValue})
+	}
+
+	add("title", &before.Title, &after.Title)
+	add("description", before.Description, after.Description)
+	add("priority", &before.Priority, &after.Priority)
+	add("type", before.Type, after.Type)
+	add("sprint_id", before.SprintID, after.SprintID)
+	add("assignees", activityIDList(before.AssigneeIDs), activityIDList(after.AssigneeIDs))
+	add("labels", activityIDList(before.LabelIDs), activityIDList(after.LabelIDs))
+	add("story_points", activityInt(before.StoryPoints), activityInt(after.StoryPoints))
+	add("estimated_hours", activityFloat(before.EstimatedHours), activityFloat(after.EstimatedHours))
+	add("actual_hours", activityFloat(before.ActualHours), activityFloat(after.ActualHours))
+	add("start_date", activityDate(before.StartDate), activityDate(after.StartDate))
+	add("due_date", activityDate(before.DueDate), activityDate(after.DueDate))
+	return changes
+}
+
+func activityIDList(ids []string) *string {
+	if len(ids) == 0 {
+		return nil
+	}
+	sorted := append([]string(nil), ids...)
+	sort.Strings(sorted)
+	return strPtr(strings.Join(sorted, ","))
+}
+
+func activityInt(v *int) *string {
+	if v == nil {
+		return nil
+	}
+	return strPtr(strconv.Itoa(*v))
+}
+
+func activityFloat(v *float64) *string {
+	if v == nil {
+		return nil
+	}
+	return strPtr(strconv.FormatFloat(*v, 'f', -1, 64))
+}
+
+func activityDate(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+	return strPtr(t.Format("2006-01-02"))
+}
+
 func (s *taskService) updateCycleTimeFields(
 	ctx context.Context,
 	task *repository.Task,
@@ -1082,16 +1472,39 @@ func (s *taskService) recalculateLinkedGoals(ctx context.Context, taskID string)
 	// Recalculate each goal's progress
 	for _, goal := range goals {
 		if err := s.goalService.RecalculateGoalProgress(ctx, goal.ID); err != nil {
-			log.Printf("Failed to recalculate goal %s progress: %v", goal.ID, err)
+			slog.WarnContext(ctx, "failed to recalculate goal progress", "goalID", goal.ID, "error", err)
 		}
 	}
 }
 
-func (s *taskService) UpdatePriority(ctx context.Context, taskID, priority, userID string) error {
+func (s *taskService) UpdatePriority(ctx context.Context, taskID, priority, userID string, version *int) error {
 	if !s.permService.CanEditTask(ctx, userID, taskID) {
 		return ErrUnauthorized
 	}
-	return s.taskRepo.UpdatePriority(ctx, taskID, priority)
+	if err := validatePriority(priority); err != nil {
+		return err
+	}
+
+	task, err := s.taskRepo.FindByID(ctx, taskID)
+	if err != nil || task == nil {
+		return ErrTaskNotFound
+	}
+	if err := s.taskRepo.UpdatePriority(ctx, taskID, priority, version); err != nil {
+		return taskWriteError(err)
+	}
+
+	after := *task
+	after.Priority = priority
+	s.recordFieldChanges(ctx, task, &after, userID)
+	return nil
+}
+
+// taskWriteError reports a write that lost an optimistic-concurrency race as ErrStaleVersion
+func taskWriteError(err error) error {
+	if errors.Is(err, repository.ErrVersionConflict) {
+		return ErrStaleVersion
+	}
+	return err
 }
 
 // ============================================
@@ -1101,7 +1514,7 @@ func (s *taskService) UpdatePriority(ctx context.Context, taskID, priority, user
 func (s *taskService) AssignTask(ctx context.Context, taskID, assigneeID, actorID string) error {
 	task, err := s.taskRepo.FindByID(ctx, taskID)
 	if err != nil || task == nil {
-		return ErrNotFound
+		return ErrTaskNotFound
 	}
 
 	if !s.permService.CanEditTask(ctx, actorID, taskID) {
@@ -1115,7 +1528,7 @@ func (s *taskService) AssignTask(ctx context.Context, taskID, assigneeID, actorI
 
 	// ✅ Check if user is already assigned
 	if contains(task.AssigneeIDs, assigneeID) {
-		log.Printf("User %s already assigned to task %s", assigneeID, taskID)
+		slog.DebugContext(ctx, "user already assigned to task", "assigneeID", assigneeID, "taskID", taskID)
 		return nil // Not an error, just skip
 	}
 
@@ -1123,7 +1536,12 @@ func (s *taskService) AssignTask(ctx context.Context, taskID, assigneeID, actorI
 		return err
 	}
 
-	// ✅ NOTIFICATIONS - Only send assignment notification
+	after := *task
+	after.AssigneeIDs = append(append([]string(nil), task.AssigneeIDs...), assigneeID)
+	s.recordFieldChanges(ctx, task, &after, actorID)
+
+	// ✅ NOTIFICATIONS - assignee gets the assignment, watchers get a single update
+	notifiedUsers := map[string]bool{assigneeID: true}
 	if assigneeID != actorID {
 		s.notificationSvc.SendTaskAssignedBy(
 			ctx,
@@ -1135,6 +1553,17 @@ func (s *taskService) AssignTask(ctx context.Context, taskID, assigneeID, actorI
 		)
 	}
 
+	assigneeName := "someone"
+	if assignee, _ := s.userRepo.FindByID(ctx, assigneeID); assignee != nil {
+		assigneeName = assignee.Name
+	}
+	s.notifyWatchers(ctx, task, actorID, notifiedUsers,
+		notification.TypeTaskUpdated,
+		"Task Assigned",
+		fmt.Sprintf("'%s' was assigned to %s", task.Title, assigneeName),
+		"assignee", assigneeID,
+	)
+
 	// ✅ Broadcast task update (UI needs to know assignees changed)
 	if s.broadcaster != nil {
 		// Refresh task to get updated assignees list
@@ -1161,13 +1590,30 @@ func (s *taskService) UnassignTask(ctx context.Context, taskID, assigneeID, acto
 	if !s.permService.CanEditTask(ctx, actorID, taskID) {
 		return ErrUnauthorized
 	}
-	return s.taskRepo.RemoveAssignee(ctx, taskID, assigneeID)
+
+	task, err := s.taskRepo.FindByID(ctx, taskID)
+	if err != nil || task == nil {
+		return ErrTaskNotFound
+	}
+	if err := s.taskRepo.RemoveAssignee(ctx, taskID, assigneeID); err != nil {
+		return err
+	}
+
+	after := *task
+	after.AssigneeIDs = nil
+	for _, id := range task.AssigneeIDs {
+		if id != assigneeID {
+			after.AssigneeIDs = append(after.AssigneeIDs, id)
+		}
+	}
+	s.recordFieldChanges(ctx, task, &after, actorID)
+	return nil
 }
 
 func (s *taskService) AddWatcher(ctx context.Context, taskID, watcherID, actorID string) error {
 	task, err := s.taskRepo.FindByID(ctx, taskID)
 	if err != nil || task == nil {
-		return ErrNotFound
+		return ErrTaskNotFound
 	}
 
 	// ✅ Verify watcher has access to project
@@ -1183,91 +1629,424 @@ func (s *taskService) RemoveWatcher(ctx context.Context, taskID, watcherID, acto
 	return s.taskRepo.RemoveWatcher(ctx, taskID, watcherID)
 }
 
+// MarkComplete moves the task to "done". It is a status change like any other,
+// so the workflow and WIP rules, notifications and unblocking all apply.
 func (s *taskService) MarkComplete(ctx context.Context, taskID, userID string) error {
-	if !s.permService.CanEditTask(ctx, userID, taskID) {
-		return ErrUnauthorized
-	}
-	return s.taskRepo.MarkComplete(ctx, taskID)
+	return s.UpdateStatus(ctx, taskID, types.StatusDone, userID, nil)
 }
 
 func (s *taskService) MoveToSprint(ctx context.Context, taskID, sprintID, userID string) error {
 	task, err := s.taskRepo.FindByID(ctx, taskID)
 	if err != nil || task == nil {
-		return ErrNotFound
+		return ErrTaskNotFound
 	}
 
 	if !s.permService.CanEditTask(ctx, userID, taskID) {
 		return ErrUnauthorized
 	}
 
+	previous := *task
 	task.SprintID = &sprintID
-	return s.taskRepo.Update(ctx, task)
-}
-
-// In task_service.go, add these methods:
-
-func (s *taskService) ConvertToSubtask(ctx context.Context, taskID, parentTaskID, userID string) error {
-	task, err := s.taskRepo.FindByID(ctx, taskID)
-	if err != nil || task == nil {
-		return ErrNotFound
+	if err := s.taskRepo.Update(ctx, task); err != nil {
+		return taskWriteError(err)
 	}
 
-	if !s.permService.CanEditTask(ctx, userID, taskID) {
-		return ErrUnauthorized
-	}
+	s.recordSprintMoves(ctx, []*repository.Task{&previous}, &sprintID, &userID)
+	return nil
+}
 
-	parentTask, err := s.taskRepo.FindByID(ctx, parentTaskID)
-	if err != nil || parentTask == nil {
-		return ErrNotFound
-	}
+// recordSprintEntry records a task created directly in a sprint
+func (s *taskService) recordSprintEntry(ctx context.Context, task *repository.Task, changedBy *string) {
+	entered := *task
+	entered.SprintID = nil
+	s.recordSprintMoves(ctx, []*repository.Task{&entered}, task.SprintID, changedBy)
+}
 
-	// Verify same project
-	if task.ProjectID != parentTask.ProjectID {
-		return ErrInvalidInput
+// recordSprintMoves writes the sprint membership history of tasks moved to
+// sprintID: a removal from the sprint each task was in and an addition to the
+// new one. A nil or empty sprintID only records the removal. The tasks must be
+// loaded before the move; a new task is passed with a nil SprintID.
+func (s *taskService) recordSprintMoves(ctx context.Context, tasks []*repository.Task, sprintID, changedBy *string) {
+	if s.commitmentRepo == nil {
+		return
 	}
 
-	// Prevent circular reference
-	if parentTask.ParentTaskID != nil && *parentTask.ParentTaskID == taskID {
-		return ErrInvalidInput
+	to := ""
+	if sprintID != nil {
+		to = *sprintID
 	}
 
-	task.ParentTaskID = &parentTaskID
-	if err := s.taskRepo.Update(ctx, task); err != nil {
-		return err
+	var changes []*repository.SprintMembershipChange
+	for _, task := range tasks {
+		from := ""
+		if task.SprintID != nil {
+			from = *task.SprintID
+		}
+		if from == to {
+			continue
+		}
+		points := 0
+		if task.StoryPoints != nil {
+			points = *task.StoryPoints
+		}
+		if from != "" {
+			changes = append(changes, &repository.SprintMembershipChange{
+				SprintID: from, TaskID: task.ID, Action: repository.SprintMembershipRemoved,
+				StoryPoints: points, ChangedBy: changedBy,
+			})
+		}
+		if to != "" {
+			changes = append(changes, &repository.SprintMembershipChange{
+				SprintID: to, TaskID: task.ID, Action: repository.SprintMembershipAdded,
+				StoryPoints: points, ChangedBy: changedBy,
+			})
+		}
 	}
-
-	// Broadcast update
-	if s.broadcaster != nil {
-		s.broadcaster.BroadcastTaskUpdated(
-			task.ProjectID,
-			s.taskToMap(task),
-			[]string{"converted to subtask"},
-						userID,
-
-		)
+	if len(changes) == 0 {
+		return
 	}
 
-	return nil
+	if err := s.commitmentRepo.RecordMembershipChanges(ctx, changes); err != nil {
+		slog.WarnContext(ctx, "failed to record sprint membership history", "error", err)
+	}
 }
 
-// Add helper method to convert subtask to main task
-func (s *taskService) PromoteToTask(ctx context.Context, taskID, userID string) error {
+// ============================================
+// MOVE TO PROJECT
+// ============================================
+
+// MoveToProject moves a task and its subtasks to another project. Each one is
+// re-keyed with the target's next number and taken out of its sprint, since
+// sprints belong to one project. Labels are matched by name in the target and
+// dropped when it has no label of that name, and a status the target's
+// workflow lacks falls back to its first column. Assignees and watchers who
+// cannot access the target are dropped. A subtask moved on its own is
+// detached from its parent. The whole tree moves in one transaction.
+// Comments, attachments and history stay with the task, which keeps its ID.
+func (s *taskService) MoveToProject(ctx context.Context, taskID, targetProjectID, userID string) (*repository.Task, error) {
 	task, err := s.taskRepo.FindByID(ctx, taskID)
 	if err != nil || task == nil {
-		return ErrNotFound
+		return nil, ErrTaskNotFound
 	}
 
 	if !s.permService.CanEditTask(ctx, userID, taskID) {
-		return ErrUnauthorized
+		return nil, ErrUnauthorized
 	}
 
-	if task.ParentTaskID == nil {
-		return ErrInvalidInput // Already a main task
+	if task.ProjectID == targetProjectID {
+		return nil, fmt.Errorf("%w: the task is already in this project", ErrInvalidInput)
+	}
+	target, err := s.projectRepo.FindByID(ctx, targetProjectID)
+	if err != nil || target == nil {
+		return nil, ErrProjectNotFound
+	}
+	if !s.permService.CanCreateTask(ctx, userID, targetProjectID) {
+		return nil, ErrUnauthorized
 	}
 
+	tasks, err := s.subtaskTree(ctx, task)
+	if err != nil {
+		return nil, err
+	}
+
+	sourceProjectID := task.ProjectID
+	oldParentID := task.ParentTaskID
+	fallbackStatus := s.defaultStatus(ctx, targetProjectID)
+	labelMap := make(map[string]string)
+	canAccess := make(map[string]bool)
+
+	befores := make([]repository.Task, len(tasks))
+	for i, t := range tasks {
+		befores[i] = *t
+		t.ProjectID = targetProjectID
+		t.SprintID = nil
+		if t.ID == task.ID {
+			t.ParentTaskID = nil
+		}
+		if s.validateStatus(ctx, targetProjectID, t.Status) != nil {
+			t.Status = fallbackStatus
+		}
+		if t.LabelIDs, err = s.matchLabelsInProject(ctx, t.LabelIDs, targetProjectID, labelMap); err != nil {
+			return nil, err
+		}
+		watcherIDs, err := s.taskWatcherIDs(ctx, t)
+		if err != nil {
+			return nil, err
+		}
+		t.AssigneeIDs = s.usersWithProjectAccess(ctx, t.AssigneeIDs, targetProjectID, canAccess)
+		t.WatcherIDs = s.usersWithProjectAccess(ctx, watcherIDs, targetProjectID, canAccess)
+	}
+
+	if err := s.taskRepo.MoveToProject(ctx, tasks); err != nil {
+		return nil, taskWriteError(err)
+	}
+
+	var sprintChanges []*repository.SprintMembershipChange
+	for i, t := range tasks {
+		before := befores[i]
+		if s.activityRepo != nil {
+			if err := s.activityRepo.Create(ctx, &repository.TaskActivity{
+				TaskID:    t.ID,
+				UserID:    &userID,
+				Action:    "moved",
+				FieldName: strPtr("project"),
+				OldValue:  strPtr(s.getTaskKey(&before)),
+				NewValue:  strPtr(s.getTaskKey(t)),
+			}); err != nil {
+				slog.WarnContext(ctx, "failed to log task activity", "taskID", t.ID, "action", "moved", "error", err)
+			}
+		}
+		s.recordFieldChanges(ctx, &before, t, userID)
+
+		if before.SprintID != nil {
+			points := 0
+			if before.StoryPoints != nil {
+				points = *before.StoryPoints
+			}
+			sprintChanges = append(sprintChanges, &repository.SprintMembershipChange{
+				SprintID: *before.SprintID, TaskID: t.ID, Action: repository.SprintMembershipRemoved,
+				StoryPoints: points, ChangedBy: &userID,
+			})
+		}
+
+		if s.broadcaster != nil {
+			s.broadcaster.BroadcastTaskDeleted(sourceProjectID, t.ID, s.getTaskKey(&before), userID)
+			s.broadcaster.BroadcastTaskCreated(targetProjectID, s.taskToMap(t), userID)
+		}
+	}
+
+	if len(sprintChanges) > 0 && s.commitmentRepo != nil {
+		if err := s.commitmentRepo.RecordMembershipChanges(ctx, sprintChanges); err != nil {
+			slog.WarnContext(ctx, "failed to record sprint membership history", "error", err)
+		}
+	}
+
+	if oldParentID != nil {
+		s.onSubtaskStatusChanged(ctx, &repository.Task{ID: task.ID, ParentTaskID: oldParentID}, userID)
+	}
+
+	return task, nil
+}
+
+// subtaskTree returns root followed by all of its subtasks, each after its
+// parent
+func (s *taskService) subtaskTree(ctx context.Context, root *repository.Task) ([]*repository.Task, error) {
+	tasks := []*repository.Task{root}
+	seen := map[string]bool{root.ID: true}
+	for i := 0; i < len(tasks); i++ {
+		children, err := s.taskRepo.FindByParentTaskID(ctx, tasks[i].ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, child := range children {
+			if seen[child.ID] {
+				continue
+			}
+			if len(seen) >= maxParentChainWalk {
+				return nil, fmt.Errorf("%w: the task has too many subtasks to move", ErrInvalidInput)
+			}
+			seen[child.ID] = true
+			tasks = append(tasks, child)
+		}
+	}
+	return tasks, nil
+}
+
+// usersWithProjectAccess keeps the users who can access projectID, so a
+// moved task is not left assigned to or watched by someone who cannot open
+// it. canAccess caches the checks across the moved tasks.
+func (s *taskService) usersWithProjectAccess(ctx context.Context, userIDs []string, projectID string, canAccess map[string]bool) []string {
+	kept := []string{}
+	for _, id := range userIDs {
+		ok, checked := canAccess[id]
+		if !checked {
+			ok = s.permService.CanAccessProject(ctx, id, projectID)
+			canAccess[id] = ok
+		}
+		if ok {
+			kept = append(kept, id)
+		}
+	}
+	return kept
+}
+
+// matchLabelsInProject returns the labels of projectID named like the given
+// ones, leaving out those it has no label of that name for. labelMap caches
+// the matches, with "" for a label that is dropped.
+func (s *taskService) matchLabelsInProject(ctx context.Context, labelIDs []string, projectID string, labelMap map[string]string) ([]string, error) {
+	matched := []string{}
+	for _, labelID := range labelIDs {
+		id, ok := labelMap[labelID]
+		if !ok {
+			label, err := s.labelRepo.FindByID(ctx, labelID)
+			if err != nil {
+				return nil, err
+			}
+			if label != nil && label.ProjectID != projectID {
+				label, err = s.labelRepo.FindByName(ctx, projectID, label.Name)
+				if err != nil {
+					return nil, err
+				}
+			}
+			if label != nil {
+				id = label.ID
+			}
+			labelMap[labelID] = id
+		}
+		if id != "" && !contains(matched, id) {
+			matched = append(matched, id)
+		}
+	}
+	return matched, nil
+}
+
+// In task_service.go, add these methods:
+
+func (s *taskService) ConvertToSubtask(ctx context.Context, taskID, parentTaskID, userID string) error {
+	task, err := s.taskRepo.FindByID(ctx, taskID)
+	if err != nil || task == nil {
+		return ErrTaskNotFound
+	}
+
+	if !s.permService.CanEditTask(ctx, userID, taskID) {
+		return ErrUnauthorized
+	}
+
+	parentTask, err := s.taskRepo.FindByID(ctx, parentTaskID)
+	if err != nil || parentTask == nil {
+		return ErrTaskNotFound
+	}
+
+	// Verify same project
+	if task.ProjectID != parentTask.ProjectID {
+		return ErrInvalidInput
+	}
+
+	// Prevent circular references and too deep nesting
+	height, err := s.subtreeHeight(ctx, taskID)
+	if err != nil {
+		return err
+	}
+	if err := s.checkSubtaskPlacement(ctx, taskID, parentTask, height); err != nil {
+		return err
+	}
+
+	oldParentID := task.ParentTaskID
+	task.ParentTaskID = &parentTaskID
+	if err := s.taskRepo.Update(ctx, task); err != nil {
+		return taskWriteError(err)
+	}
+
+	// Broadcast update
+	if s.broadcaster != nil {
+		s.broadcaster.BroadcastTaskUpdated(
+			task.ProjectID,
+			s.taskToMap(task),
+			[]string{"converted to subtask"},
+						userID,
+
+		)
+	}
+
+	if oldParentID != nil && *oldParentID != parentTaskID {
+		s.onSubtaskStatusChanged(ctx, &repository.Task{ID: task.ID, ParentTaskID: oldParentID}, userID)
+	}
+	s.onSubtaskStatusChanged(ctx, task, userID)
+
+	return nil
+}
+
+// Upper bound on parent links followed, so that a loop already in the data
+// can't hang a request
+const maxParentChainWalk = 1000
+
+// checkSubtaskPlacement rejects making parent the parent of taskID ("" for a
+// task being created) when parent is the task itself or one of its
+// descendants, or when the task and the height levels of subtasks below it
+// would end up deeper than maxSubtaskDepth
+func (s *taskService) checkSubtaskPlacement(ctx context.Context, taskID string, parent *repository.Task, height int) error {
+	if taskID != "" && parent.ID == taskID {
+		return fmt.Errorf("%w: a task cannot be its own parent", ErrInvalidInput)
+	}
+
+	// Walk up from the parent; the task must not be among its ancestors
+	depth := 1 // level the task would be at
+	visited := map[string]bool{parent.ID: true}
+	for current := parent; current.ParentTaskID != nil; depth++ {
+		nextID := *current.ParentTaskID
+		if taskID != "" && nextID == taskID {
+			return fmt.Errorf("%w: a task cannot become a subtask of its own subtask", ErrInvalidInput)
+		}
+		if visited[nextID] || len(visited) >= maxParentChainWalk {
+			return fmt.Errorf("%w: the parent task's hierarchy contains a loop", ErrInvalidInput)
+		}
+		visited[nextID] = true
+
+		next, err := s.taskRepo.FindByID(ctx, nextID)
+		if err != nil {
+			return err
+		}
+		if next == nil {
+			break
+		}
+		current = next
+	}
+
+	if s.maxSubtaskDepth > 0 && depth+height > s.maxSubtaskDepth {
+		return fmt.Errorf("%w: subtasks can be nested at most %d levels deep", ErrInvalidInput, s.maxSubtaskDepth)
+	}
+	return nil
+}
+
+// subtreeHeight returns how many levels of subtasks are below taskID: 0 when
+// it has none. It stops counting past maxSubtaskDepth, which is enough to
+// reject a move.
+func (s *taskService) subtreeHeight(ctx context.Context, taskID string) (int, error) {
+	if s.maxSubtaskDepth <= 0 {
+		return 0, nil
+	}
+
+	height := 0
+	level := []string{taskID}
+	for len(level) > 0 && height <= s.maxSubtaskDepth {
+		var next []string
+		for _, id := range level {
+			children, err := s.taskRepo.FindByParentTaskID(ctx, id)
+			if err != nil {
+				return 0, err
+			}
+			for _, child := range children {
+				next = append(next, child.ID)
+			}
+		}
+		if len(next) == 0 {
+			break
+		}
+		height++
+		level = next
+	}
+	return height, nil
+}
+
+// Add helper method to convert subtask to main task
+func (s *taskService) PromoteToTask(ctx context.Context, taskID, userID string) error {
+	task, err := s.taskRepo.FindByID(ctx, taskID)
+	if err != nil || task == nil {
+		return ErrTaskNotFound
+	}
+
+	if !s.permService.CanEditTask(ctx, userID, taskID) {
+		return ErrUnauthorized
+	}
+
+	if task.ParentTaskID == nil {
+		return ErrInvalidInput // Already a main task
+	}
+
+	oldParentID := *task.ParentTaskID
 	task.ParentTaskID = nil
 	if err := s.taskRepo.Update(ctx, task); err != nil {
-		return err
+		return taskWriteError(err)
 	}
 
 	if s.broadcaster != nil {
@@ -1279,6 +2058,7 @@ func (s *taskService) PromoteToTask(ctx context.Context, taskID, userID string)
 
 		)
 	}
+	s.onSubtaskStatusChanged(ctx, &repository.Task{ID: task.ID, ParentTaskID: &oldParentID}, userID)
 
 	return nil
 }
@@ -1288,35 +2068,95 @@ func (s *taskService) AddComment(
 	ctx context.Context,
 	taskID, userID, content string,
 	mentionedUsers []string,
+	parentCommentID *string,
 ) (*repository.TaskComment, error) {
 
-	if !s.permService.CanAccessTask(ctx, userID, taskID) {
-		log.Printf("[AddComment] unauthorized access userID=%s taskID=%s", userID, taskID)
+	if !s.permService.CanCommentOnTask(ctx, userID, taskID) {
+		slog.DebugContext(ctx, "add comment denied", "taskID", taskID)
 		return nil, ErrUnauthorized
 	}
+	return s.addComment(ctx, taskID, userID, content, mentionedUsers, parentCommentID)
+}
+
+// AddSystemComment posts a comment as the integration bot, which is not a
+// project member, so no permission check applies
+func (s *taskService) AddSystemComment(ctx context.Context, taskID, content string) (*repository.TaskComment, error) {
+	return s.addComment(ctx, taskID, repository.IntegrationBotUserID, content, nil, nil)
+}
 
+// addComment stores a comment once the author may post it, then notifies
+// and broadcasts
+func (s *taskService) addComment(
+	ctx context.Context,
+	taskID, userID, content string,
+	mentionedUsers []string,
+	parentCommentID *string,
+) (*repository.TaskComment, error) {
 	content = strings.TrimSpace(content)
 	if content == "" {
-		log.Printf("[AddComment] empty content userID=%s taskID=%s", userID, taskID)
+		slog.DebugContext(ctx, "add comment rejected: empty content", "taskID", taskID)
 		return nil, ErrBadRequest
 	}
 
 	// Get task info for notifications
 	task, err := s.taskRepo.FindByID(ctx, taskID)
 	if err != nil || task == nil {
-		return nil, ErrNotFound
+		return nil, ErrTaskNotFound
+	}
+
+	// Replies nest one level: answering a reply joins its parent's thread
+	var parent *repository.TaskComment
+	if parentCommentID != nil && *parentCommentID != "" {
+		parent, err = s.commentRepo.FindByID(ctx, *parentCommentID)
+		if err != nil {
+			return nil, err
+		}
+		if parent == nil || parent.TaskID != taskID {
+			slog.DebugContext(ctx, "add comment rejected: invalid parent", "taskID", taskID, "parentCommentID", *parentCommentID)
+			return nil, fmt.Errorf("%w: parent comment not found on this task", ErrInvalidInput)
+		}
+		if parent.ParentCommentID != nil {
+			parent, err = s.commentRepo.FindByID(ctx, *parent.ParentCommentID)
+			if err != nil {
+				return nil, err
+			}
+			if parent == nil {
+				return nil, fmt.Errorf("%w: parent comment not found on this task", ErrInvalidInput)
+			}
+		}
+	}
+
+	// ✅ Collect mentions: explicit IDs from the client plus @handles in the content
+	mentionedUserIDs := s.extractMentionedUserIDs(ctx, content, userID)
+	for _, id := range mentionedUsers {
+		if id != "" && id != userID {
+			mentionedUserIDs[id] = true
+		}
+	}
+
+	// Only users who can see the task's project may be mentioned
+	validMentions := make([]string, 0, len(mentionedUserIDs))
+	for mentionedUserID := range mentionedUserIDs {
+		hasAccess, _, err := s.memberService.HasEffectiveAccess(ctx, EntityTypeProject, task.ProjectID, mentionedUserID)
+		if err != nil || !hasAccess {
+			slog.DebugContext(ctx, "skipping mention without project access", "mentionedUserID", mentionedUserID, "taskID", taskID)
+			continue
+		}
+		validMentions = append(validMentions, mentionedUserID)
 	}
 
 	comment := &repository.TaskComment{
 		TaskID:         taskID,
 		UserID:         userID,
 		Content:        content,
-		MentionedUsers: mentionedUsers,
+		MentionedUsers: validMentions,
+	}
+	if parent != nil {
+		comment.ParentCommentID = &parent.ID
 	}
 
 	if err := s.commentRepo.Create(ctx, comment); err != nil {
-		log.Printf("[AddComment] failed to create comment userID=%s taskID=%s err=%v",
-			userID, taskID, err)
+		slog.ErrorContext(ctx, "failed to create comment", "taskID", taskID, "error", err)
 		return nil, err
 	}
 
@@ -1327,32 +2167,47 @@ func (s *taskService) AddComment(
 		commenterName = commenter.Name
 	}
 
-	// ✅ Parse mentions FIRST to get mentioned user IDs
-	mentionedUserIDs := make(map[string]bool)
-	if s.notificationSvc != nil {
-		// Extract mentioned user IDs from content
-		mentionedUserIDs = s.extractMentionedUserIDs(ctx, content, userID)
-	}
-
 	// ✅ Track who gets notified
 	notifiedUsers := make(map[string]bool)
 
 	// 1. Send MENTION notifications (highest priority)
-	for mentionedUserID := range mentionedUserIDs {
-		if mentionedUserID != userID {
-			s.notificationSvc.SendMention(
-				ctx,
-				mentionedUserID,
-				commenterName,
-				task.Title,
-				task.ID,
-				task.ProjectID,
-			)
-			notifiedUsers[mentionedUserID] = true
-		}
+	for _, mentionedUserID := range validMentions {
+		s.notificationSvc.SendCommentMention(
+			ctx,
+			mentionedUserID,
+			commenterName,
+			task.Title,
+			s.getTaskKey(task),
+			task.ID,
+			task.ProjectID,
+			commentSnippet(content),
+		)
+		notifiedUsers[mentionedUserID] = true
+	}
+
+	// 2. Tell the author of the parent comment about the reply
+	if parent != nil && parent.UserID != userID && !notifiedUsers[parent.UserID] {
+		s.notificationSvc.SendBatchNotifications(
+			ctx,
+			[]string{parent.UserID},
+			userID,
+			notification.TypeCommentReplied,
+			"New Reply",
+			fmt.Sprintf("%s replied to your comment on: %s", commenterName, task.Title),
+			map[string]interface{}{
+				"taskId":          task.ID,
+				"taskKey":         s.getTaskKey(task),
+				"projectId":       task.ProjectID,
+				"commentId":       comment.ID,
+				"parentCommentId": parent.ID,
+				"comment":         commentSnippet(content),
+				"action":          "view_task",
+			},
+		)
+		notifiedUsers[parent.UserID] = true
 	}
 
-	// 2. Send COMMENT notifications to assignees (only if NOT mentioned)
+	// 3. Send COMMENT notifications to assignees (only if NOT already notified)
 	for _, assigneeID := range task.AssigneeIDs {
 		if assigneeID != userID && !notifiedUsers[assigneeID] {
 			s.notificationSvc.SendTaskCommented(
@@ -1367,31 +2222,25 @@ func (s *taskService) AddComment(
 		}
 	}
 
-	// 3. Send COMMENT notifications to watchers (only if NOT already notified)
-	for _, watcherID := range task.WatcherIDs {
-		if watcherID != userID && !notifiedUsers[watcherID] {
-			s.notificationSvc.SendTaskCommented(
-				ctx,
-				watcherID,
-				commenterName,
-				task.Title,
-				task.ID,
-				task.ProjectID,
-			)
-			notifiedUsers[watcherID] = true
-		}
-	}
+	// 4. Send COMMENT notifications to watchers (only if NOT already notified)
+	s.notifyWatchers(ctx, task, userID, notifiedUsers,
+		notification.TypeTaskCommented,
+		"New Comment",
+		fmt.Sprintf("%s commented on: %s", commenterName, task.Title),
+		"comment", commentSnippet(content),
+	)
 
-	// 4. Broadcast comment
+	// 5. Broadcast comment
 	if s.broadcaster != nil {
 		s.broadcaster.BroadcastCommentAdded(
 			task.ProjectID,
 			task.ID,
 			map[string]interface{}{
-				"id":        comment.ID,
-				"content":   comment.Content,
-				"userId":    comment.UserID,
-				"createdAt": comment.CreatedAt,
+				"id":              comment.ID,
+				"content":         comment.Content,
+				"userId":          comment.UserID,
+				"parentCommentId": comment.ParentCommentID,
+				"createdAt":       comment.CreatedAt,
 			},
 			userID,
 		)
@@ -1403,8 +2252,7 @@ func (s *taskService) AddComment(
 		UserID: &userID,
 		Action: "commented",
 	}); err != nil {
-		log.Printf("[AddComment] activity log failed commentID=%s taskID=%s err=%v",
-			comment.ID, taskID, err)
+		slog.WarnContext(ctx, "failed to log comment activity", "commentID", comment.ID, "taskID", taskID, "error", err)
 	}
 
 	return comment, nil
@@ -1455,98 +2303,239 @@ func (s *taskService) ListComments(
 ) ([]*repository.TaskComment, error) {
 
     if !s.permService.CanAccessTask(ctx, userID, taskID) {
-        log.Printf(
-            "[ListComments] unauthorized access userID=%s taskID=%s",
-            userID, taskID,
-        )
+        slog.DebugContext(ctx, "list comments denied", "taskID", taskID)
         return nil, ErrUnauthorized
     }
 
     comments, err := s.commentRepo.FindByTaskID(ctx, taskID)
     if err != nil {
-        log.Printf(
-            "[ListComments] failed userID=%s taskID=%s err=%v",
-            userID, taskID, err,
-        )
+        slog.ErrorContext(ctx, "failed to list comments", "taskID", taskID, "error", err)
         return nil, err
     }
 
-    return comments, nil
-}
-
-
+    if err := s.attachCommentReactions(ctx, comments, userID); err != nil {
+        return nil, err
+    }
+    if err := s.attachCommentAuthors(ctx, comments); err != nil {
+        return nil, err
+    }
 
-// ============================================
-// UPDATE COMMENT - With Notifications
-// ============================================
+    return threadComments(comments), nil
+}
 
-func (s *taskService) UpdateComment(
-	ctx context.Context,
-	commentID, userID, content string,
-) error {
+// attachCommentAuthors loads the authors of comments in one query. Authors
+// who deleted their account are still returned, so the comment keeps an
+// author that renders as "Deleted user".
+func (s *taskService) attachCommentAuthors(ctx context.Context, comments []*repository.TaskComment) error {
+	seen := make(map[string]bool, len(comments))
+	ids := make([]string, 0, len(comments))
+	for _, c := range comments {
+		if !seen[c.UserID] {
+			seen[c.UserID] = true
+			ids = append(ids, c.UserID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
 
-	comment, err := s.commentRepo.FindByID(ctx, commentID)
+	users, err := s.userRepo.FindByIDs(ctx, ids)
 	if err != nil {
-		log.Printf("[UpdateComment] find failed commentID=%s err=%v", commentID, err)
 		return err
 	}
-
-	if comment == nil {
-		log.Printf("[UpdateComment] not found commentID=%s", commentID)
-		return ErrNotFound
+	byID := make(map[string]*repository.User, len(users))
+	for _, u := range users {
+		byID[u.ID] = u
 	}
-
-	if comment.UserID != userID {
-		log.Printf("[UpdateComment] unauthorized userID=%s commentID=%s", userID, commentID)
-		return ErrUnauthorized
+	for _, c := range comments {
+		c.User = byID[c.UserID]
 	}
+	return nil
+}
 
-	content = strings.TrimSpace(content)
-	if content == "" {
-		log.Printf("[UpdateComment] empty content userID=%s commentID=%s", userID, commentID)
-		return ErrBadRequest
+// attachCommentReactions fills the reaction counts of comments as seen by userID
+func (s *taskService) attachCommentReactions(ctx context.Context, comments []*repository.TaskComment, userID string) error {
+	ids := make([]string, 0, len(comments))
+	for _, c := range comments {
+		ids = append(ids, c.ID)
 	}
 
-	comment.Content = content
-
-	if err := s.commentRepo.Update(ctx, comment); err != nil {
-		log.Printf("[UpdateComment] update failed commentID=%s err=%v", commentID, err)
+	summaries, err := s.commentRepo.SummarizeReactions(ctx, ids, userID)
+	if err != nil {
 		return err
 	}
-
-	// ✅ BROADCAST COMMENT UPDATE
-	if s.broadcaster != nil {
-		task, _ := s.taskRepo.FindByID(ctx, comment.TaskID)
-		if task != nil {
-			s.broadcaster.BroadcastCommentUpdated(
-				task.ProjectID,
-				comment.TaskID,
-				map[string]interface{}{
-					"id":        comment.ID,
-					"content":   comment.Content,
-					"userId":    comment.UserID,
-					"updatedAt": comment.UpdatedAt,
-				},
-				userID,
-			)
-		}
+	for _, c := range comments {
+		c.Reactions = summaries[c.ID]
 	}
+	return nil
+}
 
-	// Activity log
-	if err := s.activityRepo.Create(ctx, &repository.TaskActivity{
-		TaskID: comment.TaskID,
-		UserID: &userID,
-		Action: "comment_updated",
-	}); err != nil {
-		log.Printf("[UpdateComment] activity log failed commentID=%s err=%v", commentID, err)
-	}
+// maxReactionEmojiLength matches comment_reactions.emoji
+const maxReactionEmojiLength = 50
 
-	return nil
+// AddCommentReaction reacts to a comment with an emoji and returns the comment with updated counts
+func (s *taskService) AddCommentReaction(ctx context.Context, commentID, userID, emoji string) (*repository.TaskComment, error) {
+	return s.changeCommentReaction(ctx, commentID, userID, emoji, true)
 }
 
-// ============================================
-// DELETE COMMENT - With Notifications
-// ============================================
+// RemoveCommentReaction takes back the user's emoji reaction and returns the comment with updated counts
+func (s *taskService) RemoveCommentReaction(ctx context.Context, commentID, userID, emoji string) (*repository.TaskComment, error) {
+	return s.changeCommentReaction(ctx, commentID, userID, emoji, false)
+}
+
+func (s *taskService) changeCommentReaction(ctx context.Context, commentID, userID, emoji string, add bool) (*repository.TaskComment, error) {
+	emoji = strings.TrimSpace(emoji)
+	if emoji == "" || len(emoji) > maxReactionEmojiLength {
+		return nil, fmt.Errorf("%w: emoji is required and must be at most %d bytes", ErrInvalidInput, maxReactionEmojiLength)
+	}
+
+	comment, err := s.commentRepo.FindByID(ctx, commentID)
+	if err != nil {
+		return nil, err
+	}
+	if comment == nil {
+		return nil, ErrCommentNotFound
+	}
+
+	if !s.permService.CanAccessTask(ctx, userID, comment.TaskID) {
+		return nil, ErrUnauthorized
+	}
+
+	if add {
+		err = s.commentRepo.AddReaction(ctx, commentID, userID, emoji)
+	} else {
+		err = s.commentRepo.RemoveReaction(ctx, commentID, userID, emoji)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if s.broadcaster != nil {
+		if task, _ := s.taskRepo.FindByID(ctx, comment.TaskID); task != nil {
+			s.broadcaster.BroadcastCommentReaction(task.ProjectID, task.ID, commentID, userID, emoji, add)
+		}
+	}
+
+	if err := s.attachCommentReactions(ctx, []*repository.TaskComment{comment}, userID); err != nil {
+		return nil, err
+	}
+	return comment, nil
+}
+
+// threadComments groups replies under their top-level comment. Input and
+// output are oldest first, within threads as well.
+func threadComments(comments []*repository.TaskComment) []*repository.TaskComment {
+	byID := make(map[string]*repository.TaskComment, len(comments))
+	for _, c := range comments {
+		if c.ParentCommentID == nil {
+			byID[c.ID] = c
+		}
+	}
+
+	threads := make([]*repository.TaskComment, 0, len(byID))
+	for _, c := range comments {
+		if c.ParentCommentID == nil {
+			threads = append(threads, c)
+			continue
+		}
+		if parent, ok := byID[*c.ParentCommentID]; ok {
+			parent.Replies = append(parent.Replies, c)
+		}
+	}
+	return threads
+}
+
+
+
+// ============================================
+// UPDATE COMMENT - With Notifications
+// ============================================
+
+func (s *taskService) UpdateComment(
+	ctx context.Context,
+	commentID, userID, content string,
+) error {
+
+	comment, err := s.commentRepo.FindByID(ctx, commentID)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to load comment", "commentID", commentID, "error", err)
+		return err
+	}
+
+	if comment == nil {
+		slog.DebugContext(ctx, "update comment: not found", "commentID", commentID)
+		return ErrCommentNotFound
+	}
+
+	if comment.UserID != userID {
+		slog.DebugContext(ctx, "update comment denied", "commentID", commentID)
+		return ErrUnauthorized
+	}
+
+	content = strings.TrimSpace(content)
+	if content == "" {
+		slog.DebugContext(ctx, "update comment rejected: empty content", "commentID", commentID)
+		return ErrBadRequest
+	}
+
+	comment.Content = content
+
+	if err := s.commentRepo.Update(ctx, comment); err != nil {
+		slog.ErrorContext(ctx, "failed to update comment", "commentID", commentID, "error", err)
+		return err
+	}
+
+	// ✅ BROADCAST COMMENT UPDATE
+	if s.broadcaster != nil {
+		task, _ := s.taskRepo.FindByID(ctx, comment.TaskID)
+		if task != nil {
+			s.broadcaster.BroadcastCommentUpdated(
+				task.ProjectID,
+				comment.TaskID,
+				map[string]interface{}{
+					"id":        comment.ID,
+					"content":   comment.Content,
+					"userId":    comment.UserID,
+					"editedAt":  comment.EditedAt,
+					"updatedAt": comment.UpdatedAt,
+				},
+				userID,
+			)
+		}
+	}
+
+	// Activity log
+	if err := s.activityRepo.Create(ctx, &repository.TaskActivity{
+		TaskID: comment.TaskID,
+		UserID: &userID,
+		Action: "comment_updated",
+	}); err != nil {
+		slog.WarnContext(ctx, "failed to log comment activity", "commentID", commentID, "error", err)
+	}
+
+	return nil
+}
+
+// GetCommentHistory returns the previous versions of a comment, oldest first
+func (s *taskService) GetCommentHistory(ctx context.Context, commentID, userID string) ([]*repository.CommentEdit, error) {
+	comment, err := s.commentRepo.FindByID(ctx, commentID)
+	if err != nil {
+		return nil, err
+	}
+	if comment == nil {
+		return nil, ErrCommentNotFound
+	}
+
+	if !s.permService.CanAccessTask(ctx, userID, comment.TaskID) {
+		return nil, ErrUnauthorized
+	}
+
+	return s.commentRepo.FindEdits(ctx, commentID)
+}
+
+// ============================================
+// DELETE COMMENT - With Notifications
+// ============================================
 
 func (s *taskService) DeleteComment(
 	ctx context.Context,
@@ -1555,24 +2544,23 @@ func (s *taskService) DeleteComment(
 
 	comment, err := s.commentRepo.FindByID(ctx, commentID)
 	if err != nil {
-		log.Printf("[DeleteComment] find failed commentID=%s err=%v", commentID, err)
+		slog.ErrorContext(ctx, "failed to load comment", "commentID", commentID, "error", err)
 		return err
 	}
 
 	if comment == nil {
-		log.Printf("[DeleteComment] not found commentID=%s", commentID)
-		return ErrNotFound
+		slog.DebugContext(ctx, "delete comment: not found", "commentID", commentID)
+		return ErrCommentNotFound
 	}
 
 	if comment.UserID != userID &&
 		!s.permService.CanEditTask(ctx, userID, comment.TaskID) {
-		log.Printf("[DeleteComment] unauthorized userID=%s commentID=%s taskID=%s",
-			userID, commentID, comment.TaskID)
+		slog.DebugContext(ctx, "delete comment denied", "commentID", commentID, "taskID", comment.TaskID)
 		return ErrUnauthorized
 	}
 
 	if err := s.commentRepo.Delete(ctx, commentID); err != nil {
-		log.Printf("[DeleteComment] delete failed commentID=%s err=%v", commentID, err)
+		slog.ErrorContext(ctx, "failed to delete comment", "commentID", commentID, "error", err)
 		return err
 	}
 
@@ -1595,7 +2583,7 @@ func (s *taskService) DeleteComment(
 		UserID: &userID,
 		Action: "comment_deleted",
 	}); err != nil {
-		log.Printf("[DeleteComment] activity log failed commentID=%s err=%v", commentID, err)
+		slog.WarnContext(ctx, "failed to log comment activity", "commentID", commentID, "error", err)
 	}
 
 	return nil
@@ -1606,15 +2594,20 @@ func (s *taskService) DeleteComment(
 // ADD ATTACHMENT - With Notifications
 // ============================================
 
+// AddAttachment records an external link; uploaded files go through UploadAttachment
 func (s *taskService) AddAttachment(ctx context.Context, taskID, userID, filename, fileURL string, fileSize int64, mimeType string) (*repository.TaskAttachment, error) {
-	if !s.permService.CanAccessTask(ctx, userID, taskID) {
+	if !s.permService.CanEditTask(ctx, userID, taskID) {
 		return nil, ErrUnauthorized
 	}
 
+	if u, err := url.Parse(fileURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("%w: attachment URL must be an http or https link", ErrInvalidInput)
+	}
+
 	// Get task for notifications
 	task, err := s.taskRepo.FindByID(ctx, taskID)
 	if err != nil || task == nil {
-		return nil, ErrNotFound
+		return nil, ErrTaskNotFound
 	}
 
 	attachment := &repository.TaskAttachment{
@@ -1630,6 +2623,78 @@ func (s *taskService) AddAttachment(ctx context.Context, taskID, userID, filenam
 		return nil, err
 	}
 
+	s.attachmentAdded(ctx, task, userID, filename)
+	return attachment, nil
+}
+
+// UploadAttachment stores the uploaded bytes and records them as an attachment
+func (s *taskService) UploadAttachment(ctx context.Context, taskID, userID, filename string, file io.Reader, size int64) (*repository.TaskAttachment, error) {
+	if !s.permService.CanEditTask(ctx, userID, taskID) {
+		return nil, ErrUnauthorized
+	}
+	if s.store == nil {
+		return nil, fmt.Errorf("file storage is not configured")
+	}
+
+	task, err := s.taskRepo.FindByID(ctx, taskID)
+	if err != nil || task == nil {
+		return nil, ErrTaskNotFound
+	}
+
+	limits := s.uploadLimits
+	if limits.MaxFileSize > 0 && size > limits.MaxFileSize {
+		return nil, fmt.Errorf("%w: attachments must be at most %d MB", ErrFileTooLarge, limits.MaxFileSize>>20)
+	}
+	if limits.WorkspaceQuota > 0 {
+		used, err := s.attachmentRepo.TotalStoredSizeInTaskWorkspace(ctx, taskID)
+		if err != nil {
+			return nil, err
+		}
+		if used+size > limits.WorkspaceQuota {
+			return nil, fmt.Errorf("%w: workspace attachment storage is full (%d MB of %d MB used)",
+				ErrLimitExceeded, used>>20, limits.WorkspaceQuota>>20)
+		}
+	}
+
+	mimeType, content, err := storage.DetectContentType(file)
+	if err != nil {
+		return nil, err
+	}
+	// Sniffing only recognises a few formats; trust the extension for the rest
+	if mimeType == "application/octet-stream" {
+		if byExt := mime.TypeByExtension(path.Ext(filename)); byExt != "" {
+			mimeType = byExt
+		}
+	}
+
+	key := storage.NewKey("attachments/"+taskID, filename)
+	fileURL, err := s.store.Save(ctx, key, content, size, mimeType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to store attachment: %w", err)
+	}
+
+	attachment := &repository.TaskAttachment{
+		TaskID:     taskID,
+		UserID:     userID,
+		Filename:   path.Base(filename),
+		FileURL:    fileURL,
+		FileSize:   size,
+		MimeType:   mimeType,
+		StorageKey: &key,
+	}
+	if err := s.attachmentRepo.Create(ctx, attachment); err != nil {
+		s.store.Delete(ctx, key)
+		return nil, err
+	}
+
+	s.attachmentAdded(ctx, task, userID, attachment.Filename)
+	return attachment, nil
+}
+
+// attachmentAdded notifies assignees and watchers and logs the activity
+func (s *taskService) attachmentAdded(ctx context.Context, task *repository.Task, userID, filename string) {
+	taskID := task.ID
+
 	// ✅ NOTIFICATIONS START
 	// Get uploader info
 	uploader, _ := s.userRepo.FindByID(ctx, userID)
@@ -1688,8 +2753,6 @@ func (s *taskService) AddAttachment(ctx context.Context, taskID, userID, filenam
 		Action:   "added_attachment",
 		NewValue: &filename,
 	})
-
-	return attachment, nil
 }
 
 func (s *taskService) ListAttachments(ctx context.Context, taskID, userID string) ([]*repository.TaskAttachment, error) {
@@ -1702,7 +2765,7 @@ func (s *taskService) ListAttachments(ctx context.Context, taskID, userID string
 func (s *taskService) DeleteAttachment(ctx context.Context, attachmentID, userID string) error {
 	attachment, err := s.attachmentRepo.FindByID(ctx, attachmentID)
 	if err != nil || attachment == nil {
-		return ErrNotFound
+		return ErrAttachmentNotFound
 	}
 
 	// Only attachment uploader or task editors can delete
@@ -1718,7 +2781,20 @@ func (s *taskService) DeleteAttachment(ctx context.Context, attachmentID, userID
 		OldValue: &attachment.Filename,
 	})
 
-	return s.attachmentRepo.Delete(ctx, attachmentID)
+	if err := s.attachmentRepo.Delete(ctx, attachmentID); err != nil {
+		return err
+	}
+
+	// Task duplicates point at the same stored file; keep it while any remain
+	if attachment.StorageKey != nil && s.store != nil {
+		if remaining, err := s.attachmentRepo.CountByFileURL(ctx, attachment.FileURL); err != nil || remaining > 0 {
+			return nil
+		}
+		if err := s.store.Delete(ctx, *attachment.StorageKey); err != nil {
+			slog.WarnContext(ctx, "failed to delete stored attachment file", "key", *attachment.StorageKey, "error", err)
+		}
+	}
+	return nil
 }
 
 // ============================================
@@ -1727,13 +2803,17 @@ func (s *taskService) DeleteAttachment(ctx context.Context, attachmentID, userID
 
 func (s *taskService) StartTimer(ctx context.Context, taskID, userID string) (*repository.TimeEntry, error) {
 	// Check access
-	if !s.permService.CanAccessTask(ctx, userID, taskID) {
+	if !s.permService.CanEditTask(ctx, userID, taskID) {
 		return nil, ErrUnauthorized
 	}
 
-	// Stop any existing timer
+	// Stop any existing timer; one already running on this task keeps going
+	// so its elapsed time isn't lost
 	active, _ := s.timeEntryRepo.FindActiveTimer(ctx, userID)
 	if active != nil {
+		if active.TaskID == taskID {
+			return active, nil
+		}
 		s.timeEntryRepo.StopTimer(ctx, active.ID)
 	}
 
@@ -1769,8 +2849,15 @@ func (s *taskService) StopTimer(ctx context.Context, userID string) (*repository
 	}
 
 	// Get updated entry
-	entry, _ := s.timeEntryRepo.FindByTaskID(ctx, active.ID)
-	
+	entry, err := s.timeEntryRepo.FindByID(ctx, active.ID)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, ErrNotFound
+	}
+
+
 	// Update task actual hours
 	totalSeconds, _ := s.timeEntryRepo.GetTotalTime(ctx, active.TaskID)
 	task, _ := s.taskRepo.FindByID(ctx, active.TaskID)
@@ -1787,7 +2874,7 @@ func (s *taskService) StopTimer(ctx context.Context, userID string) (*repository
 		Action: "stopped_timer",
 	})
 
-	return entry[len(entry)-1], nil
+	return entry, nil
 }
 
 func (s *taskService) GetActiveTimer(ctx context.Context, userID string) (*repository.TimeEntry, error) {
@@ -1802,7 +2889,7 @@ func (s *taskService) GetActiveTimer(ctx context.Context, userID string) (*repos
 }
 
 func (s *taskService) LogTime(ctx context.Context, taskID, userID string, durationSeconds int, description *string) (*repository.TimeEntry, error) {
-	if !s.permService.CanAccessTask(ctx, userID, taskID) {
+	if !s.permService.CanEditTask(ctx, userID, taskID) {
 		return nil, ErrUnauthorized
 	}
 
@@ -1851,6 +2938,43 @@ func (s *taskService) GetTotalTime(ctx context.Context, taskID string) (int, err
 	return s.timeEntryRepo.GetTotalTime(ctx, taskID)
 }
 
+func (s *taskService) GetTimeReport(ctx context.Context, projectID, userID string, from, to time.Time) (*TimeReport, error) {
+	if !s.permService.CanViewReports(ctx, userID, projectID) {
+		return nil, ErrUnauthorized
+	}
+	if !to.After(from) {
+		return nil, fmt.Errorf("%w: 'to' must be after 'from'", ErrInvalidInput)
+	}
+
+	totals, err := s.timeEntryRepo.SumByUserInRange(ctx, projectID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	breakdown, err := s.timeEntryRepo.SumByUserAndTaskInRange(ctx, projectID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	byUser := make(map[string]*UserTimeReport, len(totals))
+	report := &TimeReport{ProjectID: projectID, From: from, To: to, Users: []*UserTimeReport{}}
+	for uid, seconds := range totals {
+		user := &UserTimeReport{UserID: uid, TotalSeconds: seconds, Tasks: []*repository.TaskTimeTotal{}}
+		byUser[uid] = user
+		report.Users = append(report.Users, user)
+		report.TotalSeconds += seconds
+	}
+	for _, item := range breakdown {
+		if user, ok := byUser[item.UserID]; ok {
+			user.Tasks = append(user.Tasks, item)
+		}
+	}
+
+	sort.Slice(report.Users, func(i, j int) bool {
+		return report.Users[i].TotalSeconds > report.Users[j].TotalSeconds
+	})
+	return report, nil
+}
+
 // ============================================
 // DEPENDENCIES IMPLEMENTATION
 // ============================================
@@ -1859,12 +2983,12 @@ func (s *taskService) AddDependency(ctx context.Context, taskID, dependsOnTaskID
 	// Verify both tasks exist and user has access
 	task, err := s.taskRepo.FindByID(ctx, taskID)
 	if err != nil || task == nil {
-		return ErrNotFound
+		return ErrTaskNotFound
 	}
 
 	dependsOnTask, err := s.taskRepo.FindByID(ctx, dependsOnTaskID)
 	if err != nil || dependsOnTask == nil {
-		return ErrNotFound
+		return ErrTaskNotFound
 	}
 
 	// Verify same project
@@ -1876,6 +3000,25 @@ func (s *taskService) AddDependency(ctx context.Context, taskID, dependsOnTaskID
 		return ErrUnauthorized
 	}
 
+	// Reject edges that would close a loop in the dependency graph
+	chain, err := s.findDependencyPath(ctx, dependsOnTaskID, taskID)
+	if err != nil {
+		return err
+	}
+	if chain != nil {
+		return fmt.Errorf("%w: circular dependency %s", ErrInvalidInput,
+			strings.Join(append([]string{taskID}, chain...), " -> "))
+	}
+
+	// An open blocker moves the task to "blocked", which must be a move the
+	// workflow and WIP limits allow before the dependency is stored
+	block := depType == "blocks" && dependsOnTask.Status != types.StatusDone && task.Status != "blocked"
+	if block {
+		if err := s.checkStatusMove(ctx, task, "blocked"); err != nil {
+			return err
+		}
+	}
+
 	dep := &repository.TaskDependency{
 		TaskID:          taskID,
 		DependsOnTaskID: dependsOnTaskID,
@@ -1887,9 +3030,10 @@ func (s *taskService) AddDependency(ctx context.Context, taskID, dependsOnTaskID
 	}
 
 	// Mark task as blocked if dependency is not complete
-	if depType == "blocks" && dependsOnTask.Status != "done" {
-		task.Status = "blocked"
-		s.taskRepo.Update(ctx, task)
+	if block {
+		if err := s.taskRepo.UpdateStatus(ctx, task.ID, "blocked", nil); err != nil {
+			return taskWriteError(err)
+		}
 	}
 
 	// Log activity
@@ -1904,44 +3048,240 @@ func (s *taskService) AddDependency(ctx context.Context, taskID, dependsOnTaskID
 	return nil
 }
 
-func (s *taskService) RemoveDependency(ctx context.Context, taskID, dependsOnTaskID, userID string) error {
-	task, err := s.taskRepo.FindByID(ctx, taskID)
-	if err != nil || task == nil {
-		return ErrNotFound
+// taskWatcherIDs returns the distinct watchers of a task, from both its
+// watcher_ids column and the task_watchers table
+func (s *taskService) taskWatcherIDs(ctx context.Context, task *repository.Task) ([]string, error) {
+	watcherIDs := append([]string(nil), task.WatcherIDs...)
+	if s.watcherRepo != nil {
+		watching, err := s.watcherRepo.GetWatcherUserIDs(ctx, task.ID)
+		if err != nil {
+			return nil, err
+		}
+		watcherIDs = append(watcherIDs, watching...)
+	}
+	seen := make(map[string]bool, len(watcherIDs))
+	unique := watcherIDs[:0]
+	for _, id := range watcherIDs {
+		if id != "" && !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
 	}
+	return unique, nil
+}
 
-	if !s.permService.CanEditTask(ctx, userID, taskID) {
-		return ErrUnauthorized
+// notifyWatchers sends one notification to every watcher of the task except the actor
+// and anyone already in notified; notified is updated with the recipients.
+func (s *taskService) notifyWatchers(
+	ctx context.Context,
+	task *repository.Task,
+	actorID string,
+	notified map[string]bool,
+	notificationType, title, message, field, newValue string,
+) {
+	if s.notificationSvc == nil {
+		return
 	}
 
-	if err := s.dependencyRepo.Delete(ctx, taskID, dependsOnTaskID); err != nil {
-		return err
+	watcherIDs, err := s.taskWatcherIDs(ctx, task)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to load task watchers", "taskID", task.ID, "error", err)
+		watcherIDs = task.WatcherIDs
 	}
 
-	// Check if task should still be blocked
-	deps, _ := s.dependencyRepo.FindByTaskID(ctx, taskID)
-	stillBlocked := false
-	for _, dep := range deps {
-		if dep.DependencyType == "blocks" {
-			dependsTask, _ := s.taskRepo.FindByID(ctx, dep.DependsOnTaskID)
-			if dependsTask != nil && dependsTask.Status != "done" {
-				stillBlocked = true
-				break
-			}
+	var recipients []string
+	for _, watcherID := range watcherIDs {
+		if watcherID == actorID || notified[watcherID] {
+			continue
 		}
+		notified[watcherID] = true
+		recipients = append(recipients, watcherID)
+	}
+	if len(recipients) == 0 {
+		return
 	}
-	task.Blocked = stillBlocked
-	s.taskRepo.Update(ctx, task)
-
-	// Log activity
-	s.activityRepo.Create(ctx, &repository.TaskActivity{
-		TaskID:   taskID,
-		UserID:   &userID,
-		Action:   "removed_dependency",
-		OldValue: &dependsOnTaskID,
-	})
 
-	return nil
+	s.notificationSvc.SendBatchNotifications(ctx, recipients, actorID, notificationType, title, message,
+		map[string]interface{}{
+			"taskId":    task.ID,
+			"taskKey":   s.getTaskKey(task),
+			"taskTitle": task.Title,
+			"projectId": task.ProjectID,
+			"field":     field,
+			"newValue":  newValue,
+			"actorId":   actorID,
+			"action":    "view_task",
+		},
+	)
+}
+
+// commentSnippet shortens comment content for notification payloads
+func commentSnippet(content string) string {
+	const maxLen = 140
+	runes := []rune(content)
+	if len(runes) <= maxLen {
+		return content
+	}
+	return string(runes[:maxLen]) + "…"
+}
+
+// unblockDependents re-evaluates tasks blocked by a task that was just completed.
+// A dependent task is moved to the first status of its project's workflow only
+// when none of its blockers remain open.
+func (s *taskService) unblockDependents(ctx context.Context, completedTask *repository.Task, userID string) {
+	dependents, err := s.dependencyRepo.FindBlockedBy(ctx, completedTask.ID)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to load dependent tasks", "taskID", completedTask.ID, "error", err)
+		return
+	}
+
+	for _, dep := range dependents {
+		if dep.DependencyType != "blocks" {
+			continue
+		}
+
+		dependent, err := s.taskRepo.FindByID(ctx, dep.TaskID)
+		if err != nil || dependent == nil || dependent.Status != "blocked" {
+			continue
+		}
+
+		// Check every remaining blocker of the dependent task
+		blockers, err := s.dependencyRepo.FindByTaskID(ctx, dependent.ID)
+		if err != nil {
+			slog.WarnContext(ctx, "failed to load blocking tasks", "taskID", dependent.ID, "error", err)
+			continue
+		}
+
+		stillBlocked := false
+		for _, blocker := range blockers {
+			if blocker.DependencyType != "blocks" || blocker.DependsOnTaskID == completedTask.ID {
+				continue
+			}
+			blockerTask, err := s.taskRepo.FindByID(ctx, blocker.DependsOnTaskID)
+			if err == nil && blockerTask != nil && blockerTask.Status != "done" {
+				stillBlocked = true
+				break
+			}
+		}
+		if stillBlocked {
+			continue
+		}
+
+		newStatus := s.defaultStatus(ctx, dependent.ProjectID)
+		if err := s.taskRepo.UpdateStatus(ctx, dependent.ID, newStatus, nil); err != nil {
+			slog.WarnContext(ctx, "failed to unblock task", "taskID", dependent.ID, "error", err)
+			continue
+		}
+
+		oldStatus := dependent.Status
+		s.activityRepo.Create(ctx, &repository.TaskActivity{
+			TaskID:   dependent.ID,
+			UserID:   &userID,
+			Action:   "unblocked",
+			OldValue: &oldStatus,
+			NewValue: &completedTask.ID,
+		})
+
+		s.notificationSvc.SendBatchNotifications(
+			ctx,
+			dependent.AssigneeIDs,
+			userID,
+			notification.TypeTaskUnblocked,
+			"Task Unblocked",
+			fmt.Sprintf("'%s' is no longer blocked now that '%s' is done", dependent.Title, completedTask.Title),
+			map[string]interface{}{
+				"taskId":        dependent.ID,
+				"taskTitle":     dependent.Title,
+				"projectId":     dependent.ProjectID,
+				"blockerTaskId": completedTask.ID,
+				"blockerTitle":  completedTask.Title,
+				"action":        "view_task",
+			},
+		)
+
+		if s.broadcaster != nil {
+			dependent.Status = newStatus
+			s.broadcaster.BroadcastTaskStatusChanged(
+				dependent.ProjectID,
+				s.taskToMap(dependent),
+				oldStatus,
+				newStatus,
+				userID,
+			)
+		}
+	}
+}
+
+// findDependencyPath walks dependencies of fromTaskID transitively and returns
+// the chain of task IDs leading to targetTaskID, or nil if it is unreachable.
+func (s *taskService) findDependencyPath(ctx context.Context, fromTaskID, targetTaskID string) ([]string, error) {
+	visited := make(map[string]bool)
+
+	var walk func(current string, path []string) ([]string, error)
+	walk = func(current string, path []string) ([]string, error) {
+		path = append(path, current)
+		if current == targetTaskID {
+			return path, nil
+		}
+		if visited[current] {
+			return nil, nil
+		}
+		visited[current] = true
+
+		deps, err := s.dependencyRepo.FindByTaskID(ctx, current)
+		if err != nil {
+			return nil, err
+		}
+		for _, dep := range deps {
+			found, err := walk(dep.DependsOnTaskID, path)
+			if err != nil || found != nil {
+				return found, err
+			}
+		}
+		return nil, nil
+	}
+
+	return walk(fromTaskID, nil)
+}
+
+func (s *taskService) RemoveDependency(ctx context.Context, taskID, dependsOnTaskID, userID string) error {
+	task, err := s.taskRepo.FindByID(ctx, taskID)
+	if err != nil || task == nil {
+		return ErrTaskNotFound
+	}
+
+	if !s.permService.CanEditTask(ctx, userID, taskID) {
+		return ErrUnauthorized
+	}
+
+	if err := s.dependencyRepo.Delete(ctx, taskID, dependsOnTaskID); err != nil {
+		return err
+	}
+
+	// Check if task should still be blocked
+	deps, _ := s.dependencyRepo.FindByTaskID(ctx, taskID)
+	stillBlocked := false
+	for _, dep := range deps {
+		if dep.DependencyType == "blocks" {
+			dependsTask, _ := s.taskRepo.FindByID(ctx, dep.DependsOnTaskID)
+			if dependsTask != nil && dependsTask.Status != "done" {
+				stillBlocked = true
+				break
+			}
+		}
+	}
+	task.Blocked = stillBlocked
+	s.taskRepo.Update(ctx, task)
+
+	// Log activity
+	s.activityRepo.Create(ctx, &repository.TaskActivity{
+		TaskID:   taskID,
+		UserID:   &userID,
+		Action:   "removed_dependency",
+		OldValue: &dependsOnTaskID,
+	})
+
+	return nil
 }
 
 func (s *taskService) ListDependencies(ctx context.Context, taskID, userID string) ([]*repository.TaskDependency, error) {
@@ -1963,7 +3303,7 @@ func (s *taskService) ListBlockedBy(ctx context.Context, taskID, userID string)
 // ============================================
 
 func (s *taskService) CreateChecklist(ctx context.Context, taskID, userID, title string) (*repository.TaskChecklist, error) {
-	if !s.permService.CanAccessTask(ctx, userID, taskID) {
+	if !s.permService.CanEditTask(ctx, userID, taskID) {
 		return nil, ErrUnauthorized
 	}
 
@@ -1993,18 +3333,16 @@ func (s *taskService) AddChecklistItem(ctx context.Context, checklistID, userID,
 		return nil, ErrNotFound
 	}
 
-	if !s.permService.CanAccessTask(ctx, userID, checklist.TaskID) {
+	if !s.permService.CanEditTask(ctx, userID, checklist.TaskID) {
 		return nil, ErrUnauthorized
 	}
 
+	task, _ := s.taskRepo.FindByID(ctx, checklist.TaskID)
+
 	// Verify assignee has access if provided
-	if assigneeID != nil {
-		task, _ := s.taskRepo.FindByID(ctx, checklist.TaskID)
-		if task != nil {
-			hasAccess, _, err := s.memberService.HasEffectiveAccess(ctx, EntityTypeProject, task.ProjectID, *assigneeID)
-			if err != nil || !hasAccess {
-				return nil, ErrUnauthorized
-			}
+	if assigneeID != nil && task != nil {
+		if !s.canBeAssignedChecklistItem(ctx, task, *assigneeID) {
+			return nil, ErrUnauthorized
 		}
 	}
 
@@ -2018,9 +3356,124 @@ func (s *taskService) AddChecklistItem(ctx context.Context, checklistID, userID,
 		return nil, err
 	}
 
+	if assigneeID != nil && task != nil {
+		s.notifyChecklistItemAssigned(ctx, task, checklist, item, userID)
+	}
+
+	return item, nil
+}
+
+// UpdateChecklistItem changes an item's content and/or assignee; an empty assigneeID unassigns it
+func (s *taskService) UpdateChecklistItem(ctx context.Context, itemID, userID string, content, assigneeID *string) (*repository.ChecklistItem, error) {
+	item, err := s.checklistRepo.FindItemByID(ctx, itemID)
+	if err != nil || item == nil {
+		return nil, ErrNotFound
+	}
+
+	checklist, err := s.checklistRepo.FindChecklistByID(ctx, item.ChecklistID)
+	if err != nil || checklist == nil {
+		return nil, ErrNotFound
+	}
+
+	if !s.permService.CanEditTask(ctx, userID, checklist.TaskID) {
+		return nil, ErrUnauthorized
+	}
+
+	task, err := s.taskRepo.FindByID(ctx, checklist.TaskID)
+	if err != nil || task == nil {
+		return nil, ErrTaskNotFound
+	}
+
+	if content != nil {
+		trimmed := strings.TrimSpace(*content)
+		if trimmed == "" {
+			return nil, fmt.Errorf("%w: content cannot be empty", ErrInvalidInput)
+		}
+		item.Content = trimmed
+	}
+
+	newlyAssigned := false
+	if assigneeID != nil {
+		if *assigneeID == "" {
+			item.AssigneeID = nil
+		} else if item.AssigneeID == nil || *item.AssigneeID != *assigneeID {
+			if !s.canBeAssignedChecklistItem(ctx, task, *assigneeID) {
+				return nil, ErrUnauthorized
+			}
+			assignee := *assigneeID
+			item.AssigneeID = &assignee
+			newlyAssigned = true
+		}
+	}
+
+	if err := s.checklistRepo.UpdateItem(ctx, item); err != nil {
+		return nil, err
+	}
+
+	if newlyAssigned {
+		s.notifyChecklistItemAssigned(ctx, task, checklist, item, userID)
+	}
+
 	return item, nil
 }
 
+// ListMyChecklistItems returns the open checklist items assigned to the user across accessible projects
+func (s *taskService) ListMyChecklistItems(ctx context.Context, userID string) ([]*repository.AssignedChecklistItem, error) {
+	projects, err := s.memberService.GetAccessibleProjects(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	projectIDs := make([]string, 0, len(projects))
+	for _, p := range projects {
+		projectIDs = append(projectIDs, p.ID)
+	}
+
+	items, err := s.checklistRepo.FindOpenItemsByAssignee(ctx, userID, projectIDs)
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// canBeAssignedChecklistItem checks that the assignee can access the task's project
+func (s *taskService) canBeAssignedChecklistItem(ctx context.Context, task *repository.Task, assigneeID string) bool {
+	hasAccess, _, err := s.memberService.HasEffectiveAccess(ctx, EntityTypeProject, task.ProjectID, assigneeID)
+	return err == nil && hasAccess
+}
+
+// notifyChecklistItemAssigned tells the assignee of an item about it, unless they assigned themselves
+func (s *taskService) notifyChecklistItemAssigned(ctx context.Context, task *repository.Task, checklist *repository.TaskChecklist, item *repository.ChecklistItem, actorID string) {
+	if s.notificationSvc == nil || item.AssigneeID == nil || *item.AssigneeID == actorID {
+		return
+	}
+
+	actorName := "Someone"
+	if actor, _ := s.userRepo.FindByID(ctx, actorID); actor != nil {
+		actorName = actor.Name
+	}
+
+	taskKey := s.getTaskKey(task)
+	s.notificationSvc.SendBatchNotifications(
+		ctx,
+		[]string{*item.AssigneeID},
+		actorID,
+		notification.TypeChecklistItemAssigned,
+		"Checklist Item Assigned",
+		fmt.Sprintf("%s assigned you \"%s\" in %s: %s", actorName, item.Content, checklist.Title, task.Title),
+		map[string]interface{}{
+			"taskId":         task.ID,
+			"taskKey":        taskKey,
+			"taskTitle":      task.Title,
+			"projectId":      task.ProjectID,
+			"checklistId":    checklist.ID,
+			"checklistTitle": checklist.Title,
+			"itemId":         item.ID,
+			"action":         "view_task",
+		},
+	)
+}
+
 
 // ============================================
 // CHECKLIST ITEM TOGGLE - With Notifications
@@ -2037,7 +3490,7 @@ func (s *taskService) ToggleChecklistItem(ctx context.Context, itemID, userID st
 		return ErrNotFound
 	}
 
-	if !s.permService.CanAccessTask(ctx, userID, checklist.TaskID) {
+	if !s.permService.CanEditTask(ctx, userID, checklist.TaskID) {
 		return ErrUnauthorized
 	}
 
@@ -2109,268 +3562,2186 @@ func (s *taskService) ListChecklists(ctx context.Context, taskID, userID string)
 	return s.checklistRepo.FindByTaskID(ctx, taskID)
 }
 
-// ============================================
-// ACTIVITY IMPLEMENTATION
-// ============================================
+// ReorderChecklist moves a checklist between two others of the same task. beforeID is
+// the checklist that ends up directly above it and afterID the one directly below.
+func (s *taskService) ReorderChecklist(ctx context.Context, checklistID, userID string, beforeID, afterID *string) (*repository.TaskChecklist, error) {
+	checklist, err := s.checklistRepo.FindChecklistByID(ctx, checklistID)
+	if err != nil || checklist == nil {
+		return nil, ErrNotFound
+	}
 
-func (s *taskService) GetActivity(ctx context.Context, taskID, userID string, limit int) ([]*repository.TaskActivity, error) {
-	if !s.permService.CanAccessTask(ctx, userID, taskID) {
+	if !s.permService.CanEditTask(ctx, userID, checklist.TaskID) {
 		return nil, ErrUnauthorized
 	}
 
-	if limit <= 0 {
-		limit = 50 // Default limit
+	slot := func() (int, error) {
+		siblings, err := s.checklistRepo.FindByTaskID(ctx, checklist.TaskID)
+		if err != nil {
+			return 0, err
+		}
+		others := make([]positionedItem, 0, len(siblings))
+		for _, c := range siblings {
+			if c.ID != checklist.ID {
+				others = append(others, positionedItem{id: c.ID, position: c.Position})
+			}
+		}
+		return slotPosition(others, beforeID, afterID, repository.ChecklistPositionGap)
 	}
 
-	return s.activityRepo.FindByTaskID(ctx, taskID, limit)
-}
+	position, err := slot()
+	if err == errPositionGapExhausted {
+		if err := s.checklistRepo.RenumberChecklists(ctx, checklist.TaskID, repository.ChecklistPositionGap); err != nil {
+			return nil, err
+		}
+		position, err = slot()
+	}
+	if err != nil {
+		return nil, err
+	}
 
-// ============================================
-// ADVANCED FILTERING
-// ============================================
+	if err := s.checklistRepo.UpdateChecklistPosition(ctx, checklistID, position); err != nil {
+		return nil, err
+	}
+	return s.checklistRepo.FindChecklistByID(ctx, checklistID)
+}
 
-func (s *taskService) FilterTasks(ctx context.Context, filters *repository.TaskFilters, userID string) ([]*repository.Task, int, error) {
-	// Check project access
-	hasAccess, _, err := s.memberService.HasEffectiveAccess(ctx, EntityTypeProject, filters.ProjectID, userID)
-	if err != nil || !hasAccess {
-		return nil, 0, ErrUnauthorized
+// ReorderChecklistItem moves an item between two others of the same checklist. beforeID
+// is the item that ends up directly above it and afterID the one directly below.
+func (s *taskService) ReorderChecklistItem(ctx context.Context, itemID, userID string, beforeID, afterID *string) (*repository.ChecklistItem, error) {
+	item, err := s.checklistRepo.FindItemByID(ctx, itemID)
+	if err != nil || item == nil {
+		return nil, ErrNotFound
 	}
 
-	return s.taskRepo.FindWithFilters(ctx, filters)
-}
+	checklist, err := s.checklistRepo.FindChecklistByID(ctx, item.ChecklistID)
+	if err != nil || checklist == nil {
+		return nil, ErrNotFound
+	}
 
-func (s *taskService) FindOverdue(ctx context.Context, projectID, userID string) ([]*repository.Task, error) {
-	// Check project access
-	hasAccess, _, err := s.memberService.HasEffectiveAccess(ctx, EntityTypeProject, projectID, userID)
-	if err != nil || !hasAccess {
+	if !s.permService.CanEditTask(ctx, userID, checklist.TaskID) {
 		return nil, ErrUnauthorized
 	}
 
-	return s.taskRepo.FindOverdue(ctx, projectID)
-}
+	slot := func() (int, error) {
+		siblings, err := s.checklistRepo.FindItemsByChecklistID(ctx, item.ChecklistID)
+		if err != nil {
+			return 0, err
+		}
+		others := make([]positionedItem, 0, len(siblings))
+		for _, i := range siblings {
+			if i.ID != item.ID {
+				others = append(others, positionedItem{id: i.ID, position: i.Position})
+			}
+		}
+		return slotPosition(others, beforeID, afterID, repository.ChecklistPositionGap)
+	}
 
-func (s *taskService) FindBlocked(ctx context.Context, projectID, userID string) ([]*repository.Task, error) {
-	// Check project access
-	hasAccess, _, err := s.memberService.HasEffectiveAccess(ctx, EntityTypeProject, projectID, userID)
-	if err != nil || !hasAccess {
-		return nil, ErrUnauthorized
+	position, err := slot()
+	if err == errPositionGapExhausted {
+		if err := s.checklistRepo.RenumberItems(ctx, item.ChecklistID, repository.ChecklistPositionGap); err != nil {
+			return nil, err
+		}
+		position, err = slot()
+	}
+	if err != nil {
+		return nil, err
 	}
 
-	return s.taskRepo.FindBlocked(ctx, projectID)
+	if err := s.checklistRepo.UpdateItemPosition(ctx, itemID, position); err != nil {
+		return nil, err
+	}
+	return s.checklistRepo.FindItemByID(ctx, itemID)
 }
 
 // ============================================
-// SCRUM SPECIFIC IMPLEMENTATION
+// ACTIVITY IMPLEMENTATION
 // ============================================
 
-func (s *taskService) GetBacklog(ctx context.Context, projectID, userID string) ([]*repository.Task, error) {
-	// Check project access
-	hasAccess, _, err := s.memberService.HasEffectiveAccess(ctx, EntityTypeProject, projectID, userID)
-	if err != nil || !hasAccess {
+func (s *taskService) GetActivity(ctx context.Context, taskID, userID string, limit int) ([]*repository.TaskActivity, error) {
+	if !s.permService.CanAccessTask(ctx, userID, taskID) {
 		return nil, ErrUnauthorized
 	}
 
-	return s.taskRepo.FindBacklog(ctx, projectID)
+	if limit <= 0 {
+		limit = 50 // Default limit
+	}
+
+	return s.activityRepo.FindByTaskID(ctx, taskID, limit)
 }
 
-func (s *taskService) GetSprintBoard(ctx context.Context, sprintID, userID string) (map[string][]*repository.Task, error) {
-	// Get all tasks in sprint
-	tasks, err := s.taskRepo.FindBySprintID(ctx, sprintID)
-	if err != nil {
-		return nil, err
-	}
+// TaskHistoryEntry is one or more activity records of a task by the same
+// actor, made close together, described in words
+type TaskHistoryEntry struct {
+	ID        string    // newest activity in the entry
+	ActorID   *string   // nil for system actions
+	ActorName string
+	Changes   []string  // e.g. "changed status from todo to in_progress", oldest first
+	CreatedAt time.Time // time of the newest activity
+}
 
-	// Group by status
-	board := make(map[string][]*repository.Task)
-	statuses := []string{"todo", "in_progress", "in_review", "done"}
-	
-	for _, status := range statuses {
-		board[status] = []*repository.Task{}
-	}
+// Same-actor activity records less than this apart are shown as one entry
+const historyMergeWindow = 2 * time.Minute
 
-	for _, task := range tasks {
-		// Check user has access to task's project
-		hasAccess, _, _ := s.memberService.HasEffectiveAccess(ctx, EntityTypeProject, task.ProjectID, userID)
-		if hasAccess {
-			board[task.Status] = append(board[task.Status], task)
+// historyScanFactor is how many activity records are read per requested
+// entry, since several records can merge into one
+const historyScanFactor = 4
+
+func (s *taskService) GetHistory(ctx context.Context, taskID, userID string, limit int) ([]*TaskHistoryEntry, error) {
+	task, err := s.taskRepo.FindByID(ctx, taskID)
+	if err != nil || task == nil {
+		return nil, ErrTaskNotFound
+	}
+	if !s.permService.CanAccessTask(ctx, userID, taskID) {
+		return nil, ErrUnauthorized
+	}
+
+	if limit <= 0 {
+		limit = 50
+	}
+	if limit > 200 {
+		limit = 200
+	}
+
+	activities, err := s.activityRepo.FindByTaskID(ctx, taskID, limit*historyScanFactor)
+	if err != nil {
+		return nil, err
+	}
+
+	names, err := s.historyNames(ctx, task, activities)
+	if err != nil {
+		return nil, err
+	}
+
+	// Activities come newest first; an entry grows backwards in time
+	var entries []*TaskHistoryEntry
+	var oldest time.Time
+	for _, a := range activities {
+		changes := describeActivity(a, names)
+		if len(changes) == 0 {
+			continue
+		}
+		if n := len(entries); n > 0 && sameActor(entries[n-1].ActorID, a.UserID) && oldest.Sub(a.CreatedAt) < historyMergeWindow {
+			entry := entries[n-1]
+			entry.Changes = append(changes, entry.Changes...)
+			oldest = a.CreatedAt
+			continue
+		}
+		if len(entries) == limit {
+			break
+		}
+		entries = append(entries, &TaskHistoryEntry{
+			ID:        a.ID,
+			ActorID:   a.UserID,
+			ActorName: names.actor(a.UserID),
+			Changes:   changes,
+			CreatedAt: a.CreatedAt,
+		})
+		oldest = a.CreatedAt
+	}
+	return entries, nil
+}
+
+// historyNameSet resolves the IDs that appear in activity records
+type historyNameSet struct {
+	users   map[string]string
+	labels  map[string]string
+	sprints map[string]string
+}
+
+func (n historyNameSet) actor(userID *string) string {
+	if userID == nil {
+		return "System"
+	}
+	return n.user(*userID)
+}
+
+func (n historyNameSet) user(id string) string {
+	if name, ok := n.users[id]; ok {
+		return name
+	}
+	return repository.DeletedUserName
+}
+
+func (n historyNameSet) label(id string) string {
+	if name, ok := n.labels[id]; ok {
+		return name
+	}
+	return "(deleted label)"
+}
+
+func (n historyNameSet) sprint(id string) string {
+	if name, ok := n.sprints[id]; ok {
+		return name
+	}
+	return "(unknown sprint)"
+}
+
+// historyNames loads, in one query each, the users, labels and sprints the
+// activities refer to
+func (s *taskService) historyNames(ctx context.Context, task *repository.Task, activities []*repository.TaskActivity) (historyNameSet, error) {
+	names := historyNameSet{
+		users:   make(map[string]string),
+		labels:  make(map[string]string),
+		sprints: make(map[string]string),
+	}
+
+	userIDs := make(map[string]bool)
+	sprintIDs := make(map[string]bool)
+	labelIDs := make(map[string]bool)
+	for _, a := range activities {
+		if a.UserID != nil {
+			userIDs[*a.UserID] = true
+		}
+		if a.Action != "updated" || a.FieldName == nil {
+			continue
+		}
+		switch *a.FieldName {
+		case "assignees":
+			for _, id := range append(splitIDList(a.OldValue), splitIDList(a.NewValue)...) {
+				userIDs[id] = true
+			}
+		case "labels":
+			for _, id := range append(splitIDList(a.OldValue), splitIDList(a.NewValue)...) {
+				labelIDs[id] = true
+			}
+		case "sprint_id":
+			for _, v := range []*string{a.OldValue, a.NewValue} {
+				if v != nil {
+					sprintIDs[*v] = true
+				}
+			}
+		}
+	}
+
+	if len(userIDs) > 0 {
+		ids := make([]string, 0, len(userIDs))
+		for id := range userIDs {
+			ids = append(ids, id)
+		}
+		users, err := s.userRepo.FindByIDs(ctx, ids)
+		if err != nil {
+			return names, err
+		}
+		for _, u := range users {
+			if u.DeletedAt != nil {
+				names.users[u.ID] = repository.DeletedUserName
+			} else {
+				names.users[u.ID] = u.Name
+			}
+		}
+	}
+
+	if len(labelIDs) > 0 && s.labelRepo != nil {
+		labels, err := s.labelRepo.FindByProjectID(ctx, task.ProjectID)
+		if err != nil {
+			return names, err
+		}
+		for _, l := range labels {
+			names.labels[l.ID] = l.Name
+		}
+		// Labels of a project the task was moved out of
+		for id := range labelIDs {
+			if _, ok := names.labels[id]; ok {
+				continue
+			}
+			if l, err := s.labelRepo.FindByID(ctx, id); err == nil && l != nil {
+				names.labels[id] = l.Name
+			}
+		}
+	}
+
+	if len(sprintIDs) > 0 && s.sprintRepo != nil {
+		sprints, err := s.sprintRepo.FindByProjectID(ctx, task.ProjectID)
+		if err != nil {
+			return names, err
+		}
+		for _, sp := range sprints {
+			names.sprints[sp.ID] = sp.Name
+		}
+		for id := range sprintIDs {
+			if _, ok := names.sprints[id]; ok {
+				continue
+			}
+			if sp, err := s.sprintRepo.FindByID(ctx, id); err == nil && sp != nil {
+				names.sprints[id] = sp.Name
+			}
+		}
+	}
+	return names, nil
+}
+
+// describeActivity renders an activity record as one or more changes
+func describeActivity(a *repository.TaskActivity, names historyNameSet) []string {
+	value := func(v *string) string {
+		if v == nil {
+			return ""
+		}
+		return *v
+	}
+
+	switch a.Action {
+	case "created":
+		if a.FieldName != nil && *a.FieldName == "cloned_from" {
+			return []string{"cloned the task"}
+		}
+		return []string{"created the task"}
+	case "status_changed":
+		return []string{fmt.Sprintf("changed status from %s to %s", value(a.OldValue), value(a.NewValue))}
+	case "updated":
+		if a.FieldName == nil {
+			return []string{"updated the task"}
+		}
+		return describeFieldChange(*a.FieldName, a.OldValue, a.NewValue, names)
+	case "label_added":
+		return []string{"added label " + value(a.NewValue)}
+	case "label_removed":
+		return []string{"removed label " + value(a.OldValue)}
+	case "commented":
+		return []string{"commented"}
+	case "comment_updated":
+		return []string{"edited a comment"}
+	case "comment_deleted":
+		return []string{"deleted a comment"}
+	case "added_attachment":
+		return []string{"attached " + value(a.NewValue)}
+	case "deleted_attachment":
+		return []string{"removed attachment " + value(a.OldValue)}
+	case "started_timer":
+		return []string{"started a timer"}
+	case "stopped_timer":
+		return []string{"stopped a timer"}
+	case "logged_time":
+		return []string{"logged time"}
+	case "added_dependency":
+		return []string{"added a dependency"}
+	case "removed_dependency":
+		return []string{"removed a dependency"}
+	case "unblocked":
+		return []string{"unblocked the task"}
+	case "moved":
+		return []string{fmt.Sprintf("moved the task from %s to %s", value(a.OldValue), value(a.NewValue))}
+	case "created_checklist":
+		return []string{fmt.Sprintf("added checklist %q", value(a.NewValue))}
+	}
+	return []string{strings.ReplaceAll(a.Action, "_", " ")}
+}
+
+func describeFieldChange(field string, oldValue, newValue *string, names historyNameSet) []string {
+	switch field {
+	case "title":
+		if oldValue == nil || newValue == nil {
+			break
+		}
+		return []string{fmt.Sprintf("renamed the task from %q to %q", *oldValue, *newValue)}
+	case "description":
+		return []string{"updated the description"}
+	case "assignees":
+		added, removed := diffIDLists(oldValue, newValue)
+		var changes []string
+		for _, id := range added {
+			changes = append(changes, "assigned "+names.user(id))
+		}
+		for _, id := range removed {
+			changes = append(changes, "unassigned "+names.user(id))
+		}
+		return changes
+	case "labels":
+		added, removed := diffIDLists(oldValue, newValue)
+		var changes []string
+		for _, id := range added {
+			changes = append(changes, "added label "+names.label(id))
+		}
+		for _, id := range removed {
+			changes = append(changes, "removed label "+names.label(id))
+		}
+		return changes
+	case "sprint_id":
+		if newValue == nil {
+			if oldValue == nil {
+				return nil
+			}
+			return []string{"removed the task from sprint " + names.sprint(*oldValue)}
+		}
+		return []string{"moved the task to sprint " + names.sprint(*newValue)}
+	}
+
+	label := strings.ReplaceAll(field, "_", " ")
+	switch {
+	case oldValue == nil && newValue == nil:
+		return nil
+	case oldValue == nil:
+		return []string{fmt.Sprintf("set %s to %s", label, *newValue)}
+	case newValue == nil:
+		return []string{"cleared " + label}
+	}
+	return []string{fmt.Sprintf("changed %s from %s to %s", label, *oldValue, *newValue)}
+}
+
+// splitIDList reads an ID list stored by activityIDList
+func splitIDList(v *string) []string {
+	if v == nil || *v == "" {
+		return nil
+	}
+	return strings.Split(*v, ",")
+}
+
+func diffIDLists(oldValue, newValue *string) (added, removed []string) {
+	oldIDs := splitIDList(oldValue)
+	newIDs := splitIDList(newValue)
+	for _, id := range newIDs {
+		if !contains(oldIDs, id) {
+			added = append(added, id)
+		}
+	}
+	for _, id := range oldIDs {
+		if !contains(newIDs, id) {
+			removed = append(removed, id)
+		}
+	}
+	return added, removed
+}
+
+func sameActor(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+// ============================================
+// ADVANCED FILTERING
+// ============================================
+
+func (s *taskService) FilterTasks(ctx context.Context, filters *repository.TaskFilters, userID string) ([]*repository.Task, int, error) {
+	// Check project access
+	hasAccess, _, err := s.memberService.HasEffectiveAccess(ctx, EntityTypeProject, filters.ProjectID, userID)
+	if err != nil || !hasAccess {
+		return nil, 0, ErrUnauthorized
+	}
+
+	return s.taskRepo.FindWithFilters(ctx, filters)
+}
+
+// TaskExportRow is one exported task, with assignees and labels resolved to names
+type TaskExportRow struct {
+	Key         string     `json:"key"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	Status      string     `json:"status"`
+	Priority    string     `json:"priority"`
+	Assignees   []string   `json:"assignees"`
+	StoryPoints *int       `json:"storyPoints"`
+	DueDate     *time.Time `json:"dueDate"`
+	Labels      []string   `json:"labels"`
+}
+
+// exportBatchSize is how many tasks an export loads from the database at a time
+const exportBatchSize = 500
+
+// ExportTasks checks the CanExport permission before emitting anything, then
+// streams the tasks in batches so large projects are never held in memory
+func (s *taskService) ExportTasks(ctx context.Context, filters *repository.TaskFilters, userID string, emit func(*TaskExportRow) error) error {
+	if !s.permService.CanExport(ctx, userID, filters.ProjectID) {
+		return ErrUnauthorized
+	}
+
+	userNames := make(map[string]string)
+	return s.taskRepo.StreamWithFilters(ctx, filters, exportBatchSize, func(tasks []*repository.Task) error {
+		for _, task := range tasks {
+			row := &TaskExportRow{
+				Key:         s.getTaskKey(task),
+				Title:       task.Title,
+				Status:      task.Status,
+				Priority:    task.Priority,
+				Assignees:   make([]string, 0, len(task.AssigneeIDs)),
+				StoryPoints: task.StoryPoints,
+				DueDate:     task.DueDate,
+				Labels:      make([]string, 0, len(task.Labels)),
+			}
+			if task.Description != nil {
+				row.Description = *task.Description
+			}
+			for _, assigneeID := range task.AssigneeIDs {
+				name, ok := userNames[assigneeID]
+				if !ok {
+					name = assigneeID
+					if user, err := s.userRepo.FindByID(ctx, assigneeID); err == nil && user != nil {
+						name = user.Name
+					}
+					userNames[assigneeID] = name
+				}
+				row.Assignees = append(row.Assignees, name)
+			}
+			for _, label := range task.Labels {
+				row.Labels = append(row.Labels, label.Name)
+			}
+			if err := emit(row); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ============================================
+// SAVED VIEWS
+// ============================================
+
+// DefaultViewPageSize applies when a saved view is run without a limit
+const DefaultViewPageSize = 50
+
+// CreateView saves a filter set for the user; sharing it with the project
+// requires the CanCreateViews permission
+func (s *taskService) CreateView(ctx context.Context, projectID, userID, name string, shared bool, filters *repository.TaskFilters) (*repository.SavedView, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, fmt.Errorf("%w: view name is required", ErrInvalidInput)
+	}
+	if !s.permService.CanAccessProject(ctx, userID, projectID) {
+		return nil, ErrUnauthorized
+	}
+	if shared && !s.permService.CanCreateViews(ctx, userID, projectID) {
+		return nil, ErrUnauthorized
+	}
+
+	view := &repository.SavedView{
+		ProjectID: projectID,
+		UserID:    userID,
+		Name:      name,
+		Shared:    shared,
+	}
+	if filters != nil {
+		view.Filters = *filters
+	}
+	view.Filters.ProjectID = projectID
+
+	if err := s.savedViewRepo.Create(ctx, view); err != nil {
+		return nil, err
+	}
+	return view, nil
+}
+
+// ListViews returns the user's own views of the project followed by shared ones
+func (s *taskService) ListViews(ctx context.Context, projectID, userID string) ([]*repository.SavedView, error) {
+	if !s.permService.CanAccessProject(ctx, userID, projectID) {
+		return nil, ErrUnauthorized
+	}
+
+	views, err := s.savedViewRepo.FindVisible(ctx, projectID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if views == nil {
+		views = []*repository.SavedView{}
+	}
+	return views, nil
+}
+
+// GetView returns a view the user owns, or a shared view of a project they can access
+func (s *taskService) GetView(ctx context.Context, viewID, userID string) (*repository.SavedView, error) {
+	view, err := s.savedViewRepo.FindByID(ctx, viewID)
+	if err != nil {
+		return nil, err
+	}
+	if view == nil {
+		return nil, ErrNotFound
+	}
+	// Someone else's private view doesn't exist as far as the caller is concerned
+	if view.UserID != userID && !view.Shared {
+		return nil, ErrNotFound
+	}
+	if !s.permService.CanAccessProject(ctx, userID, view.ProjectID) {
+		return nil, ErrUnauthorized
+	}
+
+	view.Filters.ProjectID = view.ProjectID
+	return view, nil
+}
+
+// UpdateView changes a view; only its owner may edit it
+func (s *taskService) UpdateView(ctx context.Context, viewID, userID string, name *string, shared *bool, filters *repository.TaskFilters) (*repository.SavedView, error) {
+	view, err := s.GetView(ctx, viewID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if view.UserID != userID {
+		return nil, ErrUnauthorized
+	}
+
+	if name != nil {
+		trimmed := strings.TrimSpace(*name)
+		if trimmed == "" {
+			return nil, fmt.Errorf("%w: view name is required", ErrInvalidInput)
+		}
+		view.Name = trimmed
+	}
+	if shared != nil {
+		if *shared && !view.Shared && !s.permService.CanCreateViews(ctx, userID, view.ProjectID) {
+			return nil, ErrUnauthorized
+		}
+		view.Shared = *shared
+	}
+	if filters != nil {
+		view.Filters = *filters
+		view.Filters.ProjectID = view.ProjectID
+	}
+
+	if err := s.savedViewRepo.Update(ctx, view); err != nil {
+		return nil, err
+	}
+	return view, nil
+}
+
+// DeleteView removes a view; its owner or a project lead may delete it
+func (s *taskService) DeleteView(ctx context.Context, viewID, userID string) error {
+	view, err := s.GetView(ctx, viewID, userID)
+	if err != nil {
+		return err
+	}
+	if view.UserID != userID && !hasMinimumRole(s.permService.GetProjectRole(ctx, userID, view.ProjectID), PermissionLead) {
+		return ErrUnauthorized
+	}
+	return s.savedViewRepo.Delete(ctx, viewID)
+}
+
+// ApplySavedView runs the view's filters against its project
+func (s *taskService) ApplySavedView(ctx context.Context, viewID, userID string, limit, offset int) ([]*repository.Task, int, error) {
+	view, err := s.GetView(ctx, viewID, userID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	filters := view.Filters
+	filters.Limit = limit
+	if filters.Limit <= 0 {
+		filters.Limit = DefaultViewPageSize
+	}
+	filters.Offset = offset
+	if filters.Offset < 0 {
+		filters.Offset = 0
+	}
+
+	return s.FilterTasks(ctx, &filters, userID)
+}
+
+func (s *taskService) FindOverdue(ctx context.Context, projectID, userID string) ([]*repository.Task, error) {
+	// Check project access
+	hasAccess, _, err := s.memberService.HasEffectiveAccess(ctx, EntityTypeProject, projectID, userID)
+	if err != nil || !hasAccess {
+		return nil, ErrUnauthorized
+	}
+
+	return s.taskRepo.FindOverdue(ctx, projectID)
+}
+
+func (s *taskService) FindBlocked(ctx context.Context, projectID, userID string) ([]*repository.Task, error) {
+	// Check project access
+	hasAccess, _, err := s.memberService.HasEffectiveAccess(ctx, EntityTypeProject, projectID, userID)
+	if err != nil || !hasAccess {
+		return nil, ErrUnauthorized
+	}
+
+	return s.taskRepo.FindBlocked(ctx, projectID)
+}
+
+// Search result caps
+const (
+	defaultTaskSearchLimit = 20
+	maxTaskSearchLimit     = 50
+)
+
+// SearchTasks searches task titles and keys across every project the user can access
+func (s *taskService) SearchTasks(ctx context.Context, userID, query string, limit int) ([]*repository.Task, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, ErrInvalidInput
+	}
+
+	if limit <= 0 {
+		limit = defaultTaskSearchLimit
+	}
+	if limit > maxTaskSearchLimit {
+		limit = maxTaskSearchLimit
+	}
+
+	projects, err := s.memberService.GetAccessibleProjects(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	projectIDs := make([]string, 0, len(projects))
+	for _, p := range projects {
+		projectIDs = append(projectIDs, p.ID)
+	}
+
+	return s.taskRepo.Search(ctx, projectIDs, query, limit)
+}
+
+// SearchComments full-text searches comment content across the user's accessible projects
+func (s *taskService) SearchComments(ctx context.Context, userID, query string, limit int) ([]*repository.CommentSearchResult, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, ErrInvalidInput
+	}
+
+	if limit <= 0 {
+		limit = defaultTaskSearchLimit
+	}
+	if limit > maxTaskSearchLimit {
+		limit = maxTaskSearchLimit
+	}
+
+	projects, err := s.memberService.GetAccessibleProjects(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	projectIDs := make([]string, 0, len(projects))
+	for _, p := range projects {
+		projectIDs = append(projectIDs, p.ID)
+	}
+
+	results, err := s.commentRepo.SearchByContent(ctx, projectIDs, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// ============================================
+// SCRUM SPECIFIC IMPLEMENTATION
+// ============================================
+
+func (s *taskService) GetBacklog(ctx context.Context, projectID, userID string) ([]*repository.Task, error) {
+	// Check project access
+	hasAccess, _, err := s.memberService.HasEffectiveAccess(ctx, EntityTypeProject, projectID, userID)
+	if err != nil || !hasAccess {
+		return nil, ErrUnauthorized
+	}
+
+	return s.taskRepo.FindBacklog(ctx, projectID)
+}
+
+// Backlog is the page of backlog tasks asked for, with the number of tasks
+// and the totals over every backlog task matching the filters
+type Backlog struct {
+	Tasks   []*repository.Task
+	Total   int
+	Summary *repository.TaskSummary
+}
+
+// FilterBacklog lists the project's backlog, the top-level tasks that are in
+// no sprint, narrowed by the filters. A sprint filter is ignored.
+func (s *taskService) FilterBacklog(ctx context.Context, filters *repository.TaskFilters, userID string) (*Backlog, error) {
+	hasAccess, _, err := s.memberService.HasEffectiveAccess(ctx, EntityTypeProject, filters.ProjectID, userID)
+	if err != nil || !hasAccess {
+		return nil, ErrUnauthorized
+	}
+
+	filters.Backlog = true
+	filters.SprintID = nil
+
+	tasks, total, err := s.taskRepo.FindWithFilters(ctx, filters)
+	if err != nil {
+		return nil, err
+	}
+	summary, err := s.taskRepo.SummarizeWithFilters(ctx, filters)
+	if err != nil {
+		return nil, err
+	}
+	return &Backlog{Tasks: tasks, Total: total, Summary: summary}, nil
+}
+
+// GetSprintBoard serves the board from the read cache when possible. The
+// cached board holds every task of the sprint; it is trimmed to the tasks the
+// user may see on each request.
+func (s *taskService) GetSprintBoard(ctx context.Context, sprintID, userID string) (*SprintBoard, error) {
+	sprint, err := s.sprintRepo.FindByID(ctx, sprintID)
+	if err != nil {
+		return nil, err
+	}
+
+	board := &SprintBoard{}
+	if sprint == nil || !s.readCache.get(ctx, sprintBoardCacheKey(sprint.ProjectID, sprintID), board) {
+		board, err = s.buildSprintBoard(ctx, sprintID, sprint)
+		if err != nil {
+			return nil, err
+		}
+		if sprint != nil {
+			s.readCache.put(ctx, sprintBoardCacheKey(sprint.ProjectID, sprintID), board)
+		}
+	}
+
+	var tasks []*repository.Task
+	for status, column := range board.Columns {
+		tasks = append(tasks, column...)
+		board.Columns[status] = []*repository.Task{}
+	}
+	for _, task := range s.filterAccessibleTasks(ctx, userID, tasks) {
+		board.Columns[task.Status] = append(board.Columns[task.Status], task)
+	}
+	return board, nil
+}
+
+// buildSprintBoard groups every task of the sprint into the workflow's columns
+func (s *taskService) buildSprintBoard(ctx context.Context, sprintID string, sprint *repository.Sprint) (*SprintBoard, error) {
+	tasks, err := s.taskRepo.FindBySprintID(ctx, sprintID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Group by status
+	board := &SprintBoard{
+		Columns: make(map[string][]*repository.Task),
+		WIP:     make(map[string]*ColumnWIP),
+	}
+
+	// Columns follow the project's workflow when one is configured
+	var statuses []*repository.ProjectStatus
+	if sprint != nil {
+		statuses, err = s.getProjectStatuses(ctx, sprint.ProjectID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(statuses) == 0 {
+		statuses = make([]*repository.ProjectStatus, 0, len(defaultBoardStatuses))
+		for i, key := range defaultBoardStatuses {
+			statuses = append(statuses, &repository.ProjectStatus{Key: key, Name: formatStatus(key), Position: i})
+		}
+	}
+	board.Statuses = statuses
+
+	for _, status := range statuses {
+		board.Columns[status.Key] = []*repository.Task{}
+	}
+
+	for _, task := range tasks {
+		board.Columns[task.Status] = append(board.Columns[task.Status], task)
+	}
+
+	// Attach WIP limit and current count per column
+	if sprint == nil {
+		return board, nil
+	}
+
+	limits := make(map[string]int)
+	if s.wipLimitRepo != nil {
+		projectLimits, err := s.wipLimitRepo.FindByProjectID(ctx, sprint.ProjectID)
+		if err != nil {
+			slog.WarnContext(ctx, "failed to load WIP limits", "projectID", sprint.ProjectID, "error", err)
+		}
+		for _, l := range projectLimits {
+			limits[l.Status] = l.MaxTasks
+		}
+	}
+
+	for status := range board.Columns {
+		count, _ := s.taskRepo.CountByStatus(ctx, sprint.ProjectID, status)
+		column := &ColumnWIP{Count: count}
+		if max, ok := limits[status]; ok {
+			max := max
+			column.Limit = &max
+		}
+		board.WIP[status] = column
+	}
+
+	return board, nil
+}
+
+// GetSprintBoardByAssignee groups sprint tasks into per-assignee swimlanes.
+// Tasks with several assignees show up in each of their lanes.
+func (s *taskService) GetSprintBoardByAssignee(ctx context.Context, sprintID, userID string) (map[string]*AssigneeBucket, error) {
+	sprint, err := s.sprintRepo.FindByID(ctx, sprintID)
+	if err != nil || sprint == nil {
+		return nil, ErrSprintNotFound
+	}
+
+	hasAccess, _, err := s.memberService.HasEffectiveAccess(ctx, EntityTypeProject, sprint.ProjectID, userID)
+	if err != nil || !hasAccess {
+		return nil, ErrUnauthorized
+	}
+
+	tasks, err := s.taskRepo.FindBySprintID(ctx, sprintID)
+	if err != nil {
+		return nil, err
+	}
+
+	board := map[string]*AssigneeBucket{
+		repository.UnassignedFilter: {Tasks: []*repository.Task{}},
+	}
+
+	for _, task := range tasks {
+		if len(task.AssigneeIDs) == 0 {
+			board[repository.UnassignedFilter].Tasks = append(board[repository.UnassignedFilter].Tasks, task)
+			continue
+		}
+
+		for _, assigneeID := range task.AssigneeIDs {
+			bucket, ok := board[assigneeID]
+			if !ok {
+				bucket = &AssigneeBucket{Tasks: []*repository.Task{}}
+				if user, err := s.userRepo.FindByID(ctx, assigneeID); err == nil {
+					bucket.Assignee = user
+				}
+				board[assigneeID] = bucket
+			}
+			bucket.Tasks = append(bucket.Tasks, task)
+		}
+	}
+
+	return board, nil
+}
+
+func (s *taskService) GetSprintVelocity(ctx context.Context, sprintID, userID string) (int, error) {
+	// Verify user has access to sprint
+	sprint, err := s.sprintRepo.FindByID(ctx, sprintID)
+	if err != nil || sprint == nil {
+		return 0, ErrSprintNotFound
+	}
+
+	hasAccess, _, err := s.memberService.HasEffectiveAccess(ctx, EntityTypeProject, sprint.ProjectID, userID)
+	if err != nil || !hasAccess {
+		return 0, ErrUnauthorized
+	}
+
+	return s.taskRepo.GetSprintVelocity(ctx, sprintID)
+}
+
+func (s *taskService) GetSprintBurndown(ctx context.Context, sprintID, userID string) (*SprintBurndown, error) {
+	// Get sprint
+	sprint, err := s.sprintRepo.FindByID(ctx, sprintID)
+	if err != nil || sprint == nil {
+		return nil, ErrSprintNotFound
+	}
+
+	hasAccess, _, err := s.memberService.HasEffectiveAccess(ctx, EntityTypeProject, sprint.ProjectID, userID)
+	if err != nil || !hasAccess {
+		return nil, ErrUnauthorized
+	}
+
+	// Get total and completed story points
+	totalPoints, _ := s.taskRepo.GetSprintVelocity(ctx, sprintID)
+	completedPoints, _ := s.taskRepo.GetCompletedStoryPoints(ctx, sprintID)
+	remainingPoints := totalPoints - completedPoints
+
+	// Calculate ideal burndown
+	sprintDays := int(sprint.EndDate.Sub(sprint.StartDate).Hours() / 24)
+	if sprintDays == 0 {
+		sprintDays = 1 // Prevent division by zero
+	}
+	pointsPerDay := float64(totalPoints) / float64(sprintDays)
+	
+	idealBurndown := []BurndownPoint{}
+	for i := 0; i <= sprintDays; i++ {
+		date := sprint.StartDate.AddDate(0, 0, i)
+		points := totalPoints - int(float64(i)*pointsPerDay)
+		if points < 0 {
+			points = 0
+		}
+		idealBurndown = append(idealBurndown, BurndownPoint{
+			Date:   date,
+			Points: points,
+		})
+	}
+
+	// Actual burndown: persisted daily snapshots for past days, live value for today
+	snapshotByDate := make(map[string]int)
+	if s.analyticsRepo != nil {
+		snapshots, err := s.analyticsRepo.GetBurndownSnapshots(ctx, sprintID)
+		if err != nil {
+			slog.WarnContext(ctx, "failed to load burndown snapshots", "sprintID", sprintID, "error", err)
+		}
+		for _, snap := range snapshots {
+			snapshotByDate[snap.SnapshotDate.Format("2006-01-02")] = snap.RemainingPoints
+		}
+	}
+
+	// Days without a snapshot (e.g. before snapshots existed) fall back to completed_at
+	tasks, _ := s.taskRepo.FindBySprintID(ctx, sprintID)
+	completedByDate := make(map[string]int)
+	for _, task := range tasks {
+		if task.CompletedAt != nil && task.StoryPoints != nil {
+			dateStr := task.CompletedAt.Format("2006-01-02")
+			completedByDate[dateStr] += *task.StoryPoints
+		}
+	}
+
+	actualBurndown := []BurndownPoint{}
+	today := time.Now().Format("2006-01-02")
+	currentRemaining := totalPoints
+	for i := 0; i <= sprintDays; i++ {
+		date := sprint.StartDate.AddDate(0, 0, i)
+		dateStr := date.Format("2006-01-02")
+		if dateStr > today {
+			break
+		}
+
+		currentRemaining -= completedByDate[dateStr]
+		if currentRemaining < 0 {
+			currentRemaining = 0
+		}
+
+		points := currentRemaining
+		if dateStr == today {
+			points = remainingPoints
+		} else if snap, ok := snapshotByDate[dateStr]; ok {
+			points = snap
+		}
+
+		actualBurndown = append(actualBurndown, BurndownPoint{
+			Date:   date,
+			Points: points,
+		})
+	}
+
+	completionRate := 0.0
+	if totalPoints > 0 {
+		completionRate = float64(completedPoints) / float64(totalPoints) * 100
+	}
+
+	return &SprintBurndown{
+		SprintID:         sprintID,
+		StartDate:        sprint.StartDate,
+		EndDate:          sprint.EndDate,
+		TotalStoryPoints: totalPoints,
+		CompletedPoints:  completedPoints,
+		RemainingPoints:  remainingPoints,
+		IdealBurndown:    idealBurndown,
+		ActualBurndown:   actualBurndown,
+		CompletionRate:   completionRate,
+	}, nil
+}
+
+// ============================================
+// BULK OPERATIONS
+// ============================================
+
+func (s *taskService) BulkUpdateStatus(ctx context.Context, taskIDs []string, status, userID string) error {
+	// Verify user can edit all tasks
+	for _, taskID := range taskIDs {
+		if !s.permService.CanEditTask(ctx, userID, taskID) {
+			return ErrUnauthorized
+		}
+	}
+
+	// Count tasks entering the column per project for WIP limits
+	incoming := make(map[string]int)
+	var subtasks []*repository.Task
+	for _, taskID := range taskIDs {
+		task, err := s.taskRepo.FindByID(ctx, taskID)
+		if err != nil || task == nil {
+			return ErrTaskNotFound
+		}
+		if task.Status == status {
+			continue
+		}
+		if task.ParentTaskID != nil {
+			subtasks = append(subtasks, task)
+		}
+		if err := s.validateStatus(ctx, task.ProjectID, status); err != nil {
+			return err
+		}
+		if err := s.checkStatusTransition(ctx, task.ProjectID, task.Status, status); err != nil {
+			return err
+		}
+		if task.ParentTaskID == nil {
+			incoming[task.ProjectID]++
+		}
+	}
+	for projectID, count := range incoming {
+		if err := s.checkWIPLimit(ctx, projectID, status, count); err != nil {
+			return err
+		}
+	}
+
+	if err := s.taskRepo.BulkUpdateStatus(ctx, taskIDs, status); err != nil {
+		return err
+	}
+
+	// Re-evaluate each affected parent once
+	seenParents := make(map[string]bool)
+	for _, task := range subtasks {
+		if seenParents[*task.ParentTaskID] {
+			continue
+		}
+		seenParents[*task.ParentTaskID] = true
+		task.Status = status
+		s.onSubtaskStatusChanged(ctx, task, userID)
+	}
+	return nil
+}
+
+func (s *taskService) BulkAssign(ctx context.Context, taskIDs []string, assigneeID, actorID string) error {
+	// Verify actor can edit all tasks
+	for _, taskID := range taskIDs {
+		if !s.permService.CanEditTask(ctx, actorID, taskID) {
+			return ErrUnauthorized
+		}
+	}
+
+	// Verify assignee has access to all task projects
+	projectIDs := make([]string, 0, len(taskIDs))
+	for _, taskID := range taskIDs {
+		task, err := s.taskRepo.FindByID(ctx, taskID)
+		if err != nil || task == nil {
+			return ErrTaskNotFound
+		}
+		projectIDs = append(projectIDs, task.ProjectID)
+	}
+	for _, hasAccess := range s.memberService.FilterAccessibleProjects(ctx, assigneeID, projectIDs) {
+		if !hasAccess {
+			return ErrUnauthorized
+		}
+	}
+
+	// Add assignee to all tasks
+	for _, taskID := range taskIDs {
+		if err := s.taskRepo.AddAssignee(ctx, taskID, assigneeID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *taskService) BulkMoveToSprint(ctx context.Context, taskIDs []string, sprintID, userID string) error {
+	// Verify user can edit all tasks
+	tasks := make([]*repository.Task, 0, len(taskIDs))
+	for _, taskID := range taskIDs {
+		if !s.permService.CanEditTask(ctx, userID, taskID) {
+			return ErrUnauthorized
+		}
+		task, err := s.taskRepo.FindByID(ctx, taskID)
+		if err != nil || task == nil {
+			return ErrTaskNotFound
+		}
+		tasks = append(tasks, task)
+	}
+
+	if err := s.taskRepo.BulkMoveToSprint(ctx, taskIDs, sprintID); err != nil {
+		return err
+	}
+
+	s.recordSprintMoves(ctx, tasks, &sprintID, &userID)
+	return nil
+}
+
+// BulkDelete removes the tasks atomically. Subtasks of a deleted task are
+// deleted with it; each one is recorded in the project activity log.
+func (s *taskService) BulkDelete(ctx context.Context, taskIDs []string, userID string) error {
+	if len(taskIDs) == 0 {
+		return ErrInvalidInput
+	}
+
+	// Verify user can delete all tasks
+	for _, taskID := range taskIDs {
+		task, err := s.taskRepo.FindByID(ctx, taskID)
+		if err != nil || task == nil {
+			return ErrTaskNotFound
+		}
+		if !s.permService.CanDeleteTask(ctx, userID, taskID) {
+			return ErrUnauthorized
+		}
+	}
+
+	deleted, err := s.taskRepo.BulkDelete(ctx, taskIDs, userID)
+	if err != nil {
+		return err
+	}
+
+	if s.broadcaster != nil {
+		for _, task := range deleted {
+			s.broadcaster.BroadcastTaskDeleted(task.ProjectID, task.ID, s.getTaskKey(task), userID)
+		}
+	}
+
+	return nil
+}
+
+// Outcome of a bulk label change for one task
+const (
+	BulkLabelApplied = "applied"
+	BulkLabelSkipped = "skipped" // already had the label, or didn't have it to remove
+	BulkLabelFailed  = "failed"
+)
+
+// BulkLabelResult tells what a bulk label change did to one task. Reason
+// explains a skipped or failed task.
+type BulkLabelResult struct {
+	TaskID string
+	Status string
+	Reason string
+}
+
+// BulkAddLabel tags the tasks with a label in one transaction. Tasks that
+// already carry it are skipped. Tasks that are missing, can't be edited by
+// the user or are in another project than the label fail on their own
+// without stopping the rest.
+func (s *taskService) BulkAddLabel(ctx context.Context, taskIDs []string, labelID, userID string) ([]*BulkLabelResult, error) {
+	return s.bulkChangeLabel(ctx, taskIDs, labelID, userID, true)
+}
+
+// BulkRemoveLabel takes a label off the tasks in one transaction, like
+// BulkAddLabel. Tasks without the label are skipped.
+func (s *taskService) BulkRemoveLabel(ctx context.Context, taskIDs []string, labelID, userID string) ([]*BulkLabelResult, error) {
+	return s.bulkChangeLabel(ctx, taskIDs, labelID, userID, false)
+}
+
+func (s *taskService) bulkChangeLabel(ctx context.Context, taskIDs []string, labelID, userID string, add bool) ([]*BulkLabelResult, error) {
+	if len(taskIDs) == 0 {
+		return nil, fmt.Errorf("%w: no tasks given", ErrInvalidInput)
+	}
+
+	label, err := s.labelRepo.FindByID(ctx, labelID)
+	if err != nil || label == nil {
+		return nil, ErrLabelNotFound
+	}
+
+	results := make([]*BulkLabelResult, 0, len(taskIDs))
+	byTask := make(map[string]*BulkLabelResult, len(taskIDs))
+	var eligible []string
+	for _, taskID := range taskIDs {
+		if byTask[taskID] != nil {
+			continue
+		}
+		result := &BulkLabelResult{TaskID: taskID, Status: BulkLabelFailed}
+		byTask[taskID] = result
+		results = append(results, result)
+
+		task, err := s.taskRepo.FindByID(ctx, taskID)
+		switch {
+		case err != nil || task == nil:
+			result.Reason = "task not found"
+		case !s.permService.CanEditTask(ctx, userID, taskID):
+			result.Reason = "no permission to edit the task"
+		case task.ProjectID != label.ProjectID:
+			result.Reason = "label belongs to another project"
+		default:
+			eligible = append(eligible, taskID)
+		}
+	}
+
+	var changed []string
+	if len(eligible) > 0 {
+		if add {
+			changed, err = s.taskLabelRepo.BulkAddLabel(ctx, eligible, labelID)
+		} else {
+			changed, err = s.taskLabelRepo.BulkRemoveLabel(ctx, eligible, labelID)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, taskID := range eligible {
+		byTask[taskID].Status = BulkLabelSkipped
+		if add {
+			byTask[taskID].Reason = "already has the label"
+		} else {
+			byTask[taskID].Reason = "does not have the label"
+		}
+	}
+	for _, taskID := range changed {
+		result := byTask[taskID]
+		if result == nil {
+			continue
+		}
+		result.Status = BulkLabelApplied
+		result.Reason = ""
+
+		activity := &repository.TaskActivity{TaskID: taskID, UserID: &userID, FieldName: strPtr("labels")}
+		if add {
+			activity.Action = "label_added"
+			activity.NewValue = &label.Name
+		} else {
+			activity.Action = "label_removed"
+			activity.OldValue = &label.Name
+		}
+		if err := s.activityRepo.Create(ctx, activity); err != nil {
+			slog.WarnContext(ctx, "failed to log task activity", "taskID", taskID, "action", activity.Action, "error", err)
+		}
+	}
+
+	if len(changed) > 0 {
+		s.readCache.InvalidateProject(ctx, label.ProjectID)
+	}
+	return results, nil
+}
+
+// ============================================
+// DRAG AND DROP
+// ============================================
+
+func (s *taskService) UpdatePosition(ctx context.Context, taskID string, position int, userID string) error {
+	if !s.permService.CanEditTask(ctx, userID, taskID) {
+		return ErrUnauthorized
+	}
+	return s.taskRepo.UpdatePosition(ctx, taskID, position)
+}
+
+
+// ============================================
+// LABELS
+// ============================================
+
+// replaceTaskLabels stores the labels given as IDs or names and leaves the
+// resolved label IDs on the task
+func (s *taskService) replaceTaskLabels(ctx context.Context, task *repository.Task, values []string) error {
+	if err := s.taskLabelRepo.ReplaceLabels(ctx, task.ID, values); err != nil {
+		return err
+	}
+	labels, err := s.taskLabelRepo.FindLabelsByTaskID(ctx, task.ID)
+	if err != nil {
+		return err
+	}
+	task.LabelIDs = make([]string, len(labels))
+	for i, l := range labels {
+		task.LabelIDs[i] = l.ID
+	}
+	return nil
+}
+
+func (s *taskService) AddLabel(ctx context.Context, taskID, labelID, userID string) error {
+	task, err := s.taskRepo.FindByID(ctx, taskID)
+	if err != nil || task == nil {
+		return ErrTaskNotFound
+	}
+
+	if !s.permService.CanEditTask(ctx, userID, taskID) {
+		return ErrUnauthorized
+	}
+
+	label, err := s.labelRepo.FindByID(ctx, labelID)
+	if err != nil || label == nil {
+		return ErrLabelNotFound
+	}
+	if label.ProjectID != task.ProjectID {
+		return fmt.Errorf("%w: label belongs to another project", ErrInvalidInput)
+	}
+
+	if err := s.taskLabelRepo.AddLabel(ctx, taskID, labelID); err != nil {
+		return err
+	}
+	s.readCache.InvalidateProject(ctx, task.ProjectID)
+
+	_ = s.activityRepo.Create(ctx, &repository.TaskActivity{
+		TaskID:    taskID,
+		UserID:    &userID,
+		Action:    "label_added",
+		FieldName: strPtr("labels"),
+		NewValue:  &label.Name,
+	})
+	return nil
+}
+
+func (s *taskService) RemoveLabel(ctx context.Context, taskID, labelID, userID string) error {
+	task, err := s.taskRepo.FindByID(ctx, taskID)
+	if err != nil || task == nil {
+		return ErrTaskNotFound
+	}
+
+	if !s.permService.CanEditTask(ctx, userID, taskID) {
+		return ErrUnauthorized
+	}
+
+	if err := s.taskLabelRepo.RemoveLabel(ctx, taskID, labelID); err != nil {
+		return err
+	}
+	s.readCache.InvalidateProject(ctx, task.ProjectID)
+
+	activity := &repository.TaskActivity{
+		TaskID:    taskID,
+		UserID:    &userID,
+		Action:    "label_removed",
+		FieldName: strPtr("labels"),
+		OldValue:  &labelID,
+	}
+	if label, err := s.labelRepo.FindByID(ctx, labelID); err == nil && label != nil {
+		activity.OldValue = &label.Name
+	}
+	_ = s.activityRepo.Create(ctx, activity)
+	return nil
+}
+
+func (s *taskService) GetLabels(ctx context.Context, taskID, userID string) ([]*repository.Label, error) {
+	task, err := s.taskRepo.FindByID(ctx, taskID)
+	if err != nil || task == nil {
+		return nil, ErrTaskNotFound
+	}
+
+	if !s.permService.CanAccessTask(ctx, userID, taskID) {
+		return nil, ErrUnauthorized
+	}
+
+	return s.taskLabelRepo.FindLabelsByTaskID(ctx, taskID)
+}
+
+// ============================================
+// CLONE
+// ============================================
+
+func (s *taskService) CloneTask(ctx context.Context, taskID, userID string, opts CloneOptions) (*repository.Task, error) {
+	source, err := s.taskRepo.FindByID(ctx, taskID)
+	if err != nil || source == nil {
+		return nil, ErrNotFound
+	}
+
+	if !s.permService.CanEditTask(ctx, userID, taskID) {
+		return nil, ErrUnauthorized
+	}
+
+	targetProjectID := source.ProjectID
+	if opts.TargetProjectID != nil && *opts.TargetProjectID != "" {
+		targetProjectID = *opts.TargetProjectID
+	}
+
+	if targetProjectID != source.ProjectID {
+		project, err := s.projectRepo.FindByID(ctx, targetProjectID)
+		if err != nil || project == nil {
+			return nil, ErrProjectNotFound
+		}
+	}
+
+	if !s.permService.CanCreateTask(ctx, userID, targetProjectID) {
+		return nil, ErrUnauthorized
+	}
+
+	// Copies share the stored file but still count against the target quota
+	if opts.CopyAttachments && s.uploadLimits.WorkspaceQuota > 0 {
+		size, err := s.storedAttachmentSize(ctx, source, opts.IncludeSubtasks)
+		if err != nil {
+			return nil, err
+		}
+		if size > 0 {
+			used, err := s.attachmentRepo.TotalStoredSizeInProjectWorkspace(ctx, targetProjectID)
+			if err != nil {
+				return nil, err
+			}
+			if used+size > s.uploadLimits.WorkspaceQuota {
+				return nil, fmt.Errorf("%w: workspace attachment storage is full (%d MB of %d MB used)",
+					ErrLimitExceeded, used>>20, s.uploadLimits.WorkspaceQuota>>20)
+			}
+		}
+	}
+
+	// Source label ID -> label ID in the target project, shared across subtasks
+	labelMap := make(map[string]string)
+
+	clone, err := s.cloneTaskTree(ctx, source, targetProjectID, nil, userID, opts, labelMap)
+	if err != nil {
+		return nil, err
+	}
+
+	_ = s.activityRepo.Create(ctx, &repository.TaskActivity{
+		TaskID:    clone.ID,
+		UserID:    &userID,
+		Action:    "created",
+		FieldName: strPtr("cloned_from"),
+		NewValue:  &source.ID,
+	})
+
+	return clone, nil
+}
+
+// cloneTaskTree copies one task (and its subtasks when requested) under parentID
+func (s *taskService) cloneTaskTree(
+	ctx context.Context,
+	source *repository.Task,
+	projectID string,
+	parentID *string,
+	userID string,
+	opts CloneOptions,
+	labelMap map[string]string,
+) (*repository.Task, error) {
+	labelIDs, err := s.mapLabelsToProject(ctx, source.LabelIDs, projectID, labelMap)
+	if err != nil {
+		return nil, err
+	}
+
+	assigneeIDs := []string{}
+	if opts.CopyAssignees {
+		for _, assigneeID := range source.AssigneeIDs {
+			// Assignees who can't see the target project are dropped
+			hasAccess, _, err := s.memberService.HasEffectiveAccess(ctx, EntityTypeProject, projectID, assigneeID)
+			if err == nil && hasAccess {
+				assigneeIDs = append(assigneeIDs, assigneeID)
+			}
+		}
+	}
+
+	// Sprints are project-scoped, so the sprint only carries over within the project
+	var sprintID *string
+	if projectID == source.ProjectID {
+		sprintID = source.SprintID
+	}
+
+	clone := &repository.Task{
+		ProjectID:      projectID,
+		SprintID:       sprintID,
+		ParentTaskID:   parentID,
+		Title:          source.Title,
+		Description:    source.Description,
+		Status:         s.defaultStatus(ctx, projectID),
+		Priority:       source.Priority,
+		Type:           source.Type,
+		AssigneeIDs:    assigneeIDs,
+		WatcherIDs:     []string{userID},
+		LabelIDs:       labelIDs,
+		StoryPoints:    source.StoryPoints,
+		EstimatedHours: source.EstimatedHours,
+		StartDate:      source.StartDate,
+		DueDate:        source.DueDate,
+		CreatedBy:      &userID,
+	}
+
+	if err := s.taskRepo.Create(ctx, clone); err != nil {
+		return nil, err
+	}
+	if err := s.taskLabelRepo.ReplaceLabels(ctx, clone.ID, labelIDs); err != nil {
+		return nil, err
+	}
+
+	// Checklists are always copied, with every item reset to not completed
+	checklists, err := s.checklistRepo.FindByTaskID(ctx, source.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, checklist := range checklists {
+		copied := &repository.TaskChecklist{TaskID: clone.ID, Title: checklist.Title}
+		if err := s.checklistRepo.CreateChecklist(ctx, copied); err != nil {
+			return nil, err
+		}
+		for _, item := range checklist.Items {
+			if err := s.checklistRepo.CreateItem(ctx, &repository.ChecklistItem{
+				ChecklistID: copied.ID,
+				Content:     item.Content,
+				AssigneeID:  item.AssigneeID,
+				Position:    item.Position,
+			}); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if opts.CopyComments {
+		comments, err := s.commentRepo.FindByTaskID(ctx, source.ID)
+		if err != nil {
+			return nil, err
+		}
+		// Oldest first, so parents are copied before their replies
+		copiedIDs := make(map[string]string, len(comments))
+		for _, comment := range comments {
+			copied := &repository.TaskComment{
+				TaskID:         clone.ID,
+				UserID:         comment.UserID,
+				Content:        comment.Content,
+				MentionedUsers: comment.MentionedUsers,
+			}
+			if comment.ParentCommentID != nil {
+				if parentID, ok := copiedIDs[*comment.ParentCommentID]; ok {
+					copied.ParentCommentID = &parentID
+				}
+			}
+			if err := s.commentRepo.Create(ctx, copied); err != nil {
+				return nil, err
+			}
+			copiedIDs[comment.ID] = copied.ID
+		}
+	}
+
+	if opts.CopyAttachments {
+		attachments, err := s.attachmentRepo.FindByTaskID(ctx, source.ID)
+		if err != nil {
+			return nil, err
+		}
+		// The copy points at the same stored file
+		for _, attachment := range attachments {
+			if err := s.attachmentRepo.Create(ctx, &repository.TaskAttachment{
+				TaskID:     clone.ID,
+				UserID:     attachment.UserID,
+				Filename:   attachment.Filename,
+				FileURL:    attachment.FileURL,
+				FileSize:   attachment.FileSize,
+				MimeType:   attachment.MimeType,
+				StorageKey: attachment.StorageKey,
+			}); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if opts.IncludeSubtasks {
+		subtasks, err := s.taskRepo.FindByParentTaskID(ctx, source.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, subtask := range subtasks {
+			if _, err := s.cloneTaskTree(ctx, subtask, projectID, &clone.ID, userID, opts, labelMap); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return clone, nil
+}
+
+// storedAttachmentSize sums the uploaded attachments a clone of task would copy
+func (s *taskService) storedAttachmentSize(ctx context.Context, task *repository.Task, includeSubtasks bool) (int64, error) {
+	attachments, err := s.attachmentRepo.FindByTaskID(ctx, task.ID)
+	if err != nil {
+		return 0, err
+	}
+	var total int64
+	for _, attachment := range attachments {
+		if attachment.StorageKey != nil {
+			total += attachment.FileSize
+		}
+	}
+	if !includeSubtasks {
+		return total, nil
+	}
+	subtasks, err := s.taskRepo.FindByParentTaskID(ctx, task.ID)
+	if err != nil {
+		return 0, err
+	}
+	for _, subtask := range subtasks {
+		size, err := s.storedAttachmentSize(ctx, subtask, true)
+		if err != nil {
+			return 0, err
+		}
+		total += size
+	}
+	return total, nil
+}
+
+// mapLabelsToProject returns label IDs valid in projectID, matching labels of
+// other projects by name and creating the ones that don't exist there yet
+func (s *taskService) mapLabelsToProject(ctx context.Context, labelIDs []string, projectID string, labelMap map[string]string) ([]string, error) {
+	mapped := []string{}
+	for _, labelID := range labelIDs {
+		if id, ok := labelMap[labelID]; ok {
+			mapped = append(mapped, id)
+			continue
+		}
+
+		label, err := s.labelRepo.FindByID(ctx, labelID)
+		if err != nil {
+			return nil, err
+		}
+		if label == nil {
+			continue // stale label reference
+		}
+
+		if label.ProjectID != projectID {
+			existing, err := s.labelRepo.FindByName(ctx, projectID, label.Name)
+			if err != nil {
+				return nil, err
+			}
+			if existing == nil {
+				existing = &repository.Label{Name: label.Name, Color: label.Color, ProjectID: projectID}
+				if err := s.labelRepo.Create(ctx, existing); err != nil {
+					return nil, err
+				}
+			}
+			label = existing
+		}
+
+		labelMap[labelID] = label.ID
+		mapped = append(mapped, label.ID)
+	}
+	return mapped, nil
+}
+
+// ============================================
+// STATUS WORKFLOW
+// ============================================
+
+// defaultBoardStatuses are the board columns of projects without a configured workflow
+var defaultBoardStatuses = []string{"todo", "in_progress", "in_review", "done"}
+
+func (s *taskService) getProjectStatuses(ctx context.Context, projectID string) ([]*repository.ProjectStatus, error) {
+	if s.statusRepo == nil {
+		return nil, nil
+	}
+	return s.statusRepo.FindByProjectID(ctx, projectID)
+}
+
+// defaultStatus is the first column of the project's workflow, or "todo" without one
+func (s *taskService) defaultStatus(ctx context.Context, projectID string) string {
+	statuses, err := s.getProjectStatuses(ctx, projectID)
+	if err != nil || len(statuses) == 0 {
+		return "todo"
+	}
+	return statuses[0].Key
+}
+
+// validateTaskUpdate rejects a partial update that sets title, status or
+// priority to an empty or unknown value. Nil fields are left unchanged and
+// not checked.
+func (s *taskService) validateTaskUpdate(ctx context.Context, projectID string, req *models.UpdateTaskRequest) error {
+	if req.Title != nil && strings.TrimSpace(*req.Title) == "" {
+		return fmt.Errorf("%w: title cannot be empty", ErrInvalidInput)
+	}
+	if req.Status != nil {
+		if err := s.validateStatus(ctx, projectID, *req.Status); err != nil {
+			return err
+		}
+	}
+	if req.Priority != nil {
+		if err := validatePriority(*req.Priority); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateStatus returns ErrInvalidInput unless status is a column of the
+// project's workflow or, for projects without one, a default task status
+func (s *taskService) validateStatus(ctx context.Context, projectID, status string) error {
+	if strings.TrimSpace(status) == "" {
+		return fmt.Errorf("%w: status cannot be empty", ErrInvalidInput)
+	}
+	statuses, err := s.getProjectStatuses(ctx, projectID)
+	if err != nil {
+		return err
+	}
+	if len(statuses) == 0 {
+		if types.IsValidTaskStatus(status) {
+			return nil
+		}
+	}
+	for _, st := range statuses {
+		if st.Key == status {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: unknown status %q", ErrInvalidInput, status)
+}
+
+// validatePriority returns ErrInvalidInput unless priority is one of types.ValidPriorities
+func validatePriority(priority string) error {
+	if strings.TrimSpace(priority) == "" {
+		return fmt.Errorf("%w: priority cannot be empty", ErrInvalidInput)
+	}
+	if !types.IsValidPriority(priority) {
+		return fmt.Errorf("%w: unknown priority %q; expected one of %s",
+			ErrInvalidInput, priority, strings.Join(types.ValidPriorities, ", "))
+	}
+	return nil
+}
+
+// checkStatusTransition returns ErrInvalidTransition if the project's workflow
+// has no such status or does not allow moving there from the current one.
+// Projects without configured statuses accept any transition.
+func (s *taskService) checkStatusTransition(ctx context.Context, projectID, from, to string) error {
+	statuses, err := s.getProjectStatuses(ctx, projectID)
+	if err != nil {
+		return err
+	}
+	if len(statuses) == 0 {
+		return nil
+	}
+
+	var current, target *repository.ProjectStatus
+	for _, st := range statuses {
+		switch st.Key {
+		case from:
+			current = st
+		case to:
+			target = st
 		}
 	}
 
-	return board, nil
+	if target == nil {
+		return fmt.Errorf("%w: status %q is not part of this project's workflow", ErrInvalidTransition, to)
+	}
+	// Tasks left in a status that was removed from the workflow may move anywhere
+	if current != nil && !current.CanTransitionTo(to) {
+		return fmt.Errorf("%w: cannot move from %q to %q", ErrInvalidTransition, from, to)
+	}
+	return nil
 }
 
-func (s *taskService) GetSprintVelocity(ctx context.Context, sprintID, userID string) (int, error) {
-	// Verify user has access to sprint
-	sprint, err := s.sprintRepo.FindByID(ctx, sprintID)
-	if err != nil || sprint == nil {
-		return 0, ErrNotFound
+// checkStatusMove applies the workflow transition rules and, for top-level
+// tasks, the WIP limit of the target column to a status change of task
+func (s *taskService) checkStatusMove(ctx context.Context, task *repository.Task, status string) error {
+	if err := s.checkStatusTransition(ctx, task.ProjectID, task.Status, status); err != nil {
+		return err
+	}
+	if task.ParentTaskID == nil {
+		return s.checkWIPLimit(ctx, task.ProjectID, status, 1)
 	}
+	return nil
+}
 
-	hasAccess, _, err := s.memberService.HasEffectiveAccess(ctx, EntityTypeProject, sprint.ProjectID, userID)
-	if err != nil || !hasAccess {
-		return 0, ErrUnauthorized
+// ============================================
+// WIP LIMITS
+// ============================================
+
+// checkWIPLimit returns ErrWIPLimitExceeded if adding incoming tasks to the column would exceed its limit
+func (s *taskService) checkWIPLimit(ctx context.Context, projectID, status string, incoming int) error {
+	if s.wipLimitRepo == nil || incoming <= 0 {
+		return nil
 	}
 
-	return s.taskRepo.GetSprintVelocity(ctx, sprintID)
+	limit, err := s.wipLimitRepo.FindByStatus(ctx, projectID, status)
+	if err != nil {
+		return err
+	}
+	if limit == nil {
+		return nil
+	}
+
+	count, err := s.taskRepo.CountByStatus(ctx, projectID, status)
+	if err != nil {
+		return err
+	}
+	if count+incoming > limit.MaxTasks {
+		return ErrWIPLimitExceeded
+	}
+	return nil
 }
 
-func (s *taskService) GetSprintBurndown(ctx context.Context, sprintID, userID string) (*SprintBurndown, error) {
-	// Get sprint
-	sprint, err := s.sprintRepo.FindByID(ctx, sprintID)
-	if err != nil || sprint == nil {
-		return nil, ErrNotFound
+// SetWIPLimit sets the max tasks for a column; maxTasks <= 0 removes the limit
+func (s *taskService) SetWIPLimit(ctx context.Context, projectID, status string, maxTasks int, userID string) error {
+	// Limits shape the whole board, so only project admins change them
+	if !s.permService.CanManageProject(ctx, userID, projectID) {
+		return ErrUnauthorized
 	}
 
-	hasAccess, _, err := s.memberService.HasEffectiveAccess(ctx, EntityTypeProject, sprint.ProjectID, userID)
+	if status == "" {
+		return ErrInvalidInput
+	}
+
+	var err error
+	if maxTasks <= 0 {
+		err = s.wipLimitRepo.Delete(ctx, projectID, status)
+	} else {
+		err = s.wipLimitRepo.Upsert(ctx, &repository.WIPLimit{
+			ProjectID: projectID,
+			Status:    status,
+			MaxTasks:  maxTasks,
+		})
+	}
+	if err != nil {
+		return err
+	}
+	// Boards show each column's limit
+	s.readCache.InvalidateProject(ctx, projectID)
+	return nil
+}
+
+func (s *taskService) GetWIPLimits(ctx context.Context, projectID, userID string) ([]*repository.WIPLimit, error) {
+	hasAccess, _, err := s.memberService.HasEffectiveAccess(ctx, EntityTypeProject, projectID, userID)
 	if err != nil || !hasAccess {
 		return nil, ErrUnauthorized
 	}
 
-	// Get total and completed story points
-	totalPoints, _ := s.taskRepo.GetSprintVelocity(ctx, sprintID)
-	completedPoints, _ := s.taskRepo.GetCompletedStoryPoints(ctx, sprintID)
-	remainingPoints := totalPoints - completedPoints
+	return s.wipLimitRepo.FindByProjectID(ctx, projectID)
+}
 
-	// Calculate ideal burndown
-	sprintDays := int(sprint.EndDate.Sub(sprint.StartDate).Hours() / 24)
-	if sprintDays == 0 {
-		sprintDays = 1 // Prevent division by zero
-	}
-	pointsPerDay := float64(totalPoints) / float64(sprintDays)
-	
-	idealBurndown := []BurndownPoint{}
-	for i := 0; i <= sprintDays; i++ {
-		date := sprint.StartDate.AddDate(0, 0, i)
-		points := totalPoints - int(float64(i)*pointsPerDay)
-		if points < 0 {
-			points = 0
-		}
-		idealBurndown = append(idealBurndown, BurndownPoint{
-			Date:   date,
-			Points: points,
-		})
+// ============================================
+// TASK SETTINGS & SUBTASK ROLLUP
+// ============================================
+
+func (s *taskService) GetTaskSettings(ctx context.Context, projectID, userID string) (*repository.ProjectTaskSettings, error) {
+	hasAccess, _, err := s.memberService.HasEffectiveAccess(ctx, EntityTypeProject, projectID, userID)
+	if err != nil || !hasAccess {
+		return nil, ErrUnauthorized
 	}
+	return s.settingsRepo.Get(ctx, projectID)
+}
 
-	// Calculate actual burndown from activity history
-	actualBurndown := []BurndownPoint{}
-	tasks, _ := s.taskRepo.FindBySprintID(ctx, sprintID)
-	
-	// Create map of date -> completed points
-	completedByDate := make(map[string]int)
-	for _, task := range tasks {
-		if task.CompletedAt != nil && task.StoryPoints != nil {
-			dateStr := task.CompletedAt.Format("2006-01-02")
-			completedByDate[dateStr] += *task.StoryPoints
-		}
+// UpdateTaskSettings changes the project's task behaviour; project admins only
+func (s *taskService) UpdateTaskSettings(ctx context.Context, projectID, userID string, req *models.UpdateTaskSettingsRequest) (*repository.ProjectTaskSettings, error) {
+	if !s.permService.CanManageProject(ctx, userID, projectID) {
+		return nil, ErrUnauthorized
 	}
 
-	// Build actual burndown
-	currentRemaining := totalPoints
-	for i := 0; i <= sprintDays; i++ {
-		date := sprint.StartDate.AddDate(0, 0, i)
-		dateStr := date.Format("2006-01-02")
-		
-		if completed, ok := completedByDate[dateStr]; ok {
-			currentRemaining -= completed
+	settings, err := s.settingsRepo.Get(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if req.ParentCompletion != nil {
+		if !repository.IsValidParentCompletion(*req.ParentCompletion) {
+			return nil, fmt.Errorf("%w: parentCompletion must be off, suggest or auto", ErrInvalidInput)
 		}
-		
-		if currentRemaining < 0 {
-			currentRemaining = 0
+		settings.ParentCompletion = *req.ParentCompletion
+	}
+	if req.SprintCarryOver != nil {
+		if !repository.IsValidSprintCarryOver(*req.SprintCarryOver) {
+			return nil, fmt.Errorf("%w: sprintCarryOver must be next_sprint or backlog", ErrInvalidInput)
 		}
-		
-		actualBurndown = append(actualBurndown, BurndownPoint{
-			Date:   date,
-			Points: currentRemaining,
-		})
+		settings.SprintCarryOver = *req.SprintCarryOver
 	}
 
-	completionRate := 0.0
-	if totalPoints > 0 {
-		completionRate = float64(completedPoints) / float64(totalPoints) * 100
+	if err := s.settingsRepo.Save(ctx, settings); err != nil {
+		return nil, err
 	}
-
-	return &SprintBurndown{
-		SprintID:         sprintID,
-		StartDate:        sprint.StartDate,
-		EndDate:          sprint.EndDate,
-		TotalStoryPoints: totalPoints,
-		CompletedPoints:  completedPoints,
-		RemainingPoints:  remainingPoints,
-		IdealBurndown:    idealBurndown,
-		ActualBurndown:   actualBurndown,
-		CompletionRate:   completionRate,
-	}, nil
+	return settings, nil
 }
 
-// ============================================
-// BULK OPERATIONS
-// ============================================
+// onSubtaskStatusChanged is called after a subtask's status changed, or after
+// a task joined or left the subtasks of subtask.ParentTaskID. It broadcasts
+// the parent's new progress and, once every subtask is done, completes the
+// parent or suggests doing so as the project's settings say.
+func (s *taskService) onSubtaskStatusChanged(ctx context.Context, subtask *repository.Task, userID string) {
+	if subtask.ParentTaskID == nil {
+		return
+	}
+	parent := s.broadcastSubtaskProgress(ctx, *subtask.ParentTaskID, userID)
+	if parent == nil || parent.Status == "done" || parent.SubtaskTotal == 0 || parent.SubtaskCompleted < parent.SubtaskTotal {
+		return
+	}
+	if s.settingsRepo == nil {
+		return
+	}
 
-func (s *taskService) BulkUpdateStatus(ctx context.Context, taskIDs []string, status, userID string) error {
-	// Verify user can edit all tasks
-	for _, taskID := range taskIDs {
-		if !s.permService.CanEditTask(ctx, userID, taskID) {
-			return ErrUnauthorized
+	settings, err := s.settingsRepo.Get(ctx, parent.ProjectID)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to load task settings", "projectID", parent.ProjectID, "error", err)
+		return
+	}
+
+	switch settings.ParentCompletion {
+	case repository.ParentCompletionAuto:
+		if s.autoCompleteParent(ctx, parent, userID) {
+			return
 		}
+		// The workflow doesn't allow it; suggest it instead
+		s.suggestCompletingParent(ctx, parent, userID)
+	case repository.ParentCompletionSuggest:
+		s.suggestCompletingParent(ctx, parent, userID)
 	}
+}
 
-	return s.taskRepo.BulkUpdateStatus(ctx, taskIDs, status)
+// broadcastSubtaskProgress sends the parent's subtask counts to its project
+// room and returns the reloaded parent, or nil if it no longer exists
+func (s *taskService) broadcastSubtaskProgress(ctx context.Context, parentID, userID string) *repository.Task {
+	parent, err := s.taskRepo.FindByID(ctx, parentID)
+	if err != nil || parent == nil {
+		return nil
+	}
+	if s.broadcaster != nil {
+		data := s.taskToMap(parent)
+		data["subtaskTotal"] = parent.SubtaskTotal
+		data["subtaskCompleted"] = parent.SubtaskCompleted
+		data["subtaskStoryPoints"] = parent.SubtaskStoryPoints
+		s.broadcaster.BroadcastTaskUpdated(parent.ProjectID, data, []string{"subtasks"}, userID)
+	}
+	return parent
 }
 
-func (s *taskService) BulkAssign(ctx context.Context, taskIDs []string, assigneeID, actorID string) error {
-	// Verify actor can edit all tasks
-	for _, taskID := range taskIDs {
-		if !s.permService.CanEditTask(ctx, actorID, taskID) {
-			return ErrUnauthorized
-		}
+// autoCompleteParent moves the parent to done on behalf of userID. It
+// reports false when the project's workflow doesn't allow the move.
+func (s *taskService) autoCompleteParent(ctx context.Context, parent *repository.Task, userID string) bool {
+	const doneStatus = "done"
+	if err := s.validateStatus(ctx, parent.ProjectID, doneStatus); err != nil {
+		return false
+	}
+	if err := s.checkStatusTransition(ctx, parent.ProjectID, parent.Status, doneStatus); err != nil {
+		return false
+	}
+	if err := s.taskRepo.UpdateStatus(ctx, parent.ID, doneStatus, nil); err != nil {
+		slog.WarnContext(ctx, "failed to complete parent task", "taskID", parent.ID, "error", err)
+		return true
 	}
 
-	// Verify assignee has access to all task projects
-	for _, taskID := range taskIDs {
-		task, err := s.taskRepo.FindByID(ctx, taskID)
-		if err != nil || task == nil {
-			return ErrNotFound
-		}
-		
-		hasAccess, _, err := s.memberService.HasEffectiveAccess(ctx, EntityTypeProject, task.ProjectID, assigneeID)
-		if err != nil || !hasAccess {
-			return ErrUnauthorized
+	oldStatus := parent.Status
+	parent.Status = doneStatus
+	s.recordStatusHistory(ctx, parent.ID, oldStatus, doneStatus, userID)
+	s.recalculateLinkedGoals(ctx, parent.ID)
+	s.unblockDependents(ctx, parent, userID)
+
+	s.notificationSvc.SendBatchNotifications(
+		ctx,
+		parent.AssigneeIDs,
+		userID,
+		notification.TypeSubtasksCompleted,
+		"Task Completed",
+		fmt.Sprintf("'%s' was completed because all of its subtasks are done", parent.Title),
+		map[string]interface{}{
+			"taskId":    parent.ID,
+			"taskTitle": parent.Title,
+			"projectId": parent.ProjectID,
+			"action":    "view_task",
+		},
+	)
+
+	if s.broadcaster != nil {
+		s.broadcaster.BroadcastTaskStatusChanged(parent.ProjectID, s.taskToMap(parent), oldStatus, doneStatus, userID)
+	}
+
+	// The parent may itself be the last open subtask of another task
+	s.onSubtaskStatusChanged(ctx, parent, userID)
+	return true
+}
+
+func (s *taskService) suggestCompletingParent(ctx context.Context, parent *repository.Task, userID string) {
+	recipients := parent.AssigneeIDs
+	if len(recipients) == 0 && parent.CreatedBy != nil {
+		recipients = []string{*parent.CreatedBy}
+	}
+	s.notificationSvc.SendBatchNotifications(
+		ctx,
+		recipients,
+		userID,
+		notification.TypeSubtasksCompleted,
+		"All Subtasks Done",
+		fmt.Sprintf("All subtasks of '%s' are done. It may be ready to complete.", parent.Title),
+		map[string]interface{}{
+			"taskId":    parent.ID,
+			"taskTitle": parent.Title,
+			"projectId": parent.ProjectID,
+			"action":    "view_task",
+		},
+	)
+}
+
+// Spacing between neighbouring positions after a column is renumbered
+const taskPositionGap = 1024
+
+// ReorderTask places a task between two neighbours of a column. beforeTaskID is the task
+// that ends up directly above it and afterTaskID the one directly below; either may be nil
+// at the edges of the column. The column is the task's current sprint (or backlog) and targetStatus.
+func (s *taskService) ReorderTask(ctx context.Context, taskID, userID string, targetStatus string, beforeTaskID, afterTaskID *string) (*repository.Task, error) {
+	task, err := s.taskRepo.FindByID(ctx, taskID)
+	if err != nil || task == nil {
+		return nil, ErrTaskNotFound
+	}
+
+	if !s.permService.CanEditTask(ctx, userID, taskID) {
+		return nil, ErrUnauthorized
+	}
+
+	if targetStatus == "" {
+		targetStatus = task.Status
+	}
+
+	// Find the slot first, so bad neighbours are rejected before the status moves
+	position, err := s.computeColumnPosition(ctx, task, targetStatus, beforeTaskID, afterTaskID)
+	if err == errPositionGapExhausted {
+		if err := s.taskRepo.RenumberColumn(ctx, task.ProjectID, task.SprintID, targetStatus, taskPositionGap); err != nil {
+			return nil, err
 		}
+		position, err = s.computeColumnPosition(ctx, task, targetStatus, beforeTaskID, afterTaskID)
+	}
+	if err != nil {
+		return nil, err
 	}
 
-	// Add assignee to all tasks
-	for _, taskID := range taskIDs {
-		if err := s.taskRepo.AddAssignee(ctx, taskID, assigneeID); err != nil {
-			return err
+	if targetStatus != task.Status {
+		if err := s.UpdateStatus(ctx, taskID, targetStatus, userID, nil); err != nil {
+			return nil, err
 		}
 	}
 
-	return nil
+	if err := s.taskRepo.UpdatePosition(ctx, taskID, position); err != nil {
+		return nil, err
+	}
+
+	updated, err := s.taskRepo.FindByID(ctx, taskID)
+	if err != nil || updated == nil {
+		return nil, ErrTaskNotFound
+	}
+
+	if s.broadcaster != nil {
+		s.broadcaster.BroadcastTaskUpdated(updated.ProjectID, s.taskToMap(updated), []string{"position"}, userID)
+	}
+
+	return updated, nil
 }
 
-func (s *taskService) BulkMoveToSprint(ctx context.Context, taskIDs []string, sprintID, userID string) error {
-	// Verify user can edit all tasks
-	for _, taskID := range taskIDs {
-		if !s.permService.CanEditTask(ctx, userID, taskID) {
-			return ErrUnauthorized
+var errPositionGapExhausted = errors.New("no position gap left between neighbours")
+
+// computeColumnPosition returns a position between the requested neighbours of the column
+func (s *taskService) computeColumnPosition(ctx context.Context, task *repository.Task, status string, beforeTaskID, afterTaskID *string) (int, error) {
+	column, err := s.taskRepo.FindColumnTasks(ctx, task.ProjectID, task.SprintID, status)
+	if err != nil {
+		return 0, err
+	}
+
+	// Neighbours are looked up without the moved task itself
+	others := make([]positionedItem, 0, len(column))
+	for _, t := range column {
+		if t.ID != task.ID {
+			others = append(others, positionedItem{id: t.ID, position: t.Position})
 		}
 	}
 
-	return s.taskRepo.BulkMoveToSprint(ctx, taskIDs, sprintID)
+	return slotPosition(others, beforeTaskID, afterTaskID, taskPositionGap)
 }
 
+// positionedItem is an entry of a manually ordered list
+type positionedItem struct {
+	id       string
+	position int
+}
 
-// ============================================
-// DRAG AND DROP
-// ============================================
+// slotPosition returns a position between two neighbours of an ordered list that
+// excludes the moved entry. beforeID ends up directly above the slot and afterID
+// directly below; with neither, the slot is at the end. Neighbours that are not
+// in the list, or not next to each other in that order, are ErrInvalidInput.
+// Returns errPositionGapExhausted when the neighbours are adjacent and the list
+// needs renumbering.
+func slotPosition(others []positionedItem, beforeID, afterID *string, gap int) (int, error) {
+	indexOf := func(id string) int {
+		for i, o := range others {
+			if o.id == id {
+				return i
+			}
+		}
+		return -1
+	}
 
-func (s *taskService) UpdatePosition(ctx context.Context, taskID string, position int, userID string) error {
-	if !s.permService.CanEditTask(ctx, userID, taskID) {
-		return ErrUnauthorized
+	var prev, next *positionedItem
+	if beforeID != nil && afterID != nil {
+		if i, j := indexOf(*beforeID), indexOf(*afterID); i >= 0 && j >= 0 && j != i+1 {
+			return 0, fmt.Errorf("%w: the neighbours are not next to each other in that order", ErrInvalidInput)
+		}
+	}
+	if beforeID != nil {
+		i := indexOf(*beforeID)
+		if i < 0 {
+			return 0, fmt.Errorf("%w: the item above is not in this list", ErrInvalidInput)
+		}
+		prev = &others[i]
+		if afterID == nil && i+1 < len(others) {
+			next = &others[i+1]
+		}
+	}
+	if afterID != nil {
+		i := indexOf(*afterID)
+		if i < 0 {
+			return 0, fmt.Errorf("%w: the item below is not in this list", ErrInvalidInput)
+		}
+		next = &others[i]
+		if beforeID == nil && i > 0 {
+			prev = &others[i-1]
+		}
+	}
+	if beforeID == nil && afterID == nil && len(others) > 0 {
+		// No neighbours given: append to the end of the list
+		prev = &others[len(others)-1]
+	}
+
+	switch {
+	case prev == nil && next == nil:
+		return gap, nil
+	case next == nil:
+		return prev.position + gap, nil
 	}
-	return s.taskRepo.UpdatePosition(ctx, taskID, position)
-}
 
+	lo := 0
+	if prev != nil {
+		lo = prev.position
+	}
+	if next.position-lo <= 1 {
+		return 0, errPositionGapExhausted
+	}
+	return lo + (next.position-lo)/2, nil
+}
 
 // ✅ FIXED: service/task_service.go - ReorderTasksInColumn
 func (s *taskService) ReorderTasksInColumn(
@@ -2381,8 +5752,8 @@ func (s *taskService) ReorderTasksInColumn(
 	newPosition int,
 	userID string,
 ) error {
-	log.Printf("🔄 ReorderTasksInColumn: project=%s, status=%s, movedTask=%s, newPos=%d",
-		projectID, status, movedTaskID, newPosition)
+	slog.DebugContext(ctx, "reordering column", "projectID", projectID, "status", status,
+		"taskID", movedTaskID, "position", newPosition)
 
 	// Get ALL tasks in target column
 	allTasks, err := s.taskRepo.FindByStatus(ctx, projectID, status)
@@ -2390,20 +5761,11 @@ func (s *taskService) ReorderTasksInColumn(
 		return err
 	}
 
-	log.Printf("📊 Found %d tasks in column %s", len(allTasks), status)
-
-	// Separate parents from subtasks
+	// Only parents are ordered; subtasks follow their parent
 	parents := make([]*repository.Task, 0)
-	subtasksMap := make(map[string][]*repository.Task)
-	
 	for _, t := range allTasks {
 		if t.ParentTaskID == nil {
 			parents = append(parents, t)
-		} else {
-			if _, exists := subtasksMap[*t.ParentTaskID]; !exists {
-				subtasksMap[*t.ParentTaskID] = make([]*repository.Task, 0)
-			}
-			subtasksMap[*t.ParentTaskID] = append(subtasksMap[*t.ParentTaskID], t)
 		}
 	}
 
@@ -2419,12 +5781,9 @@ func (s *taskService) ReorderTasksInColumn(
 	}
 
 	if movedTask == nil {
-		log.Printf("❌ Moved task not found in parents list")
 		return ErrNotFound
 	}
 
-	log.Printf("🎯 Found moved task at index %d", movedIndex)
-
 	// Build list without moved task
 	otherParents := make([]*repository.Task, 0, len(parents)-1)
 	for i, t := range parents {
@@ -2441,8 +5800,6 @@ func (s *taskService) ReorderTasksInColumn(
 		newPosition = len(otherParents)
 	}
 
-	log.Printf("📍 Inserting at position %d (out of %d tasks)", newPosition, len(otherParents))
-
 	// Build final order with moved task inserted at new position
 	finalOrder := make([]*repository.Task, 0, len(parents))
 	finalOrder = append(finalOrder, otherParents[:newPosition]...)
@@ -2451,38 +5808,24 @@ func (s *taskService) ReorderTasksInColumn(
 
 	// ✅ Update positions in database - CRITICAL FIX
 	for i, task := range finalOrder {
-		log.Printf("✏️ Updating %s to position %d", task.Title, i)
-		
 		if err := s.taskRepo.UpdatePosition(ctx, task.ID, i); err != nil {
-			log.Printf("❌ Failed to update position for task %s: %v", task.ID, err)
+			slog.ErrorContext(ctx, "failed to update task position", "taskID", task.ID, "error", err)
 			return err
 		}
-
-		// ✅ Update subtasks (they stay right after parent in visual order)
-		// But we don't need to update their DB position if they follow parent
-		// Just log for debugging
-		if subtasks := subtasksMap[task.ID]; len(subtasks) > 0 {
-			log.Printf("   └─ Task has %d subtasks", len(subtasks))
-		}
 	}
 
-	log.Printf("✅ Reordering complete")
-
 
 	// ✅ Broadcast position change (silent - no notifications)
 if s.broadcaster != nil {
-	log.Printf("📡 Broadcasting position update: project=%s, task=%s", projectID, movedTaskID)
-	
 	updatedTask, err := s.taskRepo.FindByID(ctx, movedTaskID)
-	if err != nil {
-		log.Printf("⚠️ Failed to fetch updated task: %v", err)
+	if err != nil || updatedTask == nil {
+		slog.WarnContext(ctx, "failed to load reordered task for broadcast", "taskID", movedTaskID, "error", err)
 	} else {
 		s.broadcaster.BroadcastTaskPositionChanged(
 			projectID,
 			s.taskToMap(updatedTask),
 			userID, // ✅ Exclude the user who moved it
 		)
-		log.Printf("✅ Broadcasted position update to all users in project")
 	}
 }
 
@@ -2511,13 +5854,12 @@ func (s *taskService) taskToMap(task *repository.Task) map[string]interface{} {
 }
 
 func (s *taskService) getTaskKey(task *repository.Task) string {
-	// If task has a Key field, use it
-	// Otherwise generate a simple key
-	if task.ID != "" {
-		return task.ID
+	// Tasks loaded from the database carry their "PROJ-123" key; partially
+	// built ones fall back to the ID
+	if task.Key != "" {
+		return task.Key
 	}
-	// Fallback: use task ID or generate project prefix + number
-	return task.ID[:8] // Use first 8 chars of ID
+	return task.ID
 }
 
 func contains(slice []string, item string) bool {
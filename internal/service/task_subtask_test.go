@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/repository"
+)
+
+// treeTaskRepo extends depTaskRepo with the parent links ConvertToSubtask
+// walks and writes
+type treeTaskRepo struct {
+	depTaskRepo
+}
+
+func (r *treeTaskRepo) FindByParentTaskID(_ context.Context, parentTaskID string) ([]*repository.Task, error) {
+	var children []*repository.Task
+	for _, t := range r.tasks {
+		if t.ParentTaskID != nil && *t.ParentTaskID == parentTaskID {
+			copied := *t
+			children = append(children, &copied)
+		}
+	}
+	return children, nil
+}
+
+func (r *treeTaskRepo) Update(_ context.Context, task *repository.Task) error {
+	copied := *task
+	r.tasks[task.ID] = &copied
+	return nil
+}
+
+// newChainFixture builds t0 <- t1 <- ... <- t(n-1), each task the parent of
+// the next, plus a free-standing task "loose"
+func newChainFixture(n, maxDepth int) (*taskService, *treeTaskRepo) {
+	tasks := &treeTaskRepo{depTaskRepo{tasks: map[string]*repository.Task{
+		"loose": {ID: "loose", ProjectID: "p1", Title: "Loose", Status: "todo"},
+	}}}
+	for i := 0; i < n; i++ {
+		task := &repository.Task{ID: fmt.Sprintf("t%d", i), ProjectID: "p1", Status: "todo"}
+		if i > 0 {
+			parent := fmt.Sprintf("t%d", i-1)
+			task.ParentTaskID = &parent
+		}
+		tasks.tasks[task.ID] = task
+	}
+	svc := &taskService{
+		taskRepo:        tasks,
+		activityRepo:    &depActivityRepo{},
+		permService:     allowEditPermissions{},
+		maxSubtaskDepth: maxDepth,
+	}
+	return svc, tasks
+}
+
+func TestConvertToSubtaskRejectsSelfParent(t *testing.T) {
+	svc, tasks := newChainFixture(1, 5)
+
+	err := svc.ConvertToSubtask(context.Background(), "t0", "t0", "user-1")
+	if !errors.Is(err, ErrInvalidInput) {
+		t.Fatalf("ConvertToSubtask(t0, t0) error = %v, want ErrInvalidInput", err)
+	}
+	if tasks.tasks["t0"].ParentTaskID != nil {
+		t.Fatal("task was made its own parent")
+	}
+}
+
+func TestConvertToSubtaskRejectsDescendantParent(t *testing.T) {
+	svc, tasks := newChainFixture(4, 0)
+	ctx := context.Background()
+
+	for _, descendant := range []string{"t1", "t3"} {
+		err := svc.ConvertToSubtask(ctx, "t0", descendant, "user-1")
+		if !errors.Is(err, ErrInvalidInput) {
+			t.Fatalf("ConvertToSubtask(t0, %s) error = %v, want ErrInvalidInput", descendant, err)
+		}
+	}
+	if tasks.tasks["t0"].ParentTaskID != nil {
+		t.Fatal("t0 ended up under one of its own subtasks")
+	}
+}
+
+func TestConvertToSubtaskEnforcesMaxDepth(t *testing.T) {
+	// t1..t4 sit at levels 1..4 below t0
+	svc, tasks := newChainFixture(5, 5)
+	ctx := context.Background()
+
+	// Under t4, loose would be level 5: the limit, still allowed
+	if err := svc.ConvertToSubtask(ctx, "loose", "t4", "user-1"); err != nil {
+		t.Fatalf("ConvertToSubtask(loose, t4): %v", err)
+	}
+	if p := tasks.tasks["loose"].ParentTaskID; p == nil || *p != "t4" {
+		t.Fatalf("loose parent = %v, want t4", p)
+	}
+
+	// A sixth level is one too many
+	tasks.tasks["extra"] = &repository.Task{ID: "extra", ProjectID: "p1", Status: "todo"}
+	err := svc.ConvertToSubtask(ctx, "extra", "loose", "user-1")
+	if !errors.Is(err, ErrInvalidInput) {
+		t.Fatalf("ConvertToSubtask(extra, loose) error = %v, want ErrInvalidInput", err)
+	}
+
+	// Moving a task counts the subtasks it brings along: side is level 3, so
+	// t3 under it would push t4 to level 5 and loose to level 6
+	tasks.tasks["side"] = &repository.Task{ID: "side", ProjectID: "p1", Status: "todo", ParentTaskID: strPtr("t2")}
+	err = svc.ConvertToSubtask(ctx, "t3", "side", "user-1")
+	if !errors.Is(err, ErrInvalidInput) {
+		t.Fatalf("ConvertToSubtask(t3, side) error = %v, want ErrInvalidInput", err)
+	}
+}
+
+func TestConvertToSubtaskDetectsExistingLoop(t *testing.T) {
+	svc, tasks := newChainFixture(3, 0)
+	// Corrupt data: t0 already points back at t2
+	tasks.tasks["t0"].ParentTaskID = strPtr("t2")
+
+	err := svc.ConvertToSubtask(context.Background(), "loose", "t1", "user-1")
+	if !errors.Is(err, ErrInvalidInput) {
+		t.Fatalf("ConvertToSubtask into a looped chain error = %v, want ErrInvalidInput", err)
+	}
+}
@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/repository"
+)
+
+type timerEntryRepo struct {
+	repository.TimeEntryRepository
+	entries map[string]*repository.TimeEntry
+}
+
+func (r *timerEntryRepo) FindByID(_ context.Context, id string) (*repository.TimeEntry, error) {
+	e, ok := r.entries[id]
+	if !ok {
+		return nil, nil
+	}
+	copied := *e
+	return &copied, nil
+}
+
+func (r *timerEntryRepo) FindActiveTimer(_ context.Context, userID string) (*repository.TimeEntry, error) {
+	for _, e := range r.entries {
+		if e.UserID == userID && e.EndTime == nil {
+			copied := *e
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *timerEntryRepo) StopTimer(_ context.Context, id string) error {
+	e := r.entries[id]
+	end := time.Now()
+	duration := int(end.Sub(e.StartTime).Seconds())
+	e.EndTime, e.DurationSeconds = &end, &duration
+	return nil
+}
+
+func (r *timerEntryRepo) Create(_ context.Context, e *repository.TimeEntry) error {
+	e.ID = fmt.Sprintf("entry-%d", len(r.entries)+1)
+	copied := *e
+	r.entries[e.ID] = &copied
+	return nil
+}
+
+func (r *timerEntryRepo) GetTotalTime(_ context.Context, taskID string) (int, error) {
+	total := 0
+	for _, e := range r.entries {
+		if e.TaskID == taskID && e.DurationSeconds != nil {
+			total += *e.DurationSeconds
+		}
+	}
+	return total, nil
+}
+
+func newTimerFixture() (*taskService, *timerEntryRepo) {
+	earlierEnd := time.Now().Add(-2 * time.Hour)
+	earlierDuration := 600
+	entries := &timerEntryRepo{entries: map[string]*repository.TimeEntry{
+		// An older, finished entry on the same task must not be returned
+		"entry-old": {ID: "entry-old", TaskID: "t1", UserID: "user-1", StartTime: earlierEnd.Add(-10 * time.Minute), EndTime: &earlierEnd, DurationSeconds: &earlierDuration},
+		"entry-now": {ID: "entry-now", TaskID: "t1", UserID: "user-1", StartTime: time.Now().Add(-90 * time.Second)},
+	}}
+	tasks := &depTaskRepo{tasks: map[string]*repository.Task{
+		"t1": {ID: "t1", ProjectID: "p1", Status: "in_progress", Title: "Timed"},
+	}}
+	svc := &taskService{
+		taskRepo:      &timerTaskRepo{depTaskRepo: tasks},
+		timeEntryRepo: entries,
+		activityRepo:  &depActivityRepo{},
+		permService:   allowEditPermissions{},
+	}
+	return svc, entries
+}
+
+// timerTaskRepo accepts the actual-hours update StopTimer makes
+type timerTaskRepo struct {
+	*depTaskRepo
+}
+
+func (r *timerTaskRepo) Update(_ context.Context, task *repository.Task) error {
+	copied := *task
+	r.tasks[task.ID] = &copied
+	return nil
+}
+
+func TestStopTimerReturnsStoppedEntry(t *testing.T) {
+	svc, _ := newTimerFixture()
+
+	entry, err := svc.StopTimer(context.Background(), "user-1")
+	if err != nil {
+		t.Fatalf("StopTimer: %v", err)
+	}
+	if entry.ID != "entry-now" {
+		t.Fatalf("returned entry %s, want the stopped entry-now", entry.ID)
+	}
+	if entry.EndTime == nil {
+		t.Fatal("stopped entry has no EndTime")
+	}
+	if entry.DurationSeconds == nil || *entry.DurationSeconds < 90 {
+		t.Fatalf("DurationSeconds = %v, want at least 90", entry.DurationSeconds)
+	}
+}
+
+func TestStopTimerWithoutActiveTimer(t *testing.T) {
+	svc, entries := newTimerFixture()
+	delete(entries.entries, "entry-now")
+
+	if _, err := svc.StopTimer(context.Background(), "user-1"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("StopTimer error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestStartTimerOnRunningTaskKeepsTimer(t *testing.T) {
+	svc, entries := newTimerFixture()
+	startedAt := entries.entries["entry-now"].StartTime
+
+	entry, err := svc.StartTimer(context.Background(), "t1", "user-1")
+	if err != nil {
+		t.Fatalf("StartTimer: %v", err)
+	}
+	if entry.ID != "entry-now" || !entry.StartTime.Equal(startedAt) {
+		t.Fatalf("got entry %s started %v, want the running entry-now started %v", entry.ID, entry.StartTime, startedAt)
+	}
+	if running := entries.entries["entry-now"]; running.EndTime != nil {
+		t.Fatal("the running timer was stopped")
+	}
+	if len(entries.entries) != 2 {
+		t.Fatalf("%d entries, want no new one", len(entries.entries))
+	}
+}
+
+func TestStartTimerOnOtherTaskStopsPrevious(t *testing.T) {
+	svc, entries := newTimerFixture()
+
+	entry, err := svc.StartTimer(context.Background(), "t2", "user-1")
+	if err != nil {
+		t.Fatalf("StartTimer: %v", err)
+	}
+	if entry.TaskID != "t2" || entry.ID == "entry-now" || entry.EndTime != nil {
+		t.Fatalf("got %+v, want a new running entry on t2", entry)
+	}
+
+	previous := entries.entries["entry-now"]
+	if previous.EndTime == nil || previous.DurationSeconds == nil {
+		t.Fatal("the timer on t1 was not stopped")
+	}
+	if *previous.DurationSeconds < 90 {
+		t.Errorf("saved duration = %ds, want the 90s already elapsed", *previous.DurationSeconds)
+	}
+	if active, _ := entries.FindActiveTimer(context.Background(), "user-1"); active == nil || active.ID != entry.ID {
+		t.Errorf("active timer = %+v, want %s", active, entry.ID)
+	}
+}
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/models"
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/repository"
+)
+
+func TestUpdateRejectsInvalidFields(t *testing.T) {
+	empty, blank, bogus := "", "   ", "not-a-status"
+
+	cases := []struct {
+		name string
+		req  models.UpdateTaskRequest
+	}{
+		{"empty title", models.UpdateTaskRequest{Title: &empty}},
+		{"blank title", models.UpdateTaskRequest{Title: &blank}},
+		{"empty status", models.UpdateTaskRequest{Status: &empty}},
+		{"bogus status", models.UpdateTaskRequest{Status: &bogus}},
+		{"empty priority", models.UpdateTaskRequest{Priority: &empty}},
+		{"bogus priority", models.UpdateTaskRequest{Priority: &bogus}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			svc, tasks := newVersionFixture()
+			version := 1
+			req := tc.req
+			req.Version = &version
+
+			_, err := svc.Update(context.Background(), "t1", "user-1", &req)
+			if !errors.Is(err, ErrInvalidInput) {
+				t.Fatalf("Update error = %v, want ErrInvalidInput", err)
+			}
+			if tasks.task.Title != "Original" || tasks.task.Status != "todo" || tasks.task.Priority != "medium" {
+				t.Errorf("rejected update was written: %+v", tasks.task)
+			}
+		})
+	}
+}
+
+func TestUpdateStatusChecksProjectWorkflow(t *testing.T) {
+	svc, _ := newVersionFixture()
+	svc.statusRepo = &depStatusRepo{statuses: []*repository.ProjectStatus{
+		{Key: "backlog"}, {Key: "doing"}, {Key: "shipped"},
+	}}
+	ctx := context.Background()
+
+	// "done" is a default status, but not a column of this project
+	if err := svc.UpdateStatus(ctx, "t1", "done", "user-1", nil); !errors.Is(err, ErrInvalidInput) {
+		t.Fatalf("UpdateStatus(done) error = %v, want ErrInvalidInput", err)
+	}
+	if err := svc.UpdateStatus(ctx, "t1", "", "user-1", nil); !errors.Is(err, ErrInvalidInput) {
+		t.Fatalf("UpdateStatus(\"\") error = %v, want ErrInvalidInput", err)
+	}
+}
+
+func TestUpdatePriorityRejectsUnknownPriority(t *testing.T) {
+	svc, tasks := newVersionFixture()
+
+	if err := svc.UpdatePriority(context.Background(), "t1", "whenever", "user-1", nil); !errors.Is(err, ErrInvalidInput) {
+		t.Fatalf("UpdatePriority error = %v, want ErrInvalidInput", err)
+	}
+	if tasks.task.Priority != "medium" {
+		t.Errorf("priority changed to %q", tasks.task.Priority)
+	}
+}
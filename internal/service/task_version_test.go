@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/models"
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/repository"
+)
+
+// versionedTaskRepo applies writes only while the expected version is still
+// current, as the SQL repository does in its UPDATE
+type versionedTaskRepo struct {
+	repository.TaskRepository
+	mu   sync.Mutex
+	task repository.Task
+}
+
+func (r *versionedTaskRepo) FindByID(_ context.Context, id string) (*repository.Task, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if id != r.task.ID {
+		return nil, nil
+	}
+	copied := r.task
+	return &copied, nil
+}
+
+func (r *versionedTaskRepo) Update(_ context.Context, task *repository.Task) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if task.Version != r.task.Version {
+		return repository.ErrVersionConflict
+	}
+	task.Version++
+	r.task = *task
+	return nil
+}
+
+func (r *versionedTaskRepo) UpdatePriority(_ context.Context, _ string, priority string, expectedVersion *int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if expectedVersion != nil && *expectedVersion != r.task.Version {
+		return repository.ErrVersionConflict
+	}
+	r.task.Priority = priority
+	r.task.Version++
+	return nil
+}
+
+type versionUserRepo struct {
+	repository.UserRepository
+}
+
+func (versionUserRepo) FindByID(_ context.Context, id string) (*repository.User, error) {
+	return &repository.User{ID: id, Name: "Tester"}, nil
+}
+
+func newVersionFixture() (*taskService, *versionedTaskRepo) {
+	tasks := &versionedTaskRepo{task: repository.Task{
+		ID: "t1", ProjectID: "p1", Title: "Original", Status: "todo", Priority: "medium", Version: 1,
+	}}
+	svc := &taskService{
+		taskRepo:     tasks,
+		userRepo:     versionUserRepo{},
+		activityRepo: &depActivityRepo{},
+		permService:  allowEditPermissions{},
+	}
+	return svc, tasks
+}
+
+func TestUpdateRejectsStaleVersion(t *testing.T) {
+	svc, tasks := newVersionFixture()
+	ctx := context.Background()
+
+	// Both editors read version 1
+	read := 1
+	first, second := "First edit", "Second edit"
+
+	updated, err := svc.Update(ctx, "t1", "user-1", &models.UpdateTaskRequest{Title: &first, Version: &read})
+	if err != nil {
+		t.Fatalf("first Update: %v", err)
+	}
+	if updated.Version != 2 {
+		t.Fatalf("version after first Update = %d, want 2", updated.Version)
+	}
+
+	_, err = svc.Update(ctx, "t1", "user-2", &models.UpdateTaskRequest{Title: &second, Version: &read})
+	if !errors.Is(err, ErrStaleVersion) {
+		t.Fatalf("second Update error = %v, want ErrStaleVersion", err)
+	}
+	if tasks.task.Title != first {
+		t.Fatalf("title = %q, the stale write clobbered %q", tasks.task.Title, first)
+	}
+}
+
+func TestUpdateRequiresVersion(t *testing.T) {
+	svc, _ := newVersionFixture()
+	title := "Edit"
+
+	_, err := svc.Update(context.Background(), "t1", "user-1", &models.UpdateTaskRequest{Title: &title})
+	if !errors.Is(err, ErrInvalidInput) {
+		t.Fatalf("Update without version error = %v, want ErrInvalidInput", err)
+	}
+}
+
+func TestConcurrentPriorityUpdatesOnlyOneWins(t *testing.T) {
+	svc, tasks := newVersionFixture()
+	ctx := context.Background()
+	priorities := []string{"low", "high", "urgent", "low", "high", "urgent", "low", "high"}
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(priorities))
+	for i, priority := range priorities {
+		wg.Add(1)
+		go func(i int, priority string) {
+			defer wg.Done()
+			read := 1
+			errs[i] = svc.UpdatePriority(ctx, "t1", priority, "user-1", &read)
+		}(i, priority)
+	}
+	wg.Wait()
+
+	wins := 0
+	for i, err := range errs {
+		switch {
+		case err == nil:
+			wins++
+		case !errors.Is(err, ErrStaleVersion):
+			t.Fatalf("UpdatePriority(%s): %v, want nil or ErrStaleVersion", priorities[i], err)
+		}
+	}
+	if wins != 1 {
+		t.Fatalf("%d concurrent updates succeeded, want exactly 1", wins)
+	}
+	if tasks.task.Version != 2 {
+		t.Fatalf("version = %d, want 2", tasks.task.Version)
+	}
+}
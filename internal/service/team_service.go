@@ -107,7 +107,7 @@ func (s *teamService) GetByID(ctx context.Context, id string) (*repository.Team,
 		return nil, err
 	}
 	if team == nil {
-		return nil, ErrNotFound
+		return nil, ErrTeamNotFound
 	}
 
 	// Load members
@@ -139,7 +139,7 @@ func (s *teamService) ListByUser(ctx context.Context, userID string) ([]*reposit
 func (s *teamService) Update(ctx context.Context, id, userID string, name, description, avatar, color *string) (*repository.Team, error) {
 	team, err := s.teamRepo.FindByID(ctx, id)
 	if err != nil || team == nil {
-		return nil, ErrNotFound
+		return nil, ErrTeamNotFound
 	}
 
 	// Check permission (owner or admin)
@@ -180,7 +180,7 @@ func (s *teamService) Update(ctx context.Context, id, userID string, name, descr
 func (s *teamService) Delete(ctx context.Context, id, userID string) error {
 	team, err := s.teamRepo.FindByID(ctx, id)
 	if err != nil || team == nil {
-		return ErrNotFound
+		return ErrTeamNotFound
 	}
 
 	// Check permission (only owner)
@@ -206,7 +206,7 @@ func (s *teamService) Delete(ctx context.Context, id, userID string) error {
 func (s *teamService) AddMember(ctx context.Context, teamID, userID, role, addedByID string) error {
 	team, err := s.teamRepo.FindByID(ctx, teamID)
 	if err != nil || team == nil {
-		return ErrNotFound
+		return ErrTeamNotFound
 	}
 
 	// Check if already a member
@@ -289,7 +289,7 @@ func (s *teamService) UpdateMemberRole(ctx context.Context, teamID, userID, role
 func (s *teamService) RemoveMember(ctx context.Context, teamID, userID string) error {
 	team, err := s.teamRepo.FindByID(ctx, teamID)
 	if err != nil || team == nil {
-		return ErrNotFound
+		return ErrTeamNotFound
 	}
 
 	if err := s.teamRepo.RemoveMember(ctx, teamID, userID); err != nil {
@@ -2,8 +2,14 @@ package service
 
 import (
 	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"time"
 
 	"github.com/Marga-Ghale/ora-scrum-backend/internal/repository"
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/socket"
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/storage"
 )
 
 // ============================================
@@ -16,14 +22,57 @@ type UserService interface {
 	Update(ctx context.Context, id string, name, avatar *string) (*repository.User, error)
 	UpdateLastActive(ctx context.Context, id string) error
 	Search(ctx context.Context, query string) ([]*repository.User, error)
+	// SetPresence persists a WebSocket-driven online/offline transition and
+	// broadcasts it to the user's workspaces
+	SetPresence(ctx context.Context, id string, online bool) error
+	// MarkAway sets online users inactive for longer than inactiveFor to away,
+	// skipping those with a live connection, and broadcasts each change. It
+	// returns the number of users changed.
+	MarkAway(ctx context.Context, inactiveFor time.Duration, connected []string) (int, error)
+	// MarkOffline does the same for users inactive for longer than
+	// inactiveFor, setting them offline
+	MarkOffline(ctx context.Context, inactiveFor time.Duration, connected []string) (int, error)
+	// UploadAvatar stores an image and makes it the user's avatar
+	UploadAvatar(ctx context.Context, id, filename string, file io.Reader, size int64) (*repository.User, error)
+	// Delete soft-deletes the account, removes its memberships and revokes
+	// its sessions and tokens. It fails with ErrLastOwner while the user is
+	// the only owner of a workspace.
+	Delete(ctx context.Context, id string) error
 }
 
 type userService struct {
-	userRepo repository.UserRepository
+	userRepo      repository.UserRepository
+	workspaceRepo repository.WorkspaceRepository
+	broadcaster   *socket.Broadcaster
+	store         storage.Storage
+	accessCache   *AccessCache
+	avatarMaxSize int64
 }
 
-func NewUserService(userRepo repository.UserRepository) UserService {
-	return &userService{userRepo: userRepo}
+func NewUserService(
+	userRepo repository.UserRepository,
+	workspaceRepo repository.WorkspaceRepository,
+	broadcaster *socket.Broadcaster,
+	store storage.Storage,
+	accessCache *AccessCache,
+	avatarMaxSize int64,
+) UserService {
+	return &userService{
+		userRepo:      userRepo,
+		workspaceRepo: workspaceRepo,
+		broadcaster:   broadcaster,
+		store:         store,
+		accessCache:   accessCache,
+		avatarMaxSize: avatarMaxSize,
+	}
+}
+
+// Image types accepted as avatars, detected from the file content
+var avatarContentTypes = map[string]bool{
+	"image/png":  true,
+	"image/jpeg": true,
+	"image/gif":  true,
+	"image/webp": true,
 }
 
 func (s *userService) GetByID(ctx context.Context, id string) (*repository.User, error) {
@@ -31,7 +80,7 @@ func (s *userService) GetByID(ctx context.Context, id string) (*repository.User,
 	if err != nil {
 		return nil, err
 	}
-	if user == nil {
+	if user == nil || user.DeletedAt != nil {
 		return nil, ErrUserNotFound
 	}
 	return user, nil
@@ -50,7 +99,7 @@ func (s *userService) GetByEmail(ctx context.Context, email string) (*repository
 
 func (s *userService) Update(ctx context.Context, id string, name, avatar *string) (*repository.User, error) {
 	user, err := s.userRepo.FindByID(ctx, id)
-	if err != nil || user == nil {
+	if err != nil || user == nil || user.DeletedAt != nil {
 		return nil, ErrUserNotFound
 	}
 
@@ -74,3 +123,161 @@ func (s *userService) UpdateLastActive(ctx context.Context, id string) error {
 func (s *userService) Search(ctx context.Context, query string) ([]*repository.User, error) {
 	return s.userRepo.Search(ctx, query)
 }
+
+func (s *userService) SetPresence(ctx context.Context, id string, online bool) error {
+	status := "offline"
+	var err error
+	if online {
+		status = "online"
+		err = s.userRepo.UpdateLastActive(ctx, id)
+	} else {
+		err = s.userRepo.UpdateStatus(ctx, id, status)
+	}
+	if err != nil {
+		return err
+	}
+
+	return s.broadcastPresence(ctx, id, status)
+}
+
+func (s *userService) MarkAway(ctx context.Context, inactiveFor time.Duration, connected []string) (int, error) {
+	ids, err := s.userRepo.UpdateStatusForInactive(ctx, inactiveFor, connected)
+	if err != nil {
+		return 0, err
+	}
+	s.broadcastPresenceAll(ctx, ids, "away")
+	return len(ids), nil
+}
+
+func (s *userService) MarkOffline(ctx context.Context, inactiveFor time.Duration, connected []string) (int, error) {
+	ids, err := s.userRepo.UpdateStatusForOffline(ctx, inactiveFor, connected)
+	if err != nil {
+		return 0, err
+	}
+	s.broadcastPresenceAll(ctx, ids, "offline")
+	return len(ids), nil
+}
+
+// broadcastPresenceAll broadcasts a status shared by several users; failures
+// are logged, as the status change itself has already been saved
+func (s *userService) broadcastPresenceAll(ctx context.Context, ids []string, status string) {
+	for _, id := range ids {
+		if err := s.broadcastPresence(ctx, id, status); err != nil {
+			slog.WarnContext(ctx, "failed to broadcast presence", "userID", id, "status", status, "error", err)
+		}
+	}
+}
+
+// broadcastPresence sends a user's new status to every workspace they belong to
+func (s *userService) broadcastPresence(ctx context.Context, id, status string) error {
+	if s.broadcaster == nil {
+		return nil
+	}
+
+	user, err := s.userRepo.FindByID(ctx, id)
+	if err != nil || user == nil {
+		return ErrUserNotFound
+	}
+	workspaces, err := s.workspaceRepo.FindByUserID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	workspaceIDs := make([]string, 0, len(workspaces))
+	for _, ws := range workspaces {
+		workspaceIDs = append(workspaceIDs, ws.ID)
+	}
+	s.broadcaster.BroadcastPresenceChanged(workspaceIDs, id, status, user.LastActiveAt)
+	return nil
+}
+
+func (s *userService) Delete(ctx context.Context, id string) error {
+	user, err := s.userRepo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if user == nil || user.DeletedAt != nil {
+		return ErrUserNotFound
+	}
+
+	// Refuse to leave a workspace without an owner
+	workspaces, err := s.workspaceRepo.FindByUserID(ctx, id)
+	if err != nil {
+		return err
+	}
+	for _, ws := range workspaces {
+		member, err := s.workspaceRepo.FindMember(ctx, ws.ID, id)
+		if err != nil {
+			return err
+		}
+		if member == nil || member.Role != "owner" {
+			continue
+		}
+		members, err := s.workspaceRepo.FindMembers(ctx, ws.ID)
+		if err != nil {
+			return err
+		}
+		owners := 0
+		for _, m := range members {
+			if m.Role == "owner" {
+				owners++
+			}
+		}
+		if owners <= 1 {
+			return fmt.Errorf("%w of workspace %q", ErrLastOwner, ws.Name)
+		}
+	}
+
+	if err := s.userRepo.SoftDelete(ctx, id); err != nil {
+		return err
+	}
+	s.accessCache.InvalidateUser(ctx, id)
+	slog.InfoContext(ctx, "user account deleted", "user_id", id)
+	return nil
+}
+
+func (s *userService) UploadAvatar(ctx context.Context, id, filename string, file io.Reader, size int64) (*repository.User, error) {
+	if s.store == nil {
+		return nil, fmt.Errorf("file storage is not configured")
+	}
+	if s.avatarMaxSize > 0 && size > s.avatarMaxSize {
+		return nil, fmt.Errorf("%w: avatar must be at most %d MB", ErrFileTooLarge, s.avatarMaxSize>>20)
+	}
+
+	contentType, content, err := storage.DetectContentType(file)
+	if err != nil {
+		return nil, err
+	}
+	if !avatarContentTypes[contentType] {
+		return nil, fmt.Errorf("%w: avatar must be a PNG, JPEG, GIF or WebP image", ErrInvalidInput)
+	}
+
+	user, err := s.userRepo.FindByID(ctx, id)
+	if err != nil || user == nil {
+		return nil, ErrUserNotFound
+	}
+
+	key := storage.NewKey("avatars/"+id, filename)
+	url, err := s.store.Save(ctx, key, content, size, contentType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to store avatar: %w", err)
+	}
+
+	previous := user.Avatar
+	user.Avatar = &url
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		s.store.Delete(ctx, key)
+		return nil, err
+	}
+
+	// Only remove the old avatar if we stored it; external URLs are left alone
+	if previous != nil {
+		if oldKey, ok := storage.KeyFromURL(s.store, *previous); ok {
+			if err := s.store.Delete(ctx, oldKey); err != nil {
+				slog.WarnContext(ctx, "failed to delete old avatar", "key", oldKey, "error", err)
+			}
+		}
+	}
+
+	return user, nil
+}
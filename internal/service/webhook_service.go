@@ -0,0 +1,609 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/models"
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/repository"
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/socket"
+	"github.com/google/uuid"
+)
+
+// ============================================
+// Webhook Service
+// ============================================
+
+// Webhook event types
+const (
+	WebhookEventTaskCreated       = "task.created"
+	WebhookEventTaskStatusChanged = "task.status_changed"
+	WebhookEventSprintCompleted   = "sprint.completed"
+	// WebhookEventTest is only sent by the test delivery endpoint
+	WebhookEventTest = "webhook.test"
+)
+
+// WebhookEvents lists the event types a webhook can subscribe to
+var WebhookEvents = []string{
+	WebhookEventTaskCreated,
+	WebhookEventTaskStatusChanged,
+	WebhookEventSprintCompleted,
+}
+
+const (
+	DefaultWebhookWorkers     = 4
+	DefaultWebhookMaxAttempts = 5
+
+	webhookQueueSize       = 1000
+	webhookTimeout         = 10 * time.Second
+	webhookRetryBaseDelay  = 2 * time.Second
+	webhookDeadLetterLimit = 100
+)
+
+// WebhookService manages a workspace's webhooks and delivers events to them.
+// Deliveries are POSTed as JSON signed with HMAC-SHA256 of the body using the
+// webhook's secret, sent as "X-Ora-Signature: sha256=<hex>". Failed deliveries
+// are retried with exponential backoff; the last failure goes to the
+// dead-letter log. Webhooks may only reach public addresses.
+type WebhookService interface {
+	List(ctx context.Context, workspaceID, userID string) ([]*repository.Webhook, error)
+	Get(ctx context.Context, workspaceID, webhookID, userID string) (*repository.Webhook, error)
+	Create(ctx context.Context, workspaceID, userID string, req *models.CreateWebhookRequest) (*repository.Webhook, error)
+	Update(ctx context.Context, workspaceID, webhookID, userID string, req *models.UpdateWebhookRequest) (*repository.Webhook, error)
+	Delete(ctx context.Context, workspaceID, webhookID, userID string) error
+	// TestDelivery sends a webhook.test event once, synchronously, and reports the outcome
+	TestDelivery(ctx context.Context, workspaceID, webhookID, userID string) (*WebhookDeliveryResult, error)
+	ListDeadLetters(ctx context.Context, workspaceID, webhookID, userID string) ([]*repository.WebhookDeadLetter, error)
+
+	// HandleEvent is a socket.EventListener: it queues the event for the
+	// subscribed webhooks of the project's workspace without blocking
+	HandleEvent(projectID string, msgType socket.MessageType, payload map[string]interface{})
+}
+
+// WebhookDeliveryResult is the outcome of one delivery attempt
+type WebhookDeliveryResult struct {
+	Delivered  bool   `json:"delivered"`
+	StatusCode int    `json:"statusCode,omitempty"`
+	Error      string `json:"error,omitempty"`
+	DurationMs int64  `json:"durationMs"`
+}
+
+// webhookPayload is the JSON body of every delivery
+type webhookPayload struct {
+	ID          string                 `json:"id"`
+	Event       string                 `json:"event"`
+	WorkspaceID string                 `json:"workspaceId"`
+	ProjectID   string                 `json:"projectId,omitempty"`
+	OccurredAt  time.Time              `json:"occurredAt"`
+	Data        map[string]interface{} `json:"data"`
+}
+
+type webhookEvent struct {
+	projectID  string
+	event      string
+	data       map[string]interface{}
+	occurredAt time.Time
+}
+
+// webhookDelivery is one pending attempt to deliver a body to a webhook
+type webhookDelivery struct {
+	webhook *repository.Webhook
+	event   string
+	body    []byte
+	attempt int
+}
+
+type webhookService struct {
+	webhookRepo   repository.WebhookRepository
+	projectRepo   repository.ProjectRepository
+	spaceRepo     repository.SpaceRepository
+	memberService MemberService
+	client        *http.Client
+	maxAttempts   int
+	queue         chan *webhookEvent
+	retries       chan *webhookDelivery
+}
+
+// NewWebhookService creates the service and starts its delivery workers
+func NewWebhookService(
+	webhookRepo repository.WebhookRepository,
+	projectRepo repository.ProjectRepository,
+	spaceRepo repository.SpaceRepository,
+	memberService MemberService,
+	workers int,
+	maxAttempts int,
+) WebhookService {
+	if workers <= 0 {
+		workers = DefaultWebhookWorkers
+	}
+	if maxAttempts <= 0 {
+		maxAttempts = DefaultWebhookMaxAttempts
+	}
+
+	s := &webhookService{
+		webhookRepo:   webhookRepo,
+		projectRepo:   projectRepo,
+		spaceRepo:     spaceRepo,
+		memberService: memberService,
+		client:        newWebhookClient(),
+		maxAttempts:   maxAttempts,
+		queue:         make(chan *webhookEvent, webhookQueueSize),
+		retries:       make(chan *webhookDelivery, webhookQueueSize),
+	}
+	for i := 0; i < workers; i++ {
+		go s.worker()
+	}
+	return s
+}
+
+// ============================================
+// Management
+// ============================================
+
+func (s *webhookService) List(ctx context.Context, workspaceID, userID string) ([]*repository.Webhook, error) {
+	if err := s.requireAdmin(ctx, workspaceID, userID); err != nil {
+		return nil, err
+	}
+	webhooks, err := s.webhookRepo.FindByWorkspaceID(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	if webhooks == nil {
+		webhooks = []*repository.Webhook{}
+	}
+	return webhooks, nil
+}
+
+func (s *webhookService) Get(ctx context.Context, workspaceID, webhookID, userID string) (*repository.Webhook, error) {
+	if err := s.requireAdmin(ctx, workspaceID, userID); err != nil {
+		return nil, err
+	}
+	return s.find(ctx, workspaceID, webhookID)
+}
+
+func (s *webhookService) Create(ctx context.Context, workspaceID, userID string, req *models.CreateWebhookRequest) (*repository.Webhook, error) {
+	if err := s.requireAdmin(ctx, workspaceID, userID); err != nil {
+		return nil, err
+	}
+	if err := validateWebhookURL(req.URL); err != nil {
+		return nil, err
+	}
+	events, err := validateWebhookEvents(req.Events)
+	if err != nil {
+		return nil, err
+	}
+
+	secret := req.Secret
+	if secret == "" {
+		if secret, err = generateWebhookSecret(); err != nil {
+			return nil, err
+		}
+	}
+	active := true
+	if req.Active != nil {
+		active = *req.Active
+	}
+
+	webhook := &repository.Webhook{
+		WorkspaceID: workspaceID,
+		URL:         req.URL,
+		Secret:      secret,
+		Events:      events,
+		Active:      active,
+		CreatedBy:   &userID,
+	}
+	if err := s.webhookRepo.Create(ctx, webhook); err != nil {
+		return nil, err
+	}
+	return webhook, nil
+}
+
+func (s *webhookService) Update(ctx context.Context, workspaceID, webhookID, userID string, req *models.UpdateWebhookRequest) (*repository.Webhook, error) {
+	if err := s.requireAdmin(ctx, workspaceID, userID); err != nil {
+		return nil, err
+	}
+	webhook, err := s.find(ctx, workspaceID, webhookID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.URL != nil {
+		if err := validateWebhookURL(*req.URL); err != nil {
+			return nil, err
+		}
+		webhook.URL = *req.URL
+	}
+	if req.Events != nil {
+		if webhook.Events, err = validateWebhookEvents(*req.Events); err != nil {
+			return nil, err
+		}
+	}
+	if req.Secret != nil {
+		if *req.Secret == "" {
+			return nil, fmt.Errorf("%w: secret cannot be empty", ErrInvalidInput)
+		}
+		webhook.Secret = *req.Secret
+	}
+	if req.Active != nil {
+		webhook.Active = *req.Active
+	}
+
+	if err := s.webhookRepo.Update(ctx, webhook); err != nil {
+		return nil, err
+	}
+	return webhook, nil
+}
+
+func (s *webhookService) Delete(ctx context.Context, workspaceID, webhookID, userID string) error {
+	if err := s.requireAdmin(ctx, workspaceID, userID); err != nil {
+		return err
+	}
+	if _, err := s.find(ctx, workspaceID, webhookID); err != nil {
+		return err
+	}
+	return s.webhookRepo.Delete(ctx, webhookID)
+}
+
+func (s *webhookService) TestDelivery(ctx context.Context, workspaceID, webhookID, userID string) (*WebhookDeliveryResult, error) {
+	if err := s.requireAdmin(ctx, workspaceID, userID); err != nil {
+		return nil, err
+	}
+	webhook, err := s.find(ctx, workspaceID, webhookID)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := json.Marshal(&webhookPayload{
+		ID:          uuid.New().String(),
+		Event:       WebhookEventTest,
+		WorkspaceID: workspaceID,
+		OccurredAt:  time.Now().UTC(),
+		Data:        map[string]interface{}{"webhookId": webhook.ID, "triggeredBy": userID},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.send(ctx, webhook, WebhookEventTest, body), nil
+}
+
+func (s *webhookService) ListDeadLetters(ctx context.Context, workspaceID, webhookID, userID string) ([]*repository.WebhookDeadLetter, error) {
+	if err := s.requireAdmin(ctx, workspaceID, userID); err != nil {
+		return nil, err
+	}
+	if _, err := s.find(ctx, workspaceID, webhookID); err != nil {
+		return nil, err
+	}
+	letters, err := s.webhookRepo.FindDeadLetters(ctx, webhookID, webhookDeadLetterLimit)
+	if err != nil {
+		return nil, err
+	}
+	if letters == nil {
+		letters = []*repository.WebhookDeadLetter{}
+	}
+	return letters, nil
+}
+
+// requireAdmin allows workspace admins and owners
+func (s *webhookService) requireAdmin(ctx context.Context, workspaceID, userID string) error {
+	hasAccess, role, err := s.memberService.HasEffectiveAccess(ctx, EntityTypeWorkspace, workspaceID, userID)
+	if err != nil || !hasAccess || !hasMinimumRole(normalizeRole(role), PermissionAdmin) {
+		return ErrUnauthorized
+	}
+	return nil
+}
+
+// find loads a webhook of the workspace
+func (s *webhookService) find(ctx context.Context, workspaceID, webhookID string) (*repository.Webhook, error) {
+	webhook, err := s.webhookRepo.FindByID(ctx, webhookID)
+	if err != nil {
+		return nil, err
+	}
+	if webhook == nil || webhook.WorkspaceID != workspaceID {
+		return nil, ErrNotFound
+	}
+	return webhook, nil
+}
+
+// validateWebhookURL rejects URLs that aren't http(s) or name a non-public
+// host outright. Hostnames are checked again on every dial, after resolution.
+func validateWebhookURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%w: url must be an absolute http or https URL", ErrInvalidInput)
+	}
+	host := strings.ToLower(u.Hostname())
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return fmt.Errorf("%w: url must point to a public address", ErrInvalidInput)
+	}
+	if addr, err := netip.ParseAddr(host); err == nil && !isPublicAddr(addr) {
+		return fmt.Errorf("%w: url must point to a public address", ErrInvalidInput)
+	}
+	return nil
+}
+
+// validateWebhookEvents checks and de-duplicates the subscribed events
+func validateWebhookEvents(events []string) ([]string, error) {
+	if len(events) == 0 {
+		return nil, fmt.Errorf("%w: subscribe to at least one event", ErrInvalidInput)
+	}
+	seen := make(map[string]bool, len(events))
+	result := make([]string, 0, len(events))
+	for _, event := range events {
+		if !contains(WebhookEvents, event) {
+			return nil, fmt.Errorf("%w: unknown webhook event %q", ErrInvalidInput, event)
+		}
+		if !seen[event] {
+			seen[event] = true
+			result = append(result, event)
+		}
+	}
+	return result, nil
+}
+
+func generateWebhookSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// ============================================
+// Delivery
+// ============================================
+
+func (s *webhookService) HandleEvent(projectID string, msgType socket.MessageType, payload map[string]interface{}) {
+	event, data := webhookEventFor(msgType, payload)
+	if event == "" {
+		return
+	}
+
+	select {
+	case s.queue <- &webhookEvent{projectID: projectID, event: event, data: data, occurredAt: time.Now().UTC()}:
+	default:
+		slog.Warn("webhook queue full, dropping event", "event", event, "projectID", projectID)
+	}
+}
+
+// webhookEventFor maps a broadcast to its webhook event, or "" if webhooks
+// do not carry it. Task edits that change the status count as status changes.
+func webhookEventFor(msgType socket.MessageType, payload map[string]interface{}) (string, map[string]interface{}) {
+	switch msgType {
+	case socket.MessageTaskCreated:
+		return WebhookEventTaskCreated, payload
+	case socket.MessageTaskStatusChanged:
+		return WebhookEventTaskStatusChanged, payload
+	case socket.MessageTaskUpdated:
+		fields, _ := payload["changedFields"].([]string)
+		if !contains(fields, "status") {
+			return "", nil
+		}
+		data := make(map[string]interface{}, len(payload)+1)
+		for k, v := range payload {
+			data[k] = v
+		}
+		if task, ok := payload["task"].(map[string]interface{}); ok {
+			data["newStatus"] = task["status"]
+		}
+		return WebhookEventTaskStatusChanged, data
+	case socket.MessageSprintCompleted:
+		return WebhookEventSprintCompleted, payload
+	}
+	return "", nil
+}
+
+func (s *webhookService) worker() {
+	for {
+		select {
+		case event := <-s.queue:
+			s.dispatch(event)
+		case delivery := <-s.retries:
+			s.retry(delivery)
+		}
+	}
+}
+
+// dispatch delivers an event to every subscribed webhook of the project's workspace
+func (s *webhookService) dispatch(event *webhookEvent) {
+	ctx := context.Background()
+
+	workspaceID := s.projectWorkspaceID(ctx, event.projectID)
+	if workspaceID == "" {
+		return
+	}
+	webhooks, err := s.webhookRepo.FindSubscribed(ctx, workspaceID, event.event)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to load webhooks", "workspaceID", workspaceID, "error", err)
+		return
+	}
+
+	for _, webhook := range webhooks {
+		body, err := json.Marshal(&webhookPayload{
+			ID:          uuid.New().String(),
+			Event:       event.event,
+			WorkspaceID: workspaceID,
+			ProjectID:   event.projectID,
+			OccurredAt:  event.occurredAt,
+			Data:        event.data,
+		})
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to encode webhook payload", "event", event.event, "error", err)
+			return
+		}
+		s.deliver(ctx, &webhookDelivery{webhook: webhook, event: event.event, body: body, attempt: 1})
+	}
+}
+
+// deliver makes one attempt. A failure is retried later with exponential
+// backoff, so the worker never waits; the final failure goes to the
+// dead-letter log.
+func (s *webhookService) deliver(ctx context.Context, delivery *webhookDelivery) {
+	result := s.send(ctx, delivery.webhook, delivery.event, delivery.body)
+	if result.Delivered {
+		return
+	}
+	if delivery.attempt < s.maxAttempts {
+		delay := webhookRetryBaseDelay << (delivery.attempt - 1)
+		delivery.attempt++
+		time.AfterFunc(delay, func() { s.retries <- delivery })
+		return
+	}
+
+	webhook := delivery.webhook
+	slog.WarnContext(ctx, "webhook delivery gave up", "webhookID", webhook.ID, "event", delivery.event, "attempts", delivery.attempt, "error", result.Error)
+	letter := &repository.WebhookDeadLetter{
+		WebhookID: webhook.ID,
+		Event:     delivery.event,
+		Payload:   delivery.body,
+		Attempts:  delivery.attempt,
+		LastError: result.Error,
+	}
+	if result.StatusCode != 0 {
+		letter.StatusCode = &result.StatusCode
+	}
+	if err := s.webhookRepo.CreateDeadLetter(ctx, letter); err != nil {
+		slog.ErrorContext(ctx, "failed to record webhook dead letter", "webhookID", webhook.ID, "error", err)
+	}
+}
+
+// retry re-sends a failed delivery to the webhook as it is now, unless it has
+// since been deleted or deactivated
+func (s *webhookService) retry(delivery *webhookDelivery) {
+	ctx := context.Background()
+	webhook, err := s.webhookRepo.FindByID(ctx, delivery.webhook.ID)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to reload webhook for retry", "webhookID", delivery.webhook.ID, "error", err)
+		return
+	}
+	if webhook == nil || !webhook.Active {
+		return
+	}
+	delivery.webhook = webhook
+	s.deliver(ctx, delivery)
+}
+
+// send makes one signed POST. Any 2xx response counts as delivered.
+func (s *webhookService) send(ctx context.Context, webhook *repository.Webhook, event string, body []byte) *WebhookDeliveryResult {
+	start := time.Now()
+	result := &WebhookDeliveryResult{}
+	defer func() { result.DurationMs = time.Since(start).Milliseconds() }()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "ORA-Scrum-Webhooks/1.0")
+	req.Header.Set("X-Ora-Event", event)
+	req.Header.Set("X-Ora-Signature", "sha256="+signWebhookBody(webhook.Secret, body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	result.StatusCode = resp.StatusCode
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		result.Error = "unexpected response status " + resp.Status
+		return result
+	}
+	result.Delivered = true
+	return result
+}
+
+// errWebhookAddressBlocked is returned when a webhook host resolves to a
+// loopback, private or otherwise non-public address
+var errWebhookAddressBlocked = errors.New("webhook address is not public")
+
+// newWebhookClient returns a client whose every connection, including those
+// made for redirects, is refused unless the resolved address is public. The
+// check runs on the dialed IP, so DNS rebinding can't get past it, and no
+// proxy is used so the dialed IP is the real destination.
+func newWebhookClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout: webhookTimeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil || !isPublicAddr(addrPort.Addr()) {
+				return errWebhookAddressBlocked
+			}
+			return nil
+		},
+	}
+	return &http.Client{
+		Timeout: webhookTimeout,
+		Transport: &http.Transport{
+			Proxy:                 nil,
+			DialContext:           dialer.DialContext,
+			TLSHandshakeTimeout:   webhookTimeout,
+			ResponseHeaderTimeout: webhookTimeout,
+			MaxIdleConns:          10,
+			IdleConnTimeout:       90 * time.Second,
+		},
+	}
+}
+
+// nonPublicPrefixes are ranges that IsPrivate and friends don't cover but
+// that still aren't reachable on the public internet
+var nonPublicPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+}
+
+// isPublicAddr reports whether a webhook may connect to addr
+func isPublicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsValid() || addr.IsLoopback() || addr.IsPrivate() || addr.IsUnspecified() ||
+		addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() ||
+		addr.IsInterfaceLocalMulticast() || addr.IsMulticast() {
+		return false
+	}
+	for _, prefix := range nonPublicPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+func signWebhookBody(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (s *webhookService) projectWorkspaceID(ctx context.Context, projectID string) string {
+	project, err := s.projectRepo.FindByID(ctx, projectID)
+	if err != nil || project == nil {
+		return ""
+	}
+	space, err := s.spaceRepo.FindByID(ctx, project.SpaceID)
+	if err != nil || space == nil {
+		return ""
+	}
+	return space.WorkspaceID
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/repository"
+)
+
+func TestIsPublicAddr(t *testing.T) {
+	tests := []struct {
+		addr   string
+		public bool
+	}{
+		{"8.8.8.8", true},
+		{"2606:4700:4700::1111", true},
+		{"127.0.0.1", false},
+		{"::1", false},
+		{"10.1.2.3", false},
+		{"172.16.0.1", false},
+		{"192.168.1.1", false},
+		{"169.254.169.254", false},
+		{"100.64.0.1", false},
+		{"0.0.0.0", false},
+		{"fe80::1", false},
+		{"fd00::1", false},
+		{"::ffff:127.0.0.1", false},
+	}
+	for _, tt := range tests {
+		if got := isPublicAddr(netip.MustParseAddr(tt.addr)); got != tt.public {
+			t.Errorf("isPublicAddr(%s) = %v, want %v", tt.addr, got, tt.public)
+		}
+	}
+}
+
+func TestValidateWebhookURL(t *testing.T) {
+	valid := []string{
+		"https://hooks.example.com/ora",
+		"http://203.0.113.10:8080/in",
+	}
+	for _, raw := range valid {
+		if err := validateWebhookURL(raw); err != nil {
+			t.Errorf("validateWebhookURL(%q) = %v, want nil", raw, err)
+		}
+	}
+
+	invalid := []string{
+		"ftp://example.com/",
+		"/relative",
+		"http://localhost:8080/",
+		"http://api.localhost/",
+		"http://127.0.0.1/",
+		"http://[::1]/",
+		"http://169.254.169.254/latest/meta-data",
+		"http://10.0.0.5/",
+	}
+	for _, raw := range invalid {
+		if err := validateWebhookURL(raw); !errors.Is(err, ErrInvalidInput) {
+			t.Errorf("validateWebhookURL(%q) = %v, want ErrInvalidInput", raw, err)
+		}
+	}
+}
+
+func TestWebhookClientRefusesLoopback(t *testing.T) {
+	called := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer server.Close()
+
+	s := &webhookService{client: newWebhookClient()}
+	result := s.send(context.Background(), &repository.Webhook{URL: server.URL, Secret: "s"}, WebhookEventTest, []byte("{}"))
+
+	if result.Delivered || called {
+		t.Fatalf("delivery to %s was not blocked: %+v", server.URL, result)
+	}
+}
@@ -17,10 +17,13 @@ type WorkspaceService interface {
 	AddMember(ctx context.Context, workspaceID, email, role, inviterID string) error
 	AddMemberByID(ctx context.Context, workspaceID, userID, role, inviterID string) error
 	ListMembers(ctx context.Context, workspaceID string) ([]*repository.WorkspaceMember, error)
-	UpdateMemberRole(ctx context.Context, workspaceID, userID, role string) error
+	// UpdateMemberRole applies the same role hierarchy as MemberService.UpdateMemberRole
+	UpdateMemberRole(ctx context.Context, workspaceID, userID, role, actorID string) error
 	RemoveMember(ctx context.Context, workspaceID, userID string) error
 	IsMember(ctx context.Context, workspaceID, userID string) (bool, error)
 	HasAccess(ctx context.Context, workspaceID, userID string) (bool, error)
+	// GetPresence lists the status of every member; the requester is always included
+	GetPresence(ctx context.Context, workspaceID, userID string) ([]*repository.User, error)
 }
 
 type workspaceService struct {
@@ -101,7 +104,7 @@ func (s *workspaceService) GetByID(ctx context.Context, id string) (*repository.
 		return nil, err
 	}
 	if workspace == nil {
-		return nil, ErrNotFound
+		return nil, ErrWorkspaceNotFound
 	}
 	return workspace, nil
 }
@@ -113,7 +116,7 @@ func (s *workspaceService) List(ctx context.Context, userID string) ([]*reposito
 func (s *workspaceService) Update(ctx context.Context, id string, name, description, icon, color, visibility *string, allowedUsers, allowedTeams *[]string) (*repository.Workspace, error) {
 	workspace, err := s.workspaceRepo.FindByID(ctx, id)
 	if err != nil || workspace == nil {
-		return nil, ErrNotFound
+		return nil, ErrWorkspaceNotFound
 	}
 
 	if name != nil {
@@ -254,7 +257,34 @@ func (s *workspaceService) ListMembers(ctx context.Context, workspaceID string)
 	return s.workspaceRepo.FindMembers(ctx, workspaceID)
 }
 
-func (s *workspaceService) UpdateMemberRole(ctx context.Context, workspaceID, userID, role string) error {
+func (s *workspaceService) UpdateMemberRole(ctx context.Context, workspaceID, userID, role, actorID string) error {
+	actor, err := s.workspaceRepo.FindMember(ctx, workspaceID, actorID)
+	if err != nil || actor == nil {
+		return ErrUnauthorized
+	}
+	target, err := s.workspaceRepo.FindMember(ctx, workspaceID, userID)
+	if err != nil || target == nil {
+		return ErrUserNotFound
+	}
+	if err := checkRoleChange(actor.Role, target.Role, role); err != nil {
+		return err
+	}
+	if target.Role == "owner" && role != "owner" {
+		members, err := s.workspaceRepo.FindMembers(ctx, workspaceID)
+		if err != nil {
+			return err
+		}
+		owners := 0
+		for _, m := range members {
+			if m.Role == "owner" {
+				owners++
+			}
+		}
+		if owners <= 1 {
+			return ErrLastOwner
+		}
+	}
+
 	if err := s.workspaceRepo.UpdateMemberRole(ctx, workspaceID, userID, role); err != nil {
 		return err
 	}
@@ -298,4 +328,39 @@ func (s *workspaceService) IsMember(ctx context.Context, workspaceID, userID str
 
 func (s *workspaceService) HasAccess(ctx context.Context, workspaceID, userID string) (bool, error) {
 	return s.workspaceRepo.HasAccess(ctx, workspaceID, userID)
-}
\ No newline at end of file
+}
+func (s *workspaceService) GetPresence(ctx context.Context, workspaceID, userID string) ([]*repository.User, error) {
+	hasAccess, err := s.workspaceRepo.HasAccess(ctx, workspaceID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !hasAccess {
+		return nil, ErrUnauthorized
+	}
+
+	members, err := s.workspaceRepo.FindMembers(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	users := make([]*repository.User, 0, len(members)+1)
+	includesRequester := false
+	for _, m := range members {
+		if m.User == nil {
+			continue
+		}
+		if m.UserID == userID {
+			includesRequester = true
+		}
+		users = append(users, m.User)
+	}
+
+	// Access may come from ownership or visibility rather than a member row
+	if !includesRequester {
+		if requester, err := s.userRepo.FindByID(ctx, userID); err == nil && requester != nil {
+			users = append(users, requester)
+		}
+	}
+
+	return users, nil
+}
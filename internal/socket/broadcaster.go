@@ -3,18 +3,37 @@ package socket
 import (
 	"fmt"
 	"log"
+	"time"
 )
 
 // Broadcaster provides high-level methods for broadcasting events
 type Broadcaster struct {
-	hub *Hub
+	hub       *Hub
+	listeners []EventListener
 }
 
+// EventListener receives task and sprint events of a project as they are
+// broadcast, e.g. to forward them to webhooks. It is called on the
+// broadcasting goroutine and must not block.
+type EventListener func(projectID string, msgType MessageType, payload map[string]interface{})
+
 // NewBroadcaster creates a new Broadcaster
 func NewBroadcaster(hub *Hub) *Broadcaster {
 	return &Broadcaster{hub: hub}
 }
 
+// AddListener registers a listener. Register listeners at startup, before
+// anything is broadcast.
+func (b *Broadcaster) AddListener(listener EventListener) {
+	b.listeners = append(b.listeners, listener)
+}
+
+func (b *Broadcaster) notifyListeners(projectID string, msgType MessageType, payload map[string]interface{}) {
+	for _, listener := range b.listeners {
+		listener(projectID, msgType, payload)
+	}
+}
+
 // ============================================
 // Notification Broadcasting
 // ============================================
@@ -32,6 +51,22 @@ func (b *Broadcaster) SendNotificationCount(userID string, total, unread int) {
 	})
 }
 
+// SendNotificationUnread pushes the user's new unread notification total
+func (b *Broadcaster) SendNotificationUnread(userID string, unread int) {
+	b.hub.SendToUser(userID, MessageNotificationUnread, map[string]interface{}{
+		"unread": unread,
+	})
+}
+
+// SendChatUnread pushes a channel's unread count and the user's total across channels
+func (b *Broadcaster) SendChatUnread(userID, channelID string, channelUnread, totalUnread int) {
+	b.hub.SendToUser(userID, MessageChatUnread, map[string]interface{}{
+		"channelId":     channelID,
+		"channelUnread": channelUnread,
+		"totalUnread":   totalUnread,
+	})
+}
+
 // ============================================
 // Task Broadcasting
 // ============================================
@@ -39,7 +74,17 @@ func (b *Broadcaster) SendNotificationCount(userID string, total, unread int) {
 // BroadcastTaskCreated broadcasts task creation to project members
 func (b *Broadcaster) BroadcastTaskCreated(projectID string, task map[string]interface{}, excludeUserID string) {
 	room := fmt.Sprintf("project:%s", projectID)
-	b.hub.SendToRoom(room, MessageTaskCreated, task, excludeUserID)
+	// The payload stays the task itself, as clients already read it; the
+	// fields shared with the other task events are only added alongside
+	payload := make(map[string]interface{}, len(task)+3)
+	for k, v := range task {
+		payload[k] = v
+	}
+	payload["taskId"] = task["id"]
+	payload["projectId"] = projectID
+	payload["createdByUser"] = excludeUserID
+	b.hub.SendToRoom(room, MessageTaskCreated, payload, excludeUserID)
+	b.notifyListeners(projectID, MessageTaskCreated, payload)
 }
 
 // BroadcastTaskUpdated broadcasts task updates to project members
@@ -53,6 +98,7 @@ func (b *Broadcaster) BroadcastTaskUpdated(
 
 	payload := map[string]interface{}{
 		"task":          task,
+		"taskId":        task["id"],
 		"changedFields": changes,
 		"changedByUser": excludeUserID,
 		"projectId":     projectID,
@@ -62,6 +108,7 @@ func (b *Broadcaster) BroadcastTaskUpdated(
 		room, task["id"], excludeUserID)
 
 	b.hub.SendToRoom(room, MessageTaskUpdated, payload, excludeUserID)
+	b.notifyListeners(projectID, MessageTaskUpdated, payload)
 }
 
 // BroadcastTaskDeleted broadcasts task deletion to project members
@@ -76,12 +123,17 @@ func (b *Broadcaster) BroadcastTaskDeleted(projectID, taskID, taskKey string, ex
 // BroadcastTaskStatusChanged broadcasts task status change to project members
 func (b *Broadcaster) BroadcastTaskStatusChanged(projectID string, task map[string]interface{}, oldStatus, newStatus string, excludeUserID string) {
 	room := fmt.Sprintf("project:%s", projectID)
-	b.hub.SendToRoom(room, MessageTaskStatusChanged, map[string]interface{}{
+	payload := map[string]interface{}{
 		"task":          task,
+		"taskId":        task["id"],
+		"projectId":     projectID,
 		"oldStatus":     oldStatus,
 		"newStatus":     newStatus,
+		"changedFields": []string{"status"},
 		"changedByUser": excludeUserID,
-	}, excludeUserID)
+	}
+	b.hub.SendToRoom(room, MessageTaskStatusChanged, payload, excludeUserID)
+	b.notifyListeners(projectID, MessageTaskStatusChanged, payload)
 }
 
 // BroadcastTaskPositionChanged broadcasts task position/status change WITHOUT notifications
@@ -89,8 +141,11 @@ func (b *Broadcaster) BroadcastTaskPositionChanged(projectID string, task map[st
 	room := fmt.Sprintf("project:%s", projectID)
 
 	payload := map[string]interface{}{
-		"task":      task,
-		"projectId": projectID,
+		"task":          task,
+		"taskId":        task["id"],
+		"projectId":     projectID,
+		"changedFields": []string{"position", "status"},
+		"changedByUser": excludeUserID,
 	}
 
 	log.Printf("📡 BroadcastTaskPositionChanged: room=%s, taskId=%v, exclude=%s",
@@ -115,15 +170,18 @@ func (b *Broadcaster) BroadcastTaskAssigned(assigneeID string, task map[string]i
 func (b *Broadcaster) BroadcastSprintStarted(projectID string, sprint map[string]interface{}) {
 	room := fmt.Sprintf("project:%s", projectID)
 	b.hub.SendToRoom(room, MessageSprintStarted, sprint, "")
+	b.notifyListeners(projectID, MessageSprintStarted, sprint)
 }
 
 // BroadcastSprintCompleted broadcasts sprint completion to project members
 func (b *Broadcaster) BroadcastSprintCompleted(projectID string, sprint map[string]interface{}, stats map[string]interface{}) {
 	room := fmt.Sprintf("project:%s", projectID)
-	b.hub.SendToRoom(room, MessageSprintCompleted, map[string]interface{}{
+	payload := map[string]interface{}{
 		"sprint": sprint,
 		"stats":  stats,
-	}, "")
+	}
+	b.hub.SendToRoom(room, MessageSprintCompleted, payload, "")
+	b.notifyListeners(projectID, MessageSprintCompleted, payload)
 }
 
 // ============================================
@@ -157,6 +215,18 @@ func (b *Broadcaster) BroadcastCommentDeleted(projectID, taskID, commentID strin
 	}, excludeUserID)
 }
 
+// BroadcastCommentReaction broadcasts a reaction added to or removed from a comment
+func (b *Broadcaster) BroadcastCommentReaction(projectID, taskID, commentID, userID, emoji string, added bool) {
+	room := fmt.Sprintf("project:%s", projectID)
+	b.hub.SendToRoom(room, MessageCommentReacted, map[string]interface{}{
+		"taskId":    taskID,
+		"commentId": commentID,
+		"userId":    userID,
+		"emoji":     emoji,
+		"added":     added,
+	}, userID)
+}
+
 // ============================================
 // Team Broadcasting
 // ============================================
@@ -354,6 +424,11 @@ func (b *Broadcaster) BroadcastToWorkspace(workspaceID string, msgType MessageTy
 
 
 
+// GetChannelPresence returns the users currently connected to a chat channel
+func (b *Broadcaster) GetChannelPresence(channelID string) []string {
+	return b.hub.GetRoomUserIDs(fmt.Sprintf("chat:%s", channelID))
+}
+
 // ============================================
 // Direct User Messaging
 // ============================================
@@ -367,6 +442,23 @@ func (b *Broadcaster) SendToUsers(userIDs []string, msgType MessageType, payload
 
 
 
+// BroadcastPresenceChanged tells every workspace the user belongs to about their new status
+func (b *Broadcaster) BroadcastPresenceChanged(workspaceIDs []string, userID, status string, lastActiveAt *time.Time) {
+	payload := map[string]interface{}{
+		"userId":       userID,
+		"status":       status,
+		"lastActiveAt": lastActiveAt,
+	}
+	for _, workspaceID := range workspaceIDs {
+		b.BroadcastToWorkspace(workspaceID, MessagePresenceChanged, payload, "")
+	}
+}
+
+// DisconnectUser closes all of a user's connections, e.g. after losing workspace access
+func (b *Broadcaster) DisconnectUser(userID string) {
+	b.hub.DisconnectUser(userID)
+}
+
 // ============================================
 // Member Broadcasting 
 // ============================================
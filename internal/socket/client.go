@@ -22,9 +22,39 @@ const (
 
 	// Maximum message size allowed from peer (4KB)
 	maxMessageSize int64 = 4096
+
+	// How long a connection stays open after its token expires, giving the
+	// client time to send "auth.refresh"
+	authExpiryGrace = 5 * time.Minute
 )
 
-// ClientMessage represents an incoming message from a client
+// ClientMessage represents an incoming message from a client.
+//
+// Room subscription, e.g. to receive live task events of a board:
+//
+//	{"action": "join", "room": "project:<projectId>"}
+//	{"action": "leave", "room": "project:<projectId>"}
+//
+// The client receives an "ack" with action "joined"/"left", or "join_denied"
+// when the user has no access to the room.
+//
+// Typing indicators for a joined room (chat channels use "chat:<channelId>"):
+//
+//	{"action": "typing.start", "room": "chat:<channelId>"}
+//	{"action": "typing.stop", "room": "chat:<channelId>"}
+//
+// Other subscribers receive "typing_start"/"typing_stop"; an indicator that is
+// not refreshed expires on its own after a few seconds.
+//
+// Token rotation, sent before the connection's access token expires:
+//
+//	{"action": "auth.refresh", "payload": {"token": "<new access token>"}}
+//
+// A valid token for the same user extends the session and is answered with
+// "auth_refreshed" {"expiresAt": <unix seconds>}. An invalid token yields
+// "auth_expired" {"reason": "..."} followed by a normal close of the socket.
+// A connection not refreshed within authExpiryGrace of its token's expiry
+// gets "auth_expired" {"reason": "token expired"} and is closed.
 type ClientMessage struct {
 	Action  string                 `json:"action"`
 	Room    string                 `json:"room,omitempty"`
@@ -97,6 +127,17 @@ func (c *Client) WritePump() {
 
 		case <-ticker.C:
 			c.Conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if c.authLapsed(time.Now()) {
+				data, _ := json.Marshal(Message{
+					Type:      MessageAuthExpired,
+					Payload:   map[string]interface{}{"reason": "token expired"},
+					Timestamp: time.Now(),
+				})
+				c.Conn.WriteMessage(websocket.TextMessage, data)
+				c.Conn.WriteMessage(websocket.CloseMessage,
+					websocket.FormatCloseMessage(websocket.ClosePolicyViolation, "token expired"))
+				return
+			}
 			if err := c.Conn.WriteMessage(websocket.PingMessage, nil); err != nil {
 				return
 			}
@@ -117,6 +158,11 @@ func (c *Client) handleMessage(message []byte) {
 	switch msg.Action {
 	case "join":
 		if msg.Room != "" {
+			if !c.Hub.CanJoinRoom(c.UserID, msg.Room) {
+				log.Printf("[Client] Join denied: user=%s room=%s", c.UserID, msg.Room)
+				c.sendAck("join_denied", msg.Room)
+				return
+			}
 			c.Hub.JoinRoom(c, msg.Room)
 			c.sendAck("joined", msg.Room)
 		}
@@ -135,6 +181,19 @@ func (c *Client) handleMessage(message []byte) {
 			}, c.UserID)
 		}
 
+	case "typing.start":
+		if msg.Room != "" && c.inRoom(msg.Room) {
+			c.Hub.StartTyping(msg.Room, c.UserID)
+		}
+
+	case "typing.stop":
+		if msg.Room != "" {
+			c.Hub.StopTyping(msg.Room, c.UserID)
+		}
+
+	case "auth.refresh":
+		c.refreshAuth(msg.Payload)
+
 	case "ping":
 		c.lastPing = time.Now()
 		c.sendPong()
@@ -147,6 +206,72 @@ func (c *Client) handleMessage(message []byte) {
 	}
 }
 
+// refreshAuth swaps in a newer token for the same user. On failure the client
+// is told why and unregistered, which makes WritePump close the socket.
+func (c *Client) refreshAuth(payload map[string]interface{}) {
+	token, _ := payload["token"].(string)
+	if token == "" || c.validateToken == nil {
+		c.expireAuth("token required")
+		return
+	}
+
+	userID, expiresAt, err := c.validateToken(token)
+	if err != nil {
+		c.expireAuth(err.Error())
+		return
+	}
+	if userID != c.UserID {
+		c.expireAuth("token belongs to a different user")
+		return
+	}
+
+	c.mu.Lock()
+	c.authExpiresAt = expiresAt
+	c.mu.Unlock()
+
+	log.Printf("[Client] 🔑 Token refreshed: user=%s, expires=%s", c.UserID, expiresAt.Format(time.RFC3339))
+
+	c.sendMessage(MessageAuthRefreshed, map[string]interface{}{
+		"expiresAt": expiresAt.Unix(),
+	})
+}
+
+func (c *Client) expireAuth(reason string) {
+	log.Printf("[Client] Auth refresh rejected for user %s: %s", c.UserID, reason)
+	c.sendMessage(MessageAuthExpired, map[string]interface{}{
+		"reason": reason,
+	})
+	c.Hub.unregister <- c
+}
+
+// authLapsed reports whether the connection's token expired more than
+// authExpiryGrace before now without being refreshed
+func (c *Client) authLapsed(now time.Time) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return !c.authExpiresAt.IsZero() && now.After(c.authExpiresAt.Add(authExpiryGrace))
+}
+
+func (c *Client) sendMessage(msgType MessageType, payload map[string]interface{}) {
+	data, _ := json.Marshal(Message{
+		Type:      msgType,
+		Payload:   payload,
+		Timestamp: time.Now(),
+	})
+
+	select {
+	case c.Send <- data:
+	default:
+		log.Printf("[Client] Failed to send %s to user %s", msgType, c.UserID)
+	}
+}
+
+func (c *Client) inRoom(room string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.Rooms[room]
+}
+
 func (c *Client) sendAck(action, room string) {
 	msg := Message{
 		Type: MessageAck,
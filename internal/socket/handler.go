@@ -2,11 +2,13 @@
 package socket
 
 import (
+	"errors"
 	"log"
 	"net/http"
 	"strings"
 	"time"
 
+	"github.com/Marga-Ghale/ora-scrum-backend/internal/models"
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
@@ -53,52 +55,14 @@ func (h *Handler) HandleWebSocket(c *gin.Context) {
 
 	if tokenString == "" {
 		log.Println("[WebSocket] No token provided")
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "No token provided"})
+		c.JSON(http.StatusUnauthorized, models.NewErrorResponse(models.CodeUnauthenticated, "No token provided", nil))
 		return
 	}
 
-	// Parse and validate JWT token
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		// Validate signing method
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, jwt.ErrSignatureInvalid
-		}
-		return []byte(h.JWTSecret), nil
-	})
-
+	userID, expiresAt, err := h.validateToken(tokenString)
 	if err != nil {
-		log.Printf("[WebSocket] Token parse error: %v", err)
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
-		return
-	}
-
-	if !token.Valid {
-		log.Println("[WebSocket] Token is not valid")
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
-		return
-	}
-
-	// Extract user ID from claims
-	claims, ok := token.Claims.(jwt.MapClaims)
-	if !ok {
-		log.Println("[WebSocket] Invalid token claims")
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token claims"})
-		return
-	}
-
-	// Check token expiration
-	if exp, ok := claims["exp"].(float64); ok {
-		if time.Now().Unix() > int64(exp) {
-			log.Println("[WebSocket] Token expired")
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Token expired"})
-			return
-		}
-	}
-
-	userID, ok := claims["sub"].(string)
-	if !ok || userID == "" {
-		log.Println("[WebSocket] No user ID in token")
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "No user ID in token"})
+		log.Printf("[WebSocket] Token rejected: %v", err)
+		c.JSON(http.StatusUnauthorized, models.NewErrorResponse(models.CodeUnauthenticated, err.Error(), nil))
 		return
 	}
 
@@ -113,6 +77,8 @@ func (h *Handler) HandleWebSocket(c *gin.Context) {
 
 	// Create new client
 	client := NewClient(h.Hub, userID, conn)
+	client.authExpiresAt = expiresAt
+	client.validateToken = h.validateToken
 
 	// Register client with hub
 	h.Hub.register <- client
@@ -125,6 +91,40 @@ func (h *Handler) HandleWebSocket(c *gin.Context) {
 	go client.ReadPump()
 }
 
+// validateToken parses a JWT and returns its subject and expiry (zero when the token has none)
+func (h *Handler) validateToken(tokenString string) (string, time.Time, error) {
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		// Validate signing method
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, jwt.ErrSignatureInvalid
+		}
+		return []byte(h.JWTSecret), nil
+	})
+	if err != nil || !token.Valid {
+		return "", time.Time{}, errors.New("Invalid token")
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return "", time.Time{}, errors.New("Invalid token claims")
+	}
+
+	var expiresAt time.Time
+	if exp, ok := claims["exp"].(float64); ok {
+		expiresAt = time.Unix(int64(exp), 0)
+		if time.Now().After(expiresAt) {
+			return "", time.Time{}, errors.New("Token expired")
+		}
+	}
+
+	userID, ok := claims["sub"].(string)
+	if !ok || userID == "" {
+		return "", time.Time{}, errors.New("No user ID in token")
+	}
+
+	return userID, expiresAt, nil
+}
+
 // NewClient creates a new WebSocket client
 func NewClient(hub *Hub, userID string, conn *websocket.Conn) *Client {
 	return &Client{
@@ -0,0 +1,137 @@
+package socket
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/gorilla/websocket"
+)
+
+const testSecret = "test-secret"
+
+func signToken(t *testing.T, userID string, ttl time.Duration) string {
+	t.Helper()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub": userID,
+		"exp": time.Now().Add(ttl).Unix(),
+	}).SignedString([]byte(testSecret))
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+	return token
+}
+
+func dialTestSocket(t *testing.T, token string) *websocket.Conn {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	hub := NewHub()
+	go hub.Run()
+
+	router := gin.New()
+	router.GET("/ws", NewHandler(hub, testSecret).HandleWebSocket)
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/ws?token=" + token
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+// readUntil returns the first message of the given type, skipping others.
+// The write pump may batch several messages into one frame, newline-separated.
+func readUntil(t *testing.T, conn *websocket.Conn, msgType MessageType) Message {
+	t.Helper()
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			t.Fatalf("waiting for %s: %v", msgType, err)
+		}
+		for _, line := range bytes.Split(data, []byte{'\n'}) {
+			var msg Message
+			if err := json.Unmarshal(line, &msg); err != nil {
+				t.Fatalf("decode %q: %v", line, err)
+			}
+			if msg.Type == msgType {
+				return msg
+			}
+		}
+	}
+}
+
+func send(t *testing.T, conn *websocket.Conn, msg ClientMessage) {
+	t.Helper()
+	if err := conn.WriteJSON(msg); err != nil {
+		t.Fatalf("write %s: %v", msg.Action, err)
+	}
+}
+
+func TestConnectionSurvivesTokenRotation(t *testing.T) {
+	conn := dialTestSocket(t, signToken(t, "user-1", time.Minute))
+
+	fresh := signToken(t, "user-1", time.Hour)
+	send(t, conn, ClientMessage{Action: "auth.refresh", Payload: map[string]interface{}{"token": fresh}})
+
+	msg := readUntil(t, conn, MessageAuthRefreshed)
+	expiresAt, _ := msg.Payload["expiresAt"].(float64)
+	if time.Until(time.Unix(int64(expiresAt), 0)) < 30*time.Minute {
+		t.Fatalf("expiresAt = %v, want the rotated token's expiry", expiresAt)
+	}
+
+	send(t, conn, ClientMessage{Action: "ping"})
+	readUntil(t, conn, MessagePong)
+}
+
+func TestRefreshWithOtherUsersTokenClosesConnection(t *testing.T) {
+	conn := dialTestSocket(t, signToken(t, "user-1", time.Minute))
+
+	send(t, conn, ClientMessage{Action: "auth.refresh", Payload: map[string]interface{}{"token": signToken(t, "user-2", time.Hour)}})
+	readUntil(t, conn, MessageAuthExpired)
+
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	for {
+		if _, _, err := conn.ReadMessage(); err != nil {
+			if !websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseNoStatusReceived) {
+				t.Fatalf("expected a close, got %v", err)
+			}
+			return
+		}
+	}
+}
+
+func TestExpiredTokenLapsesAfterGraceUnlessRefreshed(t *testing.T) {
+	now := time.Now()
+	c := &Client{
+		UserID:        "user-1",
+		Send:          make(chan []byte, 4),
+		authExpiresAt: now.Add(-time.Minute),
+		validateToken: NewHandler(nil, testSecret).validateToken,
+	}
+
+	if c.authLapsed(now) {
+		t.Fatal("connection closed inside the grace period")
+	}
+	if !c.authLapsed(now.Add(authExpiryGrace)) {
+		t.Fatal("connection kept open past the grace period")
+	}
+
+	c.refreshAuth(map[string]interface{}{"token": signToken(t, "user-1", time.Hour)})
+	if c.authLapsed(now.Add(authExpiryGrace)) {
+		t.Fatal("auth.refresh did not extend the connection")
+	}
+
+	if (&Client{}).authLapsed(now.Add(24 * time.Hour)) {
+		t.Error("a connection without a token expiry must never lapse")
+	}
+}
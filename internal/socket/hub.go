@@ -19,6 +19,13 @@ const (
 	MessageNotificationRead  MessageType = "notification_read"
 	MessageNotificationCount MessageType = "notification_count"
 
+	// Unread badge updates
+	MessageNotificationUnread MessageType = "notification.unread"
+	MessageChatUnread         MessageType = "chat.unread"
+
+	// Chat messages
+	MessageChatMention MessageType = "chat_mention"
+
 	// Task messages
 	MessageTaskCreated       MessageType = "task_created"
 	MessageTaskUpdated       MessageType = "task_updated"
@@ -51,17 +58,27 @@ const (
 	MessageUserOnline  MessageType = "user_online"
 	MessageUserOffline MessageType = "user_offline"
 	MessageUserTyping  MessageType = "user_typing"
+	MessageTypingStart MessageType = "typing_start"
+	MessageTypingStop  MessageType = "typing_stop"
+
+	// Persisted presence (online/offline), sent to the user's workspaces
+	MessagePresenceChanged MessageType = "presence.changed"
 
 	// Comment messages
 	MessageCommentAdded   MessageType = "comment_added"
 	MessageCommentUpdated MessageType = "comment_updated"
 	MessageCommentDeleted MessageType = "comment_deleted"
+	MessageCommentReacted MessageType = "comment_reacted"
 
 	// System messages
 	MessagePing MessageType = "ping"
 	MessagePong MessageType = "pong"
 	MessageAck  MessageType = "ack"
 
+	// Connection authentication
+	MessageAuthRefreshed MessageType = "auth_refreshed"
+	MessageAuthExpired   MessageType = "auth_expired"
+
 	// ✅ NEW: Workspace CRUD messages
 	MessageWorkspaceCreated MessageType = "workspace_created"
 	MessageWorkspaceUpdated MessageType = "workspace_updated"
@@ -99,6 +116,11 @@ type Client struct {
 	Rooms    map[string]bool // Subscribed rooms (workspace:id, project:id, etc.)
 	mu       sync.Mutex
 	lastPing time.Time
+
+	// Expiry of the token the connection is authenticated with (zero = never);
+	// extended by "auth.refresh" messages checked with validateToken
+	authExpiresAt time.Time
+	validateToken func(token string) (string, time.Time, error)
 }
 
 // Hub maintains the set of active clients and broadcasts messages
@@ -127,9 +149,36 @@ type Hub struct {
 	// Direct message to specific user
 	directMessage chan *DirectMessage
 
+	// Decides whether a user may join a room; nil allows every room
+	authorizeRoom RoomAuthorizer
+
+	// Active typing indicators: room -> user ID -> auto-expiry timer
+	typing   map[string]map[string]*time.Timer
+	typingMu sync.Mutex
+
+	// Called on debounced online/offline transitions; nil skips persistence
+	onPresence PresenceHandler
+
+	// Pending offline transitions: user ID -> grace timer
+	offlineTimers map[string]*time.Timer
+	presenceMu    sync.Mutex
+
 	mu sync.RWMutex
 }
 
+// typingTimeout clears a typing indicator that was never stopped (e.g. dropped connection)
+const typingTimeout = 6 * time.Second
+
+// presenceGrace is how long a user may be without connections before going
+// offline, so a page reload or brief network drop does not flap their status
+const presenceGrace = 10 * time.Second
+
+// PresenceHandler is told when a user comes online or goes offline
+type PresenceHandler func(userID string, online bool)
+
+// RoomAuthorizer reports whether userID may subscribe to room
+type RoomAuthorizer func(userID, room string) bool
+
 // RoomMessage represents a message to be sent to a specific room
 type RoomMessage struct {
 	Room    string
@@ -154,6 +203,8 @@ func NewHub() *Hub {
 		broadcast:     make(chan []byte, 256),
 		roomBroadcast: make(chan *RoomMessage, 256),
 		directMessage: make(chan *DirectMessage, 256),
+		typing:        make(map[string]map[string]*time.Timer),
+		offlineTimers: make(map[string]*time.Timer),
 	}
 }
 
@@ -195,7 +246,8 @@ func (h *Hub) registerClient(client *Client) {
 	h.clients[client] = true
 
 	// Index by user ID
-	if h.userClients[client.UserID] == nil {
+	firstConnection := h.userClients[client.UserID] == nil
+	if firstConnection {
 		h.userClients[client.UserID] = make(map[*Client]bool)
 	}
 	h.userClients[client.UserID][client] = true
@@ -203,8 +255,10 @@ func (h *Hub) registerClient(client *Client) {
 	log.Printf("[Hub] ✅ Client registered: user=%s, id=%s, total_clients=%d",
 		client.UserID, client.ID, len(h.clients))
 
-	// Broadcast user online status
-	go h.BroadcastUserStatus(client.UserID, true)
+	// Broadcast user online status, unless this is a reconnect within the grace period
+	if firstConnection && !h.cancelOffline(client.UserID) {
+		go h.setPresence(client.UserID, true)
+	}
 }
 
 func (h *Hub) unregisterClient(client *Client) {
@@ -219,8 +273,8 @@ func (h *Hub) unregisterClient(client *Client) {
 			delete(clients, client)
 			if len(clients) == 0 {
 				delete(h.userClients, client.UserID)
-				// User went offline (no more connections)
-				go h.BroadcastUserStatus(client.UserID, false)
+				// User goes offline unless they reconnect shortly
+				h.scheduleOffline(client.UserID)
 			}
 		}
 
@@ -232,6 +286,10 @@ func (h *Hub) unregisterClient(client *Client) {
 					delete(h.roomClients, room)
 				}
 			}
+			// Clear the typing indicator once the user's last tab in the room is gone
+			if !h.userInRoomLocked(client.UserID, room) {
+				go h.StopTyping(room, client.UserID)
+			}
 		}
 
 		close(client.Send)
@@ -332,6 +390,75 @@ func (h *Hub) pingClients() {
 // Public Methods for Room Management
 // ============================================
 
+// SetRoomAuthorizer installs the check applied to client join requests
+func (h *Hub) SetRoomAuthorizer(authorize RoomAuthorizer) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.authorizeRoom = authorize
+}
+
+// SetPresenceHandler installs the callback run on online/offline transitions
+func (h *Hub) SetPresenceHandler(handler PresenceHandler) {
+	h.presenceMu.Lock()
+	defer h.presenceMu.Unlock()
+	h.onPresence = handler
+}
+
+// scheduleOffline marks the user offline after presenceGrace unless they reconnect
+func (h *Hub) scheduleOffline(userID string) {
+	h.presenceMu.Lock()
+	defer h.presenceMu.Unlock()
+
+	if _, pending := h.offlineTimers[userID]; pending {
+		return
+	}
+	h.offlineTimers[userID] = time.AfterFunc(presenceGrace, func() {
+		h.presenceMu.Lock()
+		delete(h.offlineTimers, userID)
+		h.presenceMu.Unlock()
+
+		if !h.IsUserOnline(userID) {
+			h.setPresence(userID, false)
+		}
+	})
+}
+
+// cancelOffline stops a pending offline transition; reports whether one was pending
+func (h *Hub) cancelOffline(userID string) bool {
+	h.presenceMu.Lock()
+	defer h.presenceMu.Unlock()
+
+	timer, pending := h.offlineTimers[userID]
+	if !pending {
+		return false
+	}
+	delete(h.offlineTimers, userID)
+	return timer.Stop()
+}
+
+func (h *Hub) setPresence(userID string, online bool) {
+	h.presenceMu.Lock()
+	handler := h.onPresence
+	h.presenceMu.Unlock()
+
+	if handler != nil {
+		handler(userID, online)
+	}
+	h.BroadcastUserStatus(userID, online)
+}
+
+// CanJoinRoom reports whether a user may subscribe to a room
+func (h *Hub) CanJoinRoom(userID, room string) bool {
+	h.mu.RLock()
+	authorize := h.authorizeRoom
+	h.mu.RUnlock()
+
+	if authorize == nil {
+		return true
+	}
+	return authorize(userID, room)
+}
+
 // JoinRoom adds a client to a room
 func (h *Hub) JoinRoom(client *Client, room string) {
 	h.mu.Lock()
@@ -368,6 +495,57 @@ func (h *Hub) LeaveRoom(client *Client, room string) {
 	log.Printf("[Hub] 👋 Client left room: user=%s, room=%s", client.UserID, room)
 }
 
+// ============================================
+// Typing Indicators
+// ============================================
+
+// StartTyping marks a user as typing in a room and tells the other subscribers.
+// Repeated calls only extend the expiry, so clients may resend while typing.
+func (h *Hub) StartTyping(room, userID string) {
+	h.typingMu.Lock()
+	users := h.typing[room]
+	if users == nil {
+		users = make(map[string]*time.Timer)
+		h.typing[room] = users
+	}
+	if timer, ok := users[userID]; ok {
+		timer.Reset(typingTimeout)
+		h.typingMu.Unlock()
+		return
+	}
+	users[userID] = time.AfterFunc(typingTimeout, func() {
+		h.StopTyping(room, userID)
+	})
+	h.typingMu.Unlock()
+
+	h.SendToRoom(room, MessageTypingStart, map[string]interface{}{
+		"userId": userID,
+		"room":   room,
+	}, userID)
+}
+
+// StopTyping clears a user's typing indicator; it is a no-op when none is active
+func (h *Hub) StopTyping(room, userID string) {
+	h.typingMu.Lock()
+	timer, ok := h.typing[room][userID]
+	if ok {
+		timer.Stop()
+		delete(h.typing[room], userID)
+		if len(h.typing[room]) == 0 {
+			delete(h.typing, room)
+		}
+	}
+	h.typingMu.Unlock()
+
+	if !ok {
+		return
+	}
+	h.SendToRoom(room, MessageTypingStop, map[string]interface{}{
+		"userId": userID,
+		"room":   room,
+	}, userID)
+}
+
 // ============================================
 // Public Methods for Sending Messages
 // ============================================
@@ -470,9 +648,67 @@ func (h *Hub) GetRoomClients(room string) int {
 	return 0
 }
 
+// GetRoomUserIDs returns the distinct users subscribed to a room; a user with
+// several tabs open is listed once
+func (h *Hub) GetRoomUserIDs(room string) []string {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	seen := make(map[string]bool)
+	users := []string{}
+	for client := range h.roomClients[room] {
+		if !seen[client.UserID] {
+			seen[client.UserID] = true
+			users = append(users, client.UserID)
+		}
+	}
+	return users
+}
+
+// userInRoomLocked reports whether any client of the user is in the room; h.mu must be held
+func (h *Hub) userInRoomLocked(userID, room string) bool {
+	for client := range h.roomClients[room] {
+		if client.UserID == userID {
+			return true
+		}
+	}
+	return false
+}
+
 // GetConnectedClientsCount returns total connected clients
 func (h *Hub) GetConnectedClientsCount() int {
 	h.mu.RLock()
 	defer h.mu.RUnlock()
 	return len(h.clients)
-}
\ No newline at end of file
+}
+// GetConnectedUserIDs returns the distinct users with at least one open connection
+func (h *Hub) GetConnectedUserIDs() []string {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	users := make([]string, 0, len(h.userClients))
+	for userID := range h.userClients {
+		users = append(users, userID)
+	}
+	return users
+}
+
+// DisconnectUser closes every connection of a user. The clients go through the
+// unregister channel so their Send channels are closed by the Run loop, never
+// while a broadcast is writing to them.
+func (h *Hub) DisconnectUser(userID string) {
+	h.mu.RLock()
+	clients := make([]*Client, 0, len(h.userClients[userID]))
+	for client := range h.userClients[userID] {
+		clients = append(clients, client)
+	}
+	h.mu.RUnlock()
+
+	for _, client := range clients {
+		h.unregister <- client
+	}
+
+	if len(clients) > 0 {
+		log.Printf("[Hub] 🔌 Disconnected user %s (%d connections)", userID, len(clients))
+	}
+}
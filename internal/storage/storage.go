@@ -0,0 +1,86 @@
+// Package storage stores uploaded files (avatars, attachments) on local disk
+// or in an S3-compatible bucket
+package storage
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Storage is implemented by every backend
+type Storage interface {
+	// Save writes the object and returns its URL
+	Save(ctx context.Context, key string, r io.Reader, size int64, contentType string) (string, error)
+	Delete(ctx context.Context, key string) error
+	// SignedURL returns a time-limited URL for reading a private object
+	SignedURL(ctx context.Context, key string, expiry time.Duration) (string, error)
+	// URL returns the address an object is served from
+	URL(key string) string
+}
+
+// Config selects and configures the backend
+type Config struct {
+	Driver string // "local" (default) or "s3"
+
+	// Local disk
+	LocalDir  string
+	PublicURL string // base URL the local directory is served under
+
+	// S3-compatible
+	S3Endpoint  string
+	S3Region    string
+	S3Bucket    string
+	S3AccessKey string
+	S3SecretKey string
+	S3PublicURL string // optional CDN/public base URL; defaults to endpoint/bucket
+}
+
+// New returns the backend selected by cfg.Driver
+func New(cfg *Config) (Storage, error) {
+	switch cfg.Driver {
+	case "", "local":
+		return NewLocal(cfg.LocalDir, cfg.PublicURL)
+	case "s3":
+		return NewS3(cfg)
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q", cfg.Driver)
+	}
+}
+
+// NewKey builds a unique object key under prefix, keeping the file extension
+func NewKey(prefix, filename string) string {
+	ext := strings.ToLower(path.Ext(filename))
+	return path.Join(prefix, uuid.New().String()+ext)
+}
+
+// KeyFromURL recovers the object key from a URL returned by s, if it is one
+func KeyFromURL(s Storage, url string) (string, bool) {
+	base := s.URL("")
+	if url == "" || !strings.HasPrefix(url, base) {
+		return "", false
+	}
+	return strings.TrimPrefix(url, base), true
+}
+
+// DetectContentType sniffs the content type from the first bytes of r. The
+// returned reader still yields the full content.
+func DetectContentType(r io.Reader) (string, io.Reader, error) {
+	br := bufio.NewReaderSize(r, 512)
+	head, err := br.Peek(512)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return "", nil, err
+	}
+	contentType := http.DetectContentType(head)
+	if i := strings.Index(contentType, ";"); i >= 0 {
+		contentType = contentType[:i]
+	}
+	return contentType, br, nil
+}